@@ -122,21 +122,36 @@ func ensureDefaultConfig() (*model.Configuration, error) {
 	return model.NewDefaultConfiguration(), nil
 }
 
-// process applies command completion and if successful processes the resulting command.
-func (m commandMap) process(cmdPrefix string, command string) (string, error) {
+// complete returns the command for cmdPrefix.
+// An exact match takes precedence over command completion (eg. sign vs. signatures).
+func (m commandMap) complete(cmdPrefix string) (string, error) {
+	if _, ok := m[cmdPrefix]; ok {
+		return cmdPrefix, nil
+	}
+
 	var cmdStr string
 
-	// Support command completion.
 	for k := range m {
 		if !strings.HasPrefix(k, cmdPrefix) {
 			continue
 		}
 		if len(cmdStr) > 0 {
-			return command, errAmbiguousCmd
+			return "", errAmbiguousCmd
 		}
 		cmdStr = k
 	}
 
+	return cmdStr, nil
+}
+
+// process applies command completion and if successful processes the resulting command.
+func (m commandMap) process(cmdPrefix string, command string) (string, error) {
+	// Support command completion.
+	cmdStr, err := m.complete(cmdPrefix)
+	if err != nil {
+		return command, err
+	}
+
 	if cmdStr == "" {
 		return command, errUnknownCmd
 	}
@@ -155,6 +170,8 @@ func (m commandMap) process(cmdPrefix string, command string) (string, error) {
 		conf.Offline = offline
 	}
 
+	conf.ReadLazy = lazy
+
 	if m[cmdStr].handler != nil {
 
 		if conf.Version != model.VersionStr && cmdStr != "reset" {
@@ -175,15 +192,9 @@ func (m commandMap) process(cmdPrefix string, command string) (string, error) {
 
 // HelpString returns documentation for a topic.
 func (m commandMap) HelpString(topic string) (string, error) {
-	topicStr := ""
-	for k := range m {
-		if !strings.HasPrefix(k, topic) {
-			continue
-		}
-		if len(topicStr) > 0 {
-			return topic, errAmbiguousCmd
-		}
-		topicStr = k
+	topicStr, err := m.complete(topic)
+	if err != nil {
+		return topic, err
 	}
 
 	cmd, ok := m[topicStr]
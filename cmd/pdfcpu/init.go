@@ -25,8 +25,9 @@ import (
 func initAnnotsCmdMap() commandMap {
 	m := newCommandMap()
 	for k, v := range map[string]command{
-		"list":   {processListAnnotationsCommand, nil, "", ""},
-		"remove": {processRemoveAnnotationsCommand, nil, "", ""},
+		"list":    {processListAnnotationsCommand, nil, "", ""},
+		"remove":  {processRemoveAnnotationsCommand, nil, "", ""},
+		"flatten": {processFlattenAnnotationsCommand, nil, "", ""},
 	} {
 		m.register(k, v)
 	}
@@ -102,6 +103,7 @@ func initFormCmdMap() commandMap {
 		"lock":      {processLockFormCommand, nil, "", ""},
 		"unlock":    {processUnlockFormCommand, nil, "", ""},
 		"reset":     {processResetFormCommand, nil, "", ""},
+		"flatten":   {processFlattenFormCommand, nil, "", ""},
 		"export":    {processExportFormCommand, nil, "", ""},
 		"fill":      {processFillFormCommand, nil, "", ""},
 		"multifill": {processMultiFillFormCommand, nil, "", ""},
@@ -182,6 +184,77 @@ func initPropertiesCmdMap() commandMap {
 	return m
 }
 
+func initSignaturesCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"verify": {processVerifySignaturesCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
+func initRevisionsCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"list":    {processListRevisionsCommand, nil, "", ""},
+		"extract": {processExtractRevisionCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
+func initPageLabelsCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"list":   {processListPageLabelsCommand, nil, "", ""},
+		"set":    {processSetPageLabelsCommand, nil, "", ""},
+		"remove": {processRemovePageLabelsCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
+func initHeaderFooterCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"add":    {processAddHeaderFooterCommand, nil, "", ""},
+		"remove": {processRemoveHeaderFooterCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
+func initLayersCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"list":    {processListLayersCommand, nil, "", ""},
+		"add":     {processAddLayerCommand, nil, "", ""},
+		"remove":  {processRemoveLayerCommand, nil, "", ""},
+		"show":    {processShowLayerCommand, nil, "", ""},
+		"hide":    {processHideLayerCommand, nil, "", ""},
+		"rename":  {processRenameLayerCommand, nil, "", ""},
+		"merge":   {processMergeLayersCommand, nil, "", ""},
+		"flatten": {processFlattenLayersCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
+func initRedactCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"apply": {processApplyRedactionsCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
 func initStampCmdMap() commandMap {
 	m := newCommandMap()
 	for k, v := range map[string]command{
@@ -250,13 +323,19 @@ func initCommandMap() {
 	configCmdMap := initConfigCmdMap()
 	fontsCmdMap := initFontsCmdMap()
 	formCmdMap := initFormCmdMap()
+	headerFooterCmdMap := initHeaderFooterCmdMap()
 	imagesCmdMap := initImagesCmdMap()
 	keywordsCmdMap := initKeywordsCmdMap()
+	layersCmdMap := initLayersCmdMap()
+	pageLabelsCmdMap := initPageLabelsCmdMap()
 	pagesCmdMap := initPagesCmdMap()
 	permissionsCmdMap := initPermissionsCmdMap()
 	portfolioCmdMap := initPortfolioCmdMap()
 	propertiesCmdMap := initPropertiesCmdMap()
+	redactCmdMap := initRedactCmdMap()
+	revisionsCmdMap := initRevisionsCmdMap()
 	stampCmdMap := initStampCmdMap()
+	signaturesCmdMap := initSignaturesCmdMap()
 	watermarkCmdMap := initWatermarkCmdMap()
 	pageModeCmdMap := initPageModeCmdMap()
 	pageLayoutCmdMap := initPageLayoutCmdMap()
@@ -268,6 +347,7 @@ func initCommandMap() {
 		"annotations":   {nil, annotsCmdMap, usageAnnots, usageLongAnnots},
 		"attachments":   {nil, attachCmdMap, usageAttach, usageLongAttach},
 		"bookmarks":     {nil, bookmarksCmdMap, usageBookmarks, usageLongBookmarks},
+		"bates":         {processBatesCommand, nil, usageBates, usageLongBates},
 		"booklet":       {processBookletCommand, nil, usageBooklet, usageLongBooklet},
 		"boxes":         {nil, boxesCmdMap, usageBoxes, usageLongBoxes},
 		"changeopw":     {processChangeOwnerPasswordCommand, nil, usageChangeOwnerPW, usageLongChangeOwnerPW},
@@ -275,24 +355,29 @@ func initCommandMap() {
 		"collect":       {processCollectCommand, nil, usageCollect, usageLongCollect},
 		"config":        {nil, configCmdMap, usageConfig, usageLongConfig},
 		"create":        {processCreateCommand, nil, usageCreate, usageLongCreate},
+		"convert":       {processConvertCommand, nil, usageConvert, usageLongConvert},
 		"crop":          {processCropCommand, nil, usageCrop, usageLongCrop},
 		"cut":           {processCutCommand, nil, usageCut, usageLongCut},
 		"decrypt":       {processDecryptCommand, nil, usageDecrypt, usageLongDecrypt},
+		"diff":          {processDiffCommand, nil, usageDiff, usageLongDiff},
 		"dump":          {processDumpCommand, nil, "", ""},
 		"encrypt":       {processEncryptCommand, nil, usageEncrypt, usageLongEncrypt},
 		"extract":       {processExtractCommand, nil, usageExtract, usageLongExtract},
 		"fonts":         {nil, fontsCmdMap, usageFonts, usageLongFonts},
 		"form":          {nil, formCmdMap, usageForm, usageLongForm},
 		"grid":          {processGridCommand, nil, usageGrid, usageLongGrid},
+		"headerfooter":  {nil, headerFooterCmdMap, usageHeaderFooter, usageLongHeaderFooter},
 		"help":          {printHelp, nil, "", ""},
 		"images":        {nil, imagesCmdMap, usageImages, usageLongImages},
 		"import":        {processImportImagesCommand, nil, usageImportImages, usageLongImportImages},
 		"info":          {processInfoCommand, nil, usageInfo, usageLongInfo},
 		"keywords":      {nil, keywordsCmdMap, usageKeywords, usageLongKeywords},
+		"layers":        {nil, layersCmdMap, usageLayers, usageLongLayers},
 		"merge":         {processMergeCommand, nil, usageMerge, usageLongMerge},
 		"ndown":         {processNDownCommand, nil, usageNDown, usageLongNDown},
 		"nup":           {processNUpCommand, nil, usageNUp, usageLongNUp},
 		"optimize":      {processOptimizeCommand, nil, usageOptimize, usageLongOptimize},
+		"pagelabels":    {nil, pageLabelsCmdMap, usagePageLabels, usageLongPageLabels},
 		"pagelayout":    {nil, pageLayoutCmdMap, usagePageLayout, usageLongPageLayout},
 		"pagemode":      {nil, pageModeCmdMap, usagePageMode, usageLongPageMode},
 		"pages":         {nil, pagesCmdMap, usagePages, usageLongPages},
@@ -301,9 +386,14 @@ func initCommandMap() {
 		"portfolio":     {nil, portfolioCmdMap, usagePortfolio, usageLongPortfolio},
 		"poster":        {processPosterCommand, nil, usagePoster, usageLongPoster},
 		"properties":    {nil, propertiesCmdMap, usageProperties, usageLongProperties},
+		"redact":        {nil, redactCmdMap, usageRedact, usageLongRedact},
+		"render":        {processRenderCommand, nil, usageRender, usageLongRender},
 		"resize":        {processResizeCommand, nil, usageResize, usageLongResize},
+		"revisions":     {nil, revisionsCmdMap, usageRevisions, usageLongRevisions},
 		"rotate":        {processRotateCommand, nil, usageRotate, usageLongRotate},
 		"selectedpages": {printSelectedPages, nil, usageSelectedPages, usageLongSelectedPages},
+		"sign":          {processSignCommand, nil, usageSign, usageLongSign},
+		"signatures":    {nil, signaturesCmdMap, usageSignatures, usageLongSignatures},
 		"split":         {processSplitCommand, nil, usageSplit, usageLongSplit},
 		"stamp":         {nil, stampCmdMap, usageStamp, usageLongStamp},
 		"trim":          {processTrimCommand, nil, usageTrim, usageLongTrim},
@@ -325,6 +415,14 @@ func initFlags() {
 	flag.BoolVar(&bookmarks, "bookmarks", false, bookmarksUsage)
 	flag.BoolVar(&bookmarks, "b", false, bookmarksUsage)
 
+	flag.StringVar(&batesPrefix, "prefix", "", "bates: prefix of each Bates number")
+	flag.IntVar(&batesStart, "start", 1, "bates: first Bates number")
+	flag.IntVar(&batesDigits, "digits", 6, "bates: number of digits (zero-padded)")
+	flag.BoolVar(&batesProps, "props", false, "bates: record Bates range as document properties")
+
+	flag.StringVar(&cert, "cert", "", "sign: certificate chain file (PEM); encrypt: recipient certificates (PEM)")
+	flag.StringVar(&keyFile, "keyfile", "", "sign, decrypt: private key file (PEM)")
+
 	confUsage := "the config directory path | skip | none"
 	flag.StringVar(&conf, "config", "", confUsage)
 	flag.StringVar(&conf, "conf", "", confUsage)
@@ -334,6 +432,8 @@ func initFlags() {
 	flag.BoolVar(&dividerPage, "dividerPage", false, dividerPageUsage)
 	flag.BoolVar(&dividerPage, "d", false, dividerPageUsage)
 
+	flag.IntVar(&dpi, "dpi", 0, "render: resolution in dots per inch; optimize: max image resolution")
+
 	fontsUsage := "include font info"
 	flag.BoolVar(&fonts, "fonts", false, fontsUsage)
 	flag.BoolVar(&fonts, "f", false, fontsUsage)
@@ -346,11 +446,19 @@ func initFlags() {
 	flag.StringVar(&key, "key", "256", keyUsage)
 	flag.StringVar(&key, "k", "256", keyUsage)
 
+	flag.StringVar(&format, "format", "png", "render: png|jpg|tif")
+
+	flag.BoolVar(&gray, "gray", false, "optimize: convert grayscale RGB images to DeviceGray")
+
+	flag.BoolVar(&lazy, "lazy", false, "info, properties list, extract pages: read objects on demand")
+
+	flag.BoolVar(&linearize, "linearize", false, "optimize: write linearized file (fast web view)")
+
 	linksUsage := "check for broken links"
 	flag.BoolVar(&links, "links", false, linksUsage)
 	flag.BoolVar(&links, "l", false, linksUsage)
 
-	modeUsage := "validate: strict|relaxed; extract: image|font|content|page|meta; encrypt: rc4|aes; stamp:text|image/pdf"
+	modeUsage := "validate: strict|relaxed; extract: image|font|content|page|meta; encrypt: rc4|aes; stamp:text|image/pdf; annotations flatten: all|print|screen"
 	flag.StringVar(&mode, "mode", "", modeUsage)
 	flag.StringVar(&mode, "m", "", modeUsage)
 
@@ -362,16 +470,28 @@ func initFlags() {
 	flag.BoolVar(&optimize, "optimize", false, optimizeUsage)
 	flag.BoolVar(&optimize, "opt", false, optimizeUsage)
 
+	flag.StringVar(&profile, "profile", "", "validate: pdfa-1b|pdfa-2b|pdfa-3b")
+
+	flag.IntVar(&quality, "quality", 0, "optimize: JPEG quality 1..100 for recompressing photos")
+
 	selectedPagesUsage := "a comma separated list of pages or page ranges, see pdfcpu selectedpages"
 	flag.StringVar(&selectedPages, "pages", "", selectedPagesUsage)
 	flag.StringVar(&selectedPages, "p", "", selectedPagesUsage)
 
+	flag.StringVar(&to, "to", "", "convert: pdfa-1b|pdfa-2b|pdfa-3b")
+
+	flag.StringVar(&trustStore, "trust", "", "signatures verify: trust store directory (PEM)")
+
 	permUsage := "encrypt, perm set: none|all"
 	flag.StringVar(&perm, "perm", "none", permUsage)
 
 	flag.BoolVar(&quiet, "quiet", false, "")
 	flag.BoolVar(&quiet, "q", false, "")
 
+	revisionUsage := "revisions extract: revision number"
+	flag.IntVar(&revision, "revision", 0, revisionUsage)
+	flag.IntVar(&revision, "n", 0, revisionUsage)
+
 	replaceUsage := "replace existing bookmarks"
 	flag.BoolVar(&replaceBookmarks, "replace", false, replaceUsage)
 	flag.BoolVar(&replaceBookmarks, "r", false, replaceUsage)
@@ -383,6 +503,8 @@ func initFlags() {
 	statsUsage := "optimize: create a csv file for stats"
 	flag.StringVar(&fileStats, "stats", "", statsUsage)
 
+	flag.BoolVar(&subset, "subset", false, "optimize: subset embedded TrueType fonts")
+
 	unitUsage := "info: po|in|cm|mm"
 	flag.StringVar(&unit, "unit", "", unitUsage)
 	flag.StringVar(&unit, "u", "", unitUsage)
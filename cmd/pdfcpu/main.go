@@ -26,13 +26,25 @@ import (
 
 var (
 	fileStats, mode, selectedPages           string
+	profile                                  string // Validate
+	to                                       string // Convert
 	upw, opw, key, perm, unit, conf          string
+	cert, keyFile, trustStore                string // Sign, Verify signatures, Encrypt, Decrypt
+	dpi                                      int    // Render, Optimize
+	quality                                  int    // Optimize
+	revision                                 int    // Extract revision
+	format                                   string // Render
+	batesPrefix                              string // Bates
+	batesStart, batesDigits                  int    // Bates
+	batesProps                               bool   // Bates
 	verbose, veryVerbose                     bool
 	links, quiet, offline                    bool
+	linearize, gray, subset                  bool // Optimize
 	replaceBookmarks                         bool // Import Bookmarks
 	all                                      bool // List Viewer Preferences
 	fonts                                    bool // Info
-	json                                     bool // List Viewer Preferences, Info
+	lazy                                     bool // Info, Properties, Extract pages
+	json                                     bool // Info, Diff, Extract text and list: annotations, attachments, bookmarks, boxes, fonts, form fields, images, keywords, layers, page labels, page layout, page mode, permissions, properties, revisions, viewer preferences
 	bookmarks, dividerPage, optimize, sorted bool // Merge
 	bookmarksSet, offlineSet, optimizeSet    bool
 	needStackTrace                           = true
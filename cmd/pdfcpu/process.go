@@ -36,6 +36,7 @@ import (
 	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/validate"
 	"github.com/pkg/errors"
@@ -344,6 +345,15 @@ func processValidateCommand(conf *model.Configuration) {
 		os.Exit(1)
 	}
 
+	if profile != "" {
+		p, ok := model.PDFAProfile(profile)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "%s\n\n", usageValidate)
+			os.Exit(1)
+		}
+		conf.ValidationProfile = p
+	}
+
 	if links {
 		conf.ValidateLinks = true
 	}
@@ -373,6 +383,17 @@ func processOptimizeCommand(conf *model.Configuration) {
 		ensurePDFExtension(outFile)
 	}
 
+	conf.Linearize = linearize
+
+	if dpi < 0 || quality < 0 || quality > 100 {
+		fmt.Fprintf(os.Stderr, "%s\n\n", usageOptimize)
+		os.Exit(1)
+	}
+	conf.OptimizeImagesMaxDPI = dpi
+	conf.OptimizeImagesJPEGQuality = quality
+	conf.OptimizeImagesGray = gray
+	conf.OptimizeSubsetFonts = subset
+
 	conf.StatsFileName = fileStats
 	if len(fileStats) > 0 {
 		fmt.Fprintf(os.Stdout, "stats will be appended to %s\n", fileStats)
@@ -578,7 +599,7 @@ func modeCompletion(modePrefix string, modes []string) string {
 }
 
 func processExtractCommand(conf *model.Configuration) {
-	mode = modeCompletion(mode, []string{"image", "font", "page", "content", "meta"})
+	mode = modeCompletion(mode, []string{"image", "font", "page", "content", "text", "meta"})
 	if len(flag.Args()) != 2 || mode == "" {
 		fmt.Fprintf(os.Stderr, "%s\n\n", usageExtract)
 		os.Exit(1)
@@ -612,6 +633,9 @@ func processExtractCommand(conf *model.Configuration) {
 	case "content":
 		cmd = cli.ExtractContentCommand(inFile, outDir, pages, conf)
 
+	case "text":
+		cmd = cli.ExtractTextCommand(inFile, outDir, pages, json, conf)
+
 	case "meta":
 		cmd = cli.ExtractMetadataCommand(inFile, outDir, conf)
 
@@ -660,7 +684,11 @@ func processListAttachmentsCommand(conf *model.Configuration) {
 	if conf.CheckFileNameExt {
 		ensurePDFExtension(inFile)
 	}
-	process(cli.ListAttachmentsCommand(inFile, conf))
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListAttachmentsCommand(inFile, json, conf))
 }
 
 func processAddAttachmentsCommand(conf *model.Configuration) {
@@ -801,7 +829,11 @@ func processListPermissionsCommand(conf *model.Configuration) {
 		inFiles = append(inFiles, arg)
 	}
 
-	process(cli.ListPermissionsCommand(inFiles, conf))
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListPermissionsCommand(inFiles, json, conf))
 }
 
 func permCompletion(permPrefix string) string {
@@ -879,6 +911,13 @@ func processDecryptCommand(conf *model.Configuration) {
 		os.Exit(1)
 	}
 
+	if keyFile != "" {
+		if err := readRecipientCredentials(conf); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	inFile := flag.Arg(0)
 	if conf.CheckFileNameExt {
 		ensurePDFExtension(inFile)
@@ -893,6 +932,26 @@ func processDecryptCommand(conf *model.Configuration) {
 	process(cli.DecryptCommand(inFile, outFile, conf))
 }
 
+func readRecipientCredentials(conf *model.Configuration) error {
+	k, err := api.ReadRecipientKeyFile(keyFile)
+	if err != nil {
+		return err
+	}
+	conf.RecipientKey = k
+
+	if cert == "" {
+		return nil
+	}
+
+	certs, err := api.ReadRecipientCertificatesFile(cert)
+	if err != nil {
+		return err
+	}
+	conf.RecipientCert = certs[0]
+
+	return nil
+}
+
 func validateEncryptModeFlag() {
 	if !types.MemberOf(mode, []string{"rc4", "aes", ""}) {
 		fmt.Fprintf(os.Stderr, "%s\n\n", "valid modes: rc4,aes default:aes")
@@ -942,13 +1001,26 @@ func processEncryptCommand(conf *model.Configuration) {
 		os.Exit(1)
 	}
 
-	if conf.OwnerPW == "" {
+	if conf.OwnerPW == "" && cert == "" {
 		fmt.Fprintln(os.Stderr, "missing non-empty owner password!")
 		fmt.Fprintf(os.Stderr, "%s\n\n", usageEncrypt)
 		os.Exit(1)
 	}
 
 	validateEncryptFlags()
+
+	if cert != "" {
+		if mode != "aes" || key != "256" {
+			fmt.Fprintf(os.Stderr, "%s\n\n", "public-key encryption supports aes/256 only")
+			os.Exit(1)
+		}
+		certs, err := api.ReadRecipientCertificatesFile(cert)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		conf.Recipients = certs
+	}
 	if perm != "" {
 		perm = permCompletion(perm)
 	}
@@ -1042,8 +1114,8 @@ func addWatermarks(conf *model.Configuration, onTop bool) {
 		os.Exit(1)
 	}
 
-	if mode != "text" && mode != "image" && mode != "pdf" {
-		fmt.Fprintln(os.Stderr, "mode has to be one of: text, image or pdf")
+	if mode != "text" && mode != "image" && mode != "pdf" && mode != "barcode" {
+		fmt.Fprintln(os.Stderr, "mode has to be one of: text, image, pdf or barcode")
 		os.Exit(1)
 	}
 
@@ -1063,6 +1135,8 @@ func addWatermarks(conf *model.Configuration, onTop bool) {
 
 	case "pdf":
 		wm, err = pdfcpu.ParsePDFWatermarkDetails(flag.Arg(0), flag.Arg(1), onTop, conf.Unit)
+	case "barcode":
+		wm, err = pdfcpu.ParseBarcodeWatermarkDetails(flag.Arg(0), flag.Arg(1), onTop, conf.Unit)
 	default:
 		err = errors.Errorf("unsupported wm type: %s\n", mode)
 	}
@@ -1111,7 +1185,7 @@ func updateWatermarks(conf *model.Configuration, onTop bool) {
 		os.Exit(1)
 	}
 
-	if mode != "text" && mode != "image" && mode != "pdf" {
+	if mode != "text" && mode != "image" && mode != "pdf" && mode != "barcode" {
 		fmt.Fprintf(os.Stderr, "%s\n\n", u)
 		os.Exit(1)
 	}
@@ -1130,6 +1204,8 @@ func updateWatermarks(conf *model.Configuration, onTop bool) {
 		wm, err = pdfcpu.ParseImageWatermarkDetails(flag.Arg(0), flag.Arg(1), onTop, conf.Unit)
 	case "pdf":
 		wm, err = pdfcpu.ParsePDFWatermarkDetails(flag.Arg(0), flag.Arg(1), onTop, conf.Unit)
+	case "barcode":
+		wm, err = pdfcpu.ParseBarcodeWatermarkDetails(flag.Arg(0), flag.Arg(1), onTop, conf.Unit)
 	default:
 		err = errors.Errorf("unsupported wm type: %s\n", mode)
 	}
@@ -1639,7 +1715,11 @@ func processInfoCommand(conf *model.Configuration) {
 }
 
 func processListFontsCommand(conf *model.Configuration) {
-	process(cli.ListFontsCommand(conf))
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListFontsCommand(json, conf))
 }
 
 func processInstallFontsCommand(conf *model.Configuration) {
@@ -1679,7 +1759,11 @@ func processListKeywordsCommand(conf *model.Configuration) {
 	if conf.CheckFileNameExt {
 		ensurePDFExtension(inFile)
 	}
-	process(cli.ListKeywordsCommand(inFile, conf))
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListKeywordsCommand(inFile, json, conf))
 }
 
 func processAddKeywordsCommand(conf *model.Configuration) {
@@ -1738,7 +1822,11 @@ func processListPropertiesCommand(conf *model.Configuration) {
 	if conf.CheckFileNameExt {
 		ensurePDFExtension(inFile)
 	}
-	process(cli.ListPropertiesCommand(inFile, conf))
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListPropertiesCommand(inFile, json, conf))
 }
 
 func processAddPropertiesCommand(conf *model.Configuration) {
@@ -1848,12 +1936,16 @@ func processListBoxesCommand(conf *model.Configuration) {
 		os.Exit(1)
 	}
 
+	if json {
+		log.SetCLILogger(nil)
+	}
+
 	if len(flag.Args()) == 1 {
 		inFile := flag.Arg(0)
 		if conf.CheckFileNameExt {
 			ensurePDFExtension(inFile)
 		}
-		process(cli.ListBoxesCommand(inFile, selectedPages, nil, conf))
+		process(cli.ListBoxesCommand(inFile, selectedPages, nil, json, conf))
 		return
 	}
 
@@ -1868,7 +1960,7 @@ func processListBoxesCommand(conf *model.Configuration) {
 		ensurePDFExtension(inFile)
 	}
 
-	process(cli.ListBoxesCommand(inFile, selectedPages, pb, conf))
+	process(cli.ListBoxesCommand(inFile, selectedPages, pb, json, conf))
 }
 
 func processAddBoxesCommand(conf *model.Configuration) {
@@ -1997,7 +2089,11 @@ func processListAnnotationsCommand(conf *model.Configuration) {
 		os.Exit(1)
 	}
 
-	process(cli.ListAnnotationsCommand(inFile, selectedPages, conf))
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListAnnotationsCommand(inFile, selectedPages, json, conf))
 }
 
 func processRemoveAnnotationsCommand(conf *model.Configuration) {
@@ -2046,6 +2142,209 @@ func processRemoveAnnotationsCommand(conf *model.Configuration) {
 	process(cli.RemoveAnnotationsCommand(inFile, outFile, selectedPages, idsAndTypes, objNrs, conf))
 }
 
+func processFlattenAnnotationsCommand(conf *model.Configuration) {
+	if mode == "" {
+		mode = "all"
+	}
+	mode = modeCompletion(mode, []string{"all", "print", "screen"})
+	if len(flag.Args()) < 1 || mode == "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageAnnotsFlatten)
+		os.Exit(1)
+	}
+
+	selectedPages, err := api.ParsePageSelection(selectedPages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "problem with flag selectedPages: %v\n", err)
+		os.Exit(1)
+	}
+
+	flattenMode := pdfcpu.FlattenAll
+	switch mode {
+	case "print":
+		flattenMode = pdfcpu.FlattenPrint
+	case "screen":
+		flattenMode = pdfcpu.FlattenScreen
+	}
+
+	inFile, outFile := "", ""
+
+	var annotTypes []string
+
+	for i, arg := range flag.Args() {
+		if i == 0 {
+			inFile = arg
+			if conf.CheckFileNameExt {
+				ensurePDFExtension(inFile)
+			}
+			continue
+		}
+		if i == 1 && hasPDFExtension(arg) {
+			outFile = arg
+			continue
+		}
+		annotTypes = append(annotTypes, arg)
+	}
+
+	process(cli.FlattenAnnotationsCommand(inFile, outFile, selectedPages, annotTypes, flattenMode, conf))
+}
+
+func processApplyRedactionsCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageRedactApply)
+		os.Exit(1)
+	}
+
+	processDisplayUnit(conf)
+
+	selectedPages, err := api.ParsePageSelection(selectedPages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "problem with flag selectedPages: %v\n", err)
+		os.Exit(1)
+	}
+
+	inFile, outFile := "", ""
+	var rects []*types.Rectangle
+
+	for i, arg := range flag.Args() {
+		if i == 0 {
+			inFile = arg
+			if conf.CheckFileNameExt {
+				ensurePDFExtension(inFile)
+			}
+			continue
+		}
+		if i == 1 && hasPDFExtension(arg) {
+			outFile = arg
+			continue
+		}
+		arg = strings.TrimSpace(arg)
+		if !strings.HasPrefix(arg, "[") {
+			fmt.Fprintf(os.Stderr, "invalid region: %s, expected '[llx lly urx ury]'\n", arg)
+			os.Exit(1)
+		}
+		box, err := api.Box(arg, conf.Unit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "problem parsing region: %v\n", err)
+			os.Exit(1)
+		}
+		rects = append(rects, box.Rect)
+	}
+
+	process(cli.ApplyRedactionsCommand(inFile, outFile, selectedPages, rects, conf))
+}
+
+func processRenderCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 2 {
+		fmt.Fprintf(os.Stderr, "%s\n", usageRender)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+	outDir := flag.Arg(1)
+
+	selectedPages, err := api.ParsePageSelection(selectedPages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "problem with flag selectedPages: %v\n", err)
+		os.Exit(1)
+	}
+
+	if dpi == 0 {
+		dpi = api.DefaultRenderDPI
+	}
+	if dpi < 0 {
+		fmt.Fprintf(os.Stderr, "invalid resolution: %d\n", dpi)
+		os.Exit(1)
+	}
+
+	process(cli.RenderCommand(inFile, outDir, selectedPages, dpi, format, conf))
+}
+
+func processConvertCommand(conf *model.Configuration) {
+	if len(flag.Args()) == 0 || len(flag.Args()) > 2 || to == "" {
+		fmt.Fprintf(os.Stderr, "%s\n", usageConvert)
+		os.Exit(1)
+	}
+
+	p, ok := model.PDFAProfile(to)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unsupported conversion target: %s\n", to)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := ""
+	if len(flag.Args()) == 2 {
+		outFile = flag.Arg(1)
+		ensurePDFExtension(outFile)
+	}
+
+	process(cli.ConvertToPDFACommand(inFile, outFile, p, conf))
+}
+
+func processDiffCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 2 {
+		fmt.Fprintf(os.Stderr, "%s\n", usageDiff)
+		os.Exit(1)
+	}
+
+	inFile1, inFile2 := flag.Arg(0), flag.Arg(1)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile1)
+		ensurePDFExtension(inFile2)
+	}
+
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.DiffCommand(inFile1, inFile2, json, conf))
+}
+
+func processListRevisionsCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 1 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageRevisionsList)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListRevisionsCommand(inFile, json, conf))
+}
+
+func processExtractRevisionCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 || len(flag.Args()) > 2 || revision < 1 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageRevisionsExtract)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := ""
+	if len(flag.Args()) == 2 {
+		outFile = flag.Arg(1)
+		ensurePDFExtension(outFile)
+	}
+
+	process(cli.ExtractRevisionCommand(inFile, outFile, revision, conf))
+}
+
 func processListImagesCommand(conf *model.Configuration) {
 	if len(flag.Args()) < 1 {
 		fmt.Fprintf(os.Stderr, "usage: %s\n", usageImagesList)
@@ -2075,7 +2374,11 @@ func processListImagesCommand(conf *model.Configuration) {
 		os.Exit(1)
 	}
 
-	process(cli.ListImagesCommand(inFiles, selectedPages, conf))
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListImagesCommand(inFiles, selectedPages, json, conf))
 }
 
 func processExtractImagesCommand(conf *model.Configuration) {
@@ -2226,7 +2529,11 @@ func processListFormFieldsCommand(conf *model.Configuration) {
 		inFiles = append(inFiles, arg)
 	}
 
-	process(cli.ListFormFieldsCommand(inFiles, conf))
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListFormFieldsCommand(inFiles, json, conf))
 }
 
 func processRemoveFormFieldsCommand(conf *model.Configuration) {
@@ -2329,6 +2636,38 @@ func processUnlockFormCommand(conf *model.Configuration) {
 	process(cli.UnlockFormCommand(inFile, outFile, fieldIDs, conf))
 }
 
+func processFlattenFormCommand(conf *model.Configuration) {
+	if len(flag.Args()) == 0 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFormFlatten)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	var fieldIDs []string
+	outFile := inFile
+
+	if len(flag.Args()) > 1 {
+		s := flag.Arg(1)
+		if hasPDFExtension(s) {
+			outFile = s
+		} else {
+			fieldIDs = append(fieldIDs, s)
+		}
+	}
+
+	if len(flag.Args()) > 2 {
+		for i := 2; i < len(flag.Args()); i++ {
+			fieldIDs = append(fieldIDs, flag.Arg(i))
+		}
+	}
+
+	process(cli.FlattenFormCommand(inFile, outFile, fieldIDs, conf))
+}
+
 func processResetFormCommand(conf *model.Configuration) {
 	if len(flag.Args()) == 0 || selectedPages != "" {
 		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageFormReset)
@@ -2631,7 +2970,11 @@ func processListBookmarksCommand(conf *model.Configuration) {
 		ensurePDFExtension(inFile)
 	}
 
-	process(cli.ListBookmarksCommand(inFile, conf))
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListBookmarksCommand(inFile, json, conf))
 }
 
 func processExportBookmarksCommand(conf *model.Configuration) {
@@ -2707,7 +3050,11 @@ func processListPageLayoutCommand(conf *model.Configuration) {
 	if conf.CheckFileNameExt {
 		ensurePDFExtension(inFile)
 	}
-	process(cli.ListPageLayoutCommand(inFile, conf))
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListPageLayoutCommand(inFile, json, conf))
 }
 
 func processSetPageLayoutCommand(conf *model.Configuration) {
@@ -2754,7 +3101,11 @@ func processListPageModeCommand(conf *model.Configuration) {
 	if conf.CheckFileNameExt {
 		ensurePDFExtension(inFile)
 	}
-	process(cli.ListPageModeCommand(inFile, conf))
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListPageModeCommand(inFile, json, conf))
 }
 
 func processSetPageModeCommand(conf *model.Configuration) {
@@ -2878,3 +3229,314 @@ func processZoomCommand(conf *model.Configuration) {
 
 	process(cli.ZoomCommand(inFile, outFile, selectedPages, zc, conf))
 }
+
+func processSignCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 || len(flag.Args()) > 3 {
+		fmt.Fprintf(os.Stderr, "%s\n", usageSign)
+		os.Exit(1)
+	}
+
+	if cert == "" || keyFile == "" {
+		fmt.Fprintf(os.Stderr, "please provide -cert and -keyfile\n\n%s\n", usageSign)
+		os.Exit(1)
+	}
+
+	args := flag.Args()
+
+	description := ""
+	if len(args) > 1 && !hasPDFExtension(args[0]) {
+		description = args[0]
+		args = args[1:]
+	}
+
+	if len(args) > 2 {
+		fmt.Fprintf(os.Stderr, "%s\n", usageSign)
+		os.Exit(1)
+	}
+
+	sc, err := sign.ParseSignatureConfig(description)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	inFile := args[0]
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := ""
+	if len(args) == 2 {
+		outFile = args[1]
+		ensurePDFExtension(outFile)
+	}
+
+	process(cli.SignCommand(inFile, outFile, cert, keyFile, sc, conf))
+}
+
+func processVerifySignaturesCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 1 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageSignaturesVerify)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	process(cli.VerifySignaturesCommand(inFile, trustStore, conf))
+}
+
+func processListLayersCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 1 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageLayersList)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListLayersCommand(inFile, json, conf))
+}
+
+// layerArgs returns inFile followed by n layer names or exits printing usage.
+func layerArgs(n int, usage string, conf *model.Configuration) (string, []string) {
+	if len(flag.Args()) != n+1 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usage)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	return inFile, flag.Args()[1:]
+}
+
+func processAddLayerCommand(conf *model.Configuration) {
+	inFile, names := layerArgs(1, usageLayersAdd, conf)
+	process(cli.AddLayerCommand(inFile, "", names[0], conf))
+}
+
+func processRemoveLayerCommand(conf *model.Configuration) {
+	inFile, names := layerArgs(1, usageLayersRemove, conf)
+	process(cli.RemoveLayerCommand(inFile, "", names[0], conf))
+}
+
+func processShowLayerCommand(conf *model.Configuration) {
+	inFile, names := layerArgs(1, usageLayersShow, conf)
+	process(cli.SetLayerVisibilityCommand(inFile, "", names[0], true, conf))
+}
+
+func processHideLayerCommand(conf *model.Configuration) {
+	inFile, names := layerArgs(1, usageLayersHide, conf)
+	process(cli.SetLayerVisibilityCommand(inFile, "", names[0], false, conf))
+}
+
+func processRenameLayerCommand(conf *model.Configuration) {
+	inFile, names := layerArgs(2, usageLayersRename, conf)
+	process(cli.RenameLayerCommand(inFile, "", names[0], names[1], conf))
+}
+
+func processMergeLayersCommand(conf *model.Configuration) {
+	inFile, names := layerArgs(2, usageLayersMerge, conf)
+	process(cli.MergeLayersCommand(inFile, "", names[0], names[1], conf))
+}
+
+func processFlattenLayersCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 || len(flag.Args()) > 2 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageLayersFlatten)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := ""
+	if len(flag.Args()) == 2 {
+		outFile = flag.Arg(1)
+		ensurePDFExtension(outFile)
+	}
+
+	process(cli.FlattenLayersCommand(inFile, outFile, conf))
+}
+
+func processListPageLabelsCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 1 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usagePageLabelsList)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	if json {
+		log.SetCLILogger(nil)
+	}
+
+	process(cli.ListPageLabelsCommand(inFile, json, conf))
+}
+
+func processSetPageLabelsCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 2 || len(flag.Args()) > 3 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usagePageLabelsSet)
+		os.Exit(1)
+	}
+
+	pl, err := pdfcpu.ParsePageLabelConfig(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(1)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := ""
+	if len(flag.Args()) == 3 {
+		outFile = flag.Arg(2)
+		ensurePDFExtension(outFile)
+	}
+
+	selectedPages, err := api.ParsePageSelection(selectedPages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "problem with flag selectedPages: %v\n", err)
+		os.Exit(1)
+	}
+
+	process(cli.SetPageLabelsCommand(inFile, outFile, selectedPages, pl, conf))
+}
+
+func processRemovePageLabelsCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 || len(flag.Args()) > 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usagePageLabelsRemove)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := ""
+	if len(flag.Args()) == 2 {
+		outFile = flag.Arg(1)
+		ensurePDFExtension(outFile)
+	}
+
+	process(cli.RemovePageLabelsCommand(inFile, outFile, conf))
+}
+
+func processBatesCommand(conf *model.Configuration) {
+	args := flag.Args()
+
+	desc := ""
+	if len(args) > 2 && !hasPDFExtension(args[0]) {
+		desc = args[0]
+		args = args[1:]
+	}
+
+	if len(args) < 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "%s\n\n", usageBates)
+		os.Exit(1)
+	}
+
+	processDisplayUnit(conf)
+
+	inFiles := args[:len(args)-1]
+	for _, inFile := range inFiles {
+		if conf.CheckFileNameExt {
+			ensurePDFExtension(inFile)
+		}
+	}
+
+	outDir := args[len(args)-1]
+
+	b := model.DefaultBatesConfig()
+	b.Prefix = batesPrefix
+	b.Start = batesStart
+	b.Digits = batesDigits
+	b.Desc = desc
+	b.Properties = batesProps
+
+	if err := b.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	process(cli.BatesCommand(inFiles, outDir, filepath.Join(outDir, "bates.csv"), b, conf))
+}
+
+func processAddHeaderFooterCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 2 || len(flag.Args()) > 3 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageHeaderFooterAdd)
+		os.Exit(1)
+	}
+
+	processDisplayUnit(conf)
+
+	hf, err := pdfcpu.ParseHeaderFooterConfig(flag.Arg(0), conf.Unit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(1)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := ""
+	if len(flag.Args()) == 3 {
+		outFile = flag.Arg(2)
+		ensurePDFExtension(outFile)
+	}
+
+	selectedPages, err := api.ParsePageSelection(selectedPages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "problem with flag selectedPages: %v\n", err)
+		os.Exit(1)
+	}
+
+	process(cli.AddHeaderFooterCommand(inFile, outFile, selectedPages, hf, conf))
+}
+
+func processRemoveHeaderFooterCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 || len(flag.Args()) > 2 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", usageHeaderFooterRemove)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := ""
+	if len(flag.Args()) == 2 {
+		outFile = flag.Arg(1)
+		ensurePDFExtension(outFile)
+	}
+
+	selectedPages, err := api.ParsePageSelection(selectedPages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "problem with flag selectedPages: %v\n", err)
+		os.Exit(1)
+	}
+
+	process(cli.RemoveHeaderFooterCommand(inFile, outFile, selectedPages, conf))
+}
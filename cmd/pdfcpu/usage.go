@@ -25,8 +25,9 @@ Usage:
    
 The commands are:
 
-   annotations   list, remove page annotations
+   annotations   list, remove, flatten page annotations
    attachments   list, add, remove, extract embedded file attachments
+   bates         stamp Bates numbers continuing across files
    booklet       arrange pages onto larger sheets of paper to make a booklet or zine
    bookmarks     list, import, export, remove bookmarks
    boxes         list, add, remove page boundaries for selected pages
@@ -35,23 +36,28 @@ The commands are:
    collect       create custom sequence of selected pages
    config        list, reset configuration
    create        create PDF content including forms via JSON
+   convert       convert PDF to PDF/A
    crop          set cropbox for selected pages
    cut           custom cut pages horizontally or vertically
    decrypt       remove password protection
+   diff          compare two PDF files
    encrypt       set password protection		
-   extract       extract images, fonts, content, pages or metadata
+   extract       extract images, fonts, content, text, pages or metadata
    fonts         install, list supported fonts, create cheat sheets
-   form          list, remove fields, lock, unlock, reset, export, fill form via JSON or CSV
+   form          list, remove fields, lock, unlock, reset, flatten, export, fill form via JSON or CSV
    grid          rearrange pages or images for enhanced browsing experience
+   headerfooter  add, remove page headers and footers
    images        list, extract, update images
    import        import/convert images to PDF
    info          print file info
    keywords      list, add, remove keywords
+   layers        list, add, remove, show, hide, rename, merge, flatten layers
    merge         concatenate PDFs
    ndown         cut selected pages into n pages symmetrically
    nup           rearrange pages or images for reduced number of pages
    optimize      optimize PDF by getting rid of redundant page resources
    pagelayout    list, set, reset page layout for opened document
+   pagelabels    list, set, remove page labels
    pagemode      list, set, reset page mode for opened document
    pages         insert, remove selected pages
    paper         print list of supported paper sizes
@@ -59,9 +65,14 @@ The commands are:
    portfolio     list, add, remove, extract portfolio entries with optional description
    poster        cut selected pages into poster by paper size or dimensions
    properties    list, add, remove document properties
+   redact        apply redactions by removing the underlying content
+   render        render pages into images
    resize        scale selected pages
+   revisions     list, extract revisions of incrementally updated files
    rotate        rotate selected pages
    selectedpages print definition of the -pages flag
+   sign          digitally sign a PDF (PKCS#7 or PAdES)
+   signatures    verify digital signatures
    split         split up a PDF by span or bookmark
    stamp         add, remove, update Unicode text, image or PDF stamps for selected pages
    trim          create trimmed version of selected pages
@@ -90,11 +101,12 @@ common flags: -v(erbose)  ... turn on logging
                                                   cm ... centimetres
                                                   mm ... millimetres`
 
-	usageValidate = "usage: pdfcpu validate [-m(ode) strict|relaxed] [-l(inks) -opt(imize)] inFile..." + generalFlags
+	usageValidate = "usage: pdfcpu validate [-m(ode) strict|relaxed] [-profile pdfa-1b|pdfa-2b|pdfa-3b] [-l(inks) -opt(imize)] inFile..." + generalFlags
 
 	usageLongValidate = `Check inFile for specification compliance.
 
       mode ... validation mode
+   profile ... additionally check for PDF/A conformance
      links ... check for broken links
   optimize ... optimize resources (fonts, forms, images)
     inFile ... input PDF file
@@ -103,14 +115,27 @@ The validation modes are:
     strict ... validates against PDF 32000-1:2008 (PDF 1.7) and rudimentary against PDF 32000:2 (PDF 2.0)
    relaxed ... (default) like strict but doesn't complain about common seen spec violations.
 
+The validation profiles are:
+   pdfa-1b ... ISO 19005-1 (PDF/A-1) level B
+   pdfa-2b ... ISO 19005-2 (PDF/A-2) level B
+   pdfa-3b ... ISO 19005-3 (PDF/A-3) level B, like pdfa-2b but allows embedded files of any kind
+
+Each PDF/A violation is reported with its ISO 19005 clause and object number.
+
 Validation turns off optimization unless in verbose mode.
 You can enforce optimization using -opt=true.`
 
-	usageOptimize     = "usage: pdfcpu optimize [-stats csvFile] inFile [outFile]" + generalFlags
+	usageOptimize     = "usage: pdfcpu optimize [-stats csvFile] [-linearize] [-dpi n] [-quality n] [-gray] [-subset] inFile [outFile]" + generalFlags
 	usageLongOptimize = `Read inFile, remove redundant page resources like embedded fonts and images and write the result to outFile.
 
      stats ... appends a stats line to a csv file with information about the usage of root and page entries.
                useful for batch optimization and debugging PDFs.
+ linearize ... write a linearized file (aka "fast web view") for page at a time downloading via HTTP range requests.
+       dpi ... downsample images placed with a higher resolution to n dots per inch.
+   quality ... recompress Flate encoded photos as JPEG using quality n (1..100).
+      gray ... convert RGB images containing gray pixels only to DeviceGray.
+               Images getting bigger by re-encoding are left untouched.
+    subset ... reduce embedded TrueType fonts to the glyphs used, except for fonts used by form fields.
     inFile ... input PDF file
    outFile ... output PDF file`
 
@@ -198,15 +223,20 @@ Skip optimization before writing: -opt(imize)=false`
    l-3- ... include last 3 pages         l-3 ... include page # last-3
   -l-3  ... include all, but last 3    2-l-1 ... pages 2 up to "last-1"
 
+  label:iv ... include page labelled iv    label:iv-x ... include pages labelled iv - x
+ !label:iv ... exclude page labelled iv   !label:iv-x ... exclude pages labelled iv - x
+
 	n serves as an alternative for !, since ! needs to be escaped with single quotes on the cmd line.
 
-        e.g. -3,5,7- or 4-7,!6 or 1-,!5 or odd,n1`
+        e.g. -3,5,7- or 4-7,!6 or 1-,!5 or odd,n1 or label:i-iv`
 
-	usageExtract     = "usage: pdfcpu extract -m(ode) i(mage)|f(ont)|c(ontent)|p(age)|m(eta) [-p(ages) selectedPages] inFile outDir" + generalFlags
-	usageLongExtract = `Export inFile's images, fonts, content or pages into outDir.
+	usageExtract     = "usage: pdfcpu extract -m(ode) i(mage)|f(ont)|c(ontent)|t(ext)|p(age)|m(eta) [-p(ages) selectedPages] [-j(son)] [-lazy] inFile outDir" + generalFlags
+	usageLongExtract = `Export inFile's images, fonts, content, text or pages into outDir.
 
       mode ... extraction mode
      pages ... Please refer to "pdfcpu selectedpages"
+      json ... text mode only: produce JSON including glyph and line bounding boxes
+      lazy ... page mode only: read objects on demand (for very large files)
     inFile ... input PDF file
     outDir ... output directory
 
@@ -215,6 +245,7 @@ Skip optimization before writing: -opt(imize)=false`
   image ... extract images
    font ... extract font files (supported font types: TrueType)
 content ... extract raw page content
+   text ... extract page text
    page ... extract single page PDFs
    meta ... extract all metadata (page selection does not apply)
    
@@ -229,7 +260,7 @@ content ... extract raw page content
    
 `
 
-	usageAttachList    = "pdfcpu attachments list    inFile"
+	usageAttachList    = "pdfcpu attachments list    [-j(son)] inFile"
 	usageAttachAdd     = "pdfcpu attachments add     inFile file..."
 	usageAttachRemove  = "pdfcpu attachments remove  inFile [file...]"
 	usageAttachExtract = "pdfcpu attachments extract inFile outDir [file...]"
@@ -272,7 +303,7 @@ content ... extract raw page content
            pdfcpu portfolio add test.pdf "test.mp3, Test sound file" "test.mkv, Test video file"
     `
 
-	usagePermList = "pdfcpu permissions list [-upw userpw] [-opw ownerpw] inFile..."
+	usagePermList = "pdfcpu permissions list [-j(son)] [-upw userpw] [-opw ownerpw] inFile..."
 	usagePermSet  = "pdfcpu permissions set [-perm none|print|all|max4Hex|max12Bits] [-upw userpw] -opw ownerpw inFile"
 
 	usagePerm = "usage: " + usagePermList +
@@ -306,20 +337,26 @@ content ... extract raw page content
      11: Assemble document (security handlers >= rev.3)
      12: Print (security handlers >= rev.3)`
 
-	usageEncrypt     = "usage: pdfcpu encrypt [-m(ode) rc4|aes] [-key 40|128|256] [-perm none|print|all] [-upw userpw] -opw ownerpw inFile [outFile]" + generalFlags
-	usageLongEncrypt = `Setup password protection based on user and owner password.
+	usageEncrypt = "usage: pdfcpu encrypt [-m(ode) rc4|aes] [-key 40|128|256] [-perm none|print|all] [-upw userpw] -opw ownerpw inFile [outFile]" +
+		"\n       pdfcpu encrypt -cert recipients.pem [-perm none|print|all] inFile [outFile]" + generalFlags
+	usageLongEncrypt = `Setup password protection based on user and owner password
+or public-key encryption for certificate recipients.
 
       mode ... algorithm (default=aes)
        key ... key length in bits (default=256)
       perm ... user access permissions
+      cert ... recipient certificates (PEM)
     inFile ... input PDF file
    outFile ... output PDF file
    
-   PDF 2.0 files have to be encrypted using aes/256.`
+   PDF 2.0 files have to be encrypted using aes/256.
+   Public-key encryption (Adobe.PubSec) uses aes/256 and RSA recipient certificates.`
 
-	usageDecrypt     = "usage: pdfcpu decrypt [-upw userpw] [-opw ownerpw] inFile [outFile]" + generalFlags
+	usageDecrypt     = "usage: pdfcpu decrypt [-upw userpw] [-opw ownerpw] [-keyfile private.pem [-cert cert.pem]] inFile [outFile]" + generalFlags
 	usageLongDecrypt = `Remove password protection and reset permissions.
 
+   keyfile ... recipient private key (PEM) for public-key encryption
+      cert ... optional recipient certificate (PEM) selecting the recipient
     inFile ... input PDF file
    outFile ... output PDF file`
 
@@ -339,7 +376,7 @@ content ... extract raw page content
     opwOld ... old owner password (provide user password on initial changeopw)
     opwNew ... new owner password`
 
-	usageStampMode = `There are 3 different kinds of stamps:
+	usageStampMode = `There are 4 different kinds of stamps:
 
    1) text based:
       -mode text string			
@@ -367,9 +404,18 @@ content ... extract raw page content
          Customize your multistamp by starting with startPage#Src of a stamp PDF file.
          Apply repeatedly pages of the stamp file to inFile starting at startPage#Dest.
          Eg: pdfcpu stamp add -mode pdf -- "stamp.pdf:2:3" "" in.pdf out.pdf ... multistamp starting with page 2 of stamp.pdf onto page 3 of in.pdf
+
+   4) barcode based
+      -mode barcode type:content
+         supported types: qr, qr-l, qr-m, qr-q, qr-h (error correction level), datamatrix, code128, ean13
+         Use the following format strings within content:
+               %p ... current page number
+               %P ... total pages
+         eg. pdfcpu stamp add -mode barcode -- "qr:https://example.com/doc?page=%p" "pos:br, off:-20 20, scale:.15" in.pdf out.pdf
+             pdfcpu stamp add -mode barcode -- "ean13:400638133393" "pos:bl, scale:.3, bgcolor:#FFFFFF" in.pdf out.pdf
    `
 
-	usageWatermarkMode = `There are 3 different kinds of watermarks:
+	usageWatermarkMode = `There are 4 different kinds of watermarks:
 
    1) text based:
       -mode text string			
@@ -398,6 +444,15 @@ content ... extract raw page content
          Apply repeatedly pages of the watermark file to inFile starting at startPage#Dest.
          Eg: pdfcpu watermark add -mode pdf -- "watermark.pdf:2:3" "" in.pdf out.pdf ... multiwatermark starting with page 2 of watermark.pdf onto page 3 of in.pdf
 
+   4) barcode based
+      -mode barcode type:content
+         supported types: qr, qr-l, qr-m, qr-q, qr-h (error correction level), datamatrix, code128, ean13
+         Use the following format strings within content:
+               %p ... current page number
+               %P ... total pages
+         eg. pdfcpu watermark add -mode barcode -- "qr:https://example.com/doc?page=%p" "pos:br, off:-20 20, scale:.15" in.pdf out.pdf
+             pdfcpu watermark add -mode barcode -- "ean13:400638133393" "pos:bl, scale:.3, bgcolor:#FFFFFF" in.pdf out.pdf
+
    A watermark is the first content that gets rendered for a page.
    The visibility of the watermark depends on the transparency of all layers rendered on top.
 `
@@ -438,7 +493,8 @@ content ... extract raw page content
    
    strokecolor:      color value to be used when rendering text, see also rendermode
    
-   backgroundcolor:  color value for visualization of the bounding box background for text.
+   backgroundcolor:  color value for visualization of the bounding box background for text
+                     or the background including the quiet zone of barcodes.
                      "bgcolor" is also accepted. 
    
    rotation:         -180.0 <= x <= 180.0
@@ -483,8 +539,8 @@ e.g. "pos:bl, off: 20 5"   "rot:45"                 "op:0.5, scale:0.5 abs, rot:
 
 `
 
-	usageStampAdd    = "pdfcpu stamp add    [-p(ages) selectedPages] -m(ode) text|image|pdf -- string|file description inFile [outFile]"
-	usageStampUpdate = "pdfcpu stamp update [-p(ages) selectedPages] -m(ode) text|image|pdf -- string|file description inFile [outFile]"
+	usageStampAdd    = "pdfcpu stamp add    [-p(ages) selectedPages] -m(ode) text|image|pdf|barcode -- string|file|code description inFile [outFile]"
+	usageStampUpdate = "pdfcpu stamp update [-p(ages) selectedPages] -m(ode) text|image|pdf|barcode -- string|file|code description inFile [outFile]"
 	usageStampRemove = "pdfcpu stamp remove [-p(ages) selectedPages] inFile [outFile]"
 
 	usageStamp = "usage: " + usageStampAdd +
@@ -506,8 +562,8 @@ description ... fontname, points, position, offset, scalefactor, aligntext, rota
 
 ` + usageStampMode + usageWMDescription
 
-	usageWatermarkAdd    = "pdfcpu watermark add    [-p(ages) selectedPages] -m(ode) text|image|pdf -- string|file description inFile [outFile]"
-	usageWatermarkUpdate = "pdfcpu watermark update [-p(ages) selectedPages] -m(ode) text|image|pdf -- string|file description inFile [outFile]"
+	usageWatermarkAdd    = "pdfcpu watermark add    [-p(ages) selectedPages] -m(ode) text|image|pdf|barcode -- string|file|code description inFile [outFile]"
+	usageWatermarkUpdate = "pdfcpu watermark update [-p(ages) selectedPages] -m(ode) text|image|pdf|barcode -- string|file|code description inFile [outFile]"
 	usageWatermarkRemove = "pdfcpu watermark remove [-p(ages) selectedPages] inFile [outFile]"
 
 	usageWatermark = "usage: " + usageWatermarkAdd +
@@ -541,7 +597,7 @@ description ... dimensions, formsize, position, offset, scale factor, boxes
 
   optional entries:
 
-      (defaults: "dim:595 842, f:A4, pos:full, off:0 0, sc:0.5 rel, dpi:72, gray:off, sepia:off")
+      (defaults: "dim:595 842, f:A4, pos:full, off:0 0, sc:0.5 rel, dpi:72, gray:off, sepia:off, bilevel:off")
 
   dimensions:      (width height) in given display unit eg. '400 200' setting the media box
 
@@ -567,6 +623,9 @@ description ... dimensions, formsize, position, offset, scale factor, boxes
 
   sepia:           Apply sepia effect (on/off, true/false, t/f)
 
+  bilevel:         Convert to black and white (on/off, true/false, t/f)
+                   Black and white images are stored CCITT Group 4 encoded.
+
   backgroundcolor: "bgcolor" is also accepted.
   
   Only one of dimensions or formsize is allowed.
@@ -701,6 +760,89 @@ Examples: pdfcpu nup out.pdf 4 in.pdf
            Rearrange all jpg files into 2x2 grids and write result to out.pdf using the Tabloid form size
            and the default orientation.
 
+`
+
+	usageBates     = "usage: pdfcpu bates [-prefix prefix] [-start n] [-digits n] [-props] -- [description] inFile... outDir" + generalFlags
+	usageLongBates = `Stamp every page of inFiles with a sequential Bates number continuing across all files.
+
+     prefix ... prefix of each Bates number
+      start ... first Bates number, default: 1
+     digits ... Bates numbers get zero-padded to this number of digits, default: 6
+      props ... record each file's Bates range as document properties BatesBegin and BatesEnd
+description ... stamp configuration, see "pdfcpu help stamp"
+     inFile ... a list of PDF input files
+     outDir ... output directory
+
+The stamped files are written to outDir using their original file names.
+outDir/bates.csv logs the Bates number of each page along with the Bates range of each file.
+
+By default Bates numbers are placed upright into the lower right corner of each page using Helvetica 10pt:
+   "pos:br, off:-10 10, rot:0, scale:1 abs, points:10, fillc:#000000"
+Any description provided gets applied on top of this default.
+
+Examples:
+   pdfcpu bates -prefix ABC in1.pdf in2.pdf out
+     Stamp ABC000001 ... onto the pages of in1.pdf followed by in2.pdf.
+
+   pdfcpu bates -prefix ABC -start 1001 -digits 8 -props -- "pos:bl, off:10 10" *.pdf out
+     Stamp ABC00001001 ... into the lower left corner and record the Bates range of each file.
+`
+
+	usageHeaderFooterAdd    = "pdfcpu headerfooter add    [-p(ages) selectedPages] -- description inFile [outFile]"
+	usageHeaderFooterRemove = "pdfcpu headerfooter remove [-p(ages) selectedPages] inFile [outFile]"
+
+	usageHeaderFooter = "usage: " + usageHeaderFooterAdd +
+		"\n       " + usageHeaderFooterRemove + generalFlags
+
+	usageLongHeaderFooter = `Manage running headers and footers for selected pages.
+
+      pages ... Please refer to "pdfcpu selectedpages"
+description ... slot texts and configuration
+     inFile ... input PDF file
+    outFile ... output PDF file
+
+    <description> is a comma separated configuration string containing:
+
+    slots: (at least one is required)
+
+        hl, hc, hr:  left, center, right header text
+        fl, fc, fr:  left, center, right footer text
+
+    optional entries:
+
+        (defaults: "margin:20, fontname:Helvetica, points:9, fillcolor:#000000, mirror:off, shrink:on")
+
+        margin:      distance between header/footer and the page edges in given display unit
+        fontname:    Please refer to "pdfcpu fonts list"
+        points:      font size in points
+        fillcolor:   text color value, see "pdfcpu help stamp"
+        mirror:      swap left and right slots on even pages (on/off, true/false, t/f)
+        shrink:      shrink page content overlapping header or footer (on/off, true/false, t/f)
+
+    Slot texts support these placeholders:
+
+        %p      page number
+        %P      page count
+        %l      page label
+        %f      file name
+        %d      date
+        %t      timestamp
+        %v      pdfcpu version
+        %{key}  document property eg. %{Title}, %{Author} or any custom property
+
+    Use \n for line breaks and \, for commas within slot texts.
+
+    Adding replaces any header and footer added before.
+    Removing leaves all other stamps and watermarks untouched.
+
+    add ... add header and footer
+ remove ... remove header and footer
+
+Examples:
+   pdfcpu headerfooter add -- "hl:%f, hr:Page %p of %P" in.pdf
+   pdfcpu headerfooter add -pages 2- -- "hc:%{Title}, fr:%l, mirror:on, margin:30" in.pdf out.pdf
+   pdfcpu headerfooter add -- "fc:Confidential\, do not distribute, points:12, fillc:#FF0000" in.pdf
+   pdfcpu headerfooter remove in.pdf
 `
 
 	usageBooklet     = "usage: pdfcpu booklet [-p(ages) selectedPages] -- [description] outFile n inFile|imageFiles..." + generalFlags
@@ -946,15 +1088,16 @@ Examples: pdfcpu grid out.pdf 1 10 in.pdf
 	usageSelectedPages     = "usage: pdfcpu selectedpages"
 	usageLongSelectedPages = "Print definition of the -pages flag."
 
-	usageInfo     = "usage: pdfcpu info [-p(ages) selectedPages] [-f(onts) -j(son)] inFile..." + generalFlags
+	usageInfo     = "usage: pdfcpu info [-p(ages) selectedPages] [-f(onts) -j(son)] [-lazy] inFile..." + generalFlags
 	usageLongInfo = `Print info about a PDF file.
    
    pages ... Please refer to "pdfcpu selectedpages"
    fonts ... include font info
     json ... output JSON
+    lazy ... read objects on demand (for very large files, ignored for fonts)
   inFile ... a list of PDF input files`
 
-	usageFontsList       = "pdfcpu fonts list"
+	usageFontsList       = "pdfcpu fonts list [-j(son)]"
 	usageFontsInstall    = "pdfcpu fonts install fontFiles..."
 	usageFontsCheatSheet = "pdfcpu fonts cheatsheet fontFiles..."
 
@@ -965,7 +1108,7 @@ Examples: pdfcpu grid out.pdf 1 10 in.pdf
 Install given True Type fonts(.ttf) or True Type collections(.ttc) for usage in stamps/watermarks.
 Create single page PDF cheat sheets in current dir.`
 
-	usageKeywordsList   = "pdfcpu keywords list    inFile"
+	usageKeywordsList   = "pdfcpu keywords list    [-j(son)] inFile"
 	usageKeywordsAdd    = "pdfcpu keywords add     inFile keyword..."
 	usageKeywordsRemove = "pdfcpu keywords remove  inFile [keyword...]"
 
@@ -985,7 +1128,95 @@ Create single page PDF cheat sheets in current dir.`
            pdfcpu keywords remove test.pdf
     `
 
-	usagePropertiesList   = "pdfcpu properties list    inFile"
+	usageLayersList    = "pdfcpu layers list    [-j(son)] inFile"
+	usageLayersAdd     = "pdfcpu layers add     inFile name"
+	usageLayersRemove  = "pdfcpu layers remove  inFile name"
+	usageLayersShow    = "pdfcpu layers show    inFile name"
+	usageLayersHide    = "pdfcpu layers hide    inFile name"
+	usageLayersRename  = "pdfcpu layers rename  inFile oldName newName"
+	usageLayersMerge   = "pdfcpu layers merge   inFile from to"
+	usageLayersFlatten = "pdfcpu layers flatten inFile [outFile]"
+
+	usageLayers = "usage: " + usageLayersList +
+		"\n       " + usageLayersAdd +
+		"\n       " + usageLayersRemove +
+		"\n       " + usageLayersShow +
+		"\n       " + usageLayersHide +
+		"\n       " + usageLayersRename +
+		"\n       " + usageLayersMerge +
+		"\n       " + usageLayersFlatten + generalFlags
+
+	usageLongLayers = `Manage layers (optional content groups).
+
+   inFile ... input PDF file
+  outFile ... output PDF file
+     name ... layer name
+     from ... layer to be merged into another one
+       to ... layer receiving the content of from
+
+The visibility of a layer refers to the default configuration used when opening the document.
+
+   list ... list all layers along with their default visibility
+    add ... add an empty layer
+ remove ... remove a layer along with all of its content
+   show ... turn a layer on by default
+   hide ... turn a layer off by default
+ rename ... rename a layer
+  merge ... move all content of layer from into layer to and remove layer from
+flatten ... remove all hidden content and make all visible content unconditional
+
+Examples:
+   pdfcpu layers list in.pdf
+   pdfcpu layers hide in.pdf Watermark
+   pdfcpu layers merge in.pdf 'Notes draft' Notes
+   pdfcpu layers flatten in.pdf out.pdf
+`
+
+	usagePageLabelsList   = "pdfcpu pagelabels list   [-j(son)] inFile"
+	usagePageLabelsSet    = "pdfcpu pagelabels set    [-p(ages) selectedPages] -- description inFile [outFile]"
+	usagePageLabelsRemove = "pdfcpu pagelabels remove inFile [outFile]"
+
+	usagePageLabels = "usage: " + usagePageLabelsList +
+		"\n       " + usagePageLabelsSet +
+		"\n       " + usagePageLabelsRemove + generalFlags
+
+	usageLongPageLabels = `Manage page labels.
+
+        pages ... Please refer to "pdfcpu selectedpages"
+  description ... page label style, prefix and start number
+       inFile ... input PDF file
+      outFile ... output PDF file
+
+    <description> is a comma separated configuration string containing:
+
+    optional entries:
+
+        (defaults: "style:decimal, start:1")
+
+        style:   decimal, Roman, roman, Alpha, alpha, none
+                 (or the PDF style names D, R, r, A, a)
+        prefix:  label prefix
+        start:   value of the numeric portion of the first label, an integer >= 1
+
+    Each run of consecutive selected pages gets numbered beginning with start.
+    The labels of all other pages remain unchanged.
+
+    Page labels are preserved when pages get removed, extracted, split or merged
+    and may be used for page selection, e.g. -pages label:iv-x
+
+   list ... list page label ranges
+    set ... label selected pages
+ remove ... remove all page labels
+
+Examples:
+   pdfcpu pagelabels list in.pdf
+   pdfcpu pagelabels set -pages 1-4 -- "style:roman" in.pdf
+   pdfcpu pagelabels set -pages 5- -- "style:decimal" in.pdf
+   pdfcpu pagelabels set -pages 20- -- "style:Alpha, prefix:A-" in.pdf out.pdf
+   pdfcpu pagelabels remove in.pdf
+`
+
+	usagePropertiesList   = "pdfcpu properties list    [-j(son)] [-lazy] inFile"
 	usagePropertiesAdd    = "pdfcpu properties add     inFile nameValuePair..."
 	usagePropertiesRemove = "pdfcpu properties remove  inFile [name...]"
 
@@ -995,6 +1226,7 @@ Create single page PDF cheat sheets in current dir.`
 
 	usageLongProperties = `Manage document properties.
 
+         lazy ... list: read objects on demand (for very large files)
        inFile ... input PDF file
 nameValuePair ... 'name = value'
          name ... property name
@@ -1071,7 +1303,7 @@ Examples:
 
 ` + usageBoxDescription
 
-	usageBoxesList   = "pdfcpu boxes list    [-p(ages) selectedPages] -- [boxTypes] inFile"
+	usageBoxesList   = "pdfcpu boxes list    [-p(ages) selectedPages] [-j(son)] -- [boxTypes] inFile"
 	usageBoxesAdd    = "pdfcpu boxes add     [-p(ages) selectedPages] -- description inFile [outFile]"
 	usageBoxesRemove = "pdfcpu boxes remove  [-p(ages) selectedPages] -- boxTypes inFile [outFile]"
 
@@ -1103,15 +1335,18 @@ Examples:
      
 ` + usageBoxDescription
 
-	usageAnnotsList   = "pdfcpu annotations list   [-p(ages) selectedPages] inFile"
-	usageAnnotsRemove = "pdfcpu annotations remove [-p(ages) selectedPages] inFile [outFile] [objNr|annotId|annotType]..."
+	usageAnnotsList    = "pdfcpu annotations list    [-p(ages) selectedPages] [-j(son)] inFile"
+	usageAnnotsRemove  = "pdfcpu annotations remove  [-p(ages) selectedPages] inFile [outFile] [objNr|annotId|annotType]..."
+	usageAnnotsFlatten = "pdfcpu annotations flatten [-p(ages) selectedPages] [-m(ode) all|print|screen] inFile [outFile] [annotType]..."
 
 	usageAnnots = "usage: " + usageAnnotsList +
-		"\n       " + usageAnnotsRemove + generalFlags
+		"\n       " + usageAnnotsRemove +
+		"\n       " + usageAnnotsFlatten + generalFlags
 
 	usageLongAnnots = `Manage annotations.
    
       pages ... Please refer to "pdfcpu selectedpages"
+       mode ... flatten all annotations except hidden ones (default), annotations to be printed or annotations displayed on screen
      inFile ... input PDF file
       objNr ... obj# from "pdfcpu annotations list"
     annotId ... id from "pdfcpu annotations list"
@@ -1143,9 +1378,17 @@ Examples:
 
       Remove annotations by type, id and obj# and write to out.pdf:
          pdfcpu annot remove in.pdf out.pdf Link 30 Text someId
+
+      Flatten all markup annotations except Redact into the page content and write to out.pdf:
+         pdfcpu annot flatten in.pdf out.pdf
+
+      Flatten all printable Ink and Stamp annotations on page 3:
+         pdfcpu annot flatten -pages 3 -mode print in.pdf Ink Stamp
+
+      Hidden annotations and annotations without appearance stream are left untouched.
       `
 
-	usageImagesList    = "pdfcpu images list    [-p(ages) selectedPages] -- inFile..."
+	usageImagesList    = "pdfcpu images list    [-p(ages) selectedPages] [-j(son)] -- inFile..."
 	usageImagesExtract = "pdfcpu images extract [-p(ages) selectedPages] -- inFile outDir"
 	usageImagesUpdate  = "pdfcpu images update inFile imageFile [outFile] [ objNr | (pageNr Id) ]"
 
@@ -1222,11 +1465,12 @@ For more info on json syntax & samples please refer to :
    pdfcpu/pkg/testdata/json/*
    pdfcpu/pkg/samples/create/*`
 
-	usageFormListFields   = "pdfcpu form list   inFile..."
+	usageFormListFields   = "pdfcpu form list   [-j(son)] inFile..."
 	usageFormRemoveFields = "pdfcpu form remove inFile [outFile] <fieldID|fieldName>..."
 	usageFormLock         = "pdfcpu form lock   inFile [outFile] [fieldID|fieldName]..."
 	usageFormUnlock       = "pdfcpu form unlock inFile [outFile] [fieldID|fieldName]..."
 	usageFormReset        = "pdfcpu form reset  inFile [outFile] [fieldID|fieldName]..."
+	usageFormFlatten      = "pdfcpu form flatten inFile [outFile] [fieldID|fieldName]..."
 	usageFormExport       = "pdfcpu form export inFile [outFileJSON]"
 	usageFormFill         = "pdfcpu form fill inFile inFileJSON [outFile]"
 	usageFormMultiFill    = "pdfcpu form multifill [-m(ode) single|merge] inFile inFileData outDir [outName]"
@@ -1236,6 +1480,7 @@ For more info on json syntax & samples please refer to :
 		"\n       " + usageFormLock +
 		"\n       " + usageFormUnlock +
 		"\n       " + usageFormReset +
+		"\n       " + usageFormFlatten +
 		"\n       " + usageFormExport +
 		"\n\n       " + usageFormFill +
 		"\n       " + usageFormMultiFill + generalFlags
@@ -1285,17 +1530,22 @@ Supported usecases:
          "pdfcpu form reset in.pdf" resets the whole form of in.pdf.
          You may supply a mixed list of field ids and field names.
        
-   6) Export all form fields as preparation for form filling:
+   6) Flatten some or all fields:
+         "pdfcpu form flatten in.pdf signature" paints the field "signature" into the page content and removes the field.
+         "pdfcpu form flatten in.pdf out.pdf" turns the whole form of in.pdf into static page content written to out.pdf.
+         You may supply a mixed list of field ids and field names.
+
+   7) Export all form fields as preparation for form filling:
          "pdfcpu form export in.pdf" exports field data into a JSON structure written to in.json.
    
-   7) Fill a form with data:
+   8) Fill a form with data:
          a) Export your form into in.json and edit the field values.
          b) Optionally trim down each field to id or name and value(s).
          c) "pdfcpu form fill in.pdf in.json out.pdf" fills in.pdf with form data from in.json and writes the result to out.pdf.
 
    or
 
-   8) Generate a sequence of filled instances of a form:
+   9) Generate a sequence of filled instances of a form:
          a) Export your form to in.json and edit the field values.
             Extend the JSON Array containing the form by using copy & paste and edit the corresponding form data.
          b) Optionally trim down each field to id or name and value(s).
@@ -1308,7 +1558,7 @@ Supported usecases:
 
    or
 
-   9) Generate a sequence of filled instances of a form and merge output:
+   10) Generate a sequence of filled instances of a form and merge output:
          a) Export your form to in.json and edit the field values.
             Extend the JSON Array containing the form by using copy & paste and edit the corresponding form data.
          b) Optionally trim down each field to id or name and value(s).
@@ -1511,7 +1761,7 @@ description ... scalefactor, dimensions, formsize, enforce, border, bgcolor
             
    See also the related commands: poster, ndown`
 
-	usageBookmarksList   = "pdfcpu bookmarks list   inFile"
+	usageBookmarksList   = "pdfcpu bookmarks list   [-j(son)] inFile"
 	usageBookmarksImport = "pdfcpu bookmarks import [-r(eplace)] inFile inFileJSON [outFile]"
 	usageBookmarksExport = "pdfcpu bookmarks export inFile [outFileJSON]"
 	usageBookmarksRemove = "pdfcpu bookmarks remove inFile [outFile]"
@@ -1529,7 +1779,7 @@ description ... scalefactor, dimensions, formsize, enforce, border, bgcolor
       outFileJSON ... output PDF file
 `
 
-	usagePageLayoutList  = "pdfcpu pagelayout list  inFile"
+	usagePageLayoutList  = "pdfcpu pagelayout list  [-j(son)] inFile"
 	usagePageLayoutSet   = "pdfcpu pagelayout set   inFile value"
 	usagePageLayoutReset = "pdfcpu pagelayout reset inFile"
 
@@ -1555,7 +1805,7 @@ description ... scalefactor, dimensions, formsize, enforce, border, bgcolor
            pdfcpu pagelayout reset test.pdf
 `
 
-	usagePageModeList  = "pdfcpu pagemode list  inFile"
+	usagePageModeList  = "pdfcpu pagemode list  [-j(son)] inFile"
 	usagePageModeSet   = "pdfcpu pagemode set   inFile value"
 	usagePageModeReset = "pdfcpu pagemode reset inFile"
 
@@ -1712,6 +1962,153 @@ Examples:
    pdfcpu zoom -unit cm -- "vmargin: 1, border:true, bgcolor:lightgray" in.pdf out.pdf ... zoom out to vertical margin of 1 cm
 `
 
+	usageSign     = "usage: pdfcpu sign -cert certFile -keyfile keyFile [-- description] inFile [outFile]" + generalFlags
+	usageLongSign = `Digitally sign a PDF file using a detached CMS signature appended as an incremental update.
+
+       cert ... PEM file containing the signer certificate followed by optional intermediate certificates
+    keyfile ... PEM file containing the unencrypted private key (RSA or ECDSA) of the signer
+description ... field, name, reason, location, contact, format, size, certify
+     inFile ... input PDF file
+    outFile ... output PDF file (if missing the signature will be appended to inFile)
+
+<description> is a comma separated configuration string containing:
+
+    field:    name of the signature field, an existing unsigned signature field will be used (default: Signature1)
+    name:     name of the person or authority signing
+    reason:   reason for signing
+    location: location of signing
+    contact:  contact info for verifying the signature
+    format:   pkcs7, pades (default: pkcs7)
+    size:     number of bytes reserved for the signature value (default: 16384)
+    certify:  create a certification signature with DocMDP permissions:
+                 1 ... no changes permitted
+                 2 ... form filling and signing permitted
+                 3 ... form filling, signing and annotating permitted
+
+Examples:
+   pdfcpu sign -cert cert.pem -keyfile key.pem in.pdf out.pdf
+   pdfcpu sign -cert cert.pem -keyfile key.pem -- "reason:Approved, location:Berlin, format:pades" in.pdf
+   pdfcpu sign -cert cert.pem -keyfile key.pem -- "certify:2" in.pdf out.pdf
+`
+
+	usageSignaturesVerify = "pdfcpu signatures verify [-trust trustStoreDir] inFile"
+
+	usageSignatures = "usage: " + usageSignaturesVerify + generalFlags
+
+	usageLongSignatures = `Verify digital signatures.
+
+trustStoreDir ... directory containing PEM encoded trusted root certificates (default: system certificate pool)
+       inFile ... input PDF file
+
+For each signature the report covers:
+   - the integrity of the signed byte range
+   - the validity of the CMS signature and the trust chain of the signer certificate
+   - the number of bytes appended by later revisions
+   - all objects touched by later revisions and whether the DocMDP/FieldMDP permissions allow these changes
+
+Examples:
+   pdfcpu signatures verify signed.pdf
+   pdfcpu signatures verify -trust myRootCAs signed.pdf
+`
+
+	usageRedactApply = "pdfcpu redact apply [-p(ages) selectedPages] inFile [outFile] ['[llx lly urx ury]'...]"
+
+	usageRedact = "usage: " + usageRedactApply + generalFlags
+
+	usageLongRedact = `Remove content for good by applying redactions.
+
+          pages ... Please refer to "pdfcpu selectedpages"
+         inFile ... input PDF file
+        outFile ... output PDF file
+llx lly urx ury ... region to be redacted on each selected page in given display unit
+
+Redactions are defined by Redact annotations and/or rectangles passed on the command line.
+All text, image pixels and vector graphics located within a redacted region are removed from the page content.
+Then the overlay color and text of each Redact annotation get painted and the annotation gets removed.
+Regions passed on the command line are filled in black.
+
+Examples:
+   pdfcpu redact apply in.pdf out.pdf
+   pdfcpu redact apply -p 1 in.pdf out.pdf '[100 600 300 650]'
+   pdfcpu redact apply -u cm in.pdf '[2 2 5 3]' '[10 2 15 3]'
+`
+
+	usageRevisionsList    = "pdfcpu revisions list [-j(son)] inFile"
+	usageRevisionsExtract = "pdfcpu revisions extract -n(r) revision inFile [outFile]"
+
+	usageRevisions = "usage: " + usageRevisionsList +
+		"\n       " + usageRevisionsExtract + generalFlags
+
+	usageLongRevisions = `Manage the revisions of incrementally updated files.
+
+      json ... output JSON
+  revision ... revision number starting with 1 for the original document
+    inFile ... input PDF file
+   outFile ... output PDF file
+
+Each incremental update appends a revision terminated by %%EOF.
+For each revision list reports the file size, the offset of its cross reference section,
+the number of objects in use and the objects added, modified or freed by the revision.
+
+extract writes the document exactly as it was at the given revision.
+If outFile is omitted inFile gets rolled back to this revision.
+
+Examples:
+   pdfcpu revisions list in.pdf
+   pdfcpu revisions extract -n 2 in.pdf out.pdf
+   pdfcpu revisions extract -n 1 in.pdf
+`
+
+	usageRender     = "usage: pdfcpu render [-p(ages) selectedPages] [-dpi n] [-format png|jpg|tif] inFile outDir" + generalFlags
+	usageLongRender = `Render selected pages into images.
+
+    pages ... Please refer to "pdfcpu selectedpages"
+      dpi ... resolution in dots per inch, defaults to 150
+   format ... image format: png (default), jpg or tif
+   inFile ... input PDF file
+   outDir ... output directory
+
+Pages are rendered including annotation appearances.
+Text is rendered using embedded fonts. Fonts that are not embedded get replaced by similar Go fonts.
+
+Examples:
+   pdfcpu render in.pdf out
+   pdfcpu render -p 1-3 -dpi 300 -format tif in.pdf out
+`
+
+	usageDiff     = "usage: pdfcpu diff [-j(son)] inFile1 inFile2" + generalFlags
+	usageLongDiff = `Compare two PDF files structurally and textually.
+
+     json ... output JSON
+  inFile1 ... original PDF file
+  inFile2 ... revised PDF file
+
+Reported are differences in page count, per page media/crop box and rotation,
+added, removed and changed annotations, changed page content on operator level,
+form field values, document properties, keywords and attachments.
+
+Eg. pdfcpu diff contract_v1.pdf contract_v2.pdf
+`
+
+	usageConvert     = "usage: pdfcpu convert -to pdfa-1b|pdfa-2b|pdfa-3b inFile [outFile]" + generalFlags
+	usageLongConvert = `Convert inFile into a PDF/A file.
+
+       to ... PDF/A conformance level
+   inFile ... input PDF file
+  outFile ... output PDF file
+
+The conversion removes encryption, JavaScript and any other actions not permitted by PDF/A,
+embeds fonts for which a matching user font is installed (see "pdfcpu fonts install"),
+adds an sRGB output intent unless there is one already
+and writes XMP metadata carrying the PDF/A identification in sync with the document info.
+
+Anything that cannot be fixed is reported like with "pdfcpu validate -profile" and no file gets written.
+
+Examples:
+   pdfcpu convert -to pdfa-2b in.pdf out.pdf
+   pdfcpu convert -to pdfa-3b in.pdf
+`
+
 	usageConfigList  = "pdfcpu config list"
 	usageConfigReset = "pdfcpu config reset"
 
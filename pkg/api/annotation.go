@@ -19,9 +19,11 @@ package api
 import (
 	"io"
 	"os"
+	"sort"
 
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 	"github.com/pkg/errors"
 )
 
@@ -41,7 +43,7 @@ func Annotations(rs io.ReadSeeker, selectedPages []string, conf *model.Configura
 		return nil, err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +51,56 @@ func Annotations(rs io.ReadSeeker, selectedPages []string, conf *model.Configura
 	return pdfcpu.AnnotationsForSelectedPages(ctx, pages), nil
 }
 
+// AnnotationInfo describes a page annotation as listed by "pdfcpu annotations list -json".
+type AnnotationInfo struct {
+	PageNr  int             `json:"page"`
+	ObjNr   int             `json:"objNr"`
+	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`
+	Rect    types.Rectangle `json:"rect"`
+	Content string          `json:"content,omitempty"`
+}
+
+// AnnotationInfos returns descriptions of page annotations of rs for selected pages sorted by page and object number.
+func AnnotationInfos(rs io.ReadSeeker, selectedPages []string, conf *model.Configuration) ([]AnnotationInfo, error) {
+	m, err := Annotations(rs, selectedPages, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	aa := []AnnotationInfo{}
+	for pageNr, pgAnnots := range m {
+		for annType, annots := range pgAnnots {
+			for objNr, ann := range annots.Map {
+				aa = append(aa, AnnotationInfo{
+					PageNr:  pageNr,
+					ObjNr:   objNr,
+					Type:    model.AnnotTypeStrings[annType],
+					ID:      ann.ID(),
+					Rect:    ann.Rectangle(),
+					Content: ann.ContentString(),
+				})
+			}
+		}
+	}
+
+	sort.Slice(aa, func(i, j int) bool {
+		if aa[i].PageNr != aa[j].PageNr {
+			return aa[i].PageNr < aa[j].PageNr
+		}
+		return aa[i].ObjNr < aa[j].ObjNr
+	})
+
+	return aa, nil
+}
+
+// ListAnnotationsFileJSON returns a JSON listing of page annotations of inFile for selected pages.
+func ListAnnotationsFileJSON(inFile string, selectedPages []string, conf *model.Configuration) ([]string, error) {
+	return listFileJSON(inFile, "annotations", func(rs io.ReadSeeker) ([]AnnotationInfo, error) {
+		return AnnotationInfos(rs, selectedPages, conf)
+	})
+}
+
 // AddAnnotations adds annotations for selected pages in rs and writes the result to w.
 func AddAnnotations(rs io.ReadSeeker, w io.Writer, selectedPages []string, ann model.AnnotationRenderer, conf *model.Configuration) error {
 	if rs == nil {
@@ -65,7 +117,7 @@ func AddAnnotations(rs io.ReadSeeker, w io.Writer, selectedPages []string, ann m
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -101,7 +153,7 @@ func AddAnnotationsAsIncrement(rws io.ReadWriteSeeker, selectedPages []string, a
 		return errors.New("Incremental writing not supported for PDF version < V1.4 (Hint: Use pdfcpu optimize then try again)")
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -293,7 +345,7 @@ func RemoveAnnotations(rs io.ReadSeeker, w io.Writer, selectedPages, idsAndTypes
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -330,7 +382,7 @@ func RemoveAnnotationsAsIncrement(rws io.ReadWriteSeeker, selectedPages, idsAndT
 		return errors.New("pdfcpu: Incremental writing unsupported for PDF version < V1.4 (Hint: Use pdfcpu optimize then try again)")
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -400,3 +452,78 @@ func RemoveAnnotationsFile(inFile, outFile string, selectedPages, idsAndTypes []
 
 	return RemoveAnnotations(f1, f2, selectedPages, idsAndTypes, objNrs, conf)
 }
+
+// FlattenAnnotations paints the normal appearances of annotations for selected pages into the page content,
+// removes the annotations and writes the result to w.
+// annotTypes defaults to all markup annotation types except Redact.
+func FlattenAnnotations(rs io.ReadSeeker, w io.Writer, selectedPages, annotTypes []string, mode pdfcpu.AnnotFlattenMode, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: FlattenAnnotations: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FLATTENANNOTATIONS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
+	if err != nil {
+		return err
+	}
+
+	ok, err := pdfcpu.FlattenAnnotations(ctx, pages, annotTypes, mode)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("pdfcpu: FlattenAnnotations: No annotation flattened")
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// FlattenAnnotationsFile paints the normal appearances of annotations for selected pages of inFile into the page content,
+// removes the annotations and writes the result to outFile.
+func FlattenAnnotationsFile(inFile, outFile string, selectedPages, annotTypes []string, mode pdfcpu.AnnotFlattenMode, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+	}
+	logWritingTo(outFile)
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return FlattenAnnotations(f1, f2, selectedPages, annotTypes, mode, conf)
+}
@@ -107,6 +107,9 @@ func ValidateContext(ctx *model.Context) error {
 	if ctx.XRefTable.Version() == model.V20 {
 		logDisclaimerPDF20()
 	}
+	if ctx.Lazy() {
+		return validate.XRefTableLazy(ctx)
+	}
 	return validate.XRefTable(ctx)
 }
 
@@ -181,6 +184,11 @@ func ReadValidateAndOptimize(rs io.ReadSeeker, conf *model.Configuration) (ctx *
 		return nil, err
 	}
 
+	if ctx.Lazy() {
+		// Optimization needs all objects in memory.
+		return ctx, nil
+	}
+
 	// With the exception of commands utilizing structs provided the Optimize step
 	// command optimization of the cross reference table is optional but usually recommended.
 	// For large or complex files it may make sense to skip optimization and set conf.Optimize = false.
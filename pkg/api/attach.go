@@ -20,7 +20,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
@@ -46,6 +48,38 @@ func Attachments(rs io.ReadSeeker, conf *model.Configuration) ([]model.Attachmen
 	return ctx.ListAttachments()
 }
 
+// AttachmentInfo describes an embedded file as listed by "pdfcpu attachments list -json".
+type AttachmentInfo struct {
+	ID          string     `json:"id"`
+	FileName    string     `json:"fileName"`
+	Description string     `json:"description,omitempty"`
+	ModTime     *time.Time `json:"modTime,omitempty"`
+}
+
+// AttachmentInfos returns descriptions of rs's attachments sorted by file name.
+func AttachmentInfos(rs io.ReadSeeker, conf *model.Configuration) ([]AttachmentInfo, error) {
+	aa, err := Attachments(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	ai := []AttachmentInfo{}
+	for _, a := range aa {
+		ai = append(ai, AttachmentInfo{ID: a.ID, FileName: a.FileName, Description: a.Desc, ModTime: a.ModTime})
+	}
+
+	sort.Slice(ai, func(i, j int) bool { return ai[i].FileName < ai[j].FileName })
+
+	return ai, nil
+}
+
+// ListAttachmentsFileJSON returns a JSON listing of inFile's attachments.
+func ListAttachmentsFileJSON(inFile string, conf *model.Configuration) ([]string, error) {
+	return listFileJSON(inFile, "attachments", func(rs io.ReadSeeker) ([]AttachmentInfo, error) {
+		return AttachmentInfos(rs, conf)
+	})
+}
+
 // AddAttachments embeds files into a PDF context read from rs and writes the result to w.
 // file is either a file name or a file name and a description separated by a comma.
 func AddAttachments(rs io.ReadSeeker, w io.Writer, files []string, coll bool, conf *model.Configuration) error {
@@ -0,0 +1,180 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// AddBatesNumbers stamps all pages of rs with consecutive Bates numbers beginning with start,
+// writes the result to w and returns the next unused Bates number.
+func AddBatesNumbers(rs io.ReadSeeker, w io.Writer, b *model.Bates, start int, conf *model.Configuration) (int, error) {
+	if rs == nil {
+		return 0, errors.New("pdfcpu: AddBatesNumbers: missing rs")
+	}
+
+	if b == nil {
+		return 0, errors.New("pdfcpu: AddBatesNumbers: missing b")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.BATES
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return 0, err
+	}
+
+	next, err := pdfcpu.AddBatesNumbers(ctx, b, start)
+	if err != nil {
+		return 0, err
+	}
+
+	return next, Write(ctx, w, conf)
+}
+
+// AddBatesNumbersFile stamps all pages of inFile with consecutive Bates numbers beginning with start,
+// writes the result to outFile and returns the next unused Bates number.
+func AddBatesNumbersFile(inFile, outFile string, b *model.Bates, start int, conf *model.Configuration) (next int, err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return 0, err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return AddBatesNumbers(f1, f2, b, start, conf)
+}
+
+func writeBatesLog(logFile string, records [][]string) error {
+	f, err := os.Create(logFile)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(records); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// AddBatesNumbersFiles stamps all pages of inFiles with Bates numbers continuing across files beginning with b.Start
+// and writes the results to outDir.
+// If logFile is set a CSV log mapping each file and page to its Bates number and each file to its Bates range gets written.
+func AddBatesNumbersFiles(inFiles []string, outDir, logFile string, b *model.Bates, conf *model.Configuration) error {
+	if b == nil {
+		return errors.New("pdfcpu: AddBatesNumbersFiles: missing b")
+	}
+
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+
+	// Detect invalid stamp descriptions before writing any file.
+	if _, err := pdfcpu.BatesWatermark(b, b.Start, conf.Unit); err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for _, inFile := range inFiles {
+		fileName := filepath.Base(inFile)
+		if seen[fileName] {
+			return errors.Errorf("pdfcpu: duplicate file name: %s", fileName)
+		}
+		seen[fileName] = true
+	}
+
+	records := [][]string{{"file", "page", "bates", "batesBegin", "batesEnd"}}
+
+	nr := b.Start
+
+	for _, inFile := range inFiles {
+		fileName := filepath.Base(inFile)
+		outFile := filepath.Join(outDir, fileName)
+
+		next, err := AddBatesNumbersFile(inFile, outFile, b, nr, conf)
+		if err != nil {
+			return errors.Wrapf(err, "%s", inFile)
+		}
+
+		for i := nr; i < next; i++ {
+			records = append(records, []string{fileName, strconv.Itoa(i - nr + 1), b.Number(i), b.Number(nr), b.Number(next - 1)})
+		}
+
+		if log.CLIEnabled() && next > nr {
+			log.CLI.Printf("%s: %s - %s\n", fileName, b.Number(nr), b.Number(next-1))
+		}
+
+		nr = next
+	}
+
+	if logFile == "" {
+		return nil
+	}
+
+	logWritingTo(logFile)
+
+	return writeBatesLog(logFile, records)
+}
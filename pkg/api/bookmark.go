@@ -50,6 +50,17 @@ func Bookmarks(rs io.ReadSeeker, conf *model.Configuration) ([]pdfcpu.Bookmark,
 	return pdfcpu.Bookmarks(ctx)
 }
 
+// ListBookmarksFileJSON returns a JSON listing of the bookmark hierarchy of inFile.
+func ListBookmarksFileJSON(inFile string, conf *model.Configuration) ([]string, error) {
+	return listFileJSON(inFile, "bookmarks", func(rs io.ReadSeeker) ([]pdfcpu.Bookmark, error) {
+		bms, err := Bookmarks(rs, conf)
+		if bms == nil {
+			bms = []pdfcpu.Bookmark{}
+		}
+		return bms, err
+	})
+}
+
 // ExportBookmarksJSON extracts outline data from rs (originating from source) and writes the result to w.
 func ExportBookmarksJSON(rs io.ReadSeeker, w io.Writer, source string, conf *model.Configuration) error {
 	if rs == nil {
@@ -57,7 +57,7 @@ func Boxes(rs io.ReadSeeker, selectedPages []string, conf *model.Configuration)
 		return nil, err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -65,6 +65,68 @@ func Boxes(rs io.ReadSeeker, selectedPages []string, conf *model.Configuration)
 	return ctx.PageBoundaries(pages)
 }
 
+// PageBoxes represents the page boundaries of a page as listed by "pdfcpu boxes list -json".
+type PageBoxes struct {
+	PageNr int `json:"page"`
+	model.PageBoundaries
+}
+
+// PageBoxesInfo returns the page boundaries wanted by pb for selected pages of rs.
+// A nil pb selects all page boundaries.
+func PageBoxesInfo(rs io.ReadSeeker, selectedPages []string, pb *model.PageBoundaries, conf *model.Configuration) ([]PageBoxes, error) {
+	pbs, err := Boxes(rs, selectedPages, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := PagesForPageSelection(len(pbs), selectedPages, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	bb := []PageBoxes{}
+	for i, b := range pbs {
+		if !pages[i+1] {
+			continue
+		}
+		d := b.CropBox().Dimensions()
+		if b.Rot%180 != 0 {
+			d.Width, d.Height = d.Height, d.Width
+		}
+		b.Orientation = "portrait"
+		if d.Landscape() {
+			b.Orientation = "landscape"
+		}
+		if pb != nil {
+			if pb.Media == nil {
+				b.Media = nil
+			}
+			if pb.Crop == nil {
+				b.Crop = nil
+			}
+			if pb.Trim == nil {
+				b.Trim = nil
+			}
+			if pb.Bleed == nil {
+				b.Bleed = nil
+			}
+			if pb.Art == nil {
+				b.Art = nil
+			}
+		}
+		bb = append(bb, PageBoxes{PageNr: i + 1, PageBoundaries: b})
+	}
+
+	return bb, nil
+}
+
+// ListBoxesFileJSON returns a JSON listing of the page boundaries wanted by pb for selected pages of inFile.
+func ListBoxesFileJSON(inFile string, selectedPages []string, pb *model.PageBoundaries, conf *model.Configuration) ([]string, error) {
+	return listFileJSON(inFile, "boxes", func(rs io.ReadSeeker) ([]PageBoxes, error) {
+		return PageBoxesInfo(rs, selectedPages, pb, conf)
+	})
+}
+
 // AddBoxes adds page boundaries for selected pages of rs and writes result to w.
 func AddBoxes(rs io.ReadSeeker, w io.Writer, selectedPages []string, pb *model.PageBoundaries, conf *model.Configuration) error {
 	if rs == nil {
@@ -81,7 +143,7 @@ func AddBoxes(rs io.ReadSeeker, w io.Writer, selectedPages []string, pb *model.P
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -154,7 +216,7 @@ func RemoveBoxes(rs io.ReadSeeker, w io.Writer, selectedPages []string, pb *mode
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -228,7 +290,7 @@ func Crop(rs io.ReadSeeker, w io.Writer, selectedPages []string, b *model.Box, c
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
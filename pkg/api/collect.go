@@ -41,6 +41,11 @@ func Collect(rs io.ReadSeeker, w io.Writer, selectedPages []string, conf *model.
 		return err
 	}
 
+	selectedPages, err = ResolvePageLabels(ctx, selectedPages)
+	if err != nil {
+		return err
+	}
+
 	pages, err := PagesForPageCollection(ctx.PageCount, selectedPages)
 	if err != nil {
 		return err
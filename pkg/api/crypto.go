@@ -17,10 +17,13 @@
 package api
 
 import (
+	"crypto"
+	"crypto/x509"
 	"io"
 	"os"
 
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
 	"github.com/pkg/errors"
 )
 
@@ -285,3 +288,23 @@ func ChangeOwnerPasswordFile(inFile, outFile string, pwOld, pwNew string, conf *
 
 	return ChangeOwnerPassword(f1, f2, pwOld, pwNew, conf)
 }
+
+// ReadRecipientCertificatesFile returns the recipient certificates for public-key encryption contained in certFile (PEM).
+func ReadRecipientCertificatesFile(certFile string) ([]*x509.Certificate, error) {
+	bb, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return sign.ParseCertificates(bb)
+}
+
+// ReadRecipientKeyFile returns the private key of a recipient of a file using public-key encryption contained in keyFile (PEM).
+func ReadRecipientKeyFile(keyFile string) (crypto.PrivateKey, error) {
+	bb, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return sign.ParsePrivateKey(bb)
+}
@@ -37,7 +37,7 @@ func prepareForCut(rs io.ReadSeeker, selectedPages []string, conf *model.Configu
 		return nil, nil, err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// Diff compares the PDF contexts read from rs1 and rs2 and returns their differences.
+func Diff(rs1, rs2 io.ReadSeeker, conf *model.Configuration) (*pdfcpu.Diff, error) {
+	if rs1 == nil || rs2 == nil {
+		return nil, errors.New("pdfcpu: Diff: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.DIFF
+
+	ctx1, err := ReadAndValidate(rs1, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx2, err := ReadAndValidate(rs2, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdfcpu.DiffContexts(ctx1, ctx2)
+}
+
+// DiffFile compares inFile1 and inFile2 and returns their differences.
+func DiffFile(inFile1, inFile2 string, conf *model.Configuration) (*pdfcpu.Diff, error) {
+	f1, err := os.Open(inFile1)
+	if err != nil {
+		return nil, err
+	}
+	defer f1.Close()
+
+	f2, err := os.Open(inFile2)
+	if err != nil {
+		return nil, err
+	}
+	defer f2.Close()
+
+	return Diff(f1, f2, conf)
+}
+
+// DiffFileJSON returns a JSON report of the differences between inFile1 and inFile2.
+func DiffFileJSON(inFile1, inFile2 string, conf *model.Configuration) ([]string, error) {
+	d, err := DiffFile(inFile1, inFile2, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	v := struct {
+		Source1 string `json:"source1"`
+		Source2 string `json:"source2"`
+		Equal   bool   `json:"equal"`
+		*pdfcpu.Diff
+	}{inFile1, inFile2, d.Equal(), d}
+
+	return marshalListing("", "diff", v)
+}
@@ -18,6 +18,7 @@ package api
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -50,7 +51,7 @@ func ExtractImagesRaw(rs io.ReadSeeker, selectedPages []string, conf *model.Conf
 		return nil, err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +87,7 @@ func ExtractImages(rs io.ReadSeeker, selectedPages []string, digestImage func(mo
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -169,7 +170,7 @@ func ExtractFonts(rs io.ReadSeeker, outDir, fileName string, selectedPages []str
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -259,7 +260,7 @@ func ExtractPages(rs io.ReadSeeker, outDir, fileName string, selectedPages []str
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -317,7 +318,7 @@ func ExtractContent(rs io.ReadSeeker, outDir, fileName string, selectedPages []s
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -371,6 +372,81 @@ func ExtractContentFile(inFile, outDir string, selectedPages []string, conf *mod
 	return ExtractContent(f, outDir, inFile, selectedPages, conf)
 }
 
+// ExtractText returns the text of selected pages of rs including glyph and line bounding boxes.
+func ExtractText(rs io.ReadSeeker, selectedPages []string, conf *model.Configuration) ([]pdfcpu.PageText, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: ExtractText: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXTRACTTEXT
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var pp []pdfcpu.PageText
+
+	for _, i := range sortedPages(pages) {
+		pt, err := pdfcpu.ExtractPageText(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		pp = append(pp, *pt)
+	}
+
+	return pp, nil
+}
+
+// ExtractTextFile writes the text of selected pages of inFile into outDir, either as plain text or as JSON including bounding boxes.
+func ExtractTextFile(inFile, outDir string, selectedPages []string, jsonOut bool, conf *model.Configuration) error {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if log.CLIEnabled() {
+		log.CLI.Printf("extracting text from %s into %s/ ...\n", inFile, outDir)
+	}
+
+	pp, err := ExtractText(f, selectedPages, conf)
+	if err != nil {
+		return err
+	}
+
+	fileName := strings.TrimSuffix(filepath.Base(inFile), ".pdf")
+
+	for _, pt := range pp {
+		var bb []byte
+		ext := "txt"
+		if jsonOut {
+			if bb, err = json.MarshalIndent(pt, "", "\t"); err != nil {
+				return err
+			}
+			ext = "json"
+		} else {
+			bb = []byte(pt.String())
+		}
+
+		outFile := filepath.Join(outDir, fmt.Sprintf("%s_Text_page_%d.%s", fileName, pt.PageNr, ext))
+		logWritingTo(outFile)
+		if err := os.WriteFile(outFile, bb, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ExtractMetadata dumps all metadata dict entries for rs into outDir.
 func ExtractMetadata(rs io.ReadSeeker, outDir, fileName string, conf *model.Configuration) error {
 	if rs == nil {
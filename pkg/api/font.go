@@ -60,6 +60,40 @@ func ListFonts() ([]string, error) {
 	return append(sscf, ssuf...), nil
 }
 
+// UserFontInfo describes an installed TrueType font.
+type UserFontInfo struct {
+	Name   string `json:"name"`
+	Glyphs int    `json:"glyphs"`
+}
+
+// FontList lists the fonts supported by pdfcpu.
+type FontList struct {
+	CoreFonts   []string       `json:"coreFonts"`
+	UserFontDir string         `json:"userFontDir"`
+	UserFonts   []UserFontInfo `json:"userFonts"`
+}
+
+// Fonts returns the supported fonts sorted by name.
+func Fonts() FontList {
+	coreFonts := font.CoreFontNames()
+	sort.Strings(coreFonts)
+
+	userFonts := []UserFontInfo{}
+	font.UserFontMetricsLock.RLock()
+	for fName, ttf := range font.UserFontMetrics {
+		userFonts = append(userFonts, UserFontInfo{Name: fName, Glyphs: ttf.GlyphCount})
+	}
+	font.UserFontMetricsLock.RUnlock()
+	sort.Slice(userFonts, func(i, j int) bool { return userFonts[i].Name < userFonts[j].Name })
+
+	return FontList{CoreFonts: coreFonts, UserFontDir: font.UserFontDir, UserFonts: userFonts}
+}
+
+// ListFontsJSON returns a JSON listing of supported fonts.
+func ListFontsJSON() ([]string, error) {
+	return marshalListing("", "fonts", Fonts())
+}
+
 // InstallFonts installs true type fonts for embedding.
 func InstallFonts(fileNames []string) error {
 	if log.CLIEnabled() {
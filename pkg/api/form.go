@@ -63,6 +63,72 @@ func FormFields(rs io.ReadSeeker, conf *model.Configuration) ([]form.Field, erro
 	return fields, err
 }
 
+// FormFieldInfo describes a form field as listed by "pdfcpu form list -json".
+type FormFieldInfo struct {
+	Pages   []int    `json:"pages"`
+	Locked  bool     `json:"locked"`
+	Type    string   `json:"type"` // one of textfield, datefield, checkbox, combobox, listbox, radiobuttongroup
+	ID      string   `json:"id"`
+	Name    string   `json:"name,omitempty"`
+	Default string   `json:"default,omitempty"`
+	Value   string   `json:"value"`
+	Options []string `json:"options,omitempty"`
+}
+
+// FormFieldList is the JSON form field listing of a single input file.
+type FormFieldList struct {
+	Source string          `json:"source"`
+	Error  string          `json:"error,omitempty"`
+	Fields []FormFieldInfo `json:"fields"`
+}
+
+var formFieldTypes = map[form.FieldType]string{
+	form.FTText:             "textfield",
+	form.FTDate:             "datefield",
+	form.FTCheckBox:         "checkbox",
+	form.FTComboBox:         "combobox",
+	form.FTListBox:          "listbox",
+	form.FTRadioButtonGroup: "radiobuttongroup",
+}
+
+// FormFieldInfos returns descriptions of all form fields of rs.
+func FormFieldInfos(rs io.ReadSeeker, conf *model.Configuration) ([]FormFieldInfo, error) {
+	fields, err := FormFields(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	ff := []FormFieldInfo{}
+	for _, f := range fields {
+		fi := FormFieldInfo{
+			Pages:   f.Pages,
+			Locked:  f.Locked,
+			Type:    formFieldTypes[f.Typ],
+			ID:      f.ID,
+			Name:    f.Name,
+			Default: f.Dv,
+			Value:   f.V,
+		}
+		if f.Opts != "" {
+			fi.Options = strings.Split(f.Opts, ",")
+		}
+		ff = append(ff, fi)
+	}
+
+	return ff, nil
+}
+
+// ListFormFieldsFilesJSON returns a JSON listing of form fields of inFiles.
+func ListFormFieldsFilesJSON(inFiles []string, conf *model.Configuration) ([]string, error) {
+	return listFilesJSON(inFiles,
+		func(rs io.ReadSeeker) ([]FormFieldInfo, error) {
+			return FormFieldInfos(rs, conf)
+		},
+		func(source string, ff []FormFieldInfo, err error) FormFieldList {
+			return FormFieldList{Source: source, Error: errString(err), Fields: ff}
+		})
+}
+
 // RemoveFormFields deletes form fields in rs and writes the result to w.
 func RemoveFormFields(rs io.ReadSeeker, w io.Writer, fieldIDsOrNames []string, conf *model.Configuration) error {
 	if rs == nil {
@@ -264,6 +330,74 @@ func UnlockFormFieldsFile(inFile, outFile string, fieldIDsOrNames []string, conf
 	return UnlockFormFields(f1, f2, fieldIDsOrNames, conf)
 }
 
+// FlattenFormFields paints the appearances of form fields in rs into the page content, removes the fields and writes the result to w.
+// Missing appearances are generated first if the form sets NeedAppearances.
+func FlattenFormFields(rs io.ReadSeeker, w io.Writer, fieldIDsOrNames []string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: FlattenFormFields: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FLATTENFORMFIELDS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	ok, err := form.FlattenFormFields(ctx, fieldIDsOrNames)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoFormFieldsAffected
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// FlattenFormFieldsFile paints the appearances of form fields in inFile into the page content, removes the fields and writes the result to outFile.
+func FlattenFormFieldsFile(inFile, outFile string, fieldIDsOrNames []string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+	}
+	logWritingTo(outFile)
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return FlattenFormFields(f1, f2, fieldIDsOrNames, conf)
+}
+
 // ResetFormFields resets form fields of rs and writes the result to w.
 func ResetFormFields(rs io.ReadSeeker, w io.Writer, fieldIDsOrNames []string, conf *model.Configuration) error {
 	if rs == nil {
@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// AddHeaderFooter adds hf to selected pages of rs and writes the result to w.
+// Any header and footer added before gets replaced.
+func AddHeaderFooter(rs io.ReadSeeker, w io.Writer, selectedPages []string, hf *model.HeaderFooter, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: AddHeaderFooter: missing rs")
+	}
+
+	if hf == nil {
+		return errors.New("pdfcpu: AddHeaderFooter: missing hf")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.ADDHEADERFOOTER
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
+	if err != nil {
+		return err
+	}
+
+	if err = pdfcpu.AddHeaderFooter(ctx, pages, hf); err != nil {
+		return err
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// AddHeaderFooterFile adds hf to selected pages of inFile and writes the result to outFile.
+// Unless set hf.FileName defaults to the base name of inFile.
+func AddHeaderFooterFile(inFile, outFile string, selectedPages []string, hf *model.HeaderFooter, conf *model.Configuration) (err error) {
+	if hf == nil {
+		return errors.New("pdfcpu: AddHeaderFooterFile: missing hf")
+	}
+
+	if hf.FileName == "" {
+		hf1 := *hf
+		hf1.FileName = filepath.Base(inFile)
+		hf = &hf1
+	}
+
+	if log.CLIEnabled() {
+		log.CLI.Printf("adding header/footer to %s\n", inFile)
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	var (
+		f1, f2 *os.File
+	)
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return AddHeaderFooter(f1, f2, selectedPages, hf, conf)
+}
+
+// RemoveHeaderFooter removes header and footer from selected pages of rs and writes the result to w.
+func RemoveHeaderFooter(rs io.ReadSeeker, w io.Writer, selectedPages []string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: RemoveHeaderFooter: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REMOVEHEADERFOOTER
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
+	if err != nil {
+		return err
+	}
+
+	if err = pdfcpu.RemoveHeaderFooter(ctx, pages); err != nil {
+		return err
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// RemoveHeaderFooterFile removes header and footer from selected pages of inFile and writes the result to outFile.
+func RemoveHeaderFooterFile(inFile, outFile string, selectedPages []string, conf *model.Configuration) (err error) {
+	if log.CLIEnabled() {
+		log.CLI.Printf("removing header/footer from %s\n", inFile)
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	var (
+		f1, f2 *os.File
+	)
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return RemoveHeaderFooter(f1, f2, selectedPages, conf)
+}
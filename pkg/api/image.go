@@ -20,6 +20,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -44,7 +45,7 @@ func Images(rs io.ReadSeeker, selectedPages []string, conf *model.Configuration)
 		return nil, err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +55,90 @@ func Images(rs io.ReadSeeker, selectedPages []string, conf *model.Configuration)
 	return ii, err
 }
 
+// ImageInfo describes an embedded image as listed by "pdfcpu images list -json".
+type ImageInfo struct {
+	PageNr      int    `json:"page"`
+	ObjNr       int    `json:"objNr"`
+	ID          string `json:"id"`
+	Type        string `json:"type"` // one of image, imask, thumb
+	SoftMask    bool   `json:"softMask"`
+	ImageMask   bool   `json:"imageMask"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	ColorSpace  string `json:"colorSpace"`
+	Components  int    `json:"components"`
+	Bpc         int    `json:"bpc"`
+	Interpolate bool   `json:"interpolate"`
+	Size        int64  `json:"size"`
+	Filters     string `json:"filters,omitempty"`
+	DecodeParms string `json:"decodeParms,omitempty"`
+}
+
+// ImageList is the JSON image listing of a single input file.
+type ImageList struct {
+	Source string      `json:"source"`
+	Error  string      `json:"error,omitempty"`
+	Images []ImageInfo `json:"images"`
+}
+
+// ImageInfos returns descriptions of all embedded images of rs for selected pages sorted by page and object number.
+func ImageInfos(rs io.ReadSeeker, selectedPages []string, conf *model.Configuration) ([]ImageInfo, error) {
+	mm, err := Images(rs, selectedPages, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	ii := []ImageInfo{}
+	for _, m := range mm {
+		for _, img := range m {
+			t := "image"
+			if img.IsImgMask {
+				t = "imask"
+			}
+			if img.Thumb {
+				t = "thumb"
+			}
+			ii = append(ii, ImageInfo{
+				PageNr:      img.PageNr,
+				ObjNr:       img.ObjNr,
+				ID:          img.Name,
+				Type:        t,
+				SoftMask:    img.HasSMask,
+				ImageMask:   img.HasImgMask,
+				Width:       img.Width,
+				Height:      img.Height,
+				ColorSpace:  img.Cs,
+				Components:  img.Comp,
+				Bpc:         img.Bpc,
+				Interpolate: img.Interpol,
+				Size:        img.Size,
+				Filters:     img.Filter,
+				DecodeParms: img.DecodeParms,
+			})
+		}
+	}
+
+	sort.Slice(ii, func(i, j int) bool {
+		if ii[i].PageNr != ii[j].PageNr {
+			return ii[i].PageNr < ii[j].PageNr
+		}
+		return ii[i].ObjNr < ii[j].ObjNr
+	})
+
+	return ii, nil
+}
+
+// ListImagesFilesJSON returns a JSON listing of embedded images of inFiles for selected pages.
+func ListImagesFilesJSON(inFiles []string, selectedPages []string, conf *model.Configuration) ([]string, error) {
+	return listFilesJSON(inFiles,
+		func(rs io.ReadSeeker) ([]ImageInfo, error) {
+			return ImageInfos(rs, selectedPages, conf)
+		},
+		func(source string, ii []ImageInfo, err error) ImageList {
+			return ImageList{Source: source, Error: errString(err), Images: ii}
+		})
+}
+
 // UpdateImages replaces the XObject identified by objNr or (pageNr and resourceId).
 func UpdateImages(rs io.ReadSeeker, rd io.Reader, w io.Writer, objNr, pageNr int, id string, conf *model.Configuration) error {
 
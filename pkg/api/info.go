@@ -36,6 +36,10 @@ func PDFInfo(rs io.ReadSeeker, fileName string, selectedPages []string, fonts bo
 		conf.ValidationMode = model.ValidationRelaxed
 	}
 	conf.Cmd = model.LISTINFO
+	if fonts {
+		// Font detection relies on optimization which needs all objects in memory.
+		conf.ReadLazy = false
+	}
 
 	ctx, err := ReadAndValidate(rs, conf)
 	if err != nil {
@@ -48,7 +52,7 @@ func PDFInfo(rs io.ReadSeeker, fileName string, selectedPages []string, fonts bo
 		}
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, false, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, false, true)
 	if err != nil {
 		return nil, err
 	}
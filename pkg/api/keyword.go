@@ -46,6 +46,17 @@ func Keywords(rs io.ReadSeeker, conf *model.Configuration) ([]string, error) {
 	return pdfcpu.KeywordsList(ctx)
 }
 
+// ListKeywordsFileJSON returns a JSON listing of the keywords of inFile.
+func ListKeywordsFileJSON(inFile string, conf *model.Configuration) ([]string, error) {
+	return listFileJSON(inFile, "keywords", func(rs io.ReadSeeker) ([]string, error) {
+		kk, err := Keywords(rs, conf)
+		if kk == nil {
+			kk = []string{}
+		}
+		return kk, err
+	})
+}
+
 // AddKeywords adds keywords to rs's infodict and writes the result to w.
 func AddKeywords(rs io.ReadSeeker, w io.Writer, files []string, conf *model.Configuration) error {
 	if rs == nil {
@@ -0,0 +1,234 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// Layers returns the optional content groups of rs along with their visibility in the default configuration.
+func Layers(rs io.ReadSeeker, conf *model.Configuration) ([]pdfcpu.Layer, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: Layers: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTLAYERS
+
+	ctx, err := ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdfcpu.Layers(ctx)
+}
+
+// ListLayersFile returns a list of the optional content groups of inFile.
+func ListLayersFile(inFile string, conf *model.Configuration) ([]string, error) {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ll, err := Layers(f, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ll) == 0 {
+		return []string{"no layers available"}, nil
+	}
+
+	ss := []string{"   id vis name"}
+	for _, l := range ll {
+		ss = append(ss, l.String())
+	}
+
+	return ss, nil
+}
+
+// ListLayersFileJSON returns a JSON listing of the optional content groups of inFile.
+func ListLayersFileJSON(inFile string, conf *model.Configuration) ([]string, error) {
+	return listFileJSON(inFile, "layers", func(rs io.ReadSeeker) ([]pdfcpu.Layer, error) {
+		ll, err := Layers(rs, conf)
+		if ll == nil {
+			ll = []pdfcpu.Layer{}
+		}
+		return ll, err
+	})
+}
+
+// updateLayers applies f to the context read from rs and writes the result to w.
+func updateLayers(rs io.ReadSeeker, w io.Writer, cmd model.CommandMode, conf *model.Configuration, f func(ctx *model.Context) error) error {
+	if rs == nil {
+		return errors.New("pdfcpu: layers: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = cmd
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	if err := f(ctx); err != nil {
+		return err
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// updateLayersFile applies f to inFile and writes the result to outFile.
+func updateLayersFile(inFile, outFile string, f func(rs io.ReadSeeker, w io.Writer) error) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return f(f1, f2)
+}
+
+// AddLayer adds an empty optional content group to rs and writes the result to w.
+func AddLayer(rs io.ReadSeeker, w io.Writer, name string, visible bool, conf *model.Configuration) error {
+	return updateLayers(rs, w, model.ADDLAYER, conf, func(ctx *model.Context) error {
+		return pdfcpu.AddLayer(ctx, name, visible)
+	})
+}
+
+// AddLayerFile adds an empty optional content group to inFile and writes the result to outFile.
+func AddLayerFile(inFile, outFile, name string, visible bool, conf *model.Configuration) error {
+	return updateLayersFile(inFile, outFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return AddLayer(rs, w, name, visible, conf)
+	})
+}
+
+// RemoveLayer removes an optional content group along with its content from rs and writes the result to w.
+func RemoveLayer(rs io.ReadSeeker, w io.Writer, name string, conf *model.Configuration) error {
+	return updateLayers(rs, w, model.REMOVELAYER, conf, func(ctx *model.Context) error {
+		return pdfcpu.RemoveLayer(ctx, name)
+	})
+}
+
+// RemoveLayerFile removes an optional content group along with its content from inFile and writes the result to outFile.
+func RemoveLayerFile(inFile, outFile, name string, conf *model.Configuration) error {
+	return updateLayersFile(inFile, outFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return RemoveLayer(rs, w, name, conf)
+	})
+}
+
+// SetLayerVisibility shows or hides an optional content group of rs by default and writes the result to w.
+func SetLayerVisibility(rs io.ReadSeeker, w io.Writer, name string, visible bool, conf *model.Configuration) error {
+	cmd := model.HIDELAYER
+	if visible {
+		cmd = model.SHOWLAYER
+	}
+	return updateLayers(rs, w, cmd, conf, func(ctx *model.Context) error {
+		return pdfcpu.SetLayerVisibility(ctx, name, visible)
+	})
+}
+
+// SetLayerVisibilityFile shows or hides an optional content group of inFile by default and writes the result to outFile.
+func SetLayerVisibilityFile(inFile, outFile, name string, visible bool, conf *model.Configuration) error {
+	return updateLayersFile(inFile, outFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return SetLayerVisibility(rs, w, name, visible, conf)
+	})
+}
+
+// RenameLayer renames an optional content group of rs and writes the result to w.
+func RenameLayer(rs io.ReadSeeker, w io.Writer, oldName, newName string, conf *model.Configuration) error {
+	return updateLayers(rs, w, model.RENAMELAYER, conf, func(ctx *model.Context) error {
+		return pdfcpu.RenameLayer(ctx, oldName, newName)
+	})
+}
+
+// RenameLayerFile renames an optional content group of inFile and writes the result to outFile.
+func RenameLayerFile(inFile, outFile, oldName, newName string, conf *model.Configuration) error {
+	return updateLayersFile(inFile, outFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return RenameLayer(rs, w, oldName, newName, conf)
+	})
+}
+
+// MergeLayers moves the content of optional content group from into to, removes from and writes the result to w.
+func MergeLayers(rs io.ReadSeeker, w io.Writer, from, to string, conf *model.Configuration) error {
+	return updateLayers(rs, w, model.MERGELAYERS, conf, func(ctx *model.Context) error {
+		return pdfcpu.MergeLayers(ctx, from, to)
+	})
+}
+
+// MergeLayersFile moves the content of optional content group from into to, removes from and writes the result to outFile.
+func MergeLayersFile(inFile, outFile, from, to string, conf *model.Configuration) error {
+	return updateLayersFile(inFile, outFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return MergeLayers(rs, w, from, to, conf)
+	})
+}
+
+// FlattenLayers removes all content of rs hidden by default, makes all remaining content unconditional
+// and writes the result to w.
+func FlattenLayers(rs io.ReadSeeker, w io.Writer, conf *model.Configuration) error {
+	return updateLayers(rs, w, model.FLATTENLAYERS, conf, pdfcpu.FlattenLayers)
+}
+
+// FlattenLayersFile removes all content of inFile hidden by default, makes all remaining content unconditional
+// and writes the result to outFile.
+func FlattenLayersFile(inFile, outFile string, conf *model.Configuration) error {
+	return updateLayersFile(inFile, outFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return FlattenLayers(rs, w, conf)
+	})
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// JSON output of list commands:
+//
+//	{
+//		"header": {"source": inFile, "version": "pdfcpu vX.Y.Z", "creation": timestamp},
+//		"<listing>": ...
+//	}
+//
+// Commands processing more than one input file omit the source in the header
+// and list one entry per file carrying its source and an optional error.
+
+func jsonHeader(source string) pdfcpu.Header {
+	return pdfcpu.Header{
+		Source:   source,
+		Version:  "pdfcpu " + model.VersionStr,
+		Creation: time.Now().Format("2006-01-02 15:04:05 MST"),
+	}
+}
+
+// listing renders a header followed by a named listing.
+type listing struct {
+	header pdfcpu.Header
+	key    string
+	v      any
+}
+
+func (l listing) MarshalJSON() ([]byte, error) {
+	h, err := json.Marshal(l.header)
+	if err != nil {
+		return nil, err
+	}
+	k, err := json.Marshal(l.key)
+	if err != nil {
+		return nil, err
+	}
+	v, err := json.Marshal(l.v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"header":`)
+	buf.Write(h)
+	buf.WriteByte(',')
+	buf.Write(k)
+	buf.WriteByte(':')
+	buf.Write(v)
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func marshalListing(source, key string, v any) ([]string, error) {
+	bb, err := json.MarshalIndent(listing{header: jsonHeader(source), key: key, v: v}, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return []string{string(bb)}, nil
+}
+
+// listFileJSON renders the result of f for inFile as JSON using key as the name of the listing.
+func listFileJSON[T any](inFile, key string, f func(rs io.ReadSeeker) (T, error)) ([]string, error) {
+	fp, err := os.Open(inFile)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	v, err := f(fp)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalListing(inFile, key, v)
+}
+
+// listFilesJSON renders the results of f for inFiles as JSON.
+// newEntry wraps the result for a file or the error processing it.
+func listFilesJSON[T, E any](inFiles []string, f func(rs io.ReadSeeker) (T, error), newEntry func(source string, v T, err error) E) ([]string, error) {
+	ee := []E{}
+
+	for _, fn := range inFiles {
+		v, err := func() (T, error) {
+			fp, err := os.Open(fn)
+			if err != nil {
+				var zero T
+				return zero, err
+			}
+			defer fp.Close()
+			return f(fp)
+		}()
+		if err != nil && len(inFiles) == 1 {
+			return nil, err
+		}
+		ee = append(ee, newEntry(fn, v, err))
+	}
+
+	return marshalListing("", "files", ee)
+}
+
+// errString returns err's message or "" for nil.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -47,7 +47,7 @@ func InsertPages(rs io.ReadSeeker, w io.Writer, selectedPages []string, before b
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -121,7 +121,7 @@ func RemovePages(rs io.ReadSeeker, w io.Writer, selectedPages []string, conf *mo
 		return err
 	}
 
-	pages, err := RemainingPagesForPageRemoval(ctx.PageCount, selectedPages, true)
+	pages, err := remainingPagesForPageRemoval(ctx, selectedPages, true)
 	if err != nil {
 		return err
 	}
@@ -196,6 +196,11 @@ func PageCount(rs io.ReadSeeker, conf *model.Configuration) (int, error) {
 		return 0, errors.New("pdfcpu: PageCount: missing rs")
 	}
 
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.PAGECOUNT
+
 	ctx, err := ReadAndValidate(rs, conf)
 	if err != nil {
 		return 0, err
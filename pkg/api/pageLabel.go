@@ -0,0 +1,230 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// PageLabels returns the page label ranges of rs or nil if rs does not use page labels.
+func PageLabels(rs io.ReadSeeker, conf *model.Configuration) ([]pdfcpu.PageLabel, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: PageLabels: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTPAGELABELS
+
+	ctx, err := ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	pls, err := pdfcpu.PageLabels(ctx)
+	if err == pdfcpu.ErrNoPageLabels {
+		return nil, nil
+	}
+
+	return pls, err
+}
+
+// ListPageLabelsFile returns a list of the page label ranges of inFile.
+func ListPageLabelsFile(inFile string, conf *model.Configuration) ([]string, error) {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	pls, err := PageLabels(f, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pls) == 0 {
+		return []string{"no page labels available"}, nil
+	}
+
+	ss := make([]string, len(pls))
+	for i, pl := range pls {
+		ss[i] = pl.String()
+	}
+
+	return ss, nil
+}
+
+// ListPageLabelsFileJSON returns a JSON listing of the page label ranges of inFile.
+func ListPageLabelsFileJSON(inFile string, conf *model.Configuration) ([]string, error) {
+	return listFileJSON(inFile, "pageLabels", func(rs io.ReadSeeker) ([]pdfcpu.PageLabel, error) {
+		pls, err := PageLabels(rs, conf)
+		if pls == nil {
+			pls = []pdfcpu.PageLabel{}
+		}
+		return pls, err
+	})
+}
+
+// SetPageLabels labels selected pages of rs as described by pl and writes the result to w.
+// Each run of consecutive selected pages gets numbered beginning with pl.Start.
+func SetPageLabels(rs io.ReadSeeker, w io.Writer, selectedPages []string, pl *pdfcpu.PageLabel, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: SetPageLabels: missing rs")
+	}
+
+	if pl == nil {
+		return errors.New("pdfcpu: SetPageLabels: missing pl")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.SETPAGELABELS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
+	if err != nil {
+		return err
+	}
+
+	if err := pdfcpu.SetPageLabels(ctx, pages, pl.Style, pl.Prefix, pl.Start); err != nil {
+		return err
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// SetPageLabelsFile labels selected pages of inFile as described by pl and writes the result to outFile.
+func SetPageLabelsFile(inFile, outFile string, selectedPages []string, pl *pdfcpu.PageLabel, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return SetPageLabels(f1, f2, selectedPages, pl, conf)
+}
+
+// RemovePageLabels removes all page labels from rs and writes the result to w.
+func RemovePageLabels(rs io.ReadSeeker, w io.Writer, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: RemovePageLabels: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REMOVEPAGELABELS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	ok, err := pdfcpu.RemovePageLabels(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return pdfcpu.ErrNoPageLabels
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// RemovePageLabelsFile removes all page labels from inFile and writes the result to outFile.
+func RemovePageLabelsFile(inFile, outFile string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return RemovePageLabels(f1, f2, conf)
+}
@@ -93,6 +93,19 @@ func ListPageLayoutFile(inFile string, conf *model.Configuration) ([]string, err
 	return ListPageLayout(f, conf)
 }
 
+// ListPageLayoutFileJSON returns a JSON listing of inFile's page layout.
+// An unset page layout is listed as null.
+func ListPageLayoutFileJSON(inFile string, conf *model.Configuration) ([]string, error) {
+	return listFileJSON(inFile, "pageLayout", func(rs io.ReadSeeker) (*string, error) {
+		pl, err := PageLayout(rs, conf)
+		if err != nil || pl == nil {
+			return nil, err
+		}
+		s := pl.String()
+		return &s, nil
+	})
+}
+
 // SetPageLayout sets rs's page layout and writes the result to w.
 func SetPageLayout(rs io.ReadSeeker, w io.Writer, val model.PageLayout, conf *model.Configuration) error {
 	if rs == nil {
@@ -93,6 +93,19 @@ func ListPageModeFile(inFile string, conf *model.Configuration) ([]string, error
 	return ListPageMode(f, conf)
 }
 
+// ListPageModeFileJSON returns a JSON listing of inFile's page mode.
+// An unset page mode is listed as null.
+func ListPageModeFileJSON(inFile string, conf *model.Configuration) ([]string, error) {
+	return listFileJSON(inFile, "pageMode", func(rs io.ReadSeeker) (*string, error) {
+		pm, err := PageMode(rs, conf)
+		if err != nil || pm == nil {
+			return nil, err
+		}
+		s := pm.String()
+		return &s, nil
+	})
+}
+
 // SetPageMode sets rs's page mode and writes the result to w.
 func SetPageMode(rs io.ReadSeeker, w io.Writer, val model.PageMode, conf *model.Configuration) error {
 	if rs == nil {
@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// ConvertToPDFA converts a PDF context read from rs into a PDF/A file of conformance level profile and writes the result to w.
+// Encryption and forbidden actions get removed, missing fonts get embedded using matching installed user fonts,
+// an sRGB output intent and XMP metadata carrying the PDF/A identification get added.
+// Violations that cannot be fixed are returned as *model.PDFAError and nothing gets written.
+func ConvertToPDFA(rs io.ReadSeeker, w io.Writer, profile string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ConvertToPDFA: missing rs")
+	}
+
+	p, ok := model.PDFAProfile(profile)
+	if !ok {
+		return errors.Errorf("pdfcpu: unsupported PDF/A conformance level: %s", profile)
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.CONVERTPDFA
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	if err := pdfcpu.ConvertToPDFA(ctx, p); err != nil {
+		return err
+	}
+
+	if err := validatePDFA(ctx, p); err != nil {
+		return err
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// ConvertToPDFAFile converts inFile into a PDF/A file of conformance level profile and writes the result to outFile.
+func ConvertToPDFAFile(inFile, outFile, profile string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return ConvertToPDFA(f1, f2, profile, conf)
+}
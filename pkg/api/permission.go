@@ -140,6 +140,71 @@ func GetPermissions(rs io.ReadSeeker, conf *model.Configuration) (*int16, error)
 	return &p, nil
 }
 
+// PermissionInfo describes the user access permissions of a file as listed by "pdfcpu permissions list -json".
+type PermissionInfo struct {
+	Source               string `json:"source"`
+	Error                string `json:"error,omitempty"`
+	FullAccess           bool   `json:"fullAccess"`
+	Bits                 int    `json:"bits"`
+	Print                bool   `json:"print"`                // bit 3
+	Modify               bool   `json:"modify"`               // bit 4
+	Extract              bool   `json:"extract"`              // bit 5
+	Annotations          bool   `json:"annotations"`          // bit 6
+	FillForms            bool   `json:"fillForms"`            // bit 9
+	ExtractAccessibility bool   `json:"extractAccessibility"` // bit 10
+	Assemble             bool   `json:"assemble"`             // bit 11
+	PrintHighQuality     bool   `json:"printHighQuality"`     // bit 12
+}
+
+// PermissionsInfo returns a description of the user access permissions for rs.
+func PermissionsInfo(rs io.ReadSeeker, conf *model.Configuration) (*PermissionInfo, error) {
+	p, err := Permissions(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if p == 0 {
+		return &PermissionInfo{
+			FullAccess:           true,
+			Print:                true,
+			Modify:               true,
+			Extract:              true,
+			Annotations:          true,
+			FillForms:            true,
+			ExtractAccessibility: true,
+			Assemble:             true,
+			PrintHighQuality:     true,
+		}, nil
+	}
+
+	return &PermissionInfo{
+		Bits:                 p & 0x0F3C,
+		Print:                p&0x0004 > 0,
+		Modify:               p&0x0008 > 0,
+		Extract:              p&0x0010 > 0,
+		Annotations:          p&0x0020 > 0,
+		FillForms:            p&0x0100 > 0,
+		ExtractAccessibility: p&0x0200 > 0,
+		Assemble:             p&0x0400 > 0,
+		PrintHighQuality:     p&0x0800 > 0,
+	}, nil
+}
+
+// ListPermissionsFilesJSON returns a JSON listing of user access permissions for inFiles.
+func ListPermissionsFilesJSON(inFiles []string, conf *model.Configuration) ([]string, error) {
+	return listFilesJSON(inFiles,
+		func(rs io.ReadSeeker) (*PermissionInfo, error) {
+			return PermissionsInfo(rs, conf)
+		},
+		func(source string, p *PermissionInfo, err error) *PermissionInfo {
+			if p == nil {
+				p = &PermissionInfo{}
+			}
+			p.Source, p.Error = source, errString(err)
+			return p
+		})
+}
+
 // GetPermissionsFile returns the permissions for inFile.
 func GetPermissionsFile(inFile string, conf *model.Configuration) (*int16, error) {
 	f, err := os.Open(inFile)
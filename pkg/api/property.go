@@ -45,6 +45,17 @@ func Properties(rs io.ReadSeeker, conf *model.Configuration) (map[string]string,
 	return ctx.Properties, nil
 }
 
+// ListPropertiesFileJSON returns a JSON listing of the properties of inFile.
+func ListPropertiesFileJSON(inFile string, conf *model.Configuration) ([]string, error) {
+	return listFileJSON(inFile, "properties", func(rs io.ReadSeeker) (map[string]string, error) {
+		m, err := Properties(rs, conf)
+		if m == nil {
+			m = map[string]string{}
+		}
+		return m, err
+	})
+}
+
 // AddProperties adds properties to rs's infodict and writes the result to w.
 func AddProperties(rs io.ReadSeeker, w io.Writer, properties map[string]string, conf *model.Configuration) error {
 	if rs == nil {
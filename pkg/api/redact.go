@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// ApplyRedactions removes all content of selected pages located within the regions marked by Redact annotations
+// or within rects (given in user space) from a PDF context read from rs and writes the result to w.
+// Overlays get painted over the redacted regions and all Redact annotations of the selected pages get removed.
+func ApplyRedactions(rs io.ReadSeeker, w io.Writer, selectedPages []string, rects []*types.Rectangle, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ApplyRedactions: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.APPLYREDACTIONS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
+	if err != nil {
+		return err
+	}
+
+	ok, err := pdfcpu.ApplyRedactions(ctx, pages, rects)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("pdfcpu: ApplyRedactions: no redactions found")
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// ApplyRedactionsFile removes all content of selected pages located within the regions marked by Redact annotations
+// or within rects (given in user space) from a PDF context read from inFile and writes the result to outFile.
+func ApplyRedactionsFile(inFile, outFile string, selectedPages []string, rects []*types.Rectangle, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return ApplyRedactions(f1, f2, selectedPages, rects, conf)
+}
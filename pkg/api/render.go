@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hhrutter/tiff"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// DefaultRenderDPI is the resolution used for rendering pages unless specified otherwise.
+const DefaultRenderDPI = 150
+
+// RenderPage renders page pageNr of ctx at a resolution of dpi dots per inch.
+func RenderPage(ctx *model.Context, pageNr int, dpi float64) (image.Image, error) {
+	img, err := pdfcpu.RenderPage(ctx, pageNr, dpi)
+	if err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// RenderPages renders selected pages of rs at a resolution of dpi dots per inch
+// and calls f for each rendered page.
+func RenderPages(rs io.ReadSeeker, selectedPages []string, dpi float64, f func(pageNr int, img image.Image) error, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: RenderPages: missing rs")
+	}
+
+	if f == nil {
+		return errors.New("pdfcpu: RenderPages: missing f")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.RENDER
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range sortedPages(pages) {
+		img, err := RenderPage(ctx, i, dpi)
+		if err != nil {
+			return err
+		}
+		if err := f(i, img); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeRenderedPage(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case "jpg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 90})
+	case "tif":
+		return tiff.Encode(w, img, nil)
+	}
+	return png.Encode(w, img)
+}
+
+// RenderPagesFile renders selected pages of inFile at a resolution of dpi dots per inch
+// and writes them into outDir as png, jpg or tif images.
+func RenderPagesFile(inFile, outDir string, selectedPages []string, dpi float64, format string, conf *model.Configuration) error {
+	format = strings.ToLower(strings.TrimPrefix(format, "."))
+	switch format {
+	case "":
+		format = "png"
+	case "jpeg":
+		format = "jpg"
+	case "tiff":
+		format = "tif"
+	case "png", "jpg", "tif":
+	default:
+		return errors.Errorf("pdfcpu: unsupported image format: %s", format)
+	}
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if log.CLIEnabled() {
+		log.CLI.Printf("rendering %s into %s/ ...\n", inFile, outDir)
+	}
+
+	fileName := strings.TrimSuffix(filepath.Base(inFile), ".pdf")
+
+	return RenderPages(f, selectedPages, dpi, func(pageNr int, img image.Image) error {
+		outFile := filepath.Join(outDir, fmt.Sprintf("%s_page_%d.%s", fileName, pageNr, format))
+		logWritingTo(outFile)
+		w, err := os.Create(outFile)
+		if err != nil {
+			return err
+		}
+		if err := writeRenderedPage(w, img, format); err != nil {
+			w.Close()
+			return err
+		}
+		return w.Close()
+	}, conf)
+}
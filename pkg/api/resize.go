@@ -42,7 +42,7 @@ func Resize(rs io.ReadSeeker, w io.Writer, selectedPages []string, resize *model
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
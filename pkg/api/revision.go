@@ -0,0 +1,197 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// readerAt returns rs as io.ReaderAt along with its size.
+func readerAt(rs io.ReadSeeker) (io.ReaderAt, int64, error) {
+	size, err := rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, 0, err
+	}
+	if ra, ok := rs.(io.ReaderAt); ok {
+		return ra, size, nil
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+	bb, err := io.ReadAll(rs)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(bb), size, nil
+}
+
+// Revisions returns the revisions of the incrementally updated PDF in rs, the oldest first.
+func Revisions(rs io.ReadSeeker, conf *model.Configuration) ([]pdfcpu.Revision, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: Revisions: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTREVISIONS
+
+	ra, size, err := readerAt(rs)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdfcpu.Revisions(ra, size, conf)
+}
+
+// ListRevisionsFile returns a list of the revisions of inFile.
+func ListRevisionsFile(inFile string, conf *model.Configuration) ([]string, error) {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	revs, err := Revisions(f, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	ss := []string{"Revisions:"}
+	for _, rev := range revs {
+		ss = append(ss, rev.String())
+	}
+
+	return ss, nil
+}
+
+// ListRevisionsFileJSON returns the revisions of inFile as JSON.
+func ListRevisionsFileJSON(inFile string, conf *model.Configuration) ([]string, error) {
+	return listFileJSON(inFile, "revisions", func(rs io.ReadSeeker) ([]pdfcpu.Revision, error) {
+		return Revisions(rs, conf)
+	})
+}
+
+func revisionReader(rs io.ReadSeeker, nr int, conf *model.Configuration) (*io.SectionReader, error) {
+	ra, size, err := readerAt(rs)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := pdfcpu.RevisionSize(ra, size, nr, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NewSectionReader(ra, 0, n), nil
+}
+
+// ReadContextAtRevision returns the validated context of rs as of revision nr.
+func ReadContextAtRevision(rs io.ReadSeeker, nr int, conf *model.Configuration) (*model.Context, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: ReadContextAtRevision: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXTRACTREVISION
+
+	sr, err := revisionReader(rs, nr, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadAndValidate(sr, conf)
+}
+
+// ExtractRevision writes rs as of revision nr to w.
+func ExtractRevision(rs io.ReadSeeker, w io.Writer, nr int, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ExtractRevision: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXTRACTREVISION
+
+	sr, err := revisionReader(rs, nr, conf)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ReadAndValidate(sr, conf); err != nil {
+		return err
+	}
+
+	if _, err := sr.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, sr)
+	return err
+}
+
+// ExtractRevisionFile writes inFile as of revision nr to outFile.
+// If outFile is empty inFile gets rolled back to revision nr.
+func ExtractRevisionFile(inFile, outFile string, nr int, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return ExtractRevision(f1, f2, nr, conf)
+}
@@ -24,6 +24,8 @@ import (
 	"strings"
 
 	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 	"github.com/pkg/errors"
 )
@@ -35,7 +37,7 @@ var (
 func setupRegExpForPageSelection() *regexp.Regexp {
 	e := "(\\d+)?-l(-\\d+)?|l(-(\\d+)-?)?"
 	e = "[!n]?((-\\d+)|(\\d+(-(\\d+)?)?)|" + e + ")"
-	e = "\\Qeven\\E|\\Qodd\\E|" + e
+	e = "\\Qeven\\E|\\Qodd\\E|[!n]?label:[^,]+|" + e
 	exp := "^" + e + "(," + e + ")*$"
 	re, _ := regexp.Compile(exp)
 	return re
@@ -51,7 +53,7 @@ func ParsePageSelection(s string) ([]string, error) {
 		return nil, nil
 	}
 
-	// Ensure valid comma separated expression of:{ {even|odd}{!}{-}# | {even|odd}{!}#-{#} }*
+	// Ensure valid comma separated expression of:{ {even|odd}{!}{-}# | {even|odd}{!}#-{#} | {!}label:l{-l} }*
 	//
 	// Negated expressions:
 	// '!' negates an expression
@@ -63,6 +65,9 @@ func ParsePageSelection(s string) ([]string, error) {
 	// The pageSelection is evaluated strictly from left to right!
 	// e.g. "!3,1-5" extracts pages 1-5 whereas "1-5,!3" extracts pages 1,2,4,5
 	//
+	// Pages may also be selected by page label:
+	// "label:iv" selects the page labelled iv, "label:iv-x" selects the pages labelled iv thru x.
+	//
 
 	if !selectedPagesRegExp.MatchString(s) {
 		return nil, errors.Errorf("-pages \"%s\" => syntax error\n", s)
@@ -391,6 +396,7 @@ func PagesForPageSelection(pageCount int, pageSelection []string, ensureAllforNo
 	return m, nil
 }
 
+// RemainingPagesForPageRemoval returns the set of pages remaining after removing the pages of pageSelection.
 func RemainingPagesForPageRemoval(pageCount int, pageSelection []string, log bool) (types.IntSet, error) {
 	pagesToRemove, err := selectedPages(pageCount, pageSelection, log)
 	if err != nil {
@@ -411,6 +417,48 @@ func RemainingPagesForPageRemoval(pageCount int, pageSelection []string, log boo
 	return m, nil
 }
 
+// ResolvePageLabels replaces all label based expressions of pageSelection by the corresponding page numbers of ctx.
+func ResolvePageLabels(ctx *model.Context, pageSelection []string) ([]string, error) {
+	var ss []string
+	for _, v := range pageSelection {
+		var neg string
+		if len(v) > 0 && negation(v[0]) && strings.HasPrefix(v[1:], "label:") {
+			neg, v = v[:1], v[1:]
+		}
+		if !strings.HasPrefix(v, "label:") {
+			ss = append(ss, neg+v)
+			continue
+		}
+		pageNrs, err := pdfcpu.PagesForLabels(ctx, v[len("label:"):])
+		if err != nil {
+			return nil, err
+		}
+		from, thru := pageNrs[0], pageNrs[len(pageNrs)-1]
+		if from == thru {
+			ss = append(ss, fmt.Sprintf("%s%d", neg, from))
+			continue
+		}
+		ss = append(ss, fmt.Sprintf("%s%d-%d", neg, from, thru))
+	}
+	return ss, nil
+}
+
+func pagesForPageSelection(ctx *model.Context, pageSelection []string, ensureAllforNone bool, log bool) (types.IntSet, error) {
+	pageSelection, err := ResolvePageLabels(ctx, pageSelection)
+	if err != nil {
+		return nil, err
+	}
+	return PagesForPageSelection(ctx.PageCount, pageSelection, ensureAllforNone, log)
+}
+
+func remainingPagesForPageRemoval(ctx *model.Context, pageSelection []string, log bool) (types.IntSet, error) {
+	pageSelection, err := ResolvePageLabels(ctx, pageSelection)
+	if err != nil {
+		return nil, err
+	}
+	return RemainingPagesForPageRemoval(ctx.PageCount, pageSelection, log)
+}
+
 func deletePageFromCollection(cp *[]int, p int) {
 	a := []int{}
 	for _, i := range *cp {
@@ -1,185 +1,127 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
 package api
 
 import (
 	"bytes"
 	"fmt"
 	"os"
+	"regexp"
 
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
-	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
 )
 
-// GetLastObjectID returns the highest object ID in the XRefTable.
+// The placeholder helpers in this file predate Sign and will be removed in the next release.
+
+// GetLastObjectID returns the highest object number in use.
+//
+// Deprecated: Use xRefTable.Size.
 func GetLastObjectID(xRefTable *model.XRefTable) int {
 	maxID := 0
-	for objID := range xRefTable.Table {
-		if objID > maxID {
-			maxID = objID
+	for objNr := range xRefTable.Table {
+		if objNr > maxID {
+			maxID = objNr
 		}
 	}
 	return maxID
 }
 
-// AddObject adds a new object to the PDF and returns its object ID.
-func AddObject(ctx *model.Context, object []byte, outputPath string, conf *model.Configuration) (uint32, error) {
+// AddObject adds a stream object for content to ctx, writes ctx to outputPath and returns the new object number.
+//
+// Deprecated: Use ctx.InsertObject and WriteContextFile.
+func AddObject(ctx *model.Context, content []byte, outputPath string, conf *model.Configuration) (uint32, error) {
 	if ctx == nil {
-		return 0, fmt.Errorf("nil PDF context")
+		return 0, errors.New("pdfcpu: AddObject: missing ctx")
 	}
 
-	// Determine the last object ID.
-	lastXrefID := GetLastObjectID(ctx.XRefTable)
-	objectID := lastXrefID + 1
-
-	// Create PDF object entry.
-	pdfObject := &types.StreamDict{
-		Dict: types.Dict{
-			"Length": types.Integer(len(object)),
-		},
-		Content:        object,
-		FilterPipeline: nil,
+	sd, err := ctx.NewStreamDictForBuf(content)
+	if err != nil {
+		return 0, err
 	}
-	indRef := types.IndirectRef{ObjectNumber: types.Integer(objectID)}
-
-	ctx.XRefTable.Table[int(indRef.ObjectNumber)] = &model.XRefTableEntry{
-		Object:     pdfObject,
-		Compressed: false,
+	if err := sd.Encode(); err != nil {
+		return 0, err
 	}
-	// Write the updated PDF file.
-	err := WriteUpdatedPDF(ctx, outputPath, conf)
+
+	objNr, err := ctx.InsertObject(*sd)
 	if err != nil {
-		return 0, fmt.Errorf("failed to write updated PDF: %w", err)
+		return 0, err
+	}
+
+	if err := WriteUpdatedPDF(ctx, outputPath, conf); err != nil {
+		return 0, err
 	}
 
-	// Return the new object ID.
-	return uint32(indRef.ObjectNumber), nil
+	return uint32(objNr), nil
 }
 
-// WriteUpdatedPDF writes the modified PDF context to the output file.
-func WriteUpdatedPDF(ctx *model.Context, outputPath string, conf *model.Configuration) error {
+// WriteUpdatedPDF writes ctx to outputPath.
+//
+// Deprecated: Use WriteContextFile.
+func WriteUpdatedPDF(ctx *model.Context, outputPath string, conf *model.Configuration) (err error) {
 	if ctx == nil {
-		return fmt.Errorf("nil PDF context")
+		return errors.New("pdfcpu: WriteUpdatedPDF: missing ctx")
 	}
 
-	// Create or open the output file.
-	outFile, err := os.Create(outputPath)
+	f, err := os.Create(outputPath)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return err
 	}
 	defer func() {
-		if err := outFile.Close(); err != nil {
-			fmt.Printf("warning: failed to close output file: %v\n", err)
+		if cerr := f.Close(); err == nil {
+			err = cerr
 		}
 	}()
 
-	// Write the modified PDF to the output file.
-	if err := Write(ctx, outFile, conf); err != nil {
-		return fmt.Errorf("failed to write PDF context: %w", err) // TODO: The content format is a compressed object strema. Consider changing it in the future
-	}
-
-	return nil
+	return Write(ctx, f, conf)
 }
 
-// CreateSignaturePlaceholder generates a basic signature placeholder for PDF signing.
+// CreateSignaturePlaceholder returns a serialized signature dict with placeholders for ByteRange
+// and a Contents value of signatureMaxLength hex digits.
+//
+// Deprecated: Use Sign or SignFile which reserve and fill in the placeholders themselves.
 func CreateSignaturePlaceholder(signatureMaxLength int, name, location, reason, contactInfo string) []byte {
-	var buf bytes.Buffer
-
-	buf.WriteString("<<\n")
-	buf.WriteString(" /Type /Sig\n")
-	buf.WriteString(" /Filter /Adobe.PPKLite\n")
-	buf.WriteString(" /SubFilter /adbe.pkcs7.detached\n")
-
-	// Byte range placeholder to be replaced later.
-	buf.WriteString(" /ByteRange [0 0 0 0]\n")
-
-	// Placeholder for the actual signature content.
-	buf.WriteString(" /Contents <")
-	buf.Write(bytes.Repeat([]byte("0"), signatureMaxLength))
-	buf.WriteString(">\n")
-
-	// Optional metadata.
-	if name != "" {
-		buf.WriteString(" /Name ")
-		buf.WriteString(pdfString(name))
-		buf.WriteString("\n")
-	}
-	if location != "" {
-		buf.WriteString(" /Location ")
-		buf.WriteString(pdfString(location))
-		buf.WriteString("\n")
-	}
-	if reason != "" {
-		buf.WriteString(" /Reason ")
-		buf.WriteString(pdfString(reason))
-		buf.WriteString("\n")
-	}
-	if contactInfo != "" {
-		buf.WriteString(" /ContactInfo ")
-		buf.WriteString(pdfString(contactInfo))
-		buf.WriteString("\n")
-	}
+	return []byte(CreateSignaturePlaceholder1(signatureMaxLength, name, location, reason, contactInfo).PDFString())
+}
 
-	buf.WriteString(">>\n")
+var reByteRangePlaceholder = regexp.MustCompile(`/ByteRange\s*\[\s*0\s+0\s+0\s+0\s*\]`)
 
-	return buf.Bytes()
-}
+// UpdateByteRange replaces the ByteRange placeholder of the file pdfPath
+// by the byte range excluding signatureLength bytes at signatureOffset.
+//
+// Deprecated: Use Sign or SignFile.
 func UpdateByteRange(pdfPath string, signatureOffset int, signatureLength int) error {
-	// Read the entire PDF file.
-	pdfData, err := os.ReadFile(pdfPath)
+	bb, err := os.ReadFile(pdfPath)
 	if err != nil {
-		return fmt.Errorf("failed to read PDF file: %w", err)
+		return err
 	}
 
-	// Calculate byte range values.
-	brStart := 0
-	br1 := signatureOffset            // Start of the signature
-	br2 := signatureLength            // Length of the signature
-	br3 := len(pdfData) - (br1 + br2) // Remaining bytes after signature
-
-	// Format the new byte range string.
-	byteRangeStr := fmt.Sprintf("/ByteRange [%d %d %d %d]", brStart, br1, br1+br2, br3)
-
-	// Locate and replace the existing /ByteRange placeholder.
-	byteRangePlaceholder := "/ByteRange [0 0 0 0]"
-	updatedPDF := bytes.Replace(pdfData, []byte(byteRangePlaceholder), []byte(byteRangeStr), 1)
-
-	if !bytes.Contains(updatedPDF, []byte(byteRangeStr)) {
-		return fmt.Errorf("failed to update /ByteRange in PDF")
-	}
+	end := signatureOffset + signatureLength
+	br := fmt.Sprintf("/ByteRange [%d %d %d %d]", 0, signatureOffset, end, len(bb)-end)
 
-	// Write the updated PDF back to file.
-	if err := os.WriteFile(pdfPath, updatedPDF, 0644); err != nil {
-		return fmt.Errorf("failed to write updated PDF file: %w", err)
+	loc := reByteRangePlaceholder.FindIndex(bb)
+	if loc == nil {
+		return errors.New("pdfcpu: UpdateByteRange: missing ByteRange placeholder")
 	}
 
-	return nil
-}
-
-// CreateAcroForm generates the AcroForm dictionary for the PDF catalog.
-//func CreateAcroForm(ctx *model.Context) []byte {
-//var buf bytes.Buffer
-
-//buf.WriteString("<<\n")
-//buf.WriteString("  /Fields [")
-
-// Add existing signatures
-//for i, sig := range existingSignatures {
-//if i > 0 {
-//buf.WriteString(" ")
-//}
-//buf.WriteString(strconv.Itoa(sig.objectId) + " 0 R")
-//}
-
-//buf.WriteString("]\n") // Close Fields array
-
-// Signature flags
-//buf.WriteString("  /SigFlags 3\n")
-
-//buf.WriteString(">>\n") // Close AcroForm dictionary
-
-//return buf.Bytes()
-//}
+	var buf bytes.Buffer
+	buf.Write(bb[:loc[0]])
+	buf.WriteString(br)
+	buf.Write(bb[loc[1]:])
 
-// pdfString escapes and formats a string for PDF content.
-func pdfString(input string) string {
-	return "(" + input + ")" // Simplified escape logic.
+	return os.WriteFile(pdfPath, buf.Bytes(), 0644)
 }
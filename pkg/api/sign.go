@@ -0,0 +1,268 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
+	"github.com/pkg/errors"
+)
+
+func copyFile(srcFileName, destFileName string) (err error) {
+	from, err := os.Open(srcFileName)
+	if err != nil {
+		return err
+	}
+	defer from.Close()
+
+	to, err := os.Create(destFileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := to.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	_, err = io.Copy(to, from)
+	return err
+}
+
+func endsWithEol(rs io.ReadSeeker) (bool, error) {
+	if _, err := rs.Seek(-1, io.SeekEnd); err != nil {
+		return false, err
+	}
+	b := make([]byte, 1)
+	if _, err := io.ReadFull(rs, b); err != nil {
+		return false, err
+	}
+	return b[0] == '\n' || b[0] == '\r', nil
+}
+
+// Sign digitally signs rws using signer and certChain and appends the signature as a PDF increment.
+// certChain[0] is the signer certificate, any remaining certificates are embedded for chain building.
+// The signature is a detached CMS SignedData computed over the ByteRange covering the whole file except the signature value.
+func Sign(rws io.ReadWriteSeeker, signer crypto.Signer, certChain []*x509.Certificate, sc *model.SignatureConfig, conf *model.Configuration) error {
+	if rws == nil {
+		return errors.New("pdfcpu: Sign: missing rws")
+	}
+
+	if signer == nil {
+		return errors.New("pdfcpu: Sign: missing signer")
+	}
+
+	if len(certChain) == 0 {
+		return errors.New("pdfcpu: Sign: missing certificate")
+	}
+
+	if sc == nil {
+		sc = model.DefaultSignatureConfig()
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.SIGN
+
+	ctx, err := ReadAndValidate(rws, conf)
+	if err != nil {
+		return err
+	}
+
+	if *ctx.HeaderVersion < model.V14 {
+		return errors.New("pdfcpu: Signing not supported for PDF version < V1.4 (Hint: Use pdfcpu optimize then try again)")
+	}
+
+	signingTime := time.Now()
+
+	sigObjNr, err := sign.PrepareSignature(ctx, sc, signingTime)
+	if err != nil {
+		return err
+	}
+
+	// Stick to the cross reference format of the original file.
+	if !ctx.Read.UsingXRefStreams {
+		ctx.WriteXRefStream = false
+	}
+
+	var buf bytes.Buffer
+
+	ok, err := endsWithEol(rws)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		buf.WriteString(ctx.Write.Eol)
+	}
+
+	ctx.Write.Increment = true
+	ctx.Write.Offset = ctx.Read.FileSize + int64(buf.Len())
+
+	if err := WriteIncrement(ctx, &buf); err != nil {
+		return err
+	}
+
+	sigOff := int(ctx.Write.Table[sigObjNr] - ctx.Read.FileSize)
+
+	incr := buf.Bytes()
+	if err := sign.ApplySignature(rws, ctx.Read.FileSize, incr, sigOff, signer, certChain, sc, signingTime); err != nil {
+		return err
+	}
+
+	if _, err := rws.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+
+	_, err = rws.Write(incr)
+	return err
+}
+
+// ReadSigningCredentialsFiles returns the private key in keyFile and the certificate chain in certFile.
+func ReadSigningCredentialsFiles(certFile, keyFile string) (crypto.Signer, []*x509.Certificate, error) {
+	bb, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certs, err := sign.ParseCertificates(bb)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if bb, err = os.ReadFile(keyFile); err != nil {
+		return nil, nil, err
+	}
+
+	signer, err := sign.ParsePrivateKey(bb)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return signer, certs, nil
+}
+
+// SignFile digitally signs inFile using the private key in keyFile and the certificate chain in certFile.
+// The signed result is written to outFile or appended to inFile if outFile is empty.
+func SignFile(inFile, outFile, certFile, keyFile string, sc *model.SignatureConfig, conf *model.Configuration) (err error) {
+	if log.CLIEnabled() {
+		log.CLI.Printf("signing %s\n", inFile)
+	}
+
+	signer, certs, err := ReadSigningCredentialsFiles(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+
+	fileName := inFile
+	if outFile != "" && inFile != outFile {
+		if err := copyFile(inFile, outFile); err != nil {
+			return err
+		}
+		fileName = outFile
+	}
+	logWritingTo(fileName)
+
+	f, err := os.OpenFile(fileName, os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f.Close()
+			if fileName != inFile {
+				os.Remove(fileName)
+			}
+			return
+		}
+		err = f.Close()
+	}()
+
+	return Sign(f, signer, certs, sc, conf)
+}
+
+// VerifySignatures verifies all digital signatures of rs against the certificates in roots.
+// If roots is nil the system certificate pool is used.
+// For each signature the result also reports whether later revisions only applied modifications permitted by DocMDP/FieldMDP.
+func VerifySignatures(rs io.ReadSeeker, roots *x509.CertPool, conf *model.Configuration) ([]*sign.SignatureResult, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: VerifySignatures: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.VERIFYSIGNATURES
+
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	bb, err := io.ReadAll(rs)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := pdfcpu.Read(bytes.NewReader(bb), conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if roots == nil {
+		// Signatures are reported as untrusted if there is no system pool.
+		roots, _ = x509.SystemCertPool()
+	}
+
+	readRevision := func(size int64) (*model.Context, error) {
+		return pdfcpu.Read(bytes.NewReader(bb[:size]), conf)
+	}
+
+	return sign.VerifySignatures(ctx, bb, roots, readRevision)
+}
+
+// VerifySignaturesFile verifies all digital signatures of inFile against the certificates found in trustStoreDir.
+// If trustStoreDir is empty the system certificate pool is used.
+func VerifySignaturesFile(inFile, trustStoreDir string, conf *model.Configuration) ([]*sign.SignatureResult, error) {
+	var (
+		roots *x509.CertPool
+		err   error
+	)
+
+	if trustStoreDir != "" {
+		if roots, err = sign.LoadTrustStore(trustStoreDir); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return VerifySignatures(f, roots, conf)
+}
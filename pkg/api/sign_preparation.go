@@ -1,163 +1,95 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
 package api
 
 import (
-	"bufio"
 	"bytes"
-	"fmt"
 	"os"
-	"strings"
+	"time"
 
-	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
-	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
+	"github.com/pkg/errors"
 )
 
-const SignaturePlaceholderSize = 16384 // Size of the signature placeholder
+// SignaturePlaceholderSize is the number of bytes reserved for the signature value.
+//
+// Deprecated: Use model.DefaultSignatureSize.
+const SignaturePlaceholderSize = model.DefaultSignatureSize
 
-// PreparePDFForSigning prepares a PDF for signing by adding a placeholder and calculating the ByteRange.
+// PreparePDFForSigning adds an unsigned signature field to ctx and serializes ctx.
+// Returns the ByteRange excluding the signature value and the bytes covered by it.
+//
+// Deprecated: Use Sign or SignFile.
 func PreparePDFForSigning(ctx *model.Context) ([]int, []byte, error) {
 	if ctx == nil {
-		return nil, nil, fmt.Errorf("invalid PDF context")
-	}
-
-	// Access the PDF catalog
-	catalogDict, err := ctx.XRefTable.Catalog()
-	if err != nil {
-		return nil, nil, fmt.Errorf("error accessing catalog: %v", err)
-	}
-
-	// Create or access the AcroForm dictionary
-	var acroForm types.Dict
-	if acroFormObj, found := catalogDict.Find("AcroForm"); found {
-		acroForm, err = ctx.DereferenceDict(acroFormObj)
-		if err != nil {
-			return nil, nil, fmt.Errorf("error dereferencing AcroForm: %v", err)
-		}
-	}
-	if acroForm == nil {
-		acroForm = types.Dict{}
-		catalogDict["AcroForm"] = acroForm
-	}
-
-	if _, found := acroForm.Find("Fields"); !found {
-		acroForm["Fields"] = types.Array{}
+		return nil, nil, errors.New("pdfcpu: PreparePDFForSigning: missing ctx")
 	}
 
-	// Define the signature field
-	sigFieldDict := types.Dict{
-		"Type":    types.Name("Annot"),
-		"Subtype": types.Name("Widget"),
-		"FT":      types.Name("Sig"),
-		"T":       types.StringLiteral("Signature1"),
-		"Rect":    types.Array{types.Float(100), types.Float(100), types.Float(200), types.Float(150)},
-		"V":       nil,
-	}
-	sigFieldRef, err := ctx.IndRefForNewObject(sigFieldDict)
+	sigObjNr, err := sign.PrepareSignature(ctx, model.DefaultSignatureConfig(), time.Now())
 	if err != nil {
-		return nil, nil, fmt.Errorf("error creating signature field: %v", err)
+		return nil, nil, err
 	}
 
-	fieldsArray, ok := acroForm["Fields"].(types.Array)
-	if !ok {
-		return nil, nil, fmt.Errorf("AcroForm fields are not an array")
-	}
-	acroForm["Fields"] = append(fieldsArray, *sigFieldRef)
-
-	// Add the signature dictionary
-	sigDict := types.Dict{
-		"Type":      types.Name("Sig"),
-		"Filter":    types.Name("Adobe.PPKLite"),
-		"SubFilter": types.Name("adbe.pkcs7.detached"),
-		"Contents":  types.HexLiteral(strings.Repeat("0", SignaturePlaceholderSize*2)),
-		"ByteRange": types.Array{
-			types.Integer(0), // Start of the range
-			types.Integer(0), // Length before the signature
-			types.Integer(0), // Start of the second range
-			types.Integer(0), // Length after the signature
-		},
-	}
-	sigFieldDict["V"] = sigDict
+	// The signature dict must not end up in an object stream.
+	ctx.WriteObjectStream = false
 
-	// Serialize the PDF
 	var buf bytes.Buffer
-	ctx.Write.Writer = bufio.NewWriter(&buf)
-
-	// Step 3: Check placeholder size before writing
-	placeholderBytes := len(sigDict["Contents"].(types.HexLiteral))
-	if placeholderBytes > SignaturePlaceholderSize*2 {
-		return nil, nil, fmt.Errorf("signature placeholder size exceeds defined limit")
-	}
-
-	if err := pdfcpu.Write(ctx); err != nil {
-		return nil, nil, fmt.Errorf("error writing PDF: %v", err)
-	}
-
-	rawPDF := buf.Bytes()
-
-	// Step 4: Use robust method to locate the placeholder
-	placeholderIndex := locatePlaceholder(rawPDF, "/Contents")
-	if placeholderIndex == -1 {
-		return nil, nil, fmt.Errorf("signature placeholder not found in PDF")
+	if err := WriteContext(ctx, &buf); err != nil {
+		return nil, nil, err
 	}
+	bb := buf.Bytes()
 
-	// Dynamically calculate ByteRange
-	startOffset := placeholderIndex + len("/Contents") + 2 // Adjust for "/Contents<"
-	endOffset := startOffset + SignaturePlaceholderSize
-	if endOffset > len(rawPDF) {
-		return nil, nil, fmt.Errorf("signature placeholder exceeds PDF size")
+	sigOff, ok := ctx.Write.Table[sigObjNr]
+	if !ok {
+		return nil, nil, errors.New("pdfcpu: PreparePDFForSigning: missing signature dict")
 	}
 
-	byteRange := []int{
-		0,
-		startOffset,
-		endOffset,
-		len(rawPDF) - endOffset,
+	i := bytes.Index(bb[sigOff:], []byte("/Contents"))
+	if i < 0 {
+		return nil, nil, errors.New("pdfcpu: PreparePDFForSigning: missing signature placeholder")
 	}
+	start := int(sigOff) + i + bytes.IndexByte(bb[int(sigOff)+i:], '<')
+	end := start + bytes.IndexByte(bb[start:], '>') + 1
 
-	// Extract unsigned bytes
-	unsignedBytes := append(rawPDF[:startOffset], rawPDF[endOffset:]...)
+	byteRange := []int{0, start, end, len(bb) - end}
 
-	return byteRange, unsignedBytes, nil
-}
+	unsigned := append(append([]byte{}, bb[:start]...), bb[end:]...)
 
-// locatePlaceholder finds the index of a placeholder in the PDF.
-func locatePlaceholder(pdf []byte, marker string) int {
-	// Perform a case-insensitive search for the marker
-	markerBytes := []byte(marker)
-	for i := 0; i < len(pdf)-len(markerBytes); i++ {
-		if bytes.EqualFold(pdf[i:i+len(markerBytes)], markerBytes) {
-			return i
-		}
-	}
-	return -1
+	return byteRange, unsigned, nil
 }
 
-// PreparePDFForSigningFile reads a PDF, prepares it for signing, and returns ByteRange and unsigned bytes.
+// PreparePDFForSigningFile reads inputPath, prepares it for signing and writes the bytes covered by the ByteRange to outputPath.
+//
+// Deprecated: Use SignFile.
 func PreparePDFForSigningFile(inputPath, outputPath string) ([]int, []byte, error) {
-	// Read the PDF into a context
 	ctx, err := ReadContextFile(inputPath)
-	if err != nil {
-		return nil, nil, fmt.Errorf("error reading PDF file: %v", err)
-	}
-
-	// Prepare the PDF for signing
-	byteRange, unsignedBytes, err := PreparePDFForSigning(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Write the updated PDF to the output file
-	file, err := os.Create(outputPath)
+	byteRange, unsigned, err := PreparePDFForSigning(ctx)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error creating output file: %v", err)
+		return nil, nil, err
 	}
-	defer file.Close()
 
-	writer := bufio.NewWriter(file)
-	if _, err := writer.Write(unsignedBytes); err != nil {
-		return nil, nil, fmt.Errorf("error writing output PDF: %v", err)
+	if err := os.WriteFile(outputPath, unsigned, 0644); err != nil {
+		return nil, nil, err
 	}
-	writer.Flush()
 
-	return byteRange, unsignedBytes, nil
+	return byteRange, unsigned, nil
 }
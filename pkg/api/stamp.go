@@ -189,7 +189,7 @@ func AddWatermarks(rs io.ReadSeeker, w io.Writer, selectedPages []string, wm *mo
 	}
 
 	var pages types.IntSet
-	pages, err = PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err = pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -258,7 +258,7 @@ func RemoveWatermarks(rs io.ReadSeeker, w io.Writer, selectedPages []string, con
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, true, true)
 	if err != nil {
 		return err
 	}
@@ -427,6 +427,19 @@ func PDFMultiWatermarkForReadSeeker(rs io.ReadSeeker, startPageNrSrc, startPageN
 	return wm, nil
 }
 
+// BarcodeWatermark returns a barcode watermark configuration.
+// code is a barcode type followed by a colon and the content eg. "qr:https://pdfcpu.io/%p".
+func BarcodeWatermark(code, desc string, onTop, update bool, u types.DisplayUnit) (*model.Watermark, error) {
+	wm, err := pdfcpu.ParseBarcodeWatermarkDetails(code, desc, onTop, u)
+	if err != nil {
+		return nil, err
+	}
+
+	wm.Update = update
+
+	return wm, nil
+}
+
 // AddTextWatermarksFile adds text stamps/watermarks to all selected pages of inFile and writes the result to outFile.
 func AddTextWatermarksFile(inFile, outFile string, selectedPages []string, onTop bool, text, desc string, conf *model.Configuration) error {
 	unit := types.POINTS
@@ -487,6 +500,21 @@ func AddPDFWatermarksFile(inFile, outFile string, selectedPages []string, onTop
 	return AddWatermarksFile(inFile, outFile, selectedPages, wm, conf)
 }
 
+// AddBarcodeWatermarksFile adds barcode stamps/watermarks to all selected pages of inFile and writes the result to outFile.
+func AddBarcodeWatermarksFile(inFile, outFile string, selectedPages []string, onTop bool, code, desc string, conf *model.Configuration) error {
+	unit := types.POINTS
+	if conf != nil {
+		unit = conf.Unit
+	}
+
+	wm, err := BarcodeWatermark(code, desc, onTop, false, unit)
+	if err != nil {
+		return err
+	}
+
+	return AddWatermarksFile(inFile, outFile, selectedPages, wm, conf)
+}
+
 // UpdateTextWatermarksFile adds text stamps/watermarks to all selected pages of inFile and writes the result to outFile.
 func UpdateTextWatermarksFile(inFile, outFile string, selectedPages []string, onTop bool, text, desc string, conf *model.Configuration) error {
 	unit := types.POINTS
@@ -529,3 +557,18 @@ func UpdatePDFWatermarksFile(inFile, outFile string, selectedPages []string, onT
 
 	return AddWatermarksFile(inFile, outFile, selectedPages, wm, conf)
 }
+
+// UpdateBarcodeWatermarksFile adds barcode stamps/watermarks to all selected pages of inFile and writes the result to outFile.
+func UpdateBarcodeWatermarksFile(inFile, outFile string, selectedPages []string, onTop bool, code, desc string, conf *model.Configuration) error {
+	unit := types.POINTS
+	if conf != nil {
+		unit = conf.Unit
+	}
+
+	wm, err := BarcodeWatermark(code, desc, onTop, true, unit)
+	if err != nil {
+		return err
+	}
+
+	return AddWatermarksFile(inFile, outFile, selectedPages, wm, conf)
+}
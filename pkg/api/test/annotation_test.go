@@ -1075,3 +1075,35 @@ func TestCaretAnnotation(t *testing.T) {
 		t.Fatalf("%s add: %v\n", msg, err)
 	}
 }
+
+func TestFlattenAnnotations(t *testing.T) {
+	msg := "TestFlattenAnnotations"
+
+	inFile := filepath.Join(inDir, "text_annotations.pdf")
+	outFile := filepath.Join(outDir, "text_annotations_flattened.pdf")
+
+	// 1 Highlight and 7 hidden Text annotations replying to it.
+	if i := annotationCount(t, inFile); i != 8 {
+		t.Fatalf("%s count: got %d want 8\n", msg, i)
+	}
+
+	// Text annotations lack appearance streams and Widgets are reserved for form flattening.
+	for _, annotType := range []string{"Text", "Widget"} {
+		if err := api.FlattenAnnotationsFile(inFile, outFile, nil, []string{annotType}, pdfcpu.FlattenAll, nil); err == nil {
+			t.Fatalf("%s flatten %s: want error\n", msg, annotType)
+		}
+	}
+
+	if err := api.FlattenAnnotationsFile(inFile, outFile, nil, nil, pdfcpu.FlattenPrint, nil); err != nil {
+		t.Fatalf("%s flatten: %v\n", msg, err)
+	}
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s validate: %v\n", msg, err)
+	}
+
+	// The Highlight annotation is now part of the page content.
+	if i := annotationCount(t, outFile); i != 7 {
+		t.Fatalf("%s count: got %d want 7\n", msg, i)
+	}
+}
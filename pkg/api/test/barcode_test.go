@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestAddBarcodeStamps(t *testing.T) {
+	msg := "TestAddBarcodeStamps"
+
+	for _, tt := range []struct {
+		code    string
+		desc    string
+		outFile string
+	}{
+		{"qr:https://pdfcpu.io/page/%p", "pos:br, off:-20 20, scale:.15", "stampQR.pdf"},
+		{"qr-h:Page %p of %P", "pos:tl, off:20 -20, fillc:#032890, bgcolor:#FFFFFF", "stampQRH.pdf"},
+		{"datamatrix:pdfcpu %p", "pos:bl, off:20 20, scale:2 abs, rot:45", "stampDataMatrix.pdf"},
+		{"code128:INV-2026-%p", "pos:bc, scale:.3, bgcolor:#FFFFFF", "stampCode128.pdf"},
+		{"ean13:400638133393", "pos:tr, scale:1.5 abs, op:.8", "stampEAN13.pdf"},
+	} {
+		inFile := filepath.Join(inDir, "Acroforms2.pdf")
+		outFile := filepath.Join(outDir, tt.outFile)
+
+		if err := api.AddBarcodeWatermarksFile(inFile, outFile, nil, true, tt.code, tt.desc, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.code, err)
+		}
+		if err := api.ValidateFile(outFile, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.code, err)
+		}
+
+		ok, err := api.HasWatermarksFile(outFile, nil)
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.code, err)
+		}
+		if !ok {
+			t.Fatalf("%s %s: missing barcode stamp\n", msg, tt.code)
+		}
+
+		if err := api.UpdateBarcodeWatermarksFile(outFile, "", []string{"1"}, true, tt.code, "pos:c", nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.code, err)
+		}
+		if err := api.ValidateFile(outFile, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.code, err)
+		}
+	}
+}
+
+func TestAddBarcodeWatermark(t *testing.T) {
+	msg := "TestAddBarcodeWatermark"
+
+	inFile := filepath.Join(inDir, "Acroforms2.pdf")
+	outFile := filepath.Join(outDir, "watermarkQR.pdf")
+
+	if err := api.AddBarcodeWatermarksFile(inFile, outFile, []string{"odd"}, false, "qr:%p", "scale:.5, op:.2", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.RemoveWatermarksFile(outFile, "", nil, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
+
+func TestBarcodeWatermarkErrors(t *testing.T) {
+	msg := "TestBarcodeWatermarkErrors"
+
+	for _, code := range []string{
+		"qr",                  // missing content
+		"pdf417:abc",          // unsupported type
+		"ean13:12345",         // too short
+		"ean13:4006381333932", // invalid check digit
+		"code128:Grüße",       // non ASCII
+	} {
+		if _, err := api.BarcodeWatermark(code, "", true, false, types.POINTS); err == nil {
+			t.Fatalf("%s %s: expected error\n", msg, code)
+		}
+	}
+
+	// Content depending on the page number is validated per page.
+	inFile := filepath.Join(inDir, "Acroforms2.pdf")
+	outFile := filepath.Join(outDir, "stampEAN13Error.pdf")
+	if err := api.AddBarcodeWatermarksFile(inFile, outFile, nil, true, "ean13:400638133393%p", "", nil); err == nil {
+		t.Fatalf("%s: expected error for page 2\n", msg)
+	}
+}
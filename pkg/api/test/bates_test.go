@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+func TestBates(t *testing.T) {
+	msg := "TestBates"
+
+	batesDir := filepath.Join(outDir, "bates")
+	if err := os.MkdirAll(batesDir, os.ModePerm); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	inFiles := []string{
+		filepath.Join(inDir, "Acroforms2.pdf"),   // 3 pages
+		filepath.Join(inDir, "adobe_errata.pdf"), // 18 pages
+	}
+	logFile := filepath.Join(batesDir, "bates.csv")
+
+	b := model.DefaultBatesConfig()
+	b.Prefix = "ABC"
+	b.Start = 99
+	b.Digits = 4
+	b.Properties = true
+
+	if err := api.AddBatesNumbersFiles(inFiles, batesDir, logFile, b, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	for _, inFile := range inFiles {
+		outFile := filepath.Join(batesDir, filepath.Base(inFile))
+		if err := api.ValidateFile(outFile, nil); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		ok, err := api.HasWatermarksFile(outFile, nil)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if !ok {
+			t.Fatalf("%s: %s: missing Bates stamps\n", msg, outFile)
+		}
+	}
+
+	// Numbering continues across files.
+	f, err := os.Open(filepath.Join(batesDir, "adobe_errata.pdf"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	properties, err := api.Properties(f, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if properties["BatesBegin"] != "ABC0102" || properties["BatesEnd"] != "ABC0119" {
+		t.Fatalf("%s: unexpected Bates range: %s - %s\n", msg, properties["BatesBegin"], properties["BatesEnd"])
+	}
+
+	f2, err := os.Open(logFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f2.Close()
+
+	records, err := csv.NewReader(f2).ReadAll()
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if len(records) != 1+3+18 {
+		t.Fatalf("%s: unexpected log entry count: %d\n", msg, len(records))
+	}
+	if want := []string{"Acroforms2.pdf", "3", "ABC0101", "ABC0099", "ABC0101"}; !reflect.DeepEqual(records[3], want) {
+		t.Fatalf("%s: got %v, want %v\n", msg, records[3], want)
+	}
+	if want := []string{"adobe_errata.pdf", "1", "ABC0102", "ABC0102", "ABC0119"}; !reflect.DeepEqual(records[4], want) {
+		t.Fatalf("%s: got %v, want %v\n", msg, records[4], want)
+	}
+
+	// Invalid stamp descriptions are rejected before writing anything.
+	b.Desc = "bogus:1"
+	if err := api.AddBatesNumbersFiles(inFiles, batesDir, "", b, nil); err == nil {
+		t.Fatalf("%s: expected error for invalid description\n", msg)
+	}
+}
@@ -83,6 +83,9 @@ func TestCreateContentPrimitivesViaJson(t *testing.T) {
 		{"TestBoxesAndMargin", "boxesAndMargin.json", "boxesAndMargin.pdf"},
 		{"TestBoxesAndRotation", "boxesAndRotation.json", "boxesAndRotation.pdf"},
 
+		// Barcode
+		{"TestBarcodes", "barcodes.json", "barcodes.pdf"},
+
 		// Table
 		{"TestTable", "table.json", "table.pdf"},
 		{"TestTableRTL", "tableRTL.json", "tableRTL.pdf"},
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+func TestDiff(t *testing.T) {
+	msg := "TestDiff"
+	inFile := filepath.Join(inDir, "CenterOfWhy.pdf")
+
+	d, err := api.DiffFile(inFile, inFile, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if !d.Equal() {
+		t.Fatalf("%s: want no differences, got %v\n", msg, d.Strings())
+	}
+
+	// Revise: rotate page 1, drop the last page, add a keyword and a property.
+	outFile := filepath.Join(outDir, "CenterOfWhyRevised.pdf")
+	if err := api.RotateFile(inFile, outFile, 90, []string{"1"}, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.RemovePagesFile(outFile, "", []string{"l"}, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.AddKeywordsFile(outFile, "", []string{"revised"}, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.AddPropertiesFile(outFile, "", map[string]string{"Revision": "2"}, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	d, err = api.DiffFile(inFile, outFile, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if d.PageCount2 != d.PageCount1-1 {
+		t.Fatalf("%s: pageCount: got %d, want %d\n", msg, d.PageCount2, d.PageCount1-1)
+	}
+
+	if !reflect.DeepEqual(d.KeywordsAdded, []string{"revised"}) {
+		t.Fatalf("%s: keywords added: got %v\n", msg, d.KeywordsAdded)
+	}
+
+	found := false
+	for _, c := range d.Properties {
+		if c.Name == "Revision" && c.Old == "" && c.New == "2" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("%s: missing property change: %v\n", msg, d.Properties)
+	}
+
+	if len(d.Pages) == 0 || d.Pages[0].PageNr != 1 {
+		t.Fatalf("%s: missing diff for page 1\n", msg)
+	}
+	want := pdfcpu.Change{Name: "Rotate", Old: "0", New: "90"}
+	found = false
+	for _, c := range d.Pages[0].Boxes {
+		if c == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("%s: missing rotation change: %v\n", msg, d.Pages[0].Boxes)
+	}
+
+	// Only page 1 has changed.
+	if len(d.Pages) != 1 {
+		t.Fatalf("%s: unexpected page diffs: %v\n", msg, d.Strings())
+	}
+
+	ss, err := api.DiffFileJSON(inFile, outFile, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	var v struct {
+		Source1 string `json:"source1"`
+		Equal   bool   `json:"equal"`
+		pdfcpu.Diff
+	}
+	unmarshalListing(t, msg, ss, "diff", &v)
+	if v.Source1 != inFile || v.Equal || v.PageCount2 != d.PageCount2 {
+		t.Fatalf("%s: unexpected JSON diff: %+v\n", msg, v)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// writeRecipientCredentials generates a RSA key and a self-signed certificate and writes both PEM encoded to outDir.
+func writeRecipientCredentials(t *testing.T, name string, serial int64) (string, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(outDir, name+".cert.pem")
+	bb := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(certFile, bb, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	keyFile := filepath.Join(outDir, name+".key.pem")
+	bb = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(keyFile, bb, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return certFile, keyFile
+}
+
+func confForRecipient(t *testing.T, keyFile, certFile string) *model.Configuration {
+	t.Helper()
+
+	conf := model.NewDefaultConfiguration()
+
+	k, err := api.ReadRecipientKeyFile(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.RecipientKey = k
+
+	if certFile != "" {
+		certs, err := api.ReadRecipientCertificatesFile(certFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conf.RecipientCert = certs[0]
+	}
+
+	return conf
+}
+
+func TestPubSecEncryption(t *testing.T) {
+	msg := "TestPubSecEncryption"
+
+	certFile1, keyFile1 := writeRecipientCredentials(t, "recipient1", 1)
+	certFile2, keyFile2 := writeRecipientCredentials(t, "recipient2", 2)
+	_, keyFile3 := writeRecipientCredentials(t, "stranger", 3)
+
+	var recipients []*x509.Certificate
+	for _, certFile := range []string{certFile1, certFile2} {
+		certs, err := api.ReadRecipientCertificatesFile(certFile)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		recipients = append(recipients, certs...)
+	}
+
+	for _, fileName := range []string{
+		"5116.DCT_Filter.pdf",
+		filepath.Join("pdf20", "SimplePDF2.0.pdf"),
+	} {
+		inFile := filepath.Join(inDir, fileName)
+		outFile := filepath.Join(outDir, "testPubSec.pdf")
+
+		conf := model.NewPubSecConfiguration(recipients)
+		conf.Permissions = model.PermissionsPrint
+		if err := api.EncryptFile(inFile, outFile, conf); err != nil {
+			t.Fatalf("%s: encrypt %s: %v\n", msg, inFile, err)
+		}
+
+		// Reading without or with a foreign private key must fail.
+		if err := api.ValidateFile(outFile, nil); err == nil {
+			t.Fatalf("%s: validate %s w/o key should fail\n", msg, outFile)
+		}
+		if err := api.ValidateFile(outFile, confForRecipient(t, keyFile3, "")); err == nil {
+			t.Fatalf("%s: validate %s using foreign key should fail\n", msg, outFile)
+		}
+
+		// Each recipient is able to open the file.
+		if err := api.ValidateFile(outFile, confForRecipient(t, keyFile1, "")); err != nil {
+			t.Fatalf("%s: validate %s: %v\n", msg, outFile, err)
+		}
+
+		p, err := api.GetPermissionsFile(outFile, confForRecipient(t, keyFile2, certFile2))
+		if err != nil {
+			t.Fatalf("%s: get permissions %s: %v\n", msg, outFile, err)
+		}
+		if p == nil || uint16(*p) != uint16(model.PermissionsPrint) {
+			t.Fatalf("%s: unexpected permissions %v\n", msg, p)
+		}
+
+		// Password changes do not apply.
+		conf = confForRecipient(t, keyFile1, "")
+		if err := api.ChangeUserPasswordFile(outFile, "", "", "upw", conf); err == nil {
+			t.Fatalf("%s: change upw %s should fail\n", msg, outFile)
+		}
+
+		// Decrypt using the key and certificate of the second recipient.
+		if err := api.DecryptFile(outFile, "", confForRecipient(t, keyFile2, certFile2)); err != nil {
+			t.Fatalf("%s: decrypt %s: %v\n", msg, outFile, err)
+		}
+
+		if err := api.ValidateFile(outFile, nil); err != nil {
+			t.Fatalf("%s: validate %s: %v\n", msg, outFile, err)
+		}
+	}
+}
@@ -283,6 +283,61 @@ func TestExtractContentLowLevel(t *testing.T) {
 	t.Logf("Page content (PDF-syntax) for page %d:\n%s", i, string(bb))
 }
 
+func TestExtractText(t *testing.T) {
+	msg := "TestExtractText"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+
+	// Extract plain text of all pages into outDir.
+	if err := api.ExtractTextFile(inFile, outDir, nil, false, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	// Extract text including bounding boxes of page 2 into outDir.
+	if err := api.ExtractTextFile(inFile, outDir, []string{"2"}, true, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+}
+
+func TestExtractTextLowLevel(t *testing.T) {
+	msg := "TestExtractTextLowLevel"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+
+	// Create a context.
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+
+	// Extract page text for page 2.
+	pt, err := pdfcpu.ExtractPageText(ctx, 2)
+	if err != nil {
+		t.Fatalf("%s extractPageText: %v\n", msg, err)
+	}
+
+	if len(pt.Lines) == 0 || strings.TrimSpace(pt.Lines[0].Text) != "SOLITUDE" {
+		t.Fatalf("%s: unexpected first line: %v\n", msg, pt.Lines)
+	}
+
+	if !strings.Contains(pt.String(), "This is a delicious evening, when the whole body is one sense") {
+		t.Fatalf("%s: missing text:\n%s", msg, pt.String())
+	}
+
+	dims, err := ctx.PageDims()
+	if err != nil {
+		t.Fatalf("%s pageDims: %v\n", msg, err)
+	}
+	page := types.RectForDim(dims[1].Width, dims[1].Height)
+
+	for _, l := range pt.Lines {
+		if l.BBox.Width() <= 0 || l.BBox.Height() <= 0 || !l.BBox.FitsWithin(page) {
+			t.Fatalf("%s: invalid bounding box for line %q: %v\n", msg, l.Text, l.BBox)
+		}
+		if len(l.Glyphs) == 0 || l.FontSize <= 0 {
+			t.Fatalf("%s: invalid line %q\n", msg, l.Text)
+		}
+	}
+}
+
 func TestExtractMetadata(t *testing.T) {
 	msg := "TestExtractMetadata"
 	// Extract all metadata into outDir.
@@ -160,6 +160,65 @@ func TestUnlockFormFields(t *testing.T) {
 	}
 }
 
+func TestFlattenFormFields(t *testing.T) {
+
+	for _, tt := range []struct {
+		msg     string
+		inFile  string
+		outFile string
+	}{
+		{"TestFlattenFormEN", "english.pdf", "english-flattened.pdf"},              // Core font (Helvetica)
+		{"TestFlattenFormUK", "ukrainian.pdf", "ukrainian-flattened.pdf"},          // User font (Roboto-Regular)
+		{"TestFlattenFormCJK", "chineseSimple.pdf", "chineseSimple-flattened.pdf"}, // User font CJK (UnifontMedium)
+		{"TestFlattenPersonForm", "person.pdf", "person-flattened.pdf"},            // Person Form
+	} {
+		inFile := filepath.Join(samplesDir, "form", "demoSinglePage", tt.inFile)
+		outFile := filepath.Join(outDir, tt.outFile)
+		if err := api.FlattenFormFieldsFile(inFile, outFile, nil, conf); err != nil {
+			t.Fatalf("%s: %v\n", tt.msg, err)
+		}
+		if err := api.ValidateFile(outFile, conf); err != nil {
+			t.Fatalf("%s: %v\n", tt.msg, err)
+		}
+		if ss, err := listFormFieldsFile(t, outFile, conf); err == nil && len(ss) > 0 {
+			t.Fatalf("%s: want no form fields, got %d lines\n", tt.msg, len(ss))
+		}
+		// The widget appearances are painted into the page content.
+		if before, after := xObjectInvocations(t, tt.msg, inFile), xObjectInvocations(t, tt.msg, outFile); after <= before {
+			t.Fatalf("%s: want appearances painted into page content, got %d XObject invocations (before: %d)\n", tt.msg, after, before)
+		}
+	}
+}
+
+func TestFlattenSomeFormFields(t *testing.T) {
+
+	msg := "TestFlattenSomeFormFields"
+	inFile := filepath.Join(samplesDir, "form", "demo", "english.pdf")
+	outFile := filepath.Join(outDir, "english-flattenedFields.pdf")
+
+	ss, err := listFormFieldsFile(t, inFile, conf)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	want := len(ss) - 2
+
+	if err := api.FlattenFormFieldsFile(inFile, outFile, []string{"dob1", "firstName1"}, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	ss, err = listFormFieldsFile(t, outFile, conf)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if got := len(ss); got != want {
+		t.Fatalf("%s: want %d, got %d lines\n", msg, want, got)
+	}
+
+	if before, after := xObjectInvocations(t, msg, inFile), xObjectInvocations(t, msg, outFile); after < before+2 {
+		t.Fatalf("%s: want appearances painted into page content, got %d XObject invocations (before: %d)\n", msg, after, before)
+	}
+}
+
 func TestExportForm(t *testing.T) {
 
 	inDir := filepath.Join(samplesDir, "form", "demoSinglePage")
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestHeaderFooter(t *testing.T) {
+	msg := "TestHeaderFooter"
+
+	inFile := filepath.Join(inDir, "adobe_errata.pdf")
+	outFile := filepath.Join(outDir, "headerFooter.pdf")
+
+	hf, err := pdfcpu.ParseHeaderFooterConfig("hl:%f, hr:Page %p of %P, fc:%{Title}\\, %d, mirror:on, margin:25", types.POINTS)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.AddHeaderFooterFile(inFile, outFile, nil, hf, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Adding again replaces the previous header and footer.
+	if err := api.AddHeaderFooterFile(outFile, "", []string{"2-"}, hf, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Headers and footers do not count as watermarks.
+	ok, err := api.HasWatermarksFile(outFile, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if ok {
+		t.Fatalf("%s: unexpected watermark\n", msg)
+	}
+
+	if err := api.RemoveHeaderFooterFile(outFile, "", nil, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.RemoveHeaderFooterFile(outFile, "", nil, nil); err != pdfcpu.ErrNoHeaderFooter {
+		t.Fatalf("%s: expected ErrNoHeaderFooter, got %v\n", msg, err)
+	}
+
+	if _, err := pdfcpu.ParseHeaderFooterConfig("margin:10", types.POINTS); err == nil {
+		t.Fatalf("%s: expected error for missing slots\n", msg)
+	}
+}
+
+func TestHeaderFooterKeepsWatermarks(t *testing.T) {
+	msg := "TestHeaderFooterKeepsWatermarks"
+
+	inFile := filepath.Join(inDir, "Acroforms2.pdf")
+	outFile := filepath.Join(outDir, "headerFooterWatermark.pdf")
+
+	if err := api.AddTextWatermarksFile(inFile, outFile, nil, false, "Draft", "scale:.5", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	hf, err := pdfcpu.ParseHeaderFooterConfig("hc:%l, fr:%p", types.POINTS)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.AddHeaderFooterFile(outFile, "", nil, hf, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.RemoveHeaderFooterFile(outFile, "", nil, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	ok, err := api.HasWatermarksFile(outFile, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if !ok {
+		t.Fatalf("%s: missing watermark\n", msg)
+	}
+}
@@ -83,6 +83,12 @@ func TestImportImages(t *testing.T) {
 			testFile1,
 			"f:A4, pos:c, sc:.9, bgcol:#beded9, sepia:true"},
 
+		// Import another image as a new page of testfile1 and convert image to black and white.
+		{"TestCenteredGraySepia",
+			[]string{filepath.Join(resDir, "mountain.png")},
+			testFile1,
+			"f:A4, pos:c, sc:.9, bilevel:on"},
+
 		// Import another image as a new page of testfile1.
 		{"TestCenteredGraySepia",
 			[]string{filepath.Join(resDir, "mountain.tif")},
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+func layers(t *testing.T, msg, fileName string) []pdfcpu.Layer {
+	t.Helper()
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	ll, err := api.Layers(f, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	return ll
+}
+
+// xObjectInvocations returns the number of Do operators of the first page of fileName.
+func xObjectInvocations(t *testing.T, msg, fileName string) int {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	d, _, _, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	bb, err := ctx.PageContent(d)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	return bytes.Count(bb, []byte("Do"))
+}
+
+func TestLayers(t *testing.T) {
+	msg := "TestLayers"
+	inFile := filepath.Join(inDir, "zineTest.pdf")
+	outFile := filepath.Join(outDir, "zineTestLayers.pdf")
+
+	if err := copyFile(t, inFile, outFile); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	ll := layers(t, msg, outFile)
+	if len(ll) != 1 || ll[0].Name != "Watermark" || !ll[0].Visible {
+		t.Fatalf("%s: unexpected layers: %v\n", msg, ll)
+	}
+	n := xObjectInvocations(t, msg, outFile)
+
+	if err := api.AddLayerFile(outFile, "", "Notes", true, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.AddLayerFile(outFile, "", "Notes", true, nil); err == nil {
+		t.Fatalf("%s: adding a duplicate layer should fail\n", msg)
+	}
+	if err := api.RenameLayerFile(outFile, "", "Notes", "Draft", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.SetLayerVisibilityFile(outFile, "", "Watermark", false, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	ll = layers(t, msg, outFile)
+	if len(ll) != 2 || ll[0].Visible || ll[1].Name != "Draft" || !ll[1].Visible {
+		t.Fatalf("%s: unexpected layers: %v\n", msg, ll)
+	}
+
+	if err := api.MergeLayersFile(outFile, "", "Draft", "Watermark", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if ll = layers(t, msg, outFile); len(ll) != 1 || ll[0].Name != "Watermark" {
+		t.Fatalf("%s: unexpected layers: %v\n", msg, ll)
+	}
+
+	// The hidden watermark gets dropped.
+	if err := api.FlattenLayersFile(outFile, "", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if ll = layers(t, msg, outFile); len(ll) != 0 {
+		t.Fatalf("%s: unexpected layers: %v\n", msg, ll)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if got := xObjectInvocations(t, msg, outFile); got != n-1 {
+		t.Fatalf("%s: XObject invocations: got %d, want %d\n", msg, got, n-1)
+	}
+
+	// Removing a layer removes its content.
+	outFile = filepath.Join(outDir, "zineTestLayerRemoved.pdf")
+	if err := api.RemoveLayerFile(inFile, outFile, "Watermark", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if ll = layers(t, msg, outFile); len(ll) != 0 {
+		t.Fatalf("%s: unexpected layers: %v\n", msg, ll)
+	}
+	if got := xObjectInvocations(t, msg, outFile); got != n-1 {
+		t.Fatalf("%s: XObject invocations: got %d, want %d\n", msg, got, n-1)
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func lazyConf() *model.Configuration {
+	conf := model.NewDefaultConfiguration()
+	conf.ReadLazy = true
+	conf.ObjectCacheSize = 10
+	return conf
+}
+
+// checkLazyRead compares the results of reading fn lazily against reading it eagerly.
+func checkLazyRead(t *testing.T, msg, fn string) {
+	t.Helper()
+
+	inFile := filepath.Join(inDir, fn)
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	want, err := api.PageCount(f, model.NewDefaultConfiguration())
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	got, err := api.PageCount(f, lazyConf())
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	if got != want {
+		t.Fatalf("%s %s: pageCount: got %d, want %d\n", msg, fn, got, want)
+	}
+
+	wantProps, err := api.Properties(f, nil)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	gotProps, err := api.Properties(f, lazyConf())
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	if !reflect.DeepEqual(gotProps, wantProps) {
+		t.Fatalf("%s %s: properties: got %v, want %v\n", msg, fn, gotProps, wantProps)
+	}
+
+	wantInfo, err := api.PDFInfo(f, fn, nil, false, model.NewDefaultConfiguration())
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	gotInfo, err := api.PDFInfo(f, fn, nil, false, lazyConf())
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	if gotInfo.PageCount != wantInfo.PageCount ||
+		gotInfo.Title != wantInfo.Title ||
+		gotInfo.Producer != wantInfo.Producer ||
+		!reflect.DeepEqual(gotInfo.Dimensions, wantInfo.Dimensions) ||
+		gotInfo.Form != wantInfo.Form ||
+		gotInfo.Outlines != wantInfo.Outlines {
+		t.Fatalf("%s %s: info: got %+v, want %+v\n", msg, fn, gotInfo, wantInfo)
+	}
+
+	if err := api.ExtractPages(f, outDir, fn, []string{"1-2"}, lazyConf()); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+}
+
+func TestLazyRead(t *testing.T) {
+	msg := "TestLazyRead"
+
+	for _, fn := range []string{
+		"5116.DCT_Filter.pdf", // object streams
+		"adobe_errata.pdf",
+		"CenterOfWhy.pdf",
+		"test.pdf",
+		"Acroforms2.pdf",
+	} {
+		checkLazyRead(t, msg, fn)
+	}
+
+	// Extracted pages are complete.
+	ff, err := filepath.Glob(filepath.Join(outDir, "*_page_*.pdf"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	for _, fn := range ff {
+		if err := api.ValidateFile(fn, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fn, err)
+		}
+	}
+}
+
+// residentCheck records the maximum number of objects held in memory by a lazy loader.
+type residentCheck struct {
+	model.ObjectLoader
+	max int
+}
+
+func (rc *residentCheck) Load(objNr int, entry *model.XRefTableEntry) error {
+	err := rc.ObjectLoader.Load(objNr, entry)
+	if n := rc.Resident(); n > rc.max {
+		rc.max = n
+	}
+	return err
+}
+
+func readContextLazy(t *testing.T, msg, fn string) (*model.Context, *residentCheck) {
+	t.Helper()
+
+	// The file stays open for loading objects on demand.
+	f, err := os.Open(filepath.Join(inDir, fn))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	conf := lazyConf()
+	conf.Cmd = model.EXTRACTPAGES
+
+	ctx, err := api.ReadAndValidate(f, conf)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	if !ctx.Lazy() {
+		t.Fatalf("%s %s: expected lazy context\n", msg, fn)
+	}
+	if ctx.PageCount < 3 {
+		t.Fatalf("%s %s: expected multi page file\n", msg, fn)
+	}
+
+	rc := &residentCheck{ObjectLoader: ctx.Loader}
+	ctx.Loader = rc
+
+	return ctx, rc
+}
+
+// extractPagesLazy extracts the pages of ctx into memory.
+func extractPagesLazy(t *testing.T, msg string, ctx *model.Context, from int) {
+	t.Helper()
+
+	for i := from; i <= ctx.PageCount; i++ {
+		if _, err := api.ExtractPage(ctx, i); err != nil {
+			t.Fatalf("%s page %d: %v\n", msg, i, err)
+		}
+	}
+}
+
+func TestLazyReadBoundedMemory(t *testing.T) {
+	msg := "TestLazyReadBoundedMemory"
+
+	ctx, rc := readContextLazy(t, msg, "adobe_errata.pdf")
+	extractPagesLazy(t, msg, ctx, 1)
+
+	if rc.max == 0 || rc.max > ctx.Conf.ObjectCacheSize {
+		t.Fatalf("%s: resident objects: got %d, want 1..%d\n", msg, rc.max, ctx.Conf.ObjectCacheSize)
+	}
+}
+
+func TestLazyReadModification(t *testing.T) {
+	msg := "TestLazyReadModification"
+
+	ctx, _ := readContextLazy(t, msg, "adobe_errata.pdf")
+
+	// Evict page 1 and modify it after reloading.
+	extractPagesLazy(t, msg, ctx, 1)
+	d, _, _, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	d["Rotate"] = types.Integer(90)
+
+	// Evict again.
+	extractPagesLazy(t, msg, ctx, 2)
+
+	r, err := api.ExtractPage(ctx, 1)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	bb, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	ctxOut, err := api.ReadAndValidate(bytes.NewReader(bb), model.NewDefaultConfiguration())
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	d, _, _, err = ctxOut.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if rot := d.IntEntry("Rotate"); rot == nil || *rot != 90 {
+		t.Fatalf("%s: modification lost after eviction\n", msg)
+	}
+}
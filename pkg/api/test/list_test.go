@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+func unmarshalListing(t *testing.T, msg string, ss []string, key string, v any) pdfcpu.Header {
+	t.Helper()
+
+	if len(ss) != 1 {
+		t.Fatalf("%s: want 1 JSON document, got %d\n", msg, len(ss))
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(ss[0]), &m); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	var h pdfcpu.Header
+	if err := json.Unmarshal(m["header"], &h); err != nil {
+		t.Fatalf("%s: header: %v\n", msg, err)
+	}
+
+	raw, ok := m[key]
+	if !ok {
+		t.Fatalf("%s: missing %q\n", msg, key)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		t.Fatalf("%s: %s: %v\n", msg, key, err)
+	}
+
+	return h
+}
+
+func TestListJSON(t *testing.T) {
+	msg := "TestListJSON"
+	inFile := filepath.Join(inDir, "adobe_errata.pdf")
+
+	ss, err := api.ListAnnotationsFileJSON(inFile, nil, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	var aa []api.AnnotationInfo
+	h := unmarshalListing(t, msg, ss, "annotations", &aa)
+	if h.Source != inFile {
+		t.Fatalf("%s: source: got %s, want %s\n", msg, h.Source, inFile)
+	}
+	if len(aa) == 0 || aa[0].Type != "Link" {
+		t.Fatalf("%s: unexpected annotations: %v\n", msg, aa)
+	}
+
+	ss, err = api.ListBoxesFileJSON(inFile, []string{"1"}, nil, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	var bb []api.PageBoxes
+	unmarshalListing(t, msg, ss, "boxes", &bb)
+	if len(bb) != 1 || bb[0].PageNr != 1 || bb[0].Media == nil {
+		t.Fatalf("%s: unexpected boxes: %v\n", msg, bb)
+	}
+
+	// Multiple files, one of them missing.
+	inFiles := []string{
+		filepath.Join(inDir, "Acroforms2.pdf"),
+		filepath.Join(inDir, "missing.pdf"),
+	}
+	ss, err = api.ListImagesFilesJSON(inFiles, nil, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	var il []api.ImageList
+	unmarshalListing(t, msg, ss, "files", &il)
+	if len(il) != 2 {
+		t.Fatalf("%s: want 2 image listings, got %d\n", msg, len(il))
+	}
+	if il[0].Error != "" || len(il[0].Images) == 0 {
+		t.Fatalf("%s: unexpected image listing: %v\n", msg, il[0])
+	}
+	if il[1].Error == "" {
+		t.Fatalf("%s: missing error for %s\n", msg, il[1].Source)
+	}
+
+	ss, err = api.ListPermissionsFilesJSON(inFiles[:1], nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	var pp []api.PermissionInfo
+	unmarshalListing(t, msg, ss, "files", &pp)
+	if len(pp) != 1 || !pp[0].FullAccess || !pp[0].Print {
+		t.Fatalf("%s: unexpected permissions: %v\n", msg, pp)
+	}
+}
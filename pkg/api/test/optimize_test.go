@@ -17,10 +17,15 @@ limitations under the License.
 package test
 
 import (
+	"bytes"
+	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 )
 
 func TestOptimize(t *testing.T) {
@@ -41,3 +46,516 @@ func TestOptimize(t *testing.T) {
 		t.Fatalf("%s: %v\n", msg, err)
 	}
 }
+
+func TestOptimizeLinearize(t *testing.T) {
+	msg := "TestOptimizeLinearize"
+
+	for _, fileName := range []string{"Acroforms2.pdf", "annotTest.pdf", "go.pdf", "TheGoProgrammingLanguageCh1.pdf"} {
+		inFile := filepath.Join(inDir, fileName)
+		outFile := filepath.Join(outDir, "lin_"+fileName)
+
+		conf := model.NewDefaultConfiguration()
+		conf.Linearize = true
+		if err := api.OptimizeFile(inFile, outFile, conf); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fileName, err)
+		}
+
+		if err := api.ValidateFile(outFile, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fileName, err)
+		}
+
+		ctx, err := api.ReadContextFile(outFile)
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fileName, err)
+		}
+		if !ctx.Read.Linearized {
+			t.Fatalf("%s %s: not linearized\n", msg, fileName)
+		}
+		if ctx.OffsetPrimaryHintTable == nil {
+			t.Fatalf("%s %s: missing primary hint table\n", msg, fileName)
+		}
+
+		checkLinearization(t, msg+" "+fileName, outFile, ctx)
+
+		want, err := api.PageCountFile(inFile)
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fileName, err)
+		}
+		if ctx.PageCount != want {
+			t.Fatalf("%s %s: pageCount want:%d got:%d\n", msg, fileName, want, ctx.PageCount)
+		}
+
+		// Optimizing a linearized file drops linearization.
+		if err := api.OptimizeFile(outFile, "", nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fileName, err)
+		}
+		if ctx, err = api.ReadContextFile(outFile); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fileName, err)
+		}
+		if ctx.Read.Linearized {
+			t.Fatalf("%s %s: still linearized\n", msg, fileName)
+		}
+	}
+}
+
+// bitReader reads big endian bit fields.
+type bitReader struct {
+	bb  []byte
+	pos int // in bits
+}
+
+func (br *bitReader) read(t *testing.T, n int) int {
+	t.Helper()
+	v := 0
+	for i := 0; i < n; i++ {
+		if br.pos/8 >= len(br.bb) {
+			t.Fatalf("hint table exhausted\n")
+		}
+		v = v<<1 | int(br.bb[br.pos/8]>>(7-br.pos%8)&1)
+		br.pos++
+	}
+	return v
+}
+
+// align skips to the next byte boundary.
+func (br *bitReader) align() {
+	br.pos = (br.pos + 7) / 8 * 8
+}
+
+// linearizedLayout provides object offsets and lengths of a linearized file.
+type linearizedLayout struct {
+	ctx        *model.Context
+	offsets    []int64 // sorted object offsets followed by the main cross-reference section offset
+	hintOffset int64
+	hintLen    int64
+}
+
+func (ll linearizedLayout) offset(t *testing.T, objNr int) int64 {
+	t.Helper()
+	e, ok := ll.ctx.Table[objNr]
+	if !ok || e.Offset == nil {
+		t.Fatalf("missing offset for obj#%d\n", objNr)
+	}
+	return *e.Offset
+}
+
+// adjusted returns off as if the hint stream was not present.
+func (ll linearizedLayout) adjusted(off int64) int64 {
+	if off > ll.hintOffset {
+		return off - ll.hintLen
+	}
+	return off
+}
+
+// length returns the number of bytes between the start of objNr and the start of whatever follows.
+func (ll linearizedLayout) length(t *testing.T, objNr int) int64 {
+	t.Helper()
+	off := ll.offset(t, objNr)
+	i := sort.Search(len(ll.offsets), func(i int) bool { return ll.offsets[i] > off })
+	if i == len(ll.offsets) {
+		t.Fatalf("obj#%d: unable to determine length\n", objNr)
+	}
+	return ll.offsets[i] - off
+}
+
+// reachableObjs records the objects reachable from o in seen without passing any object in stop.
+func reachableObjs(ctx *model.Context, o types.Object, stop, seen types.IntSet) {
+	switch o := o.(type) {
+	case types.IndirectRef:
+		objNr := o.ObjectNumber.Value()
+		if seen[objNr] || stop[objNr] {
+			return
+		}
+		seen[objNr] = true
+		o1, _ := ctx.Dereference(o)
+		reachableObjs(ctx, o1, stop, seen)
+	case types.Dict:
+		for k, v := range o {
+			if k != "Parent" {
+				reachableObjs(ctx, v, stop, seen)
+			}
+		}
+	case types.StreamDict:
+		reachableObjs(ctx, o.Dict, stop, seen)
+	case types.Array:
+		for _, v := range o {
+			reachableObjs(ctx, v, stop, seen)
+		}
+	}
+}
+
+// checkLinearization verifies the linearization parameters and the hint tables of a linearized file.
+func checkLinearization(t *testing.T, msg, fileName string, ctx *model.Context) {
+	t.Helper()
+
+	bb, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	var lin types.Dict
+	linNr := 0
+	for objNr := range ctx.LinearizationObjs {
+		if d, err := ctx.DereferenceDict(*types.NewIndirectRef(objNr, 0)); err == nil && d.IsLinearizationParmDict() {
+			lin, linNr = d, objNr
+		}
+	}
+	if lin == nil {
+		t.Fatalf("%s: missing linearization dict\n", msg)
+	}
+
+	intEntry := func(d types.Dict, k string) int {
+		i := d.IntEntry(k)
+		if i == nil {
+			t.Fatalf("%s: missing %s\n", msg, k)
+		}
+		return *i
+	}
+
+	// L: file length
+	if l := intEntry(lin, "L"); l != len(bb) {
+		t.Fatalf("%s: L=%d, file size %d\n", msg, l, len(bb))
+	}
+
+	// T: white-space preceding the first entry of the main cross-reference section
+	tOff := intEntry(lin, "T")
+	if tOff <= 0 || tOff >= len(bb) || !bytes.ContainsAny(bb[tOff:tOff+1], "\r\n ") ||
+		!bytes.HasPrefix(bb[tOff+1:], []byte("0000000000 65535 f")) {
+		t.Fatalf("%s: T=%d does not precede the first main cross-reference entry\n", msg, tOff)
+	}
+	mainXRef := bytes.LastIndex(bb[:tOff], []byte("xref"))
+
+	h := lin.ArrayEntry("H")
+	if len(h) != 2 {
+		t.Fatalf("%s: corrupt H\n", msg)
+	}
+	hintOffset, hintLen := int64(h[0].(types.Integer)), int64(h[1].(types.Integer))
+
+	ll := linearizedLayout{ctx: ctx, hintOffset: hintOffset, hintLen: hintLen}
+	maxObjNr, hintNr := 0, 0
+	for objNr, e := range ctx.Table {
+		if objNr == 0 || e.Free || e.Offset == nil {
+			continue
+		}
+		ll.offsets = append(ll.offsets, *e.Offset)
+		if objNr > maxObjNr {
+			maxObjNr = objNr
+		}
+		if *e.Offset == hintOffset {
+			hintNr = objNr
+		}
+	}
+	ll.offsets = append(ll.offsets, int64(mainXRef))
+	sort.Slice(ll.offsets, func(i, j int) bool { return ll.offsets[i] < ll.offsets[j] })
+
+	if hintNr == 0 || ll.length(t, hintNr) != hintLen {
+		t.Fatalf("%s: H does not match the hint stream\n", msg)
+	}
+
+	// E: end of the first page, where the main section objects start.
+	e := int64(mainXRef)
+	for objNr := 1; objNr < linNr; objNr++ {
+		if off := ll.offset(t, objNr); off < e {
+			e = off
+		}
+	}
+	if got := intEntry(lin, "E"); int64(got) != e {
+		t.Fatalf("%s: E=%d, want %d\n", msg, got, e)
+	}
+
+	// Decode the hint stream.
+	sd, _, err := ctx.DereferenceStreamDict(*types.NewIndirectRef(hintNr, 0))
+	if err != nil || sd == nil {
+		t.Fatalf("%s: missing hint stream: %v\n", msg, err)
+	}
+	if err := sd.Decode(); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	n := ctx.PageCount
+	pageNrs := make([]int, n)
+	pageOffs := make([]int64, n)
+	for i := range pageNrs {
+		ir, err := ctx.PageDictIndRef(i + 1)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		pageNrs[i] = ir.ObjectNumber.Value()
+		pageOffs[i] = ll.adjusted(ll.offset(t, pageNrs[i]))
+	}
+	if pageNrs[0] != intEntry(lin, "O") {
+		t.Fatalf("%s: O=%d, want %d\n", msg, intEntry(lin, "O"), pageNrs[0])
+	}
+
+	// Page offset hint table, see Table F.3
+	br := &bitReader{bb: sd.Content}
+	minObjs := br.read(t, 32)
+	firstPageOff := br.read(t, 32)
+	objsBits := br.read(t, 16)
+	minLen := br.read(t, 32)
+	lenBits := br.read(t, 16)
+	minContentOff := br.read(t, 32)
+	contentOffBits := br.read(t, 16)
+	minContentLen := br.read(t, 32)
+	contentLenBits := br.read(t, 16)
+	refsBits := br.read(t, 16)
+	idBits := br.read(t, 16)
+	numBits := br.read(t, 16)
+	br.read(t, 16)
+
+	if int64(firstPageOff) != pageOffs[0] {
+		t.Fatalf("%s: first page offset %d, want %d\n", msg, firstPageOff, pageOffs[0])
+	}
+
+	// Page offset hint table entries, see Table F.4
+	readAll := func(min, nBits int) []int {
+		ii := make([]int, n)
+		for i := range ii {
+			ii[i] = min + br.read(t, nBits)
+		}
+		br.align()
+		return ii
+	}
+	nObjs := readAll(minObjs, objsBits)
+	pageLen := readAll(minLen, lenBits)
+	nRefs := readAll(0, refsBits)
+	refs := make([][]int, n)
+	for i := range refs {
+		for j := 0; j < nRefs[i]; j++ {
+			refs[i] = append(refs[i], br.read(t, idBits))
+		}
+	}
+	br.align()
+	for i := range refs {
+		for j := 0; j < nRefs[i]; j++ {
+			br.read(t, numBits)
+		}
+	}
+	br.align()
+	contentOff := readAll(minContentOff, contentOffBits)
+	contentLen := readAll(minContentLen, contentLenBits)
+
+	// The first page section ends the first-page cross-reference section.
+	if nObjs[0] != maxObjNr-pageNrs[0]+1 {
+		t.Fatalf("%s: page 1: %d objects, want %d\n", msg, nObjs[0], maxObjNr-pageNrs[0]+1)
+	}
+	if pageOffs[0]+int64(pageLen[0]) != ll.adjusted(e) {
+		t.Fatalf("%s: page 1: length %d does not end at E\n", msg, pageLen[0])
+	}
+
+	// The remaining pages follow each other starting with obj#1.
+	for i := 1; i < n; i++ {
+		wantNr, wantOff := 1, ll.adjusted(e)
+		if i > 1 {
+			wantNr, wantOff = pageNrs[i-1]+nObjs[i-1], pageOffs[i-1]+int64(pageLen[i-1])
+		}
+		if pageNrs[i] != wantNr || pageOffs[i] != wantOff {
+			t.Fatalf("%s: page %d: obj#%d at %d, want obj#%d at %d\n", msg, i+1, pageNrs[i], pageOffs[i], wantNr, wantOff)
+		}
+	}
+
+	// Content streams
+	for i := 0; i < n; i++ {
+		d, _, _, err := ctx.PageDict(i+1, false)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		var objNrs []int
+		o := d["Contents"]
+		if ir, ok := o.(types.IndirectRef); ok {
+			objNrs = append(objNrs, ir.ObjectNumber.Value())
+			o, _ = ctx.Dereference(ir)
+		}
+		if a, ok := o.(types.Array); ok {
+			for _, v := range a {
+				if ir, ok := v.(types.IndirectRef); ok {
+					objNrs = append(objNrs, ir.ObjectNumber.Value())
+				}
+			}
+		}
+		lo, hi := int64(-1), int64(-1)
+		for _, objNr := range objNrs {
+			off := ll.adjusted(ll.offset(t, objNr))
+			if off < pageOffs[i] || off >= pageOffs[i]+int64(pageLen[i]) {
+				continue
+			}
+			if lo < 0 || off < lo {
+				lo = off
+			}
+			if end := off + ll.length(t, objNr); end > hi {
+				hi = end
+			}
+		}
+		wantOff, wantLen := 0, 0
+		if lo >= 0 {
+			wantOff, wantLen = int(lo-pageOffs[i]), int(hi-lo)
+		}
+		if contentOff[i] != wantOff || contentLen[i] != wantLen {
+			t.Fatalf("%s: page %d: content stream at %d length %d, want %d length %d\n", msg, i+1, contentOff[i], contentLen[i], wantOff, wantLen)
+		}
+	}
+
+	// Shared object hint table, see Table F.5
+	br = &bitReader{bb: sd.Content, pos: intEntry(sd.Dict, "S") * 8}
+	firstShared := br.read(t, 32)
+	firstSharedOff := br.read(t, 32)
+	nFirst := br.read(t, 32)
+	nGroups := br.read(t, 32)
+	if br.read(t, 16) != 0 {
+		t.Fatalf("%s: unexpected shared object groups\n", msg)
+	}
+	minGroupLen := br.read(t, 32)
+	groupLenBits := br.read(t, 16)
+
+	groupObj := func(id int) int {
+		if id < nFirst {
+			return pageNrs[0] + id
+		}
+		return firstShared + id - nFirst
+	}
+
+	if nGroups > nFirst && int64(firstSharedOff) != ll.adjusted(ll.offset(t, firstShared)) {
+		t.Fatalf("%s: shared objects at %d, want %d\n", msg, firstSharedOff, ll.adjusted(ll.offset(t, firstShared)))
+	}
+	for id := 0; id < nGroups; id++ {
+		objNr := groupObj(id)
+		if l := minGroupLen + br.read(t, groupLenBits); int64(l) != ll.length(t, objNr) {
+			t.Fatalf("%s: shared obj#%d: length %d, want %d\n", msg, objNr, l, ll.length(t, objNr))
+		}
+	}
+
+	// Shared objects referenced by a page are reachable from it without passing other pages
+	// and all objects of the shared section reachable from a page are referenced.
+	for i := 0; i < n; i++ {
+		stop, seen := types.IntSet{}, types.IntSet{}
+		for j, objNr := range pageNrs {
+			stop[objNr] = j != i
+		}
+		reachableObjs(ctx, *types.NewIndirectRef(pageNrs[i], 0), stop, seen)
+		ids := map[int]bool{}
+		for _, id := range refs[i] {
+			if id >= nGroups || !seen[groupObj(id)] {
+				t.Fatalf("%s: page %d: unexpected shared object reference %d\n", msg, i+1, id)
+			}
+			ids[id] = true
+		}
+		for id := nFirst; id < nGroups; id++ {
+			if seen[groupObj(id)] && !ids[id] {
+				t.Fatalf("%s: page %d: missing shared object reference %d\n", msg, i+1, id)
+			}
+		}
+	}
+}
+
+func imagesOfPage1(t *testing.T, msg, fileName string) map[int]model.Image {
+	t.Helper()
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	mm, err := api.Images(f, []string{"1"}, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if len(mm) != 1 || len(mm[0]) == 0 {
+		t.Fatalf("%s: missing images\n", msg)
+	}
+
+	return mm[0]
+}
+
+func TestOptimizeImages(t *testing.T) {
+	msg := "TestOptimizeImages"
+	fileName := "mountain.pdf"
+	inFile := filepath.Join(inDir, fileName)
+	outFile := filepath.Join(outDir, "img_"+fileName)
+
+	// Recompress the Flate encoded photo as JPEG and downsample it to 36 dpi.
+	conf := model.NewDefaultConfiguration()
+	conf.OptimizeImagesMaxDPI = 36
+	conf.OptimizeImagesJPEGQuality = 75
+	if err := api.OptimizeFile(inFile, outFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	before := imagesOfPage1(t, msg, inFile)
+	after := imagesOfPage1(t, msg, outFile)
+
+	for objNr, img0 := range before {
+		img1, ok := after[objNr]
+		if !ok {
+			t.Fatalf("%s: missing image obj#%d\n", msg, objNr)
+		}
+		if img1.Filter != "DCTDecode" {
+			t.Fatalf("%s: obj#%d want DCTDecode got %s\n", msg, objNr, img1.Filter)
+		}
+		if img1.Width >= img0.Width || img1.Height >= img0.Height {
+			t.Fatalf("%s: obj#%d not downsampled: %dx%d\n", msg, objNr, img1.Width, img1.Height)
+		}
+		if img1.Size >= img0.Size {
+			t.Fatalf("%s: obj#%d grew from %d to %d bytes\n", msg, objNr, img0.Size, img1.Size)
+		}
+	}
+
+	// JPEG images only get re-encoded when downsampled or converted to gray.
+	conf = model.NewDefaultConfiguration()
+	conf.OptimizeImagesJPEGQuality = 100
+	if err := api.OptimizeFile(outFile, "", conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	for objNr, img := range imagesOfPage1(t, msg, outFile) {
+		if img.Size != after[objNr].Size {
+			t.Fatalf("%s: obj#%d modified\n", msg, objNr)
+		}
+	}
+}
+
+func TestOptimizeSubsetFonts(t *testing.T) {
+	msg := "TestOptimizeSubsetFonts"
+
+	for _, tt := range []struct {
+		fileName string
+		subset   bool
+	}{
+		{filepath.Join(inDir, "CenterOfWhy.pdf"), true},
+		// Fonts used by form fields are left untouched.
+		{filepath.Join(samplesDir, "form", "demoSinglePage", "ukrainian.pdf"), false},
+	} {
+		ctx, err := api.ReadContextFile(tt.fileName)
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.fileName, err)
+		}
+		if err := api.ValidateContext(ctx); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.fileName, err)
+		}
+
+		ctx.Conf.OptimizeSubsetFonts = true
+		if err := api.OptimizeContext(ctx); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.fileName, err)
+		}
+
+		if got := len(ctx.Optimize.SubsetFonts) > 0; got != tt.subset {
+			t.Fatalf("%s %s: subset want:%t got:%t\n", msg, tt.fileName, tt.subset, got)
+		}
+		if tt.subset && ctx.Optimize.FontBytesSaved <= 0 {
+			t.Fatalf("%s %s: no bytes saved\n", msg, tt.fileName)
+		}
+
+		outFile := filepath.Join(outDir, "subset_"+filepath.Base(tt.fileName))
+		if err := api.WriteContextFile(ctx, outFile); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.fileName, err)
+		}
+
+		if err := api.ValidateFile(outFile, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.fileName, err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+func pageLabelStrings(t *testing.T, msg, fileName string) []string {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	ss, err := pdfcpu.PageLabelStrings(ctx)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	return ss
+}
+
+func pageLabels(t *testing.T, msg, fileName string) []pdfcpu.PageLabel {
+	t.Helper()
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	pls, err := api.PageLabels(f, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	return pls
+}
+
+func TestPageLabels(t *testing.T) {
+	msg := "TestPageLabels"
+	inFile := filepath.Join(inDir, "adobe_errata.pdf")
+	outFile := filepath.Join(outDir, "pageLabels.pdf")
+
+	if err := copyFile(t, inFile, outFile); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Label the front matter using lower case roman numerals.
+	pl, err := pdfcpu.ParsePageLabelConfig("style:roman")
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.SetPageLabelsFile(outFile, "", []string{"1-4"}, pl, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Restart decimal numbering for the body.
+	pl, err = pdfcpu.ParsePageLabelConfig("style:decimal, start:1")
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.SetPageLabelsFile(outFile, "", []string{"5-"}, pl, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	want := []pdfcpu.PageLabel{
+		{From: 1, Thru: 4, Style: pdfcpu.PageLabelRomanLower, Start: 1, First: "i", Last: "iv"},
+		{From: 5, Thru: 18, Style: pdfcpu.PageLabelDecimal, Start: 1, First: "1", Last: "14"},
+	}
+	if got := pageLabels(t, msg, outFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s: got %v, want %v\n", msg, got, want)
+	}
+
+	// Select pages by label.
+	ctx, err := api.ReadContextFile(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	ss, err := api.ResolvePageLabels(ctx, []string{"label:ii-iv", "!label:iii", "label:14"})
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if want := []string{"2-4", "!3", "18"}; !reflect.DeepEqual(ss, want) {
+		t.Fatalf("%s: got %v, want %v\n", msg, ss, want)
+	}
+	if _, err := api.ResolvePageLabels(ctx, []string{"label:xx"}); err == nil {
+		t.Fatalf("%s: expected error for unknown label\n", msg)
+	}
+
+	// Removing pages preserves the labels of the remaining pages.
+	outFile2 := filepath.Join(outDir, "pageLabelsRemoved.pdf")
+	if err := api.RemovePagesFile(outFile, outFile2, []string{"label:ii", "label:2-13"}, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if got, want := pageLabelStrings(t, msg, outFile2), []string{"i", "iii", "iv", "1", "14"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s: got %v, want %v\n", msg, got, want)
+	}
+
+	// Merging concatenates the labels of all files.
+	outFile3 := filepath.Join(outDir, "pageLabelsMerged.pdf")
+	if err := api.MergeCreateFile([]string{outFile2, outFile2}, outFile3, false, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if got, want := pageLabelStrings(t, msg, outFile3), []string{"i", "iii", "iv", "1", "14", "i", "iii", "iv", "1", "14"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s: got %v, want %v\n", msg, got, want)
+	}
+
+	if err := api.RemovePageLabelsFile(outFile, "", nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if pls := pageLabels(t, msg, outFile); pls != nil {
+		t.Fatalf("%s: unexpected page labels: %v\n", msg, pls)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// pdfaRules returns the rules violated according to err.
+func pdfaRules(t *testing.T, msg string, err error) map[string]bool {
+	t.Helper()
+	var e *model.PDFAError
+	if !errors.As(err, &e) {
+		t.Fatalf("%s: expected PDF/A violations, got: %v\n", msg, err)
+	}
+	m := map[string]bool{}
+	for _, v := range e.Violations {
+		m[v.Rule] = true
+	}
+	return m
+}
+
+func TestValidatePDFA(t *testing.T) {
+	msg := "TestValidatePDFA"
+
+	for _, tt := range []struct {
+		fileName, profile string
+		want, wantNot     []string
+	}{
+		// No output intent, no metadata, fonts not embedded.
+		{"go.pdf", model.PDFA2B, []string{"6.2.3", "6.6.2.1", "6.2.11.4.1"}, nil},
+		{"go.pdf", model.PDFA1B, []string{"6.2.2", "6.7.2", "6.3.4"}, nil},
+		// Soft masks and transparency groups are only permitted since PDF/A-2.
+		{"RA_CI.pdf", model.PDFA1B, []string{"6.4", "6.7.11"}, nil},
+		{"RA_CI.pdf", model.PDFA2B, []string{"6.6.4"}, []string{"6.2.10"}},
+	} {
+		conf := model.NewDefaultConfiguration()
+		conf.ValidationProfile = tt.profile
+		err := api.ValidateFile(filepath.Join(inDir, tt.fileName), conf)
+		rules := pdfaRules(t, msg+" "+tt.fileName, err)
+		for _, r := range tt.want {
+			if !rules[r] {
+				t.Fatalf("%s %s %s: missing violation of %s: %v\n", msg, tt.fileName, tt.profile, r, err)
+			}
+		}
+		for _, r := range tt.wantNot {
+			if rules[r] {
+				t.Fatalf("%s %s %s: unexpected violation of %s: %v\n", msg, tt.fileName, tt.profile, r, err)
+			}
+		}
+	}
+}
+
+func TestValidatePDFAEncrypted(t *testing.T) {
+	msg := "TestValidatePDFAEncrypted"
+
+	bb, err := os.ReadFile(filepath.Join(inDir, "5116.DCT_Filter.pdf"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	var buf bytes.Buffer
+	if err := api.Encrypt(bytes.NewReader(bb), &buf, model.NewAESConfiguration("upw", "opw", 256)); err != nil {
+		t.Fatalf("%s encrypt: %v\n", msg, err)
+	}
+
+	conf := model.NewAESConfiguration("upw", "opw", 256)
+	conf.ValidationProfile = model.PDFA3B
+	if rules := pdfaRules(t, msg, api.Validate(bytes.NewReader(buf.Bytes()), conf)); !rules["6.1.3"] {
+		t.Fatalf("%s: encryption not reported\n", msg)
+	}
+}
+
+func TestValidatePDFAProfile(t *testing.T) {
+	msg := "TestValidatePDFAProfile"
+
+	if p, ok := model.PDFAProfile("PDF/A-2B"); !ok || p != model.PDFA2B {
+		t.Fatalf("%s: got %s %t\n", msg, p, ok)
+	}
+
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationProfile = "pdfa-4"
+	err := api.ValidateFile(filepath.Join(inDir, "go.pdf"), conf)
+	var e *model.PDFAError
+	if err == nil || errors.As(err, &e) {
+		t.Fatalf("%s: expected error for unsupported profile, got: %v\n", msg, err)
+	}
+}
+
+func TestConvertToPDFA(t *testing.T) {
+	msg := "TestConvertToPDFA"
+	inFile := filepath.Join(inDir, "RA_CI.pdf")
+	outFile := filepath.Join(outDir, "RA_CI_pdfa.pdf")
+
+	for _, profile := range []string{model.PDFA2B, model.PDFA3B} {
+		if err := api.ConvertToPDFAFile(inFile, outFile, profile, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, profile, err)
+		}
+		conf := model.NewDefaultConfiguration()
+		conf.ValidationProfile = profile
+		if err := api.ValidateFile(outFile, conf); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, profile, err)
+		}
+	}
+}
+
+func TestConvertToPDFAUnfixable(t *testing.T) {
+	msg := "TestConvertToPDFAUnfixable"
+	inFile := filepath.Join(inDir, "go.pdf")
+	outFile := filepath.Join(outDir, "go_pdfa.pdf")
+	os.Remove(outFile)
+
+	// Arial is not installed as a user font.
+	err := api.ConvertToPDFAFile(inFile, outFile, model.PDFA2B, nil)
+	if rules := pdfaRules(t, msg, err); !rules["6.2.11.4.1"] || len(rules) != 1 {
+		t.Fatalf("%s: unexpected violations: %v\n", msg, err)
+	}
+	if _, err := os.Stat(outFile); err == nil {
+		t.Fatalf("%s: %s should not have been written\n", msg, outFile)
+	}
+}
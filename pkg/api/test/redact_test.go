@@ -0,0 +1,171 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func pageText(t *testing.T, fileName string, pageNr int) string {
+	t.Helper()
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatalf("open %s: %v\n", fileName, err)
+	}
+	defer f.Close()
+	pp, err := api.ExtractText(f, []string{"1-"}, nil)
+	if err != nil {
+		t.Fatalf("extractText %s: %v\n", fileName, err)
+	}
+	return pp[pageNr-1].String()
+}
+
+func lineBBox(t *testing.T, fileName string, pageNr int, s string) *types.Rectangle {
+	t.Helper()
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatalf("open %s: %v\n", fileName, err)
+	}
+	defer f.Close()
+	pp, err := api.ExtractText(f, []string{"1-"}, nil)
+	if err != nil {
+		t.Fatalf("extractText %s: %v\n", fileName, err)
+	}
+	for _, l := range pp[pageNr-1].Lines {
+		if strings.Contains(l.Text, s) {
+			return l.BBox
+		}
+	}
+	t.Fatalf("%s: missing %q on page %d\n", fileName, s, pageNr)
+	return nil
+}
+
+func TestApplyRedactionsForRects(t *testing.T) {
+	msg := "TestApplyRedactionsForRects"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	outFile := filepath.Join(outDir, "WaldenRedacted.pdf")
+
+	s := "congenial to me. The bullfrogs trump"
+	r := lineBBox(t, inFile, 2, s)
+
+	if err := api.ApplyRedactionsFile(inFile, outFile, []string{"2"}, []*types.Rectangle{r}, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	text := pageText(t, outFile, 2)
+	if strings.Contains(text, "bullfrogs") {
+		t.Fatalf("%s: redacted text still extractable:\n%s", msg, text)
+	}
+	for _, s := range []string{"This is a delicious evening", "of the whip-poor-will is borne on the rippling wind"} {
+		if !strings.Contains(text, s) {
+			t.Fatalf("%s: missing %q:\n%s", msg, s, text)
+		}
+	}
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
+
+func TestApplyRedactionAnnotations(t *testing.T) {
+	msg := "TestApplyRedactionAnnotations"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	outFile := filepath.Join(outDir, "WaldenRedactAnnot.pdf")
+
+	// Stamp some text onto page 1 which is rendered using a form XObject.
+	if err := api.AddTextWatermarksFile(inFile, outFile, []string{"1"}, true, "TopSecret", "fo:Helvetica, points:24, scale:1 abs, pos:c, rot:0", nil); err != nil {
+		t.Fatalf("%s stamp: %v\n", msg, err)
+	}
+	r := lineBBox(t, outFile, 1, "TopSecret")
+
+	// Mark the stamp for redaction.
+	ql := types.NewQuadLiteralForRect(r)
+	ann := model.NewRedactAnnotation(
+		*r,                    // rect
+		"Classified",          // contents
+		"IDRedact",            // id
+		"",                    // modDate
+		0,                     // f
+		&color.Red,            // col
+		"",                    // title
+		nil,                   // popupIndRef
+		nil,                   // ca
+		"",                    // rc
+		"",                    // subject
+		types.QuadPoints{*ql}, // quad points
+		&color.Black,          // fillCol
+		"REDACTED",            // overlayText
+		false,                 // repeat
+		"/Helv 0 Tf 1 g",      // da
+		1,                     // q
+	)
+	if err := api.AddAnnotationsFile(outFile, outFile, []string{"1"}, ann, nil, false); err != nil {
+		t.Fatalf("%s add annotation: %v\n", msg, err)
+	}
+
+	if err := api.ApplyRedactionsFile(outFile, outFile, nil, nil, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	text := pageText(t, outFile, 1)
+	if strings.Contains(text, "TopSecret") || !strings.Contains(text, "REDACTED") {
+		t.Fatalf("%s: unexpected text:\n%s", msg, text)
+	}
+
+	// The redacted text must not survive anywhere in the file.
+	ctx, err := api.ReadContextFile(outFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+	for objNr, e := range ctx.Table {
+		if e == nil || e.Free {
+			continue
+		}
+		sd, ok := e.Object.(types.StreamDict)
+		if !ok || sd.Decode() != nil {
+			continue
+		}
+		if bytes.Contains(sd.Content, []byte("TopSecret")) {
+			t.Fatalf("%s: redacted text found in obj#%d\n", msg, objNr)
+		}
+	}
+
+	// The Redact annotation is gone.
+	f, err := os.Open(outFile)
+	if err != nil {
+		t.Fatalf("%s open: %v\n", msg, err)
+	}
+	defer f.Close()
+	annots, err := api.Annotations(f, nil, nil)
+	if err != nil {
+		t.Fatalf("%s annotations: %v\n", msg, err)
+	}
+	for _, pa := range annots {
+		if _, ok := pa[model.AnnRedact]; ok {
+			t.Fatalf("%s: Redact annotation not removed\n", msg)
+		}
+	}
+}
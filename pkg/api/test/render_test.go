@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// inkRatio returns the ratio of non white pixels of img.
+func inkRatio(img image.Image) float64 {
+	b := img.Bounds()
+	n := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			if r < 0xF000 || g < 0xF000 || b < 0xF000 {
+				n++
+			}
+		}
+	}
+	return float64(n) / float64(b.Dx()*b.Dy())
+}
+
+func TestRenderPage(t *testing.T) {
+	msg := "TestRenderPage"
+	inFile := filepath.Join(inDir, "TheGoProgrammingLanguageCh1.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+
+	pbs, err := ctx.PageBoundaries(nil)
+	if err != nil {
+		t.Fatalf("%s pageBoundaries: %v\n", msg, err)
+	}
+
+	for _, pageNr := range []int{1, 2, 5} {
+		img, err := api.RenderPage(ctx, pageNr, 72)
+		if err != nil {
+			t.Fatalf("%s page %d: %v\n", msg, pageNr, err)
+		}
+		// Pages are rendered using the crop box.
+		cb := pbs[pageNr-1].CropBox()
+		if w, h := img.Bounds().Dx(), img.Bounds().Dy(); w != int(math.Ceil(cb.Width()-0.01)) || h != int(math.Ceil(cb.Height()-0.01)) {
+			t.Fatalf("%s page %d: unexpected size %d x %d for %.2f x %.2f\n", msg, pageNr, w, h, cb.Width(), cb.Height())
+		}
+		if r := inkRatio(img); r < 0.01 {
+			t.Fatalf("%s page %d: page rendered blank (%.4f)\n", msg, pageNr, r)
+		}
+	}
+
+	if _, err := api.RenderPage(ctx, ctx.PageCount+1, 72); err == nil {
+		t.Fatalf("%s: expected error for missing page\n", msg)
+	}
+}
+
+func TestRenderRotatedPage(t *testing.T) {
+	msg := "TestRenderRotatedPage"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+
+	bb, err := os.ReadFile(inFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	var buf bytes.Buffer
+	if err := api.Rotate(bytes.NewReader(bb), &buf, 90, []string{"1"}, nil); err != nil {
+		t.Fatalf("%s rotate: %v\n", msg, err)
+	}
+
+	var sizes [2]image.Rectangle
+	for i, bb := range [][]byte{bb, buf.Bytes()} {
+		err := api.RenderPages(bytes.NewReader(bb), []string{"1"}, 50, func(pageNr int, img image.Image) error {
+			sizes[i] = img.Bounds()
+			return nil
+		}, nil)
+		if err != nil {
+			t.Fatalf("%s render: %v\n", msg, err)
+		}
+	}
+
+	if sizes[0].Dx() != sizes[1].Dy() || sizes[0].Dy() != sizes[1].Dx() {
+		t.Fatalf("%s: rotation not applied: %v %v\n", msg, sizes[0], sizes[1])
+	}
+}
+
+func TestRenderPagesFile(t *testing.T) {
+	msg := "TestRenderPagesFile"
+
+	for _, tt := range []struct {
+		fileName, format string
+	}{
+		{"annotTest.pdf", "png"},
+		{"read.go.pdf", "jpg"},
+		{"VectorApple.pdf", "tif"},
+	} {
+		inFile := filepath.Join(inDir, tt.fileName)
+		if err := api.RenderPagesFile(inFile, outDir, []string{"1"}, 100, tt.format, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, inFile, err)
+		}
+	}
+
+	if err := api.RenderPagesFile(filepath.Join(inDir, "annotTest.pdf"), outDir, nil, 100, "bmp", nil); err == nil {
+		t.Fatalf("%s: expected error for unsupported format\n", msg)
+	}
+}
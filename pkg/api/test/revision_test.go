@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+func TestRevisions(t *testing.T) {
+	msg := "TestRevisions"
+	inFile := filepath.Join(inDir, "testWithText.pdf")
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	revs, err := api.Revisions(f, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if len(revs) != 2 {
+		t.Fatalf("%s: want 2 revisions, got %d\n", msg, len(revs))
+	}
+	if len(revs[0].Changed) != revs[0].Objects {
+		t.Fatalf("%s: revision 1 must introduce all its objects: %v\n", msg, revs[0])
+	}
+	if revs[1].Size <= revs[0].Size || len(revs[1].Changed) == 0 {
+		t.Fatalf("%s: unexpected revision 2: %v\n", msg, revs[1])
+	}
+
+	ctx, err := api.ReadContextAtRevision(f, 1, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if ctx.Read.FileSize != revs[0].Size {
+		t.Fatalf("%s: file size: got %d, want %d\n", msg, ctx.Read.FileSize, revs[0].Size)
+	}
+
+	if _, err := api.ReadContextAtRevision(f, 3, nil); !errors.Is(err, pdfcpu.ErrNoRevision) {
+		t.Fatalf("%s: want ErrNoRevision, got %v\n", msg, err)
+	}
+
+	// Roll back a copy to the original document.
+	outFile := filepath.Join(outDir, "testWithTextRev1.pdf")
+	if err := copyFile(t, inFile, outFile); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ExtractRevisionFile(outFile, "", 1, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	fi, err := os.Stat(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if fi.Size() != revs[0].Size {
+		t.Fatalf("%s: size: got %d, want %d\n", msg, fi.Size(), revs[0].Size)
+	}
+
+	ss, err := api.ListRevisionsFileJSON(outFile, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	var rr []pdfcpu.Revision
+	unmarshalListing(t, msg, ss, "revisions", &rr)
+	if !reflect.DeepEqual(rr, revs[:1]) {
+		t.Fatalf("%s: got %v, want %v\n", msg, rr, revs[:1])
+	}
+}
@@ -46,13 +46,14 @@ func testPageSelectionSyntaxFail(t *testing.T, s string) {
 // This is used to select specific pages for extraction and trimming.
 func TestPageSelectionSyntax(t *testing.T) {
 	psOk := []string{"1", "!1", "n1", "1-", "!1-", "n1-", "-5", "!-5", "n-5", "3-5", "!3-5", "n3-5",
-		"1,2,3", "!-5,10-15,30-", "1-,n4", "odd", "even", " 1"}
+		"1,2,3", "!-5,10-15,30-", "1-,n4", "odd", "even", " 1",
+		"label:iv", "label:iv-x", "!label:A-1", "nlabel:ii,5-", "1-,label:i-iii"}
 
 	for _, s := range psOk {
 		testPageSelectionSyntaxOk(t, s)
 	}
 
-	psFail := []string{"1,", "1 ", "-", " -", " !"}
+	psFail := []string{"1,", "1 ", "-", " -", " !", "label:", "label:i,"}
 
 	for _, s := range psFail {
 		testPageSelectionSyntaxFail(t, s)
@@ -0,0 +1,567 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// createSigningCredentials generates a self-signed certificate and writes certificate and key as PEM files into outDir.
+func createSigningCredentials(t *testing.T, name string, useECDSA bool) (crypto.Signer, *x509.Certificate, string, string) {
+	t.Helper()
+
+	var (
+		signer crypto.Signer
+		err    error
+	)
+
+	if useECDSA {
+		signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	} else {
+		signer, err = rsa.GenerateKey(rand.Reader, 2048)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name, Organization: []string{"pdfcpu"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, signer.Public(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(outDir, name+".crt.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	keyFile := filepath.Join(outDir, name+".key.pem")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return signer, cert, certFile, keyFile
+}
+
+var reByteRange = regexp.MustCompile(`/ByteRange\s*\[\s*(\d+)\s+(\d+)\s+(\d+)\s+(\d+)\s*\]`)
+
+// checkByteRanges ensures the last signature covers the whole file except for its /Contents value.
+func checkByteRanges(t *testing.T, msg, fileName string, wantSigs int) {
+	t.Helper()
+
+	bb, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	mm := reByteRange.FindAllSubmatch(bb, -1)
+	if len(mm) != wantSigs {
+		t.Fatalf("%s: want %d signatures, got %d\n", msg, wantSigs, len(mm))
+	}
+
+	br := make([]int, 4)
+	for i := range br {
+		br[i], _ = strconv.Atoi(string(mm[len(mm)-1][i+1]))
+	}
+
+	if br[0] != 0 || br[2]+br[3] != len(bb) {
+		t.Fatalf("%s: ByteRange %v does not span file of length %d\n", msg, br, len(bb))
+	}
+
+	if bb[br[1]] != '<' || bb[br[2]-1] != '>' {
+		t.Fatalf("%s: ByteRange %v does not exclude /Contents\n", msg, br)
+	}
+
+	if bytes.Count(bb[br[1]+1:br[2]-1], []byte("0")) == br[2]-br[1]-2 {
+		t.Fatalf("%s: signature value missing\n", msg)
+	}
+
+	checkCMSSignature(t, msg, bb, br)
+}
+
+type testSignedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo asn1.RawValue
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	SignerInfos      []struct {
+		Version            int
+		SID                asn1.RawValue
+		DigestAlgorithm    pkix.AlgorithmIdentifier
+		SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+		SignatureAlgorithm pkix.AlgorithmIdentifier
+		Signature          []byte
+	} `asn1:"set"`
+}
+
+// checkCMSSignature parses the PKCS#7 signature of the signed range br of bb
+// and verifies the message digest and the signature of the signed attributes.
+func checkCMSSignature(t *testing.T, msg string, bb []byte, br []int) {
+	t.Helper()
+
+	der, err := hex.DecodeString(string(bb[br[1]+1 : br[2]-1]))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	var ci struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue `asn1:"explicit,tag:0"`
+	}
+	if _, err := asn1.Unmarshal(der, &ci); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	var sd testSignedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if len(sd.SignerInfos) != 1 {
+		t.Fatalf("%s: want 1 signer info, got %d\n", msg, len(sd.SignerInfos))
+	}
+	si := sd.SignerInfos[0]
+
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil || len(certs) == 0 {
+		t.Fatalf("%s: missing signer certificate: %v\n", msg, err)
+	}
+
+	// The message digest attribute has to match the signed byte range.
+	h := sha256.New()
+	h.Write(bb[br[0] : br[0]+br[1]])
+	h.Write(bb[br[2] : br[2]+br[3]])
+	digest := h.Sum(nil)
+
+	var attrs []struct {
+		Type   asn1.ObjectIdentifier
+		Values []asn1.RawValue `asn1:"set"`
+	}
+	if _, err := asn1.UnmarshalWithParams(si.SignedAttrs.FullBytes, &attrs, "set,tag:0"); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	oidMessageDigest := asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	found := false
+	for _, a := range attrs {
+		if !a.Type.Equal(oidMessageDigest) || len(a.Values) != 1 {
+			continue
+		}
+		var md []byte
+		if _, err := asn1.Unmarshal(a.Values[0].FullBytes, &md); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if !bytes.Equal(md, digest) {
+			t.Fatalf("%s: message digest does not match signed byte range\n", msg)
+		}
+		found = true
+	}
+	if !found {
+		t.Fatalf("%s: missing message digest attribute\n", msg)
+	}
+
+	// The signature covers the DER encoded SET OF signed attributes.
+	attrSet, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: si.SignedAttrs.Bytes})
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	sum := sha256.Sum256(attrSet)
+
+	switch pub := certs[0].PublicKey.(type) {
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], si.Signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, sum[:], si.Signature) {
+			err = errors.New("ecdsa: verification error")
+		}
+	default:
+		t.Fatalf("%s: unexpected public key type %T\n", msg, pub)
+	}
+	if err != nil {
+		t.Fatalf("%s: invalid signature: %v\n", msg, err)
+	}
+}
+
+func TestSignFile(t *testing.T) {
+	msg := "TestSignFile"
+
+	_, _, certFile, keyFile := createSigningCredentials(t, "signerRSA", false)
+
+	inFile := filepath.Join(inDir, "test.pdf")
+	outFile := filepath.Join(outDir, "signedRSA.pdf")
+
+	sc, err := sign.ParseSignatureConfig("reason:Approved, location:Berlin")
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.SignFile(inFile, outFile, certFile, keyFile, sc, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	checkByteRanges(t, msg, outFile, 1)
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
+
+func TestSignFileTwice(t *testing.T) {
+	msg := "TestSignFileTwice"
+
+	_, _, certFile, keyFile := createSigningCredentials(t, "signerECDSA", true)
+
+	inFile := filepath.Join(inDir, "Acroforms2.pdf")
+	outFile := filepath.Join(outDir, "signedTwice.pdf")
+	if err := copyFile(t, inFile, outFile); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	sc, err := sign.ParseSignatureConfig("format:pades, field:Author")
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Sign in place.
+	if err := api.SignFile(outFile, "", certFile, keyFile, sc, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	checkByteRanges(t, msg, outFile, 1)
+
+	// Signing the same field again must fail.
+	if err := api.SignFile(outFile, "", certFile, keyFile, sc, nil); err == nil {
+		t.Fatalf("%s: expected error for already signed field\n", msg)
+	}
+
+	// Countersign using another field.
+	sc.FieldName = "Reviewer"
+	if err := api.SignFile(outFile, "", certFile, keyFile, sc, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	checkByteRanges(t, msg, outFile, 2)
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
+
+func trustStore(t *testing.T, name string, certFiles ...string) string {
+	t.Helper()
+
+	dir := filepath.Join(outDir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fn := range certFiles {
+		if err := copyFile(t, fn, filepath.Join(dir, filepath.Base(fn))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func signFile(t *testing.T, msg, inFile, outFile, certFile, keyFile, desc string) {
+	t.Helper()
+
+	sc, err := sign.ParseSignatureConfig(desc)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.SignFile(inFile, outFile, certFile, keyFile, sc, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
+
+func verifySignatures(t *testing.T, msg, inFile, trustDir string, want int) []*sign.SignatureResult {
+	t.Helper()
+
+	results, err := api.VerifySignaturesFile(inFile, trustDir, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if len(results) != want {
+		t.Fatalf("%s: want %d signatures, got %d\n", msg, want, len(results))
+	}
+
+	return results
+}
+
+func TestVerifySignatures(t *testing.T) {
+	msg := "TestVerifySignatures"
+
+	_, _, certFile, keyFile := createSigningCredentials(t, "verifier", false)
+	_, _, otherCertFile, _ := createSigningCredentials(t, "other", true)
+
+	inFile := filepath.Join(inDir, "test.pdf")
+	outFile := filepath.Join(outDir, "verify.pdf")
+	signFile(t, msg, inFile, outFile, certFile, keyFile, "name:Horst, reason:Approved")
+
+	sr := verifySignatures(t, msg, outFile, trustStore(t, "trustVerifier", certFile), 1)[0]
+	if !sr.Valid() {
+		t.Fatalf("%s: expected valid signature: %v\n", msg, sr.Strings())
+	}
+	if sr.Signer != "verifier" || sr.Reason != "Approved" || sr.AppendedBytes != 0 {
+		t.Fatalf("%s: unexpected result: %v\n", msg, sr.Strings())
+	}
+
+	// An unknown signer.
+	sr = verifySignatures(t, msg, outFile, trustStore(t, "trustOther", otherCertFile), 1)[0]
+	if !sr.DigestOK || !sr.SignatureOK || sr.Trusted || sr.Valid() {
+		t.Fatalf("%s: expected untrusted signature: %v\n", msg, sr.Strings())
+	}
+
+	// Tamper with the signed content.
+	bb, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	// Modify the binary comment following the header.
+	i := bytes.IndexByte(bb, '\n')
+	if bb[i+1] != '%' {
+		t.Fatalf("%s: missing binary comment\n", msg)
+	}
+	bb[i+2] ^= 1
+	tamperedFile := filepath.Join(outDir, "verifyTampered.pdf")
+	if err := os.WriteFile(tamperedFile, bb, 0644); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	sr = verifySignatures(t, msg, tamperedFile, trustStore(t, "trustVerifier", certFile), 1)[0]
+	if sr.DigestOK || sr.Valid() {
+		t.Fatalf("%s: expected broken signature: %v\n", msg, sr.Strings())
+	}
+}
+
+func TestVerifySignaturesModifications(t *testing.T) {
+	msg := "TestVerifySignaturesModifications"
+
+	_, _, certFile, keyFile := createSigningCredentials(t, "certifier", false)
+	trustDir := trustStore(t, "trustCertifier", certFile)
+
+	inFile := filepath.Join(inDir, "test.pdf")
+
+	for _, tt := range []struct {
+		docMDP                  int
+		signingOK, annotatingOK bool
+	}{
+		{0, true, true},
+		{1, false, false},
+		{2, true, false},
+		{3, true, true},
+	} {
+		outFile := filepath.Join(outDir, "certified"+strconv.Itoa(tt.docMDP)+".pdf")
+
+		desc := "field:Author"
+		if tt.docMDP > 0 {
+			desc += ", certify:" + strconv.Itoa(tt.docMDP)
+		}
+		signFile(t, msg, inFile, outFile, certFile, keyFile, desc)
+
+		sr := verifySignatures(t, msg, outFile, trustDir, 1)[0]
+		if !sr.Valid() || sr.DocMDP != tt.docMDP {
+			t.Fatalf("%s: expected valid signature with DocMDP %d: %v\n", msg, tt.docMDP, sr.Strings())
+		}
+
+		// Countersign.
+		signFile(t, msg, outFile, "", certFile, keyFile, "field:Reviewer")
+
+		rr := verifySignatures(t, msg, outFile, trustDir, 2)
+		if rr[0].AppendedBytes == 0 || len(rr[0].Modifications) == 0 {
+			t.Fatalf("%s: expected modifications: %v\n", msg, rr[0].Strings())
+		}
+		if rr[0].ModificationsAllowed() != tt.signingOK {
+			t.Fatalf("%s DocMDP %d: signing permitted = %t: %v\n", msg, tt.docMDP, !tt.signingOK, rr[0].Strings())
+		}
+		if !rr[1].Valid() && tt.signingOK {
+			t.Fatalf("%s: expected valid countersignature: %v\n", msg, rr[1].Strings())
+		}
+
+		// Annotate.
+		if err := api.AddAnnotationsFile(outFile, "", []string{"1"}, textAnn, nil, true); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+
+		rr = verifySignatures(t, msg, outFile, trustDir, 2)
+		if rr[1].ModificationsAllowed() != tt.annotatingOK {
+			t.Fatalf("%s DocMDP %d: annotating permitted = %t: %v\n", msg, tt.docMDP, !tt.annotatingOK, rr[1].Strings())
+		}
+	}
+}
+
+// appendInfoRelabel appends an incremental update replacing the content stream of page 1
+// and adding an info dict referring to it, trying to pass the content change off as metadata.
+func appendInfoRelabel(t *testing.T, msg, fileName string) {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	d, _, _, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	o := d["Contents"]
+	if a, ok := o.(types.Array); ok && len(a) > 0 {
+		o = a[0]
+	}
+	indRef, ok := o.(types.IndirectRef)
+	if !ok {
+		t.Fatalf("%s: missing page content stream\n", msg)
+	}
+
+	bb, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	m := regexp.MustCompile(`startxref\s+(\d+)`).FindAllSubmatch(bb, -1)
+	if len(m) == 0 {
+		t.Fatalf("%s: missing startxref\n", msg)
+	}
+	prev := string(m[len(m)-1][1])
+
+	contentObjNr, infoObjNr := indRef.ObjectNumber.Value(), *ctx.XRefTable.Size
+
+	var buf bytes.Buffer
+	buf.Write(bb)
+	buf.WriteString("\n")
+
+	content := "BT /F1 12 Tf 100 700 Td (Forged) Tj ET"
+	off1 := buf.Len()
+	fmt.Fprintf(&buf, "%d %d obj\n<</Length %d>>\nstream\n%s\nendstream\nendobj\n", contentObjNr, indRef.GenerationNumber.Value(), len(content), content)
+
+	off2 := buf.Len()
+	fmt.Fprintf(&buf, "%d 0 obj\n<</Title (Relabeled) /X %d %d R>>\nendobj\n", infoObjNr, contentObjNr, indRef.GenerationNumber.Value())
+
+	xRefOff := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 1\n0000000000 65535 f \n%d 1\n%010d %05d n \n%d 1\n%010d 00000 n \n", contentObjNr, off1, indRef.GenerationNumber.Value(), infoObjNr, off2)
+	fmt.Fprintf(&buf, "trailer\n<</Size %d /Root %s /Info %d 0 R /Prev %s>>\nstartxref\n%d\n%%%%EOF\n", infoObjNr+1, ctx.XRefTable.Root.PDFString(), infoObjNr, prev, xRefOff)
+
+	if err := os.WriteFile(fileName, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
+
+func TestVerifySignaturesInfoRelabel(t *testing.T) {
+	msg := "TestVerifySignaturesInfoRelabel"
+
+	_, _, certFile, keyFile := createSigningCredentials(t, "relabel", false)
+	trustDir := trustStore(t, "trustRelabel", certFile)
+
+	inFile := filepath.Join(inDir, "test.pdf")
+	outFile := filepath.Join(outDir, "signedRelabel.pdf")
+
+	// Approval signatures permit metadata changes but never content changes.
+	signFile(t, msg, inFile, outFile, certFile, keyFile, "field:Author")
+	appendInfoRelabel(t, msg, outFile)
+
+	sr := verifySignatures(t, msg, outFile, trustDir, 1)[0]
+	if sr.AppendedBytes == 0 || len(sr.Modifications) == 0 {
+		t.Fatalf("%s: expected modifications: %v\n", msg, sr.Strings())
+	}
+	if sr.ModificationsAllowed() {
+		t.Fatalf("%s: content change passed off as metadata: %v\n", msg, sr.Strings())
+	}
+}
+
+func TestVerifySignaturesInvalidByteRange(t *testing.T) {
+	msg := "TestVerifySignaturesInvalidByteRange"
+
+	_, _, certFile, keyFile := createSigningCredentials(t, "byteRange", false)
+	trustDir := trustStore(t, "trustByteRange", certFile)
+
+	inFile := filepath.Join(inDir, "test.pdf")
+	outFile := filepath.Join(outDir, "signedInvalidByteRange.pdf")
+	signFile(t, msg, inFile, outFile, certFile, keyFile, "field:Author")
+
+	bb, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Let the byte range reach beyond the end of the file.
+	loc := reByteRange.FindSubmatchIndex(bb)
+	if loc == nil {
+		t.Fatalf("%s: missing ByteRange\n", msg)
+	}
+	last := bb[loc[8]:loc[9]]
+	copy(last, bytes.Repeat([]byte("9"), len(last)))
+	if err := os.WriteFile(outFile, bb, 0644); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	sr := verifySignatures(t, msg, outFile, trustDir, 1)[0]
+	if sr.Valid() || sr.ByteRangeOK {
+		t.Fatalf("%s: expected invalid ByteRange: %v\n", msg, sr.Strings())
+	}
+	want := fmt.Sprintf("ByteRange:     %v (invalid)", sr.ByteRange)
+	found := false
+	for _, s := range sr.Strings() {
+		if strings.TrimSpace(s) == want {
+			found = true
+		}
+	}
+	if sr.ByteRange[3] == 0 || !found {
+		t.Fatalf("%s: expected the invalid ByteRange to be reported: %v\n", msg, sr.Strings())
+	}
+}
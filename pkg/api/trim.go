@@ -44,7 +44,7 @@ func Trim(rs io.ReadSeeker, w io.Writer, selectedPages []string, conf *model.Con
 		return err
 	}
 
-	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, false, true)
+	pages, err := pagesForPageSelection(ctx, selectedPages, false, true)
 	if err != nil {
 		return err
 	}
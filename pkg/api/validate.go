@@ -25,6 +25,7 @@ import (
 	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/validate"
 	"github.com/pkg/errors"
 )
 
@@ -57,6 +58,10 @@ func Validate(rs io.ReadSeeker, conf *model.Configuration) error {
 		err = errors.Wrap(err, fmt.Sprintf("validation error (obj#:%d)%s", ctx.CurObj, s))
 	}
 
+	if err == nil && conf.ValidationProfile != "" {
+		err = validatePDFA(ctx, conf.ValidationProfile)
+	}
+
 	if err == nil {
 		if conf.Optimize {
 			if log.CLIEnabled() {
@@ -83,13 +88,29 @@ func Validate(rs io.ReadSeeker, conf *model.Configuration) error {
 	return err
 }
 
+// validatePDFA checks ctx against a PDF/A conformance level and reports all violations as *model.PDFAError.
+func validatePDFA(ctx *model.Context, profile string) error {
+	vv, err := validate.PDFA(ctx.XRefTable, profile)
+	if err != nil {
+		return err
+	}
+	if len(vv) > 0 {
+		return &model.PDFAError{Profile: profile, Violations: vv}
+	}
+	return nil
+}
+
 // ValidateFile validates inFile.
 func ValidateFile(inFile string, conf *model.Configuration) error {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
 
-	log.CLI.Printf("validating(mode=%s) %s ...\n", conf.ValidationModeString(), inFile)
+	if conf.ValidationProfile != "" {
+		log.CLI.Printf("validating(mode=%s, profile=%s) %s ...\n", conf.ValidationModeString(), conf.ValidationProfile, inFile)
+	} else {
+		log.CLI.Printf("validating(mode=%s) %s ...\n", conf.ValidationModeString(), inFile)
+	}
 
 	f, err := os.Open(inFile)
 	if err != nil {
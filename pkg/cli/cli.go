@@ -19,6 +19,7 @@ package cli
 
 import (
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 )
 
@@ -54,6 +55,9 @@ func ChangeOwnerPassword(cmd *Command) ([]string, error) {
 
 // ListPermissions of inFile.
 func ListPermissions(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListPermissionsFilesJSON(cmd.InFiles, cmd.Conf)
+	}
 	return ListPermissionsFile(cmd.InFiles, cmd.Conf)
 }
 
@@ -158,6 +162,11 @@ func ExtractContent(cmd *Command) ([]string, error) {
 	return nil, api.ExtractContentFile(*cmd.InFile, *cmd.OutDir, cmd.PageSelection, cmd.Conf)
 }
 
+// ExtractText writes the text of selected pages of inFile into outDir.
+func ExtractText(cmd *Command) ([]string, error) {
+	return nil, api.ExtractTextFile(*cmd.InFile, *cmd.OutDir, cmd.PageSelection, cmd.BoolVal1, cmd.Conf)
+}
+
 // ExtractMetadata dumps all metadata dict entries for inFile into outDir.
 func ExtractMetadata(cmd *Command) ([]string, error) {
 	return nil, api.ExtractMetadataFile(*cmd.InFile, *cmd.OutDir, cmd.Conf)
@@ -165,6 +174,9 @@ func ExtractMetadata(cmd *Command) ([]string, error) {
 
 // ListAttachments returns a list of embedded file attachments for inFile.
 func ListAttachments(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListAttachmentsFileJSON(*cmd.InFile, cmd.Conf)
+	}
 	return ListAttachmentsFile(*cmd.InFile, cmd.Conf)
 }
 
@@ -195,6 +207,9 @@ func CreateCheatSheetsFonts(cmd *Command) ([]string, error) {
 
 // ListFonts gathers information about supported fonts and returns the result as []string.
 func ListFonts(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListFontsJSON()
+	}
 	return api.ListFonts()
 }
 
@@ -205,6 +220,9 @@ func InstallFonts(cmd *Command) ([]string, error) {
 
 // ListKeywords returns a list of keywords for inFile.
 func ListKeywords(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListKeywordsFileJSON(*cmd.InFile, cmd.Conf)
+	}
 	return ListKeywordsFile(*cmd.InFile, cmd.Conf)
 }
 
@@ -220,6 +238,9 @@ func RemoveKeywords(cmd *Command) ([]string, error) {
 
 // ListProperties returns inFile's properties.
 func ListProperties(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListPropertiesFileJSON(*cmd.InFile, cmd.Conf)
+	}
 	return ListPropertiesFile(*cmd.InFile, cmd.Conf)
 }
 
@@ -240,6 +261,9 @@ func Collect(cmd *Command) ([]string, error) {
 
 // ListBoxes returns inFile's page boundaries.
 func ListBoxes(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListBoxesFileJSON(*cmd.InFile, cmd.PageSelection, cmd.PageBoundaries, cmd.Conf)
+	}
 	return ListBoxesFile(*cmd.InFile, cmd.PageSelection, cmd.PageBoundaries, cmd.Conf)
 }
 
@@ -260,6 +284,9 @@ func Crop(cmd *Command) ([]string, error) {
 
 // ListAnnotations returns inFile's page annotations.
 func ListAnnotations(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListAnnotationsFileJSON(*cmd.InFile, cmd.PageSelection, cmd.Conf)
+	}
 	_, ss, err := ListAnnotationsFile(*cmd.InFile, cmd.PageSelection, cmd.Conf)
 	return ss, err
 }
@@ -270,8 +297,127 @@ func RemoveAnnotations(cmd *Command) ([]string, error) {
 	return nil, api.RemoveAnnotationsFile(*cmd.InFile, *cmd.OutFile, cmd.PageSelection, cmd.StringVals, cmd.IntVals, cmd.Conf, incr)
 }
 
+// FlattenAnnotations paints annotations of inFile into the page content and removes them.
+func FlattenAnnotations(cmd *Command) ([]string, error) {
+	return nil, api.FlattenAnnotationsFile(*cmd.InFile, *cmd.OutFile, cmd.PageSelection, cmd.StringVals, pdfcpu.AnnotFlattenMode(cmd.IntVal), cmd.Conf)
+}
+
+// ApplyRedactions removes content located within redacted regions of inFile.
+func ApplyRedactions(cmd *Command) ([]string, error) {
+	return nil, api.ApplyRedactionsFile(*cmd.InFile, *cmd.OutFile, cmd.PageSelection, cmd.Rects, cmd.Conf)
+}
+
+// ConvertToPDFA converts inFile into PDF/A and writes the result to outFile.
+func ConvertToPDFA(cmd *Command) ([]string, error) {
+	return nil, api.ConvertToPDFAFile(*cmd.InFile, *cmd.OutFile, cmd.StringVal, cmd.Conf)
+}
+
+// Diff compares two PDF files.
+func Diff(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.DiffFileJSON(cmd.InFiles[0], cmd.InFiles[1], cmd.Conf)
+	}
+	d, err := api.DiffFile(cmd.InFiles[0], cmd.InFiles[1], cmd.Conf)
+	if err != nil {
+		return nil, err
+	}
+	return d.Strings(), nil
+}
+
+// ListRevisions returns the revisions of an incrementally updated file.
+func ListRevisions(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListRevisionsFileJSON(*cmd.InFile, cmd.Conf)
+	}
+	return api.ListRevisionsFile(*cmd.InFile, cmd.Conf)
+}
+
+// ExtractRevision writes inFile as of a given revision to outFile.
+func ExtractRevision(cmd *Command) ([]string, error) {
+	return nil, api.ExtractRevisionFile(*cmd.InFile, *cmd.OutFile, cmd.IntVal, cmd.Conf)
+}
+
+// ListLayers returns inFile's layers.
+func ListLayers(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListLayersFileJSON(*cmd.InFile, cmd.Conf)
+	}
+	return api.ListLayersFile(*cmd.InFile, cmd.Conf)
+}
+
+// AddLayer adds an empty layer to inFile and writes the result to outFile.
+func AddLayer(cmd *Command) ([]string, error) {
+	return nil, api.AddLayerFile(*cmd.InFile, *cmd.OutFile, cmd.StringVal, true, cmd.Conf)
+}
+
+// RemoveLayer removes a layer along with its content from inFile and writes the result to outFile.
+func RemoveLayer(cmd *Command) ([]string, error) {
+	return nil, api.RemoveLayerFile(*cmd.InFile, *cmd.OutFile, cmd.StringVal, cmd.Conf)
+}
+
+// SetLayerVisibility shows or hides a layer of inFile by default and writes the result to outFile.
+func SetLayerVisibility(cmd *Command) ([]string, error) {
+	return nil, api.SetLayerVisibilityFile(*cmd.InFile, *cmd.OutFile, cmd.StringVal, cmd.BoolVal1, cmd.Conf)
+}
+
+// RenameLayer renames a layer of inFile and writes the result to outFile.
+func RenameLayer(cmd *Command) ([]string, error) {
+	return nil, api.RenameLayerFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals[0], cmd.StringVals[1], cmd.Conf)
+}
+
+// MergeLayers merges a layer of inFile into another one and writes the result to outFile.
+func MergeLayers(cmd *Command) ([]string, error) {
+	return nil, api.MergeLayersFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals[0], cmd.StringVals[1], cmd.Conf)
+}
+
+// FlattenLayers flattens all layers of inFile and writes the result to outFile.
+func FlattenLayers(cmd *Command) ([]string, error) {
+	return nil, api.FlattenLayersFile(*cmd.InFile, *cmd.OutFile, cmd.Conf)
+}
+
+// ListPageLabels returns the page label ranges of inFile.
+func ListPageLabels(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListPageLabelsFileJSON(*cmd.InFile, cmd.Conf)
+	}
+	return api.ListPageLabelsFile(*cmd.InFile, cmd.Conf)
+}
+
+// SetPageLabels labels selected pages of inFile and writes the result to outFile.
+func SetPageLabels(cmd *Command) ([]string, error) {
+	return nil, api.SetPageLabelsFile(*cmd.InFile, *cmd.OutFile, cmd.PageSelection, cmd.PageLabel, cmd.Conf)
+}
+
+// RemovePageLabels removes all page labels from inFile and writes the result to outFile.
+func RemovePageLabels(cmd *Command) ([]string, error) {
+	return nil, api.RemovePageLabelsFile(*cmd.InFile, *cmd.OutFile, cmd.Conf)
+}
+
+// Bates stamps all pages of inFiles with Bates numbers continuing across files and writes the results to outDir.
+func Bates(cmd *Command) ([]string, error) {
+	return nil, api.AddBatesNumbersFiles(cmd.InFiles, *cmd.OutDir, *cmd.OutFile, cmd.Bates, cmd.Conf)
+}
+
+// AddHeaderFooter adds header and footer to selected pages of inFile and writes the result to outFile.
+func AddHeaderFooter(cmd *Command) ([]string, error) {
+	return nil, api.AddHeaderFooterFile(*cmd.InFile, *cmd.OutFile, cmd.PageSelection, cmd.HeaderFooter, cmd.Conf)
+}
+
+// RemoveHeaderFooter removes header and footer from selected pages of inFile and writes the result to outFile.
+func RemoveHeaderFooter(cmd *Command) ([]string, error) {
+	return nil, api.RemoveHeaderFooterFile(*cmd.InFile, *cmd.OutFile, cmd.PageSelection, cmd.Conf)
+}
+
+// Render renders selected pages of inFile into images written to outDir.
+func Render(cmd *Command) ([]string, error) {
+	return nil, api.RenderPagesFile(*cmd.InFile, *cmd.OutDir, cmd.PageSelection, float64(cmd.IntVal), cmd.StringVal, cmd.Conf)
+}
+
 // ListImages returns inFiles embedded images.
 func ListImages(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListImagesFilesJSON(cmd.InFiles, cmd.PageSelection, cmd.Conf)
+	}
 	return ListImagesFile(cmd.InFiles, cmd.PageSelection, cmd.Conf)
 }
 
@@ -308,6 +454,9 @@ func Create(cmd *Command) ([]string, error) {
 
 // ListFormFields returns inFile's form field ids.
 func ListFormFields(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListFormFieldsFilesJSON(cmd.InFiles, cmd.Conf)
+	}
 	return ListFormFieldsFile(cmd.InFiles, cmd.Conf)
 }
 
@@ -326,6 +475,11 @@ func UnlockFormFields(cmd *Command) ([]string, error) {
 	return nil, api.UnlockFormFieldsFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, cmd.Conf)
 }
 
+// FlattenFormFields paints some or all form fields of inFile into the page content and removes them.
+func FlattenFormFields(cmd *Command) ([]string, error) {
+	return nil, api.FlattenFormFieldsFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, cmd.Conf)
+}
+
 // ResetFormFields sets some or all form fields of inFile to the corresponding default value.
 func ResetFormFields(cmd *Command) ([]string, error) {
 	return nil, api.ResetFormFieldsFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, cmd.Conf)
@@ -368,6 +522,9 @@ func Cut(cmd *Command) ([]string, error) {
 
 // ListBookmarks returns inFile's outlines.
 func ListBookmarks(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListBookmarksFileJSON(*cmd.InFile, cmd.Conf)
+	}
 	return ListBookmarksFile(*cmd.InFile, cmd.Conf)
 }
 
@@ -388,6 +545,9 @@ func RemoveBookmarks(cmd *Command) ([]string, error) {
 
 // ListPageLayout returns inFile's page layout.
 func ListPageLayout(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListPageLayoutFileJSON(*cmd.InFile, cmd.Conf)
+	}
 	return api.ListPageLayoutFile(*cmd.InFile, cmd.Conf)
 }
 
@@ -404,6 +564,9 @@ func ResetPageLayout(cmd *Command) ([]string, error) {
 
 // ListPageMode returns inFile's page mode.
 func ListPageMode(cmd *Command) ([]string, error) {
+	if cmd.BoolVal1 {
+		return api.ListPageModeFileJSON(*cmd.InFile, cmd.Conf)
+	}
 	return api.ListPageModeFile(*cmd.InFile, cmd.Conf)
 }
 
@@ -440,3 +603,13 @@ func ResetViewerPreferences(cmd *Command) ([]string, error) {
 func Zoom(cmd *Command) ([]string, error) {
 	return nil, api.ZoomFile(*cmd.InFile, *cmd.OutFile, cmd.PageSelection, cmd.Zoom, cmd.Conf)
 }
+
+// Sign digitally signs inFile using a certificate chain and a private key.
+func Sign(cmd *Command) ([]string, error) {
+	return nil, api.SignFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals[0], cmd.StringVals[1], cmd.Signature, cmd.Conf)
+}
+
+// VerifySignatures verifies the digital signatures of inFile against a trust store.
+func VerifySignatures(cmd *Command) ([]string, error) {
+	return VerifySignaturesFile(*cmd.InFile, *cmd.InDir, cmd.Conf)
+}
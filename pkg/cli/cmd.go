@@ -21,6 +21,7 @@ import (
 
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 )
 
 // Command represents an execution context.
@@ -53,9 +54,14 @@ type Command struct {
 	PageBoundaries    *model.PageBoundaries
 	Resize            *model.Resize
 	Zoom              *model.Zoom
+	Signature         *model.SignatureConfig
 	Watermark         *model.Watermark
 	ViewerPreferences *model.ViewerPreferences
 	PageConf          *pdfcpu.PageConfiguration
+	PageLabel         *pdfcpu.PageLabel
+	Bates             *model.Bates
+	HeaderFooter      *model.HeaderFooter
+	Rects             []*types.Rectangle
 	Conf              *model.Configuration
 }
 
@@ -140,6 +146,31 @@ var cmdMap = map[model.CommandMode]func(cmd *Command) ([]string, error){
 	model.SETVIEWERPREFERENCES:    processViewerPreferences,
 	model.RESETVIEWERPREFERENCES:  processViewerPreferences,
 	model.ZOOM:                    Zoom,
+	model.SIGN:                    Sign,
+	model.VERIFYSIGNATURES:        VerifySignatures,
+	model.EXTRACTTEXT:             ExtractText,
+	model.APPLYREDACTIONS:         ApplyRedactions,
+	model.RENDER:                  Render,
+	model.CONVERTPDFA:             ConvertToPDFA,
+	model.DIFF:                    Diff,
+	model.LISTREVISIONS:           ListRevisions,
+	model.EXTRACTREVISION:         ExtractRevision,
+	model.LISTLAYERS:              ListLayers,
+	model.ADDLAYER:                AddLayer,
+	model.REMOVELAYER:             RemoveLayer,
+	model.SHOWLAYER:               SetLayerVisibility,
+	model.HIDELAYER:               SetLayerVisibility,
+	model.RENAMELAYER:             RenameLayer,
+	model.MERGELAYERS:             MergeLayers,
+	model.FLATTENLAYERS:           FlattenLayers,
+	model.FLATTENFORMFIELDS:       processForm,
+	model.FLATTENANNOTATIONS:      processPageAnnotations,
+	model.LISTPAGELABELS:          ListPageLabels,
+	model.SETPAGELABELS:           SetPageLabels,
+	model.REMOVEPAGELABELS:        RemovePageLabels,
+	model.BATES:                   Bates,
+	model.ADDHEADERFOOTER:         AddHeaderFooter,
+	model.REMOVEHEADERFOOTER:      RemoveHeaderFooter,
 }
 
 // ValidateCommand creates a new command to validate a file.
@@ -297,6 +328,21 @@ func ExtractContentCommand(inFile string, outDir string, pageSelection []string,
 		Conf:          conf}
 }
 
+// ExtractTextCommand creates a new command to extract page text as plain text or JSON.
+func ExtractTextCommand(inFile string, outDir string, pageSelection []string, json bool, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXTRACTTEXT
+	return &Command{
+		Mode:          model.EXTRACTTEXT,
+		InFile:        &inFile,
+		OutDir:        &outDir,
+		PageSelection: pageSelection,
+		BoolVal1:      json,
+		Conf:          conf}
+}
+
 // ExtractMetadataCommand creates a new command to extract metadata streams.
 func ExtractMetadataCommand(inFile string, outDir string, conf *model.Configuration) *Command {
 	if conf == nil {
@@ -325,15 +371,16 @@ func TrimCommand(inFile, outFile string, pageSelection []string, conf *model.Con
 }
 
 // ListAttachmentsCommand create a new command to list attachments.
-func ListAttachmentsCommand(inFile string, conf *model.Configuration) *Command {
+func ListAttachmentsCommand(inFile string, json bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
 	conf.Cmd = model.LISTATTACHMENTS
 	return &Command{
-		Mode:   model.LISTATTACHMENTS,
-		InFile: &inFile,
-		Conf:   conf}
+		Mode:     model.LISTATTACHMENTS,
+		InFile:   &inFile,
+		BoolVal1: json,
+		Conf:     conf}
 }
 
 // AddAttachmentsCommand creates a new command to add attachments.
@@ -449,15 +496,16 @@ func ChangeOwnerPWCommand(inFile, outFile string, pwOld, pwNew *string, conf *mo
 }
 
 // ListPermissionsCommand create a new command to list permissions.
-func ListPermissionsCommand(inFiles []string, conf *model.Configuration) *Command {
+func ListPermissionsCommand(inFiles []string, json bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
 	conf.Cmd = model.LISTPERMISSIONS
 	return &Command{
-		Mode:    model.LISTPERMISSIONS,
-		InFiles: inFiles,
-		Conf:    conf}
+		Mode:     model.LISTPERMISSIONS,
+		InFiles:  inFiles,
+		BoolVal1: json,
+		Conf:     conf}
 }
 
 // SetPermissionsCommand creates a new command to add permissions.
@@ -610,14 +658,15 @@ func InfoCommand(inFiles []string, pageSelection []string, fonts, json bool, con
 }
 
 // ListFontsCommand returns a list of supported fonts.
-func ListFontsCommand(conf *model.Configuration) *Command {
+func ListFontsCommand(json bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
 	conf.Cmd = model.LISTFONTS
 	return &Command{
-		Mode: model.LISTFONTS,
-		Conf: conf}
+		Mode:     model.LISTFONTS,
+		BoolVal1: json,
+		Conf:     conf}
 }
 
 // InstallFontsCommand installs true type fonts for embedding.
@@ -645,15 +694,16 @@ func CreateCheatSheetsFontsCommand(fontFiles []string, conf *model.Configuration
 }
 
 // ListKeywordsCommand create a new command to list keywords.
-func ListKeywordsCommand(inFile string, conf *model.Configuration) *Command {
+func ListKeywordsCommand(inFile string, json bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
 	conf.Cmd = model.LISTKEYWORDS
 	return &Command{
-		Mode:   model.LISTKEYWORDS,
-		InFile: &inFile,
-		Conf:   conf}
+		Mode:     model.LISTKEYWORDS,
+		InFile:   &inFile,
+		BoolVal1: json,
+		Conf:     conf}
 }
 
 // AddKeywordsCommand creates a new command to add keywords.
@@ -685,15 +735,16 @@ func RemoveKeywordsCommand(inFile, outFile string, keywords []string, conf *mode
 }
 
 // ListPropertiesCommand creates a new command to list document properties.
-func ListPropertiesCommand(inFile string, conf *model.Configuration) *Command {
+func ListPropertiesCommand(inFile string, json bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
 	conf.Cmd = model.LISTPROPERTIES
 	return &Command{
-		Mode:   model.LISTPROPERTIES,
-		InFile: &inFile,
-		Conf:   conf}
+		Mode:     model.LISTPROPERTIES,
+		InFile:   &inFile,
+		BoolVal1: json,
+		Conf:     conf}
 }
 
 // AddPropertiesCommand creates a new command to add document properties.
@@ -739,7 +790,7 @@ func CollectCommand(inFile, outFile string, pageSelection []string, conf *model.
 }
 
 // ListBoxesCommand creates a new command to list page boundaries for selected pages.
-func ListBoxesCommand(inFile string, pageSelection []string, pb *model.PageBoundaries, conf *model.Configuration) *Command {
+func ListBoxesCommand(inFile string, pageSelection []string, pb *model.PageBoundaries, json bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
@@ -749,6 +800,7 @@ func ListBoxesCommand(inFile string, pageSelection []string, pb *model.PageBound
 		InFile:         &inFile,
 		PageSelection:  pageSelection,
 		PageBoundaries: pb,
+		BoolVal1:       json,
 		Conf:           conf}
 }
 
@@ -798,7 +850,7 @@ func CropCommand(inFile, outFile string, pageSelection []string, box *model.Box,
 }
 
 // ListAnnotationsCommand creates a new command to list annotations for selected pages.
-func ListAnnotationsCommand(inFile string, pageSelection []string, conf *model.Configuration) *Command {
+func ListAnnotationsCommand(inFile string, pageSelection []string, json bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
@@ -807,6 +859,7 @@ func ListAnnotationsCommand(inFile string, pageSelection []string, conf *model.C
 		Mode:          model.LISTANNOTATIONS,
 		InFile:        &inFile,
 		PageSelection: pageSelection,
+		BoolVal1:      json,
 		Conf:          conf}
 }
 
@@ -826,8 +879,69 @@ func RemoveAnnotationsCommand(inFile, outFile string, pageSelection []string, id
 		Conf:          conf}
 }
 
+// FlattenAnnotationsCommand creates a new command to flatten annotations for selected pages.
+func FlattenAnnotationsCommand(inFile, outFile string, pageSelection []string, annotTypes []string, mode pdfcpu.AnnotFlattenMode, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FLATTENANNOTATIONS
+	return &Command{
+		Mode:          model.FLATTENANNOTATIONS,
+		InFile:        &inFile,
+		OutFile:       &outFile,
+		PageSelection: pageSelection,
+		StringVals:    annotTypes,
+		IntVal:        int(mode),
+		Conf:          conf}
+}
+
+// ApplyRedactionsCommand creates a new command to apply redactions for selected pages.
+func ApplyRedactionsCommand(inFile, outFile string, pageSelection []string, rects []*types.Rectangle, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.APPLYREDACTIONS
+	return &Command{
+		Mode:          model.APPLYREDACTIONS,
+		InFile:        &inFile,
+		OutFile:       &outFile,
+		PageSelection: pageSelection,
+		Rects:         rects,
+		Conf:          conf}
+}
+
+// RenderCommand creates a new command to render selected pages into images.
+func RenderCommand(inFile, outDir string, pageSelection []string, dpi int, format string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.RENDER
+	return &Command{
+		Mode:          model.RENDER,
+		InFile:        &inFile,
+		OutDir:        &outDir,
+		PageSelection: pageSelection,
+		IntVal:        dpi,
+		StringVal:     format,
+		Conf:          conf}
+}
+
+// ConvertToPDFACommand creates a new command to convert a file into PDF/A.
+func ConvertToPDFACommand(inFile, outFile, profile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.CONVERTPDFA
+	return &Command{
+		Mode:      model.CONVERTPDFA,
+		InFile:    &inFile,
+		OutFile:   &outFile,
+		StringVal: profile,
+		Conf:      conf}
+}
+
 // ListImagesCommand creates a new command to list annotations for selected pages.
-func ListImagesCommand(inFiles []string, pageSelection []string, conf *model.Configuration) *Command {
+func ListImagesCommand(inFiles []string, pageSelection []string, json bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
@@ -836,6 +950,7 @@ func ListImagesCommand(inFiles []string, pageSelection []string, conf *model.Con
 		Mode:          model.LISTIMAGES,
 		InFiles:       inFiles,
 		PageSelection: pageSelection,
+		BoolVal1:      json,
 		Conf:          conf}
 }
 
@@ -883,15 +998,16 @@ func CreateCommand(inFilePDF, inFileJSON, outFilePDF string, conf *model.Configu
 }
 
 // ListFormFieldsCommand creates a new command to list the field ids from a PDF form.
-func ListFormFieldsCommand(inFiles []string, conf *model.Configuration) *Command {
+func ListFormFieldsCommand(inFiles []string, json bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
 	conf.Cmd = model.LISTFORMFIELDS
 	return &Command{
-		Mode:    model.LISTFORMFIELDS,
-		InFiles: inFiles,
-		Conf:    conf}
+		Mode:     model.LISTFORMFIELDS,
+		InFiles:  inFiles,
+		BoolVal1: json,
+		Conf:     conf}
 }
 
 // RemoveFormFieldsCommand creates a new command to remove fields from a PDF form.
@@ -936,6 +1052,20 @@ func UnlockFormCommand(inFile, outFile string, fieldIDs []string, conf *model.Co
 		Conf:       conf}
 }
 
+// FlattenFormCommand creates a new command to flatten PDF form fields.
+func FlattenFormCommand(inFile, outFile string, fieldIDs []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FLATTENFORMFIELDS
+	return &Command{
+		Mode:       model.FLATTENFORMFIELDS,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: fieldIDs,
+		Conf:       conf}
+}
+
 // ResetFormCommand creates a new command to lock PDF form fields.
 func ResetFormCommand(inFile, outFile string, fieldIDs []string, conf *model.Configuration) *Command {
 	if conf == nil {
@@ -1058,15 +1188,16 @@ func CutCommand(inFile, outDir, outFile string, pageSelection []string, cut *mod
 }
 
 // ListBookmarksCommand creates a new command to list bookmarks of inFile.
-func ListBookmarksCommand(inFile string, conf *model.Configuration) *Command {
+func ListBookmarksCommand(inFile string, json bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
 	conf.Cmd = model.LISTBOOKMARKS
 	return &Command{
-		Mode:   model.LISTBOOKMARKS,
-		InFile: &inFile,
-		Conf:   conf}
+		Mode:     model.LISTBOOKMARKS,
+		InFile:   &inFile,
+		BoolVal1: json,
+		Conf:     conf}
 }
 
 // ExportBookmarksCommand creates a new command to export bookmarks of inFile.
@@ -1111,15 +1242,16 @@ func RemoveBookmarksCommand(inFile, outFile string, conf *model.Configuration) *
 }
 
 // ListPageLayoutCommand creates a new command to list the document page layout.
-func ListPageLayoutCommand(inFile string, conf *model.Configuration) *Command {
+func ListPageLayoutCommand(inFile string, json bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
 	conf.Cmd = model.LISTPAGELAYOUT
 	return &Command{
-		Mode:   model.LISTPAGELAYOUT,
-		InFile: &inFile,
-		Conf:   conf}
+		Mode:     model.LISTPAGELAYOUT,
+		InFile:   &inFile,
+		BoolVal1: json,
+		Conf:     conf}
 }
 
 // SetPageLayoutCommand creates a new command to set the document page layout.
@@ -1150,15 +1282,16 @@ func ResetPageLayoutCommand(inFile, outFile string, conf *model.Configuration) *
 }
 
 // ListPageModeCommand creates a new command to list the document page mode.
-func ListPageModeCommand(inFile string, conf *model.Configuration) *Command {
+func ListPageModeCommand(inFile string, json bool, conf *model.Configuration) *Command {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
 	conf.Cmd = model.LISTPAGEMODE
 	return &Command{
-		Mode:   model.LISTPAGEMODE,
-		InFile: &inFile,
-		Conf:   conf}
+		Mode:     model.LISTPAGEMODE,
+		InFile:   &inFile,
+		BoolVal1: json,
+		Conf:     conf}
 }
 
 // SetPageModeCommand creates a new command to set the document page mode.
@@ -1246,3 +1379,257 @@ func ZoomCommand(inFile, outFile string, pageSelection []string, zoom *model.Zoo
 		Zoom:          zoom,
 		Conf:          conf}
 }
+
+// SignCommand creates a new command to digitally sign a file.
+func SignCommand(inFile, outFile, certFile, keyFile string, sc *model.SignatureConfig, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.SIGN
+	return &Command{
+		Mode:       model.SIGN,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: []string{certFile, keyFile},
+		Signature:  sc,
+		Conf:       conf}
+}
+
+// VerifySignaturesCommand creates a new command to verify the digital signatures of a file.
+func VerifySignaturesCommand(inFile, trustStoreDir string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.VERIFYSIGNATURES
+	return &Command{
+		Mode:   model.VERIFYSIGNATURES,
+		InFile: &inFile,
+		InDir:  &trustStoreDir,
+		Conf:   conf}
+}
+
+// DiffCommand creates a new command to compare two PDF files.
+func DiffCommand(inFile1, inFile2 string, json bool, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.DIFF
+	return &Command{
+		Mode:     model.DIFF,
+		InFiles:  []string{inFile1, inFile2},
+		BoolVal1: json,
+		Conf:     conf}
+}
+
+// ListRevisionsCommand creates a new command to list the revisions of an incrementally updated file.
+func ListRevisionsCommand(inFile string, json bool, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTREVISIONS
+	return &Command{
+		Mode:     model.LISTREVISIONS,
+		InFile:   &inFile,
+		BoolVal1: json,
+		Conf:     conf}
+}
+
+// ExtractRevisionCommand creates a new command to extract a revision of an incrementally updated file.
+func ExtractRevisionCommand(inFile, outFile string, revision int, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXTRACTREVISION
+	return &Command{
+		Mode:    model.EXTRACTREVISION,
+		InFile:  &inFile,
+		OutFile: &outFile,
+		IntVal:  revision,
+		Conf:    conf}
+}
+
+// ListLayersCommand creates a new command to list the layers of a file.
+func ListLayersCommand(inFile string, json bool, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTLAYERS
+	return &Command{
+		Mode:     model.LISTLAYERS,
+		InFile:   &inFile,
+		BoolVal1: json,
+		Conf:     conf}
+}
+
+// AddLayerCommand creates a new command to add an empty layer.
+func AddLayerCommand(inFile, outFile, name string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.ADDLAYER
+	return &Command{
+		Mode:      model.ADDLAYER,
+		InFile:    &inFile,
+		OutFile:   &outFile,
+		StringVal: name,
+		Conf:      conf}
+}
+
+// RemoveLayerCommand creates a new command to remove a layer along with its content.
+func RemoveLayerCommand(inFile, outFile, name string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REMOVELAYER
+	return &Command{
+		Mode:      model.REMOVELAYER,
+		InFile:    &inFile,
+		OutFile:   &outFile,
+		StringVal: name,
+		Conf:      conf}
+}
+
+// SetLayerVisibilityCommand creates a new command to show or hide a layer by default.
+func SetLayerVisibilityCommand(inFile, outFile, name string, visible bool, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	mode := model.HIDELAYER
+	if visible {
+		mode = model.SHOWLAYER
+	}
+	conf.Cmd = mode
+	return &Command{
+		Mode:      mode,
+		InFile:    &inFile,
+		OutFile:   &outFile,
+		StringVal: name,
+		BoolVal1:  visible,
+		Conf:      conf}
+}
+
+// RenameLayerCommand creates a new command to rename a layer.
+func RenameLayerCommand(inFile, outFile, oldName, newName string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.RENAMELAYER
+	return &Command{
+		Mode:       model.RENAMELAYER,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: []string{oldName, newName},
+		Conf:       conf}
+}
+
+// MergeLayersCommand creates a new command to merge a layer into another one.
+func MergeLayersCommand(inFile, outFile, from, to string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.MERGELAYERS
+	return &Command{
+		Mode:       model.MERGELAYERS,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: []string{from, to},
+		Conf:       conf}
+}
+
+// FlattenLayersCommand creates a new command to flatten all layers.
+func FlattenLayersCommand(inFile, outFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FLATTENLAYERS
+	return &Command{
+		Mode:    model.FLATTENLAYERS,
+		InFile:  &inFile,
+		OutFile: &outFile,
+		Conf:    conf}
+}
+
+// ListPageLabelsCommand creates a new command to list the page labels of a file.
+func ListPageLabelsCommand(inFile string, json bool, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTPAGELABELS
+	return &Command{
+		Mode:     model.LISTPAGELABELS,
+		InFile:   &inFile,
+		BoolVal1: json,
+		Conf:     conf}
+}
+
+// SetPageLabelsCommand creates a new command to label selected pages.
+func SetPageLabelsCommand(inFile, outFile string, pageSelection []string, pl *pdfcpu.PageLabel, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.SETPAGELABELS
+	return &Command{
+		Mode:          model.SETPAGELABELS,
+		InFile:        &inFile,
+		OutFile:       &outFile,
+		PageSelection: pageSelection,
+		PageLabel:     pl,
+		Conf:          conf}
+}
+
+// RemovePageLabelsCommand creates a new command to remove all page labels.
+func RemovePageLabelsCommand(inFile, outFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REMOVEPAGELABELS
+	return &Command{
+		Mode:    model.REMOVEPAGELABELS,
+		InFile:  &inFile,
+		OutFile: &outFile,
+		Conf:    conf}
+}
+
+// BatesCommand creates a new command to stamp Bates numbers continuing across inFiles.
+func BatesCommand(inFiles []string, outDir, logFile string, b *model.Bates, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.BATES
+	return &Command{
+		Mode:    model.BATES,
+		InFiles: inFiles,
+		OutDir:  &outDir,
+		OutFile: &logFile,
+		Bates:   b,
+		Conf:    conf}
+}
+
+// AddHeaderFooterCommand creates a new command to add header and footer to selected pages of a file.
+func AddHeaderFooterCommand(inFile, outFile string, pageSelection []string, hf *model.HeaderFooter, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.ADDHEADERFOOTER
+	return &Command{
+		Mode:          model.ADDHEADERFOOTER,
+		InFile:        &inFile,
+		OutFile:       &outFile,
+		PageSelection: pageSelection,
+		HeaderFooter:  hf,
+		Conf:          conf}
+}
+
+// RemoveHeaderFooterCommand creates a new command to remove header and footer from selected pages of a file.
+func RemoveHeaderFooterCommand(inFile, outFile string, pageSelection []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REMOVEHEADERFOOTER
+	return &Command{
+		Mode:          model.REMOVEHEADERFOOTER,
+		InFile:        &inFile,
+		OutFile:       &outFile,
+		PageSelection: pageSelection,
+		Conf:          conf}
+}
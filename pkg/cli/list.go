@@ -128,6 +128,11 @@ func listBoxes(rs io.ReadSeeker, selectedPages []string, pb *model.PageBoundarie
 		return nil, err
 	}
 
+	selectedPages, err = api.ResolvePageLabels(ctx, selectedPages)
+	if err != nil {
+		return nil, err
+	}
+
 	pages, err := api.PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
 	if err != nil {
 		return nil, err
@@ -216,6 +221,11 @@ func listImages(rs io.ReadSeeker, selectedPages []string, conf *model.Configurat
 		return nil, err
 	}
 
+	selectedPages, err = api.ResolvePageLabels(ctx, selectedPages)
+	if err != nil {
+		return nil, err
+	}
+
 	pages, err := api.PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
 	if err != nil {
 		return nil, err
@@ -541,3 +551,27 @@ func ListBookmarksFile(inFile string, conf *model.Configuration) ([]string, erro
 
 	return listBookmarks(f, conf)
 }
+
+// VerifySignaturesFile returns a verification report for all digital signatures of inFile.
+func VerifySignaturesFile(inFile, trustStoreDir string, conf *model.Configuration) ([]string, error) {
+	results, err := api.VerifySignaturesFile(inFile, trustStoreDir, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return []string{"no signatures available"}, nil
+	}
+
+	valid := 0
+	ss := []string{}
+	for _, sr := range results {
+		ss = append(ss, sr.Strings()...)
+		ss = append(ss, "")
+		if sr.Valid() {
+			valid++
+		}
+	}
+
+	return append(ss, fmt.Sprintf("%d of %d signatures valid", valid, len(results))), nil
+}
@@ -110,6 +110,9 @@ func processForm(cmd *Command) (out []string, err error) {
 	case model.UNLOCKFORMFIELDS:
 		return UnlockFormFields(cmd)
 
+	case model.FLATTENFORMFIELDS:
+		return FlattenFormFields(cmd)
+
 	case model.RESETFORMFIELDS:
 		return ResetFormFields(cmd)
 
@@ -164,6 +167,9 @@ func processPageAnnotations(cmd *Command) (out []string, err error) {
 
 	case model.REMOVEANNOTATIONS:
 		out, err = RemoveAnnotations(cmd)
+
+	case model.FLATTENANNOTATIONS:
+		out, err = FlattenAnnotations(cmd)
 	}
 
 	return out, err
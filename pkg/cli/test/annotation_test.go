@@ -33,7 +33,7 @@ func TestListAndRemoveAnnotations(t *testing.T) {
 	// See also api/annotations_test.go for page annotation manipulation
 	// including adding annotations.
 
-	cmd := cli.ListAnnotationsCommand(inFile, nil, conf)
+	cmd := cli.ListAnnotationsCommand(inFile, nil, false, conf)
 	if _, err := cli.Process(cmd); err != nil {
 		t.Fatalf("%s: %v\n", msg, err)
 	}
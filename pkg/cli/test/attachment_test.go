@@ -37,7 +37,7 @@ func prepareForAttachmentTest(t *testing.T) error {
 
 func listAttachments(t *testing.T, msg, fileName string, want int) []string {
 	t.Helper()
-	cmd := cli.ListAttachmentsCommand(fileName, conf)
+	cmd := cli.ListAttachmentsCommand(fileName, false, conf)
 	list, err := cli.Process(cmd)
 	if err != nil {
 		t.Fatalf("%s list attachments: %v\n", msg, err)
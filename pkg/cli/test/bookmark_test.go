@@ -29,7 +29,7 @@ func TestListBookmarks(t *testing.T) {
 	inDir := filepath.Join("..", "..", "samples", "bookmarks")
 	inFile := filepath.Join(inDir, "bookmarkTree.pdf")
 
-	cmd := cli.ListBookmarksCommand(inFile, conf)
+	cmd := cli.ListBookmarksCommand(inFile, false, conf)
 	if _, err := cli.Process(cmd); err != nil {
 		t.Fatalf("%s: %v\n", msg, err)
 	}
@@ -29,7 +29,7 @@ func TestListBoxesCommand(t *testing.T) {
 	inFile := filepath.Join(inDir, "5116.DCT_Filter.pdf")
 
 	// List all page boundaries for all pages.
-	cmd := cli.ListBoxesCommand(inFile, nil, nil, conf)
+	cmd := cli.ListBoxesCommand(inFile, nil, nil, false, conf)
 	if _, err := cli.Process(cmd); err != nil {
 		t.Fatalf("%s: %v\n", msg, err)
 	}
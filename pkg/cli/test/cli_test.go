@@ -28,6 +28,7 @@ import (
 	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
 )
 
 var inDir, outDir, resDir, fontDir, samplesDir string
@@ -149,6 +150,37 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidatePDFACommand(t *testing.T) {
+	msg := "TestValidatePDFACommand"
+	inFile := filepath.Join(inDir, "go.pdf")
+
+	pdfaConf := model.NewDefaultConfiguration()
+	pdfaConf.ValidationProfile = model.PDFA2B
+
+	_, err := cli.Process(cli.ValidateCommand([]string{inFile}, pdfaConf))
+	var e *model.PDFAError
+	if !errors.As(err, &e) {
+		t.Fatalf("%s: expected PDF/A violations, got: %v\n", msg, err)
+	}
+}
+
+func TestConvertToPDFACommand(t *testing.T) {
+	msg := "TestConvertToPDFACommand"
+	inFile := filepath.Join(inDir, "RA_CI.pdf")
+	outFile := filepath.Join(outDir, "RA_CI_pdfa.pdf")
+
+	cmd := cli.ConvertToPDFACommand(inFile, outFile, model.PDFA2B, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	pdfaConf := model.NewDefaultConfiguration()
+	pdfaConf.ValidationProfile = model.PDFA2B
+	if _, err := cli.Process(cli.ValidateCommand([]string{outFile}, pdfaConf)); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
+
 func TestInfoCommand(t *testing.T) {
 	msg := "TestInfoCommand"
 	inFile := filepath.Join(inDir, "5116.DCT_Filter.pdf")
@@ -224,7 +224,7 @@ func testEncryptDecryptUseCase2(t *testing.T, fileName string, aes bool, keyLeng
 	// List permissions
 	conf = model.NewDefaultConfiguration()
 	conf.OwnerPW = "opw"
-	cmd = cli.ListPermissionsCommand([]string{outFile}, conf)
+	cmd = cli.ListPermissionsCommand([]string{outFile}, false, conf)
 	list, err := cli.Process(cmd)
 	if err != nil {
 		t.Fatalf("%s: list permissions for %s: %v\n", msg, outFile, err)
@@ -445,7 +445,7 @@ func testPermissionsOPWOnly(t *testing.T, fileName string, aes bool, keyLength i
 	outFile := filepath.Join(outDir, "test.pdf")
 	t.Log(inFile)
 
-	cmd := cli.ListPermissionsCommand([]string{inFile}, nil)
+	cmd := cli.ListPermissionsCommand([]string{inFile}, false, nil)
 	list, err := cli.Process(cmd)
 	if err != nil {
 		t.Fatalf("%s: list permissions %s: %v\n", msg, inFile, err)
@@ -461,7 +461,7 @@ func testPermissionsOPWOnly(t *testing.T, fileName string, aes bool, keyLength i
 		t.Fatalf("%s: encrypt %s: %v\n", msg, outFile, err)
 	}
 
-	cmd = cli.ListPermissionsCommand([]string{outFile}, nil)
+	cmd = cli.ListPermissionsCommand([]string{outFile}, false, nil)
 	if list, err = cli.Process(cmd); err != nil {
 		t.Fatalf("%s: list permissions %s: %v\n", msg, outFile, err)
 	}
@@ -475,7 +475,7 @@ func testPermissionsOPWOnly(t *testing.T, fileName string, aes bool, keyLength i
 		t.Fatalf("%s: set all permissions for %s: %v\n", msg, outFile, err)
 	}
 
-	cmd = cli.ListPermissionsCommand([]string{outFile}, nil)
+	cmd = cli.ListPermissionsCommand([]string{outFile}, false, nil)
 	if list, err = cli.Process(cmd); err != nil {
 		t.Fatalf("%s: list permissions for %s: %v\n", msg, outFile, err)
 	}
@@ -505,7 +505,7 @@ func testPermissions(t *testing.T, fileName string, aes bool, keyLength int) {
 	outFile := filepath.Join(outDir, "test.pdf")
 	t.Log(inFile)
 
-	cmd := cli.ListPermissionsCommand([]string{inFile}, nil)
+	cmd := cli.ListPermissionsCommand([]string{inFile}, false, nil)
 	list, err := cli.Process(cmd)
 	if err != nil {
 		t.Fatalf("%s: list permissions %s: %v\n", msg, inFile, err)
@@ -522,14 +522,14 @@ func testPermissions(t *testing.T, fileName string, aes bool, keyLength int) {
 		t.Fatalf("%s: encrypt %s: %v\n", msg, outFile, err)
 	}
 
-	cmd = cli.ListPermissionsCommand([]string{outFile}, nil)
+	cmd = cli.ListPermissionsCommand([]string{outFile}, false, nil)
 	if _, err = cli.Process(cmd); err == nil {
 		t.Fatalf("%s: list permissions w/o pw %s\n", msg, outFile)
 	}
 
 	conf = confForAlgorithm(aes, keyLength)
 	conf.UserPW = "upw"
-	cmd = cli.ListPermissionsCommand([]string{outFile}, conf)
+	cmd = cli.ListPermissionsCommand([]string{outFile}, false, conf)
 	if list, err = cli.Process(cmd); err != nil {
 		t.Fatalf("%s: list permissions %s: %v\n", msg, outFile, err)
 	}
@@ -537,7 +537,7 @@ func testPermissions(t *testing.T, fileName string, aes bool, keyLength int) {
 
 	conf = model.NewDefaultConfiguration()
 	conf.OwnerPW = "opw"
-	cmd = cli.ListPermissionsCommand([]string{outFile}, conf)
+	cmd = cli.ListPermissionsCommand([]string{outFile}, false, conf)
 	if list, err = cli.Process(cmd); err != nil {
 		t.Fatalf("%s: list permissions %s: %v\n", msg, outFile, err)
 	}
@@ -575,14 +575,14 @@ func testPermissions(t *testing.T, fileName string, aes bool, keyLength int) {
 		t.Fatalf("%s: set all permissions for %s: %v\n", msg, outFile, err)
 	}
 
-	cmd = cli.ListPermissionsCommand([]string{outFile}, nil)
+	cmd = cli.ListPermissionsCommand([]string{outFile}, false, nil)
 	if _, err = cli.Process(cmd); err == nil {
 		t.Fatalf("%s: list permissions w/o pw %s\n", msg, outFile)
 	}
 
 	conf = confForAlgorithm(aes, keyLength)
 	conf.OwnerPW = "opw"
-	cmd = cli.ListPermissionsCommand([]string{outFile}, conf)
+	cmd = cli.ListPermissionsCommand([]string{outFile}, false, conf)
 	if list, err = cli.Process(cmd); err != nil {
 		t.Fatalf("%s: list permissions for %s: %v\n", msg, outFile, err)
 	}
@@ -87,6 +87,18 @@ func TestExtractContentCommand(t *testing.T) {
 	}
 }
 
+func TestExtractTextCommand(t *testing.T) {
+	msg := "TestExtractTextCommand"
+	// Extract text of all pages into outDir.
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	for _, json := range []bool{false, true} {
+		cmd := cli.ExtractTextCommand(inFile, outDir, nil, json, conf)
+		if _, err := cli.Process(cmd); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, inFile, err)
+		}
+	}
+}
+
 func TestExtractMetadataCommand(t *testing.T) {
 	msg := "TestExtractMetadataCommand"
 	// Extract metadata into outDir.
@@ -43,7 +43,7 @@ func TestInstallTTCFontsCommand(t *testing.T) {
 
 func TestListFontsCommand(t *testing.T) {
 	msg := "TestListFontsCommand"
-	cmd := cli.ListFontsCommand(conf)
+	cmd := cli.ListFontsCommand(false, conf)
 	if _, err := cli.Process(cmd); err != nil {
 		t.Fatalf("%s list fonts: %v\n", msg, err)
 	}
@@ -26,7 +26,7 @@ import (
 
 func listKeywords(t *testing.T, msg, fileName string, want []string) []string {
 	t.Helper()
-	cmd := cli.ListKeywordsCommand(fileName, conf)
+	cmd := cli.ListKeywordsCommand(fileName, false, conf)
 	got, err := cli.Process(cmd)
 	if err != nil {
 		t.Fatalf("%s list keywords: %v\n", msg, err)
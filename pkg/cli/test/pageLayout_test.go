@@ -32,7 +32,7 @@ func TestPageLayout(t *testing.T) {
 	inFile := filepath.Join(inDir, "test.pdf")
 	outFile := filepath.Join(outDir, "test.pdf")
 
-	cmd := cli.ListPageLayoutCommand(inFile, conf)
+	cmd := cli.ListPageLayoutCommand(inFile, false, conf)
 	ss, err := cli.Process(cmd)
 	if err != nil {
 		t.Fatalf("%s %s: list pageLayout: %v\n", msg, inFile, err)
@@ -46,7 +46,7 @@ func TestPageLayout(t *testing.T) {
 		t.Fatalf("%s %s: set pageLayout: %v\n", msg, outFile, err)
 	}
 
-	cmd = cli.ListPageLayoutCommand(outFile, conf)
+	cmd = cli.ListPageLayoutCommand(outFile, false, conf)
 	ss, err = cli.Process(cmd)
 	if err != nil {
 		t.Fatalf("%s %s: list pageLayout: %v\n", msg, outFile, err)
@@ -63,7 +63,7 @@ func TestPageLayout(t *testing.T) {
 		t.Fatalf("%s %s: reset pageLayout: %v\n", msg, outFile, err)
 	}
 
-	cmd = cli.ListPageLayoutCommand(outFile, conf)
+	cmd = cli.ListPageLayoutCommand(outFile, false, conf)
 	ss, err = cli.Process(cmd)
 	if err != nil {
 		t.Fatalf("%s %s: list pageLayout: %v\n", msg, outFile, err)
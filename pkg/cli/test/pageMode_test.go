@@ -32,7 +32,7 @@ func TestPageMode(t *testing.T) {
 	inFile := filepath.Join(inDir, "test.pdf")
 	outFile := filepath.Join(outDir, "test.pdf")
 
-	cmd := cli.ListPageModeCommand(inFile, conf)
+	cmd := cli.ListPageModeCommand(inFile, false, conf)
 	ss, err := cli.Process(cmd)
 	if err != nil {
 		t.Fatalf("%s %s: list pageMode: %v\n", msg, inFile, err)
@@ -46,7 +46,7 @@ func TestPageMode(t *testing.T) {
 		t.Fatalf("%s %s: set pageMode: %v\n", msg, outFile, err)
 	}
 
-	cmd = cli.ListPageModeCommand(outFile, conf)
+	cmd = cli.ListPageModeCommand(outFile, false, conf)
 	ss, err = cli.Process(cmd)
 	if err != nil {
 		t.Fatalf("%s %s: list pageMode: %v\n", msg, outFile, err)
@@ -63,7 +63,7 @@ func TestPageMode(t *testing.T) {
 		t.Fatalf("%s %s: reset pageMode: %v\n", msg, outFile, err)
 	}
 
-	cmd = cli.ListPageModeCommand(outFile, conf)
+	cmd = cli.ListPageModeCommand(outFile, false, conf)
 	ss, err = cli.Process(cmd)
 	if err != nil {
 		t.Fatalf("%s %s: list pageMode: %v\n", msg, outFile, err)
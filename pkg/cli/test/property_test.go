@@ -25,7 +25,7 @@ import (
 
 func listProperties(t *testing.T, msg, fileName string, want []string) []string {
 	t.Helper()
-	cmd := cli.ListPropertiesCommand(fileName, conf)
+	cmd := cli.ListPropertiesCommand(fileName, false, conf)
 	got, err := cli.Process(cmd)
 	if err != nil {
 		t.Fatalf("%s list properties: %v\n", msg, err)
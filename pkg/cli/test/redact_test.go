@@ -0,0 +1,45 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/cli"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestApplyRedactionsCommand(t *testing.T) {
+	msg := "TestApplyRedactionsCommand"
+
+	// See also api/redact_test.go for applying Redact annotations.
+
+	inFile := filepath.Join(inDir, "mountain.pdf")
+	outFile := filepath.Join(outDir, "mountainRedacted.pdf")
+
+	rects := []*types.Rectangle{types.NewRectangle(100, 300, 400, 500)}
+
+	cmd := cli.ApplyRedactionsCommand(inFile, outFile, nil, rects, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := validateFile(t, outFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
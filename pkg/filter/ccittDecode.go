@@ -31,8 +31,50 @@ type ccittDecode struct {
 
 // Encode implements encoding for a CCITTDecode filter.
 func (f ccittDecode) Encode(r io.Reader) (io.Reader, error) {
-	// TODO
-	return nil, nil
+	if log.TraceEnabled() {
+		log.Trace.Println("EncodeCCITT begin")
+	}
+
+	k := f.parms["K"]
+
+	cols := 1728
+	col, ok := f.parms["Columns"]
+	if ok {
+		cols = col
+	}
+	if cols <= 0 {
+		return nil, errors.New("pdfcpu: ccitt: invalid DecodeParam \"Columns\"")
+	}
+
+	bb, err := getReaderBytes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	stride := (cols + 7) / 8
+	rows, ok := f.parms["Rows"]
+	if !ok {
+		rows = len(bb) / stride
+	}
+	if rows < 0 || len(bb) < rows*stride {
+		return nil, errors.New("pdfcpu: ccitt: insufficient image data")
+	}
+
+	blackIs1 := f.parms["BlackIs1"] == 1
+	encodedByteAlign := f.parms["EncodedByteAlign"] == 1
+
+	eob := true
+	if v, ok := f.parms["EndOfBlock"]; ok && v == 0 {
+		eob = false
+	}
+
+	b := encodeCCITT(bb, k, cols, rows, blackIs1, encodedByteAlign, eob)
+
+	if log.TraceEnabled() {
+		log.Trace.Printf("EncodeCCITT end: encoded %d bytes.\n", b.Len())
+	}
+
+	return b, nil
 }
 
 // Decode implements decoding for a CCITTDecode filter.
@@ -45,16 +87,10 @@ func (f ccittDecode) DecodeLength(r io.Reader, maxLen int64) (io.Reader, error)
 		log.Trace.Println("DecodeCCITT begin")
 	}
 
-	var ok bool
-
 	// <0 : Pure two-dimensional encoding (Group 4)
 	// =0 : Pure one-dimensional encoding (Group 3, 1-D)
 	// >0 : Mixed one- and two-dimensional encoding (Group 3, 2-D)
-	k := 0
-	k, ok = f.parms["K"]
-	if ok && k > 0 {
-		return nil, errors.New("pdfcpu: filter CCITTFax k > 0 currently unsupported")
-	}
+	k := f.parms["K"]
 
 	cols := 1728
 	col, ok := f.parms["Columns"]
@@ -79,6 +115,22 @@ func (f ccittDecode) DecodeLength(r io.Reader, maxLen int64) (io.Reader, error)
 		encodedByteAlign = true
 	}
 
+	if k > 0 {
+		// Group 3 2-D is not supported by golang.org/x/image/ccitt.
+		bb, err := getReaderBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		b, err := decodeCCITTMixed(bb, cols, rows, blackIs1)
+		if err != nil {
+			return nil, err
+		}
+		if log.TraceEnabled() {
+			log.Trace.Printf("DecodeCCITT: decoded %d bytes.\n", b.Len())
+		}
+		return b, nil
+	}
+
 	opts := &ccitt.Options{Invert: blackIs1, Align: encodedByteAlign}
 
 	mode := ccitt.Group3
@@ -99,3 +151,227 @@ func (f ccittDecode) DecodeLength(r io.Reader, maxLen int64) (io.Reader, error)
 
 	return &b, nil
 }
+
+var ccittWhiteRuns, ccittBlackRuns = ccittRunCodes(false), ccittRunCodes(true)
+
+// ccittRunCodes maps the run length codes of one color to their run lengths.
+func ccittRunCodes(black bool) map[string]int {
+	term, makeup := ccittWhiteTermCodes, ccittWhiteMakeupCodes
+	if black {
+		term, makeup = ccittBlackTermCodes, ccittBlackMakeupCodes
+	}
+	m := map[string]int{}
+	for i, c := range term {
+		m[c] = i
+	}
+	for i, c := range makeup {
+		m[c] = (i + 1) * 64
+	}
+	for i, c := range ccittExtMakeupCodes {
+		m[c] = 1792 + i*64
+	}
+	return m
+}
+
+// ccittReader reads CCITT code words MSB first.
+type ccittReader struct {
+	bb  []byte
+	pos int // bit position
+}
+
+func (r *ccittReader) bit() (byte, error) {
+	if r.pos >= len(r.bb)*8 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b := r.bb[r.pos>>3] >> (7 - r.pos&7) & 1
+	r.pos++
+	return b, nil
+}
+
+// code reads bits until they form a code contained in m.
+func (r *ccittReader) code(m map[string]int) (int, error) {
+	var code []byte
+	for len(code) < 13 {
+		b, err := r.bit()
+		if err != nil {
+			return 0, err
+		}
+		code = append(code, '0'+b)
+		if v, ok := m[string(code)]; ok {
+			return v, nil
+		}
+	}
+	return 0, errors.New("pdfcpu: ccitt: invalid code")
+}
+
+// run reads the make-up and terminating codes of a run.
+func (r *ccittReader) run(black bool) (int, error) {
+	m := ccittWhiteRuns
+	if black {
+		m = ccittBlackRuns
+	}
+	n := 0
+	for {
+		v, err := r.code(m)
+		if err != nil {
+			return 0, err
+		}
+		n += v
+		if v < 64 {
+			return n, nil
+		}
+	}
+}
+
+// eol skips fill bits and an optional EOL code.
+func (r *ccittReader) eol() {
+	pos, zeros := r.pos, 0
+	for {
+		b, err := r.bit()
+		if err != nil || (b == 1 && zeros < 11) {
+			r.pos = pos
+			return
+		}
+		if b == 1 {
+			return
+		}
+		zeros++
+	}
+}
+
+var ccittModes = func() map[string]int {
+	m := map[string]int{ccittPass: 10, ccittHorizontal: 11}
+	for i, c := range ccittVerticalCodes {
+		m[c] = i - 3
+	}
+	return m
+}()
+
+// decode1D decodes a row coded using modified Huffman coding and returns its changing elements.
+func (r *ccittReader) decode1D(cols int, cur []int) ([]int, error) {
+	cur = cur[:0]
+	a0, black := 0, false
+	for a0 < cols {
+		n, err := r.run(black)
+		if err != nil {
+			return nil, err
+		}
+		if a0 += n; a0 > cols {
+			return nil, errors.New("pdfcpu: ccitt: run length too long")
+		}
+		if a0 < cols {
+			cur = append(cur, a0)
+		}
+		black = !black
+	}
+	return append(cur, cols, cols, cols), nil
+}
+
+// decode2D decodes a row coded using modified READ coding relative to the reference row with changing elements ref
+// and returns its changing elements.
+func (r *ccittReader) decode2D(ref []int, cols int, cur []int) ([]int, error) {
+	cur = cur[:0]
+	a0, black := -1, false
+	j := 0
+
+	for a0 < cols {
+		for ref[j] <= a0 {
+			j++
+		}
+		k := j
+		if k&1 == 1 != black {
+			k++
+		}
+		b1, b2 := ref[k], ref[k+1]
+
+		mode, err := r.code(ccittModes)
+		if err != nil {
+			return nil, err
+		}
+
+		switch mode {
+		case 10: // pass
+			a0 = b2
+
+		case 11: // horizontal
+			n1, err := r.run(black)
+			if err != nil {
+				return nil, err
+			}
+			n2, err := r.run(!black)
+			if err != nil {
+				return nil, err
+			}
+			a1 := max(a0, 0) + n1
+			a2 := a1 + n2
+			if a2 > cols {
+				return nil, errors.New("pdfcpu: ccitt: run length too long")
+			}
+			cur = append(cur, a1, a2)
+			a0 = a2
+
+		default: // vertical
+			a1 := b1 + mode
+			if a1 < max(a0, 0) || a1 > cols {
+				return nil, errors.New("pdfcpu: ccitt: invalid vertical mode")
+			}
+			cur = append(cur, a1)
+			a0, black = a1, !black
+		}
+	}
+
+	// Drop changes located at the end of the row.
+	for len(cur) > 0 && cur[len(cur)-1] >= cols {
+		cur = cur[:len(cur)-1]
+	}
+
+	return append(cur, cols, cols, cols), nil
+}
+
+// decodeCCITTMixed decodes Group 3 2-D (K > 0) coded data into rows of packed 1 bit samples.
+func decodeCCITTMixed(bb []byte, cols, rows int, blackIs1 bool) (*bytes.Buffer, error) {
+	r := &ccittReader{bb: bb}
+	stride := (cols + 7) / 8
+	out := make([]byte, stride*rows)
+
+	ref := []int{cols, cols, cols}
+	var cur []int
+
+	for y := 0; y < rows; y++ {
+		// Fill bits precede the EOL.
+		r.eol()
+
+		tag, err := r.bit()
+		if err != nil {
+			return nil, err
+		}
+
+		if tag == 1 {
+			cur, err = r.decode1D(cols, cur)
+		} else {
+			cur, err = r.decode2D(ref, cols, cur)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "row %d", y)
+		}
+
+		// Paint the row starting white.
+		row := out[y*stride : (y+1)*stride]
+		x, black := 0, false
+		for _, c := range cur {
+			if !black != blackIs1 {
+				for ; x < c && x < cols; x++ {
+					row[x>>3] |= 0x80 >> (x & 7)
+				}
+			}
+			x, black = c, !black
+			if x >= cols {
+				break
+			}
+		}
+
+		ref, cur = cur, ref
+	}
+
+	return bytes.NewBuffer(out), nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+// bilevelTestImage returns rows of packed 1 bit samples made of blobs, stripes, isolated pixels and long runs.
+func bilevelTestImage(cols, rows int) []byte {
+	stride := (cols + 7) / 8
+	bb := make([]byte, stride*rows)
+	rnd := rand.New(rand.NewSource(int64(cols)))
+
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			var white bool
+			switch {
+			case y%16 == 0:
+				white = x < cols/3
+			case y%16 < 8:
+				dx, dy := x%40-20, y%16-4
+				white = dx*dx+4*dy*dy > 100
+			default:
+				white = rnd.Intn(10) != 0
+			}
+			if white {
+				bb[y*stride+x/8] |= 0x80 >> (x % 8)
+			}
+		}
+	}
+
+	return bb
+}
+
+func TestCCITTEncodeDecode(t *testing.T) {
+	for _, tt := range []struct {
+		k, cols, rows int
+		blackIs1      bool
+		align         bool
+	}{
+		{-1, 1728, 50, false, false},
+		{-1, 101, 37, false, false},
+		{-1, 3000, 20, true, false},
+		{-1, 75, 20, false, true},
+		{0, 1728, 50, false, false},
+		{0, 3000, 20, true, false},
+		{0, 77, 20, false, true},
+		{1, 1728, 50, false, false},
+		{4, 1728, 50, false, false},
+		{4, 3000, 20, true, false},
+		{2, 101, 37, false, true},
+	} {
+		want := bilevelTestImage(tt.cols, tt.rows)
+		if tt.blackIs1 {
+			for i := range want {
+				want[i] = ^want[i]
+			}
+		}
+
+		parms := map[string]int{"K": tt.k, "Columns": tt.cols, "Rows": tt.rows}
+		if tt.blackIs1 {
+			parms["BlackIs1"] = 1
+		}
+		if tt.align {
+			parms["EncodedByteAlign"] = 1
+		}
+
+		f, err := NewFilter(CCITTFax, parms)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		enc, err := f.Encode(bytes.NewReader(want))
+		if err != nil {
+			t.Fatalf("k=%d cols=%d: %v", tt.k, tt.cols, err)
+		}
+
+		dec, err := f.Decode(enc)
+		if err != nil {
+			t.Fatalf("k=%d cols=%d: %v", tt.k, tt.cols, err)
+		}
+
+		got, err := io.ReadAll(dec)
+		if err != nil {
+			t.Fatalf("k=%d cols=%d: %v", tt.k, tt.cols, err)
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("k=%d cols=%d: got %d bytes, want %d", tt.k, tt.cols, len(got), len(want))
+		}
+
+		// Ignore the padding bits of each row.
+		stride := (tt.cols + 7) / 8
+		mask := byte(0xFF << (stride*8 - tt.cols))
+		for i := range got {
+			m := byte(0xFF)
+			if i%stride == stride-1 {
+				m = mask
+			}
+			if got[i]&m != want[i]&m {
+				t.Fatalf("k=%d cols=%d: mismatch at row %d byte %d: %08b != %08b", tt.k, tt.cols, i/stride, i%stride, got[i]&m, want[i]&m)
+			}
+		}
+	}
+}
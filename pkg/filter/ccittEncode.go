@@ -0,0 +1,286 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import "bytes"
+
+// Modified Huffman (Group 3, 1-D), modified READ (Group 3, 2-D) and modified modified READ (Group 4) coding
+// as specified in ITU-T T.4 and T.6.
+
+// Terminating codes for white run lengths 0-63 (Table 2/T.4)
+var ccittWhiteTermCodes = [64]string{
+	"00110101", "000111", "0111", "1000", "1011", "1100", "1110", "1111",
+	"10011", "10100", "00111", "01000", "001000", "000011", "110100", "110101",
+	"101010", "101011", "0100111", "0001100", "0001000", "0010111", "0000011", "0000100",
+	"0101000", "0101011", "0010011", "0100100", "0011000", "00000010", "00000011", "00011010",
+	"00011011", "00010010", "00010011", "00010100", "00010101", "00010110", "00010111", "00101000",
+	"00101001", "00101010", "00101011", "00101100", "00101101", "00000100", "00000101", "00001010",
+	"00001011", "01010010", "01010011", "01010100", "01010101", "00100100", "00100101", "01011000",
+	"01011001", "01011010", "01011011", "01001010", "01001011", "00110010", "00110011", "00110100",
+}
+
+// Terminating codes for black run lengths 0-63 (Table 2/T.4)
+var ccittBlackTermCodes = [64]string{
+	"0000110111", "010", "11", "10", "011", "0011", "0010", "00011",
+	"000101", "000100", "0000100", "0000101", "0000111", "00000100", "00000111", "000011000",
+	"0000010111", "0000011000", "0000001000", "00001100111", "00001101000", "00001101100", "00000110111", "00000101000",
+	"00000010111", "00000011000", "000011001010", "000011001011", "000011001100", "000011001101", "000001101000", "000001101001",
+	"000001101010", "000001101011", "000011010010", "000011010011", "000011010100", "000011010101", "000011010110", "000011010111",
+	"000001101100", "000001101101", "000011011010", "000011011011", "000001010100", "000001010101", "000001010110", "000001010111",
+	"000001100100", "000001100101", "000001010010", "000001010011", "000000100100", "000000110111", "000000111000", "000000100111",
+	"000000101000", "000001011000", "000001011001", "000000101011", "000000101100", "000001011010", "000001100110", "000001100111",
+}
+
+// Make-up codes for white run lengths 64-1728 (Table 3a/T.4)
+var ccittWhiteMakeupCodes = [27]string{
+	"11011", "10010", "010111", "0110111", "00110110", "00110111", "01100100", "01100101",
+	"01101000", "01100111", "011001100", "011001101", "011010010", "011010011", "011010100", "011010101",
+	"011010110", "011010111", "011011000", "011011001", "011011010", "011011011", "010011000", "010011001",
+	"010011010", "011000", "010011011",
+}
+
+// Make-up codes for black run lengths 64-1728 (Table 3a/T.4)
+var ccittBlackMakeupCodes = [27]string{
+	"0000001111", "000011001000", "000011001001", "000001011011", "000000110011", "000000110100", "000000110101", "0000001101100",
+	"0000001101101", "0000001001010", "0000001001011", "0000001001100", "0000001001101", "0000001110010", "0000001110011", "0000001110100",
+	"0000001110101", "0000001110110", "0000001110111", "0000001010010", "0000001010011", "0000001010100", "0000001010101", "0000001011010",
+	"0000001011011", "0000001100100", "0000001100101",
+}
+
+// Make-up codes for run lengths 1792-2560 of either color (Table 3b/T.4)
+var ccittExtMakeupCodes = [13]string{
+	"00000001000", "00000001100", "00000001101", "000000010010", "000000010011", "000000010100", "000000010101",
+	"000000010110", "000000010111", "000000011100", "000000011101", "000000011110", "000000011111",
+}
+
+// Vertical mode codes indexed by a1 - b1 + 3 (Table 4/T.4)
+var ccittVerticalCodes = [7]string{"0000010", "000010", "010", "1", "011", "000011", "0000011"}
+
+const (
+	ccittEOL        = "000000000001"
+	ccittPass       = "0001"
+	ccittHorizontal = "001"
+)
+
+// ccittWriter writes CCITT code words MSB first.
+type ccittWriter struct {
+	buf bytes.Buffer
+	cur byte
+	n   int
+}
+
+func (w *ccittWriter) writeCode(code string) {
+	for i := 0; i < len(code); i++ {
+		w.cur <<= 1
+		if code[i] == '1' {
+			w.cur |= 1
+		}
+		w.n++
+		if w.n == 8 {
+			w.buf.WriteByte(w.cur)
+			w.cur, w.n = 0, 0
+		}
+	}
+}
+
+// alignEOL pads with 0 bits so that a following EOL ends on a byte boundary.
+func (w *ccittWriter) alignEOL() {
+	for (w.n+len(ccittEOL))%8 != 0 {
+		w.writeCode("0")
+	}
+}
+
+// align pads the current byte with 0 bits.
+func (w *ccittWriter) align() {
+	if w.n > 0 {
+		w.buf.WriteByte(w.cur << (8 - w.n))
+		w.cur, w.n = 0, 0
+	}
+}
+
+// writeRun writes the make-up and terminating codes for a run of n pixels.
+func (w *ccittWriter) writeRun(n int, black bool) {
+	term, makeup := &ccittWhiteTermCodes, &ccittWhiteMakeupCodes
+	if black {
+		term, makeup = &ccittBlackTermCodes, &ccittBlackMakeupCodes
+	}
+
+	for ; n > 2560; n -= 2560 {
+		w.writeCode(ccittExtMakeupCodes[12])
+	}
+
+	if m := n / 64; m > 27 {
+		w.writeCode(ccittExtMakeupCodes[m-28])
+	} else if m > 0 {
+		w.writeCode(makeup[m-1])
+	}
+
+	w.writeCode(term[n%64])
+}
+
+// ccittChanges returns the positions of the changing elements of row followed by sentinels at cols.
+// The imaginary element preceding the row is white.
+func ccittChanges(row []byte, cols int, blackIs1 bool, cc []int) []int {
+	cc = cc[:0]
+	black := false
+	for x := 0; x < cols; x++ {
+		bit := row[x>>3]&(0x80>>(x&7)) != 0
+		if bit == blackIs1 != black {
+			cc = append(cc, x)
+			black = !black
+		}
+	}
+	return append(cc, cols, cols, cols)
+}
+
+// encode1D codes a row with changing elements cur using modified Huffman coding.
+func (w *ccittWriter) encode1D(cur []int, cols int) {
+	a0, black := 0, false
+	for _, a1 := range cur {
+		if a1 == cols {
+			break
+		}
+		w.writeRun(a1-a0, black)
+		a0, black = a1, !black
+	}
+	w.writeRun(cols-a0, black)
+}
+
+// encode2D codes a row with changing elements cur relative to the reference row with changing elements ref
+// using modified READ coding (T.4 4.2.1.3).
+func (w *ccittWriter) encode2D(cur, ref []int, cols int) {
+	a0, black := -1, false
+	i, j := 0, 0
+
+	for a0 < cols {
+		for cur[i] <= a0 {
+			i++
+		}
+		a1 := cur[i]
+
+		// b1 is the first changing element on the reference line to the right of a0 and of opposite color.
+		// Changing elements alternate starting with a change to black.
+		for ref[j] <= a0 {
+			j++
+		}
+		k := j
+		if k&1 == 1 != black {
+			k++
+		}
+		b1, b2 := ref[k], ref[k+1]
+
+		switch {
+		case b2 < a1:
+			w.writeCode(ccittPass)
+			a0 = b2
+
+		case a1-b1 >= -3 && a1-b1 <= 3:
+			w.writeCode(ccittVerticalCodes[a1-b1+3])
+			a0, black = a1, !black
+
+		default:
+			a2 := cur[i+1]
+			w.writeCode(ccittHorizontal)
+			w.writeRun(a1-max(a0, 0), black)
+			w.writeRun(a2-a1, !black)
+			a0 = a2
+		}
+	}
+}
+
+// encodeCCITTMixed encodes rows of packed 1 bit samples using Group 3 2-D coding.
+// Each row is preceded by an EOL code and a tag bit selecting 1-D (1) or 2-D (0) coding.
+// Every k-th row is coded 1-D, see T.4 4.2.1.
+func (w *ccittWriter) encodeCCITTMixed(bb []byte, k, cols, rows int, blackIs1, align, eob bool) {
+	stride := (cols + 7) / 8
+
+	ref := []int{cols, cols, cols}
+	var cur []int
+
+	for y := 0; y < rows; y++ {
+		if align {
+			w.alignEOL()
+		}
+		w.writeCode(ccittEOL)
+		cur = ccittChanges(bb[y*stride:(y+1)*stride], cols, blackIs1, cur)
+		if y%k == 0 {
+			w.writeCode("1")
+			w.encode1D(cur, cols)
+		} else {
+			w.writeCode("0")
+			w.encode2D(cur, ref, cols)
+		}
+		ref, cur = cur, ref
+	}
+
+	if eob {
+		// RTC
+		for i := 0; i < 6; i++ {
+			w.writeCode(ccittEOL + "1")
+		}
+	}
+}
+
+// encodeCCITT encodes rows of packed 1 bit samples.
+// k < 0 selects Group 4 coding, k = 0 Group 3 1-D coding with EOL codes preceding each row
+// and k > 0 Group 3 2-D coding with at most k-1 consecutive 2-D coded rows.
+func encodeCCITT(bb []byte, k, cols, rows int, blackIs1, align, eob bool) *bytes.Buffer {
+	w := &ccittWriter{}
+	stride := (cols + 7) / 8
+
+	if k > 0 {
+		w.encodeCCITTMixed(bb, k, cols, rows, blackIs1, align, eob)
+		w.align()
+		return &w.buf
+	}
+
+	if k == 0 {
+		w.writeCode(ccittEOL)
+	}
+
+	ref := []int{cols, cols, cols}
+	var cur []int
+
+	for y := 0; y < rows; y++ {
+		if align {
+			w.align()
+		}
+		cur = ccittChanges(bb[y*stride:(y+1)*stride], cols, blackIs1, cur)
+		if k < 0 {
+			w.encode2D(cur, ref, cols)
+			ref, cur = cur, ref
+			continue
+		}
+		w.encode1D(cur, cols)
+		w.writeCode(ccittEOL)
+	}
+
+	if eob {
+		// RTC (Group 3) resp. EOFB (Group 4)
+		n := 5
+		if k < 0 {
+			n = 2
+		}
+		for i := 0; i < n; i++ {
+			w.writeCode(ccittEOL)
+		}
+	}
+
+	w.align()
+
+	return &w.buf
+}
@@ -21,7 +21,6 @@ import (
 	"bytes"
 	"io"
 
-	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pkg/errors"
 )
 
@@ -77,15 +76,10 @@ func NewFilter(filterName string, parms map[string]int) (filter Filter, err erro
 		filter = dctDecode{baseFilter{parms}}
 
 	case JBIG2:
-		// Unsupported
-		fallthrough
+		filter = jbig2Decode{baseFilter{parms}, nil}
 
 	case JPX:
-		// Unsupported
-		if log.InfoEnabled() {
-			log.Info.Printf("Filter not supported: <%s>", filterName)
-		}
-		err = ErrUnsupportedFilter
+		filter = jpxDecode{baseFilter{parms}}
 
 	default:
 		err = errors.Errorf("Invalid filter: <%s>", filterName)
@@ -0,0 +1,268 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+// MQ arithmetic decoding as specified in ITU-T T.88 Annex E.
+
+type qeEntry struct {
+	qe         uint32
+	nmps, nlps uint8
+	switchMPS  bool
+}
+
+// Table E.1 - Qe values and probability estimation.
+var qeTable = [47]qeEntry{
+	{0x5601, 1, 1, true},
+	{0x3401, 2, 6, false},
+	{0x1801, 3, 9, false},
+	{0x0AC1, 4, 12, false},
+	{0x0521, 5, 29, false},
+	{0x0221, 38, 33, false},
+	{0x5601, 7, 6, true},
+	{0x5401, 8, 14, false},
+	{0x4801, 9, 14, false},
+	{0x3801, 10, 14, false},
+	{0x3001, 11, 17, false},
+	{0x2401, 12, 18, false},
+	{0x1C01, 13, 20, false},
+	{0x1601, 29, 21, false},
+	{0x5601, 15, 14, true},
+	{0x5401, 16, 14, false},
+	{0x5101, 17, 15, false},
+	{0x4801, 18, 16, false},
+	{0x3801, 19, 17, false},
+	{0x3401, 20, 18, false},
+	{0x3001, 21, 19, false},
+	{0x2801, 22, 19, false},
+	{0x2401, 23, 20, false},
+	{0x2201, 24, 21, false},
+	{0x1C01, 25, 22, false},
+	{0x1801, 26, 23, false},
+	{0x1601, 27, 24, false},
+	{0x1401, 28, 25, false},
+	{0x1201, 29, 26, false},
+	{0x1101, 30, 27, false},
+	{0x0AC1, 31, 28, false},
+	{0x09C1, 32, 29, false},
+	{0x08A1, 33, 30, false},
+	{0x0521, 34, 31, false},
+	{0x0441, 35, 32, false},
+	{0x02A1, 36, 33, false},
+	{0x0221, 37, 34, false},
+	{0x0141, 38, 35, false},
+	{0x0111, 39, 36, false},
+	{0x0085, 40, 37, false},
+	{0x0049, 41, 38, false},
+	{0x0025, 42, 39, false},
+	{0x0015, 43, 40, false},
+	{0x0009, 44, 41, false},
+	{0x0005, 45, 42, false},
+	{0x0001, 45, 43, false},
+	{0x5601, 46, 46, false},
+}
+
+// arithContexts holds the adaptive probability state for a set of contexts.
+// Each entry packs the Qe table index and the MPS into (index<<1 | mps).
+type arithContexts []uint8
+
+func newArithContexts(n int) arithContexts {
+	return make(arithContexts, n)
+}
+
+type arithDecoder struct {
+	data  []byte
+	bp    int
+	chigh uint32
+	clow  uint32
+	a     uint32
+	ct    int
+}
+
+func newArithDecoder(data []byte) *arithDecoder {
+	d := &arithDecoder{data: data}
+	d.chigh = uint32(d.byteAt(0))
+	d.byteIn()
+	d.chigh = ((d.chigh << 7) & 0xFFFF) | ((d.clow >> 9) & 0x7F)
+	d.clow = (d.clow << 7) & 0xFFFF
+	d.ct -= 7
+	d.a = 0x8000
+	return d
+}
+
+func (d *arithDecoder) byteAt(i int) byte {
+	if i < len(d.data) {
+		return d.data[i]
+	}
+	// Past the end of data the decoder is fed with 0xFF bytes.
+	return 0xFF
+}
+
+func (d *arithDecoder) byteIn() {
+	if d.byteAt(d.bp) == 0xFF {
+		if d.byteAt(d.bp+1) > 0x8F {
+			d.clow += 0xFF00
+			d.ct = 8
+		} else {
+			d.bp++
+			d.clow += uint32(d.byteAt(d.bp)) << 9
+			d.ct = 7
+		}
+	} else {
+		d.bp++
+		d.clow += uint32(d.byteAt(d.bp)) << 8
+		d.ct = 8
+	}
+	if d.clow > 0xFFFF {
+		d.chigh += d.clow >> 16
+		d.clow &= 0xFFFF
+	}
+}
+
+// decodeBit decodes a single bit using context cx of cc.
+func (d *arithDecoder) decodeBit(cc arithContexts, cx int) int {
+	i, mps := cc[cx]>>1, int(cc[cx]&1)
+	e := &qeTable[i]
+	qe := e.qe
+
+	var bit int
+	a := d.a - qe
+
+	if d.chigh < qe {
+		// LPS exchange
+		if a < qe {
+			a = qe
+			bit = mps
+			i = e.nmps
+		} else {
+			a = qe
+			bit = 1 ^ mps
+			if e.switchMPS {
+				mps = bit
+			}
+			i = e.nlps
+		}
+	} else {
+		d.chigh -= qe
+		if a&0x8000 != 0 {
+			d.a = a
+			return mps
+		}
+		// MPS exchange
+		if a < qe {
+			bit = 1 ^ mps
+			if e.switchMPS {
+				mps = bit
+			}
+			i = e.nlps
+		} else {
+			bit = mps
+			i = e.nmps
+		}
+	}
+
+	for {
+		if d.ct == 0 {
+			d.byteIn()
+		}
+		a <<= 1
+		d.chigh = ((d.chigh << 1) & 0xFFFF) | ((d.clow >> 15) & 1)
+		d.clow = (d.clow << 1) & 0xFFFF
+		d.ct--
+		if a&0x8000 != 0 {
+			break
+		}
+	}
+
+	d.a = a
+	cc[cx] = i<<1 | uint8(mps)
+	return bit
+}
+
+// decodeInt implements the integer arithmetic decoding procedure (Annex A.2).
+// ok is false for the out-of-band value OOB.
+func (d *arithDecoder) decodeInt(cc arithContexts) (v int, ok bool) {
+	prev := 1
+
+	bits := func(n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			b := d.decodeBit(cc, prev)
+			if prev < 256 {
+				prev = prev<<1 | b
+			} else {
+				prev = ((prev<<1|b)&511 | 256)
+			}
+			v = v<<1 | b
+		}
+		return v
+	}
+
+	s := bits(1)
+
+	switch {
+	case bits(1) == 0:
+		v = bits(2)
+	case bits(1) == 0:
+		v = bits(4) + 4
+	case bits(1) == 0:
+		v = bits(6) + 20
+	case bits(1) == 0:
+		v = bits(8) + 84
+	case bits(1) == 0:
+		v = bits(12) + 340
+	default:
+		v = bits(32) + 4436
+	}
+
+	if s == 1 {
+		if v == 0 {
+			return 0, false
+		}
+		v = -v
+	}
+
+	return v, true
+}
+
+// decodeIAID implements the IAID decoding procedure (Annex A.3).
+func (d *arithDecoder) decodeIAID(cc arithContexts, codeLen int) int {
+	prev := 1
+	for i := 0; i < codeLen; i++ {
+		prev = prev<<1 | d.decodeBit(cc, prev)
+	}
+	return prev - (1 << codeLen)
+}
+
+// intContexts bundles the contexts of the integer arithmetic decoding procedures.
+type intContexts struct {
+	iadh, iadw, iaex, iaai, iardx, iardy arithContexts
+	iadt, iafs, iads, iait, iari         arithContexts
+	iardw, iardh                         arithContexts
+	iaid                                 arithContexts
+}
+
+func newIntContexts(symCodeLen int) *intContexts {
+	ic := &intContexts{}
+	for _, p := range []*arithContexts{
+		&ic.iadh, &ic.iadw, &ic.iaex, &ic.iaai, &ic.iardx, &ic.iardy,
+		&ic.iadt, &ic.iafs, &ic.iads, &ic.iait, &ic.iari, &ic.iardw, &ic.iardh,
+	} {
+		*p = newArithContexts(512)
+	}
+	ic.iaid = newArithContexts(1 << (symCodeLen + 1))
+	return ic
+}
@@ -0,0 +1,547 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pkg/errors"
+)
+
+type jbig2Decode struct {
+	baseFilter
+	globals []byte
+}
+
+// NewJBIG2Filter returns a JBIG2Decode filter using the decoded content of the optional JBIG2Globals stream.
+func NewJBIG2Filter(parms map[string]int, globals []byte) Filter {
+	return jbig2Decode{baseFilter{parms}, globals}
+}
+
+// Encode implements encoding for a JBIG2Decode filter.
+func (f jbig2Decode) Encode(r io.Reader) (io.Reader, error) {
+	// Not supported.
+	return nil, nil
+}
+
+// Decode implements decoding for a JBIG2Decode filter.
+func (f jbig2Decode) Decode(r io.Reader) (io.Reader, error) {
+	return f.DecodeLength(r, -1)
+}
+
+// DecodeLength decodes the embedded JBIG2 stream into a 1 bit per pixel image where 0 means black.
+func (f jbig2Decode) DecodeLength(r io.Reader, maxLen int64) (io.Reader, error) {
+	if log.TraceEnabled() {
+		log.Trace.Println("DecodeJBIG2 begin")
+	}
+
+	bb, err := getReaderBytes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &jbig2Decoder{segments: map[uint32]*jbig2Segment{}}
+
+	if len(f.globals) > 0 {
+		if err := d.decodeSegments(f.globals); err != nil {
+			return nil, errors.Wrap(err, "pdfcpu: jbig2: globals")
+		}
+	}
+
+	if err := d.decodeSegments(bb); err != nil {
+		return nil, err
+	}
+
+	if d.page == nil {
+		return nil, errors.New("pdfcpu: jbig2: missing page information")
+	}
+
+	// PDF expects 0 for black pixels.
+	p := d.page.bm
+	data := make([]byte, len(p.data))
+	for i, b := range p.data {
+		data[i] = ^b
+	}
+
+	if log.TraceEnabled() {
+		log.Trace.Printf("DecodeJBIG2: decoded %dx%d page.\n", p.w, p.h)
+	}
+
+	return bytes.NewBuffer(data), nil
+}
+
+// Segment types (7.3).
+const (
+	jbig2SymbolDict                = 0
+	jbig2IntermediateText          = 4
+	jbig2ImmediateText             = 6
+	jbig2ImmediateLosslessText     = 7
+	jbig2PatternDict               = 16
+	jbig2IntermediateHalftone      = 20
+	jbig2ImmediateHalftone         = 22
+	jbig2ImmediateLosslessHalftone = 23
+	jbig2IntermediateGeneric       = 36
+	jbig2ImmediateGeneric          = 38
+	jbig2ImmediateLosslessGeneric  = 39
+	jbig2IntermediateRefinement    = 40
+	jbig2ImmediateRefinement       = 42
+	jbig2ImmediateLosslessRefine   = 43
+	jbig2PageInfo                  = 48
+	jbig2EndOfPage                 = 49
+	jbig2EndOfStripe               = 50
+	jbig2EndOfFile                 = 51
+	jbig2Profiles                  = 52
+	jbig2Tables                    = 53
+	jbig2Extension                 = 62
+)
+
+const jbig2UnknownLength = 0xFFFFFFFF
+
+type jbig2Segment struct {
+	nr       uint32
+	typ      int
+	refs     []uint32
+	pageNr   uint32
+	data     []byte
+	symbols  *symbolDict    // result of a symbol dictionary segment
+	patterns []*jbig2Bitmap // result of a pattern dictionary segment
+	table    *huffTable     // result of a tables segment
+	region   *jbig2Bitmap   // result of an intermediate region segment
+}
+
+type jbig2Page struct {
+	bm            *jbig2Bitmap
+	defPixel      int
+	unknownHeight bool
+}
+
+type jbig2Decoder struct {
+	segments map[uint32]*jbig2Segment
+	page     *jbig2Page
+}
+
+// regionInfo represents the region segment information field (7.4.1).
+type regionInfo struct {
+	w, h, x, y int
+	op         int
+}
+
+const regionInfoLen = 17
+
+func parseRegionInfo(data []byte) (regionInfo, error) {
+	if len(data) < regionInfoLen {
+		return regionInfo{}, errJBIG2EOD
+	}
+	return regionInfo{
+		w:  int(binary.BigEndian.Uint32(data)),
+		h:  int(binary.BigEndian.Uint32(data[4:])),
+		x:  int(int32(binary.BigEndian.Uint32(data[8:]))),
+		y:  int(int32(binary.BigEndian.Uint32(data[12:]))),
+		op: int(data[16] & 7),
+	}, nil
+}
+
+// parseSegmentHeader parses the segment header at the start of data (7.2) and returns the header length.
+func parseSegmentHeader(data []byte) (*jbig2Segment, uint32, int, error) {
+	if len(data) < 6 {
+		return nil, 0, 0, errJBIG2EOD
+	}
+
+	s := &jbig2Segment{nr: binary.BigEndian.Uint32(data)}
+	flags := data[4]
+	s.typ = int(flags & 0x3F)
+	pageAssoc4 := flags&0x40 != 0
+	i := 5
+
+	refCount := int(data[i] >> 5)
+	if refCount == 7 {
+		if len(data) < i+4 {
+			return nil, 0, 0, errJBIG2EOD
+		}
+		refCount = int(binary.BigEndian.Uint32(data[i:]) & 0x1FFFFFFF)
+		i += 4 + (refCount+8)/8
+	} else {
+		i++
+	}
+
+	refSize := 4
+	if s.nr <= 256 {
+		refSize = 1
+	} else if s.nr <= 65536 {
+		refSize = 2
+	}
+
+	if refCount > len(data) || len(data) < i+refCount*refSize {
+		return nil, 0, 0, errJBIG2EOD
+	}
+
+	for j := 0; j < refCount; j++ {
+		var nr uint32
+		switch refSize {
+		case 1:
+			nr = uint32(data[i])
+		case 2:
+			nr = uint32(binary.BigEndian.Uint16(data[i:]))
+		default:
+			nr = binary.BigEndian.Uint32(data[i:])
+		}
+		s.refs = append(s.refs, nr)
+		i += refSize
+	}
+
+	if pageAssoc4 {
+		if len(data) < i+4 {
+			return nil, 0, 0, errJBIG2EOD
+		}
+		s.pageNr = binary.BigEndian.Uint32(data[i:])
+		i += 4
+	} else {
+		if len(data) < i+1 {
+			return nil, 0, 0, errJBIG2EOD
+		}
+		s.pageNr = uint32(data[i])
+		i++
+	}
+
+	if len(data) < i+4 {
+		return nil, 0, 0, errJBIG2EOD
+	}
+	dataLen := binary.BigEndian.Uint32(data[i:])
+	i += 4
+
+	return s, dataLen, i, nil
+}
+
+// unknownSegmentLength determines the data length of an immediate generic region segment
+// whose length is not given in the segment header (7.2.7).
+func unknownSegmentLength(data []byte) (int, error) {
+	ri, err := parseRegionInfo(data)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) < regionInfoLen+1 {
+		return 0, errJBIG2EOD
+	}
+
+	pattern := make([]byte, 6)
+	if data[regionInfoLen]&1 == 0 {
+		pattern[0], pattern[1] = 0xFF, 0xAC
+	}
+	binary.BigEndian.PutUint32(pattern[2:], uint32(ri.h))
+
+	i := bytes.Index(data[regionInfoLen:], pattern)
+	if i < 0 {
+		return 0, errors.New("pdfcpu: jbig2: end of generic region segment not found")
+	}
+
+	return regionInfoLen + i + len(pattern), nil
+}
+
+// decodeSegments decodes all segments of an embedded JBIG2 stream (7.4).
+func (d *jbig2Decoder) decodeSegments(data []byte) error {
+	for len(data) > 0 {
+		s, dataLen, n, err := parseSegmentHeader(data)
+		if err != nil {
+			return err
+		}
+		data = data[n:]
+
+		l := int(dataLen)
+		if dataLen == jbig2UnknownLength {
+			if s.typ != jbig2ImmediateGeneric {
+				return errors.New("pdfcpu: jbig2: unknown segment length")
+			}
+			if l, err = unknownSegmentLength(data); err != nil {
+				return err
+			}
+		}
+		if l > len(data) {
+			return errJBIG2EOD
+		}
+		s.data, data = data[:l], data[l:]
+
+		d.segments[s.nr] = s
+
+		if err := d.decodeSegment(s); err != nil {
+			return err
+		}
+
+		if s.typ == jbig2EndOfFile {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (d *jbig2Decoder) decodeSegment(s *jbig2Segment) error {
+	var err error
+
+	switch s.typ {
+
+	case jbig2SymbolDict:
+		err = d.decodeSymbolDictSegment(s)
+
+	case jbig2IntermediateText, jbig2ImmediateText, jbig2ImmediateLosslessText:
+		err = d.decodeTextRegionSegment(s)
+
+	case jbig2PatternDict:
+		err = d.decodePatternDictSegment(s)
+
+	case jbig2IntermediateHalftone, jbig2ImmediateHalftone, jbig2ImmediateLosslessHalftone:
+		err = d.decodeHalftoneRegionSegment(s)
+
+	case jbig2IntermediateGeneric, jbig2ImmediateGeneric, jbig2ImmediateLosslessGeneric:
+		err = d.decodeGenericRegionSegment(s)
+
+	case jbig2IntermediateRefinement, jbig2ImmediateRefinement, jbig2ImmediateLosslessRefine:
+		err = d.decodeRefinementRegionSegment(s)
+
+	case jbig2PageInfo:
+		err = d.decodePageInfoSegment(s)
+
+	case jbig2EndOfStripe:
+		err = d.decodeEndOfStripeSegment(s)
+
+	case jbig2Tables:
+		s.table, err = parseHuffTable(s.data)
+
+	case jbig2EndOfPage, jbig2EndOfFile, jbig2Profiles, jbig2Extension:
+		// Nothing to do.
+
+	default:
+		if log.DebugEnabled() {
+			log.Debug.Printf("DecodeJBIG2: ignoring segment type %d\n", s.typ)
+		}
+	}
+
+	return err
+}
+
+func (d *jbig2Decoder) decodePageInfoSegment(s *jbig2Segment) error {
+	if d.page != nil {
+		// Only the first page of an embedded stream is relevant.
+		return nil
+	}
+
+	if len(s.data) < 19 {
+		return errJBIG2EOD
+	}
+
+	w := int(binary.BigEndian.Uint32(s.data))
+	h := binary.BigEndian.Uint32(s.data[4:])
+	flags := s.data[16]
+
+	p := &jbig2Page{defPixel: int(flags>>2) & 1}
+
+	if h == 0xFFFFFFFF {
+		p.unknownHeight = true
+		h = 0
+	}
+
+	bm, err := newJBIG2Bitmap(w, int(h))
+	if err != nil {
+		return err
+	}
+	bm.fill(p.defPixel)
+	p.bm = bm
+
+	d.page = p
+
+	return nil
+}
+
+func (d *jbig2Decoder) decodeEndOfStripeSegment(s *jbig2Segment) error {
+	if d.page == nil || !d.page.unknownHeight {
+		return nil
+	}
+	if len(s.data) < 4 {
+		return errJBIG2EOD
+	}
+	return d.page.grow(int(binary.BigEndian.Uint32(s.data)) + 1)
+}
+
+// grow extends a page of initially unknown height to h rows.
+func (p *jbig2Page) grow(h int) error {
+	if !p.unknownHeight || h <= p.bm.h {
+		return nil
+	}
+	bm, err := newJBIG2Bitmap(p.bm.w, h)
+	if err != nil {
+		return err
+	}
+	bm.fill(p.defPixel)
+	copy(bm.data, p.bm.data)
+	p.bm = bm
+	return nil
+}
+
+// putRegion stores the result of an intermediate region segment or draws it onto the page.
+func (d *jbig2Decoder) putRegion(s *jbig2Segment, ri regionInfo, bm *jbig2Bitmap) error {
+	switch s.typ {
+	case jbig2IntermediateText, jbig2IntermediateHalftone, jbig2IntermediateGeneric, jbig2IntermediateRefinement:
+		s.region = bm
+		return nil
+	}
+
+	if d.page == nil {
+		return errors.New("pdfcpu: jbig2: region segment without page")
+	}
+
+	if err := d.page.grow(ri.y + bm.h); err != nil {
+		return err
+	}
+
+	d.page.bm.compose(bm, ri.x, ri.y, ri.op)
+
+	return nil
+}
+
+// referredSegments returns the segments of type typ referred to by s.
+func (d *jbig2Decoder) referredSegments(s *jbig2Segment, typ int) []*jbig2Segment {
+	var ss []*jbig2Segment
+	for _, nr := range s.refs {
+		if s1, ok := d.segments[nr]; ok && s1.typ == typ {
+			ss = append(ss, s1)
+		}
+	}
+	return ss
+}
+
+// referredTables returns the custom Huffman tables referred to by s.
+func (d *jbig2Decoder) referredTables(s *jbig2Segment) []*huffTable {
+	var tt []*huffTable
+	for _, s1 := range d.referredSegments(s, jbig2Tables) {
+		tt = append(tt, s1.table)
+	}
+	return tt
+}
+
+// readAT reads n adaptive template pixels.
+func readAT(data []byte, n int) ([]jbig2Point, error) {
+	if len(data) < 2*n {
+		return nil, errJBIG2EOD
+	}
+	at := make([]jbig2Point, n)
+	for i := range at {
+		at[i] = jbig2Point{int(int8(data[2*i])), int(int8(data[2*i+1]))}
+	}
+	return at, nil
+}
+
+func (d *jbig2Decoder) decodeGenericRegionSegment(s *jbig2Segment) error {
+	ri, err := parseRegionInfo(s.data)
+	if err != nil {
+		return err
+	}
+
+	data := s.data[regionInfoLen:]
+	if len(data) < 1 {
+		return errJBIG2EOD
+	}
+
+	flags := data[0]
+	mmr := flags&1 == 1
+	template := int(flags>>1) & 3
+	tpgdon := flags&8 != 0
+	data = data[1:]
+
+	var bm *jbig2Bitmap
+
+	if mmr {
+		if bm, _, err = decodeMMR(data, ri.w, ri.h); err != nil {
+			return err
+		}
+		return d.putRegion(s, ri, bm)
+	}
+
+	n := 1
+	if template == 0 {
+		n = 4
+	}
+	at, err := readAT(data, n)
+	if err != nil {
+		return err
+	}
+	data = data[2*n:]
+
+	ad := newArithDecoder(data)
+	cc := newArithContexts(genericContextSize(template))
+
+	bm, err = decodeGenericRegion(ad, cc, genericParams{w: ri.w, h: ri.h, template: template, tpgdon: tpgdon, at: at})
+	if err != nil {
+		return err
+	}
+
+	return d.putRegion(s, ri, bm)
+}
+
+func (d *jbig2Decoder) decodeRefinementRegionSegment(s *jbig2Segment) error {
+	ri, err := parseRegionInfo(s.data)
+	if err != nil {
+		return err
+	}
+
+	data := s.data[regionInfoLen:]
+	if len(data) < 1 {
+		return errJBIG2EOD
+	}
+
+	flags := data[0]
+	template := int(flags & 1)
+	tpgron := flags&2 != 0
+	data = data[1:]
+
+	var at []jbig2Point
+	if template == 0 {
+		if at, err = readAT(data, 2); err != nil {
+			return err
+		}
+		data = data[4:]
+	}
+
+	// The reference is either an intermediate region result or the page area covered by this region.
+	var ref *jbig2Bitmap
+	for _, nr := range s.refs {
+		if s1, ok := d.segments[nr]; ok && s1.region != nil {
+			ref = s1.region
+			break
+		}
+	}
+
+	if ref == nil {
+		if d.page == nil {
+			return errors.New("pdfcpu: jbig2: refinement region without reference")
+		}
+		if ref, err = d.page.bm.crop(ri.x, ri.y, ri.w, ri.h); err != nil {
+			return err
+		}
+	}
+
+	ad := newArithDecoder(data)
+	cc := newArithContexts(refinementContextSize(template))
+
+	bm, err := decodeRefinementRegion(ad, cc, refinementParams{w: ri.w, h: ri.h, template: template, ref: ref, tpgron: tpgron, at: at})
+	if err != nil {
+		return err
+	}
+
+	return d.putRegion(s, ri, bm)
+}
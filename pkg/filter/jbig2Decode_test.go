@@ -0,0 +1,180 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+)
+
+func hexBytes(t *testing.T, s string) []byte {
+	t.Helper()
+	bb, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bb
+}
+
+// Test sequence for the arithmetic decoder taken from ITU-T T.88 Annex H.2.
+func TestJBIG2ArithDecoder(t *testing.T) {
+	want := hexBytes(t, "00 02 00 51 00 00 00 C0 03 52 87 2A AA AA AA AA 82 C0 20 00 FC D7 9E F6 BF 7F ED 90 4F 46 A3 BF")
+	enc := hexBytes(t, "84 C7 3B FC E1 A1 43 04 02 20 00 00 41 0D BB 86 F4 31 7F FF 88 FF 37 47 1A DB 6A DF FF AC")
+
+	d := newArithDecoder(enc)
+	cc := newArithContexts(1)
+
+	got := make([]byte, len(want))
+	for i := range got {
+		for j := 0; j < 8; j++ {
+			got[i] = got[i]<<1 | byte(d.decodeBit(cc, 0))
+		}
+	}
+
+	compare(t, got, want)
+}
+
+type bitWriter struct {
+	bb  []byte
+	acc byte
+	n   int
+}
+
+func (w *bitWriter) writeBits(v uint32, n int) {
+	for i := n - 1; i >= 0; i-- {
+		w.acc = w.acc<<1 | byte(v>>uint(i)&1)
+		w.n++
+		if w.n == 8 {
+			w.bb = append(w.bb, w.acc)
+			w.acc, w.n = 0, 0
+		}
+	}
+}
+
+func (w *bitWriter) align() {
+	if w.n > 0 {
+		w.writeBits(0, 8-w.n)
+	}
+}
+
+// writeHuff writes v (or OOB) using the Huffman table t.
+func (w *bitWriter) writeHuff(t *testing.T, ht *huffTable, v int, oob bool) {
+	t.Helper()
+	for code, i := range ht.codes {
+		l := ht.lines[i]
+		switch {
+		case oob && l.kind == huffOOB:
+			w.writeBits(code.code, code.len)
+			return
+		case !oob && l.kind == huffNormal && v >= l.rangeLow && v < l.rangeLow+1<<l.rangeLen:
+			w.writeBits(code.code, code.len)
+			w.writeBits(uint32(v-l.rangeLow), l.rangeLen)
+			return
+		}
+	}
+	t.Fatalf("no Huffman code for %d", v)
+}
+
+func segment(nr uint32, typ int, refs []byte, pageNr byte, data []byte) []byte {
+	bb := binary.BigEndian.AppendUint32(nil, nr)
+	bb = append(bb, byte(typ), byte(len(refs)<<5))
+	bb = append(bb, refs...)
+	bb = append(bb, pageNr)
+	bb = binary.BigEndian.AppendUint32(bb, uint32(len(data)))
+	return append(bb, data...)
+}
+
+func TestJBIG2DecodeSymbolsWithGlobals(t *testing.T) {
+	// Huffman coded symbol dictionary with an uncompressed collective bitmap holding two 3 pixel high symbols:
+	//
+	//	##   # #
+	//	 #    #
+	//	##   # #
+	w := &bitWriter{}
+	w.writeHuff(t, standardHuffTable(4), 3, false) // HCDH
+	w.writeHuff(t, standardHuffTable(2), 2, false) // DW
+	w.writeHuff(t, standardHuffTable(2), 1, false) // DW
+	w.writeHuff(t, standardHuffTable(2), 0, true)  // OOB
+	w.writeHuff(t, standardHuffTable(1), 0, false) // BMSIZE
+	w.align()
+	w.bb = append(w.bb, 0xE8, 0x50, 0xE8)
+	w.writeHuff(t, standardHuffTable(1), 0, false) // skip no symbol
+	w.writeHuff(t, standardHuffTable(1), 2, false) // export 2 symbols
+	w.align()
+
+	sd := []byte{0x00, 0x01}
+	sd = binary.BigEndian.AppendUint32(sd, 2)
+	sd = binary.BigEndian.AppendUint32(sd, 2)
+	sd = append(sd, w.bb...)
+
+	globals := segment(1, jbig2SymbolDict, nil, 0, sd)
+
+	// Huffman coded text region placing both symbols on the first strip.
+	w = &bitWriter{}
+	w.writeBits(0, 4) // run code 0
+	w.writeBits(1, 4) // run code 1
+	w.writeBits(0, 33*4)
+	w.writeBits(0, 2) // both symbol ID codes are 1 bit long
+	w.align()
+	w.writeHuff(t, standardHuffTable(11), 1, false) // initial STRIPT
+	w.writeHuff(t, standardHuffTable(11), 1, false) // DT
+	w.writeHuff(t, standardHuffTable(6), 1, false)  // DFS
+	w.writeBits(0, 1)                               // ID
+	w.writeHuff(t, standardHuffTable(8), 1, false)  // IDS
+	w.writeBits(1, 1)                               // ID
+	w.writeHuff(t, standardHuffTable(8), 0, true)   // OOB
+	w.align()
+
+	tr := binary.BigEndian.AppendUint32(nil, 8)
+	tr = binary.BigEndian.AppendUint32(tr, 4)
+	tr = binary.BigEndian.AppendUint32(tr, 0)
+	tr = binary.BigEndian.AppendUint32(tr, 0)
+	tr = append(tr, jbig2OpOr)
+	tr = append(tr, 0x00, 0x11, 0x00, 0x00)
+	tr = binary.BigEndian.AppendUint32(tr, 2)
+	tr = append(tr, w.bb...)
+
+	pi := binary.BigEndian.AppendUint32(nil, 8)
+	pi = binary.BigEndian.AppendUint32(pi, 4)
+	pi = append(pi, make([]byte, 11)...)
+
+	var bb []byte
+	bb = append(bb, segment(2, jbig2PageInfo, nil, 1, pi)...)
+	bb = append(bb, segment(3, jbig2ImmediateText, []byte{1}, 1, tr)...)
+	bb = append(bb, segment(4, jbig2EndOfPage, nil, 1, nil)...)
+
+	r, err := NewJBIG2Filter(nil, globals).Decode(bytes.NewReader(bb))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compare(t, got, []byte{^byte(0x74), ^byte(0x28), ^byte(0x74), 0xFF})
+
+	// Without globals the text region lacks its symbols.
+	if _, err := NewJBIG2Filter(nil, nil).Decode(bytes.NewReader(bb)); err == nil {
+		t.Fatal("expected error for missing symbols")
+	}
+}
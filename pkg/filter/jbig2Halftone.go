@@ -0,0 +1,215 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// decodePatternDictSegment decodes a pattern dictionary segment (7.4.4, 6.7).
+func (d *jbig2Decoder) decodePatternDictSegment(s *jbig2Segment) error {
+	data := s.data
+	if len(data) < 7 {
+		return errJBIG2EOD
+	}
+
+	flags := data[0]
+	mmr := flags&1 == 1
+	template := int(flags>>1) & 3
+	pw, ph := int(data[1]), int(data[2])
+	grayMax := int(binary.BigEndian.Uint32(data[3:]))
+	data = data[7:]
+
+	if pw == 0 || ph == 0 || grayMax >= maxJBIG2Pixels/pw {
+		return errors.New("pdfcpu: jbig2: invalid pattern dictionary")
+	}
+
+	w := (grayMax + 1) * pw
+
+	var (
+		coll *jbig2Bitmap
+		err  error
+	)
+
+	if mmr {
+		coll, _, err = decodeMMR(data, w, ph)
+	} else {
+		at := []jbig2Point{{-pw, 0}, {-3, -1}, {2, -2}, {-2, -2}}
+		ad := newArithDecoder(data)
+		cc := newArithContexts(genericContextSize(template))
+		coll, err = decodeGenericRegion(ad, cc, genericParams{w: w, h: ph, template: template, at: at})
+	}
+	if err != nil {
+		return err
+	}
+
+	s.patterns = make([]*jbig2Bitmap, grayMax+1)
+	for i := range s.patterns {
+		if s.patterns[i], err = coll.crop(i*pw, 0, pw, ph); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type halftoneParams struct {
+	w, h     int
+	mmr      bool
+	template int
+	skip     bool
+	op       int
+	defPixel int
+	gw, gh   int
+	gx, gy   int
+	rx, ry   int
+	patterns []*jbig2Bitmap
+}
+
+// gridPos returns the position of the pattern for grid cell mg, ng (6.6.5.2).
+func (p halftoneParams) gridPos(mg, ng int) (int, int) {
+	x := (p.gx + mg*p.ry + ng*p.rx) >> 8
+	y := (p.gy + mg*p.rx - ng*p.ry) >> 8
+	return x, y
+}
+
+// decodeHalftoneRegion implements the halftone region decoding procedure (6.6.5).
+func decodeHalftoneRegion(data []byte, p halftoneParams) (*jbig2Bitmap, error) {
+	bm, err := newJBIG2Bitmap(p.w, p.h)
+	if err != nil {
+		return nil, err
+	}
+	bm.fill(p.defPixel)
+
+	if len(p.patterns) == 0 {
+		return nil, errors.New("pdfcpu: jbig2: missing halftone patterns")
+	}
+	pw, ph := p.patterns[0].w, p.patterns[0].h
+
+	var skip *jbig2Bitmap
+	if p.skip {
+		if skip, err = newJBIG2Bitmap(p.gw, p.gh); err != nil {
+			return nil, err
+		}
+		for mg := 0; mg < p.gh; mg++ {
+			for ng := 0; ng < p.gw; ng++ {
+				x, y := p.gridPos(mg, ng)
+				if x+pw <= 0 || x >= p.w || y+ph <= 0 || y >= p.h {
+					skip.set(ng, mg, 1)
+				}
+			}
+		}
+	}
+
+	bpp := codeLen(len(p.patterns))
+
+	// Gray-scale image decoding (C.5)
+	planes := make([]*jbig2Bitmap, bpp)
+
+	var (
+		ad *arithDecoder
+		cc arithContexts
+	)
+	if !p.mmr {
+		ad = newArithDecoder(data)
+		cc = newArithContexts(genericContextSize(p.template))
+	}
+
+	a1 := jbig2Point{3, -1}
+	if p.template > 1 {
+		a1.x = 2
+	}
+	at := []jbig2Point{a1, {-3, -1}, {2, -2}, {-2, -2}}
+
+	for j := bpp - 1; j >= 0; j-- {
+		if p.mmr {
+			var n int
+			if planes[j], n, err = decodeMMR(data, p.gw, p.gh); err != nil {
+				return nil, err
+			}
+			data = data[n:]
+		} else {
+			planes[j], err = decodeGenericRegion(ad, cc, genericParams{w: p.gw, h: p.gh, template: p.template, at: at, skip: skip})
+			if err != nil {
+				return nil, err
+			}
+		}
+		if j < bpp-1 {
+			for i, b := range planes[j+1].data {
+				planes[j].data[i] ^= b
+			}
+		}
+	}
+
+	for mg := 0; mg < p.gh; mg++ {
+		for ng := 0; ng < p.gw; ng++ {
+			v := 0
+			for j := bpp - 1; j >= 0; j-- {
+				v = v<<1 | planes[j].get(ng, mg)
+			}
+			v = min(v, len(p.patterns)-1)
+			x, y := p.gridPos(mg, ng)
+			bm.compose(p.patterns[v], x, y, p.op)
+		}
+	}
+
+	return bm, nil
+}
+
+// decodeHalftoneRegionSegment decodes a halftone region segment (7.4.5).
+func (d *jbig2Decoder) decodeHalftoneRegionSegment(s *jbig2Segment) error {
+	ri, err := parseRegionInfo(s.data)
+	if err != nil {
+		return err
+	}
+
+	data := s.data[regionInfoLen:]
+	if len(data) < 21 {
+		return errJBIG2EOD
+	}
+
+	flags := data[0]
+	p := halftoneParams{
+		w:        ri.w,
+		h:        ri.h,
+		mmr:      flags&1 == 1,
+		template: int(flags>>1) & 3,
+		skip:     flags&8 != 0,
+		op:       int(flags>>4) & 7,
+		defPixel: int(flags>>7) & 1,
+		gw:       int(binary.BigEndian.Uint32(data[1:])),
+		gh:       int(binary.BigEndian.Uint32(data[5:])),
+		gx:       int(int32(binary.BigEndian.Uint32(data[9:]))),
+		gy:       int(int32(binary.BigEndian.Uint32(data[13:]))),
+		rx:       int(binary.BigEndian.Uint16(data[17:])),
+		ry:       int(binary.BigEndian.Uint16(data[19:])),
+	}
+	data = data[21:]
+
+	if ss := d.referredSegments(s, jbig2PatternDict); len(ss) > 0 {
+		p.patterns = ss[0].patterns
+	}
+
+	bm, err := decodeHalftoneRegion(data, p)
+	if err != nil {
+		return err
+	}
+
+	return d.putRegion(s, ri, bm)
+}
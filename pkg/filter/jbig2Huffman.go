@@ -0,0 +1,459 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+var errJBIG2EOD = errors.New("pdfcpu: jbig2: unexpected end of data")
+
+// jbig2BitReader reads bit fields MSB first.
+type jbig2BitReader struct {
+	data []byte
+	pos  int  // byte position
+	bit  uint // bit position within data[pos]
+}
+
+func (r *jbig2BitReader) readBit() (uint32, error) {
+	if r.pos >= len(r.data) {
+		return 0, errJBIG2EOD
+	}
+	b := uint32(r.data[r.pos]>>(7-r.bit)) & 1
+	r.bit++
+	if r.bit == 8 {
+		r.bit = 0
+		r.pos++
+	}
+	return b, nil
+}
+
+func (r *jbig2BitReader) readBits(n int) (uint32, error) {
+	var v uint32
+	for i := 0; i < n; i++ {
+		b, err := r.readBit()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<1 | b
+	}
+	return v, nil
+}
+
+func (r *jbig2BitReader) align() {
+	if r.bit > 0 {
+		r.bit = 0
+		r.pos++
+	}
+}
+
+// Huffman table line kinds.
+const (
+	huffNormal = iota
+	huffLower
+	huffUpper
+	huffOOB
+)
+
+// huffLine is a line of a Huffman table as defined in Annex B.
+type huffLine struct {
+	rangeLow int
+	prefLen  int
+	rangeLen int
+	kind     int
+}
+
+type huffCode struct {
+	len  int
+	code uint32
+}
+
+type huffTable struct {
+	lines []huffLine
+	codes map[huffCode]int // code -> line index
+	oob   bool
+}
+
+// newHuffTable assigns the prefix codes for lines (B.3).
+func newHuffTable(lines []huffLine) (*huffTable, error) {
+	t := &huffTable{lines: lines, codes: map[huffCode]int{}}
+
+	lenMax := 0
+	for _, l := range lines {
+		if l.prefLen > 32 || l.prefLen < 0 {
+			return nil, errors.New("pdfcpu: jbig2: invalid Huffman prefix length")
+		}
+		lenMax = max(lenMax, l.prefLen)
+		if l.kind == huffOOB {
+			t.oob = true
+		}
+	}
+
+	lenCount := make([]uint32, lenMax+1)
+	for _, l := range lines {
+		lenCount[l.prefLen]++
+	}
+	lenCount[0] = 0
+
+	var firstCode uint32
+	for curLen := 1; curLen <= lenMax; curLen++ {
+		firstCode = (firstCode + lenCount[curLen-1]) << 1
+		curCode := firstCode
+		for i, l := range lines {
+			if l.prefLen == curLen {
+				t.codes[huffCode{curLen, curCode}] = i
+				curCode++
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// decode decodes the next value. ok is false for the out-of-band value OOB.
+func (t *huffTable) decode(r *jbig2BitReader) (v int, ok bool, err error) {
+	var code uint32
+	for l := 1; l <= 32; l++ {
+		b, err := r.readBit()
+		if err != nil {
+			return 0, false, err
+		}
+		code = code<<1 | b
+		i, found := t.codes[huffCode{l, code}]
+		if !found {
+			continue
+		}
+		line := t.lines[i]
+		switch line.kind {
+		case huffOOB:
+			return 0, false, nil
+		case huffLower:
+			off, err := r.readBits(32)
+			if err != nil {
+				return 0, false, err
+			}
+			return line.rangeLow - int(off), true, nil
+		}
+		off, err := r.readBits(line.rangeLen)
+		if err != nil {
+			return 0, false, err
+		}
+		return line.rangeLow + int(off), true, nil
+	}
+	return 0, false, errors.New("pdfcpu: jbig2: invalid Huffman code")
+}
+
+// decodeValue decodes the next value treating OOB as an error.
+func (t *huffTable) decodeValue(r *jbig2BitReader) (int, error) {
+	v, ok, err := t.decode(r)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, errors.New("pdfcpu: jbig2: unexpected OOB")
+	}
+	return v, nil
+}
+
+// Standard Huffman tables B.1 - B.15 as {rangeLow, prefLen, rangeLen, kind}.
+var standardHuffLines = [15][]huffLine{
+	{ // B.1
+		{0, 1, 4, huffNormal},
+		{16, 2, 8, huffNormal},
+		{272, 3, 16, huffNormal},
+		{65808, 3, 32, huffUpper},
+	},
+	{ // B.2
+		{0, 1, 0, huffNormal},
+		{1, 2, 0, huffNormal},
+		{2, 3, 0, huffNormal},
+		{3, 4, 3, huffNormal},
+		{11, 5, 6, huffNormal},
+		{75, 6, 32, huffUpper},
+		{0, 6, 0, huffOOB},
+	},
+	{ // B.3
+		{-256, 8, 8, huffNormal},
+		{0, 1, 0, huffNormal},
+		{1, 2, 0, huffNormal},
+		{2, 3, 0, huffNormal},
+		{3, 4, 3, huffNormal},
+		{11, 5, 6, huffNormal},
+		{-257, 8, 32, huffLower},
+		{75, 7, 32, huffUpper},
+		{0, 6, 0, huffOOB},
+	},
+	{ // B.4
+		{1, 1, 0, huffNormal},
+		{2, 2, 0, huffNormal},
+		{3, 3, 0, huffNormal},
+		{4, 4, 3, huffNormal},
+		{12, 5, 6, huffNormal},
+		{76, 5, 32, huffUpper},
+	},
+	{ // B.5
+		{-255, 7, 8, huffNormal},
+		{1, 1, 0, huffNormal},
+		{2, 2, 0, huffNormal},
+		{3, 3, 0, huffNormal},
+		{4, 4, 3, huffNormal},
+		{12, 5, 6, huffNormal},
+		{-256, 7, 32, huffLower},
+		{76, 6, 32, huffUpper},
+	},
+	{ // B.6
+		{-2048, 5, 10, huffNormal},
+		{-1024, 4, 9, huffNormal},
+		{-512, 4, 8, huffNormal},
+		{-256, 4, 7, huffNormal},
+		{-128, 5, 6, huffNormal},
+		{-64, 5, 5, huffNormal},
+		{-32, 4, 5, huffNormal},
+		{0, 2, 7, huffNormal},
+		{128, 3, 7, huffNormal},
+		{256, 3, 8, huffNormal},
+		{512, 4, 9, huffNormal},
+		{1024, 4, 10, huffNormal},
+		{-2049, 6, 32, huffLower},
+		{2048, 6, 32, huffUpper},
+	},
+	{ // B.7
+		{-1024, 4, 9, huffNormal},
+		{-512, 3, 8, huffNormal},
+		{-256, 4, 7, huffNormal},
+		{-128, 5, 6, huffNormal},
+		{-64, 5, 5, huffNormal},
+		{-32, 4, 5, huffNormal},
+		{0, 4, 5, huffNormal},
+		{32, 5, 5, huffNormal},
+		{64, 5, 6, huffNormal},
+		{128, 4, 7, huffNormal},
+		{256, 3, 8, huffNormal},
+		{512, 3, 9, huffNormal},
+		{1024, 3, 10, huffNormal},
+		{-1025, 5, 32, huffLower},
+		{2048, 5, 32, huffUpper},
+	},
+	{ // B.8
+		{-15, 8, 3, huffNormal},
+		{-7, 9, 1, huffNormal},
+		{-5, 8, 1, huffNormal},
+		{-3, 9, 0, huffNormal},
+		{-2, 7, 0, huffNormal},
+		{-1, 4, 0, huffNormal},
+		{0, 2, 1, huffNormal},
+		{2, 5, 0, huffNormal},
+		{3, 6, 0, huffNormal},
+		{4, 3, 4, huffNormal},
+		{20, 6, 1, huffNormal},
+		{22, 4, 4, huffNormal},
+		{38, 4, 5, huffNormal},
+		{70, 5, 6, huffNormal},
+		{134, 5, 7, huffNormal},
+		{262, 6, 7, huffNormal},
+		{390, 7, 8, huffNormal},
+		{646, 6, 10, huffNormal},
+		{-16, 9, 32, huffLower},
+		{1670, 9, 32, huffUpper},
+		{0, 2, 0, huffOOB},
+	},
+	{ // B.9
+		{-31, 8, 4, huffNormal},
+		{-15, 9, 2, huffNormal},
+		{-11, 8, 2, huffNormal},
+		{-7, 9, 1, huffNormal},
+		{-5, 7, 1, huffNormal},
+		{-3, 4, 1, huffNormal},
+		{-1, 3, 1, huffNormal},
+		{1, 3, 1, huffNormal},
+		{3, 5, 1, huffNormal},
+		{5, 6, 1, huffNormal},
+		{7, 3, 5, huffNormal},
+		{39, 6, 2, huffNormal},
+		{43, 4, 5, huffNormal},
+		{75, 4, 6, huffNormal},
+		{139, 5, 7, huffNormal},
+		{267, 5, 8, huffNormal},
+		{523, 6, 8, huffNormal},
+		{779, 7, 9, huffNormal},
+		{1291, 6, 11, huffNormal},
+		{-32, 9, 32, huffLower},
+		{3339, 9, 32, huffUpper},
+		{0, 2, 0, huffOOB},
+	},
+	{ // B.10
+		{-21, 7, 4, huffNormal},
+		{-5, 8, 0, huffNormal},
+		{-4, 7, 0, huffNormal},
+		{-3, 5, 0, huffNormal},
+		{-2, 2, 2, huffNormal},
+		{2, 5, 0, huffNormal},
+		{3, 6, 0, huffNormal},
+		{4, 7, 0, huffNormal},
+		{5, 8, 0, huffNormal},
+		{6, 2, 6, huffNormal},
+		{70, 5, 5, huffNormal},
+		{102, 6, 5, huffNormal},
+		{134, 6, 6, huffNormal},
+		{198, 6, 7, huffNormal},
+		{326, 6, 8, huffNormal},
+		{582, 6, 9, huffNormal},
+		{1094, 6, 10, huffNormal},
+		{2118, 7, 11, huffNormal},
+		{-22, 8, 32, huffLower},
+		{4166, 8, 32, huffUpper},
+		{0, 2, 0, huffOOB},
+	},
+	{ // B.11
+		{1, 1, 0, huffNormal},
+		{2, 2, 1, huffNormal},
+		{4, 4, 0, huffNormal},
+		{5, 4, 1, huffNormal},
+		{7, 5, 1, huffNormal},
+		{9, 5, 2, huffNormal},
+		{13, 6, 2, huffNormal},
+		{17, 7, 2, huffNormal},
+		{21, 7, 3, huffNormal},
+		{29, 7, 4, huffNormal},
+		{45, 7, 5, huffNormal},
+		{77, 7, 6, huffNormal},
+		{141, 7, 32, huffUpper},
+	},
+	{ // B.12
+		{1, 1, 0, huffNormal},
+		{2, 2, 0, huffNormal},
+		{3, 3, 1, huffNormal},
+		{5, 5, 0, huffNormal},
+		{6, 5, 1, huffNormal},
+		{8, 6, 1, huffNormal},
+		{10, 7, 0, huffNormal},
+		{11, 7, 1, huffNormal},
+		{13, 7, 2, huffNormal},
+		{17, 7, 3, huffNormal},
+		{25, 7, 4, huffNormal},
+		{41, 8, 5, huffNormal},
+		{73, 8, 32, huffUpper},
+	},
+	{ // B.13
+		{1, 1, 0, huffNormal},
+		{2, 3, 0, huffNormal},
+		{3, 4, 0, huffNormal},
+		{4, 5, 0, huffNormal},
+		{5, 4, 1, huffNormal},
+		{7, 3, 3, huffNormal},
+		{15, 6, 1, huffNormal},
+		{17, 6, 2, huffNormal},
+		{21, 6, 3, huffNormal},
+		{29, 6, 4, huffNormal},
+		{45, 6, 5, huffNormal},
+		{77, 7, 6, huffNormal},
+		{141, 7, 32, huffUpper},
+	},
+	{ // B.14
+		{-2, 3, 0, huffNormal},
+		{-1, 3, 0, huffNormal},
+		{0, 1, 0, huffNormal},
+		{1, 3, 0, huffNormal},
+		{2, 3, 0, huffNormal},
+	},
+	{ // B.15
+		{-24, 7, 4, huffNormal},
+		{-8, 6, 2, huffNormal},
+		{-4, 5, 1, huffNormal},
+		{-2, 4, 0, huffNormal},
+		{-1, 3, 0, huffNormal},
+		{0, 1, 0, huffNormal},
+		{1, 3, 0, huffNormal},
+		{2, 4, 0, huffNormal},
+		{3, 5, 1, huffNormal},
+		{5, 6, 2, huffNormal},
+		{9, 7, 4, huffNormal},
+		{-25, 7, 32, huffLower},
+		{25, 7, 32, huffUpper},
+	},
+}
+
+var standardHuffTables = func() (tt [15]*huffTable) {
+	for i, lines := range standardHuffLines {
+		// The standard tables are well formed.
+		tt[i], _ = newHuffTable(lines)
+	}
+	return tt
+}()
+
+// standardHuffTable returns the standard Huffman table B.n.
+func standardHuffTable(n int) *huffTable {
+	return standardHuffTables[n-1]
+}
+
+// parseHuffTable parses the data of a tables segment (7.4.13, B.2).
+func parseHuffTable(data []byte) (*huffTable, error) {
+	if len(data) < 9 {
+		return nil, errJBIG2EOD
+	}
+
+	flags := data[0]
+	htOOB := flags&1 == 1
+	htPS := int(flags>>1&7) + 1
+	htRS := int(flags>>4&7) + 1
+	htLow := int(int32(binary.BigEndian.Uint32(data[1:])))
+	htHigh := int(int32(binary.BigEndian.Uint32(data[5:])))
+	if htLow >= htHigh {
+		return nil, errors.New("pdfcpu: jbig2: invalid Huffman table range")
+	}
+
+	r := &jbig2BitReader{data: data[9:]}
+	var lines []huffLine
+
+	for cur := htLow; cur < htHigh; {
+		prefLen, err := r.readBits(htPS)
+		if err != nil {
+			return nil, err
+		}
+		rangeLen, err := r.readBits(htRS)
+		if err != nil {
+			return nil, err
+		}
+		if rangeLen > 32 {
+			return nil, errors.New("pdfcpu: jbig2: invalid Huffman range length")
+		}
+		lines = append(lines, huffLine{cur, int(prefLen), int(rangeLen), huffNormal})
+		cur += 1 << rangeLen
+	}
+
+	prefLen, err := r.readBits(htPS)
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, huffLine{htLow - 1, int(prefLen), 32, huffLower})
+
+	if prefLen, err = r.readBits(htPS); err != nil {
+		return nil, err
+	}
+	lines = append(lines, huffLine{htHigh, int(prefLen), 32, huffUpper})
+
+	if htOOB {
+		if prefLen, err = r.readBits(htPS); err != nil {
+			return nil, err
+		}
+		lines = append(lines, huffLine{0, int(prefLen), 0, huffOOB})
+	}
+
+	return newHuffTable(lines)
+}
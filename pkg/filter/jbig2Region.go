@@ -0,0 +1,378 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/pkg/errors"
+	"golang.org/x/image/ccitt"
+)
+
+// Combination operators.
+const (
+	jbig2OpOr = iota
+	jbig2OpAnd
+	jbig2OpXor
+	jbig2OpXnor
+	jbig2OpReplace
+)
+
+// maxJBIG2Pixels limits the size of any bitmap allocated while decoding.
+const maxJBIG2Pixels = 1 << 30
+
+// jbig2Bitmap is a bilevel image with one bit per pixel where 1 means black.
+// Rows are packed MSB first and padded to full bytes.
+type jbig2Bitmap struct {
+	w, h, stride int
+	data         []byte
+}
+
+func newJBIG2Bitmap(w, h int) (*jbig2Bitmap, error) {
+	if w < 0 || h < 0 || (h > 0 && w > maxJBIG2Pixels/h) {
+		return nil, errors.Errorf("pdfcpu: jbig2: invalid bitmap size %dx%d", w, h)
+	}
+	stride := (w + 7) >> 3
+	return &jbig2Bitmap{w: w, h: h, stride: stride, data: make([]byte, stride*h)}, nil
+}
+
+func (b *jbig2Bitmap) get(x, y int) int {
+	if x < 0 || y < 0 || x >= b.w || y >= b.h {
+		return 0
+	}
+	return int(b.data[y*b.stride+x>>3]>>(7-uint(x&7))) & 1
+}
+
+func (b *jbig2Bitmap) set(x, y, v int) {
+	if x < 0 || y < 0 || x >= b.w || y >= b.h {
+		return
+	}
+	i, m := y*b.stride+x>>3, byte(0x80>>uint(x&7))
+	if v != 0 {
+		b.data[i] |= m
+	} else {
+		b.data[i] &^= m
+	}
+}
+
+func (b *jbig2Bitmap) fill(v int) {
+	var c byte
+	if v != 0 {
+		c = 0xFF
+	}
+	for i := range b.data {
+		b.data[i] = c
+	}
+}
+
+// crop returns the w x h area of b located at x,y.
+func (b *jbig2Bitmap) crop(x, y, w, h int) (*jbig2Bitmap, error) {
+	c, err := newJBIG2Bitmap(w, h)
+	if err != nil {
+		return nil, err
+	}
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			if b.get(x+i, y+j) == 1 {
+				c.set(i, j, 1)
+			}
+		}
+	}
+	return c, nil
+}
+
+// compose combines src into b at position x,y using the combination operator op.
+func (b *jbig2Bitmap) compose(src *jbig2Bitmap, x, y, op int) {
+	x0, y0 := max(0, -x), max(0, -y)
+	x1, y1 := min(src.w, b.w-x), min(src.h, b.h-y)
+
+	for j := y0; j < y1; j++ {
+		for i := x0; i < x1; i++ {
+			s, d := src.get(i, j), b.get(x+i, y+j)
+			switch op {
+			case jbig2OpOr:
+				d |= s
+			case jbig2OpAnd:
+				d &= s
+			case jbig2OpXor:
+				d ^= s
+			case jbig2OpXnor:
+				d = 1 ^ (d ^ s)
+			default:
+				d = s
+			}
+			b.set(x+i, y+j, d)
+		}
+	}
+}
+
+type jbig2Point struct {
+	x, y int
+}
+
+// Generic region templates (6.2.5.3) listing the context pixels from the most
+// to the least significant context bit. Entries with y > 0 refer to the
+// adaptive template pixel at index y-1.
+var genericTemplates = [4][]jbig2Point{
+	{
+		{0, 4}, {-1, -2}, {0, -2}, {1, -2}, {0, 3},
+		{0, 2}, {-2, -1}, {-1, -1}, {0, -1}, {1, -1}, {2, -1}, {0, 1},
+		{-4, 0}, {-3, 0}, {-2, 0}, {-1, 0},
+	},
+	{
+		{-1, -2}, {0, -2}, {1, -2}, {2, -2},
+		{-2, -1}, {-1, -1}, {0, -1}, {1, -1}, {2, -1}, {0, 1},
+		{-3, 0}, {-2, 0}, {-1, 0},
+	},
+	{
+		{-1, -2}, {0, -2}, {1, -2},
+		{-2, -1}, {-1, -1}, {0, -1}, {1, -1}, {0, 1},
+		{-2, 0}, {-1, 0},
+	},
+	{
+		{-3, -1}, {-2, -1}, {-1, -1}, {0, -1}, {1, -1}, {0, 1},
+		{-4, 0}, {-3, 0}, {-2, 0}, {-1, 0},
+	},
+}
+
+// Contexts used for decoding SLTP in typical prediction mode (6.2.5.7).
+var genericSLTP = [4]int{0x9B25, 0x0795, 0x00E5, 0x0195}
+
+// genericContextSize returns the number of contexts used by a generic region template.
+func genericContextSize(template int) int {
+	return 1 << len(genericTemplates[template])
+}
+
+// defaultGenericAT returns the nominal adaptive template pixels for template.
+func defaultGenericAT(template int) []jbig2Point {
+	switch template {
+	case 0:
+		return []jbig2Point{{3, -1}, {-3, -1}, {2, -2}, {-2, -2}}
+	case 1:
+		return []jbig2Point{{3, -1}}
+	}
+	return []jbig2Point{{2, -1}}
+}
+
+type genericParams struct {
+	w, h     int
+	template int
+	tpgdon   bool
+	at       []jbig2Point
+	skip     *jbig2Bitmap
+}
+
+// decodeGenericRegion implements the generic region decoding procedure (6.2) using arithmetic decoding.
+func decodeGenericRegion(ad *arithDecoder, cc arithContexts, p genericParams) (*jbig2Bitmap, error) {
+	b, err := newJBIG2Bitmap(p.w, p.h)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := genericTemplates[p.template]
+	if len(p.at) < len(defaultGenericAT(p.template)) {
+		return nil, errors.New("pdfcpu: jbig2: missing adaptive template pixels")
+	}
+
+	pts := make([]jbig2Point, len(tmpl))
+	for i, pt := range tmpl {
+		if pt.y > 0 {
+			pt = p.at[pt.y-1]
+		}
+		pts[i] = pt
+	}
+
+	ltp := 0
+	for y := 0; y < p.h; y++ {
+		if p.tpgdon {
+			ltp ^= ad.decodeBit(cc, genericSLTP[p.template])
+			if ltp == 1 {
+				if y > 0 {
+					copy(b.data[y*b.stride:(y+1)*b.stride], b.data[(y-1)*b.stride:y*b.stride])
+				}
+				continue
+			}
+		}
+		for x := 0; x < p.w; x++ {
+			if p.skip != nil && p.skip.get(x, y) == 1 {
+				continue
+			}
+			cx := 0
+			for _, pt := range pts {
+				cx = cx<<1 | b.get(x+pt.x, y+pt.y)
+			}
+			if ad.decodeBit(cc, cx) == 1 {
+				b.set(x, y, 1)
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// oneByteReader hands out its data one byte at a time so the number of bytes
+// consumed by the MMR decoder is known.
+type oneByteReader struct {
+	data []byte
+	n    int
+}
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if r.n >= len(r.data) {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	p[0] = r.data[r.n]
+	r.n++
+	return 1, nil
+}
+
+// decodeMMR decodes an MMR (CCITT Group 4) coded bitmap and returns the number of bytes consumed.
+func decodeMMR(data []byte, w, h int) (*jbig2Bitmap, int, error) {
+	b, err := newJBIG2Bitmap(w, h)
+	if err != nil {
+		return nil, 0, err
+	}
+	if w == 0 || h == 0 {
+		return b, 0, nil
+	}
+
+	r := &oneByteReader{data: data}
+	rd := ccitt.NewReader(r, ccitt.MSB, ccitt.Group4, w, h, &ccitt.Options{Invert: true})
+
+	if _, err := io.ReadFull(rd, b.data); err != nil {
+		return nil, 0, errors.Wrap(err, "pdfcpu: jbig2: mmr")
+	}
+
+	// Consume an optional EOFB.
+	var buf bytes.Buffer
+	buf.ReadFrom(rd)
+
+	return b, r.n, nil
+}
+
+// Generic refinement region templates (6.3.5.3) listing the context pixels from the
+// least to the most significant context bit. Pixels flagged ref are taken from the
+// reference bitmap. Entries with y > 1 refer to the adaptive template pixel at index y-2.
+type refinementPixel struct {
+	x, y int
+	ref  bool
+}
+
+var refinementTemplates = [2][]refinementPixel{
+	{
+		{-1, 0, false}, {1, -1, false}, {0, -1, false}, {0, 2, false},
+		{1, 1, true}, {0, 1, true}, {-1, 1, true}, {1, 0, true}, {0, 0, true},
+		{-1, 0, true}, {1, -1, true}, {0, -1, true}, {0, 3, true},
+	},
+	{
+		{-1, 0, false}, {1, -1, false}, {0, -1, false}, {-1, -1, false},
+		{1, 1, true}, {0, 1, true}, {1, 0, true}, {0, 0, true}, {-1, 0, true}, {0, -1, true},
+	},
+}
+
+// Contexts used for decoding SLTP in typical prediction mode (6.3.5.6).
+// Only the reference pixel corresponding to the current pixel is set.
+var refinementSLTP = [2]int{0x0100, 0x0080}
+
+func refinementContextSize(template int) int {
+	return 1 << len(refinementTemplates[template])
+}
+
+func defaultRefinementAT() []jbig2Point {
+	return []jbig2Point{{-1, -1}, {-1, -1}}
+}
+
+type refinementParams struct {
+	w, h     int
+	template int
+	ref      *jbig2Bitmap
+	dx, dy   int
+	tpgron   bool
+	at       []jbig2Point
+}
+
+// decodeRefinementRegion implements the generic refinement region decoding procedure (6.3).
+func decodeRefinementRegion(ad *arithDecoder, cc arithContexts, p refinementParams) (*jbig2Bitmap, error) {
+	b, err := newJBIG2Bitmap(p.w, p.h)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := refinementTemplates[p.template]
+	if p.template == 0 && len(p.at) < 2 {
+		return nil, errors.New("pdfcpu: jbig2: missing refinement adaptive template pixels")
+	}
+
+	pts := make([]refinementPixel, len(tmpl))
+	for i, pt := range tmpl {
+		if pt.y > 1 {
+			at := p.at[pt.y-2]
+			pt.x, pt.y = at.x, at.y
+		}
+		pts[i] = pt
+	}
+
+	pixel := func(x, y int) int {
+		cx := 0
+		for i, pt := range pts {
+			var v int
+			if pt.ref {
+				v = p.ref.get(x-p.dx+pt.x, y-p.dy+pt.y)
+			} else {
+				v = b.get(x+pt.x, y+pt.y)
+			}
+			cx |= v << uint(i)
+		}
+		return ad.decodeBit(cc, cx)
+	}
+
+	ltp := 0
+	for y := 0; y < p.h; y++ {
+		if p.tpgron {
+			ltp ^= ad.decodeBit(cc, refinementSLTP[p.template])
+		}
+		for x := 0; x < p.w; x++ {
+			if ltp == 1 {
+				// Typical prediction: copy the reference pixel if its 3x3 neighbourhood is uniform.
+				rx, ry := x-p.dx, y-p.dy
+				v := p.ref.get(rx, ry)
+				uniform := true
+				for j := -1; j <= 1 && uniform; j++ {
+					for i := -1; i <= 1; i++ {
+						if p.ref.get(rx+i, ry+j) != v {
+							uniform = false
+							break
+						}
+					}
+				}
+				if uniform {
+					b.set(x, y, v)
+					continue
+				}
+			}
+			if pixel(x, y) == 1 {
+				b.set(x, y, 1)
+			}
+		}
+	}
+
+	return b, nil
+}
@@ -0,0 +1,771 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// Reference corners of text region symbol instances.
+const (
+	refCornerBottomLeft = iota
+	refCornerTopLeft
+	refCornerBottomRight
+	refCornerTopRight
+)
+
+// symbolDict is the result of a symbol dictionary segment.
+type symbolDict struct {
+	symbols []*jbig2Bitmap // exported symbols
+	gb, gr  arithContexts  // retained bitmap coding contexts
+}
+
+// codeLen returns ceil(log2(n)).
+func codeLen(n int) int {
+	l := 0
+	for 1<<l < n {
+		l++
+	}
+	return l
+}
+
+// textCoder provides the integer decoding procedures shared by symbol dictionaries and text regions.
+// Either ad (arithmetic) or br (Huffman) is set.
+type textCoder struct {
+	ad *arithDecoder
+	ic *intContexts
+	br *jbig2BitReader
+	gr arithContexts // refinement contexts
+}
+
+// decodeInt decodes an integer using cc for arithmetic and t for Huffman coding.
+func (tc *textCoder) decodeInt(cc arithContexts, t *huffTable) (int, bool, error) {
+	if tc.br != nil {
+		return t.decode(tc.br)
+	}
+	v, ok := tc.ad.decodeInt(cc)
+	return v, ok, nil
+}
+
+// decodeValue decodes an integer treating OOB as an error.
+func (tc *textCoder) decodeValue(cc arithContexts, t *huffTable) (int, error) {
+	v, ok, err := tc.decodeInt(cc, t)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, errors.New("pdfcpu: jbig2: unexpected OOB")
+	}
+	return v, nil
+}
+
+// refine decodes a refinement bitmap. For Huffman coding the refinement data
+// is arithmetically coded within the following size bytes (6.4.11).
+func (tc *textCoder) refine(p refinementParams, sizeTable *huffTable) (*jbig2Bitmap, error) {
+	if tc.br == nil {
+		return decodeRefinementRegion(tc.ad, tc.gr, p)
+	}
+
+	size, err := sizeTable.decodeValue(tc.br)
+	if err != nil {
+		return nil, err
+	}
+	tc.br.align()
+
+	if size < 0 || tc.br.pos+size > len(tc.br.data) {
+		return nil, errJBIG2EOD
+	}
+
+	ad := newArithDecoder(tc.br.data[tc.br.pos : tc.br.pos+size])
+	tc.br.pos += size
+
+	return decodeRefinementRegion(ad, tc.gr, p)
+}
+
+// textRegionParams holds the parameters of the text region decoding procedure (6.4).
+type textRegionParams struct {
+	huff         bool
+	refine       bool
+	w, h         int
+	numInstances int
+	logStrips    int
+	symbols      []*jbig2Bitmap
+	symCodeLen   int
+	symCodes     *huffTable // nil for fixed length symbol ID codes
+	defPixel     int
+	op           int
+	transposed   bool
+	refCorner    int
+	dsOffset     int
+	rTemplate    int
+	rAT          []jbig2Point
+
+	// Huffman tables
+	fs, ds, dt, rdw, rdh, rdx, rdy, rsize *huffTable
+}
+
+// decodeTextRegion implements the text region decoding procedure (6.4.5).
+func decodeTextRegion(tc *textCoder, p textRegionParams) (*jbig2Bitmap, error) {
+	bm, err := newJBIG2Bitmap(p.w, p.h)
+	if err != nil {
+		return nil, err
+	}
+	bm.fill(p.defPixel)
+
+	strips := 1 << p.logStrips
+
+	var ic intContexts
+	if tc.ic != nil {
+		ic = *tc.ic
+	}
+
+	stripT, err := tc.decodeValue(ic.iadt, p.dt)
+	if err != nil {
+		return nil, err
+	}
+	stripT *= -strips
+
+	firstS := 0
+
+	for n := 0; n < p.numInstances; {
+
+		dt, err := tc.decodeValue(ic.iadt, p.dt)
+		if err != nil {
+			return nil, err
+		}
+		stripT += dt * strips
+
+		dfs, err := tc.decodeValue(ic.iafs, p.fs)
+		if err != nil {
+			return nil, err
+		}
+		firstS += dfs
+		curS := firstS
+
+		for {
+			curT := 0
+			if strips > 1 {
+				if tc.br != nil {
+					v, err := tc.br.readBits(p.logStrips)
+					if err != nil {
+						return nil, err
+					}
+					curT = int(v)
+				} else {
+					curT, _ = tc.ad.decodeInt(ic.iait)
+				}
+			}
+			t := stripT + curT
+
+			id, err := tc.decodeSymbolID(p)
+			if err != nil {
+				return nil, err
+			}
+			if id < 0 || id >= len(p.symbols) {
+				return nil, errors.Errorf("pdfcpu: jbig2: invalid symbol id %d", id)
+			}
+			ib := p.symbols[id]
+
+			ri := 0
+			if p.refine {
+				if tc.br != nil {
+					v, err := tc.br.readBit()
+					if err != nil {
+						return nil, err
+					}
+					ri = int(v)
+				} else {
+					ri, _ = tc.ad.decodeInt(ic.iari)
+				}
+			}
+
+			if ri != 0 {
+				if ib, err = tc.refineSymbol(ib, &ic, p); err != nil {
+					return nil, err
+				}
+			}
+
+			wi, hi := ib.w, ib.h
+
+			if !p.transposed && p.refCorner > refCornerTopLeft {
+				curS += wi - 1
+			} else if p.transposed && p.refCorner&1 == 0 {
+				curS += hi - 1
+			}
+
+			s := curS
+
+			var x, y int
+			if !p.transposed {
+				x, y = s, t
+			} else {
+				x, y = t, s
+			}
+			switch p.refCorner {
+			case refCornerTopRight:
+				x -= wi - 1
+			case refCornerBottomLeft:
+				y -= hi - 1
+			case refCornerBottomRight:
+				x, y = x-(wi-1), y-(hi-1)
+			}
+
+			bm.compose(ib, x, y, p.op)
+
+			if !p.transposed && p.refCorner <= refCornerTopLeft {
+				curS += wi - 1
+			} else if p.transposed && p.refCorner&1 == 1 {
+				curS += hi - 1
+			}
+
+			n++
+
+			ids, ok, err := tc.decodeInt(ic.iads, p.ds)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				break
+			}
+			curS += ids + p.dsOffset
+		}
+	}
+
+	return bm, nil
+}
+
+func (tc *textCoder) decodeSymbolID(p textRegionParams) (int, error) {
+	if tc.br == nil {
+		return tc.ad.decodeIAID(tc.ic.iaid, p.symCodeLen), nil
+	}
+	if p.symCodes != nil {
+		return p.symCodes.decodeValue(tc.br)
+	}
+	v, err := tc.br.readBits(p.symCodeLen)
+	return int(v), err
+}
+
+// refineSymbol decodes the refinement of symbol ib for a text region symbol instance (6.4.11).
+func (tc *textCoder) refineSymbol(ib *jbig2Bitmap, ic *intContexts, p textRegionParams) (*jbig2Bitmap, error) {
+	var rd [4]int
+	for i, cc := range []arithContexts{ic.iardw, ic.iardh, ic.iardx, ic.iardy} {
+		t := []*huffTable{p.rdw, p.rdh, p.rdx, p.rdy}[i]
+		v, err := tc.decodeValue(cc, t)
+		if err != nil {
+			return nil, err
+		}
+		rd[i] = v
+	}
+	rdw, rdh, rdx, rdy := rd[0], rd[1], rd[2], rd[3]
+
+	return tc.refine(refinementParams{
+		w:        ib.w + rdw,
+		h:        ib.h + rdh,
+		template: p.rTemplate,
+		ref:      ib,
+		dx:       rdw>>1 + rdx,
+		dy:       rdh>>1 + rdy,
+		at:       p.rAT,
+	}, p.rsize)
+}
+
+// huffTableSelector picks a standard Huffman table by selection value or the next custom table.
+type huffTableSelector struct {
+	custom []*huffTable
+}
+
+func (hs *huffTableSelector) table(sel int, std []int) (*huffTable, error) {
+	if sel < len(std) && std[sel] > 0 {
+		return standardHuffTable(std[sel]), nil
+	}
+	if len(hs.custom) == 0 {
+		return nil, errors.New("pdfcpu: jbig2: missing custom Huffman table")
+	}
+	t := hs.custom[0]
+	hs.custom = hs.custom[1:]
+	return t, nil
+}
+
+// inputSymbols returns the symbols exported by the symbol dictionaries referred to by s.
+func (d *jbig2Decoder) inputSymbols(s *jbig2Segment) ([]*jbig2Bitmap, *symbolDict) {
+	var (
+		syms []*jbig2Bitmap
+		last *symbolDict
+	)
+	for _, s1 := range d.referredSegments(s, jbig2SymbolDict) {
+		if s1.symbols != nil {
+			syms = append(syms, s1.symbols.symbols...)
+			last = s1.symbols
+		}
+	}
+	return syms, last
+}
+
+// decodeSymbolDictSegment decodes a symbol dictionary segment (7.4.2, 6.5).
+func (d *jbig2Decoder) decodeSymbolDictSegment(s *jbig2Segment) error {
+	data := s.data
+	if len(data) < 2 {
+		return errJBIG2EOD
+	}
+
+	flags := int(binary.BigEndian.Uint16(data))
+	huff := flags&1 == 1
+	refAgg := flags&2 != 0
+	selDH := flags >> 2 & 3
+	selDW := flags >> 4 & 3
+	selBMSize := flags >> 6 & 1
+	selAggInst := flags >> 7 & 1
+	ctxUsed := flags&0x100 != 0
+	ctxRetained := flags&0x200 != 0
+	template := flags >> 10 & 3
+	rTemplate := flags >> 12 & 1
+	data = data[2:]
+
+	var (
+		at, rAT []jbig2Point
+		err     error
+	)
+
+	if !huff {
+		n := 1
+		if template == 0 {
+			n = 4
+		}
+		if at, err = readAT(data, n); err != nil {
+			return err
+		}
+		data = data[2*n:]
+	}
+
+	if refAgg && rTemplate == 0 {
+		if rAT, err = readAT(data, 2); err != nil {
+			return err
+		}
+		data = data[4:]
+	}
+
+	if len(data) < 8 {
+		return errJBIG2EOD
+	}
+	numExSyms := int(binary.BigEndian.Uint32(data))
+	numNewSyms := int(binary.BigEndian.Uint32(data[4:]))
+	data = data[8:]
+
+	inSyms, lastDict := d.inputSymbols(s)
+
+	if numExSyms > len(inSyms)+numNewSyms {
+		return errors.New("pdfcpu: jbig2: invalid number of symbols")
+	}
+
+	var dh, dw, bmSize, aggInst *huffTable
+	if huff {
+		hs := &huffTableSelector{custom: d.referredTables(s)}
+		if dh, err = hs.table(selDH, []int{4, 5, 0, 0}); err != nil {
+			return err
+		}
+		if dw, err = hs.table(selDW, []int{2, 3, 0, 0}); err != nil {
+			return err
+		}
+		if bmSize, err = hs.table(selBMSize, []int{1}); err != nil {
+			return err
+		}
+		if aggInst, err = hs.table(selAggInst, []int{1}); err != nil {
+			return err
+		}
+	}
+
+	symCodeLen := codeLen(len(inSyms) + numNewSyms)
+
+	tc := &textCoder{}
+	if huff {
+		tc.br = &jbig2BitReader{data: data}
+	} else {
+		tc.ad = newArithDecoder(data)
+		tc.ic = newIntContexts(symCodeLen)
+	}
+
+	var gb arithContexts
+	tc.gr = newArithContexts(refinementContextSize(rTemplate))
+	if !huff {
+		gb = newArithContexts(genericContextSize(template))
+	}
+	if ctxUsed && lastDict != nil {
+		if lastDict.gb != nil && len(lastDict.gb) == len(gb) {
+			gb = lastDict.gb
+		}
+		if lastDict.gr != nil && len(lastDict.gr) == len(tc.gr) {
+			tc.gr = lastDict.gr
+		}
+	}
+
+	var ic intContexts
+	if tc.ic != nil {
+		ic = *tc.ic
+	}
+
+	// syms holds the input symbols followed by the new symbols decoded so far.
+	syms := append([]*jbig2Bitmap{}, inSyms...)
+	hcHeight := 0
+
+	for hc := 0; len(syms)-len(inSyms) < numNewSyms; hc++ {
+		if hc > numNewSyms {
+			return errors.New("pdfcpu: jbig2: corrupt symbol dictionary")
+		}
+
+		hcdh, err := tc.decodeValue(ic.iadh, dh)
+		if err != nil {
+			return err
+		}
+		hcHeight += hcdh
+		if hcHeight < 0 {
+			return errors.New("pdfcpu: jbig2: invalid symbol height")
+		}
+
+		symWidth, totWidth := 0, 0
+		var widths []int
+
+		for {
+			dw, ok, err := tc.decodeInt(ic.iadw, dw)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			if len(syms)-len(inSyms)+len(widths) >= numNewSyms {
+				return errors.New("pdfcpu: jbig2: too many symbols")
+			}
+
+			symWidth += dw
+			totWidth += symWidth
+			if symWidth < 0 || totWidth < 0 {
+				return errors.New("pdfcpu: jbig2: invalid symbol width")
+			}
+
+			if huff && !refAgg {
+				widths = append(widths, symWidth)
+				continue
+			}
+
+			var bm *jbig2Bitmap
+			if !refAgg {
+				bm, err = decodeGenericRegion(tc.ad, gb, genericParams{w: symWidth, h: hcHeight, template: template, at: at})
+			} else {
+				bm, err = d.decodeAggregateSymbol(tc, aggInst, symWidth, hcHeight, syms, symCodeLen, rTemplate, rAT)
+			}
+			if err != nil {
+				return err
+			}
+			syms = append(syms, bm)
+		}
+
+		if huff && !refAgg {
+			bms, err := decodeCollectiveBitmap(tc.br, bmSize, widths, totWidth, hcHeight)
+			if err != nil {
+				return err
+			}
+			syms = append(syms, bms...)
+		}
+	}
+
+	// Exported symbols (6.5.10)
+	var exSyms []*jbig2Bitmap
+	for i, export, runs := 0, false, 0; i < len(syms); export, runs = !export, runs+1 {
+		run, err := tc.decodeValue(ic.iaex, standardHuffTable(1))
+		if err != nil {
+			return err
+		}
+		if run < 0 || i+run > len(syms) || runs > 2*len(syms)+1 {
+			return errors.New("pdfcpu: jbig2: invalid export run length")
+		}
+		if export {
+			exSyms = append(exSyms, syms[i:i+run]...)
+		}
+		i += run
+	}
+
+	sd := &symbolDict{symbols: exSyms}
+	if ctxRetained {
+		sd.gb, sd.gr = gb, tc.gr
+	}
+	s.symbols = sd
+
+	return nil
+}
+
+// decodeAggregateSymbol decodes a refinement/aggregate coded symbol bitmap (6.5.8.2).
+func (d *jbig2Decoder) decodeAggregateSymbol(tc *textCoder, aggInst *huffTable, w, h int, syms []*jbig2Bitmap, symCodeLen, rTemplate int, rAT []jbig2Point) (*jbig2Bitmap, error) {
+	var ic intContexts
+	if tc.ic != nil {
+		ic = *tc.ic
+	}
+
+	n, err := tc.decodeValue(ic.iaai, aggInst)
+	if err != nil {
+		return nil, err
+	}
+
+	if n > 1 {
+		p := textRegionParams{
+			huff:         tc.br != nil,
+			refine:       true,
+			w:            w,
+			h:            h,
+			numInstances: n,
+			symbols:      syms,
+			symCodeLen:   symCodeLen,
+			op:           jbig2OpOr,
+			refCorner:    refCornerTopLeft,
+			rTemplate:    rTemplate,
+			rAT:          rAT,
+			fs:           standardHuffTable(6),
+			ds:           standardHuffTable(8),
+			dt:           standardHuffTable(11),
+			rdw:          standardHuffTable(15),
+			rdh:          standardHuffTable(15),
+			rdx:          standardHuffTable(15),
+			rdy:          standardHuffTable(15),
+			rsize:        standardHuffTable(1),
+		}
+		return decodeTextRegion(tc, p)
+	}
+
+	id, err := tc.decodeSymbolID(textRegionParams{symCodeLen: symCodeLen})
+	if err != nil {
+		return nil, err
+	}
+	if id < 0 || id >= len(syms) {
+		return nil, errors.Errorf("pdfcpu: jbig2: invalid symbol id %d", id)
+	}
+
+	rdx, err := tc.decodeValue(ic.iardx, standardHuffTable(15))
+	if err != nil {
+		return nil, err
+	}
+	rdy, err := tc.decodeValue(ic.iardy, standardHuffTable(15))
+	if err != nil {
+		return nil, err
+	}
+
+	return tc.refine(refinementParams{w: w, h: h, template: rTemplate, ref: syms[id], dx: rdx, dy: rdy, at: rAT}, standardHuffTable(1))
+}
+
+// decodeCollectiveBitmap decodes the collective bitmap of a Huffman coded height class
+// and splits it into symbols of the given widths (6.5.9).
+func decodeCollectiveBitmap(br *jbig2BitReader, bmSizeTable *huffTable, widths []int, totWidth, h int) ([]*jbig2Bitmap, error) {
+	bmSize, err := bmSizeTable.decodeValue(br)
+	if err != nil {
+		return nil, err
+	}
+	br.align()
+
+	var coll *jbig2Bitmap
+
+	if bmSize == 0 {
+		if coll, err = newJBIG2Bitmap(totWidth, h); err != nil {
+			return nil, err
+		}
+		if br.pos+len(coll.data) > len(br.data) {
+			return nil, errJBIG2EOD
+		}
+		copy(coll.data, br.data[br.pos:])
+		br.pos += len(coll.data)
+	} else {
+		if bmSize < 0 || br.pos+bmSize > len(br.data) {
+			return nil, errJBIG2EOD
+		}
+		if coll, _, err = decodeMMR(br.data[br.pos:br.pos+bmSize], totWidth, h); err != nil {
+			return nil, err
+		}
+		br.pos += bmSize
+	}
+
+	syms := make([]*jbig2Bitmap, len(widths))
+	x := 0
+	for i, w := range widths {
+		if syms[i], err = coll.crop(x, 0, w, h); err != nil {
+			return nil, err
+		}
+		x += w
+	}
+
+	return syms, nil
+}
+
+// parseSymbolIDTable decodes the Huffman table for symbol IDs of a text region (7.4.3.1.7).
+func parseSymbolIDTable(br *jbig2BitReader, numSyms int) (*huffTable, error) {
+	lines := make([]huffLine, 35)
+	for i := range lines {
+		l, err := br.readBits(4)
+		if err != nil {
+			return nil, err
+		}
+		lines[i] = huffLine{rangeLow: i, prefLen: int(l)}
+	}
+
+	runCodes, err := newHuffTable(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	lens := make([]int, 0, numSyms)
+
+	for len(lens) < numSyms {
+		code, err := runCodes.decodeValue(br)
+		if err != nil {
+			return nil, err
+		}
+
+		v, n := code, 1
+
+		switch code {
+		case 32:
+			if len(lens) == 0 {
+				return nil, errors.New("pdfcpu: jbig2: invalid symbol ID code lengths")
+			}
+			r, err := br.readBits(2)
+			if err != nil {
+				return nil, err
+			}
+			v, n = lens[len(lens)-1], int(r)+3
+		case 33:
+			r, err := br.readBits(3)
+			if err != nil {
+				return nil, err
+			}
+			v, n = 0, int(r)+3
+		case 34:
+			r, err := br.readBits(7)
+			if err != nil {
+				return nil, err
+			}
+			v, n = 0, int(r)+11
+		}
+
+		for ; n > 0 && len(lens) < numSyms; n-- {
+			lens = append(lens, v)
+		}
+	}
+
+	br.align()
+
+	lines = make([]huffLine, numSyms)
+	for i, l := range lens {
+		lines[i] = huffLine{rangeLow: i, prefLen: l}
+	}
+
+	return newHuffTable(lines)
+}
+
+// decodeTextRegionSegment decodes a text region segment (7.4.3).
+func (d *jbig2Decoder) decodeTextRegionSegment(s *jbig2Segment) error {
+	ri, err := parseRegionInfo(s.data)
+	if err != nil {
+		return err
+	}
+
+	data := s.data[regionInfoLen:]
+	if len(data) < 2 {
+		return errJBIG2EOD
+	}
+
+	flags := int(binary.BigEndian.Uint16(data))
+	data = data[2:]
+
+	p := textRegionParams{
+		huff:       flags&1 == 1,
+		refine:     flags&2 != 0,
+		w:          ri.w,
+		h:          ri.h,
+		logStrips:  flags >> 2 & 3,
+		refCorner:  flags >> 4 & 3,
+		transposed: flags&0x40 != 0,
+		op:         flags >> 7 & 3,
+		defPixel:   flags >> 9 & 1,
+		dsOffset:   flags >> 10 & 0x1F,
+		rTemplate:  flags >> 15 & 1,
+	}
+	if p.dsOffset > 0x0F {
+		p.dsOffset -= 0x20
+	}
+
+	var huffFlags int
+	if p.huff {
+		if len(data) < 2 {
+			return errJBIG2EOD
+		}
+		huffFlags = int(binary.BigEndian.Uint16(data))
+		data = data[2:]
+	}
+
+	if p.refine && p.rTemplate == 0 {
+		if p.rAT, err = readAT(data, 2); err != nil {
+			return err
+		}
+		data = data[4:]
+	}
+
+	if len(data) < 4 {
+		return errJBIG2EOD
+	}
+	p.numInstances = int(binary.BigEndian.Uint32(data))
+	data = data[4:]
+
+	p.symbols, _ = d.inputSymbols(s)
+	p.symCodeLen = codeLen(len(p.symbols))
+
+	tc := &textCoder{gr: newArithContexts(refinementContextSize(p.rTemplate))}
+
+	if p.huff {
+		hs := &huffTableSelector{custom: d.referredTables(s)}
+		for _, v := range []struct {
+			t   **huffTable
+			sel int
+			std []int
+		}{
+			{&p.fs, huffFlags & 3, []int{6, 7, 0, 0}},
+			{&p.ds, huffFlags >> 2 & 3, []int{8, 9, 10, 0}},
+			{&p.dt, huffFlags >> 4 & 3, []int{11, 12, 13, 0}},
+			{&p.rdw, huffFlags >> 6 & 3, []int{14, 15, 0, 0}},
+			{&p.rdh, huffFlags >> 8 & 3, []int{14, 15, 0, 0}},
+			{&p.rdx, huffFlags >> 10 & 3, []int{14, 15, 0, 0}},
+			{&p.rdy, huffFlags >> 12 & 3, []int{14, 15, 0, 0}},
+			{&p.rsize, huffFlags >> 14 & 1, []int{1}},
+		} {
+			if *v.t, err = hs.table(v.sel, v.std); err != nil {
+				return err
+			}
+		}
+
+		tc.br = &jbig2BitReader{data: data}
+		if p.symCodes, err = parseSymbolIDTable(tc.br, len(p.symbols)); err != nil {
+			return err
+		}
+	} else {
+		tc.ad = newArithDecoder(data)
+		tc.ic = newIntContexts(p.symCodeLen)
+	}
+
+	bm, err := decodeTextRegion(tc, p)
+	if err != nil {
+		return err
+	}
+
+	return d.putRegion(s, ri, bm)
+}
@@ -0,0 +1,1214 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// JPEG 2000 codestream parsing as specified in ITU-T T.800 Annex A.
+
+// Codestream markers (A.2)
+const (
+	jpxSOC = 0xFF4F
+	jpxSOT = 0xFF90
+	jpxSOD = 0xFF93
+	jpxEOC = 0xFFD9
+	jpxSIZ = 0xFF51
+	jpxCOD = 0xFF52
+	jpxCOC = 0xFF53
+	jpxRGN = 0xFF5E
+	jpxQCD = 0xFF5C
+	jpxQCC = 0xFF5D
+	jpxPOC = 0xFF5F
+	jpxPPM = 0xFF60
+	jpxPPT = 0xFF61
+	jpxSOP = 0xFF91
+	jpxEPH = 0xFF92
+)
+
+// Code-block style flags (Table A.19)
+const (
+	jpxBypass    = 0x01
+	jpxReset     = 0x02
+	jpxTermAll   = 0x04
+	jpxVCausal   = 0x08
+	jpxSegSymbol = 0x20
+)
+
+// Maximum number of samples of a decoded image.
+const maxJPXSamples = 1 << 28
+
+var errJPXEOD = errors.New("pdfcpu: jpx: unexpected end of data")
+
+type jpxComponent struct {
+	prec   int
+	signed bool
+	dx, dy int
+	x0, y0 int
+	w, h   int
+}
+
+// jpxSize holds the image and tile size parameters of the SIZ marker segment (A.5.1).
+type jpxSize struct {
+	x1, y1   int
+	x0, y0   int
+	tw, th   int
+	tx0, ty0 int
+	comps    []jpxComponent
+}
+
+func (s *jpxSize) tilesWide() int {
+	return ceilDiv(s.x1-s.tx0, s.tw)
+}
+
+func (s *jpxSize) tilesHigh() int {
+	return ceilDiv(s.y1-s.ty0, s.th)
+}
+
+// jpxCodingStyle holds the parameters of a COD or COC marker segment (A.6.1, A.6.2).
+type jpxCodingStyle struct {
+	sop, eph    bool
+	progression int
+	layers      int
+	mct         bool
+	levels      int
+	xcb, ycb    int
+	cbStyle     int
+	reversible  bool
+	ppx, ppy    []int
+}
+
+// precinctSize returns the log2 precinct dimensions for resolution r.
+func (cs *jpxCodingStyle) precinctSize(r int) (int, int) {
+	if r < len(cs.ppx) {
+		return cs.ppx[r], cs.ppy[r]
+	}
+	return 15, 15
+}
+
+// jpxQuantization holds the parameters of a QCD or QCC marker segment (A.6.4, A.6.5).
+type jpxQuantization struct {
+	style int
+	guard int
+	eps   []int
+	mu    []int
+}
+
+// stepSize returns exponent and mantissa for the subband at index b with decomposition level n (E.1.1.1).
+func (q *jpxQuantization) stepSize(b, n, levels int) (int, int) {
+	if q.style == 1 {
+		// Scalar derived
+		return q.eps[0] - levels + n, q.mu[0]
+	}
+	if b >= len(q.eps) {
+		b = len(q.eps) - 1
+	}
+	return q.eps[b], q.mu[b]
+}
+
+// jpxTileHeader accumulates the tile-part headers and bodies of one tile.
+type jpxTileHeader struct {
+	cod     *jpxCodingStyle
+	coc     map[int]*jpxCodingStyle
+	qcd     *jpxQuantization
+	qcc     map[int]*jpxQuantization
+	rgn     map[int]int
+	body    []byte
+	headers []byte
+	packed  bool
+}
+
+type jpxCodestream struct {
+	siz   jpxSize
+	cod   *jpxCodingStyle
+	coc   map[int]*jpxCodingStyle
+	qcd   *jpxQuantization
+	qcc   map[int]*jpxQuantization
+	rgn   map[int]int
+	ppm   [][]byte
+	tiles map[int]*jpxTileHeader
+}
+
+func ceilDiv(a, b int) int {
+	if a >= 0 {
+		return (a + b - 1) / b
+	}
+	return -(-a / b)
+}
+
+func floorDiv(a, b int) int {
+	if a >= 0 {
+		return a / b
+	}
+	return -((-a + b - 1) / b)
+}
+
+// parseJPXSize parses a SIZ marker segment.
+func parseJPXSize(data []byte) (*jpxSize, error) {
+	if len(data) < 38 {
+		return nil, errJPXEOD
+	}
+
+	be := binary.BigEndian
+	s := &jpxSize{
+		x1:  int(be.Uint32(data[4:])),
+		y1:  int(be.Uint32(data[8:])),
+		x0:  int(be.Uint32(data[12:])),
+		y0:  int(be.Uint32(data[16:])),
+		tw:  int(be.Uint32(data[20:])),
+		th:  int(be.Uint32(data[24:])),
+		tx0: int(be.Uint32(data[28:])),
+		ty0: int(be.Uint32(data[32:])),
+	}
+
+	n := int(be.Uint16(data[36:]))
+	if n == 0 || len(data) < 38+3*n {
+		return nil, errors.New("pdfcpu: jpx: invalid SIZ marker")
+	}
+
+	if s.x1 <= s.x0 || s.y1 <= s.y0 || s.tw == 0 || s.th == 0 || s.tx0 > s.x0 || s.ty0 > s.y0 ||
+		s.tx0+s.tw <= s.x0 || s.ty0+s.th <= s.y0 {
+		return nil, errors.New("pdfcpu: jpx: invalid image geometry")
+	}
+
+	if (s.x1-s.x0)*(s.y1-s.y0) > maxJPXSamples/n {
+		return nil, errors.New("pdfcpu: jpx: image too large")
+	}
+
+	for i := 0; i < n; i++ {
+		b := data[38+3*i:]
+		c := jpxComponent{
+			prec:   int(b[0]&0x7F) + 1,
+			signed: b[0]&0x80 != 0,
+			dx:     int(b[1]),
+			dy:     int(b[2]),
+		}
+		if c.dx == 0 || c.dy == 0 || c.prec > 38 {
+			return nil, errors.New("pdfcpu: jpx: invalid component")
+		}
+		if c.prec > 16 {
+			return nil, errors.Errorf("pdfcpu: jpx: unsupported component precision: %d", c.prec)
+		}
+		c.x0, c.y0 = ceilDiv(s.x0, c.dx), ceilDiv(s.y0, c.dy)
+		c.w, c.h = ceilDiv(s.x1, c.dx)-c.x0, ceilDiv(s.y1, c.dy)-c.y0
+		s.comps = append(s.comps, c)
+	}
+
+	return s, nil
+}
+
+func (cs *jpxCodestream) componentIndex(data []byte) (int, []byte, error) {
+	if len(cs.siz.comps) < 257 {
+		if len(data) < 1 {
+			return 0, nil, errJPXEOD
+		}
+		return int(data[0]), data[1:], nil
+	}
+	if len(data) < 2 {
+		return 0, nil, errJPXEOD
+	}
+	return int(binary.BigEndian.Uint16(data)), data[2:], nil
+}
+
+// parseComponentCodingStyle parses the SPcod/SPcoc parameters following the style byte.
+func parseComponentCodingStyle(cs *jpxCodingStyle, precincts bool, data []byte) error {
+	if len(data) < 5 {
+		return errJPXEOD
+	}
+
+	cs.levels = int(data[0])
+	cs.xcb = int(data[1]&0x0F) + 2
+	cs.ycb = int(data[2]&0x0F) + 2
+	cs.cbStyle = int(data[3])
+	cs.reversible = data[4] == 1
+
+	if cs.levels > 32 || cs.xcb+cs.ycb > 12 {
+		return errors.New("pdfcpu: jpx: invalid coding style")
+	}
+
+	cs.ppx, cs.ppy = nil, nil
+	if precincts {
+		data = data[5:]
+		if len(data) < cs.levels+1 {
+			return errJPXEOD
+		}
+		for _, b := range data[:cs.levels+1] {
+			cs.ppx = append(cs.ppx, int(b&0x0F))
+			cs.ppy = append(cs.ppy, int(b>>4))
+		}
+	}
+
+	return nil
+}
+
+// parseCOD parses a COD marker segment (A.6.1).
+func parseCOD(data []byte) (*jpxCodingStyle, error) {
+	if len(data) < 10 {
+		return nil, errJPXEOD
+	}
+
+	scod := data[2]
+	cs := &jpxCodingStyle{
+		sop:         scod&2 != 0,
+		eph:         scod&4 != 0,
+		progression: int(data[3]),
+		layers:      int(binary.BigEndian.Uint16(data[4:])),
+		mct:         data[6] == 1,
+	}
+
+	if cs.progression > 4 || cs.layers == 0 {
+		return nil, errors.New("pdfcpu: jpx: invalid COD marker")
+	}
+
+	return cs, parseComponentCodingStyle(cs, scod&1 != 0, data[7:])
+}
+
+// parseCOC parses a COC marker segment (A.6.2) overriding the component parameters of cod.
+func (cs *jpxCodestream) parseCOC(cod *jpxCodingStyle, data []byte) (int, *jpxCodingStyle, error) {
+	if cod == nil {
+		return 0, nil, errors.New("pdfcpu: jpx: COC without COD")
+	}
+
+	c, data, err := cs.componentIndex(data[2:])
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 1 {
+		return 0, nil, errJPXEOD
+	}
+
+	coc := *cod
+	return c, &coc, parseComponentCodingStyle(&coc, data[0]&1 != 0, data[1:])
+}
+
+// parseQuantization parses the Sqcd/SPqcd parameters of a QCD or QCC marker segment.
+func parseQuantization(data []byte) (*jpxQuantization, error) {
+	if len(data) < 1 {
+		return nil, errJPXEOD
+	}
+
+	q := &jpxQuantization{style: int(data[0] & 0x1F), guard: int(data[0] >> 5)}
+	data = data[1:]
+
+	switch q.style {
+	case 0:
+		for _, b := range data {
+			q.eps = append(q.eps, int(b>>3))
+			q.mu = append(q.mu, 0)
+		}
+	case 1, 2:
+		for ; len(data) >= 2; data = data[2:] {
+			v := int(binary.BigEndian.Uint16(data))
+			q.eps = append(q.eps, v>>11)
+			q.mu = append(q.mu, v&0x7FF)
+		}
+	default:
+		return nil, errors.Errorf("pdfcpu: jpx: invalid quantization style %d", q.style)
+	}
+
+	if len(q.eps) == 0 {
+		return nil, errJPXEOD
+	}
+
+	return q, nil
+}
+
+// parseRGN parses a RGN marker segment (A.6.3).
+func (cs *jpxCodestream) parseRGN(data []byte) (int, int, error) {
+	c, data, err := cs.componentIndex(data[2:])
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(data) < 2 {
+		return 0, 0, errJPXEOD
+	}
+	if data[0] != 0 {
+		return 0, 0, errors.New("pdfcpu: jpx: unsupported ROI style")
+	}
+	return c, int(data[1]), nil
+}
+
+// parseCodestream parses the main header and all tile-parts of a JPEG 2000 codestream.
+func parseCodestream(data []byte, headerOnly bool) (*jpxCodestream, error) {
+	if len(data) < 2 || binary.BigEndian.Uint16(data) != jpxSOC {
+		return nil, errors.New("pdfcpu: jpx: missing SOC marker")
+	}
+
+	cs := &jpxCodestream{
+		coc:   map[int]*jpxCodingStyle{},
+		qcc:   map[int]*jpxQuantization{},
+		rgn:   map[int]int{},
+		tiles: map[int]*jpxTileHeader{},
+	}
+
+	var (
+		ppm       []byte
+		hasSIZ    bool
+		tile      *jpxTileHeader
+		tilePartE int
+		tileParts int
+	)
+
+	pos := 2
+	for pos+2 <= len(data) {
+		m := int(binary.BigEndian.Uint16(data[pos:]))
+		pos += 2
+
+		if m == jpxEOC {
+			break
+		}
+
+		if m == jpxSOD {
+			if tile == nil {
+				return nil, errors.New("pdfcpu: jpx: SOD outside tile-part")
+			}
+			end := min(tilePartE, len(data))
+			if end < pos {
+				return nil, errJPXEOD
+			}
+			tile.body = append(tile.body, data[pos:end]...)
+			pos, tile = end, nil
+			continue
+		}
+
+		if pos+2 > len(data) {
+			return nil, errJPXEOD
+		}
+		l := int(binary.BigEndian.Uint16(data[pos:]))
+		if l < 2 || pos+l > len(data) {
+			return nil, errJPXEOD
+		}
+		seg := data[pos : pos+l]
+
+		if !hasSIZ && m != jpxSIZ {
+			return nil, errors.New("pdfcpu: jpx: missing SIZ marker")
+		}
+
+		var err error
+
+		switch m {
+
+		case jpxSIZ:
+			var siz *jpxSize
+			if siz, err = parseJPXSize(seg); err == nil {
+				cs.siz, hasSIZ = *siz, true
+				if headerOnly {
+					return cs, nil
+				}
+			}
+
+		case jpxCOD:
+			var cod *jpxCodingStyle
+			if cod, err = parseCOD(seg); err == nil {
+				if tile != nil {
+					tile.cod = cod
+				} else {
+					cs.cod = cod
+				}
+			}
+
+		case jpxCOC:
+			var (
+				c   int
+				coc *jpxCodingStyle
+			)
+			if tile != nil {
+				cod := tile.cod
+				if cod == nil {
+					cod = cs.cod
+				}
+				if c, coc, err = cs.parseCOC(cod, seg); err == nil {
+					tile.coc[c] = coc
+				}
+			} else if c, coc, err = cs.parseCOC(cs.cod, seg); err == nil {
+				cs.coc[c] = coc
+			}
+
+		case jpxQCD:
+			var q *jpxQuantization
+			if q, err = parseQuantization(seg[2:]); err == nil {
+				if tile != nil {
+					tile.qcd = q
+				} else {
+					cs.qcd = q
+				}
+			}
+
+		case jpxQCC:
+			var (
+				c int
+				q *jpxQuantization
+				b []byte
+			)
+			if c, b, err = cs.componentIndex(seg[2:]); err == nil {
+				if q, err = parseQuantization(b); err == nil {
+					if tile != nil {
+						tile.qcc[c] = q
+					} else {
+						cs.qcc[c] = q
+					}
+				}
+			}
+
+		case jpxRGN:
+			var c, shift int
+			if c, shift, err = cs.parseRGN(seg); err == nil {
+				if tile != nil {
+					tile.rgn[c] = shift
+				} else {
+					cs.rgn[c] = shift
+				}
+			}
+
+		case jpxPOC:
+			err = errors.New("pdfcpu: jpx: progression order changes not supported")
+
+		case jpxPPM:
+			if len(seg) > 3 {
+				ppm = append(ppm, seg[3:]...)
+			}
+
+		case jpxPPT:
+			if tile != nil && len(seg) > 3 {
+				tile.headers = append(tile.headers, seg[3:]...)
+				tile.packed = true
+			}
+
+		case jpxSOT:
+			if len(seg) < 10 {
+				return nil, errJPXEOD
+			}
+			if tileParts == 0 {
+				if cs.ppm, err = splitPPM(ppm); err != nil {
+					return nil, err
+				}
+			}
+			i := int(binary.BigEndian.Uint16(seg[2:]))
+			psot := int(binary.BigEndian.Uint32(seg[4:]))
+			if i >= cs.siz.tilesWide()*cs.siz.tilesHigh() {
+				return nil, errors.Errorf("pdfcpu: jpx: invalid tile index %d", i)
+			}
+			tilePartE = len(data)
+			if psot != 0 {
+				tilePartE = pos - 2 + psot
+			}
+			if tile = cs.tiles[i]; tile == nil {
+				tile = &jpxTileHeader{coc: map[int]*jpxCodingStyle{}, qcc: map[int]*jpxQuantization{}, rgn: map[int]int{}}
+				cs.tiles[i] = tile
+			}
+			if tileParts < len(cs.ppm) {
+				tile.headers = append(tile.headers, cs.ppm[tileParts]...)
+				tile.packed = true
+			}
+			tileParts++
+
+		default:
+			// Skip other marker segments like TLM, PLM, PLT, CRG and COM.
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		pos += l
+	}
+
+	if !hasSIZ {
+		return nil, errors.New("pdfcpu: jpx: missing SIZ marker")
+	}
+	if cs.cod == nil || cs.qcd == nil {
+		return nil, errors.New("pdfcpu: jpx: missing COD or QCD marker")
+	}
+
+	return cs, nil
+}
+
+// splitPPM splits the concatenated Ippm data of all PPM marker segments into the packet headers per tile-part (A.7.4).
+func splitPPM(data []byte) ([][]byte, error) {
+	var hh [][]byte
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, errJPXEOD
+		}
+		n := int(binary.BigEndian.Uint32(data))
+		data = data[4:]
+		if n > len(data) {
+			return nil, errJPXEOD
+		}
+		hh = append(hh, data[:n])
+		data = data[n:]
+	}
+	return hh, nil
+}
+
+// Tile structure as described in T.800 Annex B.
+
+type jpxCodeBlock struct {
+	x0, y0, x1, y1 int
+	included       bool
+	lblock         int
+	zeroPlanes     int
+	passes         int
+	segs           []jpxCodeSegment
+}
+
+// jpxCodeSegment holds the data of a codeword segment along with its number of coding passes.
+type jpxCodeSegment struct {
+	data   []byte
+	passes int
+}
+
+type jpxPrecinct struct {
+	cw, ch int
+	blocks []*jpxCodeBlock
+	incl   *jpxTagTree
+	zero   *jpxTagTree
+}
+
+// Subband orientations
+const (
+	jpxLL = iota
+	jpxHL
+	jpxLH
+	jpxHH
+)
+
+type jpxBand struct {
+	typ            int
+	level          int
+	x0, y0, x1, y1 int
+	precincts      []*jpxPrecinct
+}
+
+type jpxResolution struct {
+	x0, y0, x1, y1 int
+	ppx, ppy       int
+	pw, ph         int
+	bands          []*jpxBand
+}
+
+type jpxTileComponent struct {
+	x0, y0, x1, y1 int
+	cs             *jpxCodingStyle
+	q              *jpxQuantization
+	roiShift       int
+	res            []*jpxResolution
+	data           []float64
+}
+
+type jpxTile struct {
+	x0, y0, x1, y1 int
+	cs             *jpxCodingStyle
+	comps          []*jpxTileComponent
+}
+
+// newTile sets up the resolutions, subbands, precincts and code-blocks of tile i.
+func (cs *jpxCodestream) newTile(i int, th *jpxTileHeader) (*jpxTile, error) {
+	s := &cs.siz
+	p, q := i%s.tilesWide(), i/s.tilesWide()
+
+	t := &jpxTile{
+		x0: max(s.tx0+p*s.tw, s.x0),
+		y0: max(s.ty0+q*s.th, s.y0),
+		x1: min(s.tx0+(p+1)*s.tw, s.x1),
+		y1: min(s.ty0+(q+1)*s.th, s.y1),
+		cs: cs.cod,
+	}
+	if th.cod != nil {
+		t.cs = th.cod
+	}
+
+	for c, comp := range s.comps {
+		tc := &jpxTileComponent{
+			x0: ceilDiv(t.x0, comp.dx),
+			y0: ceilDiv(t.y0, comp.dy),
+			x1: ceilDiv(t.x1, comp.dx),
+			y1: ceilDiv(t.y1, comp.dy),
+		}
+
+		// Tile-part COC > tile-part COD > main COC > main COD
+		switch {
+		case th.coc[c] != nil:
+			tc.cs = th.coc[c]
+		case th.cod != nil:
+			tc.cs = th.cod
+		case cs.coc[c] != nil:
+			tc.cs = cs.coc[c]
+		default:
+			tc.cs = cs.cod
+		}
+
+		switch {
+		case th.qcc[c] != nil:
+			tc.q = th.qcc[c]
+		case th.qcd != nil:
+			tc.q = th.qcd
+		case cs.qcc[c] != nil:
+			tc.q = cs.qcc[c]
+		default:
+			tc.q = cs.qcd
+		}
+
+		if shift, ok := th.rgn[c]; ok {
+			tc.roiShift = shift
+		} else {
+			tc.roiShift = cs.rgn[c]
+		}
+
+		if err := tc.build(); err != nil {
+			return nil, err
+		}
+
+		t.comps = append(t.comps, tc)
+	}
+
+	return t, nil
+}
+
+// build divides the tile-component into resolutions, subbands, precincts and code-blocks (B.5 - B.7).
+func (tc *jpxTileComponent) build() error {
+	nl := tc.cs.levels
+
+	for r := 0; r <= nl; r++ {
+		sc := 1 << (nl - r)
+		res := &jpxResolution{
+			x0: ceilDiv(tc.x0, sc),
+			y0: ceilDiv(tc.y0, sc),
+			x1: ceilDiv(tc.x1, sc),
+			y1: ceilDiv(tc.y1, sc),
+		}
+		res.ppx, res.ppy = tc.cs.precinctSize(r)
+
+		if res.x1 > res.x0 {
+			res.pw = ceilDiv(res.x1, 1<<res.ppx) - res.x0>>res.ppx
+		}
+		if res.y1 > res.y0 {
+			res.ph = ceilDiv(res.y1, 1<<res.ppy) - res.y0>>res.ppy
+		}
+		if res.pw*res.ph > maxJPXSamples {
+			return errors.New("pdfcpu: jpx: too many precincts")
+		}
+
+		orients := []int{jpxHL, jpxLH, jpxHH}
+		if r == 0 {
+			orients = []int{jpxLL}
+		}
+
+		for _, typ := range orients {
+			b := &jpxBand{typ: typ, level: nl - r + 1}
+			if r == 0 {
+				b.level = nl
+			}
+			xo, yo := typ&1, typ>>1
+			var ox, oy int
+			if b.level > 0 {
+				ox, oy = xo<<(b.level-1), yo<<(b.level-1)
+			}
+			bs := 1 << b.level
+			b.x0, b.y0 = ceilDiv(tc.x0-ox, bs), ceilDiv(tc.y0-oy, bs)
+			b.x1, b.y1 = ceilDiv(tc.x1-ox, bs), ceilDiv(tc.y1-oy, bs)
+			res.buildPrecincts(b, r, tc.cs)
+			res.bands = append(res.bands, b)
+		}
+
+		tc.res = append(tc.res, res)
+	}
+
+	return nil
+}
+
+// buildPrecincts partitions band b of resolution r into precincts and code-blocks.
+func (res *jpxResolution) buildPrecincts(b *jpxBand, r int, cs *jpxCodingStyle) {
+	ppx, ppy := res.ppx, res.ppy
+	if r > 0 {
+		ppx, ppy = ppx-1, ppy-1
+	}
+	xcb, ycb := min(cs.xcb, ppx), min(cs.ycb, ppy)
+	px0, py0 := res.x0>>res.ppx<<ppx, res.y0>>res.ppy<<ppy
+
+	b.precincts = make([]*jpxPrecinct, res.pw*res.ph)
+
+	for j := 0; j < res.ph; j++ {
+		for i := 0; i < res.pw; i++ {
+			p := &jpxPrecinct{}
+			b.precincts[j*res.pw+i] = p
+
+			x0 := max(px0+i<<ppx, b.x0)
+			y0 := max(py0+j<<ppy, b.y0)
+			x1 := min(px0+(i+1)<<ppx, b.x1)
+			y1 := min(py0+(j+1)<<ppy, b.y1)
+			if x1 <= x0 || y1 <= y0 {
+				continue
+			}
+
+			cx0, cy0 := x0>>xcb, y0>>ycb
+			cx1, cy1 := ceilDiv(x1, 1<<xcb), ceilDiv(y1, 1<<ycb)
+			p.cw, p.ch = cx1-cx0, cy1-cy0
+
+			for cy := cy0; cy < cy1; cy++ {
+				for cx := cx0; cx < cx1; cx++ {
+					p.blocks = append(p.blocks, &jpxCodeBlock{
+						x0:     max(cx<<xcb, x0),
+						y0:     max(cy<<ycb, y0),
+						x1:     min((cx+1)<<xcb, x1),
+						y1:     min((cy+1)<<ycb, y1),
+						lblock: 3,
+					})
+				}
+			}
+
+			p.incl = newJPXTagTree(p.cw, p.ch)
+			p.zero = newJPXTagTree(p.cw, p.ch)
+		}
+	}
+}
+
+// Tag trees (B.10.2)
+
+type jpxTagNode struct {
+	parent *jpxTagNode
+	value  int
+	low    int
+}
+
+type jpxTagTree struct {
+	leaves []*jpxTagNode
+}
+
+const jpxTagUndefined = 1 << 30
+
+func newJPXTagTree(w, h int) *jpxTagTree {
+	var level []*jpxTagNode
+	for i := 0; i < w*h; i++ {
+		level = append(level, &jpxTagNode{value: jpxTagUndefined})
+	}
+	t := &jpxTagTree{leaves: level}
+
+	for w > 1 || h > 1 {
+		pw, ph := (w+1)/2, (h+1)/2
+		parents := make([]*jpxTagNode, pw*ph)
+		for i := range parents {
+			parents[i] = &jpxTagNode{value: jpxTagUndefined}
+		}
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				level[y*w+x].parent = parents[(y/2)*pw+x/2]
+			}
+		}
+		level, w, h = parents, pw, ph
+	}
+
+	return t
+}
+
+// decode reads tag tree bits for leaf i until its value is known to be >= threshold or determined.
+// It returns true if the value of leaf i is less than threshold.
+func (t *jpxTagTree) decode(br *jpxBitReader, i, threshold int) (bool, error) {
+	var path []*jpxTagNode
+	for n := t.leaves[i]; n != nil; n = n.parent {
+		path = append(path, n)
+	}
+
+	low := 0
+	for k := len(path) - 1; k >= 0; k-- {
+		n := path[k]
+		if low > n.low {
+			n.low = low
+		} else {
+			low = n.low
+		}
+		for low < threshold && low < n.value {
+			bit, err := br.readBit()
+			if err != nil {
+				return false, err
+			}
+			if bit == 1 {
+				n.value = low
+			} else {
+				low++
+			}
+		}
+		n.low = low
+	}
+
+	return t.leaves[i].value < threshold, nil
+}
+
+// jpxBitReader reads packet header bits honouring bit stuffing after 0xFF (B.10.1).
+type jpxBitReader struct {
+	data []byte
+	pos  int
+	cur  byte
+	n    int
+}
+
+func (br *jpxBitReader) readBit() (int, error) {
+	if br.n == 0 {
+		if br.pos >= len(br.data) {
+			return 0, errJPXEOD
+		}
+		prev := br.cur
+		br.cur = br.data[br.pos]
+		br.pos++
+		br.n = 8
+		if prev == 0xFF {
+			br.n = 7
+		}
+	}
+	br.n--
+	return int(br.cur>>br.n) & 1, nil
+}
+
+func (br *jpxBitReader) readBits(n int) (int, error) {
+	v := 0
+	for ; n > 0; n-- {
+		bit, err := br.readBit()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<1 | bit
+	}
+	return v, nil
+}
+
+// align skips the remaining bits of the current byte including a stuffed byte following 0xFF.
+func (br *jpxBitReader) align() {
+	if br.cur == 0xFF {
+		br.pos++
+	}
+	br.cur, br.n = 0, 0
+}
+
+// skipMarker skips marker m if present at the current byte position.
+func (br *jpxBitReader) skipMarker(m, l int) {
+	if br.pos+2 <= len(br.data) && int(binary.BigEndian.Uint16(br.data[br.pos:])) == m {
+		br.pos += l
+	}
+}
+
+// Tier-2 decoding (B.9 - B.12)
+
+type jpxPacket struct {
+	c, r, p, l int
+}
+
+// precinctPosition returns the reference grid position at which precinct p of resolution r of component c is visited by position driven progressions (B.12.1.3).
+func (t *jpxTile) precinctPosition(siz *jpxSize, c, r, p int) (int, int) {
+	tc := t.comps[c]
+	res := tc.res[r]
+	comp := siz.comps[c]
+	sh := tc.cs.levels - r
+	i, j := p%res.pw, p/res.pw
+
+	x := t.x0
+	if i > 0 || res.x0&(1<<res.ppx-1) == 0 {
+		x = max(x, (res.x0>>res.ppx+i)<<res.ppx<<sh*comp.dx)
+	}
+	y := t.y0
+	if j > 0 || res.y0&(1<<res.ppy-1) == 0 {
+		y = max(y, (res.y0>>res.ppy+j)<<res.ppy<<sh*comp.dy)
+	}
+	return x, y
+}
+
+// packets returns the sequence of packets of tile t according to its progression order (B.12).
+func (t *jpxTile) packets(siz *jpxSize) []jpxPacket {
+	type prec struct {
+		c, r, p int
+		x, y    int
+	}
+
+	var pp []prec
+	maxRes := 0
+	for c, tc := range t.comps {
+		for r, res := range tc.res {
+			maxRes = max(maxRes, r)
+			for p := 0; p < res.pw*res.ph; p++ {
+				x, y := t.precinctPosition(siz, c, r, p)
+				pp = append(pp, prec{c, r, p, x, y})
+			}
+		}
+	}
+
+	layers := t.cs.layers
+	var packets []jpxPacket
+
+	switch t.cs.progression {
+
+	case 0: // LRCP
+		for l := 0; l < layers; l++ {
+			for r := 0; r <= maxRes; r++ {
+				for _, p := range pp {
+					if p.r == r {
+						packets = append(packets, jpxPacket{p.c, p.r, p.p, l})
+					}
+				}
+			}
+		}
+		return packets
+
+	case 1: // RLCP
+		for r := 0; r <= maxRes; r++ {
+			for l := 0; l < layers; l++ {
+				for _, p := range pp {
+					if p.r == r {
+						packets = append(packets, jpxPacket{p.c, p.r, p.p, l})
+					}
+				}
+			}
+		}
+		return packets
+
+	case 2: // RPCL
+		sort.SliceStable(pp, func(i, j int) bool {
+			a, b := pp[i], pp[j]
+			if a.r != b.r {
+				return a.r < b.r
+			}
+			if a.y != b.y {
+				return a.y < b.y
+			}
+			if a.x != b.x {
+				return a.x < b.x
+			}
+			return a.c < b.c
+		})
+
+	case 3: // PCRL
+		sort.SliceStable(pp, func(i, j int) bool {
+			a, b := pp[i], pp[j]
+			if a.y != b.y {
+				return a.y < b.y
+			}
+			if a.x != b.x {
+				return a.x < b.x
+			}
+			if a.c != b.c {
+				return a.c < b.c
+			}
+			return a.r < b.r
+		})
+
+	case 4: // CPRL
+		sort.SliceStable(pp, func(i, j int) bool {
+			a, b := pp[i], pp[j]
+			if a.c != b.c {
+				return a.c < b.c
+			}
+			if a.y != b.y {
+				return a.y < b.y
+			}
+			if a.x != b.x {
+				return a.x < b.x
+			}
+			return a.r < b.r
+		})
+	}
+
+	for _, p := range pp {
+		for l := 0; l < layers; l++ {
+			packets = append(packets, jpxPacket{p.c, p.r, p.p, l})
+		}
+	}
+
+	return packets
+}
+
+// segmentEnd returns the index of the last coding pass of the codeword segment containing pass p (D.4.1).
+func segmentEnd(cbStyle, p int) int {
+	switch {
+	case cbStyle&jpxTermAll != 0:
+		return p
+	case cbStyle&jpxBypass != 0:
+		if p < 10 {
+			return 9
+		}
+		if (p-10)%3 == 0 {
+			return p + 1
+		}
+		return p
+	}
+	return 1 << 30
+}
+
+func log2Floor(n int) int {
+	l := 0
+	for n > 1 {
+		n >>= 1
+		l++
+	}
+	return l
+}
+
+// readCodingPasses decodes the number of coding passes (Table B.4).
+func readCodingPasses(br *jpxBitReader) (int, error) {
+	steps := []struct{ bits, limit, offset int }{{1, 1, 1}, {1, 1, 2}, {2, 3, 3}, {5, 31, 6}, {7, 128, 37}}
+	for _, s := range steps {
+		v, err := br.readBits(s.bits)
+		if err != nil {
+			return 0, err
+		}
+		if v < s.limit {
+			return v + s.offset, nil
+		}
+	}
+	return 0, errors.New("pdfcpu: jpx: invalid number of coding passes")
+}
+
+type jpxContribution struct {
+	cb      *jpxCodeBlock
+	lengths []int
+	passes  []int
+}
+
+// readPacketHeader reads the header of packet pk (B.10).
+func (t *jpxTile) readPacketHeader(br *jpxBitReader, pk jpxPacket) ([]jpxContribution, error) {
+	tc := t.comps[pk.c]
+	res := tc.res[pk.r]
+	cbStyle := tc.cs.cbStyle
+
+	bit, err := br.readBit()
+	if err != nil || bit == 0 {
+		return nil, err
+	}
+
+	var cc []jpxContribution
+
+	for _, b := range res.bands {
+		p := b.precincts[pk.p]
+		for i, cb := range p.blocks {
+
+			var included bool
+			if cb.included {
+				if bit, err = br.readBit(); err != nil {
+					return nil, err
+				}
+				included = bit == 1
+			} else if included, err = p.incl.decode(br, i, pk.l+1); err != nil {
+				return nil, err
+			}
+
+			if !included {
+				continue
+			}
+
+			if !cb.included {
+				// First inclusion: decode the number of missing most significant bit-planes.
+				for th := 1; ; th++ {
+					ok, err := p.zero.decode(br, i, th)
+					if err != nil {
+						return nil, err
+					}
+					if ok {
+						break
+					}
+				}
+				cb.zeroPlanes = p.zero.leaves[i].value
+				cb.included = true
+			}
+
+			n, err := readCodingPasses(br)
+			if err != nil {
+				return nil, err
+			}
+
+			for {
+				if bit, err = br.readBit(); err != nil {
+					return nil, err
+				}
+				if bit == 0 {
+					break
+				}
+				cb.lblock++
+			}
+
+			c := jpxContribution{cb: cb}
+			for pass := cb.passes; n > 0; {
+				k := min(n, segmentEnd(cbStyle, pass)-pass+1)
+				l, err := br.readBits(cb.lblock + log2Floor(k))
+				if err != nil {
+					return nil, err
+				}
+				c.lengths = append(c.lengths, l)
+				c.passes = append(c.passes, k)
+				pass += k
+				n -= k
+			}
+			cc = append(cc, c)
+		}
+	}
+
+	return cc, nil
+}
+
+// addSegmentData appends the code-block contribution of k passes to the codeword segments of cb.
+func (cb *jpxCodeBlock) addSegmentData(cbStyle int, data []byte, k int) {
+	if cb.passes == 0 || segmentEnd(cbStyle, cb.passes-1) == cb.passes-1 {
+		cb.segs = append(cb.segs, jpxCodeSegment{})
+	}
+	s := &cb.segs[len(cb.segs)-1]
+	s.data = append(s.data, data...)
+	s.passes += k
+	cb.passes += k
+}
+
+// readPackets reads all packets of tile t from its tile-part bodies.
+func (t *jpxTile) readPackets(siz *jpxSize, th *jpxTileHeader) error {
+	body := &jpxBitReader{data: th.body}
+	hdr := body
+	if th.packed {
+		hdr = &jpxBitReader{data: th.headers}
+	}
+
+	for _, pk := range t.packets(siz) {
+		if body.pos >= len(body.data) {
+			// Truncated codestream
+			break
+		}
+
+		if t.cs.sop {
+			body.skipMarker(jpxSOP, 6)
+		}
+
+		cc, err := t.readPacketHeader(hdr, pk)
+		if err == errJPXEOD {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		hdr.align()
+		if t.cs.eph {
+			hdr.skipMarker(jpxEPH, 2)
+		}
+
+		cbStyle := t.comps[pk.c].cs.cbStyle
+		for _, c := range cc {
+			for i, l := range c.lengths {
+				end := min(body.pos+l, len(body.data))
+				c.cb.addSegmentData(cbStyle, body.data[body.pos:end], c.passes[i])
+				body.pos = end
+			}
+		}
+	}
+
+	return nil
+}
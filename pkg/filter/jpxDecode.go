@@ -0,0 +1,472 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+type jpxDecode struct {
+	baseFilter
+}
+
+// JPXInfo describes a JPEG 2000 image as delivered by the JPXDecode filter.
+type JPXInfo struct {
+	Width, Height int
+	Comps         int    // Number of color components of the decoded samples.
+	Bpc           int    // Bit depth of the encoded image. Decoded samples always use 8 bits per component.
+	ColorSpace    string // DeviceGray, DeviceRGB, DeviceCMYK or empty if unknown.
+}
+
+// JP2 box types (ITU-T T.800 Annex I)
+const (
+	jp2BoxHeader     = 0x6A703268 // jp2h
+	jp2BoxColour     = 0x636F6C72 // colr
+	jp2BoxPalette    = 0x70636C72 // pclr
+	jp2BoxCompMap    = 0x636D6170 // cmap
+	jp2BoxChannelDef = 0x63646566 // cdef
+	jp2BoxCodestream = 0x6A703263 // jp2c
+)
+
+// Enumerated colour spaces (Table I.10)
+const (
+	jp2CMYK  = 12
+	jp2SRGB  = 16
+	jp2SGray = 17
+	jp2SYCC  = 18
+)
+
+type jp2Channel struct {
+	comp   int
+	column int // palette column or -1
+}
+
+type jp2Palette struct {
+	bits    []int
+	entries [][]int
+}
+
+type jp2File struct {
+	colourSpace int
+	palette     *jp2Palette
+	channels    []jp2Channel
+	channelDefs [][3]int
+	codestream  []byte
+}
+
+// Encode implements encoding for a JPXDecode filter.
+func (f jpxDecode) Encode(r io.Reader) (io.Reader, error) {
+	// Not supported.
+	return nil, nil
+}
+
+// Decode implements decoding for a JPXDecode filter.
+func (f jpxDecode) Decode(r io.Reader) (io.Reader, error) {
+	return f.DecodeLength(r, -1)
+}
+
+// DecodeLength decodes a JPEG 2000 codestream or JP2 file into interleaved 8 bit color samples.
+// Alpha channels are dropped.
+func (f jpxDecode) DecodeLength(r io.Reader, maxLen int64) (io.Reader, error) {
+	bb, err := getReaderBytes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	jf, err := parseJP2(bb)
+	if err != nil {
+		return nil, err
+	}
+
+	cs, err := parseCodestream(jf.codestream, false)
+	if err != nil {
+		return nil, err
+	}
+
+	comps, err := cs.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(jf.samples(&cs.siz, comps)), nil
+}
+
+// DecodeJPXInfo returns information about the JPEG 2000 image bb without decoding it.
+func DecodeJPXInfo(bb []byte) (*JPXInfo, error) {
+	jf, err := parseJP2(bb)
+	if err != nil {
+		return nil, err
+	}
+
+	cs, err := parseCodestream(jf.codestream, true)
+	if err != nil {
+		return nil, err
+	}
+
+	siz := &cs.siz
+	info := &JPXInfo{
+		Width:  siz.x1 - siz.x0,
+		Height: siz.y1 - siz.y0,
+		Bpc:    siz.comps[0].prec,
+	}
+	if jf.palette != nil && len(jf.palette.bits) > 0 {
+		info.Bpc = jf.palette.bits[0]
+	}
+
+	info.Comps, _ = jf.colorChannels(siz)
+	switch info.Comps {
+	case 1:
+		info.ColorSpace = "DeviceGray"
+	case 3:
+		info.ColorSpace = "DeviceRGB"
+	case 4:
+		info.ColorSpace = "DeviceCMYK"
+	}
+
+	return info, nil
+}
+
+// parseJP2 parses the boxes of a JP2 file. A raw codestream is returned as is.
+func parseJP2(bb []byte) (*jp2File, error) {
+	jf := &jp2File{}
+
+	if len(bb) >= 2 && binary.BigEndian.Uint16(bb) == jpxSOC {
+		jf.codestream = bb
+		return jf, nil
+	}
+
+	if err := jf.parseBoxes(bb); err != nil {
+		return nil, err
+	}
+
+	if jf.codestream == nil {
+		return nil, errors.New("pdfcpu: jpx: missing codestream")
+	}
+
+	return jf, nil
+}
+
+func (jf *jp2File) parseBoxes(bb []byte) error {
+	for len(bb) > 0 {
+		if len(bb) < 8 {
+			return errJPXEOD
+		}
+
+		l := uint64(binary.BigEndian.Uint32(bb))
+		typ := binary.BigEndian.Uint32(bb[4:])
+		hl := uint64(8)
+
+		switch l {
+		case 0:
+			l = uint64(len(bb))
+		case 1:
+			if len(bb) < 16 {
+				return errJPXEOD
+			}
+			l, hl = binary.BigEndian.Uint64(bb[8:]), 16
+		}
+		if l < hl || l > uint64(len(bb)) {
+			return errors.New("pdfcpu: jpx: invalid box length")
+		}
+
+		data := bb[hl:l]
+		bb = bb[l:]
+
+		var err error
+
+		switch typ {
+
+		case jp2BoxHeader:
+			err = jf.parseBoxes(data)
+
+		case jp2BoxColour:
+			// Only the first colour specification is used.
+			if len(data) >= 7 && jf.colourSpace == 0 && data[0] == 1 {
+				jf.colourSpace = int(binary.BigEndian.Uint32(data[3:]))
+			}
+
+		case jp2BoxPalette:
+			jf.palette, err = parsePalette(data)
+
+		case jp2BoxCompMap:
+			for ; len(data) >= 4; data = data[4:] {
+				c := jp2Channel{comp: int(binary.BigEndian.Uint16(data)), column: -1}
+				if data[2] == 1 {
+					c.column = int(data[3])
+				}
+				jf.channels = append(jf.channels, c)
+			}
+
+		case jp2BoxChannelDef:
+			if len(data) >= 2 {
+				n := int(binary.BigEndian.Uint16(data))
+				for data = data[2:]; n > 0 && len(data) >= 6; n, data = n-1, data[6:] {
+					jf.channelDefs = append(jf.channelDefs, [3]int{
+						int(binary.BigEndian.Uint16(data)),
+						int(binary.BigEndian.Uint16(data[2:])),
+						int(binary.BigEndian.Uint16(data[4:])),
+					})
+				}
+			}
+
+		case jp2BoxCodestream:
+			if jf.codestream == nil {
+				jf.codestream = data
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parsePalette parses a palette box (I.5.3.4).
+func parsePalette(data []byte) (*jp2Palette, error) {
+	if len(data) < 3 {
+		return nil, errJPXEOD
+	}
+
+	ne, npc := int(binary.BigEndian.Uint16(data)), int(data[2])
+	data = data[3:]
+	if len(data) < npc {
+		return nil, errJPXEOD
+	}
+
+	p := &jp2Palette{}
+	for _, b := range data[:npc] {
+		p.bits = append(p.bits, int(b&0x7F)+1)
+	}
+	data = data[npc:]
+
+	for i := 0; i < ne; i++ {
+		e := make([]int, npc)
+		for j, bits := range p.bits {
+			n := (bits + 7) / 8
+			if len(data) < n {
+				return nil, errJPXEOD
+			}
+			for _, b := range data[:n] {
+				e[j] = e[j]<<8 | int(b)
+			}
+			data = data[n:]
+		}
+		p.entries = append(p.entries, e)
+	}
+
+	return p, nil
+}
+
+// decode decodes all tiles of cs and returns the samples of each component.
+func (cs *jpxCodestream) decode() ([][]int32, error) {
+	siz := &cs.siz
+
+	comps := make([][]int32, len(siz.comps))
+	for c, comp := range siz.comps {
+		comps[c] = make([]int32, comp.w*comp.h)
+	}
+
+	for i, th := range cs.tiles {
+		t, err := cs.newTile(i, th)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := t.readPackets(siz, th); err != nil {
+			return nil, err
+		}
+
+		for c, tc := range t.comps {
+			tc.decode(siz.comps[c].prec)
+		}
+
+		if t.cs.mct && len(t.comps) >= 3 {
+			t.inverseMCT()
+		}
+
+		for c, tc := range t.comps {
+			comp := siz.comps[c]
+			hi := float64(int(1)<<comp.prec - 1)
+			shift := float64(int(1) << (comp.prec - 1))
+			w := tc.x1 - tc.x0
+			for y := tc.y0; y < tc.y1; y++ {
+				for x := tc.x0; x < tc.x1; x++ {
+					v := math.Round(tc.data[(y-tc.y0)*w+x-tc.x0]) + shift
+					comps[c][(y-comp.y0)*comp.w+x-comp.x0] = int32(max(0, min(hi, v)))
+				}
+			}
+		}
+	}
+
+	return comps, nil
+}
+
+// sampleFunc returns the unsigned sample of a channel at reference grid position x, y along with its bit depth.
+type sampleFunc func(x, y int) int
+
+// channelSamples returns sample functions for the channels of jf and their bit depths.
+func (jf *jp2File) channelSamples(siz *jpxSize, comps [][]int32) ([]sampleFunc, []int) {
+	component := func(c int) sampleFunc {
+		comp := siz.comps[c]
+		data := comps[c]
+		return func(x, y int) int {
+			u := min(max(x/comp.dx-comp.x0, 0), comp.w-1)
+			v := min(max(y/comp.dy-comp.y0, 0), comp.h-1)
+			return int(data[v*comp.w+u])
+		}
+	}
+
+	var (
+		ff   []sampleFunc
+		bits []int
+	)
+
+	if jf.palette == nil || len(jf.channels) == 0 {
+		for c, comp := range siz.comps {
+			ff = append(ff, component(c))
+			bits = append(bits, comp.prec)
+		}
+		return ff, bits
+	}
+
+	p := jf.palette
+	for _, ch := range jf.channels {
+		if ch.comp >= len(siz.comps) {
+			continue
+		}
+		f := component(ch.comp)
+		if ch.column < 0 || ch.column >= len(p.bits) || len(p.entries) == 0 {
+			ff = append(ff, f)
+			bits = append(bits, siz.comps[ch.comp].prec)
+			continue
+		}
+		col := ch.column
+		ff = append(ff, func(x, y int) int {
+			return p.entries[min(f(x, y), len(p.entries)-1)][col]
+		})
+		bits = append(bits, p.bits[col])
+	}
+
+	return ff, bits
+}
+
+// colorChannels returns the number of color channels and the indices of the channels in color order.
+func (jf *jp2File) colorChannels(siz *jpxSize) (int, []int) {
+	n := len(siz.comps)
+	if jf.palette != nil && len(jf.channels) > 0 {
+		n = len(jf.channels)
+	}
+
+	order := make([]int, 0, n)
+	if len(jf.channelDefs) > 0 {
+		assoc := make([]int, n)
+		for i := range assoc {
+			assoc[i] = -1
+		}
+		var rest []int
+		for _, cd := range jf.channelDefs {
+			ch, typ, asoc := cd[0], cd[1], cd[2]
+			if ch >= n || typ != 0 {
+				// Skip opacity channels.
+				continue
+			}
+			if asoc >= 1 && asoc <= n && assoc[asoc-1] < 0 {
+				assoc[asoc-1] = ch
+			} else {
+				rest = append(rest, ch)
+			}
+		}
+		for _, ch := range assoc {
+			if ch >= 0 {
+				order = append(order, ch)
+			}
+		}
+		order = append(order, rest...)
+	} else {
+		for i := 0; i < n; i++ {
+			order = append(order, i)
+		}
+	}
+
+	want := len(order)
+	switch jf.colourSpace {
+	case jp2SGray:
+		want = 1
+	case jp2SRGB, jp2SYCC:
+		want = 3
+	case jp2CMYK:
+		want = 4
+	default:
+		if want == 2 {
+			// Gray with alpha
+			want = 1
+		}
+		want = min(want, 4)
+	}
+
+	if want > len(order) {
+		want = len(order)
+	}
+
+	return want, order[:want]
+}
+
+func scaleTo8(v, bits int) byte {
+	switch {
+	case bits == 8:
+		return byte(v)
+	case bits > 8:
+		return byte(v >> (bits - 8))
+	}
+	return byte(v * 255 / (1<<bits - 1))
+}
+
+// samples returns the interleaved 8 bit color samples of the decoded components.
+func (jf *jp2File) samples(siz *jpxSize, comps [][]int32) []byte {
+	ff, bits := jf.channelSamples(siz, comps)
+	n, order := jf.colorChannels(siz)
+
+	w, h := siz.x1-siz.x0, siz.y1-siz.y0
+	bb := make([]byte, 0, w*h*n)
+
+	for y := siz.y0; y < siz.y1; y++ {
+		for x := siz.x0; x < siz.x1; x++ {
+			for _, ch := range order {
+				bb = append(bb, scaleTo8(ff[ch](x, y), bits[ch]))
+			}
+		}
+	}
+
+	if jf.colourSpace == jp2SYCC && n == 3 {
+		clamp := func(v float64) byte { return byte(max(0, min(255, math.Round(v)))) }
+		for i := 0; i+2 < len(bb); i += 3 {
+			y, cb, cr := float64(bb[i]), float64(bb[i+1])-128, float64(bb[i+2])-128
+			bb[i] = clamp(y + 1.402*cr)
+			bb[i+1] = clamp(y - 0.34413*cb - 0.71414*cr)
+			bb[i+2] = clamp(y + 1.772*cb)
+		}
+	}
+
+	return bb
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"image"
+	_ "image/png"
+	"io"
+	"math"
+	"os"
+	"testing"
+)
+
+// analyze1D implements the forward 1D subband decomposition (F.4.8) for signal x starting at i0.
+func analyze1D(x []float64, i0 int, reversible bool) {
+	n := len(x)
+	if n == 1 {
+		if i0&1 == 1 {
+			x[0] *= 2
+		}
+		return
+	}
+
+	const pad = 4
+	e := make([]float64, n+2*pad)
+	for k := -pad; k < n+pad; k++ {
+		e[k+pad] = x[reflect(k, n)]
+	}
+
+	lift := func(from, to int, odd bool, f func(k int) float64) {
+		for k := from; k < to; k++ {
+			if (i0+k)&1 == 1 == odd {
+				e[k+pad] = f(k + pad)
+			}
+		}
+	}
+
+	if reversible {
+		lift(-3, n+3, true, func(k int) float64 { return e[k] - math.Floor((e[k-1]+e[k+1])/2) })
+		lift(-2, n+2, false, func(k int) float64 { return e[k] + math.Floor((e[k-1]+e[k+1]+2)/4) })
+	} else {
+		lift(-3, n+3, true, func(k int) float64 { return e[k] + jpxAlpha*(e[k-1]+e[k+1]) })
+		lift(-2, n+2, false, func(k int) float64 { return e[k] + jpxBeta*(e[k-1]+e[k+1]) })
+		lift(-1, n+1, true, func(k int) float64 { return e[k] + jpxGamma*(e[k-1]+e[k+1]) })
+		lift(0, n, false, func(k int) float64 { return e[k] + jpxDelta*(e[k-1]+e[k+1]) })
+		lift(0, n, false, func(k int) float64 { return e[k] / jpxK })
+		lift(0, n, true, func(k int) float64 { return e[k] * jpxK })
+	}
+
+	copy(x, e[pad:pad+n])
+}
+
+func TestJPXSynthesis(t *testing.T) {
+	for _, reversible := range []bool{true, false} {
+		for n := 1; n <= 9; n++ {
+			for i0 := 0; i0 < 2; i0++ {
+				want := make([]float64, n)
+				for i := range want {
+					want[i] = float64((i*37 + 11) % 23)
+				}
+
+				x := append([]float64(nil), want...)
+				analyze1D(x, i0, reversible)
+				synthesize1D(x, i0, reversible, make([]float64, n+8))
+
+				for i := range x {
+					if math.Abs(x[i]-want[i]) > 1e-9 {
+						t.Fatalf("reversible=%t n=%d i0=%d: got %v, want %v", reversible, n, i0, x, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestJPXDecode(t *testing.T) {
+	bb, err := os.ReadFile("../testdata/resources/mountain.jpx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := DecodeJPXInfo(bb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *info != (JPXInfo{Width: 1667, Height: 2646, Comps: 3, Bpc: 8, ColorSpace: "DeviceRGB"}) {
+		t.Fatalf("unexpected info: %+v", *info)
+	}
+
+	f, err := NewFilter(JPX, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := f.Decode(bytes.NewReader(bb))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != info.Width*info.Height*info.Comps {
+		t.Fatalf("got %d bytes, want %d", len(got), info.Width*info.Height*info.Comps)
+	}
+
+	// Compare against the PNG version of the same image which only differs in a small text label.
+	fp, err := os.Open("../testdata/resources/mountain.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fp.Close()
+
+	im, _, err := image.Decode(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var diff int
+	for y := 0; y < info.Height; y++ {
+		for x := 0; x < info.Width; x++ {
+			r, g, b, _ := im.At(x, y).RGBA()
+			i := 3 * (y*info.Width + x)
+			for k, v := range []uint32{r >> 8, g >> 8, b >> 8} {
+				diff += abs(int(got[i+k]) - int(v))
+			}
+		}
+	}
+
+	if mean := float64(diff) / float64(len(got)); mean > 1.5 {
+		t.Fatalf("mean sample difference too large: %.2f", mean)
+	}
+}
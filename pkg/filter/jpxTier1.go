@@ -0,0 +1,385 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+// Coefficient bit modeling as specified in ITU-T T.800 Annex D.
+
+// Context labels (D.3)
+const (
+	jpxCtxSign    = 9
+	jpxCtxMag     = 14
+	jpxCtxRun     = 17
+	jpxCtxUniform = 18
+	jpxContexts   = 19
+)
+
+// Coefficient state flags
+const (
+	jpxSig     = 1 << iota // significant
+	jpxVisited             // coded in the current bit-plane
+	jpxRefined             // magnitude refined at least once
+	jpxNeg                 // negative sign
+)
+
+// Significance propagation context labels (Table D.1) indexed by d<<4 | v<<2 | h,
+// the number of significant diagonal, vertical and horizontal neighbours.
+var jpxZeroContexts [3][80]uint8
+
+func init() {
+	for d := 0; d <= 4; d++ {
+		for v := 0; v <= 2; v++ {
+			for h := 0; h <= 2; h++ {
+				i := d<<4 | v<<2 | h
+				jpxZeroContexts[0][i] = zeroContextLL(h, v, d)
+				jpxZeroContexts[1][i] = zeroContextLL(v, h, d)
+				jpxZeroContexts[2][i] = zeroContextHH(h+v, d)
+			}
+		}
+	}
+}
+
+// zeroContextLL returns the context label for LL and LH subbands, HL subbands swap h and v.
+func zeroContextLL(h, v, d int) uint8 {
+	switch {
+	case h == 2:
+		return 8
+	case h == 1 && v >= 1:
+		return 7
+	case h == 1 && d >= 1:
+		return 6
+	case h == 1:
+		return 5
+	case v == 2:
+		return 4
+	case v == 1:
+		return 3
+	case d >= 2:
+		return 2
+	case d == 1:
+		return 1
+	}
+	return 0
+}
+
+func zeroContextHH(hv, d int) uint8 {
+	switch {
+	case d >= 3:
+		return 8
+	case d == 2 && hv >= 1:
+		return 7
+	case d == 2:
+		return 6
+	case d == 1 && hv >= 2:
+		return 5
+	case d == 1 && hv == 1:
+		return 4
+	case d == 1:
+		return 3
+	case hv >= 2:
+		return 2
+	case hv == 1:
+		return 1
+	}
+	return 0
+}
+
+// jpxRawDecoder reads the raw bits of coding passes in selective arithmetic coding bypass mode (D.6).
+type jpxRawDecoder struct {
+	data []byte
+	pos  int
+	cur  byte
+	n    int
+}
+
+func (d *jpxRawDecoder) readBit() int {
+	if d.n == 0 {
+		prev := d.cur
+		d.cur = 0xFF
+		if d.pos < len(d.data) {
+			d.cur = d.data[d.pos]
+			d.pos++
+		}
+		d.n = 8
+		if prev == 0xFF {
+			d.n = 7
+		}
+	}
+	d.n--
+	return int(d.cur>>d.n) & 1
+}
+
+// jpxCodeBlockDecoder holds the state of a code-block during coefficient bit modeling.
+type jpxCodeBlockDecoder struct {
+	w, h    int
+	flags   []uint8
+	nbr     []uint8
+	mag     []int64
+	planes  []uint8
+	zc      *[80]uint8
+	cc      arithContexts
+	ad      *arithDecoder
+	raw     *jpxRawDecoder
+	vcausal bool
+}
+
+func newCodeBlockDecoder(w, h, band int, vcausal bool) *jpxCodeBlockDecoder {
+	t := &jpxCodeBlockDecoder{
+		w:       w,
+		h:       h,
+		flags:   make([]uint8, w*h),
+		nbr:     make([]uint8, w*h),
+		mag:     make([]int64, w*h),
+		planes:  make([]uint8, w*h),
+		cc:      newArithContexts(jpxContexts),
+		vcausal: vcausal,
+	}
+
+	switch band {
+	case jpxHL:
+		t.zc = &jpxZeroContexts[1]
+	case jpxHH:
+		t.zc = &jpxZeroContexts[2]
+	default:
+		t.zc = &jpxZeroContexts[0]
+	}
+
+	t.resetContexts()
+	return t
+}
+
+// resetContexts sets all contexts to their initial states (Table D.7).
+func (t *jpxCodeBlockDecoder) resetContexts() {
+	for i := range t.cc {
+		t.cc[i] = 0
+	}
+	t.cc[0] = 4 << 1
+	t.cc[jpxCtxRun] = 3 << 1
+	t.cc[jpxCtxUniform] = 46 << 1
+}
+
+func (t *jpxCodeBlockDecoder) bit(raw bool, cx int) int {
+	if raw {
+		return t.raw.readBit()
+	}
+	return t.ad.decodeBit(t.cc, cx)
+}
+
+// setSignificant marks coefficient x,y significant and updates the neighbourhood of its neighbours.
+func (t *jpxCodeBlockDecoder) setSignificant(x, y int, neg bool) {
+	i := y*t.w + x
+	t.flags[i] |= jpxSig
+	if neg {
+		t.flags[i] |= jpxNeg
+	}
+	t.mag[i] = 1
+
+	for dy := -1; dy <= 1; dy++ {
+		ny := y + dy
+		if ny < 0 || ny >= t.h {
+			continue
+		}
+		if dy < 0 && t.vcausal && y%4 == 0 {
+			// Stripe causal mode hides this coefficient from the stripe above.
+			continue
+		}
+		for dx := -1; dx <= 1; dx++ {
+			nx := x + dx
+			if nx < 0 || nx >= t.w || dx == 0 && dy == 0 {
+				continue
+			}
+			switch {
+			case dy == 0:
+				t.nbr[ny*t.w+nx]++
+			case dx == 0:
+				t.nbr[ny*t.w+nx] += 4
+			default:
+				t.nbr[ny*t.w+nx] += 16
+			}
+		}
+	}
+}
+
+// signContribution returns the contribution of neighbour x,y to the sign context (Table D.2).
+func (t *jpxCodeBlockDecoder) signContribution(x, y int) int {
+	if x < 0 || x >= t.w || y < 0 || y >= t.h {
+		return 0
+	}
+	f := t.flags[y*t.w+x]
+	switch {
+	case f&jpxSig == 0:
+		return 0
+	case f&jpxNeg != 0:
+		return -1
+	}
+	return 1
+}
+
+// decodeSign decodes the sign of coefficient x,y (D.3.2) and makes it significant.
+func (t *jpxCodeBlockDecoder) decodeSign(x, y int, raw bool) {
+	if raw {
+		t.setSignificant(x, y, t.raw.readBit() == 1)
+		return
+	}
+
+	clamp := func(v int) int { return max(-1, min(1, v)) }
+
+	h := clamp(t.signContribution(x-1, y) + t.signContribution(x+1, y))
+	below := 0
+	if !t.vcausal || y%4 != 3 {
+		below = t.signContribution(x, y+1)
+	}
+	v := clamp(t.signContribution(x, y-1) + below)
+
+	// Table D.3
+	cx, xor := 12+h*v, 0
+	if h == 0 {
+		cx = jpxCtxSign + max(v, -v)
+		if v < 0 {
+			xor = 1
+		}
+	} else if h < 0 {
+		xor = 1
+	}
+
+	t.setSignificant(x, y, t.ad.decodeBit(t.cc, cx)^xor == 1)
+}
+
+// significancePass implements the significance propagation decoding pass (D.3.1).
+func (t *jpxCodeBlockDecoder) significancePass(raw bool) {
+	for y0 := 0; y0 < t.h; y0 += 4 {
+		for x := 0; x < t.w; x++ {
+			for y := y0; y < min(y0+4, t.h); y++ {
+				i := y*t.w + x
+				if t.flags[i]&jpxSig != 0 || t.nbr[i] == 0 {
+					continue
+				}
+				t.flags[i] |= jpxVisited
+				t.planes[i]++
+				if t.bit(raw, int(t.zc[t.nbr[i]])) == 1 {
+					t.decodeSign(x, y, raw)
+				}
+			}
+		}
+	}
+}
+
+// refinementPass implements the magnitude refinement decoding pass (D.3.3).
+func (t *jpxCodeBlockDecoder) refinementPass(raw bool) {
+	for y0 := 0; y0 < t.h; y0 += 4 {
+		for x := 0; x < t.w; x++ {
+			for y := y0; y < min(y0+4, t.h); y++ {
+				i := y*t.w + x
+				if t.flags[i]&(jpxSig|jpxVisited) != jpxSig {
+					continue
+				}
+				cx := jpxCtxMag + 2
+				if t.flags[i]&jpxRefined == 0 {
+					cx = jpxCtxMag
+					if t.nbr[i] != 0 {
+						cx++
+					}
+				}
+				t.mag[i] = t.mag[i]<<1 | int64(t.bit(raw, cx))
+				t.flags[i] |= jpxRefined
+				t.planes[i]++
+			}
+		}
+	}
+}
+
+// cleanupPass implements the cleanup decoding pass (D.3.4).
+func (t *jpxCodeBlockDecoder) cleanupPass(segSymbol bool) {
+	for y0 := 0; y0 < t.h; y0 += 4 {
+		for x := 0; x < t.w; x++ {
+			y := y0
+
+			if y0+4 <= t.h {
+				runMode := true
+				for k := 0; k < 4; k++ {
+					i := (y0+k)*t.w + x
+					if t.flags[i]&(jpxSig|jpxVisited) != 0 || t.nbr[i] != 0 {
+						runMode = false
+						break
+					}
+				}
+
+				if runMode {
+					if t.ad.decodeBit(t.cc, jpxCtxRun) == 0 {
+						for k := 0; k < 4; k++ {
+							t.planes[(y0+k)*t.w+x]++
+						}
+						continue
+					}
+					k := t.ad.decodeBit(t.cc, jpxCtxUniform)<<1 | t.ad.decodeBit(t.cc, jpxCtxUniform)
+					for ; y <= y0+k; y++ {
+						t.planes[y*t.w+x]++
+					}
+					t.decodeSign(x, y0+k, false)
+				}
+			}
+
+			for ; y < min(y0+4, t.h); y++ {
+				i := y*t.w + x
+				if t.flags[i]&(jpxSig|jpxVisited) != 0 {
+					continue
+				}
+				t.planes[i]++
+				if t.ad.decodeBit(t.cc, int(t.zc[t.nbr[i]])) == 1 {
+					t.decodeSign(x, y, false)
+				}
+			}
+		}
+	}
+
+	for i := range t.flags {
+		t.flags[i] &^= jpxVisited
+	}
+
+	if segSymbol {
+		for i := 0; i < 4; i++ {
+			t.ad.decodeBit(t.cc, jpxCtxUniform)
+		}
+	}
+}
+
+// decode runs the coding passes of all codeword segments of cb.
+func (t *jpxCodeBlockDecoder) decode(cb *jpxCodeBlock, cbStyle int) {
+	pass := 0
+	for _, s := range cb.segs {
+		raw := cbStyle&jpxBypass != 0 && pass >= 10 && (pass-10)%3 != 2
+		if raw {
+			t.raw = &jpxRawDecoder{data: s.data}
+		} else {
+			t.ad = newArithDecoder(s.data)
+		}
+
+		for k := 0; k < s.passes; k++ {
+			switch pass % 3 {
+			case 0:
+				t.cleanupPass(cbStyle&jpxSegSymbol != 0)
+			case 1:
+				t.significancePass(raw)
+			case 2:
+				t.refinementPass(raw)
+			}
+			if cbStyle&jpxReset != 0 {
+				t.resetContexts()
+			}
+			pass++
+		}
+	}
+}
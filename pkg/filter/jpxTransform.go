@@ -0,0 +1,243 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import "math"
+
+// Dequantization (Annex E), inverse discrete wavelet transformation (Annex F)
+// and inverse multiple component transformation (Annex G) as specified in ITU-T T.800.
+
+// Irreversible 9-7 filter lifting parameters (Table F.4)
+const (
+	jpxAlpha = -1.586134342059924
+	jpxBeta  = -0.052980118572961
+	jpxGamma = 0.882911075530934
+	jpxDelta = 0.443506852043971
+	jpxK     = 1.230174104914001
+)
+
+// decodeBand decodes all code-blocks of band b and returns its dequantized coefficients.
+func (tc *jpxTileComponent) decodeBand(b *jpxBand, r, prec int) []float64 {
+	w, h := b.x1-b.x0, b.y1-b.y0
+	coeffs := make([]float64, max(w, 0)*max(h, 0))
+	if len(coeffs) == 0 {
+		return coeffs
+	}
+
+	// Subband index within the quantization parameters
+	i := 0
+	if r > 0 {
+		i = 3*(r-1) + b.typ
+	}
+	eps, mu := tc.q.stepSize(i, b.level, tc.cs.levels)
+
+	gain := 0
+	switch b.typ {
+	case jpxHL, jpxLH:
+		gain = 1
+	case jpxHH:
+		gain = 2
+	}
+
+	delta := 1.
+	if !tc.cs.reversible {
+		delta = math.Ldexp(1+float64(mu)/2048, prec+gain-eps)
+	}
+	mb := tc.q.guard + eps - 1 + tc.roiShift
+
+	for _, p := range b.precincts {
+		for _, cb := range p.blocks {
+			if len(cb.segs) == 0 {
+				continue
+			}
+
+			cw, ch := cb.x1-cb.x0, cb.y1-cb.y0
+			t := newCodeBlockDecoder(cw, ch, b.typ, tc.cs.cbStyle&jpxVCausal != 0)
+			t.decode(cb, tc.cs.cbStyle)
+
+			for y := 0; y < ch; y++ {
+				for x := 0; x < cw; x++ {
+					k := y*cw + x
+					if t.mag[k] == 0 {
+						continue
+					}
+					shift := max(mb-cb.zeroPlanes-int(t.planes[k]), 0)
+					m := t.mag[k] << shift
+					if tc.roiShift > 0 && m >= 1<<tc.roiShift {
+						m >>= tc.roiShift
+						shift = max(shift-tc.roiShift, 0)
+					}
+					v := float64(m)
+					if !tc.cs.reversible {
+						// Reconstruct at the midpoint of the quantization interval.
+						v = (v + math.Ldexp(0.5, shift)) * delta
+					}
+					if t.flags[k]&jpxNeg != 0 {
+						v = -v
+					}
+					coeffs[(cb.y0-b.y0+y)*w+cb.x0-b.x0+x] = v
+				}
+			}
+		}
+	}
+
+	return coeffs
+}
+
+// decode decodes the samples of tile-component tc (before any component transform and DC level shift).
+func (tc *jpxTileComponent) decode(prec int) {
+	res := tc.res[0]
+	a := tc.decodeBand(res.bands[0], 0, prec)
+
+	for r := 1; r < len(tc.res); r++ {
+		prev := res
+		res = tc.res[r]
+		w, h := res.x1-res.x0, res.y1-res.y0
+
+		// 2D_INTERLEAVE (F.3.3)
+		b := make([]float64, max(w, 0)*max(h, 0))
+		put := func(src []float64, x0, y0, x1, y1, xo, yo int) {
+			sw := x1 - x0
+			for v := y0; v < y1; v++ {
+				for u := x0; u < x1; u++ {
+					x, y := 2*u+xo-res.x0, 2*v+yo-res.y0
+					if x >= 0 && x < w && y >= 0 && y < h {
+						b[y*w+x] = src[(v-y0)*sw+u-x0]
+					}
+				}
+			}
+		}
+		put(a, prev.x0, prev.y0, prev.x1, prev.y1, 0, 0)
+		for _, band := range res.bands {
+			put(tc.decodeBand(band, r, prec), band.x0, band.y0, band.x1, band.y1, band.typ&1, band.typ>>1)
+		}
+
+		if w > 0 && h > 0 {
+			tc.synthesize2D(b, res)
+		}
+		a = b
+	}
+
+	tc.data = a
+}
+
+// synthesize2D applies the horizontal and vertical 1D subband reconstruction (F.3.4, F.3.5) to the samples of res.
+func (tc *jpxTileComponent) synthesize2D(a []float64, res *jpxResolution) {
+	w, h := res.x1-res.x0, res.y1-res.y0
+	buf := make([]float64, max(w, h)+8)
+
+	for y := 0; y < h; y++ {
+		synthesize1D(a[y*w:(y+1)*w], res.x0, tc.cs.reversible, buf)
+	}
+
+	col := make([]float64, h)
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			col[y] = a[y*w+x]
+		}
+		synthesize1D(col, res.y0, tc.cs.reversible, buf)
+		for y := 0; y < h; y++ {
+			a[y*w+x] = col[y]
+		}
+	}
+}
+
+// reflect returns the index of the periodic symmetric extension of a signal of length n at position k (F.3.7).
+func reflect(k, n int) int {
+	if n == 1 {
+		return 0
+	}
+	p := 2 * (n - 1)
+	k %= p
+	if k < 0 {
+		k += p
+	}
+	if k >= n {
+		k = p - k
+	}
+	return k
+}
+
+// synthesize1D implements the 1D subband reconstruction procedure 1D_SR (F.3.6) for signal x starting at i0.
+func synthesize1D(x []float64, i0 int, reversible bool, buf []float64) {
+	n := len(x)
+	if n == 1 {
+		if i0&1 == 1 {
+			x[0] /= 2
+			if reversible {
+				x[0] = math.Trunc(x[0])
+			}
+		}
+		return
+	}
+
+	const pad = 4
+	e := buf[:n+2*pad]
+	for k := -pad; k < n+pad; k++ {
+		e[k+pad] = x[reflect(k, n)]
+	}
+
+	// lift applies f to all samples within [from, to) having the parity of odd.
+	lift := func(from, to int, odd bool, f func(k int) float64) {
+		for k := from; k < to; k++ {
+			if (i0+k)&1 == 1 == odd {
+				e[k+pad] = f(k + pad)
+			}
+		}
+	}
+
+	if reversible {
+		// 5-3 reversible filter (F.3.8.1)
+		lift(-1, n+1, false, func(k int) float64 { return e[k] - math.Floor((e[k-1]+e[k+1]+2)/4) })
+		lift(0, n, true, func(k int) float64 { return e[k] + math.Floor((e[k-1]+e[k+1])/2) })
+	} else {
+		// 9-7 irreversible filter (F.3.8.2)
+		lift(-pad, n+pad, false, func(k int) float64 { return e[k] * jpxK })
+		lift(-pad, n+pad, true, func(k int) float64 { return e[k] / jpxK })
+		lift(-3, n+3, false, func(k int) float64 { return e[k] - jpxDelta*(e[k-1]+e[k+1]) })
+		lift(-2, n+2, true, func(k int) float64 { return e[k] - jpxGamma*(e[k-1]+e[k+1]) })
+		lift(-1, n+1, false, func(k int) float64 { return e[k] - jpxBeta*(e[k-1]+e[k+1]) })
+		lift(0, n, true, func(k int) float64 { return e[k] - jpxAlpha*(e[k-1]+e[k+1]) })
+	}
+
+	copy(x, e[pad:pad+n])
+}
+
+// inverseMCT applies the inverse reversible or irreversible component transformation to the first three components of t (G.2, G.3).
+func (t *jpxTile) inverseMCT() {
+	c0, c1, c2 := t.comps[0], t.comps[1], t.comps[2]
+	if len(c0.data) != len(c1.data) || len(c0.data) != len(c2.data) {
+		return
+	}
+
+	y0, y1, y2 := c0.data, c1.data, c2.data
+
+	if c0.cs.reversible {
+		for i := range y0 {
+			g := y0[i] - math.Floor((y2[i]+y1[i])/4)
+			y0[i], y1[i], y2[i] = y2[i]+g, g, y1[i]+g
+		}
+		return
+	}
+
+	for i := range y0 {
+		y, cb, cr := y0[i], y1[i], y2[i]
+		y0[i] = y + 1.402*cr
+		y1[i] = y - 0.34413*cb - 0.71414*cr
+		y2[i] = y + 1.772*cb
+	}
+}
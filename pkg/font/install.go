@@ -761,6 +761,9 @@ func InstallFontFromBytes(fontDir, fontName string, bb []byte) error {
 }
 
 func ttfTables(tableCount int, bb []byte) (map[string]*table, error) {
+	if len(bb) < 12+tableCount*16 {
+		return nil, errors.New("pdfcpu: corrupt table directory")
+	}
 	tables := map[string]*table{}
 	b := bb[12:]
 	for j := 0; j < tableCount; j++ {
@@ -771,7 +774,11 @@ func ttfTables(tableCount int, bb []byte) (map[string]*table, error) {
 		o := binary.BigEndian.Uint32(b1[8:])
 		l := binary.BigEndian.Uint32(b1[12:])
 		ll := getNext32BitAlignedLength(l)
-		t := append([]byte(nil), bb[o:o+ll]...)
+		if uint64(o)+uint64(l) > uint64(len(bb)) {
+			return nil, errors.Errorf("pdfcpu: corrupt table: %s", tag)
+		}
+		// The last table may lack its padding.
+		t := pad(append([]byte(nil), bb[o:o+l]...))
 		tables[tag] = &table{chksum: chksum, off: o, size: l, padded: ll, data: t}
 	}
 	return tables, nil
@@ -824,6 +831,9 @@ func resolveCompoundGlyph(fontName string, bb []byte, usedGIDs map[uint16]bool,
 	locaFull, glyfsFull *table, numGlyphs, indexToLocFormat int) error {
 	last := false
 	for off := 10; !last; {
+		if off+4 > len(bb) {
+			return errors.Errorf("pdfcpu: corrupt compound glyph for font: %s", fontName)
+		}
 		flags := binary.BigEndian.Uint16(bb[off:])
 		last = flags&0x20 == 0
 		wordArgs := flags&0x01 > 0
@@ -845,13 +855,13 @@ func resolveCompoundGlyph(fontName string, bb []byte, usedGIDs map[uint16]bool,
 			off += 8
 		}
 
-		if _, ok := usedGIDs[gid]; ok {
-			// duplicate
+		if _, ok := usedGIDs[gid]; ok || int(gid) >= numGlyphs {
+			// duplicate or not available
 			continue
 		}
 
 		offFrom, offThru := glyphOffsets(int(gid), locaFull, glyfsFull, numGlyphs, indexToLocFormat)
-		if offThru < offFrom {
+		if offThru < offFrom || offThru > len(glyfsFull.data) {
 			return errors.Errorf("pdfcpu: illegal glyfOffset for font: %s", fontName)
 		}
 		if offFrom == offThru {
@@ -881,7 +891,7 @@ func resolveCompoundGlyphs(fontName string, usedGIDs map[uint16]bool, locaFull,
 	}
 	for _, gid := range gids {
 		offFrom, offThru := glyphOffsets(int(gid), locaFull, glyfsFull, numGlyphs, indexToLocFormat)
-		if offThru < offFrom {
+		if offThru < offFrom || offThru > len(glyfsFull.data) {
 			return errors.Errorf("pdfcpu: illegal glyfOffset for font: %s", fontName)
 		}
 		if offFrom == offThru {
@@ -925,6 +935,16 @@ func glyfAndLoca(fontName string, tables map[string]*table, usedGIDs map[uint16]
 	// 1 .. long offsets
 	numGlyphs := int(maxp.uint16(4))
 
+	if int(locaFull.size) < (numGlyphs+1)*(2+2*indexToLocFormat) {
+		return errors.Errorf("pdfcpu: corrupt \"loca\" table for font: %s", fontName)
+	}
+
+	for gid := range usedGIDs {
+		if int(gid) >= numGlyphs {
+			delete(usedGIDs, gid)
+		}
+	}
+
 	if err := resolveCompoundGlyphs(fontName, usedGIDs, locaFull, glyfsFull, numGlyphs, indexToLocFormat); err != nil {
 		return err
 	}
@@ -943,7 +963,7 @@ func glyfAndLoca(fontName string, tables map[string]*table, usedGIDs map[uint16]
 
 	for _, gid := range gids {
 		offFrom, offThru := glyphOffsets(gid, locaFull, glyfsFull, numGlyphs, indexToLocFormat)
-		if offThru < offFrom {
+		if offThru < offFrom || offThru > len(glyfsFull.data) {
 			return errors.Errorf("pdfcpu: illegal glyfOffset for font: %s", fontName)
 		}
 		if offThru != offFrom {
@@ -1025,6 +1045,16 @@ func Subset(fontName string, usedGIDs map[uint16]bool) ([]byte, error) {
 		return nil, err
 	}
 
+	return SubsetFontFile(fontName, bb, usedGIDs)
+}
+
+// SubsetFontFile creates a new font file for the TrueType font program bb based on usedGIDs.
+// Glyph ids are retained, unused glyphs lose their outline.
+func SubsetFontFile(fontName string, bb []byte, usedGIDs map[uint16]bool) ([]byte, error) {
+	if len(bb) < 12 {
+		return nil, errors.Errorf("pdfcpu: corrupt font file: %s", fontName)
+	}
+
 	header := bb[:12]
 	tableCount := int(binary.BigEndian.Uint16(header[4:]))
 	tables, err := ttfTables(tableCount, bb)
@@ -1028,3 +1028,163 @@ func RemoveAnnotations(ctx *model.Context, selectedPages types.IntSet, idsAndTyp
 
 	return removed, nil
 }
+
+// AnnotFlattenMode selects annotations for flattening based on their annotation flags.
+type AnnotFlattenMode int
+
+// Hidden annotations never get flattened.
+const (
+	FlattenAll    AnnotFlattenMode = iota // all annotations including NoView annotations
+	FlattenPrint                          // annotations to be printed
+	FlattenScreen                         // annotations to be displayed on screen
+)
+
+// defaultFlattenTypes are the markup annotation types except Redact, see 12.5.6.2 Markup annotations
+var defaultFlattenTypes = []model.AnnotationType{
+	model.AnnText, model.AnnFreeText, model.AnnLine, model.AnnSquare, model.AnnCircle, model.AnnPolygon, model.AnnPolyLine,
+	model.AnnHighLight, model.AnnUnderline, model.AnnSquiggly, model.AnnStrikeOut, model.AnnStamp, model.AnnCaret,
+	model.AnnInk, model.AnnFileAttachment, model.AnnSound,
+}
+
+func flattenTypes(annotTypes []string) (map[string]bool, error) {
+	m := map[string]bool{}
+
+	if len(annotTypes) == 0 {
+		for _, at := range defaultFlattenTypes {
+			m[model.AnnotTypeStrings[at]] = true
+		}
+		return m, nil
+	}
+
+	for _, s := range annotTypes {
+		at, ok := model.AnnotTypes[s]
+		if !ok {
+			return nil, errors.Errorf("pdfcpu: unknown annotation type: %s", s)
+		}
+		if at == model.AnnWidget {
+			return nil, errors.New("pdfcpu: please use \"pdfcpu form flatten\" for Widget annotations")
+		}
+		m[s] = true
+	}
+
+	return m, nil
+}
+
+func flattenable(d types.Dict, mode AnnotFlattenMode) bool {
+	f := model.AnnotationFlagsFor(d)
+	if f&model.AnnHidden > 0 {
+		return false
+	}
+	switch mode {
+	case FlattenPrint:
+		return f&model.AnnPrint > 0
+	case FlattenScreen:
+		return f&model.AnnNoView == 0
+	}
+	return true
+}
+
+// pageAnnotsForFlattening returns the annotations of page pageNr to be flattened
+// together with the pop-up annotations depending on them.
+func pageAnnotsForFlattening(ctx *model.Context, pageNr int, annTypes map[string]bool, mode AnnotFlattenMode) (map[types.IndirectRef]bool, []int, error) {
+	d, _, _, err := ctx.PageDict(pageNr, false)
+	if err != nil || d == nil {
+		return nil, nil, err
+	}
+
+	arr, err := ctx.DereferenceArray(d["Annots"])
+	if err != nil || len(arr) == 0 {
+		return nil, nil, err
+	}
+
+	m := map[types.IndirectRef]bool{}
+	var objNrs []int
+
+	for _, o := range arr {
+		indRef, ok := o.(types.IndirectRef)
+		if !ok {
+			continue
+		}
+
+		ad, err := ctx.DereferenceDict(indRef)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		subType := ad.NameEntry("Subtype")
+		if subType == nil || !annTypes[*subType] || !flattenable(ad, mode) {
+			continue
+		}
+
+		if _, sd, err := ctx.NormalAppearance(ad); err != nil || sd == nil {
+			if err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		m[indRef] = true
+		objNrs = append(objNrs, indRef.ObjectNumber.Value())
+
+		if popup := ad.IndirectRefEntry("Popup"); popup != nil {
+			objNrs = append(objNrs, popup.ObjectNumber.Value())
+		}
+	}
+
+	// Replies to flattened annotations become standalone annotations.
+	for _, o := range arr {
+		indRef, ok := o.(types.IndirectRef)
+		if !ok || m[indRef] {
+			continue
+		}
+		ad, err := ctx.DereferenceDict(indRef)
+		if err != nil {
+			return nil, nil, err
+		}
+		if irt := ad.IndirectRefEntry("IRT"); irt != nil && m[*irt] {
+			ad.Delete("IRT")
+			ad.Delete("RT")
+		}
+	}
+
+	return m, objNrs, nil
+}
+
+// FlattenAnnotations paints the normal appearances of annotations of selected pages into the page content
+// and removes the annotations afterwards.
+// Only annotations of annotTypes are affected, which defaults to all markup annotations except Redact.
+// Annotations without normal appearance are left untouched.
+func FlattenAnnotations(ctx *model.Context, selectedPages types.IntSet, annotTypes []string, mode AnnotFlattenMode) (bool, error) {
+	annTypes, err := flattenTypes(annotTypes)
+	if err != nil {
+		return false, err
+	}
+
+	var objNrs []int
+
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+		if selectedPages != nil && !selectedPages[pageNr] {
+			continue
+		}
+
+		m, objNrs1, err := pageAnnotsForFlattening(ctx, pageNr, annTypes, mode)
+		if err != nil {
+			return false, err
+		}
+		if len(m) == 0 {
+			continue
+		}
+
+		if err := ctx.PaintAppearances(pageNr, m); err != nil {
+			return false, err
+		}
+
+		objNrs = append(objNrs, objNrs1...)
+	}
+
+	if len(objNrs) == 0 {
+		return false, nil
+	}
+
+	return RemoveAnnotations(ctx, selectedPages, nil, objNrs, false)
+}
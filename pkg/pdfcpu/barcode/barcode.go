@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package barcode provides QR, DataMatrix, Code 128 and EAN-13 symbols rendered as vector paths.
+package barcode
+
+import (
+	"io"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/draw"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Type represents a barcode symbology.
+type Type int
+
+// Supported symbologies.
+const (
+	QR Type = iota
+	DataMatrix
+	Code128
+	EAN13
+)
+
+func (t Type) String() string {
+	return [...]string{"qr", "datamatrix", "code128", "ean13"}[t]
+}
+
+// Symbology represents a barcode type along with its error correction level (QR only).
+type Symbology struct {
+	Type  Type
+	Level ECLevel
+}
+
+// ParseSymbology parses one of qr, qr-l, qr-m, qr-q, qr-h, datamatrix, code128, ean13.
+// QR codes default to error correction level M.
+func ParseSymbology(s string) (Symbology, error) {
+	switch strings.ToLower(s) {
+	case "qr", "qr-m":
+		return Symbology{Type: QR, Level: ECLevelM}, nil
+	case "qr-l":
+		return Symbology{Type: QR, Level: ECLevelL}, nil
+	case "qr-q":
+		return Symbology{Type: QR, Level: ECLevelQ}, nil
+	case "qr-h":
+		return Symbology{Type: QR, Level: ECLevelH}, nil
+	case "datamatrix", "dm":
+		return Symbology{Type: DataMatrix}, nil
+	case "code128":
+		return Symbology{Type: Code128}, nil
+	case "ean13":
+		return Symbology{Type: EAN13}, nil
+	}
+	return Symbology{}, errors.Errorf("pdfcpu: unsupported barcode type: %s (use one of: qr, qr-l, qr-m, qr-q, qr-h, datamatrix, code128, ean13)", s)
+}
+
+func (s Symbology) String() string {
+	if s.Type == QR {
+		return "qr-" + strings.ToLower(s.Level.String())
+	}
+	return s.Type.String()
+}
+
+// Encode returns the symbol for content.
+func (s Symbology) Encode(content string) (*Code, error) {
+	switch s.Type {
+	case QR:
+		return EncodeQR(content, s.Level)
+	case DataMatrix:
+		return EncodeDataMatrix(content)
+	case Code128:
+		return EncodeCode128(content)
+	case EAN13:
+		return EncodeEAN13(content)
+	}
+	return nil, errors.Errorf("pdfcpu: unsupported barcode type: %d", s.Type)
+}
+
+// Code represents an encoded barcode symbol made up of dark and light modules.
+// Linear codes consist of a single row of modules stretched to the bar height.
+type Code struct {
+	Cols, Rows int // symbol size in modules excluding the quiet zone
+	Quiet      int // quiet zone in modules
+	linear     bool
+	modules    [][]bool // modules[row][col], row 0 on top
+}
+
+func newMatrixCode(modules [][]bool, quiet int) *Code {
+	return &Code{Cols: len(modules[0]), Rows: len(modules), Quiet: quiet, modules: modules}
+}
+
+func newLinearCode(bars []bool, height, quiet int) *Code {
+	return &Code{Cols: len(bars), Rows: height, Quiet: quiet, linear: true, modules: [][]bool{bars}}
+}
+
+// Linear returns true for one dimensional barcodes.
+func (c Code) Linear() bool {
+	return c.linear
+}
+
+// Dark returns true if the module at col and row is dark.
+func (c Code) Dark(col, row int) bool {
+	if c.linear {
+		row = 0
+	}
+	return c.modules[row][col]
+}
+
+// Width returns the symbol width in modules including the quiet zone.
+func (c Code) Width() int {
+	return c.Cols + 2*c.Quiet
+}
+
+// Height returns the symbol height in modules including the quiet zone.
+// Linear codes have a horizontal quiet zone only.
+func (c Code) Height() int {
+	if c.linear {
+		return c.Rows
+	}
+	return c.Rows + 2*c.Quiet
+}
+
+// Rects returns the dark areas of c scaled into r.
+func (c Code) Rects(r *types.Rectangle) []*types.Rectangle {
+	mw := r.Width() / float64(c.Width())
+	mh := r.Height() / float64(c.Height())
+
+	quietY := c.Quiet
+	if c.linear {
+		quietY = 0
+	}
+
+	var rr []*types.Rectangle
+	rows := len(c.modules)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < c.Cols; {
+			if !c.modules[row][col] {
+				col++
+				continue
+			}
+			run := 1
+			for col+run < c.Cols && c.modules[row][col+run] {
+				run++
+			}
+			x := r.LL.X + float64(c.Quiet+col)*mw
+			y, h := r.LL.Y, r.Height()
+			if !c.linear {
+				y = r.UR.Y - float64(quietY+row+1)*mh
+				h = mh
+			}
+			rr = append(rr, types.RectForWidthAndHeight(x, y, float64(run)*mw, h))
+			col += run
+		}
+	}
+
+	return rr
+}
+
+// Draw renders c into r using col for dark modules and optional bgCol for the background.
+func (c Code) Draw(w io.Writer, r *types.Rectangle, col color.SimpleColor, bgCol *color.SimpleColor) {
+	if bgCol != nil {
+		draw.FillRectNoBorder(w, r, *bgCol)
+	}
+	draw.FillRects(w, c.Rects(r), col)
+}
@@ -0,0 +1,372 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barcode
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestQRCodewords(t *testing.T) {
+	for _, tt := range []struct {
+		s     string
+		level ECLevel
+		data  []byte
+		ecc   []byte
+	}{
+		{
+			s:     "HELLO WORLD",
+			level: ECLevelM,
+			data:  []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17},
+			ecc:   []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23},
+		},
+		{
+			s:     "01234567",
+			level: ECLevelM,
+			data:  []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11},
+			ecc:   []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55},
+		},
+	} {
+		v, data, err := qrDataCodewordsFor(tt.s, tt.level)
+		if err != nil {
+			t.Fatalf("%s: %v\n", tt.s, err)
+		}
+		if v != 1 {
+			t.Fatalf("%s: version: got %d, want 1\n", tt.s, v)
+		}
+		if !bytes.Equal(data, tt.data) {
+			t.Fatalf("%s: data: got %v, want %v\n", tt.s, data, tt.data)
+		}
+		if ecc := qrCodewords(data, v, tt.level)[len(data):]; !bytes.Equal(ecc, tt.ecc) {
+			t.Fatalf("%s: ecc: got %v, want %v\n", tt.s, ecc, tt.ecc)
+		}
+	}
+}
+
+func TestQRCapacity(t *testing.T) {
+	// Data codewords for versions 1, 5, 10 and 40 at levels L, M, Q, H.
+	for v, want := range map[int][4]int{
+		1:  {19, 16, 13, 9},
+		5:  {108, 86, 62, 46},
+		10: {274, 216, 154, 122},
+		40: {2956, 2334, 1666, 1276},
+	} {
+		for l := ECLevelL; l <= ECLevelH; l++ {
+			if got := qrDataCodewords(v, l); got != want[l] {
+				t.Fatalf("version %d-%s: got %d, want %d\n", v, l, got, want[l])
+			}
+		}
+	}
+}
+
+func TestQRFormatAndVersionBits(t *testing.T) {
+	for _, tt := range []struct {
+		level ECLevel
+		mask  int
+		want  int
+	}{
+		{ECLevelM, 0, 0b101010000010010},
+		{ECLevelL, 0, 0b111011111000100},
+		{ECLevelH, 0, 0b001011010001001},
+		{ECLevelQ, 7, 0b010101111101101},
+	} {
+		if got := qrFormatBits(tt.level, tt.mask); got != tt.want {
+			t.Fatalf("format bits %s/%d: got %015b, want %015b\n", tt.level, tt.mask, got, tt.want)
+		}
+	}
+
+	if got := qrVersionBits(7); got != 0b000111110010010100 {
+		t.Fatalf("version bits 7: got %018b\n", got)
+	}
+}
+
+// readQR reads back level, mask and codewords from c.
+func readQR(t *testing.T, c *Code) (ECLevel, int, []byte) {
+	t.Helper()
+
+	v := (c.Cols - 17) / 4
+	q := newQRSymbol(v)
+	q.functionPatterns(v)
+
+	bits := 0
+	for i := 0; i <= 5; i++ {
+		if c.Dark(8, i) {
+			bits |= 1 << i
+		}
+	}
+	for i, p := range [][2]int{{8, 7}, {8, 8}, {7, 8}} {
+		if c.Dark(p[0], p[1]) {
+			bits |= 1 << (i + 6)
+		}
+	}
+	for i := 9; i < 15; i++ {
+		if c.Dark(14-i, 8) {
+			bits |= 1 << i
+		}
+	}
+
+	level, mask := -1, -1
+	for l := ECLevelL; l <= ECLevelH; l++ {
+		for m := 0; m < 8; m++ {
+			if qrFormatBits(l, m) == bits {
+				level, mask = int(l), m
+			}
+		}
+	}
+	if level < 0 {
+		t.Fatalf("invalid format bits: %015b\n", bits)
+	}
+
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			q.dark[y][x] = c.Dark(x, y)
+		}
+	}
+	q.applyMask(mask)
+
+	// Read the codewords in placement order.
+	var bb bitBuffer
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if !q.function[y][x] {
+					bb = append(bb, q.dark[y][x])
+				}
+			}
+		}
+	}
+
+	return ECLevel(level), mask, bb.bytes()[:qrRawModules(v)/8]
+}
+
+func TestQR(t *testing.T) {
+	for _, tt := range []struct {
+		s       string
+		level   ECLevel
+		version int
+	}{
+		{"HELLO WORLD", ECLevelQ, 1},
+		{"https://pdfcpu.io/shipping?id=4711&page=12", ECLevelM, 3},
+		{strings.Repeat("0123456789", 30), ECLevelH, 11},
+		{strings.Repeat("pdfcpu ", 100), ECLevelL, 18},
+	} {
+		c, err := EncodeQR(tt.s, tt.level)
+		if err != nil {
+			t.Fatalf("%s: %v\n", tt.s, err)
+		}
+		if want := 4*tt.version + 17; c.Cols != want || c.Rows != want {
+			t.Fatalf("%s: size: got %dx%d, want %d\n", tt.s, c.Cols, c.Rows, want)
+		}
+
+		level, _, cw := readQR(t, c)
+		if level != tt.level {
+			t.Fatalf("%s: level: got %s, want %s\n", tt.s, level, tt.level)
+		}
+
+		_, data, _ := qrDataCodewordsFor(tt.s, tt.level)
+		if want := qrCodewords(data, tt.version, tt.level); !bytes.Equal(cw, want) {
+			t.Fatalf("%s: codeword mismatch\n", tt.s)
+		}
+	}
+
+	if _, err := EncodeQR(strings.Repeat("x", 3000), ECLevelH); err == nil {
+		t.Fatal("expected error for content too long")
+	}
+}
+
+func TestDataMatrix(t *testing.T) {
+	data := dmPad(dmASCII([]byte("123456")), 3)
+	cw := dmCodewords(data, dmSymbols[0])
+	if want := []byte{142, 164, 186, 114, 25, 5, 88, 102}; !bytes.Equal(cw, want) {
+		t.Fatalf("codewords: got %v, want %v\n", cw, want)
+	}
+
+	if got, want := dmPad([]byte{'A' + 1}, 4), []byte{66, 129, 70, 220}; !bytes.Equal(got, want) {
+		t.Fatalf("padding: got %v, want %v\n", got, want)
+	}
+
+	for _, tt := range []struct {
+		s    string
+		size int
+	}{
+		{"123456", 10},
+		{"Shipment 4711", 16},
+		{strings.Repeat("A", 100), 40},
+		{strings.Repeat("1234567890", 60), 72},
+	} {
+		c, err := EncodeDataMatrix(tt.s)
+		if err != nil {
+			t.Fatalf("%s: %v\n", tt.s, err)
+		}
+		if c.Cols != tt.size || c.Rows != tt.size {
+			t.Fatalf("%s: size: got %dx%d, want %d\n", tt.s, c.Cols, c.Rows, tt.size)
+		}
+		// Solid finder pattern along the left and bottom edge, clock track along the top and right edge.
+		for i := 0; i < tt.size; i++ {
+			if !c.Dark(0, i) || !c.Dark(i, tt.size-1) {
+				t.Fatalf("%s: corrupt finder pattern at %d\n", tt.s, i)
+			}
+			if c.Dark(i, 0) != (i%2 == 0) || c.Dark(tt.size-1, i) != (i%2 == 1) {
+				t.Fatalf("%s: corrupt clock track at %d\n", tt.s, i)
+			}
+		}
+	}
+}
+
+func TestDataMatrixPlacement(t *testing.T) {
+	// Every codeword bit gets placed exactly once.
+	for _, s := range dmSymbols {
+		n := s.regions() * s.region
+		p := &dmPlacement{nrow: n, ncol: n, m: make([]int, n*n)}
+		p.place()
+		seen := map[int]bool{}
+		for i, v := range p.m {
+			if v <= 1 {
+				// Filler modules are limited to the lower right corner.
+				if row, col := i/n, i%n; row < n-2 || col < n-2 {
+					t.Fatalf("size %d: unplaced module at %d/%d\n", s.size, row, col)
+				}
+				continue
+			}
+			if seen[v] {
+				t.Fatalf("size %d: duplicate module %d\n", s.size, v)
+			}
+			seen[v] = true
+		}
+		if len(seen) != 8*(s.data+s.ecc) {
+			t.Fatalf("size %d: got %d placed bits, want %d\n", s.size, len(seen), 8*(s.data+s.ecc))
+		}
+	}
+}
+
+func TestCode128(t *testing.T) {
+	for i, p := range code128Patterns {
+		sum := 0
+		for _, w := range p {
+			sum += int(w - '0')
+		}
+		if want := 11 + 2*(i/code128Stop); sum != want {
+			t.Fatalf("pattern %d: got %d modules, want %d\n", i, sum, want)
+		}
+	}
+
+	for _, tt := range []struct {
+		s    string
+		want []int
+	}{
+		{"PJJ123C", []int{104, 48, 42, 42, 17, 18, 19, 35, 55}},
+		{"1234", []int{105, 12, 34, 82}},
+		{"AB123456", []int{104, 33, 34, 99, 12, 34, 56, 26}},
+		{"12345X", []int{105, 12, 34, 100, 21, 56, 25}},
+		{"a\tb", []int{104, 65, 101, 73, 100, 66, 84}},
+	} {
+		vv, err := code128Values(tt.s)
+		if err != nil {
+			t.Fatalf("%s: %v\n", tt.s, err)
+		}
+		if !reflect.DeepEqual(vv, tt.want) {
+			t.Fatalf("%s: got %v, want %v\n", tt.s, vv, tt.want)
+		}
+	}
+
+	c, err := EncodeCode128("1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Cols != 4*11+13 || !c.Linear() {
+		t.Fatalf("unexpected symbol width: %d\n", c.Cols)
+	}
+
+	if _, err := EncodeCode128("Zürich"); err == nil {
+		t.Fatal("expected error for non ASCII content")
+	}
+}
+
+func TestEAN13(t *testing.T) {
+	if got := EAN13CheckDigit("400638133393"); got != '1' {
+		t.Fatalf("check digit: got %c, want 1\n", got)
+	}
+
+	c, err := EncodeEAN13("400638133393")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Cols != 95 {
+		t.Fatalf("width: got %d, want 95\n", c.Cols)
+	}
+
+	var sb strings.Builder
+	for i := 0; i < c.Cols; i++ {
+		if c.Dark(i, 0) {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	// Start guard, 0 (L) and 0 (G), 6 (L) .. middle guard .. 1 (R) end guard.
+	s := sb.String()
+	if !strings.HasPrefix(s, "101"+"0001101"+"0100111"+"0101111") {
+		t.Fatalf("unexpected left half: %s\n", s[:24])
+	}
+	if s[45:50] != "01010" || !strings.HasSuffix(s, "1100110"+"101") {
+		t.Fatalf("unexpected right half: %s\n", s[45:])
+	}
+
+	if _, err := EncodeEAN13("4006381333932"); err == nil {
+		t.Fatal("expected error for invalid check digit")
+	}
+	if _, err := EncodeEAN13("40063813339"); err == nil {
+		t.Fatal("expected error for missing digit")
+	}
+}
+
+func TestRects(t *testing.T) {
+	c, err := EncodeEAN13("4006381333931")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := types.RectForDim(float64(c.Width()), 69)
+	rr := c.Rects(r)
+	if len(rr) != 30 {
+		t.Fatalf("got %d bars, want 30\n", len(rr))
+	}
+	if rr[0].LL.X != 11 || rr[0].Width() != 1 || rr[0].Height() != 69 {
+		t.Fatalf("unexpected first bar: %v\n", rr[0])
+	}
+
+	sym, err := ParseSymbology("QR-H")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sym.Type != QR || sym.Level != ECLevelH || sym.String() != "qr-h" {
+		t.Fatalf("unexpected symbology: %s\n", sym)
+	}
+	if _, err := ParseSymbology("pdf417"); err == nil {
+		t.Fatal("expected error for unsupported symbology")
+	}
+}
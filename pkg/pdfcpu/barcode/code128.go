@@ -0,0 +1,163 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barcode
+
+import "github.com/pkg/errors"
+
+// Code 128 bar and space widths for symbol values 0..105 followed by the stop pattern.
+var code128Patterns = []string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// Code 128 special symbol values.
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128CodeA  = 101
+	code128StartA = 103
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// code128BarHeight is the bar height in modules.
+const code128BarHeight = 40
+
+// code128Digits returns the number of consecutive digits in s starting at i.
+func code128Digits(s string, i int) int {
+	n := 0
+	for i+n < len(s) && isDigit(s[i+n]) {
+		n++
+	}
+	return n
+}
+
+// code128UseC returns true if switching to code set C pays off for the digits of s starting at i.
+func code128UseC(s string, i int) bool {
+	n := code128Digits(s, i)
+	if i == 0 || i+n == len(s) {
+		return n >= 4 || n == 2 && n == len(s)
+	}
+	return n >= 6
+}
+
+// code128Values returns the symbol values for s including start and check symbol.
+func code128Values(s string) ([]int, error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] > 127 {
+			return nil, errors.Errorf("pdfcpu: code128: unsupported character: %q", s[i])
+		}
+	}
+
+	var vv []int
+
+	// The current code set is represented by its start symbol.
+	set := code128StartB
+	switch {
+	case code128UseC(s, 0):
+		set = code128StartC
+	case s[0] < 32:
+		set = code128StartA
+	}
+	vv = append(vv, set)
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		if set == code128StartC {
+			if code128Digits(s, i) >= 2 {
+				vv = append(vv, int(c-'0')*10+int(s[i+1]-'0'))
+				i += 2
+				continue
+			}
+			if c < 32 {
+				set = code128StartA
+				vv = append(vv, code128CodeA)
+			} else {
+				set = code128StartB
+				vv = append(vv, code128CodeB)
+			}
+			continue
+		}
+
+		if code128UseC(s, i) {
+			if code128Digits(s, i)%2 == 1 {
+				vv = append(vv, int(c)-32)
+				i++
+			}
+			set = code128StartC
+			vv = append(vv, code128CodeC)
+			continue
+		}
+
+		if set == code128StartB && c < 32 {
+			set = code128StartA
+			vv = append(vv, code128CodeA)
+		} else if set == code128StartA && c >= 96 {
+			set = code128StartB
+			vv = append(vv, code128CodeB)
+		}
+
+		if c < 32 {
+			vv = append(vv, int(c)+64)
+		} else {
+			vv = append(vv, int(c)-32)
+		}
+		i++
+	}
+
+	check := vv[0]
+	for i, v := range vv[1:] {
+		check += (i + 1) * v
+	}
+
+	return append(vv, check%103), nil
+}
+
+// EncodeCode128 returns the Code 128 barcode for s which may contain ASCII characters only.
+// Runs of digits are compacted using code set C.
+func EncodeCode128(s string) (*Code, error) {
+	if s == "" {
+		return nil, errors.New("pdfcpu: code128: missing content")
+	}
+
+	vv, err := code128Values(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var bars []bool
+	for _, v := range append(vv, code128Stop) {
+		for i, w := range code128Patterns[v] {
+			for j := 0; j < int(w-'0'); j++ {
+				bars = append(bars, i%2 == 0)
+			}
+		}
+	}
+
+	return newLinearCode(bars, code128BarHeight, 10), nil
+}
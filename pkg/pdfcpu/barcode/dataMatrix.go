@@ -0,0 +1,277 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barcode
+
+import "github.com/pkg/errors"
+
+// dmSymbol describes a square ECC 200 symbol size.
+type dmSymbol struct {
+	size   int // symbol size in modules
+	region int // data region size in modules
+	data   int // data codewords
+	ecc    int // error correction codewords
+	blocks int // interleaved blocks
+}
+
+var dmSymbols = []dmSymbol{
+	{10, 8, 3, 5, 1},
+	{12, 10, 5, 7, 1},
+	{14, 12, 8, 10, 1},
+	{16, 14, 12, 12, 1},
+	{18, 16, 18, 14, 1},
+	{20, 18, 22, 18, 1},
+	{22, 20, 30, 20, 1},
+	{24, 22, 36, 24, 1},
+	{26, 24, 44, 28, 1},
+	{32, 14, 62, 36, 1},
+	{36, 16, 86, 42, 1},
+	{40, 18, 114, 48, 1},
+	{44, 20, 144, 56, 1},
+	{48, 22, 174, 68, 1},
+	{52, 24, 204, 84, 2},
+	{64, 14, 280, 112, 2},
+	{72, 16, 368, 144, 4},
+	{80, 18, 456, 192, 4},
+	{88, 20, 576, 224, 4},
+	{96, 22, 696, 272, 4},
+	{104, 24, 816, 336, 6},
+	{120, 18, 1050, 408, 6},
+	{132, 20, 1304, 496, 8},
+	{144, 22, 1558, 620, 10},
+}
+
+// regions returns the number of data regions per row and column.
+func (s dmSymbol) regions() int {
+	return s.size / (s.region + 2)
+}
+
+// dmASCII returns the ASCII encodation of b compacting digit pairs.
+func dmASCII(b []byte) []byte {
+	var cw []byte
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case isDigit(c) && i+1 < len(b) && isDigit(b[i+1]):
+			cw = append(cw, 130+(c-'0')*10+b[i+1]-'0')
+			i++
+		case c < 128:
+			cw = append(cw, c+1)
+		default:
+			// Upper shift
+			cw = append(cw, 235, c-127)
+		}
+	}
+	return cw
+}
+
+// dmPad pads cw to n codewords.
+func dmPad(cw []byte, n int) []byte {
+	if len(cw) < n {
+		cw = append(cw, 129)
+	}
+	for len(cw) < n {
+		pos := len(cw) + 1
+		v := 129 + (149*pos)%253 + 1
+		if v > 254 {
+			v -= 254
+		}
+		cw = append(cw, byte(v))
+	}
+	return cw
+}
+
+// dmCodewords returns data with interleaved error correction codewords appended.
+func dmCodewords(data []byte, s dmSymbol) []byte {
+	cw := make([]byte, s.data+s.ecc)
+	copy(cw, data)
+	eccLen := s.ecc / s.blocks
+	for b := 0; b < s.blocks; b++ {
+		var block []byte
+		for i := b; i < s.data; i += s.blocks {
+			block = append(block, data[i])
+		}
+		for i, e := range gfDataMatrix.ecc(block, eccLen, 1) {
+			cw[s.data+b+i*s.blocks] = e
+		}
+	}
+	return cw
+}
+
+// dmPlacement maps codeword bits onto a nrow x ncol matrix according to ISO/IEC 16022 Annex F.
+// Each entry holds 10*codeword+bit (both 1 based) or 1 and 0 for dark and light filler modules.
+type dmPlacement struct {
+	nrow, ncol int
+	m          []int
+}
+
+func (p *dmPlacement) module(row, col, pos, bit int) {
+	if row < 0 {
+		row += p.nrow
+		col += 4 - (p.nrow+4)%8
+	}
+	if col < 0 {
+		col += p.ncol
+		row += 4 - (p.ncol+4)%8
+	}
+	p.m[row*p.ncol+col] = 10*pos + bit
+}
+
+func (p *dmPlacement) utah(row, col, pos int) {
+	p.module(row-2, col-2, pos, 1)
+	p.module(row-2, col-1, pos, 2)
+	p.module(row-1, col-2, pos, 3)
+	p.module(row-1, col-1, pos, 4)
+	p.module(row-1, col, pos, 5)
+	p.module(row, col-2, pos, 6)
+	p.module(row, col-1, pos, 7)
+	p.module(row, col, pos, 8)
+}
+
+func (p *dmPlacement) corner(pos int, rc [8][2]int) {
+	for i, v := range rc {
+		row, col := v[0], v[1]
+		if row < 0 {
+			row += p.nrow
+		}
+		if col < 0 {
+			col += p.ncol
+		}
+		p.module(row, col, pos, i+1)
+	}
+}
+
+func (p *dmPlacement) place() {
+	nrow, ncol := p.nrow, p.ncol
+	pos, row, col := 1, 4, 0
+
+	for row < nrow || col < ncol {
+		if row == nrow && col == 0 {
+			p.corner(pos, [8][2]int{{-1, 0}, {-1, 1}, {-1, 2}, {0, -2}, {0, -1}, {1, -1}, {2, -1}, {3, -1}})
+			pos++
+		}
+		if row == nrow-2 && col == 0 && ncol%4 != 0 {
+			p.corner(pos, [8][2]int{{-3, 0}, {-2, 0}, {-1, 0}, {0, -4}, {0, -3}, {0, -2}, {0, -1}, {1, -1}})
+			pos++
+		}
+		if row == nrow-2 && col == 0 && ncol%8 == 4 {
+			p.corner(pos, [8][2]int{{-3, 0}, {-2, 0}, {-1, 0}, {0, -2}, {0, -1}, {1, -1}, {2, -1}, {3, -1}})
+			pos++
+		}
+		if row == nrow+4 && col == 2 && ncol%8 == 0 {
+			p.corner(pos, [8][2]int{{-1, 0}, {-1, -1}, {0, -3}, {0, -2}, {0, -1}, {1, -3}, {1, -2}, {1, -1}})
+			pos++
+		}
+
+		// Sweep upward diagonally.
+		for {
+			if row < nrow && col >= 0 && p.m[row*ncol+col] == 0 {
+				p.utah(row, col, pos)
+				pos++
+			}
+			row -= 2
+			col += 2
+			if row < 0 || col >= ncol {
+				break
+			}
+		}
+		row++
+		col += 3
+
+		// Sweep downward diagonally.
+		for {
+			if row >= 0 && col < ncol && p.m[row*ncol+col] == 0 {
+				p.utah(row, col, pos)
+				pos++
+			}
+			row += 2
+			col -= 2
+			if row >= nrow || col < 0 {
+				break
+			}
+		}
+		row += 3
+		col++
+	}
+
+	// Fill the unused lower right corner.
+	if p.m[nrow*ncol-1] == 0 {
+		p.m[nrow*ncol-1] = 1
+		p.m[nrow*ncol-ncol-2] = 1
+	}
+}
+
+// EncodeDataMatrix returns the smallest square ECC 200 DataMatrix symbol holding s.
+func EncodeDataMatrix(s string) (*Code, error) {
+	if s == "" {
+		return nil, errors.New("pdfcpu: datamatrix: missing content")
+	}
+
+	data := dmASCII([]byte(s))
+
+	var sym *dmSymbol
+	for i := range dmSymbols {
+		if dmSymbols[i].data >= len(data) {
+			sym = &dmSymbols[i]
+			break
+		}
+	}
+	if sym == nil {
+		return nil, errors.Errorf("pdfcpu: datamatrix: content too long: %d bytes", len(s))
+	}
+
+	cw := dmCodewords(dmPad(data, sym.data), *sym)
+
+	n := sym.regions() * sym.region
+	p := &dmPlacement{nrow: n, ncol: n, m: make([]int, n*n)}
+	p.place()
+
+	dark := func(row, col int) bool {
+		v := p.m[row*n+col]
+		if v <= 1 {
+			// Corner filler
+			return v == 1
+		}
+		pos, bit := v/10, v%10
+		return cw[pos-1]&(1<<(8-bit)) != 0
+	}
+
+	// Add finder and clock patterns around each data region.
+	modules := make([][]bool, sym.size)
+	rs := sym.region + 2
+	for row := range modules {
+		modules[row] = make([]bool, sym.size)
+		for col := range modules[row] {
+			r, c := row%rs, col%rs
+			switch {
+			case c == 0 || r == rs-1:
+				modules[row][col] = true
+			case r == 0:
+				modules[row][col] = c%2 == 0
+			case c == rs-1:
+				modules[row][col] = r%2 == 1
+			default:
+				modules[row][col] = dark(row/rs*sym.region+r-1, col/rs*sym.region+c-1)
+			}
+		}
+	}
+
+	return newMatrixCode(modules, 1), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
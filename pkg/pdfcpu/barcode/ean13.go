@@ -0,0 +1,90 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barcode
+
+import "github.com/pkg/errors"
+
+// EAN-13 L codes for digits 0..9. R codes are their complement, G codes the reversed R codes.
+var ean13LCodes = []string{
+	"0001101", "0011001", "0010011", "0111101", "0100011",
+	"0110001", "0101111", "0111011", "0110111", "0001011",
+}
+
+// Parity patterns of the left half encoding the leading digit, G marks G codes.
+var ean13Parity = []string{
+	"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG",
+	"LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL",
+}
+
+// ean13BarHeight is the bar height in modules.
+const ean13BarHeight = 69
+
+// EAN13CheckDigit returns the check digit for the 12 digits of s.
+func EAN13CheckDigit(s string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(s[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// EncodeEAN13 returns the EAN-13 barcode for s consisting of 12 digits or 13 digits including the check digit.
+func EncodeEAN13(s string) (*Code, error) {
+	if len(s) != 12 && len(s) != 13 {
+		return nil, errors.Errorf("pdfcpu: ean13: need 12 or 13 digits: %s", s)
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return nil, errors.Errorf("pdfcpu: ean13: need 12 or 13 digits: %s", s)
+		}
+	}
+
+	check := EAN13CheckDigit(s)
+	if len(s) == 13 && s[12] != check {
+		return nil, errors.Errorf("pdfcpu: ean13: invalid check digit: %s, expected %c", s, check)
+	}
+	s = s[:12] + string(check)
+
+	var bars []bool
+	add := func(pattern string, invert, reverse bool) {
+		for i := range pattern {
+			c := pattern[i]
+			if reverse {
+				c = pattern[len(pattern)-1-i]
+			}
+			bars = append(bars, (c == '1') != invert)
+		}
+	}
+
+	add("101", false, false)
+	parity := ean13Parity[s[0]-'0']
+	for i := 1; i <= 6; i++ {
+		g := parity[i-1] == 'G'
+		add(ean13LCodes[s[i]-'0'], g, g)
+	}
+	add("01010", false, false)
+	for i := 7; i <= 12; i++ {
+		add(ean13LCodes[s[i]-'0'], true, false)
+	}
+	add("101", false, false)
+
+	return newLinearCode(bars, ean13BarHeight, 11), nil
+}
@@ -0,0 +1,538 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barcode
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ECLevel represents the error correction level of a QR code.
+type ECLevel int
+
+// QR error correction levels restoring approximately 7%, 15%, 25% and 30% of the codewords.
+const (
+	ECLevelL ECLevel = iota
+	ECLevelM
+	ECLevelQ
+	ECLevelH
+)
+
+// formatBits returns the level bits used in the QR format information.
+func (l ECLevel) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+func (l ECLevel) String() string {
+	return [...]string{"L", "M", "Q", "H"}[l]
+}
+
+// Error correction codewords per block indexed by level and version.
+var qrECCPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// Error correction blocks indexed by level and version.
+var qrECCBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+const qrAlphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// QR segment modes.
+const (
+	qrModeNumeric = iota
+	qrModeAlphanumeric
+	qrModeByte
+)
+
+// qrRawModules returns the number of modules available for data and error correction in version v.
+func qrRawModules(v int) int {
+	n := (16*v+128)*v + 64
+	if v >= 2 {
+		a := v/7 + 2
+		n -= (25*a-10)*a - 55
+		if v >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+// qrDataCodewords returns the number of data codewords of version v at level l.
+func qrDataCodewords(v int, l ECLevel) int {
+	return qrRawModules(v)/8 - qrECCPerBlock[l][v]*qrECCBlocks[l][v]
+}
+
+func qrMode(s string) int {
+	numeric, alphanumeric := true, true
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			numeric = false
+		}
+		if strings.IndexByte(qrAlphanumeric, c) < 0 {
+			alphanumeric = false
+		}
+	}
+	if numeric {
+		return qrModeNumeric
+	}
+	if alphanumeric {
+		return qrModeAlphanumeric
+	}
+	return qrModeByte
+}
+
+func qrCharCountBits(mode, v int) int {
+	i := 0
+	if v >= 27 {
+		i = 2
+	} else if v >= 10 {
+		i = 1
+	}
+	return [3][3]int{{10, 12, 14}, {9, 11, 13}, {8, 16, 16}}[mode][i]
+}
+
+// bitBuffer collects bits most significant first.
+type bitBuffer []bool
+
+func (bb *bitBuffer) append(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*bb = append(*bb, (v>>i)&1 == 1)
+	}
+}
+
+func (bb bitBuffer) bytes() []byte {
+	b := make([]byte, (len(bb)+7)/8)
+	for i, bit := range bb {
+		if bit {
+			b[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return b
+}
+
+// qrSegmentBits returns the data bits of s excluding mode indicator and character count.
+func qrSegmentBits(s string, mode int) bitBuffer {
+	var bb bitBuffer
+	switch mode {
+	case qrModeNumeric:
+		for i := 0; i < len(s); i += 3 {
+			j := min(i+3, len(s))
+			v := 0
+			for _, c := range s[i:j] {
+				v = v*10 + int(c-'0')
+			}
+			bb.append(v, (j-i)*3+1)
+		}
+	case qrModeAlphanumeric:
+		for i := 0; i < len(s); i += 2 {
+			v := strings.IndexByte(qrAlphanumeric, s[i])
+			if i+1 == len(s) {
+				bb.append(v, 6)
+				break
+			}
+			bb.append(v*45+strings.IndexByte(qrAlphanumeric, s[i+1]), 11)
+		}
+	default:
+		for i := 0; i < len(s); i++ {
+			bb.append(int(s[i]), 8)
+		}
+	}
+	return bb
+}
+
+// qrDataCodewordsFor returns the smallest version holding s at level l along with its data codewords.
+func qrDataCodewordsFor(s string, l ECLevel) (int, []byte, error) {
+	mode := qrMode(s)
+	data := qrSegmentBits(s, mode)
+
+	for v := 1; v <= 40; v++ {
+		ccBits := qrCharCountBits(mode, v)
+		if len(s) >= 1<<ccBits {
+			continue
+		}
+		capBits := qrDataCodewords(v, l) * 8
+		if 4+ccBits+len(data) > capBits {
+			continue
+		}
+
+		var bb bitBuffer
+		bb.append(1<<mode, 4)
+		bb.append(len(s), ccBits)
+		bb = append(bb, data...)
+
+		// Terminator and bit padding.
+		bb.append(0, min(4, capBits-len(bb)))
+		bb.append(0, (8-len(bb)%8)%8)
+
+		// Byte padding.
+		for pad := 0xEC; len(bb) < capBits; pad ^= 0xEC ^ 0x11 {
+			bb.append(pad, 8)
+		}
+
+		return v, bb.bytes(), nil
+	}
+
+	return 0, nil, errors.Errorf("pdfcpu: qr: content too long: %d bytes", len(s))
+}
+
+// qrCodewords returns data with error correction codewords appended and interleaved for version v at level l.
+func qrCodewords(data []byte, v int, l ECLevel) []byte {
+	numBlocks := qrECCBlocks[l][v]
+	eccLen := qrECCPerBlock[l][v]
+	raw := qrRawModules(v) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw/numBlocks - eccLen
+
+	var blocks, eccs [][]byte
+	for i, k := 0, 0; i < numBlocks; i++ {
+		n := shortLen
+		if i >= numShort {
+			n++
+		}
+		blocks = append(blocks, data[k:k+n])
+		eccs = append(eccs, gfQR.ecc(data[k:k+n], eccLen, 0))
+		k += n
+	}
+
+	var res []byte
+	for i := 0; i <= shortLen; i++ {
+		for _, b := range blocks {
+			if i < len(b) {
+				res = append(res, b[i])
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for _, e := range eccs {
+			res = append(res, e[i])
+		}
+	}
+	return res
+}
+
+// qrSymbol represents a QR code matrix under construction.
+type qrSymbol struct {
+	size     int
+	dark     [][]bool
+	function [][]bool
+}
+
+func newQRSymbol(v int) *qrSymbol {
+	size := 4*v + 17
+	q := &qrSymbol{size: size}
+	q.dark = make([][]bool, size)
+	q.function = make([][]bool, size)
+	for i := range q.dark {
+		q.dark[i] = make([]bool, size)
+		q.function[i] = make([]bool, size)
+	}
+	return q
+}
+
+// set sets the function module at column x and row y.
+func (q *qrSymbol) set(x, y int, dark bool) {
+	q.dark[y][x] = dark
+	q.function[y][x] = true
+}
+
+func (q *qrSymbol) finderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= q.size || yy < 0 || yy >= q.size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			q.set(xx, yy, d != 2 && d != 4)
+		}
+	}
+}
+
+func (q *qrSymbol) alignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+func qrAlignmentPositions(v int) []int {
+	if v == 1 {
+		return nil
+	}
+	n := v/7 + 2
+	step := 26
+	if v != 32 {
+		step = (v*4 + n*2 + 1) / (n*2 - 2) * 2
+	}
+	pp := make([]int, n)
+	pp[0] = 6
+	for i, p := n-1, 4*v+10; i >= 1; i, p = i-1, p-step {
+		pp[i] = p
+	}
+	return pp
+}
+
+func (q *qrSymbol) functionPatterns(v int) {
+	// Timing patterns
+	for i := 0; i < q.size; i++ {
+		q.set(6, i, i%2 == 0)
+		q.set(i, 6, i%2 == 0)
+	}
+
+	q.finderPattern(3, 3)
+	q.finderPattern(q.size-4, 3)
+	q.finderPattern(3, q.size-4)
+
+	pp := qrAlignmentPositions(v)
+	n := len(pp)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == 0 && j == 0 || i == 0 && j == n-1 || i == n-1 && j == 0 {
+				continue
+			}
+			q.alignmentPattern(pp[i], pp[j])
+		}
+	}
+
+	// Reserve format information.
+	q.formatBits(ECLevelL, 0)
+
+	q.versionBits(v)
+}
+
+// qrFormatBits returns the BCH encoded format information for level l and mask.
+func qrFormatBits(l ECLevel, mask int) int {
+	data := l.formatBits()<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (q *qrSymbol) formatBits(l ECLevel, mask int) {
+	bits := qrFormatBits(l, mask)
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		q.set(8, i, bit(i))
+	}
+	q.set(8, 7, bit(6))
+	q.set(8, 8, bit(7))
+	q.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.set(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.set(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.set(8, q.size-15+i, bit(i))
+	}
+	q.set(8, q.size-8, true)
+}
+
+// qrVersionBits returns the BCH encoded version information for v >= 7.
+func qrVersionBits(v int) int {
+	rem := v
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	return v<<12 | rem
+}
+
+func (q *qrSymbol) versionBits(v int) {
+	if v < 7 {
+		return
+	}
+	bits := qrVersionBits(v)
+	for i := 0; i < 18; i++ {
+		dark := (bits>>i)&1 == 1
+		a, b := q.size-11+i%3, i/3
+		q.set(a, b, dark)
+		q.set(b, a, dark)
+	}
+}
+
+// codewords places the codewords in zigzag order into all non function modules.
+func (q *qrSymbol) codewords(cw []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if !q.function[y][x] && i < len(cw)*8 {
+					q.dark[y][x] = (cw[i/8]>>(7-i%8))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func qrMasked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	}
+	return ((x+y)%2+x*y%3)%2 == 0
+}
+
+// applyMask toggles all data modules selected by mask. Applying a mask twice undoes it.
+func (q *qrSymbol) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if !q.function[y][x] && qrMasked(mask, x, y) {
+				q.dark[y][x] = !q.dark[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol according to the mask evaluation rules.
+func (q *qrSymbol) penalty() int {
+	p := 0
+	n := q.size
+
+	line := func(get func(i int) bool) {
+		run := 0
+		for i := 0; i < n; i++ {
+			if i > 0 && get(i) == get(i-1) {
+				run++
+			} else {
+				run = 1
+			}
+			if run == 5 {
+				p += 3
+			} else if run > 5 {
+				p++
+			}
+		}
+		// Finder like patterns 1011101 preceded or followed by 4 light modules.
+		for i := 0; i+7 <= n; i++ {
+			if !(get(i) && !get(i+1) && get(i+2) && get(i+3) && get(i+4) && !get(i+5) && get(i+6)) {
+				continue
+			}
+			light := func(from, to int) bool {
+				for j := from; j < to; j++ {
+					if j >= 0 && j < n && get(j) {
+						return false
+					}
+				}
+				return true
+			}
+			if light(i-4, i) || light(i+7, i+11) {
+				p += 40
+			}
+		}
+	}
+
+	for y := 0; y < n; y++ {
+		line(func(i int) bool { return q.dark[y][i] })
+	}
+	for x := 0; x < n; x++ {
+		line(func(i int) bool { return q.dark[i][x] })
+	}
+
+	dark := 0
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if q.dark[y][x] {
+				dark++
+			}
+			if x < n-1 && y < n-1 {
+				c := q.dark[y][x]
+				if c == q.dark[y][x+1] && c == q.dark[y+1][x] && c == q.dark[y+1][x+1] {
+					p += 3
+				}
+			}
+		}
+	}
+
+	total := n * n
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return p + k*10
+}
+
+// EncodeQR returns the QR code for s at error correction level l.
+// The smallest version able to hold s in numeric, alphanumeric or byte mode is used.
+func EncodeQR(s string, l ECLevel) (*Code, error) {
+	if s == "" {
+		return nil, errors.New("pdfcpu: qr: missing content")
+	}
+
+	v, data, err := qrDataCodewordsFor(s, l)
+	if err != nil {
+		return nil, err
+	}
+
+	q := newQRSymbol(v)
+	q.functionPatterns(v)
+	q.codewords(qrCodewords(data, v, l))
+
+	best, minPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.formatBits(l, mask)
+		if p := q.penalty(); minPenalty < 0 || p < minPenalty {
+			best, minPenalty = mask, p
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(best)
+	q.formatBits(l, best)
+
+	return newMatrixCode(q.dark, 4), nil
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
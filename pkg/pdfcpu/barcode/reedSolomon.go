@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barcode
+
+// galoisField represents GF(256) for a given primitive polynomial.
+type galoisField struct {
+	exp [512]byte
+	log [256]byte
+}
+
+var (
+	gfQR         = newGaloisField(0x11D) // x^8 + x^4 + x^3 + x^2 + 1
+	gfDataMatrix = newGaloisField(0x12D) // x^8 + x^5 + x^3 + x^2 + 1
+)
+
+func newGaloisField(poly int) *galoisField {
+	gf := &galoisField{}
+	x := 1
+	for i := 0; i < 255; i++ {
+		gf.exp[i] = byte(x)
+		gf.log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= poly
+		}
+	}
+	for i := 255; i < 512; i++ {
+		gf.exp[i] = gf.exp[i-255]
+	}
+	return gf
+}
+
+func (gf *galoisField) mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf.exp[int(gf.log[a])+int(gf.log[b])]
+}
+
+// generator returns the coefficients of the generator polynomial (x - a^first) ... (x - a^(first+n-1))
+// highest degree first omitting the leading 1.
+func (gf *galoisField) generator(n, first int) []byte {
+	g := make([]byte, n)
+	g[n-1] = 1
+	root := gf.exp[first]
+	for i := 0; i < n; i++ {
+		// Multiply g by (x - root).
+		for j := 0; j < n; j++ {
+			g[j] = gf.mul(g[j], root)
+			if j+1 < n {
+				g[j] ^= g[j+1]
+			}
+		}
+		root = gf.mul(root, 2)
+	}
+	return g
+}
+
+// ecc returns n error correction codewords for data.
+func (gf *galoisField) ecc(data []byte, n, first int) []byte {
+	g := gf.generator(n, first)
+	r := make([]byte, n)
+	for _, b := range data {
+		f := b ^ r[0]
+		copy(r, r[1:])
+		r[n-1] = 0
+		for j := range r {
+			r[j] ^= gf.mul(g[j], f)
+		}
+	}
+	return r
+}
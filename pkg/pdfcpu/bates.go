@@ -0,0 +1,75 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// defaultBatesDesc places Bates numbers upright into the lower right corner of every page.
+// Any user supplied description gets applied on top.
+const defaultBatesDesc = "pos:br, off:-10 10, rot:0, scale:1 abs, points:10, fillc:#000000"
+
+func batesDesc(desc string) string {
+	if desc == "" {
+		return defaultBatesDesc
+	}
+	return defaultBatesDesc + ", " + desc
+}
+
+// BatesWatermark returns the stamp for Bates number nr.
+func BatesWatermark(b *model.Bates, nr int, u types.DisplayUnit) (*model.Watermark, error) {
+	return ParseTextWatermarkDetails(b.Number(nr), batesDesc(b.Desc), true, u)
+}
+
+// AddBatesNumbers stamps all pages of ctx with consecutive Bates numbers starting with start
+// and returns the next unused Bates number.
+func AddBatesNumbers(ctx *model.Context, b *model.Bates, start int) (int, error) {
+	if err := b.Validate(); err != nil {
+		return 0, err
+	}
+
+	m := map[int]*model.Watermark{}
+	for i := 1; i <= ctx.PageCount; i++ {
+		wm, err := BatesWatermark(b, start+i-1, ctx.Unit)
+		if err != nil {
+			return 0, err
+		}
+		m[i] = wm
+	}
+
+	if len(m) == 0 {
+		return start, nil
+	}
+
+	if err := AddWatermarksMap(ctx, m); err != nil {
+		return 0, err
+	}
+
+	if b.Properties {
+		properties := map[string]string{
+			"BatesBegin": b.Number(start),
+			"BatesEnd":   b.Number(start + ctx.PageCount - 1),
+		}
+		if err := PropertiesAdd(ctx, properties); err != nil {
+			return 0, err
+		}
+	}
+
+	return start + ctx.PageCount, nil
+}
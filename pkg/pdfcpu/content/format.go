@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package content
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func writeObject(b *bytes.Buffer, o types.Object) {
+	switch o := o.(type) {
+
+	case nil:
+		b.WriteString("null")
+
+	case types.Float:
+		b.WriteString(strconv.FormatFloat(o.Value(), 'f', -1, 64))
+
+	case types.Array:
+		b.WriteByte('[')
+		for i, o1 := range o {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			writeObject(b, o1)
+		}
+		b.WriteByte(']')
+
+	case types.Dict:
+		keys := make([]string, 0, len(o))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("<<")
+		for _, k := range keys {
+			b.WriteString(types.Name(k).PDFString())
+			b.WriteByte(' ')
+			writeObject(b, o[k])
+			b.WriteByte(' ')
+		}
+		b.WriteString(">>")
+
+	default:
+		b.WriteString(o.PDFString())
+	}
+}
+
+// String returns op in content stream syntax.
+func (op Operation) String() string {
+	var b bytes.Buffer
+	op.write(&b)
+	return b.String()
+}
+
+func (op Operation) write(b *bytes.Buffer) {
+	if op.Operator == "BI" && op.ImageDict != nil {
+		b.WriteString("BI ")
+		o := op.ImageDict
+		keys := make([]string, 0, len(o))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			b.WriteString(types.Name(k).PDFString())
+			b.WriteByte(' ')
+			writeObject(b, o[k])
+			b.WriteByte(' ')
+		}
+		b.WriteString("ID ")
+		b.Write(op.ImageData)
+		b.WriteString("\nEI")
+		return
+	}
+
+	for _, o := range op.Operands {
+		writeObject(b, o)
+		b.WriteByte(' ')
+	}
+	b.WriteString(op.Operator)
+}
+
+// Format returns ops as content stream.
+func Format(ops []Operation) []byte {
+	var b bytes.Buffer
+	for _, op := range ops {
+		op.write(&b)
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package content provides a parser and an interpreter for page content streams.
+package content
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// See 7.8.2 Content Streams
+
+// Operation is an operator together with its operands.
+// String literals and hex literals are kept in their raw form as parsed by model.ParseObject.
+type Operation struct {
+	Operator string
+	Operands []types.Object
+
+	// Inline images (BI ... ID ... EI) only.
+	ImageDict types.Dict
+	ImageData []byte
+}
+
+var (
+	errContentCorrupt  = errors.New("pdfcpu: corrupt content stream")
+	errUnbalancedArray = errors.New("pdfcpu: corrupt content stream: unbalanced array")
+	errUnbalancedDict  = errors.New("pdfcpu: corrupt content stream: unbalanced dict")
+)
+
+func whitespace(c byte) bool {
+	return c == 0x00 || c == 0x09 || c == 0x0A || c == 0x0C || c == 0x0D || c == 0x20
+}
+
+func delimiter(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+type lexer struct {
+	bb  []byte
+	pos int
+}
+
+func (l *lexer) skipWhitespaceAndComments() {
+	for l.pos < len(l.bb) {
+		c := l.bb[l.pos]
+		if whitespace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.bb) && l.bb[l.pos] != 0x0A && l.bb[l.pos] != 0x0D {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+func (l *lexer) regular() string {
+	i := l.pos
+	for l.pos < len(l.bb) && !whitespace(l.bb[l.pos]) && !delimiter(l.bb[l.pos]) {
+		l.pos++
+	}
+	return string(l.bb[i:l.pos])
+}
+
+func (l *lexer) stringLiteral() (types.Object, error) {
+	// l.bb[l.pos] == '('
+	i, depth := l.pos+1, 1
+	for l.pos++; l.pos < len(l.bb); l.pos++ {
+		switch l.bb[l.pos] {
+		case '\\':
+			l.pos++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				s := string(l.bb[i:l.pos])
+				l.pos++
+				return types.StringLiteral(s), nil
+			}
+		}
+	}
+	return nil, errContentCorrupt
+}
+
+func (l *lexer) hexLiteral() (types.Object, error) {
+	// l.bb[l.pos] == '<'
+	j := bytes.IndexByte(l.bb[l.pos:], '>')
+	if j < 0 {
+		return nil, errContentCorrupt
+	}
+	var b bytes.Buffer
+	for _, c := range l.bb[l.pos+1 : l.pos+j] {
+		if !whitespace(c) {
+			b.WriteByte(c)
+		}
+	}
+	l.pos += j + 1
+	return types.HexLiteral(b.String()), nil
+}
+
+func (l *lexer) name() (types.Object, error) {
+	// l.bb[l.pos] == '/'
+	l.pos++
+	s, err := types.DecodeName(l.regular())
+	if err != nil {
+		return nil, err
+	}
+	return types.Name(s), nil
+}
+
+func number(s string) (types.Object, bool) {
+	if i, err := strconv.Atoi(s); err == nil {
+		return types.Integer(i), true
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return types.Float(f), true
+	}
+	return nil, false
+}
+
+// object returns the next operand or the next operator as keyword.
+func (l *lexer) object() (o types.Object, keyword string, err error) {
+	l.skipWhitespaceAndComments()
+	if l.pos >= len(l.bb) {
+		return nil, "", nil
+	}
+
+	switch c := l.bb[l.pos]; c {
+
+	case '(':
+		o, err = l.stringLiteral()
+		return o, "", err
+
+	case '<':
+		if l.pos+1 < len(l.bb) && l.bb[l.pos+1] == '<' {
+			l.pos += 2
+			o, err = l.dict(">>")
+			return o, "", err
+		}
+		o, err = l.hexLiteral()
+		return o, "", err
+
+	case '/':
+		o, err = l.name()
+		return o, "", err
+
+	case '[':
+		l.pos++
+		o, err = l.array()
+		return o, "", err
+
+	case ']', '>', ')', '{', '}':
+		// Delimiters out of context are tolerated as keywords.
+		l.pos++
+		return nil, string(c), nil
+	}
+
+	s := l.regular()
+
+	if o, ok := number(s); ok {
+		return o, "", nil
+	}
+
+	switch s {
+	case "true":
+		return types.Boolean(true), "", nil
+	case "false":
+		return types.Boolean(false), "", nil
+	case "null":
+		return nil, "null", nil
+	}
+
+	return nil, s, nil
+}
+
+func (l *lexer) array() (types.Array, error) {
+	a := types.Array{}
+	for {
+		l.skipWhitespaceAndComments()
+		if l.pos >= len(l.bb) {
+			return nil, errUnbalancedArray
+		}
+		if l.bb[l.pos] == ']' {
+			l.pos++
+			return a, nil
+		}
+		o, kw, err := l.object()
+		if err != nil {
+			return nil, err
+		}
+		if o == nil && kw != "null" {
+			return nil, errUnbalancedArray
+		}
+		a = append(a, o)
+	}
+}
+
+// dict parses dict entries up to the terminating keyword.
+func (l *lexer) dict(terminator string) (types.Dict, error) {
+	d := types.NewDict()
+	for {
+		l.skipWhitespaceAndComments()
+		if l.pos >= len(l.bb) {
+			return nil, errUnbalancedDict
+		}
+		if bytes.HasPrefix(l.bb[l.pos:], []byte(terminator)) {
+			l.pos += len(terminator)
+			return d, nil
+		}
+		k, kw, err := l.object()
+		if err != nil {
+			return nil, err
+		}
+		if kw == terminator {
+			return d, nil
+		}
+		key, ok := k.(types.Name)
+		if !ok {
+			return nil, errUnbalancedDict
+		}
+		v, _, err := l.object()
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			d[key.Value()] = v
+		}
+	}
+}
+
+func endOfInlineImage(bb []byte, i int) bool {
+	// EI must be preceded by whitespace and followed by whitespace, a delimiter or eof.
+	if i == 0 || !whitespace(bb[i-1]) || !bytes.HasPrefix(bb[i:], []byte("EI")) {
+		return false
+	}
+	return i+2 == len(bb) || whitespace(bb[i+2]) || delimiter(bb[i+2])
+}
+
+func (l *lexer) inlineImage() (*Operation, error) {
+	d, err := l.dict("ID")
+	if err != nil {
+		return nil, err
+	}
+
+	// A single whitespace separates ID from the image data.
+	if l.pos < len(l.bb) && whitespace(l.bb[l.pos]) {
+		l.pos++
+	}
+
+	i := l.pos
+	for ; l.pos < len(l.bb); l.pos++ {
+		if endOfInlineImage(l.bb, l.pos) {
+			data := bytes.TrimRight(l.bb[i:l.pos], "\x00\x09\x0A\x0C\x0D\x20")
+			l.pos += 2
+			return &Operation{Operator: "BI", ImageDict: d, ImageData: data}, nil
+		}
+	}
+
+	return nil, errors.New("pdfcpu: corrupt content stream: unterminated inline image")
+}
+
+// Parse splits the content stream bb into operations.
+func Parse(bb []byte) ([]Operation, error) {
+	l := &lexer{bb: bb}
+
+	var (
+		ops      []Operation
+		operands []types.Object
+	)
+
+	for {
+		o, kw, err := l.object()
+		if err != nil {
+			return nil, err
+		}
+
+		if o != nil || kw == "null" {
+			operands = append(operands, o)
+			continue
+		}
+
+		if kw == "" {
+			// eof
+			break
+		}
+
+		if kw == "BI" {
+			op, err := l.inlineImage()
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, *op)
+			operands = nil
+			continue
+		}
+
+		ops = append(ops, Operation{Operator: kw, Operands: operands})
+		operands = nil
+	}
+
+	return ops, nil
+}
@@ -124,6 +124,32 @@ var (
 		model.SETVIEWERPREFERENCES:    {0, 1},
 		model.RESETVIEWERPREFERENCES:  {0, 1},
 		model.ZOOM:                    {0, 1},
+		model.SIGN:                    {0, 1},
+		model.VERIFYSIGNATURES:        {0, 0},
+		model.EXTRACTTEXT:             {1, 0},
+		model.APPLYREDACTIONS:         {0, 1},
+		model.RENDER:                  {1, 0},
+		model.CONVERTPDFA:             {0, 1},
+		model.PAGECOUNT:               {0, 0},
+		model.DIFF:                    {1, 0},
+		model.LISTREVISIONS:           {0, 0},
+		model.EXTRACTREVISION:         {0, 0},
+		model.LISTLAYERS:              {0, 0},
+		model.ADDLAYER:                {0, 1},
+		model.REMOVELAYER:             {0, 1},
+		model.SHOWLAYER:               {0, 1},
+		model.HIDELAYER:               {0, 1},
+		model.RENAMELAYER:             {0, 1},
+		model.MERGELAYERS:             {0, 1},
+		model.FLATTENLAYERS:           {0, 1},
+		model.FLATTENFORMFIELDS:       {0, 1},
+		model.FLATTENANNOTATIONS:      {0, 1},
+		model.LISTPAGELABELS:          {0, 0},
+		model.SETPAGELABELS:           {0, 1},
+		model.REMOVEPAGELABELS:        {0, 1},
+		model.BATES:                   {0, 1},
+		model.ADDHEADERFOOTER:         {0, 1},
+		model.REMOVEHEADERFOOTER:      {0, 1},
 	}
 
 	ErrUnknownEncryption = errors.New("pdfcpu: unknown encryption")
@@ -1129,8 +1155,11 @@ func validateOAndU(ctx *model.Context, d types.Dict) (o, u []byte, err error) {
 func supportedEncryption(ctx *model.Context, d types.Dict) (*model.Enc, error) {
 	// Filter
 	filter := d.NameEntry("Filter")
+	if filter != nil && *filter == pubSecFilter {
+		return supportedPubSecEncryption(ctx, d)
+	}
 	if filter == nil || *filter != "Standard" {
-		return nil, errors.New("pdfcpu: unsupported encryption: filter must be \"Standard\"")
+		return nil, errors.New("pdfcpu: unsupported encryption: filter must be \"Standard\" or \"Adobe.PubSec\"")
 	}
 
 	// SubFilter
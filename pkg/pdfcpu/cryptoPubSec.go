@@ -0,0 +1,523 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+// Functions dealing with the public-key security handler, see 7.6.5.
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// See RFC 5652 Cryptographic Message Syntax (CMS)
+
+var (
+	oidData          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEnvelopedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 3}
+	oidRSAEncryption = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidAES128CBC     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC    = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+
+	ErrMissingRecipientKey = errors.New("pdfcpu: this file is encrypted for certificate recipients, please provide a private key")
+	ErrWrongRecipientKey   = errors.New("pdfcpu: the private key does not match any recipient")
+)
+
+const (
+	pubSecFilter    = "Adobe.PubSec"
+	pubSecSubFilter = "adbe.pkcs7.s5"
+	pubSecCF        = "DefaultCryptFilter"
+	pubSecSeedLen   = 20
+)
+
+type cmsContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type cmsEnvelopedData struct {
+	Version              int
+	OriginatorInfo       asn1.RawValue   `asn1:"optional,tag:0"`
+	RecipientInfos       []asn1.RawValue `asn1:"set"`
+	EncryptedContentInfo cmsEncryptedContentInfo
+}
+
+type cmsKeyTransRecipientInfo struct {
+	Version                int
+	RID                    asn1.RawValue // issuerAndSerialNumber or [0] subjectKeyIdentifier
+	KeyEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedKey           []byte
+}
+
+type cmsIssuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type cmsEncryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+func pubSec(e *model.Enc) bool {
+	return e != nil && len(e.Recipients) > 0
+}
+
+func pkcs7Pad(b []byte, blockSize int) []byte {
+	n := blockSize - len(b)%blockSize
+	return append(b, bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+func pkcs7Unpad(b []byte, blockSize int) ([]byte, error) {
+	l := len(b)
+	if l == 0 || l%blockSize > 0 {
+		return nil, errors.New("pdfcpu: invalid padding")
+	}
+	n := int(b[l-1])
+	if n == 0 || n > blockSize || !bytes.Equal(b[l-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return nil, errors.New("pdfcpu: invalid padding")
+	}
+	return b[:l-n], nil
+}
+
+// envelope returns a DER encoded CMS EnvelopedData object holding b for the recipient cert.
+// The content is encrypted using AES-256-CBC, the content encryption key using RSA PKCS#1 v1.5.
+func envelope(b []byte, cert *x509.Certificate) ([]byte, error) {
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("pdfcpu: unsupported recipient key type for %s (need RSA)", cert.Subject)
+	}
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+
+	cb, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	data := pkcs7Pad(append([]byte{}, b...), aes.BlockSize)
+	cipher.NewCBCEncrypter(cb, iv).CryptBlocks(data, data)
+
+	encKey, err := rsa.EncryptPKCS1v15(rand.Reader, pub, key)
+	if err != nil {
+		return nil, err
+	}
+
+	rid, err := asn1.Marshal(cmsIssuerAndSerialNumber{Issuer: asn1.RawValue{FullBytes: cert.RawIssuer}, SerialNumber: cert.SerialNumber})
+	if err != nil {
+		return nil, err
+	}
+
+	ri, err := asn1.Marshal(cmsKeyTransRecipientInfo{
+		RID:                    asn1.RawValue{FullBytes: rid},
+		KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue},
+		EncryptedKey:           encKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := asn1.Marshal(cmsEnvelopedData{
+		RecipientInfos: []asn1.RawValue{{FullBytes: ri}},
+		EncryptedContentInfo: cmsEncryptedContentInfo{
+			ContentType:                oidData,
+			ContentEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: params}},
+			EncryptedContent:           asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, Bytes: data},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// encoding/asn1 writes FullBytes verbatim, so apply the explicit [0] tag here.
+	if content, err = asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: content}); err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(cmsContentInfo{ContentType: oidEnvelopedData, Content: asn1.RawValue{FullBytes: content}})
+}
+
+func matchesRecipient(rid asn1.RawValue, cert *x509.Certificate) bool {
+	if rid.Class == asn1.ClassContextSpecific && rid.Tag == 0 {
+		// subjectKeyIdentifier
+		return len(cert.SubjectKeyId) > 0 && bytes.Equal(rid.Bytes, cert.SubjectKeyId)
+	}
+	var ias cmsIssuerAndSerialNumber
+	if _, err := asn1.Unmarshal(rid.FullBytes, &ias); err != nil {
+		return false
+	}
+	return bytes.Equal(ias.Issuer.FullBytes, cert.RawIssuer) && ias.SerialNumber.Cmp(cert.SerialNumber) == 0
+}
+
+func encryptedContent(eci cmsEncryptedContentInfo) ([]byte, error) {
+	rv := eci.EncryptedContent
+	if !rv.IsCompound {
+		return rv.Bytes, nil
+	}
+
+	// Constructed encoding: a sequence of octet string segments.
+	var data []byte
+	for rest := rv.Bytes; len(rest) > 0; {
+		var seg []byte
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &seg); err != nil {
+			return nil, err
+		}
+		data = append(data, seg...)
+	}
+	return data, nil
+}
+
+// openEnvelope decrypts the content of ed using the content encryption key.
+func openEnvelope(eci cmsEncryptedContentInfo, key []byte) ([]byte, error) {
+	var iv []byte
+	if _, err := asn1.Unmarshal(eci.ContentEncryptionAlgorithm.Parameters.FullBytes, &iv); err != nil {
+		return nil, err
+	}
+
+	var (
+		cb  cipher.Block
+		err error
+	)
+
+	alg := eci.ContentEncryptionAlgorithm.Algorithm
+	switch {
+	case alg.Equal(oidAES128CBC) || alg.Equal(oidAES192CBC) || alg.Equal(oidAES256CBC):
+		cb, err = aes.NewCipher(key)
+	case alg.Equal(oidDESEDE3CBC):
+		cb, err = des.NewTripleDESCipher(key)
+	default:
+		return nil, errors.Errorf("pdfcpu: unsupported content encryption algorithm: %s", alg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := encryptedContent(eci)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(iv) != cb.BlockSize() || len(data) == 0 || len(data)%cb.BlockSize() > 0 {
+		return nil, errors.New("pdfcpu: corrupt enveloped data")
+	}
+
+	b := make([]byte, len(data))
+	cipher.NewCBCDecrypter(cb, iv).CryptBlocks(b, data)
+
+	return pkcs7Unpad(b, cb.BlockSize())
+}
+
+// openRecipient returns the content of the CMS EnvelopedData object b for the recipient key.
+// If cert is present only recipient infos for cert are considered.
+func openRecipient(b []byte, key *rsa.PrivateKey, cert *x509.Certificate) ([]byte, error) {
+	var ci cmsContentInfo
+	if _, err := asn1.Unmarshal(b, &ci); err != nil {
+		return nil, err
+	}
+	if !ci.ContentType.Equal(oidEnvelopedData) {
+		return nil, errors.New("pdfcpu: recipient is not enveloped data")
+	}
+
+	var ed cmsEnvelopedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &ed); err != nil {
+		return nil, err
+	}
+
+	for _, rv := range ed.RecipientInfos {
+		var ri cmsKeyTransRecipientInfo
+		if _, err := asn1.Unmarshal(rv.FullBytes, &ri); err != nil {
+			// Some other kind of recipient info.
+			continue
+		}
+		if !ri.KeyEncryptionAlgorithm.Algorithm.Equal(oidRSAEncryption) {
+			continue
+		}
+		if cert != nil && !matchesRecipient(ri.RID, cert) {
+			continue
+		}
+		k, err := rsa.DecryptPKCS1v15(nil, key, ri.EncryptedKey)
+		if err != nil {
+			continue
+		}
+		if b, err := openEnvelope(ed.EncryptedContentInfo, k); err == nil {
+			return b, nil
+		}
+	}
+
+	return nil, ErrWrongRecipientKey
+}
+
+// pubSecSeed returns the seed and the permissions for key from the first matching recipient.
+func pubSecSeed(recipients [][]byte, key *rsa.PrivateKey, cert *x509.Certificate) ([]byte, int, error) {
+	for _, r := range recipients {
+		b, err := openRecipient(r, key, cert)
+		if err != nil {
+			continue
+		}
+		if len(b) < pubSecSeedLen+4 {
+			return nil, 0, errors.New("pdfcpu: corrupt recipient content")
+		}
+		p := int32(binary.BigEndian.Uint32(b[pubSecSeedLen : pubSecSeedLen+4]))
+		return b[:pubSecSeedLen], int(p), nil
+	}
+	return nil, 0, ErrWrongRecipientKey
+}
+
+// pubSecFileKey computes the file encryption key (see 7.6.5.3).
+func pubSecFileKey(seed []byte, recipients [][]byte, emd bool, keyLength int) []byte {
+	var h hash.Hash = sha1.New()
+	if keyLength == 256 {
+		h = sha256.New()
+	}
+
+	h.Write(seed)
+	for _, r := range recipients {
+		h.Write(r)
+	}
+	if !emd {
+		h.Write([]byte{0xff, 0xff, 0xff, 0xff})
+	}
+
+	return h.Sum(nil)[:keyLength/8]
+}
+
+// newPubSecEncryptDict creates a new EncryptDict using the public-key security handler with AES-256.
+func newPubSecEncryptDict(recipients [][]byte) types.Dict {
+	d := types.NewDict()
+
+	d.Insert("Filter", types.Name(pubSecFilter))
+	d.Insert("SubFilter", types.Name(pubSecSubFilter))
+	d.Insert("V", types.Integer(5))
+	d.Insert("Length", types.Integer(256))
+
+	d.Insert("StmF", types.Name(pubSecCF))
+	d.Insert("StrF", types.Name(pubSecCF))
+
+	a := types.Array{}
+	for _, r := range recipients {
+		a = append(a, types.HexLiteral(hex.EncodeToString(r)))
+	}
+
+	d1 := types.NewDict()
+	d1.Insert("CFM", types.Name("AESV3"))
+	d1.Insert("AuthEvent", types.Name("DocOpen"))
+	d1.Insert("Length", types.Integer(256))
+	d1.Insert("Recipients", a)
+	d1.Insert("EncryptMetadata", types.Boolean(true))
+
+	d2 := types.NewDict()
+	d2.Insert(pubSecCF, d1)
+
+	d.Insert("CF", d2)
+
+	return d
+}
+
+// pubSecCryptFilter returns the crypt filter dict holding the recipients.
+func pubSecCryptFilter(d types.Dict) (types.Dict, error) {
+	cfDict := d.DictEntry("CF")
+	if cfDict == nil {
+		return nil, errors.New("pdfcpu: unsupported encryption: required entry \"CF\" missing")
+	}
+
+	for _, k := range []string{"StmF", "StrF", "EFF"} {
+		n := d.NameEntry(k)
+		if n == nil || *n == "Identity" {
+			continue
+		}
+		if d1 := cfDict.DictEntry(*n); d1 != nil && d1.ArrayEntry("Recipients") != nil {
+			return d1, nil
+		}
+	}
+
+	return nil, errors.New("pdfcpu: unsupported encryption: missing \"Recipients\"")
+}
+
+func recipients(a types.Array) ([][]byte, error) {
+	var rr [][]byte
+	for _, o := range a {
+		var (
+			bb  []byte
+			err error
+		)
+		switch o := o.(type) {
+		case types.StringLiteral:
+			bb, err = types.Unescape(o.Value())
+		case types.HexLiteral:
+			bb, err = o.Bytes()
+		default:
+			err = errors.New("pdfcpu: unsupported encryption: invalid \"Recipients\"")
+		}
+		if err != nil {
+			return nil, err
+		}
+		rr = append(rr, bb)
+	}
+	if len(rr) == 0 {
+		return nil, errors.New("pdfcpu: unsupported encryption: missing \"Recipients\"")
+	}
+	return rr, nil
+}
+
+// supportedPubSecEncryption returns a pointer to a struct encapsulating used public-key encryption.
+func supportedPubSecEncryption(ctx *model.Context, d types.Dict) (*model.Enc, error) {
+	sf := d.NameEntry("SubFilter")
+	if sf == nil || *sf != pubSecSubFilter {
+		return nil, errors.Errorf("pdfcpu: unsupported encryption: \"SubFilter\" must be \"%s\"", pubSecSubFilter)
+	}
+
+	l, err := length(d)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := checkV(ctx, d, l)
+	if err != nil {
+		return nil, err
+	}
+	if *v != 4 && *v != 5 {
+		return nil, errors.New("pdfcpu: unsupported encryption: \"V\" must be 4 or 5")
+	}
+	if !ctx.AES4Streams && !ctx.AES4Strings {
+		return nil, errors.New("pdfcpu: unsupported encryption: need AES")
+	}
+
+	cf, err := pubSecCryptFilter(d)
+	if err != nil {
+		return nil, err
+	}
+
+	rr, err := recipients(cf.ArrayEntry("Recipients"))
+	if err != nil {
+		return nil, err
+	}
+
+	encMeta := true
+	if emd := cf.BooleanEntry("EncryptMetadata"); emd != nil {
+		encMeta = *emd
+	}
+
+	// Use the revision of the standard security handler with equivalent file key usage.
+	l, r := 128, 4
+	if *v == 5 {
+		l, r = 256, 5
+	}
+
+	return &model.Enc{
+			L:          l,
+			R:          r,
+			V:          *v,
+			Emd:        encMeta,
+			Recipients: rr},
+		nil
+}
+
+// setupPubSecEncryptionKey calculates the file encryption key using the supplied recipient key.
+func setupPubSecEncryptionKey(ctx *model.Context) error {
+	if ctx.RecipientKey == nil {
+		return ErrMissingRecipientKey
+	}
+
+	key, ok := ctx.RecipientKey.(*rsa.PrivateKey)
+	if !ok {
+		return errors.New("pdfcpu: unsupported recipient key type (need RSA)")
+	}
+
+	seed, p, err := pubSecSeed(ctx.E.Recipients, key, ctx.RecipientCert)
+	if err != nil {
+		return err
+	}
+
+	ctx.E.P = p
+	ctx.EncKey = pubSecFileKey(seed, ctx.E.Recipients, ctx.E.Emd, ctx.E.L)
+
+	if !hasNeededPermissions(ctx.Cmd, ctx.E) {
+		return errors.New("pdfcpu: operation restricted via pdfcpu's permission bits setting")
+	}
+
+	return nil
+}
+
+// setupPubSecEncryption creates the EncryptDict and the file encryption key for ctx.Recipients.
+func setupPubSecEncryption(ctx *model.Context) (types.Dict, error) {
+	if !ctx.EncryptUsingAES || ctx.EncryptKeyLength != 256 {
+		return nil, errors.New("pdfcpu: public-key encryption supports AES/256 only")
+	}
+
+	seed := make([]byte, pubSecSeedLen)
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, err
+	}
+
+	// The enveloped content is the seed followed by the permissions, high-order byte first.
+	p := int32(ctx.Permissions)
+	b := binary.BigEndian.AppendUint32(append([]byte{}, seed...), uint32(p))
+
+	rr := make([][]byte, len(ctx.Recipients))
+	for i, cert := range ctx.Recipients {
+		r, err := envelope(b, cert)
+		if err != nil {
+			return nil, err
+		}
+		rr[i] = r
+	}
+
+	d := newPubSecEncryptDict(rr)
+
+	var err error
+	if ctx.E, err = supportedEncryption(ctx, d); err != nil {
+		return nil, err
+	}
+
+	ctx.E.P = int(p)
+	ctx.EncKey = pubSecFileKey(seed, rr, ctx.E.Emd, ctx.E.L)
+
+	return d, nil
+}
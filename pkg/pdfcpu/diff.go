@@ -0,0 +1,606 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/form"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Content stream diffs beyond this number of compared operation pairs are reported as complete replacement.
+const maxContentDiffCells = 1 << 22
+
+// Change represents a named value differing between two documents.
+type Change struct {
+	Name string `json:"name"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %q -> %q", c.Name, c.Old, c.New)
+}
+
+// AnnotationDesc identifies a page annotation within a diff.
+type AnnotationDesc struct {
+	Type    string `json:"type"`
+	ID      string `json:"id,omitempty"`
+	Rect    string `json:"rect"`
+	Content string `json:"content,omitempty"`
+}
+
+func (a AnnotationDesc) String() string {
+	s := a.Type
+	if a.ID != "" {
+		s += " " + a.ID
+	}
+	s += " " + a.Rect
+	if a.Content != "" {
+		s += fmt.Sprintf(" %q", a.Content)
+	}
+	return s
+}
+
+// key matches annotations of two document versions by id, or by type and position for annotations w/o id.
+func (a AnnotationDesc) key() string {
+	if a.ID != "" {
+		return a.Type + "/" + a.ID
+	}
+	return a.Type + "/" + a.Rect
+}
+
+// AnnotationChange represents an annotation present in both documents with differing position or content.
+type AnnotationChange struct {
+	Old AnnotationDesc `json:"old"`
+	New AnnotationDesc `json:"new"`
+}
+
+// PageDiff represents the differences of a page present in both documents.
+type PageDiff struct {
+	PageNr             int                `json:"page"`
+	Boxes              []Change           `json:"boxes,omitempty"`
+	AnnotationsAdded   []AnnotationDesc   `json:"annotationsAdded,omitempty"`
+	AnnotationsRemoved []AnnotationDesc   `json:"annotationsRemoved,omitempty"`
+	AnnotationsChanged []AnnotationChange `json:"annotationsChanged,omitempty"`
+	// Content lists removed content stream operations prefixed by "-" and added ones prefixed by "+".
+	Content []string `json:"content,omitempty"`
+}
+
+func (pd PageDiff) empty() bool {
+	return len(pd.Boxes) == 0 &&
+		len(pd.AnnotationsAdded) == 0 &&
+		len(pd.AnnotationsRemoved) == 0 &&
+		len(pd.AnnotationsChanged) == 0 &&
+		len(pd.Content) == 0
+}
+
+// Diff represents the structural and textual differences between two documents.
+type Diff struct {
+	PageCount1         int        `json:"pageCount1"`
+	PageCount2         int        `json:"pageCount2"`
+	Properties         []Change   `json:"properties,omitempty"`
+	KeywordsAdded      []string   `json:"keywordsAdded,omitempty"`
+	KeywordsRemoved    []string   `json:"keywordsRemoved,omitempty"`
+	AttachmentsAdded   []string   `json:"attachmentsAdded,omitempty"`
+	AttachmentsRemoved []string   `json:"attachmentsRemoved,omitempty"`
+	AttachmentsChanged []string   `json:"attachmentsChanged,omitempty"`
+	FieldsAdded        []string   `json:"fieldsAdded,omitempty"`
+	FieldsRemoved      []string   `json:"fieldsRemoved,omitempty"`
+	FieldsChanged      []Change   `json:"fieldsChanged,omitempty"`
+	Pages              []PageDiff `json:"pages,omitempty"`
+}
+
+// Equal returns true if no differences were found.
+func (d Diff) Equal() bool {
+	return d.PageCount1 == d.PageCount2 &&
+		len(d.Properties) == 0 &&
+		len(d.KeywordsAdded) == 0 &&
+		len(d.KeywordsRemoved) == 0 &&
+		len(d.AttachmentsAdded) == 0 &&
+		len(d.AttachmentsRemoved) == 0 &&
+		len(d.AttachmentsChanged) == 0 &&
+		len(d.FieldsAdded) == 0 &&
+		len(d.FieldsRemoved) == 0 &&
+		len(d.FieldsChanged) == 0 &&
+		len(d.Pages) == 0
+}
+
+func appendList(ss []string, title string, vv []string) []string {
+	if len(vv) > 0 {
+		ss = append(ss, fmt.Sprintf("%s: %s", title, strings.Join(vv, ", ")))
+	}
+	return ss
+}
+
+// Strings returns a textual report of d.
+func (d Diff) Strings() []string {
+	if d.Equal() {
+		return []string{"no differences"}
+	}
+
+	ss := []string{}
+
+	if d.PageCount1 != d.PageCount2 {
+		ss = append(ss, fmt.Sprintf("page count: %d -> %d", d.PageCount1, d.PageCount2))
+	}
+
+	if len(d.Properties) > 0 {
+		ss = append(ss, "properties:")
+		for _, c := range d.Properties {
+			ss = append(ss, "   "+c.String())
+		}
+	}
+
+	ss = appendList(ss, "keywords added", d.KeywordsAdded)
+	ss = appendList(ss, "keywords removed", d.KeywordsRemoved)
+	ss = appendList(ss, "attachments added", d.AttachmentsAdded)
+	ss = appendList(ss, "attachments removed", d.AttachmentsRemoved)
+	ss = appendList(ss, "attachments changed", d.AttachmentsChanged)
+	ss = appendList(ss, "form fields added", d.FieldsAdded)
+	ss = appendList(ss, "form fields removed", d.FieldsRemoved)
+
+	if len(d.FieldsChanged) > 0 {
+		ss = append(ss, "form fields changed:")
+		for _, c := range d.FieldsChanged {
+			ss = append(ss, "   "+c.String())
+		}
+	}
+
+	for _, pd := range d.Pages {
+		ss = append(ss, fmt.Sprintf("page %d:", pd.PageNr))
+		for _, c := range pd.Boxes {
+			ss = append(ss, fmt.Sprintf("   %s: %s -> %s", c.Name, c.Old, c.New))
+		}
+		for _, a := range pd.AnnotationsAdded {
+			ss = append(ss, "   annotation added: "+a.String())
+		}
+		for _, a := range pd.AnnotationsRemoved {
+			ss = append(ss, "   annotation removed: "+a.String())
+		}
+		for _, ac := range pd.AnnotationsChanged {
+			ss = append(ss, fmt.Sprintf("   annotation changed: %s -> %s", ac.Old, ac.New))
+		}
+		if len(pd.Content) > 0 {
+			ss = append(ss, "   content:")
+			for _, s := range pd.Content {
+				ss = append(ss, "      "+s)
+			}
+		}
+	}
+
+	return ss
+}
+
+func properties(ctx *model.Context) map[string]string {
+	m := map[string]string{
+		"Title":        ctx.XRefTable.Title,
+		"Subject":      ctx.XRefTable.Subject,
+		"Author":       ctx.XRefTable.Author,
+		"Creator":      ctx.XRefTable.Creator,
+		"Producer":     ctx.XRefTable.Producer,
+		"CreationDate": ctx.XRefTable.CreationDate,
+		"ModDate":      ctx.XRefTable.ModDate,
+	}
+	for k, v := range ctx.Properties {
+		m[k] = v
+	}
+	return m
+}
+
+func diffProperties(ctx1, ctx2 *model.Context) []Change {
+	m1, m2 := properties(ctx1), properties(ctx2)
+
+	keys := map[string]bool{}
+	for k := range m1 {
+		keys[k] = true
+	}
+	for k := range m2 {
+		keys[k] = true
+	}
+
+	cc := []Change{}
+	for k := range keys {
+		if m1[k] != m2[k] {
+			cc = append(cc, Change{Name: k, Old: m1[k], New: m2[k]})
+		}
+	}
+	sort.Slice(cc, func(i, j int) bool { return cc[i].Name < cc[j].Name })
+
+	return cc
+}
+
+// addedAndRemoved returns the sorted keys only present in m2 and the sorted keys only present in m1.
+func addedAndRemoved[V any](m1, m2 map[string]V) ([]string, []string) {
+	var added, removed []string
+	for k := range m2 {
+		if _, ok := m1[k]; !ok {
+			added = append(added, k)
+		}
+	}
+	for k := range m1 {
+		if _, ok := m2[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func diffKeywords(d *Diff, ctx1, ctx2 *model.Context) {
+	m1, m2 := map[string]bool{}, map[string]bool{}
+	for k, v := range ctx1.KeywordList {
+		if v {
+			m1[k] = true
+		}
+	}
+	for k, v := range ctx2.KeywordList {
+		if v {
+			m2[k] = true
+		}
+	}
+	d.KeywordsAdded, d.KeywordsRemoved = addedAndRemoved(m1, m2)
+}
+
+// attachmentDigests returns the digests of description, modification date and content for all attachments by file name.
+func attachmentDigests(ctx *model.Context) (map[string][32]byte, error) {
+	m := map[string][32]byte{}
+
+	aa, err := ctx.ListAttachments()
+	if err != nil || len(aa) == 0 {
+		return m, err
+	}
+
+	if aa, err = ctx.ExtractAttachments(nil); err != nil {
+		return nil, err
+	}
+
+	for _, a := range aa {
+		h := sha256.New()
+		h.Write([]byte(a.Desc))
+		if a.ModTime != nil {
+			h.Write([]byte(a.ModTime.String()))
+		}
+		if a.Reader != nil {
+			if _, err := io.Copy(h, a.Reader); err != nil {
+				return nil, err
+			}
+		}
+		var sum [32]byte
+		copy(sum[:], h.Sum(nil))
+		m[a.FileName] = sum
+	}
+
+	return m, nil
+}
+
+func diffAttachments(d *Diff, ctx1, ctx2 *model.Context) error {
+	m1, err := attachmentDigests(ctx1)
+	if err != nil {
+		return err
+	}
+
+	m2, err := attachmentDigests(ctx2)
+	if err != nil {
+		return err
+	}
+
+	d.AttachmentsAdded, d.AttachmentsRemoved = addedAndRemoved(m1, m2)
+
+	for fn, sum := range m1 {
+		if sum2, ok := m2[fn]; ok && sum != sum2 {
+			d.AttachmentsChanged = append(d.AttachmentsChanged, fn)
+		}
+	}
+	sort.Strings(d.AttachmentsChanged)
+
+	return nil
+}
+
+// fieldValues returns the values of all form fields by fully qualified name, or by id for unnamed fields.
+func fieldValues(ctx *model.Context) (map[string]string, error) {
+	m := map[string]string{}
+
+	if ctx.Form == nil {
+		return m, nil
+	}
+
+	ff, _, err := form.FormFields(ctx)
+	if err != nil {
+		if errors.Is(err, form.ErrNoFormFields) {
+			return m, nil
+		}
+		return nil, err
+	}
+
+	for _, f := range ff {
+		k := f.Name
+		if k == "" {
+			k = f.ID
+		}
+		m[k] = f.V
+	}
+
+	return m, nil
+}
+
+func diffFormFields(d *Diff, ctx1, ctx2 *model.Context) error {
+	m1, err := fieldValues(ctx1)
+	if err != nil {
+		return err
+	}
+
+	m2, err := fieldValues(ctx2)
+	if err != nil {
+		return err
+	}
+
+	d.FieldsAdded, d.FieldsRemoved = addedAndRemoved(m1, m2)
+
+	for k, v1 := range m1 {
+		if v2, ok := m2[k]; ok && v1 != v2 {
+			d.FieldsChanged = append(d.FieldsChanged, Change{Name: k, Old: v1, New: v2})
+		}
+	}
+	sort.Slice(d.FieldsChanged, func(i, j int) bool { return d.FieldsChanged[i].Name < d.FieldsChanged[j].Name })
+
+	return nil
+}
+
+func rectString(r *types.Rectangle) string {
+	if r == nil {
+		return "none"
+	}
+	return fmt.Sprintf("(%.2f, %.2f, %.2f, %.2f)", r.LL.X, r.LL.Y, r.UR.X, r.UR.Y)
+}
+
+func boxRect(b *model.Box) *types.Rectangle {
+	if b == nil {
+		return nil
+	}
+	return b.Rect
+}
+
+func diffBoxes(pd *PageDiff, pb1, pb2 model.PageBoundaries) {
+	for _, b := range []struct {
+		name   string
+		r1, r2 *types.Rectangle
+	}{
+		{"MediaBox", boxRect(pb1.Media), boxRect(pb2.Media)},
+		{"CropBox", pb1.CropBox(), pb2.CropBox()},
+	} {
+		if s1, s2 := rectString(b.r1), rectString(b.r2); s1 != s2 {
+			pd.Boxes = append(pd.Boxes, Change{Name: b.name, Old: s1, New: s2})
+		}
+	}
+
+	if pb1.Rot != pb2.Rot {
+		pd.Boxes = append(pd.Boxes, Change{Name: "Rotate", Old: strconv.Itoa(pb1.Rot), New: strconv.Itoa(pb2.Rot)})
+	}
+}
+
+func pageAnnotations(ctx *model.Context, pageNr int) map[string]AnnotationDesc {
+	m := map[string]AnnotationDesc{}
+	for annType, annots := range ctx.PageAnnots[pageNr] {
+		for _, ann := range annots.Map {
+			r := ann.Rectangle()
+			a := AnnotationDesc{
+				Type:    model.AnnotTypeStrings[annType],
+				ID:      ann.ID(),
+				Rect:    rectString(&r),
+				Content: ann.ContentString(),
+			}
+			m[a.key()] = a
+		}
+	}
+	return m
+}
+
+func diffAnnotations(pd *PageDiff, ctx1, ctx2 *model.Context) {
+	m1, m2 := pageAnnotations(ctx1, pd.PageNr), pageAnnotations(ctx2, pd.PageNr)
+
+	added, removed := addedAndRemoved(m1, m2)
+	for _, k := range added {
+		pd.AnnotationsAdded = append(pd.AnnotationsAdded, m2[k])
+	}
+	for _, k := range removed {
+		pd.AnnotationsRemoved = append(pd.AnnotationsRemoved, m1[k])
+	}
+
+	var keys []string
+	for k, a1 := range m1 {
+		if a2, ok := m2[k]; ok && a1 != a2 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		pd.AnnotationsChanged = append(pd.AnnotationsChanged, AnnotationChange{Old: m1[k], New: m2[k]})
+	}
+}
+
+// pageOperations returns the normalized operations of the content stream of page pageNr, one per line.
+func pageOperations(ctx *model.Context, pageNr int) ([]string, error) {
+	d, _, _, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return nil, err
+	}
+
+	bb, err := ctx.PageContent(d)
+	if err != nil {
+		if err == model.ErrNoContent {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ops, err := content.Parse(bb)
+	if err != nil {
+		return nil, err
+	}
+
+	ss := make([]string, len(ops))
+	for i, op := range ops {
+		ss[i] = op.String()
+	}
+
+	return ss, nil
+}
+
+// diffLines returns a minimal line diff transforming aa into bb.
+// Removed lines are prefixed by "-", added lines by "+".
+func diffLines(aa, bb []string) []string {
+	// Skip common prefix and suffix.
+	for len(aa) > 0 && len(bb) > 0 && aa[0] == bb[0] {
+		aa, bb = aa[1:], bb[1:]
+	}
+	for len(aa) > 0 && len(bb) > 0 && aa[len(aa)-1] == bb[len(bb)-1] {
+		aa, bb = aa[:len(aa)-1], bb[:len(bb)-1]
+	}
+
+	ss := []string{}
+
+	n, m := len(aa), len(bb)
+	if n*m > maxContentDiffCells {
+		for _, s := range aa {
+			ss = append(ss, "- "+s)
+		}
+		for _, s := range bb {
+			ss = append(ss, "+ "+s)
+		}
+		return ss
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of aa[i:] and bb[j:].
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if aa[i] == bb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case aa[i] == bb[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ss = append(ss, "- "+aa[i])
+			i++
+		default:
+			ss = append(ss, "+ "+bb[j])
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ss = append(ss, "- "+aa[i])
+	}
+	for ; j < m; j++ {
+		ss = append(ss, "+ "+bb[j])
+	}
+
+	return ss
+}
+
+func diffContent(pd *PageDiff, ctx1, ctx2 *model.Context) error {
+	ss1, err := pageOperations(ctx1, pd.PageNr)
+	if err != nil {
+		return err
+	}
+
+	ss2, err := pageOperations(ctx2, pd.PageNr)
+	if err != nil {
+		return err
+	}
+
+	if slices.Equal(ss1, ss2) {
+		return nil
+	}
+
+	pd.Content = diffLines(ss1, ss2)
+
+	return nil
+}
+
+func diffPages(d *Diff, ctx1, ctx2 *model.Context) error {
+	pbs1, err := ctx1.PageBoundaries(nil)
+	if err != nil {
+		return err
+	}
+
+	pbs2, err := ctx2.PageBoundaries(nil)
+	if err != nil {
+		return err
+	}
+
+	for i := 1; i <= min(ctx1.PageCount, ctx2.PageCount); i++ {
+		pd := PageDiff{PageNr: i}
+		diffBoxes(&pd, pbs1[i-1], pbs2[i-1])
+		diffAnnotations(&pd, ctx1, ctx2)
+		if err := diffContent(&pd, ctx1, ctx2); err != nil {
+			return err
+		}
+		if !pd.empty() {
+			d.Pages = append(d.Pages, pd)
+		}
+	}
+
+	return nil
+}
+
+// DiffContexts compares two documents page by page and returns their differences.
+// Page content gets compared on operation level ignoring formatting of the content streams.
+func DiffContexts(ctx1, ctx2 *model.Context) (*Diff, error) {
+	d := &Diff{PageCount1: ctx1.PageCount, PageCount2: ctx2.PageCount}
+
+	d.Properties = diffProperties(ctx1, ctx2)
+
+	diffKeywords(d, ctx1, ctx2)
+
+	if err := diffAttachments(d, ctx1, ctx2); err != nil {
+		return nil, err
+	}
+
+	if err := diffFormFields(d, ctx1, ctx2); err != nil {
+		return nil, err
+	}
+
+	if err := diffPages(d, ctx1, ctx2); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
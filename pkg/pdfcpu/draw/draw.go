@@ -141,6 +141,19 @@ func FillRectNoBorder(w io.Writer, r *types.Rectangle, fillCol color.SimpleColor
 	fmt.Fprintf(w, "Q ")
 }
 
+// FillRects fills the rectangular paths for rr using fillCol in a single painting operation.
+func FillRects(w io.Writer, rr []*types.Rectangle, fillCol color.SimpleColor) {
+	if len(rr) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "q ")
+	SetFillColor(w, fillCol)
+	for _, r := range rr {
+		fmt.Fprintf(w, "%.3f %.3f %.3f %.3f re ", r.LL.X, r.LL.Y, r.Width(), r.Height())
+	}
+	fmt.Fprintf(w, "f Q ")
+}
+
 // DrawGrid draws an x * y grid on r using strokeCol and fillCol.
 func DrawGrid(w io.Writer, x, y int, r *types.Rectangle, strokeCol color.SimpleColor, fillCol *color.SimpleColor) {
 
@@ -276,7 +276,17 @@ func imageStub(
 	if i := sd.IntEntry("BitsPerComponent"); i != nil {
 		bpc = *i
 	}
-	// if jpx, bpc is undefined
+	if lastFilter == filter.JPX {
+		// JPXDecode images take their bit depth and a missing color space from the JPEG 2000 data.
+		if data, _, err := imageData(sd); err == nil {
+			if info, err := filter.DecodeJPXInfo(data); err == nil {
+				bpc = info.Bpc
+				if cs == "" {
+					cs, comp = info.ColorSpace, info.Comps
+				}
+			}
+		}
+	}
 	if imgMask {
 		bpc = 1
 	}
@@ -355,8 +365,8 @@ func prepareExtractImage(sd *types.StreamDict) (string, string, types.Dict, bool
 	return filters, lastFilter, d, imgMask
 }
 func decodeImage(ctx *model.Context, sd *types.StreamDict, filters, lastFilter string, objNr int) error {
-	// CCITTDecoded images / (bit) masks don't have a ColorSpace attribute, but we render image files.
-	if lastFilter == filter.CCITTFax {
+	// CCITTDecoded and JBIG2Decoded images / (bit) masks don't have a ColorSpace attribute, but we render image files.
+	if lastFilter == filter.CCITTFax || lastFilter == filter.JBIG2 {
 		if _, err := ctx.DereferenceDictEntry(sd.Dict, "ColorSpace"); err != nil {
 			sd.InsertName("ColorSpace", model.DeviceGrayCS)
 		}
@@ -370,9 +380,15 @@ func decodeImage(ctx *model.Context, sd *types.StreamDict, filters, lastFilter s
 		sd.CSComponents = comp
 	}
 
+	if lastFilter == filter.JBIG2 {
+		if err := ctx.ResolveJBIG2Globals(sd); err != nil {
+			return err
+		}
+	}
+
 	switch lastFilter {
 
-	case filter.DCT, filter.JPX, filter.Flate, filter.LZW, filter.CCITTFax, filter.RunLength:
+	case filter.DCT, filter.JPX, filter.Flate, filter.LZW, filter.CCITTFax, filter.RunLength, filter.JBIG2:
 		if err := sd.Decode(); err != nil {
 			return err
 		}
@@ -617,6 +633,10 @@ func ExtractPages(ctx *model.Context, pageNrs []int, usePgCache bool) (*model.Co
 		return nil, err
 	}
 
+	if err := copyPageLabels(ctx, ctxDest, pageNrs); err != nil {
+		return nil, err
+	}
+
 	return ctxDest, nil
 }
 
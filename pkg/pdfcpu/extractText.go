@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"math"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// See 9.4 Text Objects
+
+// TextGlyph is a single glyph of extracted text.
+type TextGlyph struct {
+	Text string           `json:"text"`
+	BBox *types.Rectangle `json:"bbox"` // in user space
+}
+
+// TextLine is a sequence of glyphs sharing a common baseline.
+type TextLine struct {
+	Text     string           `json:"text"`
+	Font     string           `json:"font"`
+	FontSize float64          `json:"fontSize"` // in user space
+	BBox     *types.Rectangle `json:"bbox"`     // in user space
+	Glyphs   []TextGlyph      `json:"glyphs"`
+
+	origin, end types.Point // baseline
+	dir         types.Point // unit vector along the baseline
+}
+
+// PageText is the text of a page in content stream order.
+type PageText struct {
+	PageNr int        `json:"page"`
+	Lines  []TextLine `json:"lines"`
+}
+
+// String returns the plain text of pt, one line per text line.
+func (pt PageText) String() string {
+	var sb strings.Builder
+	for _, l := range pt.Lines {
+		sb.WriteString(l.Text)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+type textExtractor struct {
+	*interpreter
+	lines []TextLine
+}
+
+// addGlyph appends a glyph to the current line or starts a new line.
+func (te *textExtractor) addGlyph(g TextGlyph, origin, end types.Point, fontName string, fontSize float64) {
+	d := distance(origin, end)
+	if d == 0 {
+		d = 1
+	}
+	dir := types.Point{X: (end.X - origin.X) / d, Y: (end.Y - origin.Y) / d}
+
+	if n := len(te.lines); n > 0 {
+		l := &te.lines[n-1]
+		// Offset of origin relative to the end of the current line.
+		dx, dy := origin.X-l.end.X, origin.Y-l.end.Y
+		along := dx*l.dir.X + dy*l.dir.Y
+		across := -dx*l.dir.Y + dy*l.dir.X
+		sameDir := math.Abs(dir.X-l.dir.X) < 0.01 && math.Abs(dir.Y-l.dir.Y) < 0.01
+		size := math.Max(l.FontSize, fontSize)
+
+		if sameDir && math.Abs(across) < size/2 && along > -size && along < 3*size {
+			if along > 0.15*size && g.Text != " " && !strings.HasSuffix(l.Text, " ") {
+				l.Text += " "
+			}
+			l.Text += g.Text
+			l.Glyphs = append(l.Glyphs, g)
+			l.BBox = union(l.BBox, g.BBox)
+			l.end = end
+			return
+		}
+	}
+
+	te.lines = append(te.lines, TextLine{
+		Text:     g.Text,
+		Font:     fontName,
+		FontSize: math.Round(fontSize*100) / 100,
+		BBox:     g.BBox,
+		Glyphs:   []TextGlyph{g},
+		origin:   origin,
+		end:      end,
+		dir:      dir,
+	})
+}
+
+func (te *textExtractor) glyph(g placedGlyph) {
+	if g.Text != "" {
+		te.addGlyph(TextGlyph{Text: g.Text, BBox: g.bbox}, g.origin, g.end, te.gs.font.Name, g.size)
+	}
+}
+
+func (te *textExtractor) process(ops []content.Operation, resources types.Dict, depth int) error {
+	for _, op := range ops {
+		te.state(op, resources)
+
+		switch op.Operator {
+
+		case "Tj", "'", "\"":
+			te.showString(lastOperand(op.Operands), te.glyph)
+
+		case "TJ":
+			if a, ok := lastOperand(op.Operands).(types.Array); ok {
+				te.showArray(a, te.glyph)
+			}
+
+		case "Do":
+			if n, ok := lastOperand(op.Operands).(types.Name); ok {
+				if err := te.form(resources, n.Value(), depth); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// form processes the form XObject fName.
+func (te *textExtractor) form(resources types.Dict, fName string, depth int) error {
+	if depth >= maxFormDepth {
+		return nil
+	}
+
+	sd, ops, res, err := te.formXObject(resources, fName)
+	if err != nil || sd == nil {
+		return err
+	}
+
+	restore := te.enterForm(sd)
+	defer restore()
+
+	return te.process(ops, res, depth+1)
+}
+
+// ExtractPageText returns the text of page pageNr including glyph and line bounding boxes in user space.
+func ExtractPageText(ctx *model.Context, pageNr int) (*PageText, error) {
+	d, _, inhPAttrs, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, errors.Errorf("pdfcpu: ExtractPageText: missing page %d", pageNr)
+	}
+
+	pt := &PageText{PageNr: pageNr, Lines: []TextLine{}}
+
+	bb, err := ctx.PageContent(d)
+	if err == model.ErrNoContent {
+		return pt, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ops, err := content.Parse(bb)
+	if err != nil {
+		return nil, err
+	}
+
+	te := &textExtractor{interpreter: newInterpreter(ctx)}
+
+	if err := te.process(ops, inhPAttrs.Resources, 0); err != nil {
+		return nil, err
+	}
+
+	if te.lines != nil {
+		pt.Lines = te.lines
+	}
+
+	return pt, nil
+}
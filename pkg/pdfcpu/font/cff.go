@@ -0,0 +1,784 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/raster"
+)
+
+// See Adobe Technical Note #5176 The Compact Font Format Specification
+// and Adobe Technical Note #5177 The Type 2 Charstring Format
+
+const (
+	maxSubrDepth     = 10
+	maxCharStringOps = 100000
+)
+
+// cff represents the parts of a CFF font program needed for rendering glyphs.
+type cff struct {
+	charStrings [][]byte
+	gsubrs      [][]byte
+	subrs       [][][]byte     // local subroutines per font dict
+	fdSelect    []byte         // glyph id -> font dict, CID-keyed fonts only
+	cidToGID    map[int]uint16 // CID-keyed fonts only
+	names       map[string]uint16
+	encoding    map[byte]uint16 // built-in encoding: code -> glyph id
+	fontMatrix  matrix.Matrix
+}
+
+type cffDict map[int][]float64
+
+func cffIndex(bb []byte, off int) ([][]byte, int, error) {
+	if off+2 > len(bb) {
+		return nil, 0, errCorruptFontProgram
+	}
+	n := int(u16(bb, off))
+	if n == 0 {
+		return nil, off + 2, nil
+	}
+	if off+3 > len(bb) {
+		return nil, 0, errCorruptFontProgram
+	}
+	offSize := int(bb[off+2])
+	if offSize < 1 || offSize > 4 {
+		return nil, 0, errCorruptFontProgram
+	}
+
+	offset := func(i int) int {
+		v, p := 0, off+3+i*offSize
+		for j := 0; j < offSize; j++ {
+			if p+j >= len(bb) {
+				return -1
+			}
+			v = v<<8 | int(bb[p+j])
+		}
+		return v
+	}
+
+	base := off + 3 + (n+1)*offSize - 1
+	items := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		from, to := offset(i), offset(i+1)
+		if from < 1 || to < from || base+to > len(bb) {
+			return nil, 0, errCorruptFontProgram
+		}
+		items[i] = bb[base+from : base+to]
+	}
+
+	return items, base + offset(n), nil
+}
+
+func cffReal(bb []byte, i int) (float64, int) {
+	var s []byte
+	for ; i < len(bb); i++ {
+		for _, nib := range []byte{bb[i] >> 4, bb[i] & 0x0F} {
+			switch {
+			case nib <= 9:
+				s = append(s, '0'+nib)
+			case nib == 0x0A:
+				s = append(s, '.')
+			case nib == 0x0B:
+				s = append(s, 'E')
+			case nib == 0x0C:
+				s = append(s, 'E', '-')
+			case nib == 0x0E:
+				s = append(s, '-')
+			case nib == 0x0F:
+				f, _ := strconv.ParseFloat(string(s), 64)
+				return f, i + 1
+			}
+		}
+	}
+	return 0, i
+}
+
+func parseCFFDict(bb []byte) cffDict {
+	d := cffDict{}
+	var operands []float64
+
+	for i := 0; i < len(bb); {
+		b0 := bb[i]
+		switch {
+		case b0 <= 21:
+			op := int(b0)
+			i++
+			if b0 == 12 && i < len(bb) {
+				op = 1200 + int(bb[i])
+				i++
+			}
+			d[op] = operands
+			operands = nil
+		case b0 == 28:
+			operands = append(operands, float64(int16(u16(bb, i+1))))
+			i += 3
+		case b0 == 29:
+			operands = append(operands, float64(int32(u32(bb, i+1))))
+			i += 5
+		case b0 == 30:
+			var f float64
+			f, i = cffReal(bb, i+1)
+			operands = append(operands, f)
+		case b0 >= 32 && b0 <= 246:
+			operands = append(operands, float64(int(b0)-139))
+			i++
+		case b0 >= 247 && b0 <= 250 && i+1 < len(bb):
+			operands = append(operands, float64((int(b0)-247)*256+int(bb[i+1])+108))
+			i += 2
+		case b0 >= 251 && b0 <= 254 && i+1 < len(bb):
+			operands = append(operands, float64(-(int(b0)-251)*256-int(bb[i+1])-108))
+			i += 2
+		default:
+			i++
+		}
+	}
+
+	return d
+}
+
+func (d cffDict) int(op, def int) int {
+	if v := d[op]; len(v) > 0 {
+		return int(v[0])
+	}
+	return def
+}
+
+func (cf *cff) privateSubrs(bb []byte, d cffDict) [][]byte {
+	p := d[18]
+	if len(p) < 2 {
+		return nil
+	}
+	size, off := int(p[0]), int(p[1])
+	if off < 0 || size < 0 || off+size > len(bb) {
+		return nil
+	}
+	pd := parseCFFDict(bb[off : off+size])
+	subrsOff := pd.int(19, 0)
+	if subrsOff <= 0 {
+		return nil
+	}
+	subrs, _, err := cffIndex(bb, off+subrsOff)
+	if err != nil {
+		return nil
+	}
+	return subrs
+}
+
+// charset returns the SIDs resp. CIDs of all glyphs.
+func charset(bb []byte, off, n int) []int {
+	ids := make([]int, n)
+	if off <= 2 {
+		// ISOAdobe, Expert and ExpertSubset charsets, approximated by the identity.
+		for i := range ids {
+			ids[i] = i
+		}
+		return ids
+	}
+	if off >= len(bb) {
+		return ids
+	}
+
+	format := bb[off]
+	p := off + 1
+	switch format {
+	case 0:
+		for gid := 1; gid < n; gid++ {
+			ids[gid] = int(u16(bb, p))
+			p += 2
+		}
+	case 1, 2:
+		for gid := 1; gid < n && p < len(bb); {
+			first := int(u16(bb, p))
+			var left int
+			if format == 1 {
+				if p+2 >= len(bb) {
+					return ids
+				}
+				left = int(bb[p+2])
+				p += 3
+			} else {
+				left = int(u16(bb, p+2))
+				p += 4
+			}
+			for i := 0; i <= left && gid < n; i++ {
+				ids[gid] = first + i
+				gid++
+			}
+		}
+	}
+	return ids
+}
+
+func (cf *cff) parseEncoding(bb []byte, off int, sids []int) {
+	cf.encoding = map[byte]uint16{}
+
+	if off == 0 {
+		// Standard encoding
+		std := baseEncoding("StandardEncoding")
+		for c, name := range std {
+			if gid, ok := cf.names[name]; ok && name != "" {
+				cf.encoding[byte(c)] = gid
+			}
+		}
+		return
+	}
+	if off == 1 || off >= len(bb) {
+		return
+	}
+
+	format := bb[off]
+	p := off + 1
+	switch format & 0x7F {
+	case 0:
+		n := int(bb[p])
+		for gid := 1; gid <= n && p+gid < len(bb); gid++ {
+			cf.encoding[bb[p+gid]] = uint16(gid)
+		}
+		p += 1 + n
+	case 1:
+		n := int(bb[p])
+		gid := 1
+		for i := 0; i < n && p+2+2*i < len(bb); i++ {
+			first, left := int(bb[p+1+2*i]), int(bb[p+2+2*i])
+			for c := first; c <= first+left && c < 256; c++ {
+				cf.encoding[byte(c)] = uint16(gid)
+				gid++
+			}
+		}
+		p += 1 + 2*n
+	}
+
+	if format&0x80 != 0 && p < len(bb) {
+		// Supplements
+		n := int(bb[p])
+		for i := 0; i < n && p+3+3*i < len(bb); i++ {
+			c, sid := bb[p+1+3*i], int(u16(bb, p+2+3*i))
+			for gid, s := range sids {
+				if s == sid {
+					cf.encoding[c] = uint16(gid)
+					break
+				}
+			}
+		}
+	}
+}
+
+func parseCFF(bb []byte) (*cff, error) {
+	if len(bb) < 4 {
+		return nil, errCorruptFontProgram
+	}
+
+	_, off, err := cffIndex(bb, int(bb[2])) // Name INDEX
+	if err != nil {
+		return nil, err
+	}
+
+	topDicts, off, err := cffIndex(bb, off)
+	if err != nil || len(topDicts) == 0 {
+		return nil, errCorruptFontProgram
+	}
+
+	strs, off, err := cffIndex(bb, off)
+	if err != nil {
+		return nil, err
+	}
+
+	cf := &cff{fontMatrix: matrix.Matrix{{0.001, 0, 0}, {0, 0.001, 0}, {0, 0, 1}}}
+
+	if cf.gsubrs, _, err = cffIndex(bb, off); err != nil {
+		return nil, err
+	}
+
+	top := parseCFFDict(topDicts[0])
+
+	if cf.charStrings, _, err = cffIndex(bb, top.int(17, 0)); err != nil || len(cf.charStrings) == 0 {
+		return nil, errCorruptFontProgram
+	}
+
+	if fm := top[1207]; len(fm) == 6 {
+		cf.fontMatrix = matrix.Matrix{{fm[0], fm[1], 0}, {fm[2], fm[3], 0}, {fm[4], fm[5], 1}}
+	}
+
+	ids := charset(bb, top.int(15, 0), len(cf.charStrings))
+
+	if _, cid := top[1230]; cid {
+		cf.cidToGID = map[int]uint16{}
+		for gid, cid := range ids {
+			cf.cidToGID[cid] = uint16(gid)
+		}
+		fds, _, err := cffIndex(bb, top.int(1236, 0))
+		if err != nil {
+			return nil, err
+		}
+		for _, fd := range fds {
+			cf.subrs = append(cf.subrs, cf.privateSubrs(bb, parseCFFDict(fd)))
+		}
+		cf.parseFDSelect(bb, top.int(1237, 0))
+		return cf, nil
+	}
+
+	cf.subrs = [][][]byte{cf.privateSubrs(bb, top)}
+
+	cf.names = map[string]uint16{}
+	for gid, sid := range ids {
+		var s string
+		switch {
+		case sid < len(cffStandardStrings):
+			s = cffStandardStrings[sid]
+		case sid-len(cffStandardStrings) < len(strs):
+			s = string(strs[sid-len(cffStandardStrings)])
+		default:
+			continue
+		}
+		cf.names[s] = uint16(gid)
+	}
+
+	cf.parseEncoding(bb, top.int(16, 0), ids)
+
+	return cf, nil
+}
+
+func (cf *cff) parseFDSelect(bb []byte, off int) {
+	n := len(cf.charStrings)
+	if off <= 0 || off >= len(bb) {
+		return
+	}
+	cf.fdSelect = make([]byte, n)
+	switch bb[off] {
+	case 0:
+		for gid := 0; gid < n && off+1+gid < len(bb); gid++ {
+			cf.fdSelect[gid] = bb[off+1+gid]
+		}
+	case 3:
+		nRanges := int(u16(bb, off+1))
+		for i := 0; i < nRanges; i++ {
+			rec := off + 3 + 3*i
+			first, fd, next := int(u16(bb, rec)), bb[rec+2], int(u16(bb, rec+3))
+			for gid := first; gid < next && gid < n; gid++ {
+				cf.fdSelect[gid] = fd
+			}
+		}
+	}
+}
+
+func subrBias(n int) int {
+	switch {
+	case n < 1240:
+		return 107
+	case n < 33900:
+		return 1131
+	}
+	return 32768
+}
+
+// type2 interprets Type 2 charstrings.
+type type2 struct {
+	cf        *cff
+	subrs     [][]byte
+	p         *raster.Path
+	x, y      float64
+	stack     []float64
+	nStems    int
+	open      bool
+	seenWidth bool
+	ops       int
+	done      bool
+	depth     int // seac nesting level
+}
+
+func (t *type2) moveTo(dx, dy float64) {
+	if t.open {
+		t.p.Close()
+	}
+	t.x, t.y = t.x+dx, t.y+dy
+	t.p.MoveTo(t.x, t.y)
+	t.open = true
+}
+
+func (t *type2) lineTo(dx, dy float64) {
+	if !t.open {
+		t.moveTo(0, 0)
+	}
+	t.x, t.y = t.x+dx, t.y+dy
+	t.p.LineTo(t.x, t.y)
+}
+
+func (t *type2) curveTo(dx1, dy1, dx2, dy2, dx3, dy3 float64) {
+	if !t.open {
+		t.moveTo(0, 0)
+	}
+	x1, y1 := t.x+dx1, t.y+dy1
+	x2, y2 := x1+dx2, y1+dy2
+	t.x, t.y = x2+dx3, y2+dy3
+	t.p.CubeTo(x1, y1, x2, y2, t.x, t.y)
+}
+
+// dropWidth drops the optional leading width argument of the first stack clearing operator.
+func (t *type2) dropWidth(present bool) {
+	if t.seenWidth {
+		return
+	}
+	t.seenWidth = true
+	if present && len(t.stack) > 0 {
+		t.stack = t.stack[1:]
+	}
+}
+
+func (t *type2) stems() {
+	t.dropWidth(len(t.stack)%2 == 1)
+	t.nStems += len(t.stack) / 2
+	t.stack = t.stack[:0]
+}
+
+// alternating draws lines or curves alternating between horizontal and vertical start tangents.
+func (t *type2) alternatingLines(horizontal bool) {
+	s := t.stack
+	for i := 0; i < len(s); i++ {
+		if horizontal {
+			t.lineTo(s[i], 0)
+		} else {
+			t.lineTo(0, s[i])
+		}
+		horizontal = !horizontal
+	}
+}
+
+func (t *type2) alternatingCurves(horizontal bool) {
+	s := t.stack
+	for i := 0; i+4 <= len(s); i += 4 {
+		last := 0.
+		if len(s)-i == 5 {
+			last = s[i+4]
+		}
+		if horizontal {
+			t.curveTo(s[i], 0, s[i+1], s[i+2], last, s[i+3])
+		} else {
+			t.curveTo(0, s[i], s[i+1], s[i+2], s[i+3], last)
+		}
+		horizontal = !horizontal
+	}
+}
+
+func (t *type2) escape(op byte) {
+	s := t.stack
+	switch op {
+	case 35: // flex
+		if len(s) >= 12 {
+			t.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			t.curveTo(s[6], s[7], s[8], s[9], s[10], s[11])
+		}
+	case 34: // hflex
+		if len(s) >= 7 {
+			y := t.y
+			t.curveTo(s[0], 0, s[1], s[2], s[3], 0)
+			t.curveTo(s[4], 0, s[5], y-t.y, s[6], 0)
+		}
+	case 36: // hflex1
+		if len(s) >= 9 {
+			y := t.y
+			t.curveTo(s[0], s[1], s[2], s[3], s[4], 0)
+			t.curveTo(s[5], 0, s[6], s[7], s[8], y-t.y)
+		}
+	case 37: // flex1
+		if len(s) >= 11 {
+			x0, y0 := t.x, t.y
+			dx := s[0] + s[2] + s[4] + s[6] + s[8]
+			dy := s[1] + s[3] + s[5] + s[7] + s[9]
+			t.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			x, y := t.x, t.y
+			if math.Abs(dx) > math.Abs(dy) {
+				t.curveTo(s[6], s[7], s[8], s[9], s[10], y0-(y+s[7]+s[9]))
+			} else {
+				t.curveTo(s[6], s[7], s[8], s[9], x0-(x+s[6]+s[8]), s[10])
+			}
+		}
+	// Arithmetic operators
+	case 9: // abs
+		if n := len(s); n > 0 {
+			s[n-1] = math.Abs(s[n-1])
+		}
+		return
+	case 10: // add
+		if n := len(s); n > 1 {
+			t.stack = append(s[:n-2], s[n-2]+s[n-1])
+		}
+		return
+	case 11: // sub
+		if n := len(s); n > 1 {
+			t.stack = append(s[:n-2], s[n-2]-s[n-1])
+		}
+		return
+	case 12: // div
+		if n := len(s); n > 1 && s[n-1] != 0 {
+			t.stack = append(s[:n-2], s[n-2]/s[n-1])
+		}
+		return
+	case 14: // neg
+		if n := len(s); n > 0 {
+			s[n-1] = -s[n-1]
+		}
+		return
+	case 18: // drop
+		if n := len(s); n > 0 {
+			t.stack = s[:n-1]
+		}
+		return
+	case 24: // mul
+		if n := len(s); n > 1 {
+			t.stack = append(s[:n-2], s[n-2]*s[n-1])
+		}
+		return
+	case 27: // dup
+		if n := len(s); n > 0 {
+			t.stack = append(s, s[n-1])
+		}
+		return
+	case 28: // exch
+		if n := len(s); n > 1 {
+			s[n-2], s[n-1] = s[n-1], s[n-2]
+		}
+		return
+	}
+	t.stack = t.stack[:0]
+}
+
+// seac composes an accented glyph using the standard encoding codes of base and accent.
+func (t *type2) seac(adx, ady float64, base, accent int) {
+	std := baseEncoding("StandardEncoding")
+	if base < 0 || base > 255 || accent < 0 || accent > 255 {
+		return
+	}
+	if gid, ok := t.cf.names[std[base]]; ok {
+		t.p.Append(t.cf.charString(gid, t.depth+1))
+	}
+	if gid, ok := t.cf.names[std[accent]]; ok {
+		m := matrix.IdentMatrix
+		m[2][0], m[2][1] = adx, ady
+		t.p.Append(t.cf.charString(gid, t.depth+1).Transform(m))
+	}
+}
+
+func (t *type2) run(bb []byte, depth int) {
+	if depth > maxSubrDepth {
+		t.done = true
+		return
+	}
+
+	for i := 0; i < len(bb) && !t.done; {
+		if t.ops++; t.ops > maxCharStringOps {
+			t.done = true
+			return
+		}
+
+		b0 := bb[i]
+
+		switch {
+		case b0 == 28 && i+2 < len(bb):
+			t.stack = append(t.stack, float64(int16(u16(bb, i+1))))
+			i += 3
+			continue
+		case b0 >= 32 && b0 <= 246:
+			t.stack = append(t.stack, float64(int(b0)-139))
+			i++
+			continue
+		case b0 >= 247 && b0 <= 250 && i+1 < len(bb):
+			t.stack = append(t.stack, float64((int(b0)-247)*256+int(bb[i+1])+108))
+			i += 2
+			continue
+		case b0 >= 251 && b0 <= 254 && i+1 < len(bb):
+			t.stack = append(t.stack, float64(-(int(b0)-251)*256-int(bb[i+1])-108))
+			i += 2
+			continue
+		case b0 == 255 && i+4 < len(bb):
+			t.stack = append(t.stack, float64(int32(u32(bb, i+1)))/65536)
+			i += 5
+			continue
+		}
+
+		i++
+		s := t.stack
+
+		switch b0 {
+
+		case 1, 3, 18, 23: // hstem, vstem, hstemhm, vstemhm
+			t.stems()
+			continue
+
+		case 19, 20: // hintmask, cntrmask
+			t.stems()
+			i += (t.nStems + 7) / 8
+			continue
+
+		case 21: // rmoveto
+			t.dropWidth(len(s) > 2)
+			if s = t.stack; len(s) >= 2 {
+				t.moveTo(s[0], s[1])
+			}
+
+		case 22: // hmoveto
+			t.dropWidth(len(s) > 1)
+			if s = t.stack; len(s) >= 1 {
+				t.moveTo(s[0], 0)
+			}
+
+		case 4: // vmoveto
+			t.dropWidth(len(s) > 1)
+			if s = t.stack; len(s) >= 1 {
+				t.moveTo(0, s[0])
+			}
+
+		case 5: // rlineto
+			for j := 0; j+2 <= len(s); j += 2 {
+				t.lineTo(s[j], s[j+1])
+			}
+
+		case 6: // hlineto
+			t.alternatingLines(true)
+
+		case 7: // vlineto
+			t.alternatingLines(false)
+
+		case 8: // rrcurveto
+			for j := 0; j+6 <= len(s); j += 6 {
+				t.curveTo(s[j], s[j+1], s[j+2], s[j+3], s[j+4], s[j+5])
+			}
+
+		case 24: // rcurveline
+			j := 0
+			for ; j+6 <= len(s)-2; j += 6 {
+				t.curveTo(s[j], s[j+1], s[j+2], s[j+3], s[j+4], s[j+5])
+			}
+			if j+2 <= len(s) {
+				t.lineTo(s[j], s[j+1])
+			}
+
+		case 25: // rlinecurve
+			j := 0
+			for ; j+2 <= len(s)-6; j += 2 {
+				t.lineTo(s[j], s[j+1])
+			}
+			if j+6 <= len(s) {
+				t.curveTo(s[j], s[j+1], s[j+2], s[j+3], s[j+4], s[j+5])
+			}
+
+		case 26: // vvcurveto
+			dx1 := 0.
+			if len(s)%2 == 1 {
+				dx1, s = s[0], s[1:]
+			}
+			for j := 0; j+4 <= len(s); j += 4 {
+				t.curveTo(dx1, s[j], s[j+1], s[j+2], 0, s[j+3])
+				dx1 = 0
+			}
+
+		case 27: // hhcurveto
+			dy1 := 0.
+			if len(s)%2 == 1 {
+				dy1, s = s[0], s[1:]
+			}
+			for j := 0; j+4 <= len(s); j += 4 {
+				t.curveTo(s[j], dy1, s[j+1], s[j+2], s[j+3], 0)
+				dy1 = 0
+			}
+
+		case 30: // vhcurveto
+			t.alternatingCurves(false)
+
+		case 31: // hvcurveto
+			t.alternatingCurves(true)
+
+		case 10, 29: // callsubr, callgsubr
+			if len(s) == 0 {
+				return
+			}
+			subrs := t.subrs
+			if b0 == 29 {
+				subrs = t.cf.gsubrs
+			}
+			nr := int(s[len(s)-1]) + subrBias(len(subrs))
+			t.stack = s[:len(s)-1]
+			if nr >= 0 && nr < len(subrs) {
+				t.run(subrs[nr], depth+1)
+			}
+			continue
+
+		case 11: // return
+			return
+
+		case 14: // endchar
+			t.dropWidth(len(s) == 1 || len(s) == 5)
+			if s = t.stack; len(s) == 4 {
+				t.seac(s[0], s[1], int(s[2]), int(s[3]))
+			}
+			if t.open {
+				t.p.Close()
+				t.open = false
+			}
+			t.done = true
+			return
+
+		case 12: // escape
+			if i < len(bb) {
+				op := bb[i]
+				i++
+				t.escape(op)
+			}
+			continue
+		}
+
+		t.stack = t.stack[:0]
+	}
+}
+
+// glyph returns the glyph id for a glyph name.
+func (cf *cff) glyph(name string) (uint16, bool) {
+	gid, ok := cf.names[name]
+	return gid, ok
+}
+
+// charString returns the outline of gid in glyph space.
+func (cf *cff) charString(gid uint16, depth int) *raster.Path {
+	p := &raster.Path{}
+	if int(gid) >= len(cf.charStrings) || depth > 1 {
+		return p
+	}
+
+	fd := 0
+	if cf.fdSelect != nil {
+		fd = int(cf.fdSelect[gid])
+	}
+	var subrs [][]byte
+	if fd < len(cf.subrs) {
+		subrs = cf.subrs[fd]
+	}
+
+	t := &type2{cf: cf, subrs: subrs, p: p, depth: depth}
+	t.run(cf.charStrings[gid], 0)
+	if t.open {
+		p.Close()
+	}
+
+	return p
+}
+
+// outline returns the outline of gid scaled to a font size of 1.
+func (cf *cff) outline(gid uint16) *raster.Path {
+	return cf.charString(gid, 0).Transform(cf.fontMatrix)
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"unicode/utf16"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// See 9.7.5 CMaps and 9.10.3 ToUnicode CMaps
+
+// Ranges bigger than this are ignored in order to protect against corrupt CMaps.
+const maxCMapRange = 0x10000
+
+type codespaceRange struct {
+	lo, hi []byte
+}
+
+func (r codespaceRange) contains(bb []byte) bool {
+	if len(bb) != len(r.lo) {
+		return false
+	}
+	for i, b := range bb {
+		if b < r.lo[i] || b > r.hi[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// CMap maps character codes to CIDs and/or Unicode.
+type CMap struct {
+	codespace []codespaceRange
+	cids      map[string]int    // character code -> CID
+	unicode   map[string]string // character code -> Unicode
+}
+
+func newCMap() *CMap {
+	return &CMap{cids: map[string]int{}, unicode: map[string]string{}}
+}
+
+// identityCMap returns the predefined CMap Identity-H resp. Identity-V.
+func identityCMap() *CMap {
+	cm := newCMap()
+	cm.codespace = []codespaceRange{{lo: []byte{0x00, 0x00}, hi: []byte{0xFF, 0xFF}}}
+	return cm
+}
+
+func codeBytes(o types.Object) ([]byte, bool) {
+	switch o := o.(type) {
+	case types.HexLiteral:
+		bb, err := o.Bytes()
+		return bb, err == nil
+	case types.StringLiteral:
+		bb, err := types.Unescape(o.Value())
+		return bb, err == nil
+	}
+	return nil, false
+}
+
+func codeValue(bb []byte) int {
+	v := 0
+	for _, b := range bb {
+		v = v<<8 | int(b)
+	}
+	return v
+}
+
+func codeForValue(v, n int) []byte {
+	bb := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		bb[i] = byte(v)
+		v >>= 8
+	}
+	return bb
+}
+
+// utf16BEText decodes the UTF-16BE destination of a bfchar or bfrange mapping.
+func utf16BEText(bb []byte) string {
+	if len(bb)%2 == 1 {
+		// Tolerate single byte destinations.
+		return string(bb)
+	}
+	u := make([]uint16, len(bb)/2)
+	for i := range u {
+		u[i] = uint16(bb[2*i])<<8 | uint16(bb[2*i+1])
+	}
+	return string(utf16.Decode(u))
+}
+
+func (cm *CMap) parseCodespaceRanges(oo []types.Object) {
+	for i := 0; i+1 < len(oo); i += 2 {
+		lo, ok1 := codeBytes(oo[i])
+		hi, ok2 := codeBytes(oo[i+1])
+		if ok1 && ok2 && len(lo) == len(hi) && len(lo) > 0 {
+			cm.codespace = append(cm.codespace, codespaceRange{lo: lo, hi: hi})
+		}
+	}
+}
+
+func (cm *CMap) parseBfChars(oo []types.Object) {
+	for i := 0; i+1 < len(oo); i += 2 {
+		code, ok := codeBytes(oo[i])
+		if !ok {
+			continue
+		}
+		if dst, ok := codeBytes(oo[i+1]); ok {
+			cm.unicode[string(code)] = utf16BEText(dst)
+			continue
+		}
+		if n, ok := oo[i+1].(types.Name); ok {
+			if s, ok := GlyphNameToUnicode(n.Value()); ok {
+				cm.unicode[string(code)] = s
+			}
+		}
+	}
+}
+
+func (cm *CMap) parseBfRanges(oo []types.Object) {
+	for i := 0; i+2 < len(oo); i += 3 {
+		lo, ok1 := codeBytes(oo[i])
+		hi, ok2 := codeBytes(oo[i+1])
+		if !ok1 || !ok2 || len(lo) != len(hi) {
+			continue
+		}
+		from, thru := codeValue(lo), codeValue(hi)
+		if thru < from || thru-from >= maxCMapRange {
+			continue
+		}
+
+		if a, ok := oo[i+2].(types.Array); ok {
+			for j, o := range a {
+				if from+j > thru {
+					break
+				}
+				if dst, ok := codeBytes(o); ok {
+					cm.unicode[string(codeForValue(from+j, len(lo)))] = utf16BEText(dst)
+				}
+			}
+			continue
+		}
+
+		dst, ok := codeBytes(oo[i+2])
+		if !ok || len(dst) == 0 {
+			continue
+		}
+		for c := from; c <= thru; c++ {
+			cm.unicode[string(codeForValue(c, len(lo)))] = utf16BEText(dst)
+			// Increment the last byte of the destination.
+			dst = append([]byte(nil), dst...)
+			dst[len(dst)-1]++
+		}
+	}
+}
+
+func (cm *CMap) parseCIDChars(oo []types.Object) {
+	for i := 0; i+1 < len(oo); i += 2 {
+		code, ok := codeBytes(oo[i])
+		cid, ok1 := oo[i+1].(types.Integer)
+		if ok && ok1 {
+			cm.cids[string(code)] = cid.Value()
+		}
+	}
+}
+
+func (cm *CMap) parseCIDRanges(oo []types.Object) {
+	for i := 0; i+2 < len(oo); i += 3 {
+		lo, ok1 := codeBytes(oo[i])
+		hi, ok2 := codeBytes(oo[i+1])
+		cid, ok3 := oo[i+2].(types.Integer)
+		if !ok1 || !ok2 || !ok3 || len(lo) != len(hi) {
+			continue
+		}
+		from, thru := codeValue(lo), codeValue(hi)
+		if thru < from || thru-from >= maxCMapRange {
+			continue
+		}
+		for c := from; c <= thru; c++ {
+			cm.cids[string(codeForValue(c, len(lo)))] = cid.Value() + c - from
+		}
+	}
+}
+
+// ParseCMap parses an embedded CMap or a ToUnicode CMap.
+func ParseCMap(bb []byte) (*CMap, error) {
+	ops, err := content.Parse(bb)
+	if err != nil {
+		return nil, errors.Wrap(err, "pdfcpu: corrupt CMap")
+	}
+
+	cm := newCMap()
+
+	for _, op := range ops {
+		switch op.Operator {
+		case "endcodespacerange":
+			cm.parseCodespaceRanges(op.Operands)
+		case "endbfchar":
+			cm.parseBfChars(op.Operands)
+		case "endbfrange":
+			cm.parseBfRanges(op.Operands)
+		case "endcidchar":
+			cm.parseCIDChars(op.Operands)
+		case "endcidrange":
+			cm.parseCIDRanges(op.Operands)
+		}
+	}
+
+	return cm, nil
+}
+
+// codeLength returns the number of bytes making up the next character code of bb.
+func (cm *CMap) codeLength(bb []byte) int {
+	if len(cm.codespace) == 0 {
+		// Derive the code length from the mappings.
+		for n := 1; n <= 4 && n <= len(bb); n++ {
+			if _, ok := cm.unicode[string(bb[:n])]; ok {
+				return n
+			}
+			if _, ok := cm.cids[string(bb[:n])]; ok {
+				return n
+			}
+		}
+		return 1
+	}
+
+	for n := 1; n <= 4 && n <= len(bb); n++ {
+		for _, r := range cm.codespace {
+			if r.contains(bb[:n]) {
+				return n
+			}
+		}
+	}
+
+	// No match: consume as many bytes as the shortest code space range.
+	n := 4
+	for _, r := range cm.codespace {
+		if len(r.lo) < n {
+			n = len(r.lo)
+		}
+	}
+	if n > len(bb) {
+		n = len(bb)
+	}
+	return n
+}
+
+// Codes splits bb into character codes according to the code space ranges of cm.
+func (cm *CMap) Codes(bb []byte) [][]byte {
+	var codes [][]byte
+	for len(bb) > 0 {
+		n := cm.codeLength(bb)
+		codes = append(codes, bb[:n])
+		bb = bb[n:]
+	}
+	return codes
+}
+
+// CID returns the CID for code.
+func (cm *CMap) CID(code []byte) int {
+	if cid, ok := cm.cids[string(code)]; ok {
+		return cid
+	}
+	return codeValue(code)
+}
+
+// Unicode returns the Unicode text for code.
+func (cm *CMap) Unicode(code []byte) (string, bool) {
+	s, ok := cm.unicode[string(code)]
+	return s, ok
+}
@@ -0,0 +1,454 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pdfcpu/pdfcpu/internal/corefont/metrics"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// See 9.2 Organization and Use of Fonts
+
+// Glyph is a decoded character code of a string shown by a text showing operator.
+type Glyph struct {
+	Code  []byte
+	Text  string  // Unicode, "" if unknown
+	Width float64 // Displacement in text space units for a font size of 1
+	Space bool    // Single byte code 32, subject to word spacing
+}
+
+// Decoder maps the character codes of a font to Unicode and glyph widths.
+type Decoder struct {
+	Name      string
+	Subtype   string
+	Ascent    float64 // in text space units for a font size of 1
+	Descent   float64 // in text space units for a font size of 1
+	Vertical  bool
+	composite bool
+	encoding  *CMap       // composite fonts only
+	ucs2      bool        // composite fonts with a Unicode based encoding CMap
+	glyphs    [256]string // simple fonts only
+	named     [256]bool   // simple fonts only, glyph names taken from the Encoding entry
+	toUnicode *CMap
+	widths    map[int]float64 // code resp. CID -> width in glyph space
+	defWidth  float64
+	scale     float64 // glyph space -> text space
+}
+
+var reType1Encoding = regexp.MustCompile(`dup\s+(\d+)\s*/([^\s/]+)\s+put`)
+
+func streamBytes(xRefTable *model.XRefTable, o types.Object) []byte {
+	sd, _, err := xRefTable.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return nil
+	}
+	if err := sd.Decode(); err != nil {
+		return nil
+	}
+	return sd.Content
+}
+
+func number(xRefTable *model.XRefTable, o types.Object) (float64, bool) {
+	if o == nil {
+		return 0, false
+	}
+	f, err := xRefTable.DereferenceNumber(o)
+	return f, err == nil
+}
+
+func baseFontName(xRefTable *model.XRefTable, d types.Dict) string {
+	n, err := xRefTable.DereferenceName(d["BaseFont"], model.V10, nil)
+	if err != nil {
+		return ""
+	}
+	s := n.Value()
+	// Remove any subset prefix.
+	if len(s) > 7 && s[6] == '+' {
+		s = s[7:]
+	}
+	return s
+}
+
+// coreFontName returns the standard font name for fontName if applicable.
+func coreFontName(fontName string) string {
+	if _, ok := metrics.CoreFontMetrics[fontName]; ok {
+		return fontName
+	}
+	// Common aliases, see also Annex H.5 Standard fonts.
+	s := strings.ReplaceAll(fontName, " ", "")
+	base, style, _ := strings.Cut(s, ",")
+	switch base {
+	case "Arial", "ArialMT", "Helvetica":
+		base = "Helvetica"
+	case "TimesNewRoman", "TimesNewRomanPSMT", "Times":
+		base = "Times"
+	case "CourierNew", "CourierNewPSMT", "Courier":
+		base = "Courier"
+	default:
+		return ""
+	}
+	switch {
+	case style == "BoldItalic" && base == "Times":
+		return "Times-BoldItalic"
+	case style == "BoldItalic":
+		return base + "-BoldOblique"
+	case style == "Bold":
+		return base + "-Bold"
+	case style == "Italic" && base == "Times":
+		return "Times-Italic"
+	case style == "Italic":
+		return base + "-Oblique"
+	case base == "Times":
+		return "Times-Roman"
+	}
+	return base
+}
+
+func (dec *Decoder) fontDescriptor(xRefTable *model.XRefTable, fd types.Dict, coreFont string) {
+	dec.Ascent, dec.Descent = 0.8, -0.2
+
+	if fd != nil {
+		if f, ok := number(xRefTable, fd["Ascent"]); ok && f > 0 {
+			dec.Ascent = f * dec.scale
+		}
+		if f, ok := number(xRefTable, fd["Descent"]); ok && f < 0 {
+			dec.Descent = f * dec.scale
+		}
+		if f, ok := number(xRefTable, fd["MissingWidth"]); ok {
+			dec.defWidth = f
+		}
+		return
+	}
+
+	if coreFont != "" {
+		if fm := metrics.CoreFontMetrics[coreFont]; fm.FBox != nil {
+			dec.Ascent, dec.Descent = fm.FBox.UR.Y/1000, fm.FBox.LL.Y/1000
+		}
+	}
+}
+
+// builtinEncoding returns the glyph names of the encoding of an embedded Type1 font program.
+func builtinEncoding(xRefTable *model.XRefTable, fd types.Dict) ([256]string, bool) {
+	var enc [256]string
+	if fd == nil {
+		return enc, false
+	}
+	bb := streamBytes(xRefTable, fd["FontFile"])
+	if len(bb) == 0 {
+		return enc, false
+	}
+	// The encoding is part of the clear text portion.
+	if i := strings.Index(string(bb), "eexec"); i > 0 {
+		bb = bb[:i]
+	}
+	mm := reType1Encoding.FindAllSubmatch(bb, -1)
+	for _, m := range mm {
+		if c, err := strconv.Atoi(string(m[1])); err == nil && c < 256 {
+			enc[c] = string(m[2])
+		}
+	}
+	return enc, len(mm) > 0
+}
+
+func (dec *Decoder) nameAll() {
+	for i := range dec.named {
+		dec.named[i] = true
+	}
+}
+
+func (dec *Decoder) simpleEncoding(xRefTable *model.XRefTable, d, fd types.Dict, coreFont string) {
+	// Start with the font's built-in encoding.
+	switch {
+	case coreFont == "Symbol" || coreFont == "ZapfDingbats":
+		dec.glyphs = baseEncoding(coreFont)
+	default:
+		enc, ok := builtinEncoding(xRefTable, fd)
+		if !ok {
+			enc = baseEncoding("StandardEncoding")
+		}
+		dec.glyphs = enc
+	}
+
+	o, err := xRefTable.Dereference(d["Encoding"])
+	if err != nil || o == nil {
+		return
+	}
+
+	switch o := o.(type) {
+
+	case types.Name:
+		dec.glyphs = baseEncoding(o.Value())
+		dec.nameAll()
+
+	case types.Dict:
+		if n := o.NameEntry("BaseEncoding"); n != nil {
+			dec.glyphs = baseEncoding(*n)
+			dec.nameAll()
+		}
+		a, err := xRefTable.DereferenceArray(o["Differences"])
+		if err != nil {
+			return
+		}
+		c := 0
+		for _, o := range a {
+			switch o := o.(type) {
+			case types.Integer:
+				c = o.Value()
+			case types.Name:
+				if c >= 0 && c < 256 {
+					dec.glyphs[c] = o.Value()
+					dec.named[c] = true
+				}
+				c++
+			}
+		}
+	}
+}
+
+func (dec *Decoder) simpleWidths(xRefTable *model.XRefTable, d types.Dict, coreFont string) {
+	first := 0
+	if i, err := xRefTable.DereferenceInteger(d["FirstChar"]); err == nil && i != nil {
+		first = i.Value()
+	}
+
+	if a, err := xRefTable.DereferenceArray(d["Widths"]); err == nil && len(a) > 0 {
+		for i, o := range a {
+			if f, ok := number(xRefTable, o); ok {
+				dec.widths[first+i] = f
+			}
+		}
+		return
+	}
+
+	if coreFont == "" {
+		return
+	}
+
+	fm := metrics.CoreFontMetrics[coreFont]
+	for c, glyphName := range dec.glyphs {
+		if w, ok := fm.W[glyphName]; ok {
+			dec.widths[c] = float64(w)
+		}
+	}
+}
+
+func (dec *Decoder) compositeEncoding(xRefTable *model.XRefTable, d types.Dict) {
+	dec.encoding = identityCMap()
+
+	o, err := xRefTable.Dereference(d["Encoding"])
+	if err != nil || o == nil {
+		return
+	}
+
+	switch o := o.(type) {
+
+	case types.Name:
+		s := o.Value()
+		dec.Vertical = strings.HasSuffix(s, "-V")
+		dec.ucs2 = strings.Contains(s, "UCS2") || strings.Contains(s, "UTF16")
+
+	case types.StreamDict:
+		if err := o.Decode(); err != nil {
+			return
+		}
+		if cm, err := ParseCMap(o.Content); err == nil && len(cm.codespace) > 0 {
+			dec.encoding = cm
+		}
+		if i := o.IntEntry("WMode"); i != nil {
+			dec.Vertical = *i == 1
+		}
+	}
+}
+
+func (dec *Decoder) cidWidths(xRefTable *model.XRefTable, df types.Dict) {
+	dec.defWidth = 1000
+	if f, ok := number(xRefTable, df["DW"]); ok {
+		dec.defWidth = f
+	}
+
+	a, err := xRefTable.DereferenceArray(df["W"])
+	if err != nil {
+		return
+	}
+
+	// c [w1 w2 ... wn] or cFirst cLast w
+	for i := 0; i+1 < len(a); {
+		c, ok := number(xRefTable, a[i])
+		if !ok {
+			return
+		}
+		if ww, err := xRefTable.DereferenceArray(a[i+1]); err == nil && ww != nil {
+			for j, o := range ww {
+				if w, ok := number(xRefTable, o); ok {
+					dec.widths[int(c)+j] = w
+				}
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(a) {
+			return
+		}
+		last, ok1 := number(xRefTable, a[i+1])
+		w, ok2 := number(xRefTable, a[i+2])
+		if !ok1 || !ok2 || last < c || last-c >= maxCMapRange {
+			return
+		}
+		for cid := int(c); cid <= int(last); cid++ {
+			dec.widths[cid] = w
+		}
+		i += 3
+	}
+}
+
+func (dec *Decoder) type3(xRefTable *model.XRefTable, d types.Dict) {
+	dec.scale = 0.001
+	if a, err := xRefTable.DereferenceArray(d["FontMatrix"]); err == nil && len(a) == 6 {
+		if f, ok := number(xRefTable, a[0]); ok && f != 0 {
+			dec.scale = f
+		}
+	}
+}
+
+// NewDecoder returns a Decoder for the font dict d.
+func NewDecoder(xRefTable *model.XRefTable, d types.Dict) *Decoder {
+	dec := &Decoder{widths: map[int]float64{}, scale: 0.001}
+
+	if st := d.Subtype(); st != nil {
+		dec.Subtype = *st
+	}
+	dec.Name = baseFontName(xRefTable, d)
+
+	if bb := streamBytes(xRefTable, d["ToUnicode"]); len(bb) > 0 {
+		if cm, err := ParseCMap(bb); err == nil {
+			dec.toUnicode = cm
+		}
+	}
+
+	if dec.Subtype == "Type0" {
+		dec.composite = true
+		dec.compositeEncoding(xRefTable, d)
+		var df, fd types.Dict
+		if a, err := xRefTable.DereferenceArray(d["DescendantFonts"]); err == nil && len(a) > 0 {
+			df, _ = xRefTable.DereferenceDict(a[0])
+		}
+		if df != nil {
+			fd, _ = xRefTable.DereferenceDict(df["FontDescriptor"])
+			dec.cidWidths(xRefTable, df)
+		}
+		dw := dec.defWidth
+		dec.fontDescriptor(xRefTable, fd, "")
+		dec.defWidth = dw
+		return dec
+	}
+
+	if dec.Subtype == "Type3" {
+		dec.type3(xRefTable, d)
+	}
+
+	coreFont := ""
+	if dec.Subtype != "Type3" {
+		coreFont = coreFontName(dec.Name)
+	}
+
+	fd, _ := xRefTable.DereferenceDict(d["FontDescriptor"])
+	dec.fontDescriptor(xRefTable, fd, coreFont)
+	dec.simpleEncoding(xRefTable, d, fd, coreFont)
+	dec.simpleWidths(xRefTable, d, coreFont)
+
+	return dec
+}
+
+func (dec *Decoder) text(code []byte) string {
+	if dec.toUnicode != nil {
+		if s, ok := dec.toUnicode.Unicode(code); ok {
+			return s
+		}
+	}
+
+	if dec.composite {
+		if dec.ucs2 && len(code)%2 == 0 {
+			u := make([]uint16, len(code)/2)
+			for i := range u {
+				u[i] = uint16(code[2*i])<<8 | uint16(code[2*i+1])
+			}
+			return string(utf16.Decode(u))
+		}
+		return ""
+	}
+
+	if s, ok := GlyphNameToUnicode(dec.glyphs[code[0]]); ok {
+		return s
+	}
+
+	// Fall back to ASCII for unknown glyph names.
+	if code[0] >= 0x20 && code[0] < 0x7F {
+		return string(code)
+	}
+
+	return ""
+}
+
+// CID returns the CID for code of a composite font.
+func (dec *Decoder) CID(code []byte) int {
+	if !dec.composite {
+		return 0
+	}
+	return dec.encoding.CID(code)
+}
+
+// GlyphName returns the glyph name for code of a simple font.
+func (dec *Decoder) GlyphName(code byte) string {
+	return dec.glyphs[code]
+}
+
+// Decode splits the bytes of a string operand into glyphs.
+func (dec *Decoder) Decode(bb []byte) []Glyph {
+	var gg []Glyph
+
+	if !dec.composite {
+		for i := range bb {
+			code := bb[i : i+1]
+			w, ok := dec.widths[int(bb[i])]
+			if !ok {
+				w = dec.defWidth
+			}
+			gg = append(gg, Glyph{Code: code, Text: dec.text(code), Width: w * dec.scale, Space: bb[i] == 32})
+		}
+		return gg
+	}
+
+	for _, code := range dec.encoding.Codes(bb) {
+		w, ok := dec.widths[dec.encoding.CID(code)]
+		if !ok {
+			w = dec.defWidth
+		}
+		if dec.Vertical {
+			// Use the default vertical displacement, see 9.7.4.3 Glyph Metrics in CIDFonts.
+			w = 1000
+		}
+		gg = append(gg, Glyph{Code: code, Text: dec.text(code), Width: w * dec.scale, Space: len(code) == 1 && code[0] == 32})
+	}
+
+	return gg
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/internal/corefont/metrics"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// See Annex D.2 Latin Character Set and Encodings
+
+// standardEncoding maps the codes of StandardEncoding deviating from ASCII resp. undefined in ASCII to glyph names.
+var standardEncoding = map[int]string{
+	0047: "quoteright", 0140: "quoteleft",
+	0241: "exclamdown", 0242: "cent", 0243: "sterling", 0244: "fraction", 0245: "yen", 0246: "florin", 0247: "section",
+	0250: "currency", 0251: "quotesingle", 0252: "quotedblleft", 0253: "guillemotleft", 0254: "guilsinglleft",
+	0255: "guilsinglright", 0256: "fi", 0257: "fl", 0261: "endash", 0262: "dagger", 0263: "daggerdbl",
+	0264: "periodcentered", 0266: "paragraph", 0267: "bullet", 0270: "quotesinglbase", 0271: "quotedblbase",
+	0272: "quotedblright", 0273: "guillemotright", 0274: "ellipsis", 0275: "perthousand", 0277: "questiondown",
+	0301: "grave", 0302: "acute", 0303: "circumflex", 0304: "tilde", 0305: "macron", 0306: "breve", 0307: "dotaccent",
+	0310: "dieresis", 0312: "ring", 0313: "cedilla", 0315: "hungarumlaut", 0316: "ogonek", 0317: "caron",
+	0320: "emdash", 0341: "AE", 0343: "ordfeminine", 0350: "Lslash", 0351: "Oslash", 0352: "OE",
+	0353: "ordmasculine", 0361: "ae", 0365: "dotlessi", 0370: "lslash", 0371: "oslash", 0372: "oe", 0373: "germandbls",
+}
+
+// macRomanEncoding maps the upper half of MacRomanEncoding to glyph names.
+var macRomanEncoding = map[int]string{
+	0200: "Adieresis", 0201: "Aring", 0202: "Ccedilla", 0203: "Eacute", 0204: "Ntilde", 0205: "Odieresis",
+	0206: "Udieresis", 0207: "aacute", 0210: "agrave", 0211: "acircumflex", 0212: "adieresis", 0213: "atilde",
+	0214: "aring", 0215: "ccedilla", 0216: "eacute", 0217: "egrave", 0220: "ecircumflex", 0221: "edieresis",
+	0222: "iacute", 0223: "igrave", 0224: "icircumflex", 0225: "idieresis", 0226: "ntilde", 0227: "oacute",
+	0230: "ograve", 0231: "ocircumflex", 0232: "odieresis", 0233: "otilde", 0234: "uacute", 0235: "ugrave",
+	0236: "ucircumflex", 0237: "udieresis", 0240: "dagger", 0241: "degree", 0242: "cent", 0243: "sterling",
+	0244: "section", 0245: "bullet", 0246: "paragraph", 0247: "germandbls", 0250: "registered", 0251: "copyright",
+	0252: "trademark", 0253: "acute", 0254: "dieresis", 0256: "AE", 0257: "Oslash", 0261: "plusminus",
+	0264: "yen", 0265: "mu", 0273: "ordfeminine", 0274: "ordmasculine", 0276: "ae", 0277: "oslash",
+	0300: "questiondown", 0301: "exclamdown", 0302: "logicalnot", 0304: "florin", 0307: "guillemotleft",
+	0310: "guillemotright", 0311: "ellipsis", 0312: "space", 0313: "Agrave", 0314: "Atilde", 0315: "Otilde",
+	0316: "OE", 0317: "oe", 0320: "endash", 0321: "emdash", 0322: "quotedblleft", 0323: "quotedblright",
+	0324: "quoteleft", 0325: "quoteright", 0326: "divide", 0330: "ydieresis", 0331: "Ydieresis", 0332: "fraction",
+	0333: "currency", 0334: "guilsinglleft", 0335: "guilsinglright", 0336: "fi", 0337: "fl", 0340: "daggerdbl",
+	0341: "periodcentered", 0342: "quotesinglbase", 0343: "quotedblbase", 0344: "perthousand", 0345: "Acircumflex",
+	0346: "Ecircumflex", 0347: "Aacute", 0350: "Edieresis", 0351: "Egrave", 0352: "Iacute", 0353: "Icircumflex",
+	0354: "Idieresis", 0355: "Igrave", 0356: "Oacute", 0357: "Ocircumflex", 0361: "Ograve", 0362: "Uacute",
+	0363: "Ucircumflex", 0364: "Ugrave", 0365: "dotlessi", 0366: "circumflex", 0367: "tilde", 0370: "macron",
+	0371: "breve", 0372: "dotaccent", 0373: "ring", 0374: "cedilla", 0375: "hungarumlaut", 0376: "ogonek", 0377: "caron",
+}
+
+// glyphNames maps glyph names not covered by WinAnsiEncoding to Unicode.
+// This is the subset of the Adobe Glyph List used by the standard encodings and the core fonts.
+var glyphNames = map[string]rune{
+	"Abreve": 0x0102, "abreve": 0x0103, "Amacron": 0x0100, "amacron": 0x0101, "Aogonek": 0x0104, "aogonek": 0x0105,
+	"Cacute": 0x0106, "cacute": 0x0107, "Ccaron": 0x010C, "ccaron": 0x010D, "Dcaron": 0x010E, "dcaron": 0x010F,
+	"Dcroat": 0x0110, "dcroat": 0x0111, "Emacron": 0x0112, "emacron": 0x0113, "Edotaccent": 0x0116, "edotaccent": 0x0117,
+	"Eogonek": 0x0118, "eogonek": 0x0119, "Ecaron": 0x011A, "ecaron": 0x011B, "Gbreve": 0x011E, "gbreve": 0x011F,
+	"Gcommaaccent": 0x0122, "gcommaaccent": 0x0123, "Imacron": 0x012A, "imacron": 0x012B, "Iogonek": 0x012E,
+	"iogonek": 0x012F, "Idotaccent": 0x0130, "dotlessi": 0x0131, "Kcommaaccent": 0x0136, "kcommaaccent": 0x0137,
+	"Lacute": 0x0139, "lacute": 0x013A, "Lcommaaccent": 0x013B, "lcommaaccent": 0x013C, "Lcaron": 0x013D,
+	"lcaron": 0x013E, "Lslash": 0x0141, "lslash": 0x0142, "Nacute": 0x0143, "nacute": 0x0144, "Ncommaaccent": 0x0145,
+	"ncommaaccent": 0x0146, "Ncaron": 0x0147, "ncaron": 0x0148, "Omacron": 0x014C, "omacron": 0x014D,
+	"Ohungarumlaut": 0x0150, "ohungarumlaut": 0x0151, "Racute": 0x0154, "racute": 0x0155, "Rcommaaccent": 0x0156,
+	"rcommaaccent": 0x0157, "Rcaron": 0x0158, "rcaron": 0x0159, "Sacute": 0x015A, "sacute": 0x015B,
+	"Scedilla": 0x015E, "scedilla": 0x015F, "Tcommaaccent": 0x0162, "tcommaaccent": 0x0163, "Tcaron": 0x0164,
+	"tcaron": 0x0165, "Umacron": 0x016A, "umacron": 0x016B, "Uring": 0x016E, "uring": 0x016F,
+	"Uhungarumlaut": 0x0170, "uhungarumlaut": 0x0171, "Uogonek": 0x0172, "uogonek": 0x0173, "Zacute": 0x0179,
+	"zacute": 0x017A, "Zdotaccent": 0x017B, "zdotaccent": 0x017C, "Scommaaccent": 0x0218, "scommaaccent": 0x0219,
+	"commaaccent": 0x0326, "breve": 0x02D8, "caron": 0x02C7, "dotaccent": 0x02D9, "hungarumlaut": 0x02DD,
+	"ogonek": 0x02DB, "ring": 0x02DA, "ff": 0xFB00, "fi": 0xFB01, "fl": 0xFB02, "ffi": 0xFB03, "ffl": 0xFB04,
+	"fraction": 0x2044, "nbspace": 0x00A0, "nonbreakingspace": 0x00A0, "sfthyphen": 0x00AD, "middot": 0x00B7,
+	"apple": 0xF8FF,
+
+	"Alpha": 0x0391, "Beta": 0x0392, "Gamma": 0x0393, "Delta": 0x2206, "Epsilon": 0x0395, "Zeta": 0x0396,
+	"Eta": 0x0397, "Theta": 0x0398, "Iota": 0x0399, "Kappa": 0x039A, "Lambda": 0x039B, "Mu": 0x039C, "Nu": 0x039D,
+	"Xi": 0x039E, "Omicron": 0x039F, "Pi": 0x03A0, "Rho": 0x03A1, "Sigma": 0x03A3, "Tau": 0x03A4,
+	"Upsilon": 0x03A5, "Phi": 0x03A6, "Chi": 0x03A7, "Psi": 0x03A8, "Omega": 0x2126, "alpha": 0x03B1,
+	"beta": 0x03B2, "gamma": 0x03B3, "delta": 0x03B4, "epsilon": 0x03B5, "zeta": 0x03B6, "eta": 0x03B7,
+	"theta": 0x03B8, "iota": 0x03B9, "kappa": 0x03BA, "lambda": 0x03BB, "nu": 0x03BD, "xi": 0x03BE,
+	"omicron": 0x03BF, "pi": 0x03C0, "rho": 0x03C1, "sigma1": 0x03C2, "sigma": 0x03C3, "tau": 0x03C4,
+	"upsilon": 0x03C5, "phi": 0x03C6, "chi": 0x03C7, "psi": 0x03C8, "omega": 0x03C9, "theta1": 0x03D1,
+	"Upsilon1": 0x03D2, "phi1": 0x03D5, "omega1": 0x03D6,
+
+	"aleph": 0x2135, "angle": 0x2220, "angleleft": 0x2329, "angleright": 0x232A, "approxequal": 0x2248,
+	"arrowboth": 0x2194, "arrowdblboth": 0x21D4, "arrowdbldown": 0x21D3, "arrowdblleft": 0x21D0,
+	"arrowdblright": 0x21D2, "arrowdblup": 0x21D1, "arrowdown": 0x2193, "arrowleft": 0x2190, "arrowright": 0x2192,
+	"arrowup": 0x2191, "asteriskmath": 0x2217, "carriagereturn": 0x21B5, "circlemultiply": 0x2297,
+	"circleplus": 0x2295, "club": 0x2663, "congruent": 0x2245, "copyrightsans": 0x00A9, "copyrightserif": 0x00A9,
+	"diamond": 0x2666, "dotmath": 0x22C5, "element": 0x2208, "emptyset": 0x2205, "equivalence": 0x2261,
+	"existential": 0x2203, "gradient": 0x2207, "greaterequal": 0x2265, "heart": 0x2665, "Ifraktur": 0x2111,
+	"infinity": 0x221E, "integral": 0x222B, "intersection": 0x2229, "lessequal": 0x2264, "logicaland": 0x2227,
+	"logicalor": 0x2228, "lozenge": 0x25CA, "minus": 0x2212, "minute": 0x2032, "notelement": 0x2209,
+	"notequal": 0x2260, "notsubset": 0x2284, "partialdiff": 0x2202, "perpendicular": 0x22A5, "product": 0x220F,
+	"propersubset": 0x2282, "propersuperset": 0x2283, "proportional": 0x221D, "radical": 0x221A,
+	"reflexsubset": 0x2286, "reflexsuperset": 0x2287, "registersans": 0x00AE, "registerserif": 0x00AE,
+	"Rfraktur": 0x211C, "second": 0x2033, "similar": 0x223C, "spade": 0x2660, "suchthat": 0x220B,
+	"summation": 0x2211, "therefore": 0x2234, "trademarksans": 0x2122, "trademarkserif": 0x2122, "union": 0x222A,
+	"universal": 0x2200, "weierstrass": 0x2118,
+}
+
+func init() {
+	// Derive the remaining glyph names from WinAnsiEncoding.
+	for c, name := range metrics.WinAnsiGlyphMap {
+		if _, ok := glyphNames[name]; ok || c < 0x20 {
+			continue
+		}
+		if r := charmap.Windows1252.DecodeByte(byte(c)); r != 0xFFFD {
+			glyphNames[name] = r
+		}
+	}
+}
+
+// GlyphNameToUnicode returns the Unicode text for glyphName following the Adobe Glyph List conventions.
+func GlyphNameToUnicode(glyphName string) (string, bool) {
+	if glyphName == "" || glyphName == ".notdef" {
+		return "", false
+	}
+
+	// Strip any suffix like in "a.sc".
+	if i := strings.IndexByte(glyphName, '.'); i > 0 {
+		glyphName = glyphName[:i]
+	}
+
+	// Ligatures like "f_f_i".
+	if strings.Contains(glyphName, "_") {
+		var sb strings.Builder
+		for _, s := range strings.Split(glyphName, "_") {
+			u, ok := GlyphNameToUnicode(s)
+			if !ok {
+				return "", false
+			}
+			sb.WriteString(u)
+		}
+		return sb.String(), true
+	}
+
+	if r, ok := glyphNames[glyphName]; ok {
+		return string(r), true
+	}
+
+	// uniXXXX[XXXX..]
+	if strings.HasPrefix(glyphName, "uni") && len(glyphName) >= 7 && (len(glyphName)-3)%4 == 0 {
+		var sb strings.Builder
+		for i := 3; i < len(glyphName); i += 4 {
+			v, err := strconv.ParseUint(glyphName[i:i+4], 16, 16)
+			if err != nil {
+				return "", false
+			}
+			sb.WriteRune(rune(v))
+		}
+		return sb.String(), true
+	}
+
+	// uXXXX[XX]
+	if strings.HasPrefix(glyphName, "u") && len(glyphName) >= 5 && len(glyphName) <= 7 {
+		if v, err := strconv.ParseUint(glyphName[1:], 16, 32); err == nil {
+			return string(rune(v)), true
+		}
+	}
+
+	return "", false
+}
+
+// baseEncoding returns the glyph names of one of the predefined simple font encodings.
+func baseEncoding(name string) [256]string {
+	var enc [256]string
+
+	switch name {
+
+	case "WinAnsiEncoding":
+		for c, n := range metrics.WinAnsiGlyphMap {
+			if c < 256 {
+				enc[c] = n
+			}
+		}
+
+	case "MacRomanEncoding", "MacExpertEncoding":
+		for c, n := range metrics.WinAnsiGlyphMap {
+			if c >= 0x20 && c < 0x7F {
+				enc[c] = n
+			}
+		}
+		for c, n := range macRomanEncoding {
+			enc[c] = n
+		}
+
+	case "Symbol":
+		for c, n := range metrics.SymbolGlyphMap {
+			if c < 256 {
+				enc[c] = n
+			}
+		}
+
+	case "ZapfDingbats":
+		for c, n := range metrics.ZapfDingbatsGlyphMap {
+			if c < 256 {
+				enc[c] = n
+			}
+		}
+
+	default: // StandardEncoding
+		for c, n := range metrics.WinAnsiGlyphMap {
+			if c >= 0x20 && c < 0x7F {
+				enc[c] = n
+			}
+		}
+		for c, n := range standardEncoding {
+			enc[c] = n
+		}
+	}
+
+	return enc
+}
@@ -787,6 +787,11 @@ func subFontPrefix() string {
 	return string(bb)
 }
 
+// SubsetTag returns a random tag for prefixing the name of a font subset.
+func SubsetTag() string {
+	return subFontPrefix()
+}
+
 // CIDFontDict returns the descendant font dict with special encoding for Type0 fonts.
 func CIDFontDict(xRefTable *model.XRefTable, ttf font.TTFLight, fontName, baseFontName, lang string, parms *cjk) (*types.IndirectRef, error) {
 	fdIndRef, err := CIDFontDescriptor(xRefTable, ttf, fontName, baseFontName, lang, parms == nil)
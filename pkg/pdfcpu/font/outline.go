@@ -0,0 +1,383 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/raster"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/gomonobolditalic"
+	"golang.org/x/image/font/gofont/gomonoitalic"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// See 9.6.6.4 Encodings for TrueType Fonts and 9.9 Embedded Font Programs
+
+// Font descriptor flags
+const (
+	flagFixedPitch = 1 << 0
+	flagSymbolic   = 1 << 2
+	flagItalic     = 1 << 6
+	flagForceBold  = 1 << 18
+)
+
+var (
+	substitutesOnce sync.Once
+	substitutes     map[string]*trueType
+)
+
+// substitute returns a Go font program resembling the font fontName.
+// Go fonts are used for all fonts without an embedded font program including the standard 14 fonts.
+func substitute(fontName string, flags int) *trueType {
+	substitutesOnce.Do(func() {
+		substitutes = map[string]*trueType{}
+		for k, bb := range map[string][]byte{
+			"":               goregular.TTF,
+			"Bold":           gobold.TTF,
+			"Italic":         goitalic.TTF,
+			"BoldItalic":     gobolditalic.TTF,
+			"Mono":           gomono.TTF,
+			"MonoBold":       gomonobold.TTF,
+			"MonoItalic":     gomonoitalic.TTF,
+			"MonoBoldItalic": gomonobolditalic.TTF,
+		} {
+			if tt, err := parseTrueType(bb); err == nil {
+				substitutes[k] = tt
+			}
+		}
+	})
+
+	s := strings.ToLower(fontName)
+	contains := func(ss ...string) bool {
+		for _, sub := range ss {
+			if strings.Contains(s, sub) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var k string
+	if flags&flagFixedPitch != 0 || contains("courier", "mono", "consol") {
+		k = "Mono"
+	}
+	if flags&flagForceBold != 0 || contains("bold", "black", "heavy", "semibold", "demi") {
+		k += "Bold"
+	}
+	if flags&flagItalic != 0 || contains("italic", "oblique") {
+		k += "Italic"
+	}
+
+	return substitutes[k]
+}
+
+// Outlines provides the glyph outlines of a font.
+type Outlines struct {
+	dec      *Decoder
+	tt       *trueType
+	cff      *cff
+	t1       *type1
+	sub      *trueType // substitute font program
+	symbolic bool
+	cidToGID []byte // CIDFontType2 only, nil for Identity
+	cache    map[string]*raster.Path
+}
+
+func (o *Outlines) loadFontProgram(xRefTable *model.XRefTable, fd types.Dict) {
+	if bb := streamBytes(xRefTable, fd["FontFile2"]); len(bb) > 0 {
+		o.tt, _ = parseTrueType(bb)
+		return
+	}
+
+	if bb := streamBytes(xRefTable, fd["FontFile"]); len(bb) > 0 {
+		o.t1, _ = parseType1(bb)
+		return
+	}
+
+	bb := streamBytes(xRefTable, fd["FontFile3"])
+	if len(bb) == 0 {
+		return
+	}
+
+	if len(bb) > 4 && (string(bb[:4]) == "OTTO" || string(bb[:4]) == "\x00\x01\x00\x00" || string(bb[:4]) == "true") {
+		tables, err := sfntTables(bb)
+		if err != nil {
+			return
+		}
+		if t := tables["CFF "]; t != nil {
+			o.cff, _ = parseCFF(t)
+			return
+		}
+		o.tt, _ = parseTrueType(bb)
+		return
+	}
+
+	o.cff, _ = parseCFF(bb)
+}
+
+// NewOutlines returns the glyph outlines for the font dict d decoded by dec.
+// Glyphs of fonts without an embedded font program are taken from a similar Go font.
+func NewOutlines(xRefTable *model.XRefTable, d types.Dict, dec *Decoder) *Outlines {
+	o := &Outlines{dec: dec, cache: map[string]*raster.Path{}}
+
+	if dec.Subtype == "Type3" {
+		return o
+	}
+
+	var fd types.Dict
+
+	if dec.composite {
+		if a, err := xRefTable.DereferenceArray(d["DescendantFonts"]); err == nil && len(a) > 0 {
+			if df, _ := xRefTable.DereferenceDict(a[0]); df != nil {
+				fd, _ = xRefTable.DereferenceDict(df["FontDescriptor"])
+				if bb := streamBytes(xRefTable, df["CIDToGIDMap"]); len(bb) > 0 {
+					o.cidToGID = bb
+				}
+			}
+		}
+	} else {
+		fd, _ = xRefTable.DereferenceDict(d["FontDescriptor"])
+	}
+
+	flags := 0
+	if fd != nil {
+		if i, err := xRefTable.DereferenceInteger(fd["Flags"]); err == nil && i != nil {
+			flags = i.Value()
+		}
+		o.loadFontProgram(xRefTable, fd)
+	}
+	o.symbolic = flags&flagSymbolic != 0
+
+	if o.tt == nil && o.cff == nil && o.t1 == nil {
+		o.sub = substitute(dec.Name, flags)
+	}
+
+	return o
+}
+
+func (o *Outlines) cidGID(cid int) uint16 {
+	if o.cidToGID == nil {
+		return uint16(cid)
+	}
+	if 2*cid+1 < len(o.cidToGID) {
+		return u16(o.cidToGID, 2*cid)
+	}
+	return 0
+}
+
+func (o *Outlines) trueTypeGlyph(c byte, name string) uint16 {
+	tt := o.tt
+
+	symbolCMap := func() (uint16, bool) {
+		if m := tt.cmap(3, 0); m != nil {
+			for _, base := range []uint32{0, 0xF000, 0xF100, 0xF200} {
+				if gid, ok := m[base+uint32(c)]; ok {
+					return gid, true
+				}
+			}
+		}
+		if m := tt.cmap(1, 0); m != nil {
+			if gid, ok := m[uint32(c)]; ok {
+				return gid, true
+			}
+		}
+		return 0, false
+	}
+
+	if o.symbolic && !o.dec.named[c] {
+		if gid, ok := symbolCMap(); ok {
+			return gid
+		}
+	}
+
+	if name != "" {
+		if s, ok := GlyphNameToUnicode(name); ok {
+			r, _ := utf8.DecodeRuneInString(s)
+			if gid, ok := tt.cmap(3, 1)[uint32(r)]; ok {
+				return gid
+			}
+		}
+		if gid, ok := tt.names[name]; ok {
+			return gid
+		}
+	}
+
+	if gid, ok := symbolCMap(); ok {
+		return gid
+	}
+
+	if gid, ok := tt.cmap(3, 1)[uint32(c)]; ok {
+		return gid
+	}
+
+	// Some producers omit the cmap and use glyph ids as codes.
+	return uint16(c)
+}
+
+// TrueTypeGlyphIDs returns the ids of all glyphs of an embedded TrueType font program
+// a viewer might use for rendering g. ok is false if there is no such font program.
+func (o *Outlines) TrueTypeGlyphIDs(g Glyph) (gids []uint16, ok bool) {
+	tt := o.tt
+	if tt == nil || len(g.Code) == 0 {
+		return nil, false
+	}
+
+	if o.dec.composite {
+		return []uint16{o.cidGID(o.dec.encoding.CID(g.Code))}, true
+	}
+
+	c := g.Code[0]
+	name := o.dec.glyphs[c]
+
+	// Viewers differ in how they pick a cmap for simple fonts, so collect all candidates.
+	gids = append(gids, o.trueTypeGlyph(c, name), uint16(c))
+
+	if m := tt.cmap(3, 0); m != nil {
+		for _, base := range []uint32{0, 0xF000, 0xF100, 0xF200} {
+			if gid, ok := m[base+uint32(c)]; ok {
+				gids = append(gids, gid)
+			}
+		}
+	}
+
+	if gid, ok := tt.cmap(1, 0)[uint32(c)]; ok {
+		gids = append(gids, gid)
+	}
+
+	if gid, ok := tt.cmap(3, 1)[uint32(c)]; ok {
+		gids = append(gids, gid)
+	}
+
+	if name != "" {
+		if s, ok := GlyphNameToUnicode(name); ok {
+			r, _ := utf8.DecodeRuneInString(s)
+			if gid, ok := tt.cmap(3, 1)[uint32(r)]; ok {
+				gids = append(gids, gid)
+			}
+		}
+		if gid, ok := tt.names[name]; ok {
+			gids = append(gids, gid)
+		}
+	}
+
+	return gids, true
+}
+
+func (o *Outlines) cffGlyph(c byte, name string) (uint16, bool) {
+	cf := o.cff
+	if cf.names == nil {
+		// CID-keyed font program used by a simple font
+		gid, ok := cf.cidToGID[int(c)]
+		return gid, ok
+	}
+	if o.dec.named[c] {
+		if gid, ok := cf.names[name]; ok {
+			return gid, true
+		}
+	}
+	if gid, ok := cf.encoding[c]; ok {
+		return gid, true
+	}
+	gid, ok := cf.names[name]
+	return gid, ok
+}
+
+// substituted returns the outline of the glyph for r of the substitute font program
+// scaled horizontally to match the glyph width of the PDF font.
+func (o *Outlines) substituted(g Glyph, r rune) *raster.Path {
+	if o.sub == nil || r == utf8.RuneError {
+		return nil
+	}
+	gid, ok := o.sub.cmap(3, 1)[uint32(r)]
+	if !ok {
+		return nil
+	}
+	p := o.sub.outline(gid)
+	if adv := o.sub.advance(gid) / o.sub.unitsPerEm; adv > 0 && g.Width > 0 {
+		sx := min(max(g.Width/adv, 0.5), 2)
+		p = p.Transform(matrix.Matrix{{sx, 0, 0}, {0, 1, 0}, {0, 0, 1}})
+	}
+	return p
+}
+
+func (o *Outlines) outline(g Glyph) *raster.Path {
+	dec := o.dec
+
+	if dec.composite {
+		cid := dec.encoding.CID(g.Code)
+		switch {
+		case o.tt != nil:
+			return o.tt.outline(o.cidGID(cid))
+		case o.cff != nil && o.cff.cidToGID != nil:
+			if gid, ok := o.cff.cidToGID[cid]; ok {
+				return o.cff.outline(gid)
+			}
+			return nil
+		case o.cff != nil:
+			return o.cff.outline(uint16(cid))
+		}
+		r, _ := utf8.DecodeRuneInString(g.Text)
+		return o.substituted(g, r)
+	}
+
+	c := g.Code[0]
+	name := dec.glyphs[c]
+
+	switch {
+	case o.tt != nil:
+		return o.tt.outline(o.trueTypeGlyph(c, name))
+	case o.cff != nil:
+		if gid, ok := o.cffGlyph(c, name); ok {
+			return o.cff.outline(gid)
+		}
+		return nil
+	case o.t1 != nil:
+		return o.t1.outline(name)
+	}
+
+	r := utf8.RuneError
+	if s, ok := GlyphNameToUnicode(name); ok {
+		r, _ = utf8.DecodeRuneInString(s)
+	} else if g.Text != "" {
+		r, _ = utf8.DecodeRuneInString(g.Text)
+	}
+	return o.substituted(g, r)
+}
+
+// Outline returns the outline of g in text space for a font size of 1 or nil if there is no glyph to render.
+func (o *Outlines) Outline(g Glyph) *raster.Path {
+	if o.dec.Subtype == "Type3" || len(g.Code) == 0 {
+		return nil
+	}
+	k := string(g.Code)
+	if p, ok := o.cache[k]; ok {
+		return p
+	}
+	p := o.outline(g)
+	o.cache[k] = p
+	return p
+}
@@ -0,0 +1,507 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"encoding/binary"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/raster"
+	"github.com/pkg/errors"
+)
+
+// See the OpenType specification: https://learn.microsoft.com/en-us/typography/opentype/spec/
+
+const maxCompositeDepth = 8
+
+var errCorruptFontProgram = errors.New("pdfcpu: corrupt font program")
+
+// trueType represents the parts of a TrueType font program needed for rendering glyphs.
+type trueType struct {
+	tables     map[string][]byte
+	unitsPerEm float64
+	locaLong   bool
+	numGlyphs  int
+	cmaps      map[uint32]map[uint32]uint16 // platformID<<16|encodingID -> code -> glyph id
+	names      map[string]uint16            // post table glyph names
+	advances   []uint16                     // hmtx
+}
+
+func u16(bb []byte, off int) uint16 {
+	if off < 0 || off+2 > len(bb) {
+		return 0
+	}
+	return binary.BigEndian.Uint16(bb[off:])
+}
+
+func u32(bb []byte, off int) uint32 {
+	if off < 0 || off+4 > len(bb) {
+		return 0
+	}
+	return binary.BigEndian.Uint32(bb[off:])
+}
+
+func sfntTables(bb []byte) (map[string][]byte, error) {
+	if len(bb) < 12 {
+		return nil, errCorruptFontProgram
+	}
+	n := int(u16(bb, 4))
+	tables := map[string][]byte{}
+	for i := 0; i < n; i++ {
+		rec := 12 + 16*i
+		if rec+16 > len(bb) {
+			return nil, errCorruptFontProgram
+		}
+		tag := string(bb[rec : rec+4])
+		off, l := int(u32(bb, rec+8)), int(u32(bb, rec+12))
+		if off < 0 || l < 0 || off > len(bb) {
+			continue
+		}
+		if off+l > len(bb) {
+			// Be lenient with truncated tables.
+			l = len(bb) - off
+		}
+		tables[tag] = bb[off : off+l]
+	}
+	return tables, nil
+}
+
+func parseTrueType(bb []byte) (*trueType, error) {
+	tables, err := sfntTables(bb)
+	if err != nil {
+		return nil, err
+	}
+
+	head := tables["head"]
+	if len(head) < 54 || tables["glyf"] == nil || tables["loca"] == nil {
+		return nil, errCorruptFontProgram
+	}
+
+	tt := &trueType{tables: tables, unitsPerEm: float64(u16(head, 18)), locaLong: u16(head, 50) == 1}
+	if tt.unitsPerEm == 0 {
+		tt.unitsPerEm = 1000
+	}
+
+	if maxp := tables["maxp"]; len(maxp) >= 6 {
+		tt.numGlyphs = int(u16(maxp, 4))
+	}
+	locaEntries := len(tables["loca"])/2 - 1
+	if tt.locaLong {
+		locaEntries = len(tables["loca"])/4 - 1
+	}
+	if tt.numGlyphs == 0 || tt.numGlyphs > locaEntries {
+		tt.numGlyphs = locaEntries
+	}
+
+	tt.parseCMaps()
+	tt.parsePost()
+	tt.parseHMetrics()
+
+	return tt, nil
+}
+
+func (tt *trueType) parseHMetrics() {
+	hhea, hmtx := tt.tables["hhea"], tt.tables["hmtx"]
+	if len(hhea) < 36 {
+		return
+	}
+	n := int(u16(hhea, 34))
+	for i := 0; i < n && 4*i+2 <= len(hmtx); i++ {
+		tt.advances = append(tt.advances, u16(hmtx, 4*i))
+	}
+}
+
+// advance returns the advance width of gid in font units.
+func (tt *trueType) advance(gid uint16) float64 {
+	if len(tt.advances) == 0 {
+		return 0
+	}
+	if int(gid) >= len(tt.advances) {
+		return float64(tt.advances[len(tt.advances)-1])
+	}
+	return float64(tt.advances[gid])
+}
+
+func cmapSubtable(bb []byte) map[uint32]uint16 {
+	m := map[uint32]uint16{}
+
+	switch u16(bb, 0) {
+
+	case 0:
+		for c := 0; c < 256 && 6+c < len(bb); c++ {
+			if gid := bb[6+c]; gid != 0 {
+				m[uint32(c)] = uint16(gid)
+			}
+		}
+
+	case 4:
+		segX2 := int(u16(bb, 6))
+		ends, starts, deltas, rangeOffs := 14, 16+segX2, 16+2*segX2, 16+3*segX2
+		for i := 0; i < segX2; i += 2 {
+			end, start := int(u16(bb, ends+i)), int(u16(bb, starts+i))
+			delta, ro := u16(bb, deltas+i), int(u16(bb, rangeOffs+i))
+			if start > end || end-start > 0xFFFF {
+				continue
+			}
+			for c := start; c <= end && c != 0xFFFF; c++ {
+				var gid uint16
+				if ro == 0 {
+					gid = uint16(c) + delta
+				} else {
+					off := rangeOffs + i + ro + 2*(c-start)
+					if off+2 > len(bb) {
+						break
+					}
+					if gid = u16(bb, off); gid != 0 {
+						gid += delta
+					}
+				}
+				if gid != 0 {
+					m[uint32(c)] = gid
+				}
+			}
+		}
+
+	case 6:
+		first, n := int(u16(bb, 6)), int(u16(bb, 8))
+		for i := 0; i < n; i++ {
+			if gid := u16(bb, 10+2*i); gid != 0 {
+				m[uint32(first+i)] = gid
+			}
+		}
+
+	case 12:
+		n := int(u32(bb, 12))
+		for i := 0; i < n && 16+12*i+12 <= len(bb); i++ {
+			rec := 16 + 12*i
+			start, end, gid := u32(bb, rec), u32(bb, rec+4), u32(bb, rec+8)
+			if end < start || end-start > 0xFFFF {
+				continue
+			}
+			for c := start; c <= end; c++ {
+				m[c] = uint16(gid + c - start)
+			}
+		}
+	}
+
+	return m
+}
+
+func (tt *trueType) parseCMaps() {
+	tt.cmaps = map[uint32]map[uint32]uint16{}
+	bb := tt.tables["cmap"]
+	n := int(u16(bb, 2))
+	for i := 0; i < n; i++ {
+		rec := 4 + 8*i
+		if rec+8 > len(bb) {
+			return
+		}
+		pe := u32(bb, rec)
+		off := int(u32(bb, rec+4))
+		if off >= len(bb) {
+			continue
+		}
+		if _, ok := tt.cmaps[pe]; !ok {
+			tt.cmaps[pe] = cmapSubtable(bb[off:])
+		}
+	}
+}
+
+func (tt *trueType) parsePost() {
+	bb := tt.tables["post"]
+	if len(bb) < 34 || u32(bb, 0) != 0x00020000 {
+		return
+	}
+	n := int(u16(bb, 32))
+	ii := make([]int, n)
+	for i := range ii {
+		ii[i] = int(u16(bb, 34+2*i))
+	}
+
+	// Pascal strings following the glyph name indices.
+	var names []string
+	for off := 34 + 2*n; off < len(bb); {
+		l := int(bb[off])
+		if off+1+l > len(bb) {
+			break
+		}
+		names = append(names, string(bb[off+1:off+1+l]))
+		off += 1 + l
+	}
+
+	tt.names = map[string]uint16{}
+	for gid, i := range ii {
+		var s string
+		switch {
+		case i < len(macGlyphNames):
+			s = macGlyphNames[i]
+		case i-len(macGlyphNames) < len(names):
+			s = names[i-len(macGlyphNames)]
+		default:
+			continue
+		}
+		if _, ok := tt.names[s]; !ok {
+			tt.names[s] = uint16(gid)
+		}
+	}
+}
+
+func (tt *trueType) cmap(platformID, encodingID uint16) map[uint32]uint16 {
+	return tt.cmaps[uint32(platformID)<<16|uint32(encodingID)]
+}
+
+func (tt *trueType) glyphData(gid uint16) []byte {
+	if int(gid) >= tt.numGlyphs {
+		return nil
+	}
+	loca, glyf := tt.tables["loca"], tt.tables["glyf"]
+	var from, to int
+	if tt.locaLong {
+		from, to = int(u32(loca, 4*int(gid))), int(u32(loca, 4*int(gid)+4))
+	} else {
+		from, to = 2*int(u16(loca, 2*int(gid))), 2*int(u16(loca, 2*int(gid)+2))
+	}
+	if from >= to || to > len(glyf) {
+		return nil
+	}
+	return glyf[from:to]
+}
+
+type ttPoint struct {
+	x, y  float64
+	onCrv bool
+}
+
+// contour appends a closed contour made of quadratic Bézier curves to p.
+func contour(p *raster.Path, pts []ttPoint) {
+	n := len(pts)
+	if n == 0 {
+		return
+	}
+
+	mid := func(a, b ttPoint) ttPoint {
+		return ttPoint{x: (a.x + b.x) / 2, y: (a.y + b.y) / 2, onCrv: true}
+	}
+
+	// Start with a point on the curve.
+	seq := make([]ttPoint, 0, n+2)
+	start := -1
+	for i, pt := range pts {
+		if pt.onCrv {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		seq = append(append(seq, mid(pts[n-1], pts[0])), pts...)
+	} else {
+		seq = append(append(seq, pts[start:]...), pts[:start]...)
+	}
+	seq = append(seq, seq[0])
+
+	p.MoveTo(seq[0].x, seq[0].y)
+
+	var ctrl *ttPoint
+	for _, pt := range seq[1:] {
+		switch {
+		case pt.onCrv && ctrl == nil:
+			p.LineTo(pt.x, pt.y)
+		case pt.onCrv:
+			p.QuadTo(ctrl.x, ctrl.y, pt.x, pt.y)
+			ctrl = nil
+		case ctrl != nil:
+			m := mid(*ctrl, pt)
+			p.QuadTo(ctrl.x, ctrl.y, m.x, m.y)
+		}
+		if !pt.onCrv {
+			c := pt
+			ctrl = &c
+		}
+	}
+	p.Close()
+}
+
+func simpleGlyph(bb []byte, nc int) ([][]ttPoint, error) {
+	off := 10
+	ends := make([]int, nc)
+	for i := range ends {
+		ends[i] = int(u16(bb, off))
+		off += 2
+	}
+	if nc == 0 {
+		return nil, nil
+	}
+	n := ends[nc-1] + 1
+	off += 2 + int(u16(bb, off)) // skip instructions
+
+	flags := make([]byte, 0, n)
+	for len(flags) < n {
+		if off >= len(bb) {
+			return nil, errCorruptFontProgram
+		}
+		f := bb[off]
+		off++
+		flags = append(flags, f)
+		if f&0x08 != 0 {
+			if off >= len(bb) {
+				return nil, errCorruptFontProgram
+			}
+			r := int(bb[off])
+			off++
+			for ; r > 0 && len(flags) < n; r-- {
+				flags = append(flags, f)
+			}
+		}
+	}
+
+	pts := make([]ttPoint, n)
+
+	coords := func(short, same byte, set func(i int, v float64)) error {
+		var v int
+		for i, f := range flags {
+			switch {
+			case f&short != 0:
+				if off >= len(bb) {
+					return errCorruptFontProgram
+				}
+				d := int(bb[off])
+				off++
+				if f&same == 0 {
+					d = -d
+				}
+				v += d
+			case f&same == 0:
+				if off+2 > len(bb) {
+					return errCorruptFontProgram
+				}
+				v += int(int16(u16(bb, off)))
+				off += 2
+			}
+			set(i, float64(v))
+		}
+		return nil
+	}
+
+	if err := coords(0x02, 0x10, func(i int, v float64) { pts[i].x = v }); err != nil {
+		return nil, err
+	}
+	if err := coords(0x04, 0x20, func(i int, v float64) { pts[i].y = v }); err != nil {
+		return nil, err
+	}
+	for i, f := range flags {
+		pts[i].onCrv = f&0x01 != 0
+	}
+
+	var cc [][]ttPoint
+	from := 0
+	for _, e := range ends {
+		if e < from || e >= n {
+			return nil, errCorruptFontProgram
+		}
+		cc = append(cc, pts[from:e+1])
+		from = e + 1
+	}
+	return cc, nil
+}
+
+func f2dot14(v uint16) float64 {
+	return float64(int16(v)) / 16384
+}
+
+// contours returns the contours of gid in font units.
+func (tt *trueType) contours(gid uint16, depth int) [][]ttPoint {
+	bb := tt.glyphData(gid)
+	if len(bb) < 10 || depth > maxCompositeDepth {
+		return nil
+	}
+
+	nc := int(int16(u16(bb, 0)))
+	if nc >= 0 {
+		cc, err := simpleGlyph(bb, nc)
+		if err != nil {
+			return nil
+		}
+		return cc
+	}
+
+	// Composite glyph
+	var res [][]ttPoint
+	off := 10
+	for {
+		if off+4 > len(bb) {
+			break
+		}
+		flags, comp := u16(bb, off), u16(bb, off+2)
+		off += 4
+
+		var dx, dy float64
+		if flags&0x0001 != 0 {
+			dx, dy = float64(int16(u16(bb, off))), float64(int16(u16(bb, off+2)))
+			off += 4
+		} else {
+			if off+2 > len(bb) {
+				break
+			}
+			dx, dy = float64(int8(bb[off])), float64(int8(bb[off+1]))
+			off += 2
+		}
+		if flags&0x0002 == 0 {
+			// Point matching is not supported.
+			dx, dy = 0, 0
+		}
+
+		a, b, c, d := 1., 0., 0., 1.
+		switch {
+		case flags&0x0008 != 0:
+			a = f2dot14(u16(bb, off))
+			d = a
+			off += 2
+		case flags&0x0040 != 0:
+			a, d = f2dot14(u16(bb, off)), f2dot14(u16(bb, off+2))
+			off += 4
+		case flags&0x0080 != 0:
+			a, b, c, d = f2dot14(u16(bb, off)), f2dot14(u16(bb, off+2)), f2dot14(u16(bb, off+4)), f2dot14(u16(bb, off+6))
+			off += 8
+		}
+
+		for _, cnt := range tt.contours(comp, depth+1) {
+			t := make([]ttPoint, len(cnt))
+			for i, p := range cnt {
+				t[i] = ttPoint{x: a*p.x + c*p.y + dx, y: b*p.x + d*p.y + dy, onCrv: p.onCrv}
+			}
+			res = append(res, t)
+		}
+
+		if flags&0x0020 == 0 {
+			break
+		}
+	}
+	return res
+}
+
+// outline returns the outline of gid scaled to a font size of 1.
+func (tt *trueType) outline(gid uint16) *raster.Path {
+	p := &raster.Path{}
+	s := 1 / tt.unitsPerEm
+	for _, c := range tt.contours(gid, 0) {
+		pts := make([]ttPoint, len(c))
+		for i, pt := range c {
+			pts[i] = ttPoint{x: pt.x * s, y: pt.y * s, onCrv: pt.onCrv}
+		}
+		contour(p, pts)
+	}
+	return p
+}
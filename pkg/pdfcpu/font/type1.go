@@ -0,0 +1,514 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"bytes"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/raster"
+)
+
+// See Adobe Type 1 Font Format
+
+var (
+	reFontMatrix = regexp.MustCompile(`/FontMatrix\s*\[\s*([^\]]+)\]`)
+	reLenIV      = regexp.MustCompile(`/lenIV\s+(-?\d+)`)
+)
+
+// type1 represents the parts of a Type 1 font program needed for rendering glyphs.
+type type1 struct {
+	charStrings map[string][]byte
+	subrs       [][]byte
+	fontMatrix  matrix.Matrix
+}
+
+func decrypt(bb []byte, r uint16, skip int) []byte {
+	const c1, c2 = 52845, 22719
+	res := make([]byte, len(bb))
+	for i, c := range bb {
+		res[i] = c ^ byte(r>>8)
+		r = (uint16(c)+r)*c1 + c2
+	}
+	if skip < 0 || skip > len(res) {
+		return res
+	}
+	return res[skip:]
+}
+
+func isHex(bb []byte) bool {
+	if len(bb) < 4 {
+		return false
+	}
+	for _, c := range bb[:4] {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// token returns the next whitespace delimited token of bb starting at i.
+func token(bb []byte, i int) (string, int) {
+	for i < len(bb) && whitespace(bb[i]) {
+		i++
+	}
+	j := i
+	for j < len(bb) && !whitespace(bb[j]) {
+		j++
+	}
+	return string(bb[i:j]), j
+}
+
+func whitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+// parseCharStrings parses entries of the form: /name n RD <n binary bytes> ND starting at i.
+func (t1 *type1) parseCharStrings(bb []byte, i int, charString func([]byte) []byte) {
+	for i < len(bb) {
+		tok, j := token(bb, i)
+		if len(tok) < 2 || tok[0] != '/' {
+			if tok == "" || tok == "end" {
+				return
+			}
+			// Skip ND, |- resp. noaccess def
+			i = j
+			continue
+		}
+		nTok, j := token(bb, j)
+		n, err := strconv.Atoi(nTok)
+		if err != nil {
+			return
+		}
+		_, j = token(bb, j) // RD or -|
+		j++                 // single space
+		if n < 0 || j+n > len(bb) {
+			return
+		}
+		t1.charStrings[tok[1:]] = charString(bb[j : j+n])
+		i = j + n
+	}
+}
+
+func parseType1(bb []byte) (*type1, error) {
+	i := bytes.Index(bb, []byte("eexec"))
+	if i < 0 {
+		return nil, errCorruptFontProgram
+	}
+
+	t1 := &type1{charStrings: map[string][]byte{}, fontMatrix: matrix.Matrix{{0.001, 0, 0}, {0, 0.001, 0}, {0, 0, 1}}}
+
+	if m := reFontMatrix.FindSubmatch(bb[:i]); m != nil {
+		var ff []float64
+		for _, s := range bytes.Fields(m[1]) {
+			if f, err := strconv.ParseFloat(string(s), 64); err == nil {
+				ff = append(ff, f)
+			}
+		}
+		if len(ff) == 6 {
+			t1.fontMatrix = matrix.Matrix{{ff[0], ff[1], 0}, {ff[2], ff[3], 0}, {ff[4], ff[5], 1}}
+		}
+	}
+
+	enc := bb[i+5:]
+	for len(enc) > 0 && whitespace(enc[0]) {
+		enc = enc[1:]
+	}
+	if isHex(enc) {
+		h := bytes.Map(func(r rune) rune {
+			if r < 128 && whitespace(byte(r)) {
+				return -1
+			}
+			return r
+		}, enc)
+		if end := bytes.IndexFunc(h, func(r rune) bool { return !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F') }); end >= 0 {
+			h = h[:end]
+		}
+		dec := make([]byte, len(h)/2)
+		if _, err := hex.Decode(dec, h[:2*len(dec)]); err != nil {
+			return nil, errCorruptFontProgram
+		}
+		enc = dec
+	}
+
+	priv := decrypt(enc, 55665, 4)
+
+	lenIV := 4
+	if m := reLenIV.FindSubmatch(priv); m != nil {
+		lenIV, _ = strconv.Atoi(string(m[1]))
+	}
+
+	charString := func(data []byte) []byte {
+		if lenIV < 0 {
+			return data
+		}
+		return decrypt(data, 4330, lenIV)
+	}
+
+	if j := bytes.Index(priv, []byte("/Subrs")); j >= 0 {
+		_, k := token(priv, j+len("/Subrs")) // count
+		t1.parseSubrs(priv, k, charString)
+	}
+
+	if j := bytes.Index(priv, []byte("/CharStrings")); j >= 0 {
+		// Skip to the first glyph name.
+		k := j + len("/CharStrings")
+		for k < len(priv) && priv[k] != '/' {
+			k++
+		}
+		t1.parseCharStrings(priv, k, charString)
+	}
+
+	if len(t1.charStrings) == 0 {
+		return nil, errCorruptFontProgram
+	}
+
+	return t1, nil
+}
+
+// parseSubrs parses entries of the form: dup i n RD <n binary bytes> NP
+func (t1 *type1) parseSubrs(bb []byte, i int, charString func([]byte) []byte) {
+	for i < len(bb) {
+		tok, j := token(bb, i)
+		switch tok {
+		case "dup":
+		case "", "ND", "|-", "readonly", "def", "end":
+			return
+		default:
+			if len(tok) > 0 && tok[0] == '/' {
+				return
+			}
+			i = j
+			continue
+		}
+		idxTok, j := token(bb, j)
+		nTok, j := token(bb, j)
+		idx, err1 := strconv.Atoi(idxTok)
+		n, err2 := strconv.Atoi(nTok)
+		if err1 != nil || err2 != nil || idx < 0 || idx > 65535 {
+			return
+		}
+		_, j = token(bb, j) // RD or -|
+		j++
+		if n < 0 || j+n > len(bb) {
+			return
+		}
+		for len(t1.subrs) <= idx {
+			t1.subrs = append(t1.subrs, nil)
+		}
+		t1.subrs[idx] = charString(bb[j : j+n])
+		i = j + n
+		// Skip NP resp. noaccess put
+		for {
+			tok, k := token(bb, i)
+			if tok != "NP" && tok != "|" && tok != "noaccess" && tok != "put" {
+				break
+			}
+			i = k
+		}
+	}
+}
+
+// type1Interpreter interprets Type 1 charstrings.
+type type1Interpreter struct {
+	t1     *type1
+	p      *raster.Path
+	x, y   float64
+	stack  []float64
+	ps     []float64 // PostScript stack for othersubrs
+	flex   []float64
+	inFlex bool
+	open   bool
+	done   bool
+	ops    int
+	sbx    float64
+	depth  int // seac nesting level
+}
+
+func (t *type1Interpreter) moveTo(x, y float64) {
+	if t.inFlex {
+		t.x, t.y = x, y
+		t.flex = append(t.flex, x, y)
+		return
+	}
+	if t.open {
+		t.p.Close()
+	}
+	t.x, t.y = x, y
+	t.p.MoveTo(x, y)
+	t.open = true
+}
+
+func (t *type1Interpreter) lineTo(x, y float64) {
+	if !t.open {
+		t.moveTo(t.x, t.y)
+	}
+	t.x, t.y = x, y
+	t.p.LineTo(x, y)
+}
+
+func (t *type1Interpreter) curveTo(dx1, dy1, dx2, dy2, dx3, dy3 float64) {
+	if !t.open {
+		t.moveTo(t.x, t.y)
+	}
+	x1, y1 := t.x+dx1, t.y+dy1
+	x2, y2 := x1+dx2, y1+dy2
+	t.x, t.y = x2+dx3, y2+dy3
+	t.p.CubeTo(x1, y1, x2, y2, t.x, t.y)
+}
+
+func (t *type1Interpreter) seac(asb, adx, ady float64, base, accent int) {
+	std := baseEncoding("StandardEncoding")
+	if base < 0 || base > 255 || accent < 0 || accent > 255 {
+		return
+	}
+	t.p.Append(t.t1.charString(std[base], t.depth+1))
+	m := matrix.IdentMatrix
+	m[2][0], m[2][1] = adx-asb+t.sbx, ady
+	t.p.Append(t.t1.charString(std[accent], t.depth+1).Transform(m))
+	t.done = true
+}
+
+func (t *type1Interpreter) callOtherSubr() {
+	s := t.stack
+	if len(s) < 2 {
+		return
+	}
+	nr, n := int(s[len(s)-1]), int(s[len(s)-2])
+	s = s[:len(s)-2]
+	if n < 0 || n > len(s) {
+		n = len(s)
+	}
+	args := s[len(s)-n:]
+	t.stack = s[:len(s)-n]
+
+	switch nr {
+	case 0:
+		// End of flex: the collected points are the reference point followed by the points of two curves.
+		t.inFlex = false
+		if f := t.flex; len(f) >= 14 {
+			if !t.open {
+				t.moveTo(t.x, t.y)
+			}
+			t.p.CubeTo(f[2], f[3], f[4], f[5], f[6], f[7])
+			t.p.CubeTo(f[8], f[9], f[10], f[11], f[12], f[13])
+			t.x, t.y = f[12], f[13]
+		}
+		t.flex = nil
+		t.ps = append(t.ps, t.y, t.x)
+		return
+	case 1:
+		t.inFlex = true
+		t.flex = nil
+		return
+	case 2:
+		return
+	}
+
+	// Other subroutines like hint replacement return their arguments.
+	for i := len(args) - 1; i >= 0; i-- {
+		t.ps = append(t.ps, args[i])
+	}
+}
+
+func (t *type1Interpreter) escape(op byte) {
+	s := t.stack
+	switch op {
+	case 6: // seac
+		if len(s) >= 5 {
+			t.seac(s[0], s[1], s[2], int(s[3]), int(s[4]))
+		}
+	case 7: // sbw
+		if len(s) >= 4 {
+			t.sbx = s[0]
+			t.x, t.y = s[0], s[1]
+		}
+	case 12: // div
+		if n := len(s); n > 1 && s[n-1] != 0 {
+			t.stack = append(s[:n-2], s[n-2]/s[n-1])
+		}
+		return
+	case 16: // callothersubr
+		t.callOtherSubr()
+		return
+	case 17: // pop
+		if n := len(t.ps); n > 0 {
+			t.stack = append(t.stack, t.ps[n-1])
+			t.ps = t.ps[:n-1]
+		}
+		return
+	case 33: // setcurrentpoint
+		if len(s) >= 2 {
+			t.x, t.y = s[0], s[1]
+		}
+	}
+	t.stack = t.stack[:0]
+}
+
+func (t *type1Interpreter) run(bb []byte, depth int) {
+	if depth > maxSubrDepth {
+		t.done = true
+		return
+	}
+
+	for i := 0; i < len(bb) && !t.done; {
+		if t.ops++; t.ops > maxCharStringOps {
+			t.done = true
+			return
+		}
+
+		b0 := bb[i]
+
+		switch {
+		case b0 >= 32 && b0 <= 246:
+			t.stack = append(t.stack, float64(int(b0)-139))
+			i++
+			continue
+		case b0 >= 247 && b0 <= 250 && i+1 < len(bb):
+			t.stack = append(t.stack, float64((int(b0)-247)*256+int(bb[i+1])+108))
+			i += 2
+			continue
+		case b0 >= 251 && b0 <= 254 && i+1 < len(bb):
+			t.stack = append(t.stack, float64(-(int(b0)-251)*256-int(bb[i+1])-108))
+			i += 2
+			continue
+		case b0 == 255 && i+4 < len(bb):
+			t.stack = append(t.stack, float64(int32(u32(bb, i+1))))
+			i += 5
+			continue
+		}
+
+		i++
+		s := t.stack
+
+		switch b0 {
+
+		case 13: // hsbw
+			if len(s) >= 2 {
+				t.sbx = s[0]
+				t.x, t.y = s[0], 0
+			}
+
+		case 21: // rmoveto
+			if len(s) >= 2 {
+				t.moveTo(t.x+s[0], t.y+s[1])
+			}
+
+		case 22: // hmoveto
+			if len(s) >= 1 {
+				t.moveTo(t.x+s[0], t.y)
+			}
+
+		case 4: // vmoveto
+			if len(s) >= 1 {
+				t.moveTo(t.x, t.y+s[0])
+			}
+
+		case 5: // rlineto
+			if len(s) >= 2 {
+				t.lineTo(t.x+s[0], t.y+s[1])
+			}
+
+		case 6: // hlineto
+			if len(s) >= 1 {
+				t.lineTo(t.x+s[0], t.y)
+			}
+
+		case 7: // vlineto
+			if len(s) >= 1 {
+				t.lineTo(t.x, t.y+s[0])
+			}
+
+		case 8: // rrcurveto
+			if len(s) >= 6 {
+				t.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			}
+
+		case 30: // vhcurveto
+			if len(s) >= 4 {
+				t.curveTo(0, s[0], s[1], s[2], s[3], 0)
+			}
+
+		case 31: // hvcurveto
+			if len(s) >= 4 {
+				t.curveTo(s[0], 0, s[1], s[2], 0, s[3])
+			}
+
+		case 9: // closepath
+			if t.open {
+				t.p.Close()
+				t.open = false
+			}
+
+		case 10: // callsubr
+			if len(s) == 0 {
+				return
+			}
+			nr := int(s[len(s)-1])
+			t.stack = s[:len(s)-1]
+			if nr >= 0 && nr < len(t.t1.subrs) {
+				t.run(t.t1.subrs[nr], depth+1)
+			}
+			continue
+
+		case 11: // return
+			return
+
+		case 14: // endchar
+			if t.open {
+				t.p.Close()
+				t.open = false
+			}
+			t.done = true
+			return
+
+		case 12: // escape
+			if i < len(bb) {
+				op := bb[i]
+				i++
+				t.escape(op)
+			}
+			continue
+		}
+
+		t.stack = t.stack[:0]
+	}
+}
+
+// charString returns the outline of the named glyph in glyph space.
+func (t1 *type1) charString(name string, depth int) *raster.Path {
+	p := &raster.Path{}
+	bb, ok := t1.charStrings[name]
+	if !ok || depth > 1 {
+		return p
+	}
+	t := &type1Interpreter{t1: t1, p: p, depth: depth}
+	t.run(bb, 0)
+	if t.open {
+		p.Close()
+	}
+	return p
+}
+
+// outline returns the outline of the named glyph scaled to a font size of 1.
+func (t1 *type1) outline(name string) *raster.Path {
+	return t1.charString(name, 0).Transform(t1.fontMatrix)
+}
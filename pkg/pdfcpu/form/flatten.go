@@ -0,0 +1,244 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package form
+
+import (
+	pdffont "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/primitives"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// widgetIndRefs returns the widget annotations of the terminal fields indRefs.
+func widgetIndRefs(xRefTable *model.XRefTable, indRefs []types.IndirectRef) (map[types.IndirectRef]bool, error) {
+	m := map[types.IndirectRef]bool{}
+	for _, indRef := range indRefs {
+		d, err := xRefTable.DereferenceDict(indRef)
+		if err != nil {
+			return nil, err
+		}
+		o, ok := d.Find("Kids")
+		if !ok {
+			m[indRef] = true
+			continue
+		}
+		kids, err := xRefTable.DereferenceArray(o)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range kids {
+			if ir, ok := o.(types.IndirectRef); ok {
+				m[ir] = true
+			}
+		}
+	}
+	return m, nil
+}
+
+func fieldType(xRefTable *model.XRefTable, d types.Dict) (*string, error) {
+	if ft := d.NameEntry("FT"); ft != nil {
+		return ft, nil
+	}
+	pd, err := xRefTable.DereferenceDict(d["Parent"])
+	if err != nil || pd == nil {
+		return nil, err
+	}
+	return pd.NameEntry("FT"), nil
+}
+
+func fieldValue(d types.Dict) (string, error) {
+	o, found := d.Find("V")
+	if !found {
+		return "", nil
+	}
+	s, err := types.StringOrHexLiteral(o)
+	if err != nil || s == nil {
+		return "", err
+	}
+	return *s, nil
+}
+
+// refreshFieldAP regenerates the appearance streams of the widgets of the terminal field d.
+func refreshFieldAP(ctx *model.Context, d types.Dict, fonts map[string]types.IndirectRef) error {
+	ft, err := fieldType(ctx.XRefTable, d)
+	if err != nil || ft == nil {
+		return err
+	}
+
+	ff := primitives.FieldFlags(0)
+	if i := d.IntEntry("Ff"); i != nil {
+		ff = primitives.FieldFlags(*i)
+	}
+
+	widgets := []types.Dict{d}
+	if kids := d.ArrayEntry("Kids"); len(kids) > 0 {
+		widgets = nil
+		for _, o := range kids {
+			wd, err := ctx.DereferenceDict(o)
+			if err != nil {
+				return err
+			}
+			widgets = append(widgets, wd)
+		}
+	}
+
+	switch *ft {
+
+	case "Tx":
+		v, err := fieldValue(d)
+		if err != nil {
+			return err
+		}
+		df, err := extractDateFormat(d)
+		if err != nil {
+			return err
+		}
+		for _, wd := range widgets {
+			if df != nil {
+				err = primitives.EnsureDateFieldAP(ctx, wd, v, fonts)
+			} else {
+				err = primitives.EnsureTextFieldAP(ctx, wd, v, ff&primitives.FieldMultiline > 0, ff&primitives.FieldComb > 0, fonts)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+	case "Ch":
+		if ff&primitives.FieldCombo > 0 {
+			v, err := fieldValue(d)
+			if err != nil {
+				return err
+			}
+			for _, wd := range widgets {
+				if err := primitives.EnsureComboBoxAP(ctx, wd, v, fonts); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		opts, err := parseOptions(ctx.XRefTable, d, false)
+		if err != nil {
+			return err
+		}
+		for _, wd := range widgets {
+			if err := primitives.EnsureListBoxAP(ctx, wd, opts, d.ArrayEntry("I"), fonts); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// refreshAppearances regenerates the appearance streams of the terminal fields indRefs.
+func refreshAppearances(ctx *model.Context, indRefs []types.IndirectRef) error {
+	xRefTable := ctx.XRefTable
+
+	if err := setupFillFonts(xRefTable); err != nil {
+		return err
+	}
+
+	fonts := map[string]types.IndirectRef{}
+
+	for _, indRef := range indRefs {
+		d, err := xRefTable.DereferenceDict(indRef)
+		if err != nil {
+			return err
+		}
+		if err := refreshFieldAP(ctx, d, fonts); err != nil {
+			return err
+		}
+	}
+
+	for fName, indRef := range fonts {
+		if len(ctx.UsedGIDs[fName]) == 0 {
+			continue
+		}
+		fDict, err := xRefTable.DereferenceDict(indRef)
+		if err != nil {
+			return err
+		}
+		fr := model.FontResource{}
+		if err := pdffont.IndRefsForUserfontUpdate(xRefTable, fDict, "", &fr); err != nil {
+			return pdffont.ErrCorruptFontDict
+		}
+		if err := pdffont.UpdateUserfont(xRefTable, fName, fr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// visibleWidgets returns the widgets which are neither hidden nor excluded from display.
+func visibleWidgets(xRefTable *model.XRefTable, widgets map[types.IndirectRef]bool) (map[types.IndirectRef]bool, error) {
+	m := map[types.IndirectRef]bool{}
+	for indRef := range widgets {
+		d, err := xRefTable.DereferenceDict(indRef)
+		if err != nil {
+			return nil, err
+		}
+		if model.AnnotationFlagsFor(d)&(model.AnnHidden|model.AnnNoView) == 0 {
+			m[indRef] = true
+		}
+	}
+	return m, nil
+}
+
+// FlattenFormFields paints the appearances of all form fields contained in fieldIDsOrNames into the page content
+// and removes the fields afterwards. If fieldIDsOrNames is empty the whole form gets flattened.
+func FlattenFormFields(ctx *model.Context, fieldIDsOrNames []string) (bool, error) {
+	xRefTable := ctx.XRefTable
+
+	fields, err := fields(xRefTable)
+	if err != nil {
+		return false, err
+	}
+
+	indRefs, err := annotIndRefsForFields(xRefTable, fieldIDsOrNames, fields)
+	if err != nil {
+		return false, err
+	}
+	if len(indRefs) == 0 {
+		return false, nil
+	}
+
+	// Viewers are expected to generate appearances if NeedAppearances is set.
+	if b := xRefTable.Form.BooleanEntry("NeedAppearances"); b != nil && *b {
+		if err := refreshAppearances(ctx, indRefs); err != nil {
+			return false, err
+		}
+	}
+
+	widgets, err := widgetIndRefs(xRefTable, indRefs)
+	if err != nil {
+		return false, err
+	}
+
+	if widgets, err = visibleWidgets(xRefTable, widgets); err != nil {
+		return false, err
+	}
+
+	for i := 1; i <= xRefTable.PageCount; i++ {
+		if err := xRefTable.PaintAppearances(i, widgets); err != nil {
+			return false, err
+		}
+	}
+
+	return RemoveFormFields(ctx, fieldIDsOrNames)
+}
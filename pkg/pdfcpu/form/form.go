@@ -32,6 +32,9 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ErrNoFormFields signals a form w/o fields.
+var ErrNoFormFields = errors.New("pdfcpu: no form fields available")
+
 // FieldType represents a form field type.
 type FieldType int
 
@@ -100,7 +103,7 @@ func fields(xRefTable *model.XRefTable) (types.Array, error) {
 
 	o, ok := xRefTable.Form.Find("Fields")
 	if !ok {
-		return nil, errors.New("pdfcpu: no form fields available")
+		return nil, ErrNoFormFields
 	}
 
 	fields, err := xRefTable.DereferenceArray(o)
@@ -109,7 +112,7 @@ func fields(xRefTable *model.XRefTable) (types.Array, error) {
 	}
 
 	if len(fields) == 0 {
-		return nil, errors.New("pdfcpu: no form fields available")
+		return nil, ErrNoFormFields
 	}
 
 	return fields, nil
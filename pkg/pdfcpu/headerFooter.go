@@ -0,0 +1,536 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// ErrNoHeaderFooter indicates there is no header or footer to remove.
+var ErrNoHeaderFooter = errors.New("pdfcpu: no header/footer found")
+
+// Headers and footers are stamps marked as pagination artifacts of their own subtypes
+// which keeps them apart from regular stamps and watermarks.
+var headerFooterArtifacts = []string{"Header", "Footer"}
+
+// lineHeightFactor relates the height of a line of header/footer text to its font size.
+const lineHeightFactor = 1.2
+
+// ParseHeaderFooterConfig parses a HeaderFooter command string into an internal structure.
+// "hl:%f, hr:Page %p of %P, fc:%{Title}, mirror:on"
+// Commas within slot texts need to be escaped: "hc:Berlin\, Germany".
+func ParseHeaderFooterConfig(s string, u types.DisplayUnit) (*model.HeaderFooter, error) {
+	if s == "" {
+		return nil, errors.New("pdfcpu: missing headerfooter configuration string")
+	}
+
+	hf := model.DefaultHeaderFooterConfig()
+	hf.Unit = u
+
+	for _, s := range splitHeaderFooterConfig(s) {
+
+		ss := strings.SplitN(s, ":", 2)
+		if len(ss) != 2 {
+			return nil, errors.New("pdfcpu: Invalid headerfooter configuration string. Please consult pdfcpu help headerfooter")
+		}
+
+		paramPrefix := strings.TrimSpace(ss[0])
+		paramValueStr := strings.TrimSpace(ss[1])
+
+		if err := model.HeaderFooterParamMap.Handle(paramPrefix, paramValueStr, hf); err != nil {
+			return nil, err
+		}
+	}
+
+	return hf, hf.Validate()
+}
+
+// splitHeaderFooterConfig splits s at all commas not escaped by a backslash.
+func splitHeaderFooterConfig(s string) []string {
+	var (
+		ss []string
+		sb strings.Builder
+	)
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && s[i+1] == ',' {
+			sb.WriteByte(',')
+			i++
+			continue
+		}
+		if s[i] == ',' {
+			ss = append(ss, sb.String())
+			sb.Reset()
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return append(ss, sb.String())
+}
+
+// documentProperties returns the document info of ctx available for %{key} substitution.
+func documentProperties(ctx *model.Context) map[string]string {
+	m := map[string]string{
+		"Title":    ctx.Title,
+		"Author":   ctx.Author,
+		"Subject":  ctx.Subject,
+		"Creator":  ctx.Creator,
+		"Producer": ctx.Producer,
+		"Keywords": ctx.Keywords,
+	}
+	for k, v := range ctx.Properties {
+		m[k] = v
+	}
+	return m
+}
+
+// headerFooterText replaces %l, %f, %d and %{key} in s.
+// The remaining placeholders are left to the stamp machinery.
+func headerFooterText(s, label, fileName, date string, props map[string]string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case 'l':
+			sb.WriteString(label)
+		case 'f':
+			sb.WriteString(fileName)
+		case 'd':
+			sb.WriteString(date)
+		case '%':
+			sb.WriteString("%%")
+		case '{':
+			j := strings.IndexByte(s[i+2:], '}')
+			if j < 0 {
+				sb.WriteByte(s[i])
+				continue
+			}
+			sb.WriteString(props[s[i+2:i+2+j]])
+			i += j + 2
+			continue
+		default:
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+	}
+	return sb.String()
+}
+
+// headerFooterSlots returns the header and footer slots of hf for page pageNr.
+func headerFooterSlots(hf *model.HeaderFooter, pageNr int) ([3]string, [3]string) {
+	header, footer := hf.Header, hf.Footer
+	if hf.Mirror && pageNr%2 == 0 {
+		header[model.SlotLeft], header[model.SlotRight] = header[model.SlotRight], header[model.SlotLeft]
+		footer[model.SlotLeft], footer[model.SlotRight] = footer[model.SlotRight], footer[model.SlotLeft]
+	}
+	return header, footer
+}
+
+// bandHeight returns the height of the page band occupied by slots.
+func bandHeight(hf *model.HeaderFooter, slots [3]string) float64 {
+	lines := 0
+	for _, s := range slots {
+		if s != "" {
+			lines = max(lines, strings.Count(s, "\\n")+1)
+		}
+	}
+	if lines == 0 {
+		return 0
+	}
+	return hf.Margin + float64(lines*hf.FontSize)*lineHeightFactor
+}
+
+// headerFooterWatermark returns the stamp for text in slot of the header or footer.
+func headerFooterWatermark(hf *model.HeaderFooter, text string, header bool, slot int) (*model.Watermark, error) {
+	pos := "b"
+	dy := hf.Margin
+	artifact := "Footer"
+	if header {
+		pos = "t"
+		dy = -hf.Margin
+		artifact = "Header"
+	}
+
+	var dx float64
+	align := "c"
+
+	switch slot {
+	case model.SlotLeft:
+		pos += "l"
+		dx = hf.Margin
+		align = "l"
+	case model.SlotCenter:
+		pos += "c"
+	case model.SlotRight:
+		pos += "r"
+		dx = -hf.Margin
+		align = "r"
+	}
+
+	desc := fmt.Sprintf("pos:%s, off:%.2f %.2f, rot:0, scale:1 abs, aligntext:%s, fontname:%s, points:%d",
+		pos, dx, dy, align, hf.FontName, hf.FontSize)
+
+	wm, err := ParseTextWatermarkDetails(text, desc, true, types.POINTS)
+	if err != nil {
+		return nil, err
+	}
+
+	wm.FillColor = hf.FillColor
+	wm.Artifact = artifact
+
+	return wm, nil
+}
+
+// contentBoxer computes the bounding box of all content painted by a content stream.
+type contentBoxer struct {
+	*interpreter
+	pathBox *types.Rectangle
+	box     *types.Rectangle
+}
+
+func (cb *contentBoxer) add(r *types.Rectangle) {
+	if cb.box == nil {
+		cb.box = r
+		return
+	}
+	cb.box = union(cb.box, r)
+}
+
+// unitSquare adds the unit square in user space as occupied by images.
+func (cb *contentBoxer) unitSquare() {
+	pp := rectCorners(types.NewRectangle(0, 0, 1, 1))
+	for i := range pp {
+		pp[i] = cb.gs.ctm.Transform(pp[i])
+	}
+	cb.add(bboxForPoints(pp...))
+}
+
+// pathOp extends the current path.
+func (cb *contentBoxer) pathOp(op content.Operation) {
+	var pp []types.Point
+
+	switch op.Operator {
+	case "m", "l":
+		if ff, ok := floats(op.Operands, 2); ok {
+			pp = append(pp, types.Point{X: ff[0], Y: ff[1]})
+		}
+	case "c":
+		if ff, ok := floats(op.Operands, 6); ok {
+			pp = append(pp, types.Point{X: ff[0], Y: ff[1]}, types.Point{X: ff[2], Y: ff[3]}, types.Point{X: ff[4], Y: ff[5]})
+		}
+	case "v", "y":
+		if ff, ok := floats(op.Operands, 4); ok {
+			pp = append(pp, types.Point{X: ff[0], Y: ff[1]}, types.Point{X: ff[2], Y: ff[3]})
+		}
+	case "re":
+		if ff, ok := floats(op.Operands, 4); ok {
+			pp = rectCorners(bboxForPoints(types.Point{X: ff[0], Y: ff[1]}, types.Point{X: ff[0] + ff[2], Y: ff[1] + ff[3]}))
+		}
+	}
+
+	if len(pp) == 0 {
+		return
+	}
+
+	for i := range pp {
+		pp[i] = cb.gs.ctm.Transform(pp[i])
+	}
+	box := bboxForPoints(pp...)
+	if cb.pathBox != nil {
+		box = union(cb.pathBox, box)
+	}
+	cb.pathBox = box
+}
+
+// paint adds the current path unless it only serves for clipping.
+func (cb *contentBoxer) paint(op content.Operation) {
+	if op.Operator != "n" && cb.pathBox != nil {
+		cb.add(cb.pathBox)
+	}
+	cb.pathBox = nil
+}
+
+func (cb *contentBoxer) glyph(g placedGlyph) {
+	cb.add(g.bbox)
+}
+
+func (cb *contentBoxer) process(ops []content.Operation, resources types.Dict, depth int) error {
+	for _, op := range ops {
+		cb.state(op, resources)
+
+		switch op.Operator {
+
+		case "m", "l", "c", "v", "y", "re":
+			cb.pathOp(op)
+
+		case "S", "s", "f", "F", "f*", "B", "B*", "b", "b*", "n":
+			cb.paint(op)
+
+		case "Tj", "'", "\"":
+			cb.showString(lastOperand(op.Operands), cb.glyph)
+
+		case "TJ":
+			if a, ok := lastOperand(op.Operands).(types.Array); ok {
+				cb.showArray(a, cb.glyph)
+			}
+
+		case "BI":
+			cb.unitSquare()
+
+		case "Do":
+			if n, ok := lastOperand(op.Operands).(types.Name); ok {
+				if err := cb.xObject(resources, n.Value(), depth); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// xObject processes the XObject fName.
+func (cb *contentBoxer) xObject(resources types.Dict, fName string, depth int) error {
+	if depth >= maxFormDepth {
+		return nil
+	}
+
+	sd, err := cb.interpreter.xObject(resources, fName)
+	if err != nil || sd == nil {
+		return err
+	}
+
+	if st := sd.Dict.Subtype(); st != nil && *st == "Image" {
+		cb.unitSquare()
+		return nil
+	}
+
+	sd, ops, res, err := cb.formXObject(resources, fName)
+	if err != nil || sd == nil {
+		return err
+	}
+
+	restore := cb.enterForm(sd)
+	defer restore()
+
+	return cb.process(ops, res, depth+1)
+}
+
+// pageContentBox returns the bounding box of the content of page pageNr
+// in the coordinate system of the upright page along with the upright visible region.
+func pageContentBox(ctx *model.Context, pageNr int) (*types.Rectangle, *types.Rectangle, error) {
+	d, _, inhPAttrs, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if d == nil {
+		return nil, nil, errors.Errorf("pdfcpu: missing page %d", pageNr)
+	}
+
+	vp := viewPort(inhPAttrs)
+	if types.IntMemberOf(inhPAttrs.Rotate, []int{+90, -90, +270, -270}) {
+		w := vp.Width()
+		vp.UR.X = vp.LL.X + vp.Height()
+		vp.UR.Y = vp.LL.Y + w
+	}
+
+	bb, err := ctx.PageContent(d)
+	if err == model.ErrNoContent {
+		return nil, vp, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ops, err := content.Parse(bb)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cb := &contentBoxer{interpreter: newInterpreter(ctx)}
+	if inhPAttrs.Rotate%360 != 0 {
+		cb.gs.ctm = model.MatrixForPageRotation(inhPAttrs.Rotate, vp.Width(), vp.Height())
+	}
+
+	if err := cb.process(ops, inhPAttrs.Resources, 0); err != nil {
+		return nil, nil, err
+	}
+
+	if cb.box == nil || !intersects(cb.box, vp) {
+		return nil, vp, nil
+	}
+
+	r := types.NewRectangle(
+		max(cb.box.LL.X, vp.LL.X), max(cb.box.LL.Y, vp.LL.Y),
+		min(cb.box.UR.X, vp.UR.X), min(cb.box.UR.Y, vp.UR.Y))
+
+	return r, vp, nil
+}
+
+// shrinkPageForHeaderFooter zooms out the content of page pageNr
+// if it overlaps the header band of height hBand or the footer band of height fBand.
+func shrinkPageForHeaderFooter(ctx *model.Context, pageNr int, hBand, fBand float64) error {
+	r, vp, err := pageContentBox(ctx, pageNr)
+	if err != nil || r == nil {
+		return err
+	}
+
+	if (hBand == 0 || r.UR.Y <= vp.UR.Y-hBand) && (fBand == 0 || r.LL.Y >= vp.LL.Y+fBand) {
+		return nil
+	}
+
+	vMargin := max(hBand, fBand)
+	if 2*vMargin >= vp.Height() {
+		return errors.Errorf("pdfcpu: page %d: header/footer exceeds page height", pageNr)
+	}
+
+	if log.DebugEnabled() {
+		log.Debug.Printf("shrinkPageForHeaderFooter page:%d vmargin:%.2f\n", pageNr, vMargin)
+	}
+
+	return zoomPage(ctx, pageNr, &model.Zoom{VMargin: vMargin})
+}
+
+// removePageHeaderFooter removes the header and footer of page pageNr.
+func removePageHeaderFooter(ctx *model.Context, pageNr int) (bool, error) {
+	d, _, _, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return false, err
+	}
+	if _, found := d.Find("Resources"); !found {
+		return false, nil
+	}
+	if _, found := d.Find("Contents"); !found {
+		return false, nil
+	}
+
+	found, _, err := removePageArtifacts(ctx, pageNr, headerFooterArtifacts)
+	return found, err
+}
+
+// AddHeaderFooter adds hf to all selected pages replacing any header and footer added before.
+// Page content overlapping header or footer gets shrunk if hf.Shrink is set.
+func AddHeaderFooter(ctx *model.Context, selectedPages types.IntSet, hf *model.HeaderFooter) error {
+	if log.DebugEnabled() {
+		log.Debug.Println("AddHeaderFooter")
+	}
+
+	if err := hf.Validate(); err != nil {
+		return err
+	}
+
+	labels, err := PageLabelStrings(ctx)
+	if err != nil {
+		return err
+	}
+
+	props := documentProperties(ctx)
+	date := time.Now().Format(ctx.DateFormat)
+
+	m := map[int][]*model.Watermark{}
+
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+		if len(selectedPages) > 0 && !selectedPages[pageNr] {
+			continue
+		}
+
+		var label string
+		if pageNr <= len(labels) {
+			label = labels[pageNr-1]
+		}
+
+		header, footer := headerFooterSlots(hf, pageNr)
+
+		var wms []*model.Watermark
+		for i, slots := range [][3]string{header, footer} {
+			for slot, s := range slots {
+				if s == "" {
+					continue
+				}
+				wm, err := headerFooterWatermark(hf, headerFooterText(s, label, hf.FileName, date, props), i == 0, slot)
+				if err != nil {
+					return err
+				}
+				wms = append(wms, wm)
+			}
+		}
+
+		if len(wms) == 0 {
+			continue
+		}
+
+		if _, err := removePageHeaderFooter(ctx, pageNr); err != nil {
+			return err
+		}
+
+		if hf.Shrink {
+			if err := shrinkPageForHeaderFooter(ctx, pageNr, bandHeight(hf, header), bandHeight(hf, footer)); err != nil {
+				return err
+			}
+		}
+
+		m[pageNr] = wms
+	}
+
+	if len(m) == 0 {
+		return nil
+	}
+
+	return AddWatermarksSliceMap(ctx, m)
+}
+
+// RemoveHeaderFooter removes headers and footers from all selected pages.
+// Other stamps and watermarks remain untouched.
+func RemoveHeaderFooter(ctx *model.Context, selectedPages types.IntSet) error {
+	if log.DebugEnabled() {
+		log.Debug.Println("RemoveHeaderFooter")
+	}
+
+	var removed bool
+
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+		if len(selectedPages) > 0 && !selectedPages[pageNr] {
+			continue
+		}
+		ok, err := removePageHeaderFooter(ctx, pageNr)
+		if err != nil {
+			return err
+		}
+		if ok {
+			removed = true
+		}
+	}
+
+	if !removed {
+		return ErrNoHeaderFooter
+	}
+
+	ctx.EnsureVersionForWriting()
+
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"math"
 
 	"github.com/pkg/errors"
 )
@@ -309,3 +310,95 @@ func (p iccProfile) String() string {
 
 	return s
 }
+
+func s15Fixed16(f float64) uint32 {
+	return uint32(int32(math.Round(f * 0x10000)))
+}
+
+func iccXYZ(x, y, z float64) []byte {
+	b := make([]byte, 20)
+	copy(b, "XYZ ")
+	binary.BigEndian.PutUint32(b[8:], s15Fixed16(x))
+	binary.BigEndian.PutUint32(b[12:], s15Fixed16(y))
+	binary.BigEndian.PutUint32(b[16:], s15Fixed16(z))
+	return b
+}
+
+func iccText(s string) []byte {
+	b := make([]byte, 8, 8+len(s)+1)
+	copy(b, "text")
+	return append(append(b, s...), 0)
+}
+
+func iccTextDescription(s string) []byte {
+	b := make([]byte, 12, 12+len(s)+1+79)
+	copy(b, "desc")
+	binary.BigEndian.PutUint32(b[8:], uint32(len(s)+1))
+	b = append(append(b, s...), 0)
+	// Empty Unicode, ScriptCode and Macintosh descriptions.
+	return append(b, make([]byte, 79)...)
+}
+
+func iccSRGBCurve() []byte {
+	const n = 1024
+	b := make([]byte, 12+2*n)
+	copy(b, "curv")
+	binary.BigEndian.PutUint32(b[8:], n)
+	for i := 0; i < n; i++ {
+		v := float64(i) / (n - 1)
+		if v <= 0.04045 {
+			v /= 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		binary.BigEndian.PutUint16(b[12+2*i:], uint16(math.Round(v*0xFFFF)))
+	}
+	return b
+}
+
+// sRGBProfile returns an ICC version 2 display profile for sRGB IEC61966-2.1.
+func sRGBProfile() []byte {
+	const tagCount = 9
+
+	b := make([]byte, 128+4+12*tagCount)
+
+	binary.BigEndian.PutUint32(b[128:], tagCount)
+	j := 132
+
+	add := func(data []byte, sigs ...string) {
+		off := len(b)
+		b = append(b, data...)
+		for len(b)%4 > 0 {
+			b = append(b, 0)
+		}
+		for _, sig := range sigs {
+			copy(b[j:], sig)
+			binary.BigEndian.PutUint32(b[j+4:], uint32(off))
+			binary.BigEndian.PutUint32(b[j+8:], uint32(len(data)))
+			j += 12
+		}
+	}
+
+	add(iccTextDescription("sRGB IEC61966-2.1"), "desc")
+	add(iccText("No copyright, use freely"), "cprt")
+	add(iccXYZ(0.9642, 1.0, 0.8249), "wtpt")
+	add(iccXYZ(0.4361, 0.2225, 0.0139), "rXYZ")
+	add(iccXYZ(0.3851, 0.7169, 0.0971), "gXYZ")
+	add(iccXYZ(0.1431, 0.0606, 0.7141), "bXYZ")
+	// The tone reproduction curves share their data.
+	add(iccSRGBCurve(), "rTRC", "gTRC", "bTRC")
+
+	// Header
+	binary.BigEndian.PutUint32(b[0:], uint32(len(b)))
+	binary.BigEndian.PutUint32(b[8:], 0x02100000)
+	copy(b[12:], "mntr")
+	copy(b[16:], "RGB ")
+	copy(b[20:], "XYZ ")
+	for i, v := range []uint16{2025, 1, 1, 0, 0, 0} {
+		binary.BigEndian.PutUint16(b[24+2*i:], v)
+	}
+	copy(b[36:], "acsp")
+	copy(b[68:], iccXYZ(0.9642, 1.0, 0.8249)[8:])
+
+	return b
+}
@@ -67,6 +67,7 @@ var impParamMap = importParamMap{
 	"scalefactor":     parseScaleFactorImp,
 	"gray":            parseGray,
 	"sepia":           parseSepia,
+	"bilevel":         parseBilevel,
 	"backgroundcolor": parseImportBackgroundColor,
 	"bgcolor":         parseImportBackgroundColor,
 }
@@ -84,6 +85,7 @@ type Import struct {
 	InpUnit  types.DisplayUnit // input display unit.
 	Gray     bool              // true for rendering in Gray.
 	Sepia    bool
+	Bilevel  bool               // true for thresholding to black and white.
 	BgColor  *color.SimpleColor // background color
 }
 
@@ -216,6 +218,19 @@ func parseSepia(s string, imp *Import) error {
 	return nil
 }
 
+func parseBilevel(s string, imp *Import) error {
+	switch strings.ToLower(s) {
+	case "on", "true", "t":
+		imp.Bilevel = true
+	case "off", "false", "f":
+		imp.Bilevel = false
+	default:
+		return errors.New("pdfcpu: import bilevel, please provide one of: on/off true/false")
+	}
+
+	return nil
+}
+
 func parseImportBackgroundColor(s string, imp *Import) error {
 	c, err := color.ParseColor(s)
 	if err != nil {
@@ -331,7 +346,16 @@ func importImagePDFBytes(wr io.Writer, pageDim *types.Dim, imgWidth, imgHeight f
 func NewPageForImage(xRefTable *model.XRefTable, r io.Reader, parentIndRef *types.IndirectRef, imp *Import) (*types.IndirectRef, error) {
 
 	// create image dict.
-	imgIndRef, w, h, err := model.CreateImageResource(xRefTable, r, imp.Gray, imp.Sepia)
+	var (
+		imgIndRef *types.IndirectRef
+		w, h      int
+		err       error
+	)
+	if imp.Bilevel {
+		imgIndRef, w, h, err = model.CreateBilevelImageResource(xRefTable, r)
+	} else {
+		imgIndRef, w, h, err = model.CreateImageResource(xRefTable, r, imp.Gray, imp.Sepia)
+	}
 	if err != nil {
 		return nil, err
 	}
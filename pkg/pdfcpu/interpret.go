@@ -0,0 +1,440 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// See 8.4 Graphics State, 9.3 Text State Parameters and Operators and 9.4.4 Text Space Details
+
+// The maximum nesting level of form XObjects.
+const maxFormDepth = 16
+
+// textState represents the text state parameters.
+type textState struct {
+	charSpacing float64       // Tc
+	wordSpacing float64       // Tw
+	scale       float64       // Th
+	leading     float64       // TL
+	font        *font.Decoder // Tf
+	fontSize    float64       // Tfs
+	rise        float64       // Ts
+}
+
+// graphicsState represents the parts of the graphics state relevant for locating content.
+type graphicsState struct {
+	ctm       matrix.Matrix
+	lineWidth float64
+	textState
+}
+
+// placedGlyph is a glyph shown by a text showing operator together with its location in user space.
+type placedGlyph struct {
+	font.Glyph
+	bbox        *types.Rectangle
+	origin, end types.Point   // baseline
+	size        float64       // font size in user space
+	advance     float64       // displacement in unscaled text space units
+	trm         matrix.Matrix // text rendering matrix
+}
+
+// interpreter tracks the graphics state and the text state while processing a content stream.
+type interpreter struct {
+	ctx      *model.Context
+	gs       graphicsState
+	stack    []graphicsState
+	tm, tlm  matrix.Matrix
+	decoders map[int]*font.Decoder
+}
+
+func newInterpreter(ctx *model.Context) *interpreter {
+	return &interpreter{
+		ctx:      ctx,
+		gs:       graphicsState{ctm: matrix.IdentMatrix, lineWidth: 1, textState: textState{scale: 1}},
+		decoders: map[int]*font.Decoder{},
+	}
+}
+
+func floats(oo []types.Object, n int) ([]float64, bool) {
+	if len(oo) < n {
+		return nil, false
+	}
+	ff := make([]float64, n)
+	for i, o := range oo[len(oo)-n:] {
+		switch o := o.(type) {
+		case types.Integer:
+			ff[i] = float64(o.Value())
+		case types.Float:
+			ff[i] = o.Value()
+		default:
+			return nil, false
+		}
+	}
+	return ff, true
+}
+
+func matrixFor(ff []float64) matrix.Matrix {
+	return matrix.Matrix{{ff[0], ff[1], 0}, {ff[2], ff[3], 0}, {ff[4], ff[5], 1}}
+}
+
+func translation(tx, ty float64) matrix.Matrix {
+	m := matrix.IdentMatrix
+	m[2][0], m[2][1] = tx, ty
+	return m
+}
+
+func bboxForPoints(pp ...types.Point) *types.Rectangle {
+	r := types.NewRectangle(pp[0].X, pp[0].Y, pp[0].X, pp[0].Y)
+	for _, p := range pp[1:] {
+		r.LL.X, r.LL.Y = math.Min(r.LL.X, p.X), math.Min(r.LL.Y, p.Y)
+		r.UR.X, r.UR.Y = math.Max(r.UR.X, p.X), math.Max(r.UR.Y, p.Y)
+	}
+	return r
+}
+
+func union(r1, r2 *types.Rectangle) *types.Rectangle {
+	return bboxForPoints(r1.LL, r1.UR, r2.LL, r2.UR)
+}
+
+func distance(p1, p2 types.Point) float64 {
+	return math.Hypot(p2.X-p1.X, p2.Y-p1.Y)
+}
+
+func lastOperand(oo []types.Object) types.Object {
+	if len(oo) == 0 {
+		return nil
+	}
+	return oo[len(oo)-1]
+}
+
+func stringBytes(o types.Object) ([]byte, bool) {
+	switch o := o.(type) {
+	case types.StringLiteral:
+		bb, err := types.Unescape(o.Value())
+		return bb, err == nil
+	case types.HexLiteral:
+		bb, err := o.Bytes()
+		return bb, err == nil
+	}
+	return nil, false
+}
+
+func (ip *interpreter) decoder(resources types.Dict, fontName string) *font.Decoder {
+	if resources == nil {
+		return nil
+	}
+
+	fonts, err := ip.ctx.DereferenceDict(resources["Font"])
+	if err != nil || fonts == nil {
+		return nil
+	}
+
+	o, found := fonts.Find(fontName)
+	if !found {
+		return nil
+	}
+
+	indRef, isIndRef := o.(types.IndirectRef)
+	if isIndRef {
+		if dec, ok := ip.decoders[indRef.ObjectNumber.Value()]; ok {
+			return dec
+		}
+	}
+
+	d, err := ip.ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return nil
+	}
+
+	dec := font.NewDecoder(ip.ctx.XRefTable, d)
+	if isIndRef {
+		ip.decoders[indRef.ObjectNumber.Value()] = dec
+	}
+
+	return dec
+}
+
+func (ip *interpreter) nextLine(tx, ty float64) {
+	ip.tlm = translation(tx, ty).Multiply(ip.tlm)
+	ip.tm = ip.tlm
+}
+
+func (ip *interpreter) textStateOp(op content.Operation, resources types.Dict) {
+	oo := op.Operands
+	ts := &ip.gs.textState
+
+	switch op.Operator {
+
+	case "Tc":
+		if ff, ok := floats(oo, 1); ok {
+			ts.charSpacing = ff[0]
+		}
+
+	case "Tw":
+		if ff, ok := floats(oo, 1); ok {
+			ts.wordSpacing = ff[0]
+		}
+
+	case "Tz":
+		if ff, ok := floats(oo, 1); ok {
+			ts.scale = ff[0] / 100
+		}
+
+	case "TL":
+		if ff, ok := floats(oo, 1); ok {
+			ts.leading = ff[0]
+		}
+
+	case "Ts":
+		if ff, ok := floats(oo, 1); ok {
+			ts.rise = ff[0]
+		}
+
+	case "Tf":
+		if len(oo) < 2 {
+			return
+		}
+		if n, ok := oo[0].(types.Name); ok {
+			ts.font = ip.decoder(resources, n.Value())
+		}
+		if ff, ok := floats(oo, 1); ok {
+			ts.fontSize = ff[0]
+		}
+	}
+}
+
+// state applies the graphics and text state changes of op.
+// For the text showing operators ' and " the implicit move to the next line is applied.
+func (ip *interpreter) state(op content.Operation, resources types.Dict) {
+	oo := op.Operands
+
+	switch op.Operator {
+
+	case "q":
+		ip.stack = append(ip.stack, ip.gs)
+
+	case "Q":
+		if n := len(ip.stack); n > 0 {
+			ip.gs = ip.stack[n-1]
+			ip.stack = ip.stack[:n-1]
+		}
+
+	case "cm":
+		if ff, ok := floats(oo, 6); ok {
+			ip.gs.ctm = matrixFor(ff).Multiply(ip.gs.ctm)
+		}
+
+	case "w":
+		if ff, ok := floats(oo, 1); ok {
+			ip.gs.lineWidth = ff[0]
+		}
+
+	case "BT":
+		ip.tm, ip.tlm = matrix.IdentMatrix, matrix.IdentMatrix
+
+	case "Tc", "Tw", "Tz", "TL", "Ts", "Tf":
+		ip.textStateOp(op, resources)
+
+	case "Td":
+		if ff, ok := floats(oo, 2); ok {
+			ip.nextLine(ff[0], ff[1])
+		}
+
+	case "TD":
+		if ff, ok := floats(oo, 2); ok {
+			ip.gs.leading = -ff[1]
+			ip.nextLine(ff[0], ff[1])
+		}
+
+	case "Tm":
+		if ff, ok := floats(oo, 6); ok {
+			ip.tlm = matrixFor(ff)
+			ip.tm = ip.tlm
+		}
+
+	case "T*", "'":
+		ip.nextLine(0, -ip.gs.leading)
+
+	case "\"":
+		if len(oo) == 3 {
+			if ff, ok := floats(oo[:2], 2); ok {
+				ip.gs.wordSpacing, ip.gs.charSpacing = ff[0], ff[1]
+			}
+		}
+		ip.nextLine(0, -ip.gs.leading)
+	}
+}
+
+// showString decodes the string operand o of a text showing operator,
+// calls f for each glyph and advances the text matrix accordingly.
+func (ip *interpreter) showString(o types.Object, f func(g placedGlyph)) {
+	ts := ip.gs.textState
+	if ts.font == nil {
+		return
+	}
+
+	bb, ok := stringBytes(o)
+	if !ok {
+		return
+	}
+
+	dec := ts.font
+
+	for _, g := range dec.Decode(bb) {
+		// Trm = [Tfs*Th 0 0 Tfs 0 Trise] x Tm x CTM
+		m := matrix.Matrix{{ts.fontSize * ts.scale, 0, 0}, {0, ts.fontSize, 0}, {0, ts.rise, 1}}
+		trm := m.Multiply(ip.tm).Multiply(ip.gs.ctm)
+
+		adv := g.Width*ts.fontSize + ts.charSpacing
+		if g.Space {
+			adv += ts.wordSpacing
+		}
+
+		pg := placedGlyph{Glyph: g, advance: adv, origin: trm.Transform(types.Point{}), trm: trm}
+
+		var tx, ty float64
+		var pp []types.Point
+
+		if dec.Vertical {
+			ty = -adv
+			pp = []types.Point{{X: -0.5, Y: -g.Width}, {X: 0.5, Y: -g.Width}, {X: 0.5, Y: 0}, {X: -0.5, Y: 0}}
+			pg.end = trm.Transform(types.Point{Y: -g.Width})
+		} else {
+			tx = adv * ts.scale
+			pp = []types.Point{{X: 0, Y: dec.Descent}, {X: g.Width, Y: dec.Descent}, {X: g.Width, Y: dec.Ascent}, {X: 0, Y: dec.Ascent}}
+			pg.end = trm.Transform(types.Point{X: g.Width})
+		}
+
+		for i := range pp {
+			pp[i] = trm.Transform(pp[i])
+		}
+		pg.bbox = bboxForPoints(pp...)
+		pg.size = distance(pg.origin, trm.Transform(types.Point{Y: 1}))
+
+		f(pg)
+
+		ip.tm = translation(tx, ty).Multiply(ip.tm)
+	}
+}
+
+// adjust applies a number element of a TJ array.
+func (ip *interpreter) adjust(o types.Object) {
+	ts := ip.gs.textState
+	ff, ok := floats([]types.Object{o}, 1)
+	if !ok {
+		return
+	}
+	adj := -ff[0] / 1000 * ts.fontSize
+	if ts.font != nil && ts.font.Vertical {
+		ip.tm = translation(0, adj).Multiply(ip.tm)
+		return
+	}
+	ip.tm = translation(adj*ts.scale, 0).Multiply(ip.tm)
+}
+
+// showArray processes the array operand of TJ.
+func (ip *interpreter) showArray(a types.Array, f func(g placedGlyph)) {
+	for _, o := range a {
+		switch o.(type) {
+		case types.Integer, types.Float:
+			ip.adjust(o)
+		default:
+			ip.showString(o, f)
+		}
+	}
+}
+
+// xObject returns the XObject fName of resources.
+func (ip *interpreter) xObject(resources types.Dict, fName string) (*types.StreamDict, error) {
+	if resources == nil {
+		return nil, nil
+	}
+
+	xObjs, err := ip.ctx.DereferenceDict(resources["XObject"])
+	if err != nil || xObjs == nil {
+		return nil, err
+	}
+
+	o, found := xObjs.Find(fName)
+	if !found {
+		return nil, nil
+	}
+
+	sd, _, err := ip.ctx.DereferenceStreamDict(o)
+	return sd, err
+}
+
+// formXObject returns the form XObject fName of resources along with its parsed content and resources.
+func (ip *interpreter) formXObject(resources types.Dict, fName string) (*types.StreamDict, []content.Operation, types.Dict, error) {
+	sd, err := ip.xObject(resources, fName)
+	if err != nil || sd == nil {
+		return nil, nil, nil, err
+	}
+
+	if st := sd.Dict.Subtype(); st == nil || *st != "Form" {
+		return nil, nil, nil, nil
+	}
+
+	if err := sd.Decode(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	ops, err := content.Parse(sd.Content)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	res, err := ip.ctx.DereferenceDict(sd.Dict["Resources"])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if res == nil {
+		res = resources
+	}
+
+	return sd, ops, res, nil
+}
+
+// enter saves the current state and applies m to the CTM.
+func (ip *interpreter) enter(m matrix.Matrix) func() {
+	gs, stack := ip.gs, ip.stack
+	tm, tlm := ip.tm, ip.tlm
+
+	ip.stack = nil
+	ip.gs.ctm = m.Multiply(ip.gs.ctm)
+
+	return func() {
+		ip.gs, ip.stack = gs, stack
+		ip.tm, ip.tlm = tm, tlm
+	}
+}
+
+// enterForm saves the current state and applies the form matrix of sd.
+func (ip *interpreter) enterForm(sd *types.StreamDict) func() {
+	m := matrix.IdentMatrix
+	if ff, ok := floats(sd.Dict.ArrayEntry("Matrix"), 6); ok {
+		m = matrixFor(ff)
+	}
+	return ip.enter(m)
+}
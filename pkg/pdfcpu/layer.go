@@ -0,0 +1,822 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"fmt"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// See 8.11 Optional Content
+
+// ErrNoLayers signals a document without optional content.
+var ErrNoLayers = errors.New("pdfcpu: no layers available")
+
+// Layer represents an optional content group.
+type Layer struct {
+	ObjNr   int    `json:"id"`
+	Name    string `json:"name"`
+	Visible bool   `json:"visible"` // in the default configuration
+	Locked  bool   `json:"locked"`
+}
+
+func (l Layer) String() string {
+	s := "off"
+	if l.Visible {
+		s = "on"
+	}
+	if l.Locked {
+		s += ", locked"
+	}
+	return fmt.Sprintf("%5d %-3s %s", l.ObjNr, s, l.Name)
+}
+
+// ocProperties returns the optional content properties dict of the catalog or nil.
+func ocProperties(ctx *model.Context) (types.Dict, error) {
+	root, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	return ctx.DereferenceDict(root["OCProperties"])
+}
+
+// ocgRefs returns the object numbers of the optional content groups referenced by the array o.
+func ocgRefs(ctx *model.Context, o types.Object) []int {
+	a, err := ctx.DereferenceArray(o)
+	if err != nil {
+		return nil
+	}
+	var ii []int
+	for _, o := range a {
+		if ir, ok := o.(types.IndirectRef); ok {
+			ii = append(ii, ir.ObjectNumber.Value())
+		}
+	}
+	return ii
+}
+
+// hiddenOCGs returns the optional content groups turned off in the default configuration.
+func hiddenOCGs(ctx *model.Context) map[int]bool {
+	hidden := map[int]bool{}
+
+	ocp, err := ocProperties(ctx)
+	if err != nil || ocp == nil {
+		return hidden
+	}
+	d, err := ctx.DereferenceDict(ocp["D"])
+	if err != nil || d == nil {
+		return hidden
+	}
+
+	if bs := d.NameEntry("BaseState"); bs != nil && *bs == "OFF" {
+		for _, i := range ocgRefs(ctx, ocp["OCGs"]) {
+			hidden[i] = true
+		}
+		for _, i := range ocgRefs(ctx, d["ON"]) {
+			delete(hidden, i)
+		}
+	}
+	for _, i := range ocgRefs(ctx, d["OFF"]) {
+		hidden[i] = true
+	}
+
+	return hidden
+}
+
+// ocVisible reports whether the optional content group or membership dict o is visible.
+func ocVisible(ctx *model.Context, hidden map[int]bool, o types.Object) bool {
+	d, err := ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return true
+	}
+
+	ocgVisible := func(o types.Object) bool {
+		ir, ok := o.(types.IndirectRef)
+		return !ok || !hidden[ir.ObjectNumber.Value()]
+	}
+
+	if t := d.Type(); t == nil || *t != "OCMD" {
+		return ocgVisible(o)
+	}
+
+	var ocgs types.Array
+	switch o1 := d["OCGs"].(type) {
+	case types.IndirectRef:
+		if a, err := ctx.DereferenceArray(o1); err == nil && a != nil {
+			ocgs = a
+		} else {
+			ocgs = types.Array{o1}
+		}
+	case types.Array:
+		ocgs = o1
+	}
+	if len(ocgs) == 0 {
+		return true
+	}
+
+	var on, off int
+	for _, o1 := range ocgs {
+		if ocgVisible(o1) {
+			on++
+		} else {
+			off++
+		}
+	}
+
+	policy := "AnyOn"
+	if p := d.NameEntry("P"); p != nil {
+		policy = *p
+	}
+	switch policy {
+	case "AllOn":
+		return off == 0
+	case "AnyOff":
+		return off > 0
+	case "AllOff":
+		return on == 0
+	}
+	return on > 0
+}
+
+// Layers returns the optional content groups of ctx.
+func Layers(ctx *model.Context) ([]Layer, error) {
+	ocp, err := ocProperties(ctx)
+	if err != nil || ocp == nil {
+		return nil, err
+	}
+
+	hidden := hiddenOCGs(ctx)
+
+	locked := map[int]bool{}
+	if d, err := ctx.DereferenceDict(ocp["D"]); err == nil && d != nil {
+		for _, i := range ocgRefs(ctx, d["Locked"]) {
+			locked[i] = true
+		}
+	}
+
+	a, err := ctx.DereferenceArray(ocp["OCGs"])
+	if err != nil {
+		return nil, err
+	}
+
+	var ll []Layer
+	for _, o := range a {
+		ir, ok := o.(types.IndirectRef)
+		if !ok {
+			continue
+		}
+		d, err := ctx.DereferenceDict(ir)
+		if err != nil || d == nil {
+			continue
+		}
+		objNr := ir.ObjectNumber.Value()
+		name, err := ctx.DereferenceText(d["Name"])
+		if err != nil {
+			return nil, err
+		}
+		ll = append(ll, Layer{ObjNr: objNr, Name: name, Visible: !hidden[objNr], Locked: locked[objNr]})
+	}
+
+	return ll, nil
+}
+
+// layer returns the optional content group named name.
+func layer(ctx *model.Context, name string) (*types.IndirectRef, types.Dict, error) {
+	ll, err := Layers(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(ll) == 0 {
+		return nil, nil, ErrNoLayers
+	}
+
+	var objNr int
+	for _, l := range ll {
+		if l.Name != name {
+			continue
+		}
+		if objNr > 0 {
+			return nil, nil, errors.Errorf("pdfcpu: ambiguous layer name: %s", name)
+		}
+		objNr = l.ObjNr
+	}
+	if objNr == 0 {
+		return nil, nil, errors.Errorf("pdfcpu: unknown layer: %s", name)
+	}
+
+	entry, _ := ctx.FindTableEntryLight(objNr)
+	ir := types.NewIndirectRef(objNr, *entry.Generation)
+
+	d, err := ctx.DereferenceDict(*ir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ir, d, nil
+}
+
+// defaultConfig returns the default optional content configuration dict of ocp.
+func defaultConfig(ctx *model.Context, ocp types.Dict) (types.Dict, error) {
+	d, err := ctx.DereferenceDict(ocp["D"])
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		d = types.NewDict()
+		ocp["D"] = d
+	}
+	return d, nil
+}
+
+// withoutRef returns o with all references to objNr removed, including those of nested arrays.
+func withoutRef(ctx *model.Context, o types.Object, objNr int) types.Object {
+	a, err := ctx.DereferenceArray(o)
+	if err != nil || a == nil {
+		return o
+	}
+	a1 := types.Array{}
+	for _, o1 := range a {
+		if ir, ok := o1.(types.IndirectRef); ok && ir.ObjectNumber.Value() == objNr {
+			continue
+		}
+		if _, ok := o1.(types.Array); ok {
+			o1 = withoutRef(ctx, o1, objNr)
+		}
+		a1 = append(a1, o1)
+	}
+	return a1
+}
+
+// removeRef removes all references to objNr from the array d[key].
+func removeRef(ctx *model.Context, d types.Dict, key string, objNr int) {
+	if _, ok := d.Find(key); ok {
+		d[key] = withoutRef(ctx, d[key], objNr)
+	}
+}
+
+// appendRef appends ir to the array d[key].
+func appendRef(ctx *model.Context, d types.Dict, key string, ir types.IndirectRef) error {
+	a, err := ctx.DereferenceArray(d[key])
+	if err != nil {
+		return err
+	}
+	d[key] = append(append(types.Array{}, a...), ir)
+	return nil
+}
+
+// pruneOCG removes the optional content group objNr from all configurations.
+func pruneOCG(ctx *model.Context, ocp types.Dict, objNr int) error {
+	removeRef(ctx, ocp, "OCGs", objNr)
+
+	configs := []types.Object{ocp["D"]}
+	if a, err := ctx.DereferenceArray(ocp["Configs"]); err == nil {
+		configs = append(configs, a...)
+	}
+
+	for _, o := range configs {
+		d, err := ctx.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+		for _, k := range []string{"ON", "OFF", "Order", "RBGroups", "Locked"} {
+			removeRef(ctx, d, k, objNr)
+		}
+		as, err := ctx.DereferenceArray(d["AS"])
+		if err != nil {
+			return err
+		}
+		for _, o := range as {
+			if d1, err := ctx.DereferenceDict(o); err == nil && d1 != nil {
+				removeRef(ctx, d1, "OCGs", objNr)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddLayer adds a new empty optional content group to ctx.
+func AddLayer(ctx *model.Context, name string, visible bool) error {
+	if _, _, err := layer(ctx, name); err == nil {
+		return errors.Errorf("pdfcpu: layer already exists: %s", name)
+	}
+
+	s, err := types.EscapedUTF16String(name)
+	if err != nil {
+		return err
+	}
+
+	ir, err := ctx.IndRefForNewObject(types.Dict(
+		map[string]types.Object{
+			"Type": types.Name("OCG"),
+			"Name": types.StringLiteral(*s),
+		},
+	))
+	if err != nil {
+		return err
+	}
+
+	ocp, err := ocProperties(ctx)
+	if err != nil {
+		return err
+	}
+	if ocp == nil {
+		root, err := ctx.Catalog()
+		if err != nil {
+			return err
+		}
+		ocp = types.Dict(
+			map[string]types.Object{
+				"OCGs": types.Array{},
+				"D":    types.Dict(map[string]types.Object{"Order": types.Array{}}),
+			},
+		)
+		root["OCProperties"] = ocp
+	}
+
+	if err := appendRef(ctx, ocp, "OCGs", *ir); err != nil {
+		return err
+	}
+
+	d, err := defaultConfig(ctx, ocp)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := d.Find("Order"); ok {
+		if err := appendRef(ctx, d, "Order", *ir); err != nil {
+			return err
+		}
+	}
+
+	return setLayerVisibility(ctx, d, *ir, visible)
+}
+
+func setLayerVisibility(ctx *model.Context, d types.Dict, ir types.IndirectRef, visible bool) error {
+	objNr := ir.ObjectNumber.Value()
+	removeRef(ctx, d, "ON", objNr)
+	removeRef(ctx, d, "OFF", objNr)
+
+	if visible {
+		return appendRef(ctx, d, "ON", ir)
+	}
+	return appendRef(ctx, d, "OFF", ir)
+}
+
+// SetLayerVisibility shows or hides the layer name in the default configuration.
+func SetLayerVisibility(ctx *model.Context, name string, visible bool) error {
+	ir, _, err := layer(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	ocp, err := ocProperties(ctx)
+	if err != nil {
+		return err
+	}
+
+	d, err := defaultConfig(ctx, ocp)
+	if err != nil {
+		return err
+	}
+
+	return setLayerVisibility(ctx, d, *ir, visible)
+}
+
+// RenameLayer renames the layer oldName to newName.
+func RenameLayer(ctx *model.Context, oldName, newName string) error {
+	_, d, err := layer(ctx, oldName)
+	if err != nil {
+		return err
+	}
+
+	if _, _, err := layer(ctx, newName); err == nil {
+		return errors.Errorf("pdfcpu: layer already exists: %s", newName)
+	}
+
+	s, err := types.EscapedUTF16String(newName)
+	if err != nil {
+		return err
+	}
+
+	d["Name"] = types.StringLiteral(*s)
+
+	return nil
+}
+
+// replaceRef replaces all references to objNr within o by ir.
+func replaceRef(o types.Object, objNr int, ir types.IndirectRef) types.Object {
+	switch o1 := o.(type) {
+
+	case types.IndirectRef:
+		if o1.ObjectNumber.Value() == objNr {
+			return ir
+		}
+
+	case types.Dict:
+		for k, v := range o1 {
+			o1[k] = replaceRef(v, objNr, ir)
+		}
+
+	case types.StreamDict:
+		replaceRef(o1.Dict, objNr, ir)
+
+	case types.Array:
+		for i, v := range o1 {
+			o1[i] = replaceRef(v, objNr, ir)
+		}
+	}
+
+	return o
+}
+
+// MergeLayers moves all content of layer from into layer to and removes layer from.
+func MergeLayers(ctx *model.Context, from, to string) error {
+	ir1, _, err := layer(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	ir2, _, err := layer(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	if ir1.ObjectNumber == ir2.ObjectNumber {
+		return errors.New("pdfcpu: cannot merge a layer into itself")
+	}
+
+	ocp, err := ocProperties(ctx)
+	if err != nil {
+		return err
+	}
+
+	objNr := ir1.ObjectNumber.Value()
+
+	if err := pruneOCG(ctx, ocp, objNr); err != nil {
+		return err
+	}
+
+	// Redirect all remaining references eg. from marked content properties, XObjects, annotations and membership dicts.
+	for i, entry := range ctx.Table {
+		if i == objNr || entry.Free || entry.Object == nil {
+			continue
+		}
+		entry.Object = replaceRef(entry.Object, objNr, *ir2)
+	}
+
+	return ctx.FreeObject(objNr)
+}
+
+type ocAction int
+
+const (
+	ocKeep   ocAction = iota // Leave optional content as is.
+	ocUnwrap                 // Make optional content unconditional.
+	ocDrop                   // Remove optional content.
+)
+
+// ocFilter rewrites optional content according to action.
+type ocFilter struct {
+	ctx    *model.Context
+	action func(o types.Object) ocAction
+	forms  map[int]bool
+}
+
+// markedContentObject returns the optional content group or membership dict of a marked content sequence.
+func (f *ocFilter) markedContentObject(o types.Object, resources types.Dict) types.Object {
+	n, ok := o.(types.Name)
+	if !ok {
+		return o
+	}
+	props, err := f.ctx.DereferenceDict(resources["Properties"])
+	if err != nil || props == nil {
+		return nil
+	}
+	o, _ = props.Find(n.Value())
+	return o
+}
+
+// xObject applies the filter to the XObject fName and reports whether invoking it shall be omitted.
+func (f *ocFilter) xObject(resources types.Dict, fName string) (bool, error) {
+	xObjs, err := f.ctx.DereferenceDict(resources["XObject"])
+	if err != nil || xObjs == nil {
+		return false, err
+	}
+
+	ir, ok := xObjs[fName].(types.IndirectRef)
+	if !ok {
+		return false, nil
+	}
+
+	objNr := ir.ObjectNumber.Value()
+	if f.forms[objNr] {
+		return false, nil
+	}
+
+	sd, _, err := f.ctx.DereferenceStreamDict(ir)
+	if err != nil || sd == nil {
+		return false, err
+	}
+
+	if o, found := sd.Dict.Find("OC"); found {
+		switch f.action(o) {
+		case ocDrop:
+			return true, nil
+		case ocUnwrap:
+			delete(sd.Dict, "OC")
+		}
+	}
+
+	if st := sd.Dict.Subtype(); st == nil || *st != "Form" {
+		return false, nil
+	}
+
+	f.forms[objNr] = true
+
+	if err := sd.Decode(); err != nil {
+		return false, err
+	}
+
+	ops, err := content.Parse(sd.Content)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := f.ctx.DereferenceDict(sd.Dict["Resources"])
+	if err != nil {
+		return false, err
+	}
+	if res == nil {
+		res = resources
+	}
+
+	ops, changed, err := f.process(ops, res)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	sd.Content = content.Format(ops)
+	if err := sd.Encode(); err != nil {
+		return false, err
+	}
+
+	entry, _ := f.ctx.FindTableEntryForIndRef(&ir)
+	entry.Object = *sd
+
+	return false, nil
+}
+
+// process returns ops with optional content dropped or unwrapped.
+func (f *ocFilter) process(ops []content.Operation, resources types.Dict) ([]content.Operation, bool, error) {
+	var (
+		res     []content.Operation
+		stack   []ocAction
+		changed bool
+	)
+
+	dropping := func() bool {
+		return len(stack) > 0 && stack[len(stack)-1] == ocDrop
+	}
+
+	for _, op := range ops {
+		switch op.Operator {
+
+		case "BMC", "BDC":
+			if dropping() {
+				stack = append(stack, ocDrop)
+				continue
+			}
+			a := ocKeep
+			if op.Operator == "BDC" && len(op.Operands) == 2 {
+				if n, ok := op.Operands[0].(types.Name); ok && n.Value() == "OC" {
+					a = f.action(f.markedContentObject(op.Operands[1], resources))
+				}
+			}
+			stack = append(stack, a)
+			if a != ocKeep {
+				changed = true
+				continue
+			}
+
+		case "EMC":
+			if len(stack) > 0 {
+				a := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if a != ocKeep {
+					continue
+				}
+			}
+
+		case "Do":
+			if dropping() {
+				continue
+			}
+			n, ok := lastOperand(op.Operands).(types.Name)
+			if !ok {
+				break
+			}
+			omit, err := f.xObject(resources, n.Value())
+			if err != nil {
+				return nil, false, err
+			}
+			if omit {
+				changed = true
+				continue
+			}
+
+		default:
+			if dropping() {
+				continue
+			}
+		}
+
+		res = append(res, op)
+	}
+
+	return res, changed, nil
+}
+
+// annotations applies the filter to the annotations of the page dict d.
+func (f *ocFilter) annotations(d types.Dict) error {
+	annots, err := f.ctx.DereferenceArray(d["Annots"])
+	if err != nil || annots == nil {
+		return err
+	}
+
+	var (
+		a       types.Array
+		changed bool
+	)
+
+	for _, o := range annots {
+		d1, err := f.ctx.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d1 != nil {
+			if o1, found := d1.Find("OC"); found {
+				switch f.action(o1) {
+				case ocDrop:
+					changed = true
+					continue
+				case ocUnwrap:
+					delete(d1, "OC")
+				}
+			}
+		}
+		a = append(a, o)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if len(a) == 0 {
+		delete(d, "Annots")
+		return nil
+	}
+
+	d["Annots"] = a
+
+	return nil
+}
+
+func (f *ocFilter) page(pageNr int) error {
+	d, _, inhPAttrs, err := f.ctx.PageDict(pageNr, false)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return errors.Errorf("pdfcpu: layers: missing page %d", pageNr)
+	}
+
+	if err := f.annotations(d); err != nil {
+		return err
+	}
+
+	bb, err := f.ctx.PageContent(d)
+	if err != nil {
+		if err == model.ErrNoContent {
+			return nil
+		}
+		return err
+	}
+
+	ops, err := content.Parse(bb)
+	if err != nil {
+		return err
+	}
+
+	ops, changed, err := f.process(ops, inhPAttrs.Resources)
+	if err != nil || !changed {
+		return err
+	}
+
+	sd, err := f.ctx.NewStreamDictForBuf(content.Format(ops))
+	if err != nil {
+		return err
+	}
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	ir, err := f.ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		return err
+	}
+
+	d["Contents"] = *ir
+
+	return nil
+}
+
+func filterOptionalContent(ctx *model.Context, action func(o types.Object) ocAction) error {
+	f := &ocFilter{ctx: ctx, action: action, forms: map[int]bool{}}
+	for i := 1; i <= ctx.PageCount; i++ {
+		if err := f.page(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveLayer removes the layer name along with all content assigned to it.
+func RemoveLayer(ctx *model.Context, name string) error {
+	ir, _, err := layer(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	objNr := ir.ObjectNumber.Value()
+
+	err = filterOptionalContent(ctx, func(o types.Object) ocAction {
+		if ir, ok := o.(types.IndirectRef); ok && ir.ObjectNumber.Value() == objNr {
+			return ocDrop
+		}
+		return ocKeep
+	})
+	if err != nil {
+		return err
+	}
+
+	ocp, err := ocProperties(ctx)
+	if err != nil {
+		return err
+	}
+
+	return pruneOCG(ctx, ocp, objNr)
+}
+
+// FlattenLayers drops all content hidden in the default configuration and makes all visible content unconditional.
+func FlattenLayers(ctx *model.Context) error {
+	ocp, err := ocProperties(ctx)
+	if err != nil {
+		return err
+	}
+	if ocp == nil {
+		return ErrNoLayers
+	}
+
+	hidden := hiddenOCGs(ctx)
+
+	err = filterOptionalContent(ctx, func(o types.Object) ocAction {
+		if ocVisible(ctx, hidden, o) {
+			return ocUnwrap
+		}
+		return ocDrop
+	})
+	if err != nil {
+		return err
+	}
+
+	root, err := ctx.Catalog()
+	if err != nil {
+		return err
+	}
+
+	delete(root, "OCProperties")
+
+	return nil
+}
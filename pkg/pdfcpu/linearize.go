@@ -0,0 +1,909 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Linearized files are laid out as described in ISO 32000-1 Annex F:
+//
+//	header
+//	linearization parameter dict
+//	first-page cross-reference section and trailer
+//	catalog and document-level objects
+//	primary hint stream
+//	first-page section
+//	remaining pages
+//	shared objects
+//	other objects
+//	main cross-reference section and trailer
+//
+// Objects get renumbered so that the first-page cross-reference section
+// covers the linearization dict up to the last object of the first page.
+// Cross-reference streams and object streams are not used.
+
+// Catalog entries needed for opening the document.
+var openDocumentEntries = []string{"ViewerPreferences", "PageMode", "Threads", "OpenAction", "AcroForm"}
+
+// Inheritable page attributes, see 7.7.3.4 Inheritance of Page Attributes.
+var inheritablePageAttrs = []string{"Resources", "MediaBox", "CropBox", "Rotate"}
+
+type linearization struct {
+	ctx      *model.Context
+	pages    []int        // page object numbers in page order
+	nodes    types.IntSet // page tree nodes and page objects
+	catalog  int
+	docObjs  []int   // catalog and document-level objects
+	pageObjs [][]int // first page section followed by the private objects of each remaining page
+	shared   []int   // objects shared by pages other than the first one
+	other    []int   // any remaining objects
+	users    map[int][]int
+	objNr    map[int]int // original obj# -> obj# written
+	linNr    int         // obj# of the linearization parameter dict
+	hintNr   int         // obj# of the primary hint stream
+	size     int
+}
+
+// writeLinearized writes ctx as linearized PDF file of version v.
+func writeLinearized(ctx *model.Context, v model.Version) error {
+	if log.WriteEnabled() {
+		log.Write.Println("writeLinearized begin")
+	}
+
+	ctx.WriteObjectStream = false
+	ctx.WriteXRefStream = false
+
+	if !ctx.ApplyReducedFeatureSet() {
+		if err := ctx.BindNameTrees(); err != nil {
+			return err
+		}
+	}
+
+	l := &linearization{ctx: ctx, nodes: types.IntSet{}, users: map[int][]int{}, objNr: map[int]int{}}
+
+	if err := l.collect(); err != nil {
+		return err
+	}
+
+	l.renumber()
+
+	if err := l.write(v); err != nil {
+		return err
+	}
+
+	if log.WriteEnabled() {
+		log.Write.Println("writeLinearized end")
+	}
+
+	return nil
+}
+
+func (l *linearization) object(objNr int) (types.Object, error) {
+	entry, ok := l.ctx.FindTableEntryLight(objNr)
+	if !ok || entry.Free {
+		return nil, nil
+	}
+	// Resolve objects lazily loaded from object streams.
+	return l.ctx.Dereference(*types.NewIndirectRef(objNr, *entry.Generation))
+}
+
+// collectPages walks the page tree and pushes inherited attributes down to the pages.
+func (l *linearization) collectPages(ir types.IndirectRef, inherited types.Dict) error {
+	objNr := ir.ObjectNumber.Value()
+	if l.nodes[objNr] {
+		return errors.Errorf("pdfcpu: linearize: page tree cycle at obj#%d", objNr)
+	}
+	l.nodes[objNr] = true
+
+	d, err := l.ctx.DereferenceDict(ir)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return errors.Errorf("pdfcpu: linearize: missing page tree node obj#%d", objNr)
+	}
+
+	if d.Type() != nil && *d.Type() == "Page" {
+		for k, v := range inherited {
+			if _, found := d.Find(k); !found {
+				d.Insert(k, v.Clone())
+			}
+		}
+		l.pages = append(l.pages, objNr)
+		return nil
+	}
+
+	attrs := types.NewDict()
+	for k, v := range inherited {
+		attrs[k] = v
+	}
+	for _, k := range inheritablePageAttrs {
+		if o, found := d.Find(k); found && o != nil {
+			attrs[k] = o
+		}
+	}
+
+	for _, o := range d.ArrayEntry("Kids") {
+		kid, ok := o.(types.IndirectRef)
+		if !ok {
+			return errors.Errorf("pdfcpu: linearize: corrupt page tree node obj#%d", objNr)
+		}
+		if err := l.collectPages(kid, attrs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reachable appends the object numbers reachable from o to objNrs in depth first order.
+func (l *linearization) reachable(o types.Object, stop func(int) bool, seen types.IntSet, objNrs []int) ([]int, error) {
+	var err error
+
+	switch o := o.(type) {
+
+	case types.IndirectRef:
+		objNr := o.ObjectNumber.Value()
+		if seen[objNr] || stop(objNr) {
+			return objNrs, nil
+		}
+		obj, err := l.object(objNr)
+		if err != nil || obj == nil {
+			return objNrs, err
+		}
+		seen[objNr] = true
+		return l.reachable(obj, stop, seen, append(objNrs, objNr))
+
+	case types.Dict:
+		keys := make([]string, 0, len(o))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if objNrs, err = l.reachable(o[k], stop, seen, objNrs); err != nil {
+				return nil, err
+			}
+		}
+
+	case types.StreamDict:
+		return l.reachable(o.Dict, stop, seen, objNrs)
+
+	case types.Array:
+		for _, v := range o {
+			if objNrs, err = l.reachable(v, stop, seen, objNrs); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return objNrs, nil
+}
+
+// collect assigns all objects to be written to the parts of a linearized file.
+func (l *linearization) collect() error {
+	ctx := l.ctx
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return err
+	}
+
+	ir := rootDict.IndirectRefEntry("Pages")
+	if ir == nil {
+		return errors.New("pdfcpu: linearize: missing page tree")
+	}
+
+	if err := l.collectPages(*ir, types.NewDict()); err != nil {
+		return err
+	}
+
+	if len(l.pages) == 0 {
+		return errors.New("pdfcpu: linearize: no pages")
+	}
+
+	l.catalog = ctx.Root.ObjectNumber.Value()
+
+	// Part 4: Catalog and document-level objects.
+	assigned := types.IntSet{l.catalog: true}
+	stop := func(objNr int) bool { return l.nodes[objNr] || assigned[objNr] }
+
+	l.docObjs = []int{l.catalog}
+	for _, k := range openDocumentEntries {
+		if l.docObjs, err = l.reachable(rootDict[k], stop, assigned, l.docObjs); err != nil {
+			return err
+		}
+	}
+	if ctx.Encrypt != nil && ctx.EncKey != nil {
+		if l.docObjs, err = l.reachable(*ctx.Encrypt, stop, assigned, l.docObjs); err != nil {
+			return err
+		}
+	}
+
+	// Parts 6 and 7: Objects used by pages.
+	l.pageObjs = make([][]int, len(l.pages))
+	for i, pageNr := range l.pages {
+		pageNr := pageNr
+		pageStop := func(objNr int) bool { return objNr != pageNr && stop(objNr) }
+		if l.pageObjs[i], err = l.reachable(*types.NewIndirectRef(pageNr, 0), pageStop, types.IntSet{}, nil); err != nil {
+			return err
+		}
+		for _, objNr := range l.pageObjs[i] {
+			l.users[objNr] = append(l.users[objNr], i)
+		}
+	}
+
+	for _, objNr := range l.pageObjs[0] {
+		assigned[objNr] = true
+	}
+
+	// Part 8: Objects shared by the remaining pages.
+	for i := 1; i < len(l.pages); i++ {
+		private := []int{}
+		for _, objNr := range l.pageObjs[i] {
+			if assigned[objNr] {
+				continue
+			}
+			if len(l.users[objNr]) > 1 {
+				l.shared = append(l.shared, objNr)
+				assigned[objNr] = true
+				continue
+			}
+			private = append(private, objNr)
+		}
+		l.pageObjs[i] = private
+	}
+
+	for i := 1; i < len(l.pages); i++ {
+		for _, objNr := range l.pageObjs[i] {
+			assigned[objNr] = true
+		}
+	}
+
+	// Part 9: Anything else.
+	none := func(int) bool { return false }
+	if l.other, err = l.reachable(rootDict, none, assigned, nil); err != nil {
+		return err
+	}
+	if ctx.Info != nil {
+		if l.other, err = l.reachable(*ctx.Info, none, assigned, l.other); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renumber assigns object numbers in the order objects get written.
+// The main cross-reference section covers the remaining pages, shared and other objects,
+// the first-page cross-reference section everything up to the end of the first page.
+func (l *linearization) renumber() {
+	objNr := 1
+	assign := func(objNrs []int) {
+		for _, i := range objNrs {
+			l.objNr[i] = objNr
+			objNr++
+		}
+	}
+
+	for _, objNrs := range l.pageObjs[1:] {
+		assign(objNrs)
+	}
+	assign(l.shared)
+	assign(l.other)
+
+	l.linNr = objNr
+	objNr++
+	assign(l.docObjs)
+	l.hintNr = objNr
+	objNr++
+	assign(l.pageObjs[0])
+
+	l.size = objNr
+}
+
+// renumberRefs replaces all indirect references of o according to objNr.
+func renumberRefs(o types.Object, objNr map[int]int) types.Object {
+	switch o := o.(type) {
+
+	case types.IndirectRef:
+		i, ok := objNr[o.ObjectNumber.Value()]
+		if !ok {
+			return nil
+		}
+		return *types.NewIndirectRef(i, 0)
+
+	case types.Dict:
+		for k, v := range o {
+			o[k] = renumberRefs(v, objNr)
+		}
+
+	case types.StreamDict:
+		renumberRefs(o.Dict, objNr)
+
+	case types.Array:
+		for i, v := range o {
+			o[i] = renumberRefs(v, objNr)
+		}
+	}
+
+	return o
+}
+
+func (l *linearization) writeObject(objNr int) error {
+	ctx := l.ctx
+	o, err := l.object(objNr)
+	if err != nil {
+		return err
+	}
+	if o != nil {
+		o = renumberRefs(o.Clone(), l.objNr)
+	}
+	objNr = l.objNr[objNr]
+
+	if ctx.Encrypt != nil && l.objNr[ctx.Encrypt.ObjectNumber.Value()] == objNr {
+		// The encryption dict never gets encrypted.
+		return writeObject(ctx, objNr, 0, o.PDFString())
+	}
+
+	switch o := o.(type) {
+
+	case nil:
+		err = writePDFNullObject(ctx, objNr, 0)
+
+	case types.Dict:
+		err = writeDictObject(ctx, objNr, 0, o)
+
+	case types.StreamDict:
+		// Stream lengths are always written as direct objects.
+		o.Update("Length", types.Integer(*o.StreamLength))
+		if ctx.EncKey != nil {
+			if _, err = encryptDeepObject(o, objNr, 0, ctx.EncKey, ctx.AES4Strings, ctx.E.R); err != nil {
+				return err
+			}
+		}
+		err = writeStreamDictObject(ctx, objNr, 0, o)
+
+	case types.Array:
+		err = writeArrayObject(ctx, objNr, 0, o)
+
+	case types.Integer:
+		err = writeIntegerObject(ctx, objNr, 0, o)
+
+	case types.Float:
+		err = writeFloatObject(ctx, objNr, 0, o)
+
+	case types.StringLiteral:
+		err = writeStringLiteralObject(ctx, objNr, 0, o)
+
+	case types.HexLiteral:
+		err = writeHexLiteralObject(ctx, objNr, 0, o)
+
+	case types.Boolean:
+		err = writeBooleanObject(ctx, objNr, 0, o)
+
+	case types.Name:
+		err = writeNameObject(ctx, objNr, 0, o)
+
+	default:
+		err = errors.Errorf("pdfcpu: linearize: unexpected PDF object #%d %T\n", objNr, o)
+	}
+
+	return err
+}
+
+// serialize renders fn into a buffer and returns the result.
+// ctx.Write.Offset is relative to the start of this buffer while fn runs.
+func (l *linearization) serialize(fn func() error) ([]byte, error) {
+	w := l.ctx.Write
+	writer, offset := w.Writer, w.Offset
+	defer func() {
+		w.Writer, w.Offset = writer, offset
+	}()
+
+	var buf bytes.Buffer
+	w.Writer = bufio.NewWriter(&buf)
+	w.Offset = 0
+
+	if err := fn(); err != nil {
+		return nil, err
+	}
+
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// bitWriter writes big endian bit fields.
+type bitWriter struct {
+	bb  []byte
+	pos int // in bits
+}
+
+func (bw *bitWriter) write(v int, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if bw.pos%8 == 0 {
+			bw.bb = append(bw.bb, 0)
+		}
+		if v>>i&1 == 1 {
+			bw.bb[len(bw.bb)-1] |= 1 << (7 - bw.pos%8)
+		}
+		bw.pos++
+	}
+}
+
+// align skips to the next byte boundary.
+func (bw *bitWriter) align() {
+	bw.pos = len(bw.bb) * 8
+}
+
+// bitsNeeded returns the number of bits needed to represent i.
+func bitsNeeded(i int) int {
+	return bits.Len(uint(i))
+}
+
+func minMax(ii []int) (int, int) {
+	min, max := ii[0], ii[0]
+	for _, i := range ii[1:] {
+		if i < min {
+			min = i
+		}
+		if i > max {
+			max = i
+		}
+	}
+	return min, max
+}
+
+// contentObjs returns the object numbers of the content streams of a page
+// including an indirect array of content streams.
+func (l *linearization) contentObjs(pageObjNr int) ([]int, error) {
+	o, err := l.object(pageObjNr)
+	if err != nil {
+		return nil, err
+	}
+	d, ok := o.(types.Dict)
+	if !ok {
+		return nil, nil
+	}
+
+	var objNrs []int
+
+	o = d["Contents"]
+	if ir, ok := o.(types.IndirectRef); ok {
+		objNr := ir.ObjectNumber.Value()
+		if o, err = l.object(objNr); err != nil {
+			return nil, err
+		}
+		objNrs = append(objNrs, objNr)
+	}
+
+	if a, ok := o.(types.Array); ok {
+		for _, v := range a {
+			if ir, ok := v.(types.IndirectRef); ok {
+				objNrs = append(objNrs, ir.ObjectNumber.Value())
+			}
+		}
+	}
+
+	return objNrs, nil
+}
+
+// contentSpan returns the offset relative to the start of page i and the length
+// of the byte range covering the content streams written with page i.
+func (l *linearization) contentSpan(i int, offset map[int]int64, length map[int]int64) (int, int, error) {
+	objNrs, err := l.contentObjs(l.pages[i])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	own := types.IntSet{}
+	for _, objNr := range l.pageObjs[i] {
+		own[objNr] = true
+	}
+
+	lo, hi := int64(-1), int64(-1)
+	for _, objNr := range objNrs {
+		if !own[objNr] {
+			// Content streams shared with other pages are covered by the shared object hint table.
+			continue
+		}
+		if lo < 0 || offset[objNr] < lo {
+			lo = offset[objNr]
+		}
+		if end := offset[objNr] + length[objNr]; end > hi {
+			hi = end
+		}
+	}
+
+	if lo < 0 {
+		return 0, 0, nil
+	}
+
+	return int(lo - offset[l.pageObjs[i][0]]), int(hi - lo), nil
+}
+
+// hintStream returns the primary hint stream made up of the page offset hint table
+// and the shared object hint table, see F.4 Hint Tables.
+// Offsets are computed as if the hint stream was not present.
+//
+// There is no need for an overflow hint stream: the primary hint stream holds all hint tables
+// and its final size is known before the first page section gets laid out.
+func (l *linearization) hintStream(offset map[int]int64, length map[int]int64) (*types.StreamDict, error) {
+	n := len(l.pages)
+
+	sharedID := map[int]int{}
+	for i, objNr := range l.pageObjs[0] {
+		sharedID[objNr] = i
+	}
+	for i, objNr := range l.shared {
+		sharedID[objNr] = len(l.pageObjs[0]) + i
+	}
+
+	nObjs := make([]int, n)
+	pageLen := make([]int, n)
+	contentOff := make([]int, n)
+	contentLen := make([]int, n)
+	refs := make([][]int, n)
+	nRefs := make([]int, n)
+
+	for i, objNrs := range l.pageObjs {
+		nObjs[i] = len(objNrs)
+		for _, objNr := range objNrs {
+			pageLen[i] += int(length[objNr])
+		}
+		off, size, err := l.contentSpan(i, offset, length)
+		if err != nil {
+			return nil, err
+		}
+		contentOff[i], contentLen[i] = off, size
+	}
+
+	// Shared objects referenced by each page.
+	for objNr, users := range l.users {
+		id, ok := sharedID[objNr]
+		if !ok || len(users) < 2 {
+			continue
+		}
+		for _, i := range users {
+			refs[i] = append(refs[i], id)
+		}
+	}
+
+	maxID := 0
+	for i := range refs {
+		sort.Ints(refs[i])
+		nRefs[i] = len(refs[i])
+		for _, id := range refs[i] {
+			if id > maxID {
+				maxID = id
+			}
+		}
+	}
+
+	minObjs, maxObjs := minMax(nObjs)
+	minLen, maxLen := minMax(pageLen)
+	minContentOff, maxContentOff := minMax(contentOff)
+	minContentLen, maxContentLen := minMax(contentLen)
+	_, maxRefs := minMax(nRefs)
+
+	bw := &bitWriter{}
+
+	// Page offset hint table header
+	bw.write(minObjs, 32)
+	bw.write(int(offset[l.pages[0]]), 32)
+	bw.write(bitsNeeded(maxObjs-minObjs), 16)
+	bw.write(minLen, 32)
+	bw.write(bitsNeeded(maxLen-minLen), 16)
+	bw.write(minContentOff, 32)
+	bw.write(bitsNeeded(maxContentOff-minContentOff), 16)
+	bw.write(minContentLen, 32)
+	bw.write(bitsNeeded(maxContentLen-minContentLen), 16)
+	bw.write(bitsNeeded(maxRefs), 16)
+	bw.write(bitsNeeded(maxID), 16)
+	bw.write(0, 16) // numerator bits for the fractional position of shared objects
+	bw.write(1, 16) // denominator
+
+	// Page offset hint table entries: each item for all pages starting at a byte boundary.
+	for _, v := range nObjs {
+		bw.write(v-minObjs, bitsNeeded(maxObjs-minObjs))
+	}
+	bw.align()
+
+	for _, v := range pageLen {
+		bw.write(v-minLen, bitsNeeded(maxLen-minLen))
+	}
+	bw.align()
+
+	for _, v := range nRefs {
+		bw.write(v, bitsNeeded(maxRefs))
+	}
+	bw.align()
+
+	for _, ids := range refs {
+		for _, id := range ids {
+			bw.write(id, bitsNeeded(maxID))
+		}
+	}
+	bw.align()
+
+	// Fractional positions of shared object references take 0 bits.
+
+	for _, v := range contentOff {
+		bw.write(v-minContentOff, bitsNeeded(maxContentOff-minContentOff))
+	}
+	bw.align()
+
+	for _, v := range contentLen {
+		bw.write(v-minContentLen, bitsNeeded(maxContentLen-minContentLen))
+	}
+	bw.align()
+
+	sharedOffset := len(bw.bb)
+
+	// Shared object hint table
+	groups := append(append([]int{}, l.pageObjs[0]...), l.shared...)
+	groupLen := make([]int, len(groups))
+	for i, objNr := range groups {
+		groupLen[i] = int(length[objNr])
+	}
+	minGroupLen, maxGroupLen := minMax(groupLen)
+
+	firstShared, firstSharedOffset := 0, 0
+	if len(l.shared) > 0 {
+		firstShared = l.objNr[l.shared[0]]
+		firstSharedOffset = int(offset[l.shared[0]])
+	}
+
+	bw.write(firstShared, 32)
+	bw.write(firstSharedOffset, 32)
+	bw.write(len(l.pageObjs[0]), 32)
+	bw.write(len(groups), 32)
+	bw.write(0, 16) // each group consists of a single object
+	bw.write(minGroupLen, 32)
+	bw.write(bitsNeeded(maxGroupLen-minGroupLen), 16)
+
+	for _, v := range groupLen {
+		bw.write(v-minGroupLen, bitsNeeded(maxGroupLen-minGroupLen))
+	}
+	bw.align()
+
+	// No MD5 signatures.
+	for range groups {
+		bw.write(0, 1)
+	}
+	bw.align()
+
+	sd, err := l.ctx.NewStreamDictForBuf(bw.bb)
+	if err != nil {
+		return nil, err
+	}
+	sd.InsertInt("S", sharedOffset)
+
+	if err := sd.Encode(); err != nil {
+		return nil, err
+	}
+
+	return sd, nil
+}
+
+func xRefEntry(offset int64, eol string) string {
+	return fmt.Sprintf("%010d %05d n%2s", offset, 0, eol)
+}
+
+// firstPageXRefSection returns the first-page cross-reference section and trailer.
+func (l *linearization) firstPageXRefSection(offsets []int64, prev int64) []byte {
+	ctx := l.ctx
+	eol := ctx.Write.Eol
+
+	var buf bytes.Buffer
+	buf.WriteString("xref" + eol)
+	buf.WriteString(fmt.Sprintf("%d %d%s", l.linNr, len(offsets), eol))
+	for _, off := range offsets {
+		buf.WriteString(xRefEntry(off, eol))
+	}
+
+	d := types.NewDict()
+	d.Insert("Size", types.Integer(l.size))
+	d.Insert("Root", *types.NewIndirectRef(l.objNr[l.catalog], 0))
+	if ctx.Info != nil {
+		if objNr, ok := l.objNr[ctx.Info.ObjectNumber.Value()]; ok {
+			d.Insert("Info", *types.NewIndirectRef(objNr, 0))
+		}
+	}
+	if ctx.Encrypt != nil && ctx.EncKey != nil {
+		d.Insert("Encrypt", *types.NewIndirectRef(l.objNr[ctx.Encrypt.ObjectNumber.Value()], 0))
+	}
+	if ctx.ID != nil {
+		d.Insert("ID", ctx.ID)
+	}
+	d.Insert("Prev", types.Integer(prev))
+
+	buf.WriteString("trailer" + eol)
+	buf.WriteString(d.PDFString() + eol)
+	buf.WriteString("startxref" + eol + "0" + eol)
+	buf.WriteString("%%EOF" + eol)
+
+	return buf.Bytes()
+}
+
+// mainXRefSection returns the main cross-reference section and trailer.
+func (l *linearization) mainXRefSection(offsets []int64, startXRef int64) []byte {
+	eol := l.ctx.Write.Eol
+
+	var buf bytes.Buffer
+	buf.WriteString("xref" + eol)
+	buf.WriteString(fmt.Sprintf("0 %d%s", len(offsets)+1, eol))
+	buf.WriteString(fmt.Sprintf("%010d %05d f%2s", 0, 65535, eol))
+	for _, off := range offsets {
+		buf.WriteString(xRefEntry(off, eol))
+	}
+
+	buf.WriteString("trailer" + eol)
+	buf.WriteString(fmt.Sprintf("<</Size %d>>%s", len(offsets)+1, eol))
+	buf.WriteString(fmt.Sprintf("startxref%s%d%s", eol, startXRef, eol))
+	buf.WriteString("%%EOF" + eol)
+
+	return buf.Bytes()
+}
+
+func (l *linearization) write(v model.Version) error {
+	ctx := l.ctx
+	w := ctx.Write
+
+	header, err := l.serialize(func() error { return writeHeader(w, v) })
+	if err != nil {
+		return err
+	}
+
+	var firstSection, mainSection []int
+	firstSection = append(firstSection, l.docObjs...)
+	firstSection = append(firstSection, l.pageObjs[0]...)
+	for _, objNrs := range l.pageObjs[1:] {
+		mainSection = append(mainSection, objNrs...)
+	}
+	mainSection = append(mainSection, l.shared...)
+	mainSection = append(mainSection, l.other...)
+
+	// Offsets relative to the start of the catalog.
+	offset, length := map[int]int64{}, map[int]int64{}
+
+	body, err := l.serialize(func() error {
+		for _, objNr := range append(firstSection, mainSection...) {
+			offset[objNr] = w.Offset
+			if err := l.writeObject(objNr); err != nil {
+				return err
+			}
+			length[objNr] = w.Offset - offset[objNr]
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	docLen := offset[l.pages[0]]
+	firstPageEnd := int64(len(body))
+	if len(mainSection) > 0 {
+		firstPageEnd = offset[mainSection[0]]
+	}
+
+	var lin, firstXRef, hint, mainXRef []byte
+	var adjusted map[int]int64
+	var hintOffset int64
+
+	// The sizes of the linearization dict and the first-page cross-reference section
+	// depend on the offsets they refer to.
+	linLen, firstXRefLen := -1, -1
+	for i := 0; len(lin) != linLen || len(firstXRef) != firstXRefLen; i++ {
+		if i == 10 {
+			return errors.New("pdfcpu: linearize: unable to layout file")
+		}
+		linLen, firstXRefLen = len(lin), len(firstXRef)
+
+		base := int64(len(header) + linLen + firstXRefLen)
+
+		// Hint tables ignore the hint stream.
+		adjusted = map[int]int64{}
+		for objNr, off := range offset {
+			adjusted[objNr] = base + off
+		}
+
+		sd, err := l.hintStream(adjusted, length)
+		if err != nil {
+			return err
+		}
+
+		if hint, err = l.serialize(func() error { return writeStreamDictObject(ctx, l.hintNr, 0, *sd) }); err != nil {
+			return err
+		}
+
+		hintOffset = base + docLen
+		hintLen := int64(len(hint))
+
+		fileOffset := func(objNr int) int64 {
+			if off := adjusted[objNr]; off < hintOffset {
+				return off
+			}
+			return adjusted[objNr] + hintLen
+		}
+
+		offsets := []int64{int64(len(header))}
+		for _, objNr := range l.docObjs {
+			offsets = append(offsets, fileOffset(objNr))
+		}
+		offsets = append(offsets, hintOffset)
+		for _, objNr := range l.pageObjs[0] {
+			offsets = append(offsets, fileOffset(objNr))
+		}
+
+		mainOffsets := []int64{}
+		for _, objNr := range mainSection {
+			mainOffsets = append(mainOffsets, fileOffset(objNr))
+		}
+
+		mainXRefOffset := base + hintLen + int64(len(body))
+		mainXRef = l.mainXRefSection(mainOffsets, int64(len(header)+linLen))
+
+		firstXRef = l.firstPageXRefSection(offsets, mainXRefOffset)
+
+		s := fmt.Sprintf("<</Linearized 1/L %d/H[%d %d]/O %d/E %d/N %d/T %d>>",
+			mainXRefOffset+int64(len(mainXRef)),
+			hintOffset, hintLen,
+			l.objNr[l.pages[0]],
+			base+hintLen+firstPageEnd,
+			len(l.pages),
+			// The last white-space character preceding the first entry of the main cross-reference section.
+			mainXRefOffset+int64(len("xref"+w.Eol)+len(fmt.Sprintf("0 %d", len(mainOffsets)+1))+len(w.Eol)-1),
+		)
+
+		if lin, err = l.serialize(func() error { return writeObject(ctx, l.linNr, 0, s) }); err != nil {
+			return err
+		}
+
+	}
+
+	// Record the final offsets for the original objects.
+	w.Table = map[int]int64{}
+	for objNr, off := range adjusted {
+		if off >= hintOffset {
+			off += int64(len(hint))
+		}
+		w.Table[objNr] = off
+	}
+
+	for _, bb := range [][]byte{header, lin, firstXRef, body[:docLen], hint, body[docLen:], mainXRef} {
+		if _, err := w.Write(bb); err != nil {
+			return err
+		}
+		w.Offset += int64(len(bb))
+	}
+
+	return nil
+}
@@ -932,6 +932,11 @@ func mergeDuplicateObjNumberIntSets(ctxSrc, ctxDest *model.Context) {
 // dividerPage ... insert blank page between merged files (not applicable for zipping)
 func MergeXRefTables(fName string, ctxSrc, ctxDest *model.Context, zip, dividerPage bool) (err error) {
 
+	pls, err := mergedPageLabels(ctxSrc, ctxDest, zip, dividerPage)
+	if err != nil {
+		return err
+	}
+
 	patchSourceObjectNumbers(ctxSrc, ctxDest)
 
 	appendSourceObjectsToDest(ctxSrc, ctxDest)
@@ -963,6 +968,12 @@ func MergeXRefTables(fName string, ctxSrc, ctxDest *model.Context, zip, dividerP
 		return err
 	}
 
+	if pls != nil {
+		if err = writePageLabels(ctxDest, pls); err != nil {
+			return err
+		}
+	}
+
 	if !zip && ctxDest.Configuration.CreateBookmarks {
 		if err = mergeOutlines(fName, origDestPageCount+1, ctxSrc, ctxDest); err != nil {
 			return err
@@ -233,6 +233,7 @@ type AnnotationRenderer interface {
 	RenderDict(xRefTable *XRefTable, pageIndRef *types.IndirectRef) (types.Dict, error)
 	Type() AnnotationType
 	RectString() string
+	Rectangle() types.Rectangle
 	ID() string
 	ContentString() string
 }
@@ -310,6 +311,11 @@ func (ann Annotation) RectString() string {
 	return ann.Rect.ShortString()
 }
 
+// Rectangle returns ann's positioning rectangle.
+func (ann Annotation) Rectangle() types.Rectangle {
+	return ann.Rect
+}
+
 // Type returns ann's type.
 func (ann Annotation) Type() AnnotationType {
 	return ann.SubType
@@ -1672,3 +1678,89 @@ func (ann InkAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types.Indi
 
 	return d, nil
 }
+
+// RedactAnnotation represents a PDF redaction annotation marking content to be removed.
+type RedactAnnotation struct {
+	MarkupAnnotation
+	Quad        types.QuadPoints   // The regions to be removed. If missing, Rect is used.
+	FillCol     *color.SimpleColor // The interior color used to fill the redacted region after the affected content has been removed.
+	OverlayText string             // Text to be drawn over the redacted region after the affected content has been removed.
+	Repeat      bool               // OverlayText shall be repeated to fill the redacted region.
+	DA          string             // The appearance string to be used in formatting the overlay text.
+	Q           int                // Quadding: 0 = left justified, 1 = centered, 2 = right justified.
+}
+
+// NewRedactAnnotation returns a new redaction annotation.
+func NewRedactAnnotation(
+	rect types.Rectangle,
+	contents, id string,
+	modDate string,
+	f AnnotationFlags,
+	col *color.SimpleColor,
+	title string,
+	popupIndRef *types.IndirectRef,
+	ca *float64,
+	rc, subject string,
+
+	quad types.QuadPoints,
+	fillCol *color.SimpleColor,
+	overlayText string,
+	repeat bool,
+	da string,
+	q int) RedactAnnotation {
+
+	ma := NewMarkupAnnotation(AnnRedact, rect, contents, id, modDate, f, col, 0, 0, 0, title, popupIndRef, ca, rc, subject)
+
+	if q < 0 || q > 2 {
+		q = 0
+	}
+
+	return RedactAnnotation{
+		MarkupAnnotation: ma,
+		Quad:             quad,
+		FillCol:          fillCol,
+		OverlayText:      overlayText,
+		Repeat:           repeat,
+		DA:               da,
+		Q:                q,
+	}
+}
+
+// RenderDict renders ann into a page annotation dict.
+func (ann RedactAnnotation) RenderDict(xRefTable *XRefTable, pageIndRef *types.IndirectRef) (types.Dict, error) {
+	d, err := ann.MarkupAnnotation.RenderDict(xRefTable, pageIndRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if ann.Quad != nil {
+		d.Insert("QuadPoints", ann.Quad.Array())
+	}
+
+	if ann.FillCol != nil {
+		d["IC"] = ann.FillCol.Array()
+	}
+
+	if ann.OverlayText != "" {
+		s, err := types.EscapedUTF16String(ann.OverlayText)
+		if err != nil {
+			return nil, err
+		}
+		d.InsertString("OverlayText", *s)
+		if ann.Repeat {
+			d["Repeat"] = types.Boolean(true)
+		}
+	}
+
+	da := ann.DA
+	if da == "" {
+		da = "/Helv 0 Tf 0 g"
+	}
+	d.InsertString("DA", da)
+
+	if ann.Q > 0 {
+		d["Q"] = types.Integer(ann.Q)
+	}
+
+	return d, nil
+}
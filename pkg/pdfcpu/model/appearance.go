@@ -0,0 +1,275 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// AnnotationFlagsFor returns the flags of the annotation dict d.
+func AnnotationFlagsFor(d types.Dict) AnnotationFlags {
+	if i := d.IntEntry("F"); i != nil {
+		return AnnotationFlags(*i)
+	}
+	return 0
+}
+
+// NormalAppearance returns the normal appearance stream of the annotation d for its current appearance state.
+// If d has no normal appearance, nil is returned.
+func (xRefTable *XRefTable) NormalAppearance(d types.Dict) (*types.IndirectRef, *types.StreamDict, error) {
+	ap, err := xRefTable.DereferenceDict(d["AP"])
+	if err != nil || ap == nil {
+		return nil, nil, err
+	}
+
+	o, found := ap.Find("N")
+	if !found {
+		return nil, nil, nil
+	}
+
+	o1, err := xRefTable.Dereference(o)
+	if err != nil || o1 == nil {
+		return nil, nil, err
+	}
+
+	if states, ok := o1.(types.Dict); ok {
+		as := d.NameEntry("AS")
+		if as == nil {
+			return nil, nil, nil
+		}
+		if o, found = states.Find(*as); !found {
+			return nil, nil, nil
+		}
+	}
+
+	sd, _, err := xRefTable.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return nil, nil, err
+	}
+
+	if indRef, ok := o.(types.IndirectRef); ok {
+		return &indRef, sd, nil
+	}
+
+	indRef, err := xRefTable.IndRefForNewObject(*sd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return indRef, sd, nil
+}
+
+func (xRefTable *XRefTable) formMatrix(sd *types.StreamDict) (matrix.Matrix, error) {
+	m := matrix.IdentMatrix
+	a := sd.Dict.ArrayEntry("Matrix")
+	if len(a) != 6 {
+		return m, nil
+	}
+	var ff [6]float64
+	for i, o := range a {
+		f, err := xRefTable.DereferenceNumber(o)
+		if err != nil {
+			return m, err
+		}
+		ff[i] = f
+	}
+	return matrix.Matrix{{ff[0], ff[1], 0}, {ff[2], ff[3], 0}, {ff[4], ff[5], 1}}, nil
+}
+
+// AppearanceMatrix returns the transformation mapping the appearance stream sd into the annotation rectangle r.
+// See 12.5.5 Appearance streams
+func (xRefTable *XRefTable) AppearanceMatrix(sd *types.StreamDict, r *types.Rectangle) (*matrix.Matrix, error) {
+	a := sd.Dict.ArrayEntry("BBox")
+	if len(a) != 4 {
+		return nil, nil
+	}
+	bb, err := xRefTable.RectForArray(a)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := xRefTable.formMatrix(sd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bounding box of the transformed BBox.
+	var llx, lly, urx, ury float64
+	for i, p := range []types.Point{bb.LL, bb.UR, {X: bb.LL.X, Y: bb.UR.Y}, {X: bb.UR.X, Y: bb.LL.Y}} {
+		p = m.Transform(p)
+		if i == 0 || p.X < llx {
+			llx = p.X
+		}
+		if i == 0 || p.Y < lly {
+			lly = p.Y
+		}
+		if i == 0 || p.X > urx {
+			urx = p.X
+		}
+		if i == 0 || p.Y > ury {
+			ury = p.Y
+		}
+	}
+
+	if urx-llx == 0 || ury-lly == 0 {
+		return nil, nil
+	}
+
+	sx, sy := r.Width()/(urx-llx), r.Height()/(ury-lly)
+
+	return &matrix.Matrix{{sx, 0, 0}, {0, sy, 0}, {r.LL.X - llx*sx, r.LL.Y - lly*sy, 1}}, nil
+}
+
+// noRotateMatrix returns the transformation keeping an annotation with upper left corner p upright on a page rotated by rot.
+func noRotateMatrix(rot int, p types.Point) matrix.Matrix {
+	m := matrix.IdentMatrix
+	m[2][0], m[2][1] = -p.X, -p.Y
+	return m.Multiply(matrix.CalcRotateAndTranslateTransformMatrix(float64(rot), p.X, p.Y))
+}
+
+func unusedXObjectName(xObjs types.Dict) string {
+	for i := 0; ; i++ {
+		s := fmt.Sprintf("Fm%d", i)
+		if _, found := xObjs.Find(s); !found {
+			return s
+		}
+	}
+}
+
+// PaintAppearances paints the normal appearances of the annotations of page pageNr contained in annots
+// as form XObjects into the page content respecting annotation rectangle, form matrix and page rotation.
+// Annotations without normal appearance are skipped.
+func (xRefTable *XRefTable) PaintAppearances(pageNr int, annots map[types.IndirectRef]bool) error {
+	d, _, inhPAttrs, err := xRefTable.PageDict(pageNr, false)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return errors.Errorf("pdfcpu: missing page %d", pageNr)
+	}
+
+	arr, err := xRefTable.DereferenceArray(d["Annots"])
+	if err != nil || len(arr) == 0 {
+		return err
+	}
+
+	var (
+		b          bytes.Buffer
+		res, xObjs types.Dict
+	)
+
+	for _, o := range arr {
+		ir, ok := o.(types.IndirectRef)
+		if !ok || !annots[ir] {
+			continue
+		}
+
+		ad, err := xRefTable.DereferenceDict(ir)
+		if err != nil {
+			return err
+		}
+
+		a := ad.ArrayEntry("Rect")
+		if len(a) != 4 {
+			continue
+		}
+		r, err := xRefTable.RectForArray(a)
+		if err != nil {
+			return err
+		}
+
+		apIndRef, sd, err := xRefTable.NormalAppearance(ad)
+		if err != nil {
+			return err
+		}
+		if sd == nil {
+			continue
+		}
+
+		m, err := xRefTable.AppearanceMatrix(sd, r)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			continue
+		}
+
+		if AnnotationFlagsFor(ad)&AnnNoRotate > 0 && inhPAttrs.Rotate%360 != 0 {
+			*m = m.Multiply(noRotateMatrix(inhPAttrs.Rotate, types.Point{X: r.LL.X, Y: r.UR.Y}))
+		}
+
+		if res == nil {
+			res = types.NewDict()
+			if inhPAttrs.Resources != nil {
+				res = inhPAttrs.Resources.Clone().(types.Dict)
+			}
+			if xObjs, err = xRefTable.DereferenceDict(res["XObject"]); err != nil {
+				return err
+			}
+			if xObjs == nil {
+				xObjs = types.NewDict()
+			} else {
+				xObjs = xObjs.Clone().(types.Dict)
+			}
+			res["XObject"] = xObjs
+		}
+
+		if _, found := sd.Dict.Find("Subtype"); !found {
+			sd.Dict["Subtype"] = types.Name("Form")
+		}
+
+		name := unusedXObjectName(xObjs)
+		xObjs[name] = *apIndRef
+
+		// Keep the appearance alive when the annotation gets deleted.
+		if entry, found := xRefTable.FindTableEntryLight(apIndRef.ObjectNumber.Value()); found {
+			entry.RefCount++
+		}
+
+		fmt.Fprintf(&b, "q %.5f %.5f %.5f %.5f %.5f %.5f cm /%s Do Q\n", m[0][0], m[0][1], m[1][0], m[1][1], m[2][0], m[2][1], name)
+	}
+
+	if b.Len() == 0 {
+		return nil
+	}
+
+	bb, err := xRefTable.PageContent(d)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("q\n")
+	buf.Write(bb)
+	buf.WriteString("\nQ\n")
+	buf.Write(b.Bytes())
+
+	indRef, err := xRefTable.StreamDictIndRef(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	d["Contents"] = *indRef
+	d["Resources"] = res
+
+	return nil
+}
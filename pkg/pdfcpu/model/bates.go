@@ -0,0 +1,53 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Bates represents the command details for the command "Bates".
+type Bates struct {
+	Prefix     string // prepended to every Bates number
+	Start      int    // first Bates number
+	Digits     int    // Bates numbers get zero-padded to this number of digits
+	Desc       string // stamp description (position, offset, font, ...), see "pdfcpu help stamp"
+	Properties bool   // record the Bates range of each file as document properties BatesBegin and BatesEnd
+}
+
+// DefaultBatesConfig returns the default configuration.
+func DefaultBatesConfig() *Bates {
+	return &Bates{Start: 1, Digits: 6}
+}
+
+// Validate ensures a sane Bates configuration.
+func (b Bates) Validate() error {
+	if b.Start < 0 {
+		return errors.Errorf("pdfcpu: Bates start must be >= 0: %d", b.Start)
+	}
+	if b.Digits < 1 || b.Digits > 18 {
+		return errors.Errorf("pdfcpu: Bates digits must be between 1 and 18: %d", b.Digits)
+	}
+	return nil
+}
+
+// Number returns the zero-padded Bates number i including the prefix.
+func (b Bates) Number(i int) string {
+	return fmt.Sprintf("%s%0*d", b.Prefix, b.Digits, i)
+}
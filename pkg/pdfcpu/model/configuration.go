@@ -17,6 +17,8 @@ limitations under the License.
 package model
 
 import (
+	"crypto"
+	"crypto/x509"
 	_ "embed"
 	"fmt"
 	"os"
@@ -153,6 +155,32 @@ const (
 	SETVIEWERPREFERENCES
 	RESETVIEWERPREFERENCES
 	ZOOM
+	SIGN
+	VERIFYSIGNATURES
+	EXTRACTTEXT
+	APPLYREDACTIONS
+	RENDER
+	CONVERTPDFA
+	PAGECOUNT
+	DIFF
+	LISTREVISIONS
+	EXTRACTREVISION
+	LISTLAYERS
+	ADDLAYER
+	REMOVELAYER
+	SHOWLAYER
+	HIDELAYER
+	RENAMELAYER
+	MERGELAYERS
+	FLATTENLAYERS
+	FLATTENFORMFIELDS
+	FLATTENANNOTATIONS
+	LISTPAGELABELS
+	SETPAGELABELS
+	REMOVEPAGELABELS
+	BATES
+	ADDHEADERFOOTER
+	REMOVEHEADERFOOTER
 )
 
 // Configuration of a Context.
@@ -176,6 +204,9 @@ type Configuration struct {
 	// Validate against ISO-32000: strict or relaxed.
 	ValidationMode int
 
+	// Additionally validate against a PDF/A conformance level: pdfa-1b, pdfa-2b, pdfa-3b.
+	ValidationProfile string
+
 	// Enable validation right before writing.
 	PostProcessValidate bool
 
@@ -194,6 +225,10 @@ type Configuration struct {
 	// Switches between xRefSection (<=V1.4) and objectStream/xRefStream (>=V1.5) writing.
 	WriteXRefStream bool
 
+	// Write a linearized file optimized for incremental access aka "fast web view".
+	// Enforces xRefSection writing without object streams.
+	Linearize bool
+
 	// Turns on stats collection.
 	// TODO Decision - unused.
 	CollectStats bool
@@ -220,6 +255,13 @@ type Configuration struct {
 	// Supplied user access permissions, see Table 22.
 	Permissions PermissionFlags // int16
 
+	// Recipient certificates for public-key encryption (Adobe.PubSec) replacing passwords.
+	Recipients []*x509.Certificate
+
+	// Supplied private key and optional certificate of a recipient of a file using public-key encryption.
+	RecipientKey  crypto.PrivateKey
+	RecipientCert *x509.Certificate
+
 	// Command being executed.
 	Cmd CommandMode
 
@@ -245,6 +287,18 @@ type Configuration struct {
 	// Optimize duplicate content streams across pages. (assuming Optimize == true || OptimizeBeforeWriting == true)
 	OptimizeDuplicateContentStreams bool
 
+	// Downsample images placed with a resolution above this dpi, 0 = off.
+	OptimizeImagesMaxDPI int
+
+	// Recompress Flate encoded photos as JPEG using this quality (1..100), 0 = off.
+	OptimizeImagesJPEGQuality int
+
+	// Convert RGB images containing gray pixels only to DeviceGray.
+	OptimizeImagesGray bool
+
+	// Subset embedded TrueType fonts to the glyphs used. Fonts used by form fields are left untouched.
+	OptimizeSubsetFonts bool
+
 	// Merge creates bookmarks.
 	CreateBookmarks bool
 
@@ -254,6 +308,14 @@ type Configuration struct {
 	// Internet availability.
 	Offline bool
 
+	// Parse objects on first access instead of reading the whole file into memory.
+	// Honored by commands not modifying the document: page count, info, properties and extract pages.
+	ReadLazy bool
+
+	// Maximum number of lazily read objects kept in memory, 0 = default.
+	// Objects modified in memory are kept in addition.
+	ObjectCacheSize int
+
 	// HTTP timeout in seconds.
 	Timeout int
 }
@@ -418,6 +480,15 @@ func NewAESConfiguration(userPW, ownerPW string, keyLength int) *Configuration {
 	return c
 }
 
+// NewPubSecConfiguration returns a default configuration for AES-256 encryption to recipients.
+func NewPubSecConfiguration(recipients []*x509.Certificate) *Configuration {
+	c := NewDefaultConfiguration()
+	c.Recipients = recipients
+	c.EncryptUsingAES = true
+	c.EncryptKeyLength = 256
+	return c
+}
+
 // NewRC4Configuration returns a default configuration for RC4 encryption.
 func NewRC4Configuration(userPW, ownerPW string, keyLength int) *Configuration {
 	c := NewDefaultConfiguration()
@@ -549,3 +620,15 @@ func (c *Configuration) ApplyReducedFeatureSet() bool {
 	}
 	return false
 }
+
+// LazyRead returns true if objects shall be parsed on first access for the current command.
+func (c *Configuration) LazyRead() bool {
+	if !c.ReadLazy {
+		return false
+	}
+	switch c.Cmd {
+	case PAGECOUNT, LISTINFO, LISTPROPERTIES, EXTRACTPAGES:
+		return true
+	}
+	return false
+}
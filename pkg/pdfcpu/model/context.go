@@ -288,12 +288,17 @@ type OptimizationContext struct {
 	Fonts             map[string][]int    // All font object numbers registered for a font name.
 	DuplicateFonts    map[int]types.Dict  // Registry of duplicate font dicts.
 	DuplicateFontObjs types.IntSet        // The set of objects that represents the union of the object graphs of all duplicate font dicts.
+	SubsetFonts       types.IntSet        // The set of subset font program objects.
+	FontBytesSaved    int64               // The number of bytes saved by subsetting font programs.
 
 	// Image section
 	PageImages         []types.IntSet                // For each page a registry of image object numbers.
 	ImageObjects       map[int]*ImageObject          // ImageObject lookup table by image object number.
 	DuplicateImages    map[int]*DuplicateImageObject // Registry of duplicate image dicts.
 	DuplicateImageObjs types.IntSet                  // The set of objects that represents the union of the object graphs of all duplicate image dicts.
+	RecompressedImages types.IntSet                  // The set of downsampled or recompressed image objects.
+	SkippedImages      types.IntSet                  // The set of image objects kept because re-encoding would have grown them.
+	ImageBytesSaved    int64                         // The number of bytes saved by recompressing images.
 
 	ContentStreamCache map[int]*types.StreamDict
 	FormStreamCache    map[int]*types.StreamDict
@@ -312,9 +317,12 @@ func newOptimizationContext() *OptimizationContext {
 		Fonts:                map[string][]int{},
 		DuplicateFonts:       map[int]types.Dict{},
 		DuplicateFontObjs:    types.IntSet{},
+		SubsetFonts:          types.IntSet{},
 		ImageObjects:         map[int]*ImageObject{},
 		DuplicateImages:      map[int]*DuplicateImageObject{},
 		DuplicateImageObjs:   types.IntSet{},
+		RecompressedImages:   types.IntSet{},
+		SkippedImages:        types.IntSet{},
 		DuplicateInfoObjects: types.IntSet{},
 		ContentStreamCache:   map[int]*types.StreamDict{},
 		FormStreamCache:      map[int]*types.StreamDict{},
@@ -370,6 +378,39 @@ func (oc *OptimizationContext) DuplicateImageObjectsString() (int, string) {
 	return len(dupImages), strings.Join(dupImages, ",")
 }
 
+func intSetString(set types.IntSet) (int, string) {
+
+	var objs []int
+	for k := range set {
+		if set[k] {
+			objs = append(objs, k)
+		}
+	}
+	sort.Ints(objs)
+
+	var ss []string
+	for _, i := range objs {
+		ss = append(ss, fmt.Sprintf("%d", i))
+	}
+
+	return len(ss), strings.Join(ss, ",")
+}
+
+// SubsetFontsString returns a formatted string and the number of subset font program objs.
+func (oc *OptimizationContext) SubsetFontsString() (int, string) {
+	return intSetString(oc.SubsetFonts)
+}
+
+// RecompressedImagesString returns a formatted string and the number of recompressed image objs.
+func (oc *OptimizationContext) RecompressedImagesString() (int, string) {
+	return intSetString(oc.RecompressedImages)
+}
+
+// SkippedImagesString returns a formatted string and the number of image objs not worth recompressing.
+func (oc *OptimizationContext) SkippedImagesString() (int, string) {
+	return intSetString(oc.SkippedImages)
+}
+
 // IsDuplicateInfoObject returns true if object #i is a duplicate info object.
 func (oc *OptimizationContext) IsDuplicateInfoObject(i int) bool {
 	return oc.DuplicateInfoObjects[i]
@@ -0,0 +1,184 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Header and footer slots.
+const (
+	SlotLeft = iota
+	SlotCenter
+	SlotRight
+)
+
+// HeaderFooter represents the command details for the command "HeaderFooter".
+//
+// Slot texts support the stamp placeholders %p (page number), %P (page count), %t (timestamp), %v (pdfcpu version)
+// and in addition %l (page label), %f (file name), %d (date) and %{key} (document property eg. %{Title}).
+type HeaderFooter struct {
+	Header    [3]string         // left, center and right header slot
+	Footer    [3]string         // left, center and right footer slot
+	Mirror    bool              // swap left and right slots on even pages
+	Margin    float64           // distance between header/footer and the page edges
+	FontName  string            // font name
+	FontSize  int               // font size in points
+	FillColor color.SimpleColor // text color
+	Shrink    bool              // shrink page content overlapping header or footer
+	FileName  string            // substitution for %f
+	Unit      types.DisplayUnit // display unit
+}
+
+// DefaultHeaderFooterConfig returns the default configuration.
+func DefaultHeaderFooterConfig() *HeaderFooter {
+	return &HeaderFooter{
+		Margin:    20,
+		FontName:  "Helvetica",
+		FontSize:  9,
+		FillColor: color.Black,
+		Shrink:    true,
+	}
+}
+
+// HasHeader returns true if any header slot is in use.
+func (hf HeaderFooter) HasHeader() bool {
+	return hf.Header != [3]string{}
+}
+
+// HasFooter returns true if any footer slot is in use.
+func (hf HeaderFooter) HasFooter() bool {
+	return hf.Footer != [3]string{}
+}
+
+// Validate ensures a sane header/footer configuration.
+func (hf HeaderFooter) Validate() error {
+	if !hf.HasHeader() && !hf.HasFooter() {
+		return errors.New("pdfcpu: headerfooter: please provide at least one of hl, hc, hr, fl, fc, fr")
+	}
+	if hf.FontSize <= 0 {
+		return errors.Errorf("pdfcpu: headerfooter: font size must be > 0: %d", hf.FontSize)
+	}
+	return nil
+}
+
+func slotParser(header bool, slot int) func(string, *HeaderFooter) error {
+	return func(s string, hf *HeaderFooter) error {
+		if header {
+			hf.Header[slot] = s
+		} else {
+			hf.Footer[slot] = s
+		}
+		return nil
+	}
+}
+
+func parseHeaderFooterMargin(s string, hf *HeaderFooter) error {
+	m, err := strconv.ParseFloat(s, 64)
+	if err != nil || m < 0 {
+		return errors.Errorf("pdfcpu: \"margin\" must be a numeric value >= 0, got %s\n", s)
+	}
+	hf.Margin = types.ToUserSpace(m, hf.Unit)
+	return nil
+}
+
+func parseHeaderFooterFontName(s string, hf *HeaderFooter) error {
+	hf.FontName = s
+	return nil
+}
+
+func parseHeaderFooterFontSize(s string, hf *HeaderFooter) error {
+	fs, err := strconv.Atoi(s)
+	if err != nil || fs <= 0 {
+		return errors.Errorf("pdfcpu: \"points\" must be an integer > 0, got %s\n", s)
+	}
+	hf.FontSize = fs
+	return nil
+}
+
+func parseHeaderFooterFillColor(s string, hf *HeaderFooter) error {
+	c, err := color.ParseColor(s)
+	if err != nil {
+		return err
+	}
+	hf.FillColor = c
+	return nil
+}
+
+func parseHeaderFooterFlag(s, param string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "on", "true", "t":
+		return true, nil
+	case "off", "false", "f":
+		return false, nil
+	}
+	return false, errors.Errorf("pdfcpu: headerfooter %s, please provide one of: on/off true/false t/f", param)
+}
+
+func parseHeaderFooterMirror(s string, hf *HeaderFooter) (err error) {
+	hf.Mirror, err = parseHeaderFooterFlag(s, "mirror")
+	return err
+}
+
+func parseHeaderFooterShrink(s string, hf *HeaderFooter) (err error) {
+	hf.Shrink, err = parseHeaderFooterFlag(s, "shrink")
+	return err
+}
+
+type headerFooterParameterMap map[string]func(string, *HeaderFooter) error
+
+var HeaderFooterParamMap = headerFooterParameterMap{
+	"hl":        slotParser(true, SlotLeft),
+	"hc":        slotParser(true, SlotCenter),
+	"hr":        slotParser(true, SlotRight),
+	"fl":        slotParser(false, SlotLeft),
+	"fc":        slotParser(false, SlotCenter),
+	"fr":        slotParser(false, SlotRight),
+	"margin":    parseHeaderFooterMargin,
+	"mirror":    parseHeaderFooterMirror,
+	"fontname":  parseHeaderFooterFontName,
+	"points":    parseHeaderFooterFontSize,
+	"fillcolor": parseHeaderFooterFillColor,
+	"shrink":    parseHeaderFooterShrink,
+}
+
+// Handle applies parameter completion and on success parse parameter values into hf.
+func (m headerFooterParameterMap) Handle(paramPrefix, paramValueStr string, hf *HeaderFooter) error {
+	var param string
+
+	// Completion support
+	for k := range m {
+		if !strings.HasPrefix(k, strings.ToLower(paramPrefix)) {
+			continue
+		}
+		if len(param) > 0 {
+			return errors.Errorf("pdfcpu: ambiguous parameter prefix \"%s\"", paramPrefix)
+		}
+		param = k
+	}
+
+	if param == "" {
+		return errors.Errorf("pdfcpu: unknown parameter prefix \"%s\"", paramPrefix)
+	}
+
+	return m[param](paramValueStr, hf)
+}
@@ -153,6 +153,41 @@ func createFlateImageObject(xRefTable *XRefTable, buf, sm []byte, w, h, bpc int,
 	return sd, nil
 }
 
+// CreateCCITTImageObject returns a CCITT Group 4 encoded stream dict for rows of packed 1 bit samples.
+func CreateCCITTImageObject(xRefTable *XRefTable, buf []byte, w, h int) (*types.StreamDict, error) {
+	parms := types.Dict(
+		map[string]types.Object{
+			"K":       types.Integer(-1),
+			"Columns": types.Integer(w),
+			"Rows":    types.Integer(h),
+		},
+	)
+
+	sd := &types.StreamDict{
+		Dict: types.Dict(
+			map[string]types.Object{
+				"Type":             types.Name("XObject"),
+				"Subtype":          types.Name("Image"),
+				"Width":            types.Integer(w),
+				"Height":           types.Integer(h),
+				"BitsPerComponent": types.Integer(1),
+				"ColorSpace":       types.Name(DeviceGrayCS),
+			},
+		),
+		Content:        buf,
+		FilterPipeline: []types.PDFFilter{{Name: filter.CCITTFax, DecodeParms: parms}},
+	}
+
+	sd.InsertName("Filter", filter.CCITTFax)
+	sd.Insert("DecodeParms", parms)
+
+	if err := sd.Encode(); err != nil {
+		return nil, err
+	}
+
+	return sd, nil
+}
+
 // CreateDCTImageObject returns a DCT encoded stream dict.
 func CreateDCTImageObject(xRefTable *XRefTable, buf []byte, w, h, bpc int, cs string) (*types.StreamDict, error) {
 	sd := &types.StreamDict{
@@ -550,6 +585,60 @@ func createImageBuf(xRefTable *XRefTable, img image.Image, format string) ([]byt
 	return buf, sm, bpc, cs, nil
 }
 
+// bilevelSamples returns the samples of img packed into 1 bit per pixel with 1 representing white.
+// Unless threshold is set img has to consist of opaque black and white pixels only.
+func bilevelSamples(img image.Image, threshold bool) ([]byte, bool) {
+	var gray *image.Gray
+
+	switch img := img.(type) {
+	case *image.Gray:
+		gray = img
+	case *image.Gray16:
+		if !threshold {
+			for i := 0; i < len(img.Pix); i += 2 {
+				if b := img.Pix[i]; b != img.Pix[i+1] || b != 0x00 && b != 0xFF {
+					return nil, false
+				}
+			}
+		}
+		gray = convertToGray(img)
+	case *image.Paletted:
+		if !threshold {
+			for _, c := range img.Palette {
+				r, g, b, a := c.RGBA()
+				if a != 0xFFFF || r != g || g != b || r != 0 && r != 0xFFFF {
+					return nil, false
+				}
+			}
+		}
+		gray = convertToGray(img)
+	default:
+		if !threshold {
+			return nil, false
+		}
+		gray = convertToGray(img)
+	}
+
+	b := gray.Bounds()
+	w, h := b.Dx(), b.Dy()
+	stride := (w + 7) / 8
+	buf := make([]byte, stride*h)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := gray.GrayAt(b.Min.X+x, b.Min.Y+y).Y
+			if !threshold && v != 0x00 && v != 0xFF {
+				return nil, false
+			}
+			if v >= 0x80 {
+				buf[y*stride+x/8] |= 0x80 >> (x % 8)
+			}
+		}
+	}
+
+	return buf, true
+}
+
 func colorSpaceForJPEGColorModel(cm color.Model) string {
 	switch cm {
 	case color.GrayModel:
@@ -574,7 +663,18 @@ func createDCTImageObjectForJPEG(xRefTable *XRefTable, c image.Config, bb bytes.
 }
 
 // CreateImageStreamDict returns a stream dict for image data represented by r and applies optional filters.
+// Black and white images get CCITT Group 4 encoded.
 func CreateImageStreamDict(xRefTable *XRefTable, r io.Reader, gray, sepia bool) (*types.StreamDict, int, int, error) {
+	return createImageStreamDict(xRefTable, r, gray, sepia, false)
+}
+
+// CreateBilevelImageStreamDict returns a CCITT Group 4 encoded stream dict for image data represented by r
+// thresholded to black and white.
+func CreateBilevelImageStreamDict(xRefTable *XRefTable, r io.Reader) (*types.StreamDict, int, int, error) {
+	return createImageStreamDict(xRefTable, r, false, false, true)
+}
+
+func createImageStreamDict(xRefTable *XRefTable, r io.Reader, gray, sepia, bilevel bool) (*types.StreamDict, int, int, error) {
 
 	var bb bytes.Buffer
 	tee := io.TeeReader(r, &bb)
@@ -589,7 +689,7 @@ func CreateImageStreamDict(xRefTable *XRefTable, r io.Reader, gray, sepia bool)
 		return nil, 0, 0, err
 	}
 
-	if format == "jpeg" && !gray && !sepia {
+	if format == "jpeg" && !gray && !sepia && !bilevel {
 		return createDCTImageObjectForJPEG(xRefTable, c, bb)
 	}
 
@@ -598,6 +698,14 @@ func CreateImageStreamDict(xRefTable *XRefTable, r io.Reader, gray, sepia bool)
 		return nil, 0, 0, err
 	}
 
+	if bilevel || !sepia {
+		if buf, ok := bilevelSamples(img, bilevel); ok {
+			w, h := img.Bounds().Dx(), img.Bounds().Dy()
+			sd, err := CreateCCITTImageObject(xRefTable, buf, w, h)
+			return sd, w, h, err
+		}
+	}
+
 	if gray {
 		switch img.(type) {
 		case *image.Gray, *image.Gray16:
@@ -633,3 +741,14 @@ func CreateImageResource(xRefTable *XRefTable, r io.Reader, gray, sepia bool) (*
 	indRef, err := xRefTable.IndRefForNewObject(*sd)
 	return indRef, w, h, err
 }
+
+// CreateBilevelImageResource creates a new CCITT Group 4 encoded XObject for given image data represented by r
+// thresholded to black and white.
+func CreateBilevelImageResource(xRefTable *XRefTable, r io.Reader) (*types.IndirectRef, int, int, error) {
+	sd, w, h, err := CreateBilevelImageStreamDict(xRefTable, r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	indRef, err := xRefTable.IndRefForNewObject(*sd)
+	return indRef, w, h, err
+}
@@ -18,8 +18,11 @@ package model
 
 import (
 	"encoding/xml"
+	"fmt"
 	"strings"
 	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 )
 
 type UserDate time.Time
@@ -153,3 +156,87 @@ func RemoveKeywords(metadata *[]byte) error {
 
 	return nil
 }
+
+func xmpText(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func xmpDate(s string) (string, bool) {
+	t, ok := types.DateTime(s, true)
+	if !ok {
+		return "", false
+	}
+	return t.Format(time.RFC3339), true
+}
+
+// PDFAMetadata returns an XMP packet carrying the PDF/A identification schema for part
+// and the document information dict entries of xRefTable.
+func (xRefTable *XRefTable) PDFAMetadata(part int) ([]byte, error) {
+	var d types.Dict
+	if xRefTable.Info != nil {
+		var err error
+		if d, err = xRefTable.DereferenceDict(*xRefTable.Info); err != nil {
+			return nil, err
+		}
+	}
+
+	text := func(key string) string {
+		o, found := d.Find(key)
+		if !found {
+			return ""
+		}
+		s, err := xRefTable.DereferenceText(o)
+		if err != nil {
+			return ""
+		}
+		return s
+	}
+
+	var b strings.Builder
+
+	b.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	b.WriteString(" <rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+	b.WriteString("  <rdf:Description rdf:about=\"\"")
+	b.WriteString(" xmlns:pdfaid=\"http://www.aiim.org/pdfa/ns/id/\"")
+	b.WriteString(" xmlns:dc=\"http://purl.org/dc/elements/1.1/\"")
+	b.WriteString(" xmlns:xmp=\"http://ns.adobe.com/xap/1.0/\"")
+	b.WriteString(" xmlns:pdf=\"http://ns.adobe.com/pdf/1.3/\">\n")
+
+	fmt.Fprintf(&b, "   <pdfaid:part>%d</pdfaid:part>\n", part)
+	b.WriteString("   <pdfaid:conformance>B</pdfaid:conformance>\n")
+
+	if s := text("Title"); s != "" {
+		fmt.Fprintf(&b, "   <dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n", xmpText(s))
+	}
+	if s := text("Author"); s != "" {
+		fmt.Fprintf(&b, "   <dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", xmpText(s))
+	}
+	if s := text("Subject"); s != "" {
+		fmt.Fprintf(&b, "   <dc:description><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:description>\n", xmpText(s))
+	}
+	if s := text("Keywords"); s != "" {
+		fmt.Fprintf(&b, "   <pdf:Keywords>%s</pdf:Keywords>\n", xmpText(s))
+	}
+	if s := text("Producer"); s != "" {
+		fmt.Fprintf(&b, "   <pdf:Producer>%s</pdf:Producer>\n", xmpText(s))
+	}
+	if s := text("Creator"); s != "" {
+		fmt.Fprintf(&b, "   <xmp:CreatorTool>%s</xmp:CreatorTool>\n", xmpText(s))
+	}
+	if s, ok := xmpDate(text("CreationDate")); ok {
+		fmt.Fprintf(&b, "   <xmp:CreateDate>%s</xmp:CreateDate>\n", s)
+	}
+	if s, ok := xmpDate(text("ModDate")); ok {
+		fmt.Fprintf(&b, "   <xmp:ModifyDate>%s</xmp:ModifyDate>\n", s)
+	}
+
+	b.WriteString("  </rdf:Description>\n")
+	b.WriteString(" </rdf:RDF>\n")
+	b.WriteString("</x:xmpmeta>\n")
+	b.WriteString("<?xpacket end=\"w\"?>")
+
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// PDF/A conformance levels supported for validation (ISO 19005).
+const (
+	PDFA1B = "pdfa-1b"
+	PDFA2B = "pdfa-2b"
+	PDFA3B = "pdfa-3b"
+)
+
+// PDFAProfile returns the normalized PDF/A conformance level for s.
+func PDFAProfile(s string) (string, bool) {
+	s = strings.ToLower(strings.ReplaceAll(s, "/", ""))
+	switch s {
+	case PDFA1B, PDFA2B, PDFA3B:
+		return s, true
+	}
+	return "", false
+}
+
+// PDFAPart returns the part of ISO 19005 for profile.
+func PDFAPart(profile string) int {
+	switch profile {
+	case PDFA1B:
+		return 1
+	case PDFA2B:
+		return 2
+	case PDFA3B:
+		return 3
+	}
+	return 0
+}
+
+var (
+	pdfa1Actions = []string{"Launch", "Sound", "Movie", "ResetForm", "ImportData", "JavaScript"}
+	pdfa2Actions = []string{"Launch", "Sound", "Movie", "ResetForm", "ImportData", "Hide", "SetOCGState", "Rendition", "Trans", "GoTo3DView", "JavaScript"}
+	namedActions = []string{"NextPage", "PrevPage", "FirstPage", "LastPage"}
+)
+
+// PDFAActionPermitted returns true if PDF/A part permits actions of type s.
+// For named actions name is the name of the action to be performed.
+func PDFAActionPermitted(part int, s, name string) bool {
+	forbidden := pdfa2Actions
+	if part == 1 {
+		forbidden = pdfa1Actions
+	}
+	if types.MemberOf(s, forbidden) {
+		return false
+	}
+	return s != "Named" || types.MemberOf(name, namedActions)
+}
+
+// PDFAViolation represents a failed PDF/A rule.
+type PDFAViolation struct {
+	Rule  string // ISO 19005 clause
+	ObjNr int    // offending object, 0 if not applicable
+	Msg   string
+}
+
+func (v PDFAViolation) String() string {
+	if v.ObjNr > 0 {
+		return fmt.Sprintf("%s (obj#:%d): %s", v.Rule, v.ObjNr, v.Msg)
+	}
+	return fmt.Sprintf("%s: %s", v.Rule, v.Msg)
+}
+
+// PDFAError reports all rule violations encountered for a PDF/A profile.
+type PDFAError struct {
+	Profile    string
+	Violations []PDFAViolation
+}
+
+func (e *PDFAError) Error() string {
+	ss := make([]string, 0, len(e.Violations)+1)
+	ss = append(ss, fmt.Sprintf("pdfcpu: %d %s violation(s):", len(e.Violations), e.Profile))
+	for _, v := range e.Violations {
+		ss = append(ss, "  "+v.String())
+	}
+	return strings.Join(ss, "\n")
+}
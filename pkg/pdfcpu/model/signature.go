@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Signature formats (SubFilter values).
+const (
+	SignatureFormatPKCS7 = "adbe.pkcs7.detached"
+	SignatureFormatCAdES = "ETSI.CAdES.detached"
+)
+
+// DefaultSignatureSize is the default number of bytes reserved for the CMS blob in /Contents.
+const DefaultSignatureSize = 16384
+
+// SignatureConfig represents the details of a digital signature to be applied.
+type SignatureConfig struct {
+	FieldName   string // name of the signature field (T)
+	Name        string // name of the person or authority signing (Name)
+	Reason      string // reason for signing (Reason)
+	Location    string // location of signing (Location)
+	ContactInfo string // contact info for verifying the signature (ContactInfo)
+	Format      string // SubFilter: adbe.pkcs7.detached or ETSI.CAdES.detached (PAdES)
+	Size        int    // bytes reserved for the CMS SignedData blob
+	DocMDP      int    // 1,2,3 for a certification signature, 0 for an approval signature
+}
+
+// DefaultSignatureConfig returns the default configuration for signing.
+func DefaultSignatureConfig() *SignatureConfig {
+	return &SignatureConfig{
+		FieldName: "Signature1",
+		Format:    SignatureFormatPKCS7,
+		Size:      DefaultSignatureSize,
+	}
+}
+
+func parseSignatureFieldName(s string, sc *SignatureConfig) error {
+	if s == "" {
+		return errors.New("pdfcpu: signature field name must not be empty")
+	}
+	sc.FieldName = s
+	return nil
+}
+
+func parseSignatureName(s string, sc *SignatureConfig) error {
+	sc.Name = s
+	return nil
+}
+
+func parseSignatureReason(s string, sc *SignatureConfig) error {
+	sc.Reason = s
+	return nil
+}
+
+func parseSignatureLocation(s string, sc *SignatureConfig) error {
+	sc.Location = s
+	return nil
+}
+
+func parseSignatureContactInfo(s string, sc *SignatureConfig) error {
+	sc.ContactInfo = s
+	return nil
+}
+
+func parseSignatureFormat(s string, sc *SignatureConfig) error {
+	switch strings.ToLower(s) {
+	case "pkcs7", "adbe.pkcs7.detached":
+		sc.Format = SignatureFormatPKCS7
+	case "pades", "cades", "etsi.cades.detached":
+		sc.Format = SignatureFormatCAdES
+	default:
+		return errors.Errorf("pdfcpu: unsupported signature format: %s, please provide one of: pkcs7, pades", s)
+	}
+	return nil
+}
+
+func parseSignatureSize(s string, sc *SignatureConfig) error {
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 1024 {
+		return errors.Errorf("pdfcpu: signature size must be an integer >= 1024, got: %s", s)
+	}
+	sc.Size = i
+	return nil
+}
+
+func parseSignatureCertify(s string, sc *SignatureConfig) error {
+	switch s {
+	case "1", "2", "3":
+		sc.DocMDP = int(s[0] - '0')
+	default:
+		return errors.Errorf("pdfcpu: certify: DocMDP permissions must be one of 1,2,3, got: %s", s)
+	}
+	return nil
+}
+
+type signatureParameterMap map[string]func(string, *SignatureConfig) error
+
+// SignatureParamMap maps signature configuration parameters to their parsers.
+var SignatureParamMap = signatureParameterMap{
+	"certify":  parseSignatureCertify,
+	"contact":  parseSignatureContactInfo,
+	"field":    parseSignatureFieldName,
+	"format":   parseSignatureFormat,
+	"location": parseSignatureLocation,
+	"name":     parseSignatureName,
+	"reason":   parseSignatureReason,
+	"size":     parseSignatureSize,
+}
+
+// Handle applies parameter completion and on success parses parameter values into sc.
+func (m signatureParameterMap) Handle(paramPrefix, paramValueStr string, sc *SignatureConfig) error {
+	var param string
+
+	// Completion support
+	for k := range m {
+		if !strings.HasPrefix(k, strings.ToLower(paramPrefix)) {
+			continue
+		}
+		if len(param) > 0 {
+			return errors.Errorf("pdfcpu: ambiguous parameter prefix \"%s\"", paramPrefix)
+		}
+		param = k
+	}
+
+	if param == "" {
+		return errors.Errorf("pdfcpu: unknown parameter prefix \"%s\"", paramPrefix)
+	}
+
+	return m[param](paramValueStr, sc)
+}
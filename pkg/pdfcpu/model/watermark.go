@@ -21,6 +21,7 @@ import (
 	"io"
 	"math"
 
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/barcode"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/draw"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
@@ -44,6 +45,7 @@ const (
 	WMText = iota
 	WMImage
 	WMPDF
+	WMBarcode
 )
 
 type formCache map[types.Rectangle]*types.IndirectRef
@@ -57,11 +59,11 @@ type PdfResources struct {
 // Watermark represents the basic structure and command details for the commands "Stamp" and "Watermark".
 type Watermark struct {
 	OnTop                     bool                // if true STAMP else WATERMARK.
-	Mode                      int                 // WMText, WMImage or WMPDF
+	Mode                      int                 // WMText, WMImage, WMPDF or WMBarcode
 	FileName                  string              // image or PDF file name
 	Image                     io.Reader           // image reader
 	PDF                       io.ReadSeeker       // PDF read seeker
-	TextString                string              // raw display text.
+	TextString                string              // raw display text or barcode content.
 	TextLines                 []string            // display multiple lines of text.
 	URL                       string              // overlay link annotation for stamps.
 	InpUnit                   types.DisplayUnit   // input display unit.
@@ -91,6 +93,7 @@ type Watermark struct {
 	ScaleEff                  float64             // effective scale factor
 	ScaleAbs                  bool                // true for absolute scaling.
 	Update                    bool                // true for updating instead of adding a page watermark.
+	Artifact                  string              // pagination artifact subtype marking the content, defaults to Watermark.
 	Ocg, ExtGState, Font, Img *types.IndirectRef  // resources
 	Width, Height             int                 // image or page dimensions
 
@@ -101,6 +104,10 @@ type Watermark struct {
 	PdfMultiStartPageNrSrc  int                  // start page number of the source PDF file serving as stamp provider.
 	PdfMultiStartPageNrDest int                  // start page number of the destination PDF file.
 
+	// Barcode stamp
+	Barcode barcode.Symbology // barcode type
+	Code    *barcode.Code     // barcode for the current page
+
 	// page specific
 	Bb      *types.Rectangle   // bounding box of the form representing this watermark.
 	BbTrans types.QuadLiteral  // Transformed bounding box.
@@ -159,11 +166,19 @@ func (wm Watermark) IsImage() bool {
 	return wm.Mode == WMImage
 }
 
+// IsBarcode returns true if the watermark content is a barcode.
+func (wm Watermark) IsBarcode() bool {
+	return wm.Mode == WMBarcode
+}
+
 // Typ returns the nature of wm.
 func (wm Watermark) Typ() string {
 	if wm.IsImage() {
 		return "image"
 	}
+	if wm.IsBarcode() {
+		return "barcode"
+	}
 	if wm.IsPDF() {
 		return "pdf"
 	}
@@ -430,7 +445,7 @@ func (wm *Watermark) CalcTransformMatrix() matrix.Matrix {
 	cos = math.Cos(float64(r) * float64(DegToRad))
 
 	var dx, dy float64
-	if !wm.IsImage() && !wm.IsPDF() {
+	if wm.IsText() {
 		dy = wm.Bb.LL.Y
 	}
 
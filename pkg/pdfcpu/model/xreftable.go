@@ -85,6 +85,7 @@ type Enc struct {
 	L, P, R, V int
 	Emd        bool // encrypt meta data
 	ID         []byte
+	Recipients [][]byte // PKCS#7 recipients of the public-key security handler
 }
 
 // AnnotMap represents annotations by object number of the corresponding annotation dict.
@@ -166,6 +167,7 @@ type XRefTable struct {
 	ValidationMode int                       // see Configuration
 	ValidateLinks  bool                      // check for broken links in LinkAnnotations/URIDicts.
 	Valid          bool                      // true means successful validated against ISO 32000.
+	PDFAProfile    string                    // PDF/A conformance level to be maintained when writing.
 	URIs           map[int]map[string]string // URIs for link checking
 
 	Optimized      bool
@@ -178,6 +180,18 @@ type XRefTable struct {
 	// Fonts
 	UsedGIDs  map[string]map[uint16]bool
 	FillFonts map[string]types.IndirectRef
+
+	// Lazy reading
+	Loader ObjectLoader // Parses objects on first access, nil if all objects have been read.
+}
+
+// ObjectLoader loads objects of a lazily read file on demand.
+type ObjectLoader interface {
+	// Load ensures the object of entry objNr is available.
+	Load(objNr int, entry *XRefTableEntry) error
+
+	// Resident returns the number of loaded objects currently held in memory.
+	Resident() int
 }
 
 // NewXRefTable creates a new XRefTable.
@@ -291,9 +305,20 @@ func (xRefTable *XRefTable) Find(objNr int) (*XRefTableEntry, bool) {
 	if !found {
 		return nil, false
 	}
+	if xRefTable.Loader != nil && !e.Free {
+		// A corrupt object is treated like the null object.
+		if err := xRefTable.Loader.Load(objNr, e); err != nil && log.ReadEnabled() {
+			log.Read.Printf("Find: obj#%d: %v\n", objNr, err)
+		}
+	}
 	return e, true
 }
 
+// Lazy returns true if objects are parsed on first access.
+func (xRefTable *XRefTable) Lazy() bool {
+	return xRefTable.Loader != nil
+}
+
 // FindObject returns the object of the XRefTableEntry for a specific object number.
 func (xRefTable *XRefTable) FindObject(objNr int) (types.Object, error) {
 	entry, ok := xRefTable.Find(objNr)
@@ -994,6 +1019,36 @@ func (xRefTable *XRefTable) DereferenceStreamDict(o types.Object) (*types.Stream
 	return &sd, ev, nil
 }
 
+// ResolveJBIG2Globals provides the JBIG2Decode filters of sd with the content of their JBIG2Globals streams.
+func (xRefTable *XRefTable) ResolveJBIG2Globals(sd *types.StreamDict) error {
+	for i, f := range sd.FilterPipeline {
+		if f.Name != filter.JBIG2 || f.DecodeParms == nil || f.JBIG2Globals != nil {
+			continue
+		}
+
+		o, found := f.DecodeParms.Find("JBIG2Globals")
+		if !found {
+			continue
+		}
+
+		gsd, _, err := xRefTable.DereferenceStreamDict(o)
+		if err != nil {
+			return err
+		}
+		if gsd == nil {
+			continue
+		}
+
+		if err := gsd.Decode(); err != nil {
+			return err
+		}
+
+		sd.FilterPipeline[i].JBIG2Globals = gsd.Content
+	}
+
+	return nil
+}
+
 // DereferenceXObjectDict resolves an XObject.
 func (xRefTable *XRefTable) DereferenceXObjectDict(indRef types.IndirectRef) (*types.StreamDict, error) {
 	sd, _, err := xRefTable.DereferenceStreamDict(indRef)
@@ -1483,6 +1483,20 @@ func OptimizeXRefTable(ctx *model.Context) error {
 		return err
 	}
 
+	// Reduce embedded TrueType fonts to the glyphs used.
+	if ctx.Conf.OptimizeSubsetFonts {
+		if err := subsetFonts(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Downsample and recompress images.
+	if ctx.Conf.OptimizeImagesMaxDPI > 0 || ctx.Conf.OptimizeImagesJPEGQuality > 0 || ctx.Conf.OptimizeImagesGray {
+		if err := optimizeImages(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Get rid of PieceInfo dict from root.
 	if err := ctx.DeleteDictEntry(ctx.RootDict, "PieceInfo"); err != nil {
 		return err
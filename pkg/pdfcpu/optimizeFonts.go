@@ -0,0 +1,710 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	pdffont "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// fontProgram collects the glyphs used from an embedded TrueType font program.
+type fontProgram struct {
+	gids   map[uint16]bool
+	dicts  []int // font dicts using this program
+	unsafe bool  // true if the glyphs used are unknown
+}
+
+// fontUsage collects the character codes shown using a font dict.
+type fontUsage struct {
+	d         types.Dict
+	progNr    int
+	composite bool
+	outlines  *pdffont.Outlines
+	codes     types.IntSet // simple fonts: codes, composite fonts: CIDs
+}
+
+// glyphCollector scans all content streams for glyphs shown using embedded TrueType fonts.
+type glyphCollector struct {
+	*interpreter
+	usages     map[int]*fontUsage   // by font dict objNr
+	programs   map[int]*fontProgram // by font program objNr
+	byDecoder  map[*pdffont.Decoder]*fontUsage
+	incomplete bool // true if some text could not be attributed to a font
+}
+
+// trueTypeProgram returns the object number of the embedded TrueType font program for the font dict d.
+func trueTypeProgram(ctx *model.Context, d types.Dict) (int, bool) {
+	st := d.Subtype()
+	if st == nil {
+		return 0, false
+	}
+
+	composite := *st == "Type0"
+	if !composite && *st != "TrueType" {
+		return 0, false
+	}
+
+	if composite {
+		a, err := ctx.DereferenceArray(d["DescendantFonts"])
+		if err != nil || len(a) == 0 {
+			return 0, false
+		}
+		if d, err = ctx.DereferenceDict(a[0]); err != nil || d == nil {
+			return 0, false
+		}
+		if st := d.Subtype(); st == nil || *st != "CIDFontType2" {
+			return 0, false
+		}
+	}
+
+	fd, err := ctx.DereferenceDict(d["FontDescriptor"])
+	if err != nil || fd == nil {
+		return 0, false
+	}
+
+	ir, ok := fd["FontFile2"].(types.IndirectRef)
+	if !ok {
+		return 0, false
+	}
+
+	return ir.ObjectNumber.Value(), composite
+}
+
+func (gc *glyphCollector) program(objNr int) *fontProgram {
+	p, ok := gc.programs[objNr]
+	if !ok {
+		p = &fontProgram{gids: map[uint16]bool{}}
+		gc.programs[objNr] = p
+	}
+	return p
+}
+
+// markUnsafe excludes the font program of font o from subsetting.
+func (gc *glyphCollector) markUnsafe(o types.Object) {
+	d, err := gc.ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return
+	}
+	if progNr, _ := trueTypeProgram(gc.ctx, d); progNr > 0 {
+		gc.program(progNr).unsafe = true
+	}
+}
+
+// markFontsUnsafe excludes all font programs used by resources from subsetting.
+func (gc *glyphCollector) markFontsUnsafe(resources types.Object) {
+	d, err := gc.ctx.DereferenceDict(resources)
+	if err != nil || d == nil {
+		return
+	}
+	fonts, err := gc.ctx.DereferenceDict(d["Font"])
+	if err != nil {
+		return
+	}
+	for _, o := range fonts {
+		gc.markUnsafe(o)
+	}
+}
+
+// usage registers the current font selected by Tf fontName.
+func (gc *glyphCollector) usage(resources types.Dict, fontName string) {
+	dec := gc.gs.font
+	if dec == nil {
+		gc.incomplete = true
+		return
+	}
+	if _, ok := gc.byDecoder[dec]; ok {
+		return
+	}
+
+	fonts, _ := gc.ctx.DereferenceDict(resources["Font"])
+	o, _ := fonts.Find(fontName)
+
+	d, err := gc.ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		gc.incomplete = true
+		return
+	}
+
+	progNr, composite := trueTypeProgram(gc.ctx, d)
+	if progNr == 0 {
+		gc.byDecoder[dec] = nil
+		return
+	}
+
+	ir, ok := o.(types.IndirectRef)
+	if !ok {
+		// Direct font dicts can't be told apart.
+		gc.program(progNr).unsafe = true
+		gc.byDecoder[dec] = nil
+		return
+	}
+
+	objNr := ir.ObjectNumber.Value()
+	u, ok := gc.usages[objNr]
+	if !ok {
+		u = &fontUsage{
+			d:         d,
+			progNr:    progNr,
+			composite: composite,
+			outlines:  pdffont.NewOutlines(gc.ctx.XRefTable, d, dec),
+			codes:     types.IntSet{},
+		}
+		gc.usages[objNr] = u
+		p := gc.program(progNr)
+		p.dicts = append(p.dicts, objNr)
+	}
+	gc.byDecoder[dec] = u
+}
+
+func (gc *glyphCollector) glyph(g placedGlyph) {
+	u := gc.byDecoder[gc.gs.font]
+	if u == nil {
+		return
+	}
+
+	p := gc.programs[u.progNr]
+
+	gids, ok := u.outlines.TrueTypeGlyphIDs(g.Glyph)
+	if !ok {
+		p.unsafe = true
+		return
+	}
+	for _, gid := range gids {
+		p.gids[gid] = true
+	}
+
+	if u.composite {
+		u.codes[gc.gs.font.CID(g.Code)] = true
+		return
+	}
+	u.codes[int(g.Code[0])] = true
+}
+
+// extGStateFont excludes fonts set by the graphics state parameter dict name from subsetting.
+func (gc *glyphCollector) extGStateFont(resources types.Dict, name string) {
+	gss, err := gc.ctx.DereferenceDict(resources["ExtGState"])
+	if err != nil || gss == nil {
+		return
+	}
+	gs, err := gc.ctx.DereferenceDict(gss[name])
+	if err != nil || gs == nil {
+		return
+	}
+	if a, err := gc.ctx.DereferenceArray(gs["Font"]); err == nil && len(a) > 0 {
+		gc.markUnsafe(a[0])
+	}
+}
+
+// scan collects the glyphs shown by the content stream bb.
+func (gc *glyphCollector) scan(bb []byte, resources types.Dict) {
+	ops, err := content.Parse(bb)
+	if err != nil {
+		gc.incomplete = true
+		return
+	}
+
+	gc.interpreter = newInterpreter(gc.ctx)
+	gc.byDecoder = map[*pdffont.Decoder]*fontUsage{}
+
+	for _, op := range ops {
+		gc.state(op, resources)
+
+		switch op.Operator {
+
+		case "Tf":
+			if len(op.Operands) == 2 {
+				if n, ok := op.Operands[0].(types.Name); ok {
+					gc.usage(resources, n.Value())
+				}
+			}
+
+		case "gs":
+			if n, ok := lastOperand(op.Operands).(types.Name); ok && resources != nil {
+				gc.extGStateFont(resources, n.Value())
+			}
+
+		case "Tj", "'", "\"":
+			gc.showString(lastOperand(op.Operands), gc.glyph)
+
+		case "TJ":
+			if a, ok := lastOperand(op.Operands).(types.Array); ok {
+				gc.showArray(a, gc.glyph)
+			}
+		}
+	}
+}
+
+func (gc *glyphCollector) scanStream(sd *types.StreamDict, resources types.Object) {
+	if err := sd.Decode(); err != nil {
+		gc.incomplete = true
+		return
+	}
+	d, err := gc.ctx.DereferenceDict(resources)
+	if err != nil {
+		gc.incomplete = true
+		return
+	}
+	gc.scan(sd.Content, d)
+}
+
+func (gc *glyphCollector) scanPages() error {
+	ctx := gc.ctx
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+		d, _, inhPAttrs, err := ctx.PageDict(pageNr, false)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+
+		bb, err := ctx.PageContent(d)
+		if err == model.ErrNoContent {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		gc.scan(bb, inhPAttrs.Resources)
+	}
+	return nil
+}
+
+// scanObjects collects the glyphs shown by form XObjects, tiling patterns and Type3 glyph descriptions.
+// This includes appearance streams and does not depend on these objects being in use.
+func (gc *glyphCollector) scanObjects() error {
+	ctx := gc.ctx
+
+	objNrs := make([]int, 0, len(ctx.Table))
+	for objNr := range ctx.Table {
+		objNrs = append(objNrs, objNr)
+	}
+	sort.Ints(objNrs)
+
+	for _, objNr := range objNrs {
+		entry := ctx.Table[objNr]
+		if entry.Free || entry.Generation == nil {
+			continue
+		}
+
+		o, err := ctx.Dereference(*types.NewIndirectRef(objNr, *entry.Generation))
+		if err != nil {
+			return err
+		}
+
+		switch o := o.(type) {
+
+		case types.StreamDict:
+			if st := o.Subtype(); (st != nil && *st == "Form") || o.IntEntry("PatternType") != nil && *o.IntEntry("PatternType") == 1 {
+				gc.scanStream(&o, o.Dict["Resources"])
+			}
+
+		case types.Dict:
+			if st := o.Subtype(); st == nil || *st != "Type3" {
+				continue
+			}
+			procs, err := ctx.DereferenceDict(o["CharProcs"])
+			if err != nil || procs == nil {
+				continue
+			}
+			for _, v := range procs {
+				if sd, _, err := ctx.DereferenceStreamDict(v); err == nil && sd != nil {
+					gc.scanStream(sd, o["Resources"])
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// markFormFontsUnsafe excludes fonts used by form fields from subsetting
+// since viewers may need additional glyphs for regenerating field appearances.
+func (gc *glyphCollector) markFormFontsUnsafe() error {
+	ctx := gc.ctx
+
+	if d, err := ctx.DereferenceDict(ctx.RootDict["AcroForm"]); err == nil && d != nil {
+		gc.markFontsUnsafe(d["DR"])
+	}
+
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+		d, _, _, err := ctx.PageDict(pageNr, false)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+		annots, err := ctx.DereferenceArray(d["Annots"])
+		if err != nil {
+			continue
+		}
+		for _, o := range annots {
+			annot, err := ctx.DereferenceDict(o)
+			if err != nil || annot == nil {
+				continue
+			}
+			if st := annot.Subtype(); st == nil || *st != "Widget" {
+				continue
+			}
+			ap, err := ctx.DereferenceDict(annot["AP"])
+			if err != nil || ap == nil {
+				continue
+			}
+			for _, k := range []string{"N", "R", "D"} {
+				gc.markAppearanceFontsUnsafe(ap[k])
+			}
+		}
+	}
+
+	return nil
+}
+
+func (gc *glyphCollector) markAppearanceFontsUnsafe(o types.Object) {
+	o, err := gc.ctx.Dereference(o)
+	if err != nil {
+		return
+	}
+	switch o := o.(type) {
+	case types.StreamDict:
+		gc.markFontsUnsafe(o.Dict["Resources"])
+	case types.Dict:
+		// appearance states
+		for _, v := range o {
+			if sd, _, err := gc.ctx.DereferenceStreamDict(v); err == nil && sd != nil {
+				gc.markFontsUnsafe(sd.Dict["Resources"])
+			}
+		}
+	}
+}
+
+func subsetFontName(name, tag string) string {
+	if i := strings.Index(name, "+"); i == 6 {
+		return name
+	}
+	return tag + "+" + name
+}
+
+// tagFontName prefixes the font name of d found under key with a subset tag.
+func tagFontName(ctx *model.Context, d types.Dict, key, tag string) {
+	if d == nil {
+		return
+	}
+	n, err := ctx.DereferenceName(d[key], model.V10, nil)
+	if err != nil || n == "" {
+		return
+	}
+	d[key] = types.Name(subsetFontName(n.Value(), tag))
+}
+
+// pruneWidths restricts the Widths array of a simple font to the codes used.
+func pruneWidths(ctx *model.Context, d types.Dict, codes types.IntSet) {
+	fc, err := ctx.DereferenceInteger(d["FirstChar"])
+	if err != nil || fc == nil {
+		return
+	}
+	a, err := ctx.DereferenceArray(d["Widths"])
+	if err != nil || len(a) == 0 {
+		return
+	}
+
+	first, last := fc.Value(), fc.Value()+len(a)-1
+	lo, hi := last+1, first-1
+	for c, used := range codes {
+		if used && c >= first && c <= last {
+			lo, hi = min(lo, c), max(hi, c)
+		}
+	}
+	if lo > hi {
+		return
+	}
+
+	d["FirstChar"] = types.Integer(lo)
+	d["LastChar"] = types.Integer(hi)
+	d["Widths"] = append(types.Array(nil), a[lo-first:hi-first+1]...)
+}
+
+// cidWidths returns the widths of all CIDs of a W array.
+func cidWidths(ctx *model.Context, a types.Array) (map[int]types.Object, bool) {
+	m := map[int]types.Object{}
+	for i := 0; i < len(a); {
+		c, err := ctx.DereferenceInteger(a[i])
+		if err != nil || c == nil || i+1 >= len(a) {
+			return nil, false
+		}
+		o, err := ctx.Dereference(a[i+1])
+		if err != nil {
+			return nil, false
+		}
+		if ww, ok := o.(types.Array); ok {
+			for j, w := range ww {
+				m[c.Value()+j] = w
+			}
+			i += 2
+			continue
+		}
+		c2, ok := o.(types.Integer)
+		if !ok || i+2 >= len(a) {
+			return nil, false
+		}
+		for cid := c.Value(); cid <= c2.Value(); cid++ {
+			m[cid] = a[i+2]
+		}
+		i += 3
+	}
+	return m, true
+}
+
+// pruneCIDWidths restricts the W array of a CIDFont to the CIDs used.
+func pruneCIDWidths(ctx *model.Context, df types.Dict, cids types.IntSet) {
+	a, err := ctx.DereferenceArray(df["W"])
+	if err != nil || a == nil {
+		return
+	}
+	m, ok := cidWidths(ctx, a)
+	if !ok {
+		return
+	}
+
+	var used []int
+	for cid := range cids {
+		if _, ok := m[cid]; ok {
+			used = append(used, cid)
+		}
+	}
+	sort.Ints(used)
+
+	var w types.Array
+	for i := 0; i < len(used); {
+		j := i + 1
+		for j < len(used) && used[j] == used[j-1]+1 {
+			j++
+		}
+		ww := types.Array{}
+		for _, cid := range used[i:j] {
+			ww = append(ww, m[cid])
+		}
+		w = append(w, types.Integer(used[i]), ww)
+		i = j
+	}
+
+	if len(w) == 0 {
+		delete(df, "W")
+		return
+	}
+	df["W"] = w
+}
+
+// pruneCIDToGIDMap clears the entries of a CIDToGIDMap stream for unused CIDs.
+func pruneCIDToGIDMap(ctx *model.Context, ir types.IndirectRef, cids types.IntSet) error {
+	sd, _, err := ctx.DereferenceStreamDict(ir)
+	if err != nil || sd == nil {
+		return err
+	}
+	if err := sd.Decode(); err != nil {
+		return nil
+	}
+
+	n := 0
+	for cid, used := range cids {
+		if used && 2*cid+2 <= len(sd.Content) {
+			n = max(n, 2*cid+2)
+		}
+	}
+
+	bb := make([]byte, n)
+	for cid, used := range cids {
+		if used && 2*cid+2 <= n {
+			copy(bb[2*cid:], sd.Content[2*cid:2*cid+2])
+		}
+	}
+
+	sd1, err := ctx.NewStreamDictForBuf(bb)
+	if err != nil {
+		return err
+	}
+	if err := sd1.Encode(); err != nil {
+		return err
+	}
+
+	entry, _ := ctx.FindTableEntryLight(ir.ObjectNumber.Value())
+	entry.Object = *sd1
+
+	return nil
+}
+
+// subsetFontProgram replaces the font program obj#objNr by a subset containing the glyphs of p.
+func (gc *glyphCollector) subsetFontProgram(objNr int, p *fontProgram) (bool, error) {
+	ctx := gc.ctx
+
+	entry, ok := ctx.FindTableEntryLight(objNr)
+	if !ok || entry.Free || entry.Generation == nil {
+		return false, nil
+	}
+
+	sd, _, err := ctx.DereferenceStreamDict(*types.NewIndirectRef(objNr, *entry.Generation))
+	if err != nil || sd == nil {
+		return false, err
+	}
+
+	raw := len(sd.Raw)
+
+	if err := sd.Decode(); err != nil {
+		return false, nil
+	}
+
+	bb, err := font.SubsetFontFile("", sd.Content, p.gids)
+	if err != nil {
+		// Leave unsupported font programs untouched.
+		if log.OptimizeEnabled() {
+			log.Optimize.Printf("subsetFontProgram: obj#%d: %v\n", objNr, err)
+		}
+		return false, nil
+	}
+
+	sd1, err := ctx.NewStreamDictForBuf(bb)
+	if err != nil {
+		return false, err
+	}
+	sd1.InsertInt("Length1", len(bb))
+	if err := sd1.Encode(); err != nil {
+		return false, err
+	}
+
+	if len(sd1.Raw) >= raw {
+		return false, nil
+	}
+
+	if log.OptimizeEnabled() {
+		log.Optimize.Printf("subsetFontProgram: obj#%d %d glyphs %d -> %d bytes\n", objNr, len(p.gids), raw, len(sd1.Raw))
+	}
+
+	entry.Object = *sd1
+
+	ctx.Optimize.SubsetFonts[objNr] = true
+	ctx.Optimize.FontBytesSaved += int64(raw - len(sd1.Raw))
+
+	return true, nil
+}
+
+// updateFontDicts adjusts names and metrics of all font dicts using a subset font program.
+func (gc *glyphCollector) updateFontDicts(p *fontProgram) error {
+	ctx := gc.ctx
+	tag := pdffont.SubsetTag()
+	cidToGIDMaps := map[types.IndirectRef]types.IntSet{}
+
+	for _, objNr := range p.dicts {
+		u := gc.usages[objNr]
+		d := u.d
+		tagFontName(ctx, d, "BaseFont", tag)
+
+		if !u.composite {
+			fd, _ := ctx.DereferenceDict(d["FontDescriptor"])
+			tagFontName(ctx, fd, "FontName", tag)
+			pruneWidths(ctx, d, u.codes)
+			continue
+		}
+
+		a, _ := ctx.DereferenceArray(d["DescendantFonts"])
+		df, _ := ctx.DereferenceDict(a[0])
+		tagFontName(ctx, df, "BaseFont", tag)
+		fd, _ := ctx.DereferenceDict(df["FontDescriptor"])
+		tagFontName(ctx, fd, "FontName", tag)
+		pruneCIDWidths(ctx, df, u.codes)
+
+		if ir, ok := df["CIDToGIDMap"].(types.IndirectRef); ok {
+			// A CIDToGIDMap may be shared by several CIDFonts.
+			cids, ok := cidToGIDMaps[ir]
+			if !ok {
+				cids = types.IntSet{}
+				cidToGIDMaps[ir] = cids
+			}
+			for cid := range u.codes {
+				cids[cid] = true
+			}
+		}
+	}
+
+	for ir, cids := range cidToGIDMaps {
+		if err := pruneCIDToGIDMap(ctx, ir, cids); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// subsetFonts reduces embedded TrueType fonts to the glyphs shown.
+func subsetFonts(ctx *model.Context) error {
+	gc := &glyphCollector{
+		interpreter: newInterpreter(ctx),
+		usages:      map[int]*fontUsage{},
+		programs:    map[int]*fontProgram{},
+	}
+
+	if err := gc.scanPages(); err != nil {
+		return err
+	}
+
+	if err := gc.scanObjects(); err != nil {
+		return err
+	}
+
+	if gc.incomplete {
+		if log.OptimizeEnabled() {
+			log.Optimize.Println("subsetFonts: skipped due to text shown using unknown fonts")
+		}
+		return nil
+	}
+
+	if err := gc.markFormFontsUnsafe(); err != nil {
+		return err
+	}
+
+	objNrs := make([]int, 0, len(gc.programs))
+	for objNr := range gc.programs {
+		objNrs = append(objNrs, objNr)
+	}
+	sort.Ints(objNrs)
+
+	for _, objNr := range objNrs {
+		p := gc.programs[objNr]
+		if p.unsafe || len(p.dicts) == 0 {
+			continue
+		}
+		ok, err := gc.subsetFontProgram(objNr, p)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := gc.updateFontDicts(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,511 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"math"
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+const (
+	// Max deviation of the color components of a pixel considered gray.
+	grayTolerance = 2
+
+	// Min number of distinct colors of an image considered a photo.
+	photoColors = 1024
+)
+
+// imagePlacer records the largest size in user space each image XObject gets placed with.
+type imagePlacer struct {
+	*interpreter
+	sizes map[int]types.Dim
+}
+
+func (ip *imagePlacer) process(ops []content.Operation, resources types.Dict, depth int) error {
+	for _, op := range ops {
+		ip.state(op, resources)
+
+		if op.Operator != "Do" {
+			continue
+		}
+
+		n, ok := lastOperand(op.Operands).(types.Name)
+		if !ok {
+			continue
+		}
+
+		if err := ip.xObjectPlaced(resources, n.Value(), depth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ip *imagePlacer) xObjectPlaced(resources types.Dict, name string, depth int) error {
+	if resources == nil {
+		return nil
+	}
+
+	xObjs, err := ip.ctx.DereferenceDict(resources["XObject"])
+	if err != nil || xObjs == nil {
+		return err
+	}
+
+	ir, ok := xObjs[name].(types.IndirectRef)
+	if !ok {
+		return nil
+	}
+
+	sd, _, err := ip.ctx.DereferenceStreamDict(ir)
+	if err != nil || sd == nil {
+		return err
+	}
+
+	st := sd.Subtype()
+	if st == nil {
+		return nil
+	}
+
+	if *st == "Image" {
+		// The image gets mapped onto the unit square of the current user space.
+		m := ip.gs.ctm
+		w, h := math.Hypot(m[0][0], m[0][1]), math.Hypot(m[1][0], m[1][1])
+		objNr := ir.ObjectNumber.Value()
+		dim := ip.sizes[objNr]
+		ip.sizes[objNr] = types.Dim{Width: math.Max(dim.Width, w), Height: math.Max(dim.Height, h)}
+		return nil
+	}
+
+	if *st != "Form" || depth >= maxFormDepth {
+		return nil
+	}
+
+	sd, ops, res, err := ip.formXObject(resources, name)
+	if err != nil || sd == nil {
+		return err
+	}
+
+	restore := ip.enterForm(sd)
+	defer restore()
+
+	return ip.process(ops, res, depth+1)
+}
+
+// placedImageSizes returns the largest placement size in user space for all images used by page content.
+func placedImageSizes(ctx *model.Context) (map[int]types.Dim, error) {
+	ip := &imagePlacer{sizes: map[int]types.Dim{}}
+
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+		d, _, inhPAttrs, err := ctx.PageDict(pageNr, false)
+		if err != nil {
+			return nil, err
+		}
+		if d == nil {
+			continue
+		}
+
+		bb, err := ctx.PageContent(d)
+		if err == model.ErrNoContent {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ops, err := content.Parse(bb)
+		if err != nil {
+			return nil, err
+		}
+
+		ip.interpreter = newInterpreter(ctx)
+		if err := ip.process(ops, inhPAttrs.Resources, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return ip.sizes, nil
+}
+
+// recompressibleColorSpace returns the number of color components for images
+// using DeviceGray, DeviceRGB or an equivalent ICC based color space.
+func recompressibleColorSpace(ctx *model.Context, o types.Object) (comps int, device bool) {
+	o, err := ctx.Dereference(o)
+	if err != nil {
+		return 0, false
+	}
+
+	switch o := o.(type) {
+
+	case types.Name:
+		switch o {
+		case model.DeviceGrayCS:
+			return 1, true
+		case model.DeviceRGBCS:
+			return 3, true
+		}
+
+	case types.Array:
+		if len(o) != 2 || o[0] != types.Name(model.ICCBasedCS) {
+			return 0, false
+		}
+		sd, _, err := ctx.DereferenceStreamDict(o[1])
+		if err != nil || sd == nil {
+			return 0, false
+		}
+		if n := sd.IntEntry("N"); n != nil && (*n == 1 || *n == 3) {
+			return *n, false
+		}
+	}
+
+	return 0, false
+}
+
+// recompressibleSamples returns the 8 bit samples of an image along with its encoding filter.
+func recompressibleSamples(ctx *model.Context, sd *types.StreamDict) (bb []byte, w, h, comps int, f string, ok bool) {
+	if im := sd.BooleanEntry("ImageMask"); im != nil && *im {
+		return
+	}
+	if _, found := sd.Find("Decode"); found {
+		return
+	}
+	if o, found := sd.Find("Mask"); found {
+		// Color key masking relies on exact sample values.
+		if o, err := ctx.Dereference(o); err != nil {
+			return
+		} else if _, isArray := o.(types.Array); isArray {
+			return
+		}
+	}
+	if o, found := sd.Find("SMask"); found {
+		// A soft mask with Matte requires the image dimensions.
+		if smask, _, err := ctx.DereferenceStreamDict(o); err != nil || smask == nil || smask.ArrayEntry("Matte") != nil {
+			return
+		}
+	}
+
+	fp := sd.FilterPipeline
+	if len(fp) != 1 || (fp[0].Name != filter.Flate && fp[0].Name != filter.DCT && fp[0].Name != filter.JPX) {
+		return
+	}
+	f = fp[0].Name
+
+	if f == filter.JPX {
+		// JPXDecode images carry their bit depth in the JPEG 2000 data, an alpha channel would get lost.
+		if i := sd.IntEntry("SMaskInData"); i != nil && *i != 0 {
+			return
+		}
+	} else if bpc := sd.IntEntry("BitsPerComponent"); bpc == nil || *bpc != 8 {
+		return
+	}
+
+	cs := sd.Dict["ColorSpace"]
+	if f == filter.JPX && cs == nil {
+		info, err := filter.DecodeJPXInfo(sd.Raw)
+		if err != nil || info.ColorSpace == "" {
+			return
+		}
+		cs = types.Name(info.ColorSpace)
+	}
+
+	comps, device := recompressibleColorSpace(ctx, cs)
+	if comps == 0 {
+		return
+	}
+	if !device {
+		// Gray conversion only applies to device color spaces.
+		comps = -comps
+	}
+
+	data, _, err := imageData(sd)
+	if err != nil {
+		return
+	}
+
+	wi, hi := sd.IntEntry("Width"), sd.IntEntry("Height")
+	if wi == nil || hi == nil {
+		return
+	}
+	w, h = *wi, *hi
+
+	n := comps
+	if n < 0 {
+		n = -n
+	}
+
+	switch f {
+	case filter.DCT:
+		var c int
+		if data, w, h, c, err = jpegSamples(data); err != nil || c != n {
+			return
+		}
+	case filter.JPX:
+		var c int
+		if data, w, h, c, err = jpxSamples(data); err != nil || c != n {
+			return
+		}
+	}
+
+	if len(data) < w*h*n {
+		return
+	}
+
+	return data[:w*h*n], w, h, comps, f, true
+}
+
+// isGray returns true if all pixels of the RGB samples bb are gray.
+func isGray(bb []byte) bool {
+	for i := 0; i+2 < len(bb); i += 3 {
+		r, g, b := int(bb[i]), int(bb[i+1]), int(bb[i+2])
+		if abs(r-g) > grayTolerance || abs(g-b) > grayTolerance || abs(r-b) > grayTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+func toGray(bb []byte) []byte {
+	gray := make([]byte, len(bb)/3)
+	for i := range gray {
+		gray[i] = byte((int(bb[3*i]) + int(bb[3*i+1]) + int(bb[3*i+2]) + 1) / 3)
+	}
+	return gray
+}
+
+// isPhoto returns true if the samples bb contain many distinct colors.
+func isPhoto(bb []byte, comps int) bool {
+	colors := map[int]bool{}
+	for i := 0; i+comps <= len(bb); i += comps {
+		c := 0
+		for _, b := range bb[i : i+comps] {
+			c = c<<8 | int(b)
+		}
+		colors[c] = true
+		if len(colors) >= photoColors {
+			return true
+		}
+	}
+	return false
+}
+
+// downsample scales the w x h samples bb down to w1 x h1 by averaging the covered source pixels.
+func downsample(bb []byte, w, h, comps, w1, h1 int) []byte {
+	res := make([]byte, w1*h1*comps)
+	sum := make([]int, comps)
+	for y1 := 0; y1 < h1; y1++ {
+		y0, y2 := y1*h/h1, (y1+1)*h/h1
+		if y2 == y0 {
+			y2++
+		}
+		for x1 := 0; x1 < w1; x1++ {
+			x0, x2 := x1*w/w1, (x1+1)*w/w1
+			if x2 == x0 {
+				x2++
+			}
+			for c := range sum {
+				sum[c] = 0
+			}
+			for y := y0; y < y2; y++ {
+				for x := x0; x < x2; x++ {
+					for c := 0; c < comps; c++ {
+						sum[c] += int(bb[(y*w+x)*comps+c])
+					}
+				}
+			}
+			n := (y2 - y0) * (x2 - x0)
+			for c := 0; c < comps; c++ {
+				res[(y1*w1+x1)*comps+c] = byte((sum[c] + n/2) / n)
+			}
+		}
+	}
+	return res
+}
+
+func encodeJPEG(bb []byte, w, h, comps, quality int) ([]byte, error) {
+	var img image.Image
+	if comps == 1 {
+		img = &image.Gray{Pix: bb, Stride: w, Rect: image.Rect(0, 0, w, h)}
+	} else {
+		rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+		for i, j := 0, 0; i < len(bb); i, j = i+3, j+4 {
+			rgba.Pix[j], rgba.Pix[j+1], rgba.Pix[j+2], rgba.Pix[j+3] = bb[i], bb[i+1], bb[i+2], 0xFF
+		}
+		img = rgba
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// targetSize returns the image size needed for rendering an image of w x h pixels placed at size dim with maxDPI.
+func targetSize(w, h int, dim types.Dim, maxDPI int) (int, int) {
+	w1, h1 := w, h
+	if dim.Width > 0 {
+		if n := int(math.Ceil(dim.Width / 72 * float64(maxDPI))); n < w {
+			w1 = int(math.Max(float64(n), 1))
+		}
+	}
+	if dim.Height > 0 {
+		if n := int(math.Ceil(dim.Height / 72 * float64(maxDPI))); n < h {
+			h1 = int(math.Max(float64(n), 1))
+		}
+	}
+	return w1, h1
+}
+
+// recompressImage downsamples, converts and re-encodes the image obj#objNr according to the configuration.
+func recompressImage(ctx *model.Context, objNr int, sd *types.StreamDict, dim types.Dim) error {
+	bb, w, h, comps, f, ok := recompressibleSamples(ctx, sd)
+	if !ok {
+		return nil
+	}
+
+	conf := ctx.Conf
+	modified := false
+
+	if comps == 3 && conf.OptimizeImagesGray && isGray(bb) {
+		bb, comps = toGray(bb), 1
+		sd.Update("ColorSpace", types.Name(model.DeviceGrayCS))
+		modified = true
+	}
+	if comps < 0 {
+		comps = -comps
+	}
+
+	if conf.OptimizeImagesMaxDPI > 0 {
+		if w1, h1 := targetSize(w, h, dim, conf.OptimizeImagesMaxDPI); w1 != w || h1 != h {
+			bb, w, h = downsample(bb, w, h, comps, w1, h1), w1, h1
+			modified = true
+		}
+	}
+
+	quality := conf.OptimizeImagesJPEGQuality
+	toJPEG := quality > 0 && (f == filter.JPX || f == filter.Flate && isPhoto(bb, comps))
+
+	if !modified && !toJPEG {
+		return nil
+	}
+
+	if quality == 0 {
+		quality = jpeg.DefaultQuality
+	}
+
+	sd1 := types.StreamDict{Dict: sd.Dict.Clone().(types.Dict)}
+	sd1.Delete("DecodeParms")
+	sd1.Update("Width", types.Integer(w))
+	sd1.Update("Height", types.Integer(h))
+
+	if f == filter.JPX {
+		sd1.Delete("SMaskInData")
+		sd1.Update("BitsPerComponent", types.Integer(8))
+		if _, found := sd1.Find("ColorSpace"); !found {
+			sd1.Update("ColorSpace", types.Name([]string{"", model.DeviceGrayCS, "", model.DeviceRGBCS}[comps]))
+		}
+	}
+
+	if f != filter.Flate || toJPEG {
+		raw, err := encodeJPEG(bb, w, h, comps, quality)
+		if err != nil {
+			return err
+		}
+		sd1.Raw = raw
+		sd1.FilterPipeline = []types.PDFFilter{{Name: filter.DCT}}
+		sd1.Update("Filter", types.Name(filter.DCT))
+	} else {
+		sd1.Content = bb
+		sd1.FilterPipeline = []types.PDFFilter{{Name: filter.Flate}}
+		sd1.Update("Filter", types.Name(filter.Flate))
+		if err := sd1.Encode(); err != nil {
+			return err
+		}
+		sd1.Content = nil
+	}
+
+	if len(sd1.Raw) >= len(sd.Raw) {
+		// Keep the original.
+		ctx.Optimize.SkippedImages[objNr] = true
+		if log.OptimizeEnabled() {
+			log.Optimize.Printf("recompressImage: skipping obj#%d: %d bytes >= %d bytes\n", objNr, len(sd1.Raw), len(sd.Raw))
+		}
+		return nil
+	}
+
+	l := int64(len(sd1.Raw))
+	sd1.StreamLength = &l
+	sd1.Update("Length", types.Integer(l))
+
+	ctx.Optimize.RecompressedImages[objNr] = true
+	ctx.Optimize.ImageBytesSaved += int64(len(sd.Raw)) - l
+
+	if log.OptimizeEnabled() {
+		log.Optimize.Printf("recompressImage: obj#%d %d -> %d bytes\n", objNr, len(sd.Raw), l)
+	}
+
+	entry, _ := ctx.FindTableEntryLight(objNr)
+	entry.Object = sd1
+
+	return nil
+}
+
+// optimizeImages downsamples and recompresses images used by page content.
+func optimizeImages(ctx *model.Context) error {
+	sizes, err := placedImageSizes(ctx)
+	if err != nil {
+		return err
+	}
+
+	objNrs := make([]int, 0, len(sizes))
+	for objNr := range sizes {
+		objNrs = append(objNrs, objNr)
+	}
+	sort.Ints(objNrs)
+
+	for _, objNr := range objNrs {
+		entry, ok := ctx.FindTableEntryLight(objNr)
+		if !ok || entry.Free {
+			continue
+		}
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok {
+			continue
+		}
+		if err := recompressImage(ctx, objNr, &sd, sizes[objNr]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,551 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// See 12.4.2 Page Labels
+
+// ErrNoPageLabels indicates a document without page labels.
+var ErrNoPageLabels = errors.New("pdfcpu: no page labels available")
+
+// Page label numbering styles.
+const (
+	PageLabelDecimal    = "decimal" // 1, 2, 3
+	PageLabelRomanUpper = "Roman"   // I, II, III
+	PageLabelRomanLower = "roman"   // i, ii, iii
+	PageLabelAlphaUpper = "Alpha"   // A, B, C .. AA, BB, CC
+	PageLabelAlphaLower = "alpha"   // a, b, c .. aa, bb, cc
+	PageLabelNone       = "none"    // prefix only
+)
+
+var pageLabelStyles = map[string]string{
+	"D": PageLabelDecimal,
+	"R": PageLabelRomanUpper,
+	"r": PageLabelRomanLower,
+	"A": PageLabelAlphaUpper,
+	"a": PageLabelAlphaLower,
+}
+
+// PageLabel represents a range of pages sharing a labelling style.
+type PageLabel struct {
+	From   int    `json:"from"`
+	Thru   int    `json:"thru"`
+	Style  string `json:"style"`
+	Prefix string `json:"prefix,omitempty"`
+	Start  int    `json:"start"` // The value of the numeric portion of the first page label.
+	First  string `json:"firstLabel"`
+	Last   string `json:"lastLabel"`
+}
+
+func (pl PageLabel) String() string {
+	pages := strconv.Itoa(pl.From)
+	if pl.Thru > pl.From {
+		pages += "-" + strconv.Itoa(pl.Thru)
+	}
+	s := fmt.Sprintf("%-9s %-7s start=%d", pages, pl.Style, pl.Start)
+	if pl.Prefix != "" {
+		s += fmt.Sprintf(" prefix=%q", pl.Prefix)
+	}
+	labels := pl.First
+	if pl.Thru > pl.From {
+		labels += " .. " + pl.Last
+	}
+	return s + " => " + labels
+}
+
+// pageLabel is the label spec of a single page.
+type pageLabel struct {
+	style  string
+	prefix string
+	nr     int
+}
+
+func (pl pageLabel) String() string {
+	return pl.prefix + labelNumber(pl.style, pl.nr)
+}
+
+func romanNumeral(i int) string {
+	var sb strings.Builder
+	for _, r := range []struct {
+		v int
+		s string
+	}{
+		{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"}, {100, "C"}, {90, "XC"},
+		{50, "L"}, {40, "XL"}, {10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+	} {
+		for ; i >= r.v; i -= r.v {
+			sb.WriteString(r.s)
+		}
+	}
+	return sb.String()
+}
+
+// alphaNumeral returns A to Z for the first 26 numbers, AA to ZZ for the next 26, and so on.
+func alphaNumeral(i int) string {
+	return strings.Repeat(string(rune('A'+(i-1)%26)), (i-1)/26+1)
+}
+
+func labelNumber(style string, i int) string {
+	switch style {
+	case PageLabelDecimal:
+		return strconv.Itoa(i)
+	case PageLabelRomanUpper:
+		return romanNumeral(i)
+	case PageLabelRomanLower:
+		return strings.ToLower(romanNumeral(i))
+	case PageLabelAlphaUpper:
+		return alphaNumeral(i)
+	case PageLabelAlphaLower:
+		return strings.ToLower(alphaNumeral(i))
+	}
+	return ""
+}
+
+func defaultPageLabels(pageCount int) []pageLabel {
+	pls := make([]pageLabel, pageCount)
+	for i := range pls {
+		pls[i] = pageLabel{style: PageLabelDecimal, nr: i + 1}
+	}
+	return pls
+}
+
+func collectPageLabelNums(xRefTable *model.XRefTable, o types.Object, m map[int]types.Dict) error {
+	d, err := xRefTable.DereferenceDict(o)
+	if err != nil || d == nil {
+		return err
+	}
+
+	if o, found := d.Find("Kids"); found {
+		kids, err := xRefTable.DereferenceArray(o)
+		if err != nil {
+			return err
+		}
+		for _, kid := range kids {
+			if err := collectPageLabelNums(xRefTable, kid, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	nums, err := xRefTable.DereferenceArray(d["Nums"])
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i+1 < len(nums); i += 2 {
+		k, err := xRefTable.DereferenceInteger(nums[i])
+		if err != nil || k == nil {
+			return err
+		}
+		v, err := xRefTable.DereferenceDict(nums[i+1])
+		if err != nil {
+			return err
+		}
+		m[k.Value()] = v
+	}
+
+	return nil
+}
+
+// pageLabels returns the label spec of every page of ctx or nil if ctx has no page labels.
+func pageLabels(ctx *model.Context) ([]pageLabel, error) {
+	o, found := ctx.RootDict.Find("PageLabels")
+	if !found {
+		return nil, nil
+	}
+
+	m := map[int]types.Dict{}
+	if err := collectPageLabelNums(ctx.XRefTable, o, m); err != nil {
+		return nil, err
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	// Pages preceding the first range are labelled by page number.
+	pls := defaultPageLabels(ctx.PageCount)
+
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	for i, k := range keys {
+		d := m[k]
+
+		pl := pageLabel{style: PageLabelNone, nr: 1}
+		if s := d.NameEntry("S"); s != nil {
+			if style, ok := pageLabelStyles[*s]; ok {
+				pl.style = style
+			}
+		}
+		if o, found := d.Find("P"); found {
+			s, err := ctx.DereferenceText(o)
+			if err != nil {
+				return nil, err
+			}
+			pl.prefix = s
+		}
+		if st := d.IntEntry("St"); st != nil && *st > 0 {
+			pl.nr = *st
+		}
+
+		thru := ctx.PageCount
+		if i+1 < len(keys) {
+			thru = min(keys[i+1], ctx.PageCount)
+		}
+		for j := max(k, 0); j < thru; j++ {
+			pls[j] = pl
+			pl.nr++
+		}
+	}
+
+	return pls, nil
+}
+
+// pageLabelRanges compresses pls into page label ranges.
+func pageLabelRanges(pls []pageLabel) []PageLabel {
+	var ranges []PageLabel
+
+	for i, pl := range pls {
+		if i > 0 {
+			prev := pls[i-1]
+			r := &ranges[len(ranges)-1]
+			if pl.style == prev.style && pl.prefix == prev.prefix && (pl.style == PageLabelNone || pl.nr == prev.nr+1) {
+				r.Thru = i + 1
+				r.Last = pl.String()
+				continue
+			}
+		}
+		start := pl.nr
+		if pl.style == PageLabelNone {
+			start = 1
+		}
+		ranges = append(ranges, PageLabel{
+			From:   i + 1,
+			Thru:   i + 1,
+			Style:  pl.style,
+			Prefix: pl.prefix,
+			Start:  start,
+			First:  pl.String(),
+			Last:   pl.String()})
+	}
+
+	return ranges
+}
+
+// writePageLabels replaces the page label number tree of ctx by pls.
+func writePageLabels(ctx *model.Context, pls []pageLabel) error {
+	if len(pls) == 0 {
+		ctx.RootDict.Delete("PageLabels")
+		return nil
+	}
+
+	var nums types.Array
+
+	for _, pl := range pageLabelRanges(pls) {
+		d := types.NewDict()
+		for k, v := range pageLabelStyles {
+			if v == pl.Style {
+				d["S"] = types.Name(k)
+			}
+		}
+		if pl.Prefix != "" {
+			s, err := types.EscapedUTF16String(pl.Prefix)
+			if err != nil {
+				return err
+			}
+			d.InsertString("P", *s)
+		}
+		if pl.Start != 1 {
+			d["St"] = types.Integer(pl.Start)
+		}
+		nums = append(nums, types.Integer(pl.From-1), d)
+	}
+
+	indRef, err := ctx.IndRefForNewObject(types.Dict(map[string]types.Object{"Nums": nums}))
+	if err != nil {
+		return err
+	}
+
+	ctx.RootDict["PageLabels"] = *indRef
+
+	return nil
+}
+
+// PageLabels returns the page label ranges of ctx.
+func PageLabels(ctx *model.Context) ([]PageLabel, error) {
+	pls, err := pageLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if pls == nil {
+		return nil, ErrNoPageLabels
+	}
+	return pageLabelRanges(pls), nil
+}
+
+// PageLabelStrings returns the label of every page of ctx.
+// Pages of a document without page labels are labelled by page number.
+func PageLabelStrings(ctx *model.Context) ([]string, error) {
+	pls, err := pageLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if pls == nil {
+		pls = defaultPageLabels(ctx.PageCount)
+	}
+	ss := make([]string, len(pls))
+	for i, pl := range pls {
+		ss[i] = pl.String()
+	}
+	return ss, nil
+}
+
+// SetPageLabels labels selected pages of ctx using style and prefix.
+// Each run of consecutive selected pages gets numbered beginning with start.
+// The labels of all other pages remain unchanged.
+func SetPageLabels(ctx *model.Context, selectedPages types.IntSet, style, prefix string, start int) error {
+	if style != PageLabelNone && labelNumber(style, 1) == "" {
+		return errors.Errorf("pdfcpu: unsupported page label style: %s", style)
+	}
+	if start < 1 {
+		return errors.Errorf("pdfcpu: page label start must be >= 1: %d", start)
+	}
+
+	pls, err := pageLabels(ctx)
+	if err != nil {
+		return err
+	}
+	if pls == nil {
+		pls = defaultPageLabels(ctx.PageCount)
+	}
+
+	nr := start
+	for i := range pls {
+		if selectedPages != nil && !selectedPages[i+1] {
+			nr = start
+			continue
+		}
+		pls[i] = pageLabel{style: style, prefix: prefix, nr: nr}
+		nr++
+	}
+
+	return writePageLabels(ctx, pls)
+}
+
+// RemovePageLabels removes all page labels from ctx.
+func RemovePageLabels(ctx *model.Context) (bool, error) {
+	if _, found := ctx.RootDict.Find("PageLabels"); !found {
+		return false, nil
+	}
+	ctx.RootDict.Delete("PageLabels")
+	return true, nil
+}
+
+// PagesForLabels returns the page numbers of ctx labelled label
+// or labelled within the range from label thru label when label is of the form "from-thru".
+func PagesForLabels(ctx *model.Context, label string) ([]int, error) {
+	ss, err := PageLabelStrings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	index := func(s string) int {
+		for i, l := range ss {
+			if l == s {
+				return i + 1
+			}
+		}
+		return 0
+	}
+
+	if i := index(label); i > 0 {
+		return []int{i}, nil
+	}
+
+	// Labels may contain '-' themselves, so try all possible splits.
+	for i := 0; i < len(label); i++ {
+		if label[i] != '-' {
+			continue
+		}
+		from, thru := index(label[:i]), index(label[i+1:])
+		if from == 0 || thru == 0 {
+			continue
+		}
+		if thru < from {
+			return nil, errors.Errorf("pdfcpu: invalid page label range: %s", label)
+		}
+		pageNrs := make([]int, 0, thru-from+1)
+		for j := from; j <= thru; j++ {
+			pageNrs = append(pageNrs, j)
+		}
+		return pageNrs, nil
+	}
+
+	return nil, errors.Errorf("pdfcpu: unknown page label: %s", label)
+}
+
+// ParsePageLabelStyle returns the page label style for s.
+func ParsePageLabelStyle(s string) (string, error) {
+	if style, ok := pageLabelStyles[s]; ok {
+		return style, nil
+	}
+	switch s {
+	case PageLabelDecimal, PageLabelRomanUpper, PageLabelRomanLower, PageLabelAlphaUpper, PageLabelAlphaLower, PageLabelNone:
+		return s, nil
+	}
+	return "", errors.Errorf("pdfcpu: unsupported page label style: %s (decimal, Roman, roman, Alpha, alpha, none)", s)
+}
+
+// ParsePageLabelConfig parses a page label description like "style:roman, prefix:A-, start:1".
+func ParsePageLabelConfig(s string) (*PageLabel, error) {
+	pl := &PageLabel{Style: PageLabelDecimal, Start: 1}
+
+	if strings.TrimSpace(s) == "" {
+		return pl, nil
+	}
+
+	for _, s := range strings.Split(s, ",") {
+		ss := strings.SplitN(s, ":", 2)
+		if len(ss) != 2 {
+			return nil, errors.New("pdfcpu: Invalid page label configuration string. Please consult pdfcpu help pagelabels")
+		}
+
+		paramPrefix := strings.ToLower(strings.TrimSpace(ss[0]))
+		paramValueStr := strings.TrimSpace(ss[1])
+
+		var param string
+		for _, k := range []string{"style", "prefix", "start"} {
+			if !strings.HasPrefix(k, paramPrefix) {
+				continue
+			}
+			if len(param) > 0 {
+				return nil, errors.Errorf("pdfcpu: ambiguous parameter prefix \"%s\"", paramPrefix)
+			}
+			param = k
+		}
+
+		switch param {
+		case "style":
+			style, err := ParsePageLabelStyle(paramValueStr)
+			if err != nil {
+				return nil, err
+			}
+			pl.Style = style
+		case "prefix":
+			pl.Prefix = paramValueStr
+		case "start":
+			i, err := strconv.Atoi(paramValueStr)
+			if err != nil || i < 1 {
+				return nil, errors.Errorf("pdfcpu: page label start must be an integer >= 1: %s", paramValueStr)
+			}
+			pl.Start = i
+		default:
+			return nil, errors.Errorf("pdfcpu: unknown parameter prefix \"%s\"", paramPrefix)
+		}
+	}
+
+	return pl, nil
+}
+
+// ListPageLabels returns a formatted list of page label ranges.
+func ListPageLabels(ctx *model.Context) ([]string, error) {
+	pls, err := PageLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ss := make([]string, len(pls))
+	for i, pl := range pls {
+		ss[i] = pl.String()
+	}
+	return ss, nil
+}
+
+// copyPageLabels labels the pages of ctxDest using the labels of pageNrs of ctxSrc.
+func copyPageLabels(ctxSrc, ctxDest *model.Context, pageNrs []int) error {
+	pls, err := pageLabels(ctxSrc)
+	if err != nil || pls == nil {
+		return err
+	}
+
+	plsDest := make([]pageLabel, 0, len(pageNrs))
+	for _, i := range pageNrs {
+		if i >= 1 && i <= len(pls) {
+			plsDest = append(plsDest, pls[i-1])
+		}
+	}
+
+	return writePageLabels(ctxDest, plsDest)
+}
+
+// mergedPageLabels returns the page labels resulting from merging ctxSrc into ctxDest
+// or nil if neither of them uses page labels.
+func mergedPageLabels(ctxSrc, ctxDest *model.Context, zip, dividerPage bool) ([]pageLabel, error) {
+	plsDest, err := pageLabels(ctxDest)
+	if err != nil {
+		return nil, err
+	}
+
+	plsSrc, err := pageLabels(ctxSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	if plsDest == nil && plsSrc == nil {
+		return nil, nil
+	}
+
+	if plsDest == nil {
+		plsDest = defaultPageLabels(ctxDest.PageCount)
+	}
+
+	if plsSrc == nil {
+		plsSrc = defaultPageLabels(ctxSrc.PageCount)
+	}
+
+	if zip {
+		var pls []pageLabel
+		for i := 0; i < max(len(plsDest), len(plsSrc)); i++ {
+			if i < len(plsDest) {
+				pls = append(pls, plsDest[i])
+			}
+			if i < len(plsSrc) {
+				pls = append(pls, plsSrc[i])
+			}
+		}
+		return pls, nil
+	}
+
+	if dividerPage {
+		plsDest = append(plsDest, pageLabel{style: PageLabelNone})
+	}
+
+	return append(plsDest, plsSrc...), nil
+}
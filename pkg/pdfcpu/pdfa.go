@@ -0,0 +1,421 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	pdffont "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+const sRGBOutputCondition = "sRGB IEC61966-2.1"
+
+// ConvertToPDFA fixes ctx for PDF/A conformance level profile as far as possible.
+func ConvertToPDFA(ctx *model.Context, profile string) error {
+	part := model.PDFAPart(profile)
+	if part == 0 {
+		return errors.Errorf("pdfcpu: unsupported PDF/A conformance level: %s", profile)
+	}
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return err
+	}
+
+	if err := removeEncryption(ctx); err != nil {
+		return err
+	}
+
+	removeForbiddenActions(ctx, rootDict, part)
+
+	if err := embedUserFonts(ctx.XRefTable); err != nil {
+		return err
+	}
+
+	if err := ensureOutputIntent(ctx.XRefTable, rootDict); err != nil {
+		return err
+	}
+
+	if part == 3 {
+		fixEmbeddedFileSpecs(ctx.XRefTable, rootDict)
+	}
+
+	ctx.PDFAProfile = profile
+
+	// The XMP metadata gets synthesized once more when writing since writing updates the info dict.
+	return ensurePDFAMetadata(ctx.XRefTable)
+}
+
+func removeEncryption(ctx *model.Context) error {
+	if ctx.Encrypt == nil {
+		return nil
+	}
+	if log.CLIEnabled() {
+		log.CLI.Println("removing encryption")
+	}
+	objNr := ctx.Encrypt.ObjectNumber.Value()
+	ctx.Encrypt, ctx.EncKey, ctx.E = nil, nil, nil
+	return ctx.FreeObject(objNr)
+}
+
+// actionPermitted returns true unless o is an action forbidden by PDF/A part.
+func actionPermitted(xRefTable *model.XRefTable, o types.Object, part int) bool {
+	d, err := xRefTable.DereferenceDict(o)
+	if err != nil || d == nil {
+		return true
+	}
+	s := d.NameEntry("S")
+	if s == nil {
+		return true
+	}
+	n := ""
+	if nn := d.NameEntry("N"); nn != nil {
+		n = *nn
+	}
+	return model.PDFAActionPermitted(part, *s, n)
+}
+
+// stripActions removes all additional actions and forbidden actions from d and its direct children.
+func stripActions(xRefTable *model.XRefTable, d types.Dict, part int) {
+	if _, found := d.Find("AA"); found {
+		d.Delete("AA")
+	}
+
+	for _, k := range []string{"A", "OpenAction"} {
+		if o, found := d.Find(k); found && !actionPermitted(xRefTable, o, part) {
+			d.Delete(k)
+		}
+	}
+
+	if o, found := d.Find("Next"); found {
+		if a, ok := o.(types.Array); ok {
+			a1 := types.Array{}
+			for _, o := range a {
+				if actionPermitted(xRefTable, o, part) {
+					a1 = append(a1, o)
+				}
+			}
+			d["Next"] = a1
+		} else if !actionPermitted(xRefTable, o, part) {
+			d.Delete("Next")
+		}
+	}
+
+	for _, o := range d {
+		stripDirectActions(xRefTable, o, part)
+	}
+}
+
+func stripDirectActions(xRefTable *model.XRefTable, o types.Object, part int) {
+	switch o := o.(type) {
+	case types.Dict:
+		stripActions(xRefTable, o, part)
+	case types.StreamDict:
+		stripActions(xRefTable, o.Dict, part)
+	case types.Array:
+		for _, o := range o {
+			stripDirectActions(xRefTable, o, part)
+		}
+	}
+}
+
+// removeForbiddenActions removes JavaScript, additional actions and any other action type not permitted by PDF/A part.
+func removeForbiddenActions(ctx *model.Context, rootDict types.Dict, part int) {
+	if names, err := ctx.DereferenceDict(rootDict["Names"]); err == nil && names != nil {
+		if _, found := names.Find("JavaScript"); found {
+			names.Delete("JavaScript")
+			delete(ctx.Names, "JavaScript")
+		}
+	}
+
+	for objNr := 1; objNr < *ctx.Size; objNr++ {
+		entry, found := ctx.FindTableEntryLight(objNr)
+		if !found || entry.Free || entry.Object == nil {
+			continue
+		}
+		stripDirectActions(ctx.XRefTable, entry.Object, part)
+	}
+}
+
+func normalizedFontName(s string) string {
+	s = strings.ToLower(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s))
+	s = strings.TrimSuffix(s, "mt")
+	return strings.TrimSuffix(s, "ps")
+}
+
+// userFontFor returns the installed user font matching baseFont.
+func userFontFor(baseFont string) (string, bool) {
+	// Skip any subset prefix.
+	if i := strings.Index(baseFont, "+"); i == 6 {
+		baseFont = baseFont[7:]
+	}
+
+	fontNames := font.UserFontNames()
+	sort.Strings(fontNames)
+
+	if types.MemberOf(baseFont, fontNames) {
+		return baseFont, true
+	}
+
+	s := normalizedFontName(baseFont)
+	for _, fn := range fontNames {
+		if normalizedFontName(fn) == s {
+			return fn, true
+		}
+	}
+
+	return "", false
+}
+
+func fontEmbedded(xRefTable *model.XRefTable, d types.Dict) bool {
+	fd, err := xRefTable.DereferenceDict(d["FontDescriptor"])
+	if err != nil || fd == nil {
+		return false
+	}
+	for _, k := range []string{"FontFile", "FontFile2", "FontFile3"} {
+		if _, found := fd.Find(k); found {
+			return true
+		}
+	}
+	return false
+}
+
+// embedUserFont turns the simple font d into an embedded TrueType font using the installed user font fontName.
+func embedUserFont(xRefTable *model.XRefTable, d types.Dict, fontName string) error {
+	font.UserFontMetricsLock.RLock()
+	ttf := font.UserFontMetrics[fontName]
+	font.UserFontMetricsLock.RUnlock()
+
+	fdIndRef, err := pdffont.NewFontDescriptor(xRefTable, ttf, fontName, "")
+	if err != nil {
+		return err
+	}
+
+	d["Subtype"] = types.Name("TrueType")
+	d["BaseFont"] = types.Name(fontName)
+	d["FontDescriptor"] = *fdIndRef
+
+	if _, found := d.Find("Encoding"); !found {
+		d.InsertName("Encoding", "WinAnsiEncoding")
+	}
+
+	// Keep existing widths in order to preserve the layout.
+	if _, found := d.Find("Widths"); !found {
+		wIndRef, err := pdffont.Widths(xRefTable, ttf, 0, 256)
+		if err != nil {
+			return err
+		}
+		d["FirstChar"] = types.Integer(0)
+		d["LastChar"] = types.Integer(255)
+		d["Widths"] = *wIndRef
+	}
+
+	return nil
+}
+
+// embedUserFonts embeds fonts not embedded yet for which a matching user font is installed.
+func embedUserFonts(xRefTable *model.XRefTable) error {
+	size := *xRefTable.Size
+	for objNr := 1; objNr < size; objNr++ {
+		entry, found := xRefTable.FindTableEntryLight(objNr)
+		if !found || entry.Free || entry.Object == nil {
+			continue
+		}
+
+		d, ok := entry.Object.(types.Dict)
+		if !ok || d.Type() == nil || *d.Type() != "Font" {
+			continue
+		}
+
+		st, bf := d.Subtype(), d.NameEntry("BaseFont")
+		if st == nil || bf == nil || !types.MemberOf(*st, []string{"Type1", "MMType1", "TrueType"}) || fontEmbedded(xRefTable, d) {
+			continue
+		}
+
+		fontName, ok := userFontFor(*bf)
+		if !ok {
+			continue
+		}
+
+		if log.CLIEnabled() {
+			log.CLI.Printf("embedding %s for %s\n", fontName, *bf)
+		}
+
+		if err := embedUserFont(xRefTable, d, fontName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureOutputIntent adds an sRGB output intent unless there is a PDF/A output intent already.
+func ensureOutputIntent(xRefTable *model.XRefTable, rootDict types.Dict) error {
+	a, err := xRefTable.DereferenceArray(rootDict["OutputIntents"])
+	if err != nil {
+		return err
+	}
+
+	for _, o := range a {
+		d, err := xRefTable.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if s := d.NameEntry("S"); s != nil && *s == "GTS_PDFA1" {
+			return nil
+		}
+	}
+
+	sd, err := xRefTable.NewStreamDictForBuf(sRGBProfile())
+	if err != nil {
+		return err
+	}
+	sd.InsertInt("N", 3)
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	ir, err := xRefTable.IndRefForNewObject(*sd)
+	if err != nil {
+		return err
+	}
+
+	d := types.Dict(map[string]types.Object{
+		"Type":                      types.Name("OutputIntent"),
+		"S":                         types.Name("GTS_PDFA1"),
+		"OutputConditionIdentifier": types.StringLiteral(sRGBOutputCondition),
+		"Info":                      types.StringLiteral(sRGBOutputCondition),
+		"RegistryName":              types.StringLiteral("http://www.color.org"),
+		"DestOutputProfile":         *ir,
+	})
+
+	rootDict["OutputIntents"] = append(a, d)
+
+	return nil
+}
+
+// ensurePDFAMetadata sets the catalog metadata to an XMP packet derived from the info dict.
+func ensurePDFAMetadata(xRefTable *model.XRefTable) error {
+	rootDict, err := xRefTable.Catalog()
+	if err != nil {
+		return err
+	}
+
+	bb, err := xRefTable.PDFAMetadata(model.PDFAPart(xRefTable.PDFAProfile))
+	if err != nil {
+		return err
+	}
+
+	// PDF/A metadata streams are unfiltered.
+	sd := types.StreamDict{Dict: types.NewDict(), Content: bb}
+	sd.InsertName("Type", "Metadata")
+	sd.InsertName("Subtype", "XML")
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	if ir, ok := rootDict["Metadata"].(types.IndirectRef); ok {
+		if entry, found := xRefTable.FindTableEntryForIndRef(&ir); found && !entry.Free {
+			entry.Object = sd
+			return nil
+		}
+	}
+
+	ir, err := xRefTable.IndRefForNewObject(sd)
+	if err != nil {
+		return err
+	}
+	rootDict["Metadata"] = *ir
+
+	return nil
+}
+
+// fileSpecs returns the values of the embedded files name tree rooted at o.
+func fileSpecs(xRefTable *model.XRefTable, o types.Object, specs []types.Object) []types.Object {
+	d, err := xRefTable.DereferenceDict(o)
+	if err != nil || d == nil {
+		return specs
+	}
+	names := d.ArrayEntry("Names")
+	for i := 1; i < len(names); i += 2 {
+		specs = append(specs, names[i])
+	}
+	for _, kid := range d.ArrayEntry("Kids") {
+		specs = fileSpecs(xRefTable, kid, specs)
+	}
+	return specs
+}
+
+// fixEmbeddedFileSpecs completes the file specifications of embedded files as required by PDF/A-3.
+func fixEmbeddedFileSpecs(xRefTable *model.XRefTable, rootDict types.Dict) {
+	names, err := xRefTable.DereferenceDict(rootDict["Names"])
+	if err != nil || names == nil {
+		return
+	}
+
+	for _, o := range fileSpecs(xRefTable, names["EmbeddedFiles"], nil) {
+		d, err := xRefTable.DereferenceDict(o)
+		if err != nil || d == nil {
+			continue
+		}
+
+		if _, found := d.Find("UF"); !found {
+			if f, found := d.Find("F"); found {
+				d["UF"] = f
+			}
+		}
+		if _, found := d.Find("AFRelationship"); !found {
+			d.InsertName("AFRelationship", "Unspecified")
+		}
+
+		ef, err := xRefTable.DereferenceDict(d["EF"])
+		if err != nil || ef == nil {
+			continue
+		}
+		sd, _, err := xRefTable.DereferenceStreamDict(ef["F"])
+		if err != nil || sd == nil {
+			continue
+		}
+		if _, found := sd.Find("Subtype"); !found {
+			sd.InsertName("Subtype", "application/octet-stream")
+		}
+		params, err := xRefTable.DereferenceDict(sd.Dict["Params"])
+		if err != nil {
+			continue
+		}
+		if params == nil {
+			params = types.NewDict()
+			sd.Dict["Params"] = params
+		}
+		if _, found := params.Find("ModDate"); !found {
+			params.InsertString("ModDate", types.DateString(time.Now()))
+		}
+	}
+}
@@ -0,0 +1,153 @@
+/*
+	Copyright 2025 The pdfcpu Authors.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+		http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package primitives
+
+import (
+	"fmt"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/barcode"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/format"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Barcode is a positioned QR code, DataMatrix, Code 128 or EAN-13 barcode within content.
+type Barcode struct {
+	pdf             *PDF
+	content         *Content
+	Type            string // qr, qr-l, qr-m, qr-q, qr-h, datamatrix, code128, ean13
+	symbology       barcode.Symbology
+	Value           string     // content supporting %p, %P and timestamps
+	Position        [2]float64 `json:"pos"` // x,y
+	x, y            float64
+	Dx, Dy          float64
+	Anchor          string
+	anchor          types.Anchor
+	anchored        bool
+	Width           float64
+	Height          float64
+	Color           string `json:"col"`
+	col             *color.SimpleColor
+	BackgroundColor string `json:"bgCol"`
+	bgCol           *color.SimpleColor
+	Rotation        float64 `json:"rot"`
+	Hide            bool
+}
+
+func (bc *Barcode) validate() error {
+
+	bc.x = bc.Position[0]
+	bc.y = bc.Position[1]
+
+	if bc.Value == "" {
+		return errors.New("pdfcpu: barcode: missing \"value\"")
+	}
+
+	sym, err := barcode.ParseSymbology(bc.Type)
+	if err != nil {
+		return err
+	}
+	bc.symbology = sym
+
+	if bc.Width < 0 || bc.Height < 0 {
+		return errors.New("pdfcpu: barcode: width and height must not be negative")
+	}
+
+	if bc.Anchor != "" {
+		if bc.Position[0] != 0 || bc.Position[1] != 0 {
+			return errors.New("pdfcpu: Please supply \"pos\" or \"anchor\"")
+		}
+		a, err := types.ParseAnchor(bc.Anchor)
+		if err != nil {
+			return err
+		}
+		bc.anchor = a
+		bc.anchored = true
+	}
+
+	bc.col = &color.Black
+	if bc.Color != "" {
+		sc, err := bc.pdf.parseColor(bc.Color)
+		if err != nil {
+			return err
+		}
+		bc.col = sc
+	}
+
+	if bc.BackgroundColor != "" {
+		sc, err := bc.pdf.parseColor(bc.BackgroundColor)
+		if err != nil {
+			return err
+		}
+		bc.bgCol = sc
+	}
+
+	return nil
+}
+
+// dim returns the rendered dimensions of c preserving its aspect ratio for any missing dimension.
+// Without width and height each module is rendered as a square of 1 point.
+func (bc *Barcode) dim(c *barcode.Code) (float64, float64) {
+	w, h := bc.Width, bc.Height
+	cw, ch := float64(c.Width()), float64(c.Height())
+	switch {
+	case w == 0 && h == 0:
+		return cw, ch
+	case w == 0:
+		return h * cw / ch, h
+	case h == 0:
+		return w, w * ch / cw
+	}
+	return w, h
+}
+
+func (bc *Barcode) render(p *model.Page, pageNr int) error {
+	pdf := bc.pdf
+
+	t, _ := format.Text(bc.Value, pdf.TimestampFormat, pageNr, pdf.pageCount())
+
+	c, err := bc.symbology.Encode(t)
+	if err != nil {
+		return err
+	}
+
+	w, h := bc.dim(c)
+
+	// Reuse the positioning logic of simple boxes.
+	sb := &SimpleBox{
+		content:  bc.content,
+		x:        bc.x,
+		y:        bc.y,
+		Dx:       bc.Dx,
+		Dy:       bc.Dy,
+		anchor:   bc.anchor,
+		anchored: bc.anchored,
+		Width:    w,
+		Height:   h,
+		Rotation: bc.Rotation,
+	}
+
+	m, r := sb.calcTransform(0, 0, 0, 0, 0)
+
+	fmt.Fprintf(p.Buf, "q %.5f %.5f %.5f %.5f %.5f %.5f cm ", m[0][0], m[0][1], m[1][0], m[1][1], m[2][0], m[2][1])
+	c.Draw(p.Buf, r, *bc.col, bc.bgCol)
+	fmt.Fprint(p.Buf, "Q ")
+
+	return nil
+}
@@ -51,6 +51,7 @@ type Content struct {
 	ImageBoxPool    map[string]*ImageBox  `json:"images"`
 	Tables          []*Table              `json:"table"`
 	TablePool       map[string]*Table     `json:"tables"`
+	Barcodes        []*Barcode            `json:"barcode"`
 	// Form elements
 	TextFields        []*TextField           `json:"textfield"`        // input text fields with optional label
 	DateFields        []*DateField           `json:"datefield"`        // input date fields with optional label
@@ -158,6 +159,9 @@ func (c *Content) validatePrimitives(s string) error {
 	if len(c.Tables) > 0 {
 		return errors.Errorf("pdfcpu: \"table\" %s", s)
 	}
+	if len(c.Barcodes) > 0 {
+		return errors.Errorf("pdfcpu: \"barcode\" %s", s)
+	}
 	return nil
 }
 
@@ -214,6 +218,18 @@ func (c *Content) validateBars() error {
 	return nil
 }
 
+func (c *Content) validateBarcodes() error {
+	// barcodes
+	for _, bc := range c.Barcodes {
+		bc.pdf = c.page.pdf
+		bc.content = c
+		if err := bc.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *Content) validateSimpleBoxPool() error {
 	// boxes
 	for _, sb := range c.SimpleBoxPool {
@@ -450,6 +466,10 @@ func (c *Content) validate() error {
 		return err
 	}
 
+	if err := c.validateBarcodes(); err != nil {
+		return err
+	}
+
 	if err := c.validateTextFields(); err != nil {
 		return err
 	}
@@ -1129,6 +1149,18 @@ func (c *Content) renderTables(p *model.Page, pageNr int, fonts model.FontMap) e
 	return nil
 }
 
+func (c *Content) renderBarcodes(p *model.Page, pageNr int) error {
+	for _, bc := range c.Barcodes {
+		if bc.Hide {
+			continue
+		}
+		if err := bc.render(p, pageNr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *Content) renderTextFields(p *model.Page, pageNr int, fonts model.FontMap) error {
 	for _, tf := range c.TextFields {
 		if tf.Hide {
@@ -1256,7 +1288,11 @@ func (c *Content) renderPrimitives(p *model.Page, pageNr int, fonts model.FontMa
 		return err
 	}
 
-	return c.renderTables(p, pageNr, fonts)
+	if err := c.renderTables(p, pageNr, fonts); err != nil {
+		return err
+	}
+
+	return c.renderBarcodes(p, pageNr)
 }
 
 func (c *Content) renderFormPrimitives(p *model.Page, pageNr int, fonts model.FontMap) error {
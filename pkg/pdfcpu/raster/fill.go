@@ -0,0 +1,203 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package raster
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// The number of sample lines per pixel row.
+// Horizontal coverage is computed exactly.
+const subSamples = 5
+
+type edge struct {
+	x0, y0, x1, y1 float64 // y0 < y1
+	dxdy           float64
+	dir            int
+}
+
+type crossing struct {
+	x   float64
+	dir int
+}
+
+func edges(pl []Polyline) []edge {
+	var ee []edge
+	for _, p := range pl {
+		n := len(p.Points)
+		for i := 0; i < n; i++ {
+			// All subpaths are implicitly closed for filling.
+			a, b := p.Points[i], p.Points[(i+1)%n]
+			if a.Y == b.Y || math.IsNaN(a.Y) || math.IsNaN(b.Y) {
+				continue
+			}
+			e := edge{x0: a.X, y0: a.Y, x1: b.X, y1: b.Y, dir: 1}
+			if a.Y > b.Y {
+				e = edge{x0: b.X, y0: b.Y, x1: a.X, y1: a.Y, dir: -1}
+			}
+			e.dxdy = (e.x1 - e.x0) / (e.y1 - e.y0)
+			ee = append(ee, e)
+		}
+	}
+	return ee
+}
+
+// Bounds returns the smallest integer rectangle containing all points of pl.
+func Bounds(pl []Polyline) image.Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range pl {
+		for _, pt := range p.Points {
+			minX, minY = math.Min(minX, pt.X), math.Min(minY, pt.Y)
+			maxX, maxY = math.Max(maxX, pt.X), math.Max(maxY, pt.Y)
+		}
+	}
+	if minX > maxX || minY > maxY {
+		return image.Rectangle{}
+	}
+	// Guard against overflow for huge coordinates.
+	clamp := func(f float64) int {
+		return int(math.Max(-1<<24, math.Min(1<<24, f)))
+	}
+	return image.Rect(clamp(math.Floor(minX)), clamp(math.Floor(minY)), clamp(math.Ceil(maxX))+1, clamp(math.Ceil(maxY))+1)
+}
+
+// addSpan accumulates the coverage of the horizontal span [x0,x1) into acc relative to minX.
+func addSpan(acc []float32, minX int, x0, x1 float64, weight float32) {
+	w := float64(len(acc) - 2)
+	x0 = math.Max(0, math.Min(w, x0-float64(minX)))
+	x1 = math.Max(0, math.Min(w, x1-float64(minX)))
+	if x1 <= x0 {
+		return
+	}
+	i0, i1 := int(x0), int(x1)
+	f0, f1 := float32(x0-float64(i0)), float32(x1-float64(i1))
+	acc[i0] += (1 - f0) * weight
+	acc[i0+1] += f0 * weight
+	acc[i1] -= (1 - f1) * weight
+	acc[i1+1] -= f1 * weight
+}
+
+// Fill returns the coverage mask of the area enclosed by pl restricted to bounds.
+// The mask is nil if there is nothing to paint.
+func Fill(pl []Polyline, evenOdd bool, bounds image.Rectangle) *image.Alpha {
+	r := Bounds(pl).Intersect(bounds)
+	if r.Empty() {
+		return nil
+	}
+
+	ee := edges(pl)
+	if len(ee) == 0 {
+		return nil
+	}
+	sort.Slice(ee, func(i, j int) bool { return ee[i].y0 < ee[j].y0 })
+
+	mask := image.NewAlpha(r)
+	acc := make([]float32, r.Dx()+2)
+	weight := float32(1) / subSamples
+
+	var (
+		active []int
+		cc     []crossing
+		next   int
+	)
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for s := 0; s < subSamples; s++ {
+			sy := float64(y) + (float64(s)+0.5)/subSamples
+
+			for next < len(ee) && ee[next].y0 <= sy {
+				active = append(active, next)
+				next++
+			}
+
+			cc = cc[:0]
+			j := 0
+			for _, i := range active {
+				e := &ee[i]
+				if e.y1 <= sy {
+					continue
+				}
+				active[j] = i
+				j++
+				if e.y0 <= sy {
+					cc = append(cc, crossing{x: e.x0 + (sy-e.y0)*e.dxdy, dir: e.dir})
+				}
+			}
+			active = active[:j]
+
+			if len(cc) < 2 {
+				continue
+			}
+			sort.Slice(cc, func(i, j int) bool { return cc[i].x < cc[j].x })
+
+			wind := 0
+			for i, c := range cc[:len(cc)-1] {
+				if evenOdd {
+					wind ^= 1
+				} else {
+					wind += c.dir
+				}
+				if wind != 0 {
+					addSpan(acc, r.Min.X, c.x, cc[i+1].x, weight)
+				}
+			}
+		}
+
+		// Resolve the accumulated coverage of this row.
+		var cov float32
+		row := mask.Pix[(y-r.Min.Y)*mask.Stride:]
+		for x := 0; x < r.Dx(); x++ {
+			cov += acc[x]
+			a := cov
+			if a < 0 {
+				a = 0
+			}
+			if a > 1 {
+				a = 1
+			}
+			row[x] = uint8(a*255 + 0.5)
+		}
+		for i := range acc {
+			acc[i] = 0
+		}
+	}
+
+	return mask
+}
+
+// Intersect returns the pointwise product of the masks m1 and m2.
+// A nil mask covers everything.
+func Intersect(m1, m2 *image.Alpha) *image.Alpha {
+	if m1 == nil {
+		return m2
+	}
+	if m2 == nil {
+		return m1
+	}
+	r := m1.Rect.Intersect(m2.Rect)
+	m := image.NewAlpha(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		i1, i2, i := m1.PixOffset(r.Min.X, y), m2.PixOffset(r.Min.X, y), m.PixOffset(r.Min.X, y)
+		for x := 0; x < r.Dx(); x++ {
+			m.Pix[i+x] = uint8((uint32(m1.Pix[i1+x])*uint32(m2.Pix[i2+x]) + 127) / 255)
+		}
+	}
+	return m
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package raster provides an anti-aliasing scanline rasterizer for filling and stroking paths.
+package raster
+
+import (
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Op is a path construction operation.
+type Op uint8
+
+// Path construction operations.
+const (
+	MoveTo Op = iota
+	LineTo
+	CubeTo
+	Close
+)
+
+type segment struct {
+	op  Op
+	pts [3]types.Point
+}
+
+// Path is a sequence of subpaths made of straight lines and cubic Bézier curves.
+type Path struct {
+	segs       []segment
+	start, cur types.Point
+}
+
+// Polyline is a flattened subpath.
+type Polyline struct {
+	Points []types.Point
+	Closed bool
+}
+
+// Empty returns true if p has no segments.
+func (p *Path) Empty() bool {
+	return p == nil || len(p.segs) == 0
+}
+
+// CurrentPoint returns the current point of p.
+func (p *Path) CurrentPoint() types.Point {
+	return p.cur
+}
+
+// MoveTo begins a new subpath at (x,y).
+func (p *Path) MoveTo(x, y float64) {
+	pt := types.Point{X: x, Y: y}
+	p.segs = append(p.segs, segment{op: MoveTo, pts: [3]types.Point{pt}})
+	p.start, p.cur = pt, pt
+}
+
+// LineTo appends a straight line from the current point to (x,y).
+func (p *Path) LineTo(x, y float64) {
+	pt := types.Point{X: x, Y: y}
+	p.segs = append(p.segs, segment{op: LineTo, pts: [3]types.Point{pt}})
+	p.cur = pt
+}
+
+// CubeTo appends a cubic Bézier curve from the current point to (x3,y3) using the control points (x1,y1) and (x2,y2).
+func (p *Path) CubeTo(x1, y1, x2, y2, x3, y3 float64) {
+	p.segs = append(p.segs, segment{op: CubeTo, pts: [3]types.Point{{X: x1, Y: y1}, {X: x2, Y: y2}, {X: x3, Y: y3}}})
+	p.cur = types.Point{X: x3, Y: y3}
+}
+
+// QuadTo appends a quadratic Bézier curve from the current point to (x2,y2) using the control point (x1,y1).
+func (p *Path) QuadTo(x1, y1, x2, y2 float64) {
+	c := p.cur
+	p.CubeTo(
+		c.X+2*(x1-c.X)/3, c.Y+2*(y1-c.Y)/3,
+		x2+2*(x1-x2)/3, y2+2*(y1-y2)/3,
+		x2, y2)
+}
+
+// Rect appends a closed rectangular subpath.
+func (p *Path) Rect(x, y, w, h float64) {
+	p.MoveTo(x, y)
+	p.LineTo(x+w, y)
+	p.LineTo(x+w, y+h)
+	p.LineTo(x, y+h)
+	p.Close()
+}
+
+// Close closes the current subpath.
+func (p *Path) Close() {
+	p.segs = append(p.segs, segment{op: Close})
+	p.cur = p.start
+}
+
+// Append appends all subpaths of q to p.
+func (p *Path) Append(q *Path) {
+	if q.Empty() {
+		return
+	}
+	p.segs = append(p.segs, q.segs...)
+	p.start, p.cur = q.start, q.cur
+}
+
+// Transform returns a copy of p with m applied to all points.
+func (p *Path) Transform(m matrix.Matrix) *Path {
+	q := &Path{segs: make([]segment, len(p.segs))}
+	for i, s := range p.segs {
+		q.segs[i].op = s.op
+		for j := range s.pts {
+			q.segs[i].pts[j] = m.Transform(s.pts[j])
+		}
+	}
+	q.start, q.cur = m.Transform(p.start), m.Transform(p.cur)
+	return q
+}
+
+func flattenCubic(pp []types.Point, p0, p1, p2, p3 types.Point, tol float64) []types.Point {
+	// The distance between a cubic and its n segment approximation is bounded by max|B''| / (8n²).
+	dd := math.Max(
+		math.Hypot(p0.X-2*p1.X+p2.X, p0.Y-2*p1.Y+p2.Y),
+		math.Hypot(p1.X-2*p2.X+p3.X, p1.Y-2*p2.Y+p3.Y))
+	n := int(math.Ceil(math.Sqrt(0.75 * dd / tol)))
+	if n < 1 {
+		n = 1
+	}
+	if n > 1000 {
+		n = 1000
+	}
+	for i := 1; i <= n; i++ {
+		t := float64(i) / float64(n)
+		u := 1 - t
+		a, b, c, d := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+		pp = append(pp, types.Point{
+			X: a*p0.X + b*p1.X + c*p2.X + d*p3.X,
+			Y: a*p0.Y + b*p1.Y + c*p2.Y + d*p3.Y,
+		})
+	}
+	return pp
+}
+
+// Flatten approximates all subpaths of p by polylines within tolerance tol.
+func (p *Path) Flatten(tol float64) []Polyline {
+	var (
+		pl  []Polyline
+		cur *Polyline
+	)
+
+	if tol <= 0 {
+		tol = 0.1
+	}
+
+	begin := func(pt types.Point) {
+		pl = append(pl, Polyline{Points: []types.Point{pt}})
+		cur = &pl[len(pl)-1]
+	}
+
+	last := func() types.Point {
+		return cur.Points[len(cur.Points)-1]
+	}
+
+	for _, s := range p.segs {
+		switch s.op {
+
+		case MoveTo:
+			begin(s.pts[0])
+
+		case LineTo:
+			if cur == nil {
+				begin(s.pts[0])
+				continue
+			}
+			cur.Points = append(cur.Points, s.pts[0])
+
+		case CubeTo:
+			if cur == nil {
+				begin(s.pts[0])
+			}
+			cur.Points = flattenCubic(cur.Points, last(), s.pts[0], s.pts[1], s.pts[2], tol)
+
+		case Close:
+			if cur == nil {
+				continue
+			}
+			cur.Closed = true
+			// Any further segment starts a new subpath at the same point.
+			begin(cur.Points[0])
+		}
+	}
+
+	// Drop subpaths consisting of a lone move.
+	res := pl[:0]
+	for _, p := range pl {
+		if len(p.Points) > 1 || p.Closed {
+			res = append(res, p)
+		}
+	}
+
+	return res
+}
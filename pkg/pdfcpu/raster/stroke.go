@@ -0,0 +1,307 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package raster
+
+import (
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// See 8.4.3 Details of Graphics State Parameters
+
+// Cap is a line cap style.
+type Cap int
+
+// Line cap styles.
+const (
+	ButtCap Cap = iota
+	RoundCap
+	SquareCap
+)
+
+// Join is a line join style.
+type Join int
+
+// Line join styles.
+const (
+	MiterJoin Join = iota
+	RoundJoin
+	BevelJoin
+)
+
+// StrokeStyle defines the shape of stroked lines.
+type StrokeStyle struct {
+	Width      float64
+	Cap        Cap
+	Join       Join
+	MiterLimit float64
+	Dash       []float64
+	DashPhase  float64
+}
+
+// stroker collects the polygons making up the outline of a stroke.
+// All polygons share the same orientation so that the union is obtained by filling with the nonzero winding rule.
+type stroker struct {
+	StrokeStyle
+	hw  float64 // half width
+	tol float64
+	pl  []Polyline
+}
+
+func sub(p, q types.Point) types.Point {
+	return types.Point{X: p.X - q.X, Y: p.Y - q.Y}
+}
+
+func add(p, q types.Point) types.Point {
+	return types.Point{X: p.X + q.X, Y: p.Y + q.Y}
+}
+
+func scale(p types.Point, f float64) types.Point {
+	return types.Point{X: p.X * f, Y: p.Y * f}
+}
+
+func unit(p types.Point) types.Point {
+	l := math.Hypot(p.X, p.Y)
+	if l == 0 {
+		return types.Point{X: 1}
+	}
+	return scale(p, 1/l)
+}
+
+// normal returns the left normal of the unit vector d.
+func normal(d types.Point) types.Point {
+	return types.Point{X: -d.Y, Y: d.X}
+}
+
+func (s *stroker) polygon(pp ...types.Point) {
+	var a float64
+	for i := range pp {
+		p, q := pp[i], pp[(i+1)%len(pp)]
+		a += p.X*q.Y - q.X*p.Y
+	}
+	if a < 0 {
+		for i, j := 0, len(pp)-1; i < j; i, j = i+1, j-1 {
+			pp[i], pp[j] = pp[j], pp[i]
+		}
+	}
+	s.pl = append(s.pl, Polyline{Points: pp, Closed: true})
+}
+
+func (s *stroker) circle(c types.Point) {
+	n := 8
+	if s.hw > s.tol {
+		n = int(math.Ceil(2 * math.Pi / (2 * math.Acos(1-s.tol/s.hw))))
+	}
+	n = max(8, min(n, 256))
+	pp := make([]types.Point, n)
+	for i := range pp {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		pp[i] = types.Point{X: c.X + s.hw*math.Cos(a), Y: c.Y + s.hw*math.Sin(a)}
+	}
+	s.polygon(pp...)
+}
+
+func (s *stroker) segment(a, b types.Point) {
+	n := scale(normal(unit(sub(b, a))), s.hw)
+	s.polygon(add(a, n), add(b, n), sub(b, n), sub(a, n))
+}
+
+// cap adds the line cap at p for a line leaving p in direction d.
+func (s *stroker) cap(p, d types.Point) {
+	switch s.Cap {
+	case RoundCap:
+		s.circle(p)
+	case SquareCap:
+		n, e := scale(normal(d), s.hw), scale(d, -s.hw)
+		s.polygon(add(p, n), add(add(p, n), e), sub(add(p, e), n), sub(p, n))
+	}
+}
+
+// join adds the line join at p between a line arriving in direction d1 and a line leaving in direction d2.
+func (s *stroker) join(p, d1, d2 types.Point) {
+	cross := d1.X*d2.Y - d1.Y*d2.X
+	dot := d1.X*d2.X + d1.Y*d2.Y
+	if math.Abs(cross) < 1e-9 && dot > 0 {
+		return
+	}
+
+	if s.Join == RoundJoin {
+		s.circle(p)
+		return
+	}
+
+	// The outer side of the turn.
+	sign := 1.
+	if cross > 0 {
+		sign = -1
+	}
+	n1, n2 := scale(normal(d1), sign*s.hw), scale(normal(d2), sign*s.hw)
+	a, b := add(p, n1), add(p, n2)
+
+	if s.Join == MiterJoin {
+		// The miter length ratio is 1/sin(φ/2) where φ is the angle between the segments.
+		if sin := math.Sqrt((1 + dot) / 2); sin > 1e-9 && 1/sin <= s.MiterLimit {
+			m := add(p, scale(unit(add(n1, n2)), s.hw/sin))
+			s.polygon(p, a, m, b)
+			return
+		}
+	}
+
+	s.polygon(p, a, b)
+}
+
+func dedup(pp []types.Point) []types.Point {
+	res := []types.Point{pp[0]}
+	for _, p := range pp[1:] {
+		if q := res[len(res)-1]; math.Abs(p.X-q.X) > 1e-9 || math.Abs(p.Y-q.Y) > 1e-9 {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+func (s *stroker) polyline(p Polyline) {
+	pp := dedup(p.Points)
+
+	if p.Closed && len(pp) > 2 && pp[0] == pp[len(pp)-1] {
+		pp = pp[:len(pp)-1]
+	}
+
+	if len(pp) == 1 {
+		// Zero length subpath.
+		if s.Cap != ButtCap {
+			s.cap(pp[0], types.Point{X: -1})
+			if s.Cap == SquareCap {
+				s.cap(pp[0], types.Point{X: 1})
+			}
+		}
+		return
+	}
+
+	n := len(pp)
+	for i := 0; i < n-1; i++ {
+		s.segment(pp[i], pp[i+1])
+	}
+	for i := 1; i < n-1; i++ {
+		s.join(pp[i], unit(sub(pp[i], pp[i-1])), unit(sub(pp[i+1], pp[i])))
+	}
+
+	if p.Closed && n > 2 {
+		s.segment(pp[n-1], pp[0])
+		s.join(pp[n-1], unit(sub(pp[n-1], pp[n-2])), unit(sub(pp[0], pp[n-1])))
+		s.join(pp[0], unit(sub(pp[0], pp[n-1])), unit(sub(pp[1], pp[0])))
+		return
+	}
+
+	s.cap(pp[0], unit(sub(pp[1], pp[0])))
+	s.cap(pp[n-1], unit(sub(pp[n-2], pp[n-1])))
+}
+
+// dash splits pl into the dashes of pattern starting at phase.
+func dash(pl []Polyline, pattern []float64, phase float64) []Polyline {
+	var total float64
+	for _, d := range pattern {
+		if d < 0 {
+			return pl
+		}
+		total += d
+	}
+	if total <= 0 {
+		return pl
+	}
+
+	var res []Polyline
+
+	for _, p := range pl {
+		pp := p.Points
+		if p.Closed && len(pp) > 1 {
+			pp = append(append([]types.Point{}, pp...), pp[0])
+		}
+
+		// Locate the phase within the pattern.
+		i, rem := 0, math.Mod(phase, total)
+		if rem < 0 {
+			rem += total
+		}
+		for rem >= pattern[i] {
+			rem -= pattern[i]
+			i = (i + 1) % len(pattern)
+		}
+		left := pattern[i] - rem
+		on := i%2 == 0
+
+		var cur []types.Point
+		if on {
+			cur = []types.Point{pp[0]}
+		}
+
+		for j := 0; j+1 < len(pp); j++ {
+			a, b := pp[j], pp[j+1]
+			l := math.Hypot(b.X-a.X, b.Y-a.Y)
+			pos := 0.
+			for l-pos > left {
+				pos += left
+				q := add(a, scale(sub(b, a), pos/l))
+				if on {
+					res = append(res, Polyline{Points: append(cur, q)})
+					cur = nil
+				} else {
+					cur = []types.Point{q}
+				}
+				on = !on
+				i = (i + 1) % len(pattern)
+				left = pattern[i]
+			}
+			left -= l - pos
+			if on {
+				cur = append(cur, b)
+			}
+		}
+
+		if on && len(cur) > 0 {
+			res = append(res, Polyline{Points: cur})
+		}
+	}
+
+	return res
+}
+
+// Stroke returns the polygons making up the outline of pl stroked using style.
+// Curves are approximated within tolerance tol.
+// The result is meant to be filled using the nonzero winding number rule.
+func Stroke(pl []Polyline, style StrokeStyle, tol float64) []Polyline {
+	s := &stroker{StrokeStyle: style, hw: style.Width / 2, tol: tol}
+	if s.hw <= 0 {
+		return nil
+	}
+	if s.MiterLimit < 1 {
+		s.MiterLimit = 10
+	}
+
+	if len(style.Dash) > 0 {
+		pl = dash(pl, style.Dash, style.DashPhase)
+	}
+
+	for _, p := range pl {
+		if len(p.Points) > 0 {
+			s.polyline(p)
+		}
+	}
+
+	return s.pl
+}
@@ -107,10 +107,17 @@ func ReadWithContext(c context.Context, rs io.ReadSeeker, conf *model.Configurat
 		return nil, errors.Wrap(err, "Read: xRefTable failed")
 	}
 
-	// Make all objects explicitly available (load into memory) in corresponding xRefTable entries.
-	// Also decode any involved object streams.
-	if err = dereferenceXRefTable(c, ctx, conf); err != nil {
-		return nil, err
+	if ctx.Conf.LazyRead() {
+		// Parse objects on first access.
+		if err = readLazy(c, ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		// Make all objects explicitly available (load into memory) in corresponding xRefTable entries.
+		// Also decode any involved object streams.
+		if err = dereferenceXRefTable(c, ctx, conf); err != nil {
+			return nil, err
+		}
 	}
 
 	// Some PDFWriters write an incorrect Size into trailer.
@@ -2955,7 +2962,7 @@ func handleUnencryptedFile(ctx *model.Context) error {
 
 	// Encrypt subcommand found.
 
-	if ctx.OwnerPW == "" {
+	if ctx.OwnerPW == "" && len(ctx.Recipients) == 0 {
 		return errors.New("pdfcpu: please provide owner password and optional user password")
 	}
 
@@ -2994,6 +3001,10 @@ func setupEncryptionKey(ctx *model.Context, d types.Dict) (err error) {
 		return err
 	}
 
+	if pubSec(ctx.E) {
+		return setupPubSecEncryptionKey(ctx)
+	}
+
 	if ctx.E.ID, err = ctx.IDFirstElement(); err != nil {
 		return err
 	}
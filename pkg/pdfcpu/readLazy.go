@@ -0,0 +1,234 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"container/list"
+	"context"
+	"hash/fnv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// DefaultObjectCacheSize is the number of lazily read objects kept in memory unless configured otherwise.
+const DefaultObjectCacheSize = 1000
+
+// lazyLoader parses objects from the input read seeker on first access.
+// Only the most recently used objects are kept in memory, the others are parsed again when needed.
+// Objects modified in memory are never evicted.
+type lazyLoader struct {
+	c      context.Context
+	ctx    *model.Context
+	size   int
+	lru    *list.List // object numbers, most recently used first
+	elems  map[int]*list.Element
+	sums   map[int]uint64 // fingerprints of cached objects as parsed
+	pinned map[int]bool   // modified objects
+}
+
+func newLazyLoader(c context.Context, ctx *model.Context, size int) *lazyLoader {
+	if size <= 0 {
+		size = DefaultObjectCacheSize
+	}
+	return &lazyLoader{
+		c:      c,
+		ctx:    ctx,
+		size:   size,
+		lru:    list.New(),
+		elems:  map[int]*list.Element{},
+		sums:   map[int]uint64{},
+		pinned: map[int]bool{},
+	}
+}
+
+// fingerprint returns a hash of o's serialization including any stream content.
+func fingerprint(o types.Object) uint64 {
+	h := fnv.New64a()
+	if sd, ok := o.(types.StreamDict); ok {
+		h.Write([]byte(sd.Dict.PDFString()))
+		h.Write(sd.Raw)
+		h.Write(sd.Content)
+	} else {
+		h.Write([]byte(o.PDFString()))
+	}
+	return h.Sum64()
+}
+
+// Resident returns the number of lazily read objects currently held in memory.
+func (l *lazyLoader) Resident() int {
+	return l.lru.Len() + len(l.pinned)
+}
+
+// Load parses the object for entry objNr unless cached.
+func (l *lazyLoader) Load(objNr int, entry *model.XRefTableEntry) error {
+	if entry.Object != nil {
+		// Objects read along with the cross reference table are not tracked.
+		if e, ok := l.elems[objNr]; ok {
+			l.lru.MoveToFront(e)
+		}
+		return nil
+	}
+
+	if err := l.c.Err(); err != nil {
+		return err
+	}
+
+	if log.ReadEnabled() {
+		log.Read.Printf("lazyLoader: loading obj#%d\n", objNr)
+	}
+
+	o, err := l.object(objNr, entry)
+	if err != nil || o == nil {
+		return err
+	}
+
+	entry.Object = o
+	l.sums[objNr] = fingerprint(o)
+
+	if e, ok := l.elems[objNr]; ok {
+		l.lru.MoveToFront(e)
+		return nil
+	}
+
+	l.elems[objNr] = l.lru.PushFront(objNr)
+
+	for l.lru.Len() > l.size {
+		l.evict(l.lru.Back())
+	}
+
+	return nil
+}
+
+// evict drops the object of e from memory unless it has been modified since parsing.
+func (l *lazyLoader) evict(e *list.Element) {
+	objNr := l.lru.Remove(e).(int)
+	delete(l.elems, objNr)
+
+	sum := l.sums[objNr]
+	delete(l.sums, objNr)
+
+	entry, ok := l.ctx.Table[objNr]
+	if !ok || entry.Object == nil {
+		return
+	}
+
+	if fingerprint(entry.Object) != sum {
+		// Parsing again would lose the modification.
+		l.pinned[objNr] = true
+		return
+	}
+
+	entry.Object = nil
+}
+
+func (l *lazyLoader) object(objNr int, entry *model.XRefTableEntry) (types.Object, error) {
+	// Compressed entries keep their object stream coordinates so they may be loaded again after eviction.
+	if entry.ObjectStream != nil {
+		return l.compressedObject(entry)
+	}
+
+	if entry.Offset == nil || *entry.Offset == 0 {
+		return nil, nil
+	}
+
+	if l.ctx.Read.IsObjectStreamObject(objNr) {
+		if err := decodeObjectStream(l.c, l.ctx, objNr); err != nil {
+			return nil, err
+		}
+		return entry.Object, nil
+	}
+
+	o, err := ParseObjectWithContext(l.c, l.ctx, *entry.Offset, objNr, *entry.Generation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "lazyLoader: problem dereferencing object %d", objNr)
+	}
+
+	sd, ok := o.(types.StreamDict)
+	if !ok {
+		return o, nil
+	}
+
+	if err := loadEncodedStreamContent(l.c, l.ctx, &sd, false); err != nil {
+		return nil, errors.Wrapf(err, "lazyLoader: problem dereferencing stream %d", objNr)
+	}
+
+	if err := saveDecodedStreamContent(l.ctx, &sd, objNr, *entry.Generation, false); err != nil {
+		return nil, err
+	}
+
+	return sd, nil
+}
+
+func (l *lazyLoader) compressedObject(entry *model.XRefTableEntry) (types.Object, error) {
+	osEntry, ok := l.ctx.Find(*entry.ObjectStream)
+	if !ok {
+		return nil, errors.Errorf("lazyLoader: problem dereferencing object stream %d, no xref table entry", *entry.ObjectStream)
+	}
+
+	osd, ok := osEntry.Object.(types.ObjectStreamDict)
+	if !ok {
+		return nil, errors.Errorf("lazyLoader: problem dereferencing object stream %d, no object stream", *entry.ObjectStream)
+	}
+
+	o, err := osd.IndexedObject(*entry.ObjectStreamInd)
+	if err != nil {
+		return nil, errors.Wrapf(err, "lazyLoader: problem dereferencing object stream %d", *entry.ObjectStream)
+	}
+
+	if lo, ok := o.(types.LazyObjectStreamObject); ok {
+		if o, err = lo.DecodedObject(l.c); err != nil {
+			return nil, err
+		}
+	}
+
+	g := 0
+	entry.Generation = &g
+
+	return o, nil
+}
+
+// readLazy prepares ctx for parsing objects on first access.
+func readLazy(c context.Context, ctx *model.Context) error {
+	if log.ReadEnabled() {
+		log.Read.Println("readLazy: begin")
+	}
+
+	if err := checkForEncryption(c, ctx); err != nil {
+		return err
+	}
+
+	ctx.Loader = newLazyLoader(c, ctx, ctx.Conf.ObjectCacheSize)
+
+	for objNr := range ctx.Table {
+		if objNr > ctx.MaxObjNr {
+			ctx.MaxObjNr = objNr
+		}
+	}
+
+	if err := identifyRootVersion(ctx.XRefTable); err != nil {
+		return err
+	}
+
+	if log.ReadEnabled() {
+		log.Read.Println("readLazy: end")
+	}
+
+	return nil
+}
@@ -0,0 +1,1077 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"math"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	pdffont "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// See 12.5.6.23 Redaction Annotations
+
+// The font used for overlay text.
+const redactFontName = "Helvetica"
+
+// Paths are clipped against this rectangle minus the redacted region.
+const redactInfinity = 1e6
+
+// redaction is a region of a page whose content gets removed.
+type redaction struct {
+	rect        *types.Rectangle   // in default user space
+	fillCol     *color.SimpleColor // nil: transparent
+	overlayText string
+	repeat      bool
+	fontSize    float64 // 0: auto
+	textCol     color.SimpleColor
+	q           int // quadding
+}
+
+// parseDA returns the font size and the fill color of the default appearance string da.
+func parseDA(da string) (float64, color.SimpleColor) {
+	var (
+		fontSize float64
+		col      color.SimpleColor
+	)
+
+	ops, err := content.Parse([]byte(da))
+	if err != nil {
+		return fontSize, col
+	}
+
+	for _, op := range ops {
+		switch op.Operator {
+		case "Tf":
+			if ff, ok := floats(op.Operands, 1); ok {
+				fontSize = ff[0]
+			}
+		case "g":
+			if ff, ok := floats(op.Operands, 1); ok {
+				col = color.SimpleColor{R: float32(ff[0]), G: float32(ff[0]), B: float32(ff[0])}
+			}
+		case "rg":
+			if ff, ok := floats(op.Operands, 3); ok {
+				col = color.SimpleColor{R: float32(ff[0]), G: float32(ff[1]), B: float32(ff[2])}
+			}
+		case "k":
+			if ff, ok := floats(op.Operands, 4); ok {
+				k := 1 - ff[3]
+				col = color.SimpleColor{R: float32((1 - ff[0]) * k), G: float32((1 - ff[1]) * k), B: float32((1 - ff[2]) * k)}
+			}
+		}
+	}
+
+	return fontSize, col
+}
+
+func redactionRects(ctx *model.Context, d types.Dict) ([]*types.Rectangle, error) {
+	qp, err := ctx.DereferenceArray(d["QuadPoints"])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(qp) > 0 && len(qp)%8 == 0 {
+		ff, ok := floats(qp, len(qp))
+		if !ok {
+			return nil, errors.New("pdfcpu: redaction: corrupt QuadPoints")
+		}
+		rr := []*types.Rectangle{}
+		for i := 0; i < len(ff); i += 8 {
+			rr = append(rr, bboxForPoints(
+				types.Point{X: ff[i], Y: ff[i+1]},
+				types.Point{X: ff[i+2], Y: ff[i+3]},
+				types.Point{X: ff[i+4], Y: ff[i+5]},
+				types.Point{X: ff[i+6], Y: ff[i+7]}))
+		}
+		return rr, nil
+	}
+
+	a, err := ctx.DereferenceArray(d["Rect"])
+	if err != nil {
+		return nil, err
+	}
+	ff, ok := floats(a, 4)
+	if !ok || len(a) != 4 {
+		return nil, errors.New("pdfcpu: redaction: corrupt Rect")
+	}
+
+	return []*types.Rectangle{bboxForPoints(types.Point{X: ff[0], Y: ff[1]}, types.Point{X: ff[2], Y: ff[3]})}, nil
+}
+
+// redactionsForAnnot returns the redactions for the Redact annotation d.
+func redactionsForAnnot(ctx *model.Context, d types.Dict) ([]redaction, error) {
+	rr, err := redactionRects(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var r redaction
+
+	if a, err := ctx.DereferenceArray(d["IC"]); err == nil && len(a) == 3 {
+		if ff, ok := floats(a, 3); ok {
+			r.fillCol = &color.SimpleColor{R: float32(ff[0]), G: float32(ff[1]), B: float32(ff[2])}
+		}
+	}
+
+	if o, err := ctx.Dereference(d["OverlayText"]); err == nil && o != nil {
+		if s, err := model.Text(o); err == nil {
+			r.overlayText = s
+		}
+	}
+
+	if b := d.BooleanEntry("Repeat"); b != nil {
+		r.repeat = *b
+	}
+
+	if o, err := ctx.Dereference(d["DA"]); err == nil && o != nil {
+		if s, err := model.Text(o); err == nil {
+			r.fontSize, r.textCol = parseDA(s)
+		}
+	}
+
+	if q := d.IntEntry("Q"); q != nil {
+		r.q = *q
+	}
+
+	var res []redaction
+	for _, rect := range rr {
+		r.rect = rect
+		res = append(res, r)
+	}
+
+	return res, nil
+}
+
+// pageRedactions returns the redactions defined by the Redact annotations of page dict d.
+func pageRedactions(ctx *model.Context, d types.Dict) ([]redaction, error) {
+	annots, err := ctx.DereferenceArray(d["Annots"])
+	if err != nil || annots == nil {
+		return nil, err
+	}
+
+	var rr []redaction
+
+	for _, o := range annots {
+		d1, err := ctx.DereferenceDict(o)
+		if err != nil {
+			return nil, err
+		}
+		if d1 == nil {
+			continue
+		}
+		if st := d1.Subtype(); st == nil || *st != "Redact" {
+			continue
+		}
+		r, err := redactionsForAnnot(ctx, d1)
+		if err != nil {
+			return nil, err
+		}
+		rr = append(rr, r...)
+	}
+
+	return rr, nil
+}
+
+func intersects(r1, r2 *types.Rectangle) bool {
+	return r1.LL.X < r2.UR.X && r2.LL.X < r1.UR.X && r1.LL.Y < r2.UR.Y && r2.LL.Y < r1.UR.Y
+}
+
+func invert(m matrix.Matrix) (matrix.Matrix, bool) {
+	a, b, c, d, e, f := m[0][0], m[0][1], m[1][0], m[1][1], m[2][0], m[2][1]
+	det := a*d - b*c
+	if math.Abs(det) < 1e-12 {
+		return m, false
+	}
+	return matrix.Matrix{
+		{d / det, -b / det, 0},
+		{-c / det, a / det, 0},
+		{(c*f - d*e) / det, (b*e - a*f) / det, 1},
+	}, true
+}
+
+func rectCorners(r *types.Rectangle) []types.Point {
+	return []types.Point{r.LL, {X: r.UR.X, Y: r.LL.Y}, r.UR, {X: r.LL.X, Y: r.UR.Y}}
+}
+
+// redactor rewrites content streams omitting everything located within the redacted regions.
+type redactor struct {
+	*interpreter
+	areas   []*types.Rectangle
+	path    []content.Operation
+	pathBox *types.Rectangle
+	clip    bool
+	nameNr  int
+}
+
+// hits returns the areas intersecting r.
+func (r *redactor) hits(rect *types.Rectangle) []*types.Rectangle {
+	var rr []*types.Rectangle
+	for _, a := range r.areas {
+		if intersects(a, rect) {
+			rr = append(rr, a)
+		}
+	}
+	return rr
+}
+
+func (r *redactor) covered(rect *types.Rectangle) bool {
+	for _, a := range r.areas {
+		if rect.FitsWithin(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// unitSquareBox returns the bounding box of the unit square in user space.
+func (r *redactor) unitSquareBox() *types.Rectangle {
+	pp := rectCorners(types.NewRectangle(0, 0, 1, 1))
+	for i := range pp {
+		pp[i] = r.gs.ctm.Transform(pp[i])
+	}
+	return bboxForPoints(pp...)
+}
+
+func (r *redactor) newName(prefix string) string {
+	r.nameNr++
+	return fmt.Sprintf("%sR%d", prefix, r.nameNr)
+}
+
+func numberOperands(ff ...float64) []types.Object {
+	oo := make([]types.Object, len(ff))
+	for i, f := range ff {
+		oo[i] = types.Float(f)
+	}
+	return oo
+}
+
+func polygonOps(pp []types.Point) []content.Operation {
+	ops := []content.Operation{{Operator: "m", Operands: numberOperands(pp[0].X, pp[0].Y)}}
+	for _, p := range pp[1:] {
+		ops = append(ops, content.Operation{Operator: "l", Operands: numberOperands(p.X, p.Y)})
+	}
+	return append(ops, content.Operation{Operator: "h"})
+}
+
+// exclusion returns a clipping path excluding areas from the current clipping region.
+func (r *redactor) exclusion(areas []*types.Rectangle) ([]content.Operation, bool) {
+	inv, ok := invert(r.gs.ctm)
+	if !ok {
+		return nil, false
+	}
+
+	transform := func(pp []types.Point) []types.Point {
+		for i := range pp {
+			pp[i] = inv.Transform(pp[i])
+		}
+		return pp
+	}
+
+	var ops []content.Operation
+	for _, a := range areas {
+		ops = append(ops, polygonOps(transform(rectCorners(types.NewRectangle(-redactInfinity, -redactInfinity, redactInfinity, redactInfinity))))...)
+		ops = append(ops, polygonOps(transform(rectCorners(a)))...)
+		ops = append(ops, content.Operation{Operator: "W*"}, content.Operation{Operator: "n"})
+	}
+
+	return ops, true
+}
+
+// pathOp adds a path construction or clipping operator to the current path.
+func (r *redactor) pathOp(op content.Operation) {
+	r.path = append(r.path, op)
+
+	var pp []types.Point
+
+	switch op.Operator {
+	case "m", "l":
+		if ff, ok := floats(op.Operands, 2); ok {
+			pp = append(pp, types.Point{X: ff[0], Y: ff[1]})
+		}
+	case "c":
+		if ff, ok := floats(op.Operands, 6); ok {
+			pp = append(pp, types.Point{X: ff[0], Y: ff[1]}, types.Point{X: ff[2], Y: ff[3]}, types.Point{X: ff[4], Y: ff[5]})
+		}
+	case "v", "y":
+		if ff, ok := floats(op.Operands, 4); ok {
+			pp = append(pp, types.Point{X: ff[0], Y: ff[1]}, types.Point{X: ff[2], Y: ff[3]})
+		}
+	case "re":
+		if ff, ok := floats(op.Operands, 4); ok {
+			pp = rectCorners(bboxForPoints(types.Point{X: ff[0], Y: ff[1]}, types.Point{X: ff[0] + ff[2], Y: ff[1] + ff[3]}))
+		}
+	case "W", "W*":
+		r.clip = true
+	}
+
+	if len(pp) == 0 {
+		return
+	}
+
+	for i := range pp {
+		pp[i] = r.gs.ctm.Transform(pp[i])
+	}
+	box := bboxForPoints(pp...)
+	if r.pathBox != nil {
+		box = union(r.pathBox, box)
+	}
+	r.pathBox = box
+}
+
+// paint processes a path painting operator.
+func (r *redactor) paint(op content.Operation) ([]content.Operation, bool) {
+	path, box, clip := r.path, r.pathBox, r.clip
+	r.path, r.pathBox, r.clip = nil, nil, false
+
+	ops := append(path, op)
+
+	if op.Operator == "n" || box == nil {
+		return ops, false
+	}
+
+	if strings.ContainsAny(op.Operator, "SsBb") {
+		// Account for the line width.
+		w := r.gs.lineWidth
+		if w == 0 {
+			w = 1
+		}
+		p0 := r.gs.ctm.Transform(types.Point{})
+		w *= math.Max(distance(p0, r.gs.ctm.Transform(types.Point{X: 1})), distance(p0, r.gs.ctm.Transform(types.Point{Y: 1}))) / 2
+		box = types.NewRectangle(box.LL.X-w, box.LL.Y-w, box.UR.X+w, box.UR.Y+w)
+	}
+
+	areas := r.hits(box)
+	if len(areas) == 0 {
+		return ops, false
+	}
+
+	excl, ok := r.exclusion(areas)
+	if !ok {
+		return ops, false
+	}
+
+	ops = []content.Operation{{Operator: "q"}}
+	ops = append(ops, excl...)
+	for _, op1 := range path {
+		if op1.Operator != "W" && op1.Operator != "W*" {
+			ops = append(ops, op1)
+		}
+	}
+	ops = append(ops, op, content.Operation{Operator: "Q"})
+
+	if clip {
+		// Keep the clipping path in effect.
+		ops = append(ops, path...)
+		ops = append(ops, content.Operation{Operator: "n"})
+	}
+
+	return ops, true
+}
+
+// appendAdjustment appends the TJ number element f to a merging it with a preceding number.
+func appendAdjustment(a types.Array, f float64) types.Array {
+	if n := len(a); n > 0 {
+		if ff, ok := floats(a[n-1:], 1); ok {
+			a[n-1] = types.Float(math.Round((ff[0]+f)*1000) / 1000)
+			return a
+		}
+	}
+	return append(a, types.Float(math.Round(f*1000)/1000))
+}
+
+// text processes a text showing operator omitting all glyphs located within the redacted regions.
+func (r *redactor) text(op content.Operation) ([]content.Operation, bool) {
+	var (
+		a       types.Array
+		run     []byte
+		changed bool
+	)
+
+	flush := func() {
+		if len(run) > 0 {
+			a = append(a, types.NewHexLiteral(run))
+			run = nil
+		}
+	}
+
+	glyph := func(g placedGlyph) {
+		if len(r.hits(g.bbox)) == 0 {
+			run = append(run, g.Code...)
+			return
+		}
+		changed = true
+		flush()
+		if fs := r.gs.fontSize; fs != 0 {
+			// Replace the glyph by an equivalent displacement.
+			n := -g.advance * 1000 / fs
+			if r.gs.font.Vertical {
+				n = -n
+			}
+			a = appendAdjustment(a, n)
+		}
+	}
+
+	if op.Operator == "TJ" {
+		arr, _ := lastOperand(op.Operands).(types.Array)
+		for _, o := range arr {
+			switch o.(type) {
+			case types.Integer, types.Float:
+				flush()
+				ff, _ := floats([]types.Object{o}, 1)
+				a = appendAdjustment(a, ff[0])
+				r.adjust(o)
+			default:
+				r.showString(o, glyph)
+				flush()
+			}
+		}
+	} else {
+		r.showString(lastOperand(op.Operands), glyph)
+		flush()
+	}
+
+	if !changed {
+		return []content.Operation{op}, false
+	}
+
+	var ops []content.Operation
+
+	switch op.Operator {
+	case "'":
+		ops = append(ops, content.Operation{Operator: "T*"})
+	case "\"":
+		if len(op.Operands) == 3 {
+			ops = append(ops,
+				content.Operation{Operator: "Tw", Operands: op.Operands[:1]},
+				content.Operation{Operator: "Tc", Operands: op.Operands[1:2]})
+		}
+		ops = append(ops, content.Operation{Operator: "T*"})
+	}
+
+	return append(ops, content.Operation{Operator: "TJ", Operands: []types.Object{a}}), true
+}
+
+func imageSamplesComponents(ctx *model.Context, sd *types.StreamDict) (int, error) {
+	if b := sd.BooleanEntry("ImageMask"); b != nil && *b {
+		return 1, nil
+	}
+
+	o, err := ctx.Dereference(sd.Dict["ColorSpace"])
+	if err != nil {
+		return 0, err
+	}
+	if a, ok := o.(types.Array); ok && len(a) > 0 {
+		if n, ok := a[0].(types.Name); ok && (n == model.IndexedCS || n == "I") {
+			return 1, nil
+		}
+	}
+
+	return ColorSpaceComponents(ctx.XRefTable, sd)
+}
+
+// imageSamples returns the decoded samples of image sd along with the number of color components and bits per component.
+// Images using filters other than the generic ones or DCTDecode are not supported.
+func imageSamples(ctx *model.Context, sd *types.StreamDict) ([]byte, int, int, error) {
+	if len(sd.FilterPipeline) == 1 && sd.FilterPipeline[0].Name == filter.DCT {
+		im, err := jpeg.Decode(bytes.NewReader(sd.Raw))
+		if err != nil {
+			return nil, 0, 0, nil
+		}
+		b := im.Bounds()
+		if gray, ok := im.(*image.Gray); ok {
+			bb := make([]byte, 0, b.Dx()*b.Dy())
+			for y := b.Min.Y; y < b.Max.Y; y++ {
+				bb = append(bb, gray.Pix[gray.PixOffset(b.Min.X, y):gray.PixOffset(b.Max.X, y)]...)
+			}
+			sd.Dict["ColorSpace"] = types.Name(model.DeviceGrayCS)
+			delete(sd.Dict, "Decode")
+			return bb, 1, 8, nil
+		}
+		bb := make([]byte, 0, 3*b.Dx()*b.Dy())
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				cr, cg, cb, _ := im.At(x, y).RGBA()
+				bb = append(bb, byte(cr>>8), byte(cg>>8), byte(cb>>8))
+			}
+		}
+		sd.Dict["ColorSpace"] = types.Name(model.DeviceRGBCS)
+		delete(sd.Dict, "Decode")
+		return bb, 3, 8, nil
+	}
+
+	for _, f := range sd.FilterPipeline {
+		if !types.MemberOf(f.Name, []string{filter.Flate, filter.LZW, filter.ASCII85, filter.ASCIIHex, filter.RunLength}) {
+			return nil, 0, 0, nil
+		}
+	}
+
+	n, err := imageSamplesComponents(ctx, sd)
+	if err != nil || n == 0 {
+		return nil, 0, 0, err
+	}
+
+	bpc := 1
+	if i := sd.IntEntry("BitsPerComponent"); i != nil {
+		bpc = *i
+	}
+
+	if err := sd.Decode(); err != nil {
+		return nil, 0, 0, err
+	}
+
+	return append([]byte(nil), sd.Content...), n, bpc, nil
+}
+
+func clearBits(bb []byte, from, n int) {
+	for i := from; i < from+n; i++ {
+		if i/8 >= len(bb) {
+			return
+		}
+		bb[i/8] &^= 0x80 >> (i % 8)
+	}
+}
+
+// blankImage returns a copy of image sd with all pixels located within the redacted regions cleared.
+// It returns nil if the image format is not supported.
+func (r *redactor) blankImage(sd *types.StreamDict) (*types.IndirectRef, error) {
+	w, h := sd.IntEntry("Width"), sd.IntEntry("Height")
+	if w == nil || h == nil || *w <= 0 || *h <= 0 {
+		return nil, nil
+	}
+
+	inv, ok := invert(r.gs.ctm)
+	if !ok {
+		return nil, nil
+	}
+
+	sd1 := *sd
+	sd1.Dict = sd.Dict.Clone().(types.Dict)
+	sd1.Content = nil
+
+	bb, n, bpc, err := imageSamples(r.ctx, &sd1)
+	if err != nil || bb == nil {
+		return nil, err
+	}
+
+	stride := (*w*n*bpc + 7) / 8
+	if len(bb) < stride**h {
+		return nil, nil
+	}
+
+	for _, a := range r.areas {
+		// Determine the affected pixel range.
+		pp := rectCorners(a)
+		for i := range pp {
+			pp[i] = inv.Transform(pp[i])
+		}
+		box := bboxForPoints(pp...)
+		x0 := int(math.Max(0, math.Floor(box.LL.X*float64(*w))))
+		x1 := int(math.Min(float64(*w), math.Ceil(box.UR.X*float64(*w))))
+		y0 := int(math.Max(0, math.Floor((1-box.UR.Y)*float64(*h))))
+		y1 := int(math.Min(float64(*h), math.Ceil((1-box.LL.Y)*float64(*h))))
+
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				px := types.NewRectangle(float64(x)/float64(*w), 1-float64(y+1)/float64(*h), float64(x+1)/float64(*w), 1-float64(y)/float64(*h))
+				pp := rectCorners(px)
+				for i := range pp {
+					pp[i] = r.gs.ctm.Transform(pp[i])
+				}
+				if intersects(a, bboxForPoints(pp...)) {
+					clearBits(bb[y*stride:], x*n*bpc, n*bpc)
+				}
+			}
+		}
+	}
+
+	sd2, err := r.ctx.NewStreamDictForBuf(bb)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range sd1.Dict {
+		if !types.MemberOf(k, []string{"Filter", "DecodeParms", "Length"}) {
+			sd2.Dict[k] = v
+		}
+	}
+	sd2.Dict["BitsPerComponent"] = types.Integer(bpc)
+	if err := sd2.Encode(); err != nil {
+		return nil, err
+	}
+
+	return r.ctx.IndRefForNewObject(*sd2)
+}
+
+// copyForm returns a copy of the form XObject sd using bb as content.
+func (r *redactor) copyForm(sd *types.StreamDict, bb []byte, resources types.Dict) (*types.IndirectRef, error) {
+	sd1, err := r.ctx.NewStreamDictForBuf(bb)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range sd.Dict {
+		if !types.MemberOf(k, []string{"Filter", "DecodeParms", "Length"}) {
+			sd1.Dict[k] = v
+		}
+	}
+	if resources != nil {
+		sd1.Dict["Resources"] = resources
+	}
+	if err := sd1.Encode(); err != nil {
+		return nil, err
+	}
+	return r.ctx.IndRefForNewObject(*sd1)
+}
+
+// xObject processes the XObject operator Do.
+// It returns the name of the replacement XObject, "" for omitting op or the original name.
+func (r *redactor) xObject(resources types.Dict, fName string, depth int, repl map[string]types.IndirectRef) (string, error) {
+	sd, err := r.interpreter.xObject(resources, fName)
+	if err != nil || sd == nil {
+		return fName, err
+	}
+
+	st := sd.Dict.Subtype()
+	if st == nil {
+		return fName, nil
+	}
+
+	switch *st {
+
+	case "Image":
+		box := r.unitSquareBox()
+		if len(r.hits(box)) == 0 {
+			return fName, nil
+		}
+		if r.covered(box) {
+			return "", nil
+		}
+		indRef, err := r.blankImage(sd)
+		if err != nil || indRef == nil {
+			return "", err
+		}
+		name := r.newName(fName)
+		repl[name] = *indRef
+		return name, nil
+
+	case "Form":
+		if depth >= maxFormDepth {
+			return fName, nil
+		}
+		sd, ops, res, err := r.formXObject(resources, fName)
+		if err != nil || sd == nil {
+			return fName, err
+		}
+		restore := r.enterForm(sd)
+		ops, res1, changed, err := r.process(ops, res, depth+1)
+		restore()
+		if err != nil || !changed {
+			return fName, err
+		}
+		indRef, err := r.copyForm(sd, content.Format(ops), res1)
+		if err != nil {
+			return fName, err
+		}
+		name := r.newName(fName)
+		repl[name] = *indRef
+		return name, nil
+	}
+
+	return fName, nil
+}
+
+// resourcesWithXObjects returns a copy of resources including the XObjects of repl and excluding the XObjects of drop.
+func (r *redactor) resourcesWithXObjects(resources types.Dict, repl map[string]types.IndirectRef, drop map[string]bool) (types.Dict, error) {
+	res := types.NewDict()
+	if resources != nil {
+		res = resources.Clone().(types.Dict)
+	}
+
+	xObjs, err := r.ctx.DereferenceDict(res["XObject"])
+	if err != nil {
+		return nil, err
+	}
+	if xObjs == nil {
+		xObjs = types.NewDict()
+	} else {
+		xObjs = xObjs.Clone().(types.Dict)
+	}
+
+	for k := range drop {
+		delete(xObjs, k)
+	}
+	for k, v := range repl {
+		xObjs[k] = v
+	}
+	res["XObject"] = xObjs
+
+	return res, nil
+}
+
+// process returns ops with all content located within the redacted regions removed.
+// If XObjects had to be replaced or removed the corresponding resources are returned.
+func (r *redactor) process(ops []content.Operation, resources types.Dict, depth int) ([]content.Operation, types.Dict, bool, error) {
+	var (
+		res     []content.Operation
+		changed bool
+	)
+
+	repl := map[string]types.IndirectRef{}
+
+	// XObjects no longer in use get removed from the resources.
+	used, drop := map[string]bool{}, map[string]bool{}
+
+	for _, op := range ops {
+		r.state(op, resources)
+
+		switch op.Operator {
+
+		case "m", "l", "c", "v", "y", "h", "re", "W", "W*":
+			r.pathOp(op)
+
+		case "S", "s", "f", "F", "f*", "B", "B*", "b", "b*", "n":
+			ops1, ok := r.paint(op)
+			res = append(res, ops1...)
+			changed = changed || ok
+
+		case "sh":
+			excl, ok := r.exclusion(r.areas)
+			if !ok {
+				res = append(res, op)
+				continue
+			}
+			res = append(res, content.Operation{Operator: "q"})
+			res = append(res, excl...)
+			res = append(res, op, content.Operation{Operator: "Q"})
+			changed = true
+
+		case "Tj", "'", "\"", "TJ":
+			ops1, ok := r.text(op)
+			res = append(res, ops1...)
+			changed = changed || ok
+
+		case "BI":
+			if len(r.hits(r.unitSquareBox())) > 0 {
+				changed = true
+				continue
+			}
+			res = append(res, op)
+
+		case "Do":
+			n, ok := lastOperand(op.Operands).(types.Name)
+			if !ok {
+				res = append(res, op)
+				continue
+			}
+			name, err := r.xObject(resources, n.Value(), depth, repl)
+			if err != nil {
+				return nil, nil, false, err
+			}
+			if name != n.Value() {
+				drop[n.Value()] = true
+				changed = true
+			}
+			if name == "" {
+				continue
+			}
+			used[name] = true
+			res = append(res, content.Operation{Operator: "Do", Operands: []types.Object{types.Name(name)}})
+
+		default:
+			res = append(res, op)
+		}
+	}
+
+	// Flush an incomplete path.
+	res = append(res, r.path...)
+	r.path, r.pathBox, r.clip = nil, nil, false
+
+	for k := range used {
+		delete(drop, k)
+	}
+
+	if len(repl) == 0 && len(drop) == 0 {
+		return res, nil, changed, nil
+	}
+
+	res1, err := r.resourcesWithXObjects(resources, repl, drop)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return res, res1, changed, nil
+}
+
+func overlayTextLines(rd redaction, fontSize float64) []string {
+	if !rd.repeat {
+		return []string{rd.overlayText}
+	}
+
+	w := font.TextWidth(rd.overlayText+" ", redactFontName, int(math.Ceil(fontSize)))
+	if w <= 0 {
+		return []string{rd.overlayText}
+	}
+
+	n := int(math.Ceil(rd.rect.Width()/w)) + 1
+	s := strings.TrimSpace(strings.Repeat(rd.overlayText+" ", n))
+
+	var ss []string
+	for i := 0; i < int(rd.rect.Height()/fontSize); i++ {
+		ss = append(ss, s)
+	}
+	if len(ss) == 0 {
+		ss = append(ss, s)
+	}
+
+	return ss
+}
+
+// overlay renders the overlay of rd.
+func overlay(b *bytes.Buffer, rd redaction, fontID string) error {
+	r := rd.rect
+
+	if rd.fillCol != nil {
+		c := rd.fillCol
+		fmt.Fprintf(b, "q %.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f Q\n", c.R, c.G, c.B, r.LL.X, r.LL.Y, r.Width(), r.Height())
+	}
+
+	if rd.overlayText == "" || fontID == "" {
+		return nil
+	}
+
+	fontSize := rd.fontSize
+	if fontSize <= 0 {
+		// Auto size: fit into the redacted region.
+		fontSize = math.Min(r.Height()*0.8, 12)
+		if w := font.TextWidth(rd.overlayText, redactFontName, 1000); w > 0 && !rd.repeat {
+			fontSize = math.Min(fontSize, r.Width()*1000/w)
+		}
+		if fontSize < 1 {
+			return nil
+		}
+	}
+
+	c := rd.textCol
+	fmt.Fprintf(b, "q %.2f %.2f %.2f %.2f re W n %.3f %.3f %.3f rg BT /%s %.2f Tf\n", r.LL.X, r.LL.Y, r.Width(), r.Height(), c.R, c.G, c.B, fontID, fontSize)
+
+	lines := overlayTextLines(rd, fontSize)
+	y := r.LL.Y + (r.Height()-float64(len(lines))*fontSize)/2 + 0.2*fontSize
+	if rd.repeat {
+		y = r.UR.Y - fontSize
+	}
+
+	for _, s := range lines {
+		x := r.LL.X
+		if !rd.repeat {
+			w := font.TextWidth(s, redactFontName, 1000) * fontSize / 1000
+			switch rd.q {
+			case 1:
+				x += (r.Width() - w) / 2
+			case 2:
+				x += r.Width() - w
+			}
+		}
+		s1, err := types.Escape(model.DecodeUTF8ToByte(s))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "1 0 0 1 %.2f %.2f Tm (%s) Tj\n", x, y, *s1)
+		y -= fontSize
+	}
+
+	b.WriteString("ET Q\n")
+
+	return nil
+}
+
+// ensureOverlayFont adds a font for rendering overlay text to resources and returns its resource name.
+func ensureOverlayFont(ctx *model.Context, resources types.Dict) (string, error) {
+	indRef, err := pdffont.EnsureFontDict(ctx.XRefTable, redactFontName, "", "", false, nil)
+	if err != nil {
+		return "", err
+	}
+
+	fonts, err := ctx.DereferenceDict(resources["Font"])
+	if err != nil {
+		return "", err
+	}
+	if fonts == nil {
+		fonts = types.NewDict()
+	} else {
+		fonts = fonts.Clone().(types.Dict)
+	}
+
+	id := "FRedact"
+	for i := 1; ; i++ {
+		if _, found := fonts.Find(id); !found {
+			break
+		}
+		id = fmt.Sprintf("FRedact%d", i)
+	}
+
+	fonts[id] = *indRef
+	resources["Font"] = fonts
+
+	return id, nil
+}
+
+func redactPage(ctx *model.Context, pageNr int, rr []redaction) error {
+	d, _, inhPAttrs, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return errors.Errorf("pdfcpu: redact: missing page %d", pageNr)
+	}
+
+	bb, err := ctx.PageContent(d)
+	if err != nil && err != model.ErrNoContent {
+		return err
+	}
+
+	ops, err := content.Parse(bb)
+	if err != nil {
+		return err
+	}
+
+	r := &redactor{interpreter: newInterpreter(ctx)}
+	for _, rd := range rr {
+		r.areas = append(r.areas, rd.rect)
+	}
+
+	ops, res, _, err := r.process(ops, inhPAttrs.Resources, 0)
+	if err != nil {
+		return err
+	}
+
+	if res == nil {
+		res = types.NewDict()
+		if inhPAttrs.Resources != nil {
+			res = inhPAttrs.Resources.Clone().(types.Dict)
+		}
+	}
+
+	var fontID string
+	for _, rd := range rr {
+		if rd.overlayText != "" {
+			if fontID, err = ensureOverlayFont(ctx, res); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("q\n")
+	b.Write(content.Format(ops))
+	b.WriteString("Q\n")
+	for _, rd := range rr {
+		if err := overlay(&b, rd, fontID); err != nil {
+			return err
+		}
+	}
+
+	sd, err := ctx.NewStreamDictForBuf(b.Bytes())
+	if err != nil {
+		return err
+	}
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	indRef, err := ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		return err
+	}
+
+	d["Contents"] = *indRef
+	d["Resources"] = res
+
+	return nil
+}
+
+// ApplyRedactions removes all content of selectedPages located within the regions marked by Redact annotations
+// or within rects given in default user space and paints the corresponding overlays.
+// Redaction areas defined by rects are filled in black.
+// Finally all Redact annotations of selectedPages get removed.
+func ApplyRedactions(ctx *model.Context, selectedPages types.IntSet, rects []*types.Rectangle) (bool, error) {
+	if len(selectedPages) == 0 {
+		selectedPages = types.IntSet{}
+		for i := 1; i <= ctx.PageCount; i++ {
+			selectedPages[i] = true
+		}
+	}
+
+	var (
+		applied bool
+		annots  = types.IntSet{}
+	)
+
+	for pageNr, v := range selectedPages {
+		if !v {
+			continue
+		}
+
+		d, _, _, err := ctx.PageDict(pageNr, false)
+		if err != nil {
+			return false, err
+		}
+		if d == nil {
+			continue
+		}
+
+		rr, err := pageRedactions(ctx, d)
+		if err != nil {
+			return false, err
+		}
+		if len(rr) > 0 {
+			annots[pageNr] = true
+		}
+
+		for _, rect := range rects {
+			rr = append(rr, redaction{rect: rect, fillCol: &color.Black})
+		}
+
+		if len(rr) == 0 {
+			continue
+		}
+
+		if err := redactPage(ctx, pageNr, rr); err != nil {
+			return false, err
+		}
+		applied = true
+	}
+
+	if len(annots) > 0 {
+		if _, err := RemoveAnnotations(ctx, annots, []string{"Redact"}, nil, false); err != nil {
+			return false, err
+		}
+	}
+
+	return applied, nil
+}
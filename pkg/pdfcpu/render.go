@@ -0,0 +1,882 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"image"
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/raster"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// See 8 Graphics and 12.5.5 Appearance Streams
+
+const (
+	// Curves are flattened within this tolerance in device pixels.
+	flatness = 0.2
+
+	// The maximum number of pixels of a rendered page.
+	maxRenderPixels = 1 << 27
+)
+
+// Annotation flags
+const (
+	annHidden = 1 << 1
+	annNoView = 1 << 5
+)
+
+// paintSource provides the color of a device pixel along with its opacity.
+type paintSource interface {
+	colorAt(x, y int) (rgbColor, float64)
+}
+
+// transparent is the paint source used for unresolvable patterns.
+type transparent struct{}
+
+func (transparent) colorAt(x, y int) (rgbColor, float64) {
+	return rgbColor{}, 0
+}
+
+// paint is the current fill or stroke color.
+type paint struct {
+	cs      *colorSpace
+	rgb     rgbColor
+	pattern paintSource // nil unless cs is a Pattern colour space
+}
+
+// renderState represents the graphics state parameters used for painting not tracked by the interpreter.
+type renderState struct {
+	fill, stroke           paint
+	fillAlpha, strokeAlpha float64
+	cap                    raster.Cap
+	join                   raster.Join
+	miterLimit             float64
+	dash                   []float64
+	dashPhase              float64
+	clip                   *image.Alpha // nil for no clipping
+	renderMode             int          // Tr
+}
+
+// renderFont is a font in use by a renderer.
+type renderFont struct {
+	d        types.Dict
+	outlines *font.Outlines
+	procs    map[string][]content.Operation // Type3 glyph descriptions
+}
+
+// renderCache holds resources shared by all renderers of a page.
+type renderCache struct {
+	decoders     map[int]*font.Decoder
+	fonts        map[*font.Decoder]*renderFont
+	colorSpaces  map[int]*colorSpace
+	images       map[int]*decodedImage
+	patterns     map[string]paintSource
+	hidden       map[int]bool // optional content groups turned off
+	patternDepth int
+}
+
+// renderer paints content streams into an image.
+type renderer struct {
+	*interpreter
+	*renderCache
+	img          *image.RGBA
+	rs           renderState
+	rstack       []renderState
+	path         raster.Path   // current path in user space
+	clipRule     int           // pending clipping path operator: 0 none, 1 W, 2 W*
+	textClip     raster.Path   // accumulated glyph outlines in device space
+	textClipping bool          // the current text object uses a clipping text rendering mode
+	base         matrix.Matrix // default coordinate space of the current content stream
+	uncolored    bool          // color operators are ignored
+	ocStack      []bool        // visibility of nested marked content
+}
+
+func newRenderer(ctx *model.Context, img *image.RGBA, ctm matrix.Matrix, cache *renderCache) *renderer {
+	ip := newInterpreter(ctx)
+	ip.decoders = cache.decoders
+	ip.gs.ctm = ctm
+
+	return &renderer{
+		interpreter: ip,
+		renderCache: cache,
+		img:         img,
+		base:        ctm,
+		rs: renderState{
+			fill:        paint{cs: csGray},
+			stroke:      paint{cs: csGray},
+			fillAlpha:   1,
+			strokeAlpha: 1,
+			miterLimit:  10,
+		},
+	}
+}
+
+func newRenderCache(ctx *model.Context) *renderCache {
+	rc := &renderCache{
+		decoders:    map[int]*font.Decoder{},
+		fonts:       map[*font.Decoder]*renderFont{},
+		colorSpaces: map[int]*colorSpace{},
+		images:      map[int]*decodedImage{},
+		patterns:    map[string]paintSource{},
+		hidden:      hiddenOCGs(ctx),
+	}
+	return rc
+}
+
+// visible reports whether the optional content group or membership dict o is visible.
+func (r *renderer) visible(o types.Object) bool {
+	return ocVisible(r.ctx, r.hidden, o)
+}
+
+func (r *renderer) hiddenContent() bool {
+	n := len(r.ocStack)
+	return n > 0 && r.ocStack[n-1]
+}
+
+// markedContent tracks the visibility of optional content and reports whether op is to be skipped.
+func (r *renderer) markedContent(op content.Operation, resources types.Dict) bool {
+	switch op.Operator {
+
+	case "BDC":
+		hidden := r.hiddenContent()
+		if len(op.Operands) != 2 {
+			r.ocStack = append(r.ocStack, hidden)
+			return true
+		}
+		if n, ok := op.Operands[0].(types.Name); !hidden && ok && n.Value() == "OC" {
+			o := op.Operands[1]
+			if n, ok := o.(types.Name); ok {
+				props, err := r.ctx.DereferenceDict(resources["Properties"])
+				if err != nil || props == nil {
+					o = nil
+				} else {
+					o, _ = props.Find(n.Value())
+				}
+			}
+			hidden = !r.visible(o)
+		}
+		r.ocStack = append(r.ocStack, hidden)
+		return true
+
+	case "BMC":
+		r.ocStack = append(r.ocStack, r.hiddenContent())
+		return true
+
+	case "EMC":
+		if n := len(r.ocStack); n > 0 {
+			r.ocStack = r.ocStack[:n-1]
+		}
+		return true
+	}
+
+	return r.hiddenContent()
+}
+
+// enter saves the rendering state and applies m to the CTM.
+func (r *renderer) enter(m matrix.Matrix) func() {
+	restore := r.interpreter.enter(m)
+	rs, rstack, path, clipRule := r.rs, r.rstack, r.path, r.clipRule
+	textClip, textClipping := r.textClip, r.textClipping
+	base, uncolored, ocStack := r.base, r.uncolored, r.ocStack
+
+	r.rstack, r.path, r.clipRule = nil, raster.Path{}, 0
+	r.textClip, r.textClipping = raster.Path{}, false
+	r.base = r.gs.ctm
+
+	return func() {
+		restore()
+		r.rs, r.rstack, r.path, r.clipRule = rs, rstack, path, clipRule
+		r.textClip, r.textClipping = textClip, textClipping
+		r.base, r.uncolored, r.ocStack = base, uncolored, ocStack
+	}
+}
+
+func (r *renderer) clipBounds() image.Rectangle {
+	if r.rs.clip != nil {
+		return r.rs.clip.Rect.Intersect(r.img.Rect)
+	}
+	return r.img.Rect
+}
+
+func blend(pix []uint8, c rgbColor, a float64) {
+	a = math.Min(a, 1)
+	ia := 1 - a
+	for i := 0; i < 3; i++ {
+		pix[i] = uint8(c[i]*a*255 + float64(pix[i])*ia + 0.5)
+	}
+	pix[3] = uint8(a*255 + float64(pix[3])*ia + 0.5)
+}
+
+// composite paints p with opacity alpha through mask restricted to the current clipping path.
+func (r *renderer) composite(mask *image.Alpha, p paint, alpha float64) {
+	if mask == nil || alpha <= 0 {
+		return
+	}
+	mask = raster.Intersect(mask, r.rs.clip)
+	rect := mask.Rect.Intersect(r.img.Rect)
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		mi, pi := mask.PixOffset(rect.Min.X, y), r.img.PixOffset(rect.Min.X, y)
+		for x := rect.Min.X; x < rect.Max.X; x, mi, pi = x+1, mi+1, pi+4 {
+			m := mask.Pix[mi]
+			if m == 0 {
+				continue
+			}
+			a := float64(m) / 255 * alpha
+			c := p.rgb
+			if p.pattern != nil {
+				var ca float64
+				c, ca = p.pattern.colorAt(x, y)
+				a *= ca
+			}
+			if a > 0 {
+				blend(r.img.Pix[pi:pi+4], c, a)
+			}
+		}
+	}
+}
+
+func transformPolylines(pl []raster.Polyline, m matrix.Matrix) []raster.Polyline {
+	for i := range pl {
+		for j, p := range pl[i].Points {
+			pl[i].Points[j] = m.Transform(p)
+		}
+	}
+	return pl
+}
+
+// fillPath fills p given in user space.
+func (r *renderer) fillPath(p *raster.Path, evenOdd bool) {
+	pl := p.Transform(r.gs.ctm).Flatten(flatness)
+	r.composite(raster.Fill(pl, evenOdd, r.clipBounds()), r.rs.fill, r.rs.fillAlpha)
+}
+
+// strokePath strokes p given in user space.
+func (r *renderer) strokePath(p *raster.Path) {
+	ctm := r.gs.ctm
+	s := math.Sqrt(math.Abs(ctm[0][0]*ctm[1][1] - ctm[0][1]*ctm[1][0]))
+	if s == 0 {
+		return
+	}
+
+	// Lines are at least one device pixel wide.
+	w := math.Max(r.gs.lineWidth, 1/s)
+
+	style := raster.StrokeStyle{
+		Width:      w,
+		Cap:        r.rs.cap,
+		Join:       r.rs.join,
+		MiterLimit: r.rs.miterLimit,
+		Dash:       r.rs.dash,
+		DashPhase:  r.rs.dashPhase,
+	}
+
+	tol := flatness / s
+	pl := transformPolylines(raster.Stroke(p.Flatten(tol), style, tol), ctm)
+	r.composite(raster.Fill(pl, false, r.clipBounds()), r.rs.stroke, r.rs.strokeAlpha)
+}
+
+// clip intersects the current clipping path with p given in device space.
+func (r *renderer) clip(p *raster.Path, evenOdd bool) {
+	m := raster.Fill(p.Flatten(flatness), evenOdd, r.clipBounds())
+	if m == nil {
+		m = image.NewAlpha(image.Rectangle{})
+	}
+	r.rs.clip = raster.Intersect(r.rs.clip, m)
+}
+
+// clipRect intersects the current clipping path with the rectangle rect given in user space.
+func (r *renderer) clipRect(rect []float64) {
+	if len(rect) != 4 {
+		return
+	}
+	var p raster.Path
+	p.Rect(rect[0], rect[1], rect[2]-rect[0], rect[3]-rect[1])
+	r.clip(p.Transform(r.gs.ctm), false)
+}
+
+func (r *renderer) pathOp(op content.Operation) {
+	oo, p := op.Operands, &r.path
+
+	switch op.Operator {
+
+	case "m":
+		if ff, ok := floats(oo, 2); ok {
+			p.MoveTo(ff[0], ff[1])
+		}
+
+	case "l":
+		if ff, ok := floats(oo, 2); ok {
+			p.LineTo(ff[0], ff[1])
+		}
+
+	case "c":
+		if ff, ok := floats(oo, 6); ok {
+			p.CubeTo(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5])
+		}
+
+	case "v":
+		if ff, ok := floats(oo, 4); ok {
+			cp := p.CurrentPoint()
+			p.CubeTo(cp.X, cp.Y, ff[0], ff[1], ff[2], ff[3])
+		}
+
+	case "y":
+		if ff, ok := floats(oo, 4); ok {
+			p.CubeTo(ff[0], ff[1], ff[2], ff[3], ff[2], ff[3])
+		}
+
+	case "h":
+		p.Close()
+
+	case "re":
+		if ff, ok := floats(oo, 4); ok {
+			p.Rect(ff[0], ff[1], ff[2], ff[3])
+		}
+	}
+}
+
+// paintPath ends the current path using the path painting operator op.
+func (r *renderer) paintPath(op string) {
+	switch op {
+	case "s", "b", "b*":
+		r.path.Close()
+	}
+
+	switch op {
+	case "f", "F", "B", "b":
+		r.fillPath(&r.path, false)
+	case "f*", "B*", "b*":
+		r.fillPath(&r.path, true)
+	}
+
+	switch op {
+	case "S", "s", "B", "B*", "b", "b*":
+		r.strokePath(&r.path)
+	}
+
+	if r.clipRule != 0 {
+		r.clip(r.path.Transform(r.gs.ctm), r.clipRule == 2)
+	}
+
+	r.path, r.clipRule = raster.Path{}, 0
+}
+
+func (r *renderer) setDash(a types.Array, phase float64) {
+	ff, ok := floats(a, len(a))
+	if !ok {
+		return
+	}
+	var total float64
+	for _, f := range ff {
+		if f < 0 {
+			return
+		}
+		total += f
+	}
+	if total == 0 {
+		ff = nil
+	}
+	r.rs.dash, r.rs.dashPhase = ff, phase
+}
+
+func (r *renderer) lineStyle(op content.Operation) {
+	oo := op.Operands
+
+	switch op.Operator {
+
+	case "J":
+		if ff, ok := floats(oo, 1); ok && ff[0] >= 0 && ff[0] <= 2 {
+			r.rs.cap = raster.Cap(ff[0])
+		}
+
+	case "j":
+		if ff, ok := floats(oo, 1); ok && ff[0] >= 0 && ff[0] <= 2 {
+			r.rs.join = raster.Join(ff[0])
+		}
+
+	case "M":
+		if ff, ok := floats(oo, 1); ok {
+			r.rs.miterLimit = ff[0]
+		}
+
+	case "d":
+		if len(oo) != 2 {
+			return
+		}
+		a, ok := oo[0].(types.Array)
+		ff, ok1 := floats(oo[1:], 1)
+		if ok && ok1 {
+			r.setDash(a, ff[0])
+		}
+	}
+}
+
+// extGState applies the graphics state parameter dict named by the operand of gs.
+func (r *renderer) extGState(resources types.Dict, o types.Object) {
+	n, ok := o.(types.Name)
+	if !ok {
+		return
+	}
+	gss, err := r.ctx.DereferenceDict(resources["ExtGState"])
+	if err != nil || gss == nil {
+		return
+	}
+	o, found := gss.Find(n.Value())
+	if !found {
+		return
+	}
+	d, err := r.ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return
+	}
+
+	number := func(k string) (float64, bool) {
+		f, err := r.ctx.DereferenceNumber(d[k])
+		return f, err == nil
+	}
+
+	if f, ok := number("LW"); ok {
+		r.gs.lineWidth = f
+	}
+	if f, ok := number("LC"); ok && f >= 0 && f <= 2 {
+		r.rs.cap = raster.Cap(f)
+	}
+	if f, ok := number("LJ"); ok && f >= 0 && f <= 2 {
+		r.rs.join = raster.Join(f)
+	}
+	if f, ok := number("ML"); ok {
+		r.rs.miterLimit = f
+	}
+	if f, ok := number("CA"); ok {
+		r.rs.strokeAlpha = clip(f, 0, 1)
+	}
+	if f, ok := number("ca"); ok {
+		r.rs.fillAlpha = clip(f, 0, 1)
+	}
+	if a, err := r.ctx.DereferenceArray(d["D"]); err == nil && len(a) == 2 {
+		dashes, err := r.ctx.DereferenceArray(a[0])
+		phase, err1 := r.ctx.DereferenceNumber(a[1])
+		if err == nil && err1 == nil {
+			r.setDash(dashes, phase)
+		}
+	}
+}
+
+func (r *renderer) colorOp(op content.Operation, resources types.Dict) {
+	if r.uncolored {
+		return
+	}
+
+	oo := op.Operands
+
+	p := &r.rs.fill
+	switch op.Operator {
+	case "CS", "SC", "SCN", "G", "RG", "K":
+		p = &r.rs.stroke
+	}
+
+	setColor := func(cs *colorSpace) {
+		if ff, ok := floats(oo, cs.n); ok {
+			*p = paint{cs: cs, rgb: cs.rgb(ff)}
+		}
+	}
+
+	switch op.Operator {
+
+	case "CS", "cs":
+		cs, err := r.colorSpace(resources, lastOperand(oo), 0)
+		if err != nil {
+			return
+		}
+		*p = paint{cs: cs}
+		if cs.family == model.PatternCS {
+			p.pattern = transparent{}
+			return
+		}
+		p.rgb = cs.rgb(cs.initial())
+
+	case "G", "g":
+		setColor(csGray)
+
+	case "RG", "rg":
+		setColor(csRGB)
+
+	case "K", "k":
+		setColor(csCMYK)
+
+	default:
+		cs := p.cs
+		if cs.family != model.PatternCS {
+			if ff, ok := floats(oo, cs.n); ok {
+				p.rgb = cs.rgb(ff)
+			}
+			return
+		}
+		n, ok := lastOperand(oo).(types.Name)
+		if !ok {
+			return
+		}
+		if cs.base != nil {
+			if ff, ok := floats(oo[:len(oo)-1], cs.base.n); ok {
+				p.rgb = cs.base.rgb(ff)
+			}
+		}
+		p.pattern = r.pattern(resources, n.Value(), p.rgb)
+	}
+}
+
+func (r *renderer) showText(op content.Operation, resources types.Dict, depth int) {
+	f := func(g placedGlyph) {
+		r.glyph(g, resources, depth)
+	}
+
+	if op.Operator == "TJ" {
+		if a, ok := lastOperand(op.Operands).(types.Array); ok {
+			r.showArray(a, f)
+		}
+		return
+	}
+
+	r.showString(lastOperand(op.Operands), f)
+}
+
+func (r *renderer) operation(op content.Operation, resources types.Dict, depth int) error {
+	r.state(op, resources)
+
+	switch op.Operator {
+
+	case "q":
+		r.rstack = append(r.rstack, r.rs)
+
+	case "Q":
+		if n := len(r.rstack); n > 0 {
+			r.rs = r.rstack[n-1]
+			r.rstack = r.rstack[:n-1]
+		}
+
+	case "J", "j", "M", "d":
+		r.lineStyle(op)
+
+	case "gs":
+		r.extGState(resources, lastOperand(op.Operands))
+
+	case "m", "l", "c", "v", "y", "h", "re":
+		r.pathOp(op)
+
+	case "S", "s", "f", "F", "f*", "B", "B*", "b", "b*", "n":
+		r.paintPath(op.Operator)
+
+	case "W":
+		r.clipRule = 1
+
+	case "W*":
+		r.clipRule = 2
+
+	case "CS", "cs", "SC", "SCN", "sc", "scn", "G", "g", "RG", "rg", "K", "k":
+		r.colorOp(op, resources)
+
+	case "sh":
+		if n, ok := lastOperand(op.Operands).(types.Name); ok {
+			r.shade(resources, n.Value())
+		}
+
+	case "Do":
+		if n, ok := lastOperand(op.Operands).(types.Name); ok {
+			return r.doXObject(resources, n.Value(), depth)
+		}
+
+	case "BI":
+		r.inlineImage(op, resources)
+
+	case "BT":
+		r.textClip, r.textClipping = raster.Path{}, false
+
+	case "ET":
+		if r.textClipping {
+			r.clip(&r.textClip, false)
+		}
+		r.textClip, r.textClipping = raster.Path{}, false
+
+	case "Tf":
+		if len(op.Operands) == 2 {
+			if n, ok := op.Operands[0].(types.Name); ok {
+				r.loadFont(resources, n.Value())
+			}
+		}
+
+	case "Tr":
+		if ff, ok := floats(op.Operands, 1); ok && ff[0] >= 0 && ff[0] <= 7 {
+			r.rs.renderMode = int(ff[0])
+		}
+
+	case "Tj", "'", "\"", "TJ":
+		r.showText(op, resources, depth)
+
+	case "d1":
+		r.uncolored = true
+	}
+
+	return nil
+}
+
+func (r *renderer) process(ops []content.Operation, resources types.Dict, depth int) error {
+	for _, op := range ops {
+		if r.markedContent(op, resources) {
+			continue
+		}
+		if err := r.operation(op, resources, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runForm renders the content ops of the form sd with resources res using the form matrix m.
+func (r *renderer) runForm(sd *types.StreamDict, ops []content.Operation, res types.Dict, m matrix.Matrix, depth int) error {
+	restore := r.enter(m)
+	defer restore()
+
+	r.clipRect(numbers(r.ctx.XRefTable, sd.Dict["BBox"]))
+
+	return r.process(ops, res, depth+1)
+}
+
+func (r *renderer) doXObject(resources types.Dict, name string, depth int) error {
+	xObjs, err := r.ctx.DereferenceDict(resources["XObject"])
+	if err != nil || xObjs == nil {
+		return nil
+	}
+	o, found := xObjs.Find(name)
+	if !found {
+		return nil
+	}
+	sd, _, err := r.ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return nil
+	}
+
+	if !r.visible(sd.Dict["OC"]) {
+		return nil
+	}
+
+	st := sd.Dict.Subtype()
+	if st == nil {
+		return nil
+	}
+
+	switch *st {
+
+	case "Image":
+		objNr := -1
+		if ir, ok := o.(types.IndirectRef); ok {
+			objNr = ir.ObjectNumber.Value()
+		}
+		r.imageXObject(sd, objNr, resources)
+
+	case "Form":
+		if depth >= maxFormDepth {
+			return nil
+		}
+		sd, ops, res, err := r.formXObject(resources, name)
+		if err != nil || sd == nil {
+			return nil
+		}
+		m := matrix.IdentMatrix
+		if ff, ok := floats(sd.Dict.ArrayEntry("Matrix"), 6); ok {
+			m = matrixFor(ff)
+		}
+		return r.runForm(sd, ops, res, m, depth)
+	}
+
+	return nil
+}
+
+// appearance returns the normal appearance stream of the annotation d.
+func (r *renderer) appearance(d types.Dict) *types.StreamDict {
+	ap, err := r.ctx.DereferenceDict(d["AP"])
+	if err != nil || ap == nil {
+		return nil
+	}
+	o := ap["N"]
+	if sd, _, err := r.ctx.DereferenceStreamDict(o); err == nil && sd != nil {
+		return sd
+	}
+	states, err := r.ctx.DereferenceDict(o)
+	if err != nil || states == nil {
+		return nil
+	}
+	as := d.NameEntry("AS")
+	if as == nil {
+		return nil
+	}
+	sd, _, err := r.ctx.DereferenceStreamDict(states[*as])
+	if err != nil {
+		return nil
+	}
+	return sd
+}
+
+// annotation renders the appearance stream sd of an annotation into the annotation rectangle rect.
+func (r *renderer) annotation(sd *types.StreamDict, rect []float64) error {
+	bbox := numbers(r.ctx.XRefTable, sd.Dict["BBox"])
+	if len(bbox) != 4 || len(rect) != 4 {
+		return nil
+	}
+
+	m := matrix.IdentMatrix
+	if ff, ok := floats(sd.Dict.ArrayEntry("Matrix"), 6); ok {
+		m = matrixFor(ff)
+	}
+
+	// Map the transformed appearance box onto the annotation rectangle.
+	b := bboxForPoints(m.Transform(types.Point{X: bbox[0], Y: bbox[1]}), m.Transform(types.Point{X: bbox[2], Y: bbox[1]}),
+		m.Transform(types.Point{X: bbox[2], Y: bbox[3]}), m.Transform(types.Point{X: bbox[0], Y: bbox[3]}))
+	if b.Width() == 0 || b.Height() == 0 {
+		return nil
+	}
+	llx, lly := math.Min(rect[0], rect[2]), math.Min(rect[1], rect[3])
+	sx, sy := math.Abs(rect[2]-rect[0])/b.Width(), math.Abs(rect[3]-rect[1])/b.Height()
+	a := translation(-b.LL.X, -b.LL.Y).Multiply(matrix.Matrix{{sx, 0, 0}, {0, sy, 0}, {0, 0, 1}}).Multiply(translation(llx, lly))
+
+	if err := sd.Decode(); err != nil {
+		return nil
+	}
+	ops, err := content.Parse(sd.Content)
+	if err != nil {
+		return nil
+	}
+	res, err := r.ctx.DereferenceDict(sd.Dict["Resources"])
+	if err != nil {
+		return nil
+	}
+
+	return r.runForm(sd, ops, res, m.Multiply(a), 0)
+}
+
+func (r *renderer) annotations(pageDict types.Dict) error {
+	annots, err := r.ctx.DereferenceArray(pageDict["Annots"])
+	if err != nil {
+		return nil
+	}
+
+	for _, o := range annots {
+		d, err := r.ctx.DereferenceDict(o)
+		if err != nil || d == nil {
+			continue
+		}
+		if f := d.IntEntry("F"); f != nil && *f&(annHidden|annNoView) != 0 {
+			continue
+		}
+		if !r.visible(d["OC"]) {
+			continue
+		}
+		sd := r.appearance(d)
+		if sd == nil {
+			continue
+		}
+		if err := r.annotation(sd, numbers(r.ctx.XRefTable, d["Rect"])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deviceMatrix returns the matrix mapping the default user space of a page to device space
+// for the visible page region box, the page rotation rot and a scale factor s.
+func deviceMatrix(box *types.Rectangle, rot int, s float64) matrix.Matrix {
+	llx, lly, urx, ury := box.LL.X, box.LL.Y, box.UR.X, box.UR.Y
+	switch rot {
+	case 90:
+		return matrix.Matrix{{0, s, 0}, {s, 0, 0}, {-lly * s, -llx * s, 1}}
+	case 180:
+		return matrix.Matrix{{-s, 0, 0}, {0, s, 0}, {urx * s, -lly * s, 1}}
+	case 270:
+		return matrix.Matrix{{0, -s, 0}, {-s, 0, 0}, {ury * s, urx * s, 1}}
+	}
+	return matrix.Matrix{{s, 0, 0}, {0, -s, 0}, {-llx * s, ury * s, 1}}
+}
+
+// RenderPage renders page pageNr at a resolution of dpi dots per inch including annotation appearances.
+func RenderPage(ctx *model.Context, pageNr int, dpi float64) (*image.RGBA, error) {
+	if dpi <= 0 {
+		return nil, errors.Errorf("pdfcpu: RenderPage: invalid resolution %.2f", dpi)
+	}
+
+	d, _, inhPAttrs, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, errors.Errorf("pdfcpu: RenderPage: missing page %d", pageNr)
+	}
+
+	box := inhPAttrs.MediaBox
+	if inhPAttrs.CropBox != nil {
+		box = inhPAttrs.CropBox
+	}
+	if box == nil {
+		box = types.RectForFormat("Letter")
+	}
+
+	rot := ((inhPAttrs.Rotate % 360) + 360) % 360
+	rot -= rot % 90
+
+	s := dpi / 72
+	w, h := int(math.Ceil(box.Width()*s-0.01)), int(math.Ceil(box.Height()*s-0.01))
+	if rot == 90 || rot == 270 {
+		w, h = h, w
+	}
+	if w <= 0 || h <= 0 || w*h > maxRenderPixels {
+		return nil, errors.Errorf("pdfcpu: RenderPage: invalid image size %d x %d", w, h)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+
+	r := newRenderer(ctx, img, deviceMatrix(box, rot, s), newRenderCache(ctx))
+
+	bb, err := ctx.PageContent(d)
+	if err != nil && err != model.ErrNoContent {
+		return nil, err
+	}
+
+	if err == nil {
+		ops, err := content.Parse(bb)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.process(ops, inhPAttrs.Resources, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.annotations(d); err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
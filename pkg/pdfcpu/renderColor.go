@@ -0,0 +1,370 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// See 8.6 Colour Spaces
+
+const maxColorSpaceDepth = 8
+
+var errCorruptColorSpace = errors.New("pdfcpu: corrupt color space")
+
+// colorSpace converts color values of a PDF colour space to RGB.
+type colorSpace struct {
+	family string
+	n      int         // number of color components
+	base   *colorSpace // Indexed, Separation, DeviceN and Pattern
+	lookup []byte      // Indexed
+	hival  int         // Indexed
+	tint   *function   // Separation, DeviceN
+	none   bool        // Separation None
+	wp     [3]float64  // Lab
+	rng    []float64   // Lab
+}
+
+var (
+	csGray = &colorSpace{family: model.DeviceGrayCS, n: 1}
+	csRGB  = &colorSpace{family: model.DeviceRGBCS, n: 3}
+	csCMYK = &colorSpace{family: model.DeviceCMYKCS, n: 4}
+)
+
+// rgbColor is a color with components ranging from 0 to 1.
+type rgbColor [3]float64
+
+func deviceColorSpace(name string) *colorSpace {
+	switch name {
+	case model.DeviceGrayCS, "G", model.CalGrayCS:
+		return csGray
+	case model.DeviceRGBCS, "RGB", model.CalRGBCS:
+		return csRGB
+	case model.DeviceCMYKCS, "CMYK":
+		return csCMYK
+	case model.PatternCS:
+		return &colorSpace{family: model.PatternCS}
+	}
+	return nil
+}
+
+// colorSpace resolves the colour space o using resources for named colour spaces.
+func (r *renderer) colorSpace(resources types.Dict, o types.Object, depth int) (*colorSpace, error) {
+	if depth > maxColorSpaceDepth {
+		return nil, errCorruptColorSpace
+	}
+
+	indRef, isIndRef := o.(types.IndirectRef)
+	if isIndRef {
+		if cs, ok := r.colorSpaces[indRef.ObjectNumber.Value()]; ok {
+			return cs, nil
+		}
+	}
+
+	o, err := r.ctx.Dereference(o)
+	if err != nil || o == nil {
+		return nil, errCorruptColorSpace
+	}
+
+	var cs *colorSpace
+
+	switch o := o.(type) {
+
+	case types.Name:
+		if cs = deviceColorSpace(o.Value()); cs != nil {
+			return cs, nil
+		}
+		if resources == nil {
+			return nil, errCorruptColorSpace
+		}
+		d, err := r.ctx.DereferenceDict(resources["ColorSpace"])
+		if err != nil || d == nil {
+			return nil, errCorruptColorSpace
+		}
+		o1, found := d.Find(o.Value())
+		if !found {
+			return nil, errCorruptColorSpace
+		}
+		return r.colorSpace(nil, o1, depth+1)
+
+	case types.Array:
+		if cs, err = r.colorSpaceArray(resources, o, depth); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, errCorruptColorSpace
+	}
+
+	if isIndRef {
+		r.colorSpaces[indRef.ObjectNumber.Value()] = cs
+	}
+
+	return cs, nil
+}
+
+func (r *renderer) iccBased(o types.Object, depth int) (*colorSpace, error) {
+	sd, _, err := r.ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return nil, errCorruptColorSpace
+	}
+	if alt, found := sd.Dict.Find("Alternate"); found {
+		if cs, err := r.colorSpace(nil, alt, depth+1); err == nil && cs.n > 0 {
+			return cs, nil
+		}
+	}
+	n := sd.IntEntry("N")
+	if n == nil {
+		return nil, errCorruptColorSpace
+	}
+	switch *n {
+	case 1:
+		return csGray, nil
+	case 3:
+		return csRGB, nil
+	case 4:
+		return csCMYK, nil
+	}
+	return nil, errCorruptColorSpace
+}
+
+func (r *renderer) colorSpaceArray(resources types.Dict, a types.Array, depth int) (*colorSpace, error) {
+	if len(a) == 0 {
+		return nil, errCorruptColorSpace
+	}
+	n, ok := a[0].(types.Name)
+	if !ok {
+		return nil, errCorruptColorSpace
+	}
+
+	switch n.Value() {
+
+	case model.DeviceGrayCS, model.DeviceRGBCS, model.DeviceCMYKCS, model.CalGrayCS, model.CalRGBCS, "G", "RGB", "CMYK":
+		return deviceColorSpace(n.Value()), nil
+
+	case model.ICCBasedCS:
+		if len(a) < 2 {
+			return nil, errCorruptColorSpace
+		}
+		return r.iccBased(a[1], depth)
+
+	case model.LabCS:
+		cs := &colorSpace{family: model.LabCS, n: 3, wp: [3]float64{0.9505, 1, 1.089}, rng: []float64{-100, 100, -100, 100}}
+		if len(a) > 1 {
+			if d, err := r.ctx.DereferenceDict(a[1]); err == nil && d != nil {
+				if wp := numbers(r.ctx.XRefTable, d["WhitePoint"]); len(wp) == 3 {
+					cs.wp = [3]float64{wp[0], wp[1], wp[2]}
+				}
+				if rng := numbers(r.ctx.XRefTable, d["Range"]); len(rng) == 4 {
+					cs.rng = rng
+				}
+			}
+		}
+		return cs, nil
+
+	case model.IndexedCS, "I":
+		return r.indexed(resources, a, depth)
+
+	case model.SeparationCS, model.DeviceNCS:
+		return r.separation(resources, a, depth)
+
+	case model.PatternCS:
+		cs := &colorSpace{family: model.PatternCS}
+		if len(a) > 1 {
+			base, err := r.colorSpace(resources, a[1], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			cs.base = base
+		}
+		return cs, nil
+	}
+
+	return nil, errCorruptColorSpace
+}
+
+func (r *renderer) indexed(resources types.Dict, a types.Array, depth int) (*colorSpace, error) {
+	if len(a) != 4 {
+		return nil, errCorruptColorSpace
+	}
+	base, err := r.colorSpace(resources, a[1], depth+1)
+	if err != nil {
+		return nil, err
+	}
+	hival, err := r.ctx.DereferenceInteger(a[2])
+	if err != nil || hival == nil {
+		return nil, errCorruptColorSpace
+	}
+	lookup, err := colorLookupTable(r.ctx.XRefTable, a[3])
+	if err != nil {
+		return nil, err
+	}
+	return &colorSpace{family: model.IndexedCS, n: 1, base: base, hival: hival.Value(), lookup: lookup}, nil
+}
+
+func (r *renderer) separation(resources types.Dict, a types.Array, depth int) (*colorSpace, error) {
+	if len(a) < 4 {
+		return nil, errCorruptColorSpace
+	}
+	cs := &colorSpace{family: a[0].(types.Name).Value(), n: 1}
+
+	switch o := a[1].(type) {
+	case types.Name:
+		cs.none = o.Value() == "None"
+	case types.Array:
+		cs.n = len(o)
+		cs.none = true
+		for _, o1 := range o {
+			if n, ok := o1.(types.Name); !ok || n.Value() != "None" {
+				cs.none = false
+			}
+		}
+	default:
+		if a1, err := r.ctx.DereferenceArray(a[1]); err == nil {
+			cs.n = len(a1)
+		}
+	}
+
+	base, err := r.colorSpace(resources, a[2], depth+1)
+	if err != nil {
+		return nil, err
+	}
+	cs.base = base
+
+	if cs.tint, err = parseFunction(r.ctx.XRefTable, a[3], 0); err != nil {
+		return nil, err
+	}
+
+	return cs, nil
+}
+
+// initial returns the initial color values for cs.
+func (cs *colorSpace) initial() []float64 {
+	switch cs.family {
+	case model.DeviceCMYKCS:
+		return []float64{0, 0, 0, 1}
+	case model.SeparationCS, model.DeviceNCS:
+		c := make([]float64, cs.n)
+		for i := range c {
+			c[i] = 1
+		}
+		return c
+	}
+	return make([]float64, cs.n)
+}
+
+// defaultDecode returns the default decode array for images using cs.
+func (cs *colorSpace) defaultDecode(bpc int) []float64 {
+	if cs.family == model.IndexedCS {
+		return []float64{0, math.Exp2(float64(bpc)) - 1}
+	}
+	if cs.family == model.LabCS {
+		return []float64{0, 100, cs.rng[0], cs.rng[1], cs.rng[2], cs.rng[3]}
+	}
+	d := make([]float64, 2*cs.n)
+	for i := 0; i < cs.n; i++ {
+		d[2*i+1] = 1
+	}
+	return d
+}
+
+func labToRGB(l, a, b float64, wp [3]float64) rgbColor {
+	g := func(x float64) float64 {
+		if x >= 6.0/29 {
+			return x * x * x
+		}
+		return 108.0 / 841 * (x - 4.0/29)
+	}
+	m := (l + 16) / 116
+	x, y, z := wp[0]*g(m+a/500), wp[1]*g(m), wp[2]*g(m-b/200)
+
+	// XYZ (D65) to linear sRGB
+	rl := 3.2406*x - 1.5372*y - 0.4986*z
+	gl := -0.9689*x + 1.8758*y + 0.0415*z
+	bl := 0.0557*x - 0.2040*y + 1.0570*z
+
+	gamma := func(c float64) float64 {
+		c = clip(c, 0, 1)
+		if c <= 0.0031308 {
+			return 12.92 * c
+		}
+		return 1.055*math.Pow(c, 1/2.4) - 0.055
+	}
+	return rgbColor{gamma(rl), gamma(gl), gamma(bl)}
+}
+
+func cmykToRGB(c, m, y, k float64) rgbColor {
+	return rgbColor{(1 - c) * (1 - k), (1 - m) * (1 - k), (1 - y) * (1 - k)}
+}
+
+func component(c []float64, i int) float64 {
+	if i < len(c) {
+		return clip(c[i], 0, 1)
+	}
+	return 0
+}
+
+// rgb converts the color values c of cs into RGB.
+func (cs *colorSpace) rgb(c []float64) rgbColor {
+	switch cs.family {
+
+	case model.DeviceGrayCS:
+		v := component(c, 0)
+		return rgbColor{v, v, v}
+
+	case model.DeviceRGBCS:
+		return rgbColor{component(c, 0), component(c, 1), component(c, 2)}
+
+	case model.DeviceCMYKCS:
+		return cmykToRGB(component(c, 0), component(c, 1), component(c, 2), component(c, 3))
+
+	case model.LabCS:
+		if len(c) < 3 {
+			return rgbColor{}
+		}
+		return labToRGB(c[0], c[1], c[2], cs.wp)
+
+	case model.IndexedCS:
+		if len(c) == 0 || cs.base == nil {
+			return rgbColor{}
+		}
+		i := int(math.Max(0, math.Min(float64(cs.hival), math.Round(c[0]))))
+		n := cs.base.n
+		cc := make([]float64, n)
+		for j := range cc {
+			if k := i*n + j; k < len(cs.lookup) {
+				cc[j] = float64(cs.lookup[k]) / 255
+			}
+		}
+		if cs.base.family == model.LabCS {
+			cc = []float64{cc[0] * 100, interpolate(cc[1], 0, 1, cs.base.rng[0], cs.base.rng[1]), interpolate(cc[2], 0, 1, cs.base.rng[2], cs.base.rng[3])}
+		}
+		return cs.base.rgb(cc)
+
+	case model.SeparationCS, model.DeviceNCS:
+		if cs.tint == nil || cs.base == nil {
+			return rgbColor{}
+		}
+		return cs.base.rgb(cs.tint.eval(c))
+	}
+
+	return rgbColor{}
+}
@@ -0,0 +1,648 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// See 7.10 Functions
+
+const (
+	maxFunctionDepth = 8
+	maxSampleCount   = 1 << 24
+	maxPSOps         = 10000
+)
+
+var errCorruptFunction = errors.New("pdfcpu: corrupt function")
+
+// function represents a PDF function of type 0, 2, 3 or 4 resp. an array of 1-in functions.
+type function struct {
+	typ    int
+	domain []float64
+	rng    []float64
+
+	// Type 0
+	size    []int
+	bps     int
+	encode  []float64
+	decode  []float64
+	samples []float64
+
+	// Type 2
+	c0, c1 []float64
+	n      float64
+
+	// Type 3 and function arrays
+	fns    []*function
+	bounds []float64
+
+	// Type 4
+	prog []psOp
+}
+
+type psOp struct {
+	op         string
+	val        float64
+	then, els  []psOp
+	isProcCall bool
+}
+
+func clip(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+func interpolate(x, xmin, xmax, ymin, ymax float64) float64 {
+	if xmax == xmin {
+		return ymin
+	}
+	return ymin + (x-xmin)*(ymax-ymin)/(xmax-xmin)
+}
+
+func numbers(xRefTable *model.XRefTable, o types.Object) []float64 {
+	a, err := xRefTable.DereferenceArray(o)
+	if err != nil {
+		return nil
+	}
+	ff := make([]float64, 0, len(a))
+	for _, o := range a {
+		f, err := xRefTable.DereferenceNumber(o)
+		if err != nil {
+			return nil
+		}
+		ff = append(ff, f)
+	}
+	return ff
+}
+
+func parseFunction(xRefTable *model.XRefTable, o types.Object, depth int) (*function, error) {
+	if depth > maxFunctionDepth {
+		return nil, errCorruptFunction
+	}
+
+	o, err := xRefTable.Dereference(o)
+	if err != nil || o == nil {
+		return nil, errCorruptFunction
+	}
+
+	if a, ok := o.(types.Array); ok {
+		// An array of n 1-in 1-out functions.
+		f := &function{typ: -1}
+		for _, o := range a {
+			fn, err := parseFunction(xRefTable, o, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			f.fns = append(f.fns, fn)
+		}
+		return f, nil
+	}
+
+	var (
+		d  types.Dict
+		sd *types.StreamDict
+	)
+
+	switch o := o.(type) {
+	case types.Dict:
+		d = o
+	case types.StreamDict:
+		sd = &o
+		d = o.Dict
+	default:
+		return nil, errCorruptFunction
+	}
+
+	f := &function{domain: numbers(xRefTable, d["Domain"]), rng: numbers(xRefTable, d["Range"])}
+	if i := d.IntEntry("FunctionType"); i != nil {
+		f.typ = *i
+	}
+	if len(f.domain) < 2 {
+		f.domain = []float64{0, 1}
+	}
+
+	switch f.typ {
+	case 0:
+		err = f.sampled(xRefTable, d, sd)
+	case 2:
+		f.exponential(xRefTable, d)
+	case 3:
+		err = f.stitching(xRefTable, d, depth)
+	case 4:
+		err = f.postScript(sd)
+	default:
+		err = errCorruptFunction
+	}
+
+	return f, err
+}
+
+func (f *function) sampled(xRefTable *model.XRefTable, d types.Dict, sd *types.StreamDict) error {
+	if sd == nil || len(f.rng) < 2 {
+		return errCorruptFunction
+	}
+	m, n := len(f.domain)/2, len(f.rng)/2
+
+	for _, s := range numbers(xRefTable, d["Size"]) {
+		f.size = append(f.size, int(s))
+	}
+	if len(f.size) != m {
+		return errCorruptFunction
+	}
+
+	f.bps = 8
+	if i := d.IntEntry("BitsPerSample"); i != nil {
+		f.bps = *i
+	}
+
+	f.encode = numbers(xRefTable, d["Encode"])
+	if len(f.encode) != 2*m {
+		f.encode = make([]float64, 2*m)
+		for i, s := range f.size {
+			f.encode[2*i+1] = float64(s - 1)
+		}
+	}
+	f.decode = numbers(xRefTable, d["Decode"])
+	if len(f.decode) != 2*n {
+		f.decode = f.rng
+	}
+
+	total := n
+	for _, s := range f.size {
+		if s < 1 || total > maxSampleCount/s {
+			return errCorruptFunction
+		}
+		total *= s
+	}
+
+	if err := sd.Decode(); err != nil {
+		return err
+	}
+
+	maxVal := math.Exp2(float64(f.bps)) - 1
+	f.samples = make([]float64, total)
+	bits := &bitReader{bb: sd.Content}
+	for i := range f.samples {
+		f.samples[i] = float64(bits.read(f.bps)) / maxVal
+	}
+
+	return nil
+}
+
+func (f *function) exponential(xRefTable *model.XRefTable, d types.Dict) {
+	f.c0, f.c1 = numbers(xRefTable, d["C0"]), numbers(xRefTable, d["C1"])
+	if f.c0 == nil {
+		f.c0 = []float64{0}
+	}
+	if f.c1 == nil {
+		f.c1 = []float64{1}
+	}
+	f.n = 1
+	if n, err := xRefTable.DereferenceNumber(d["N"]); err == nil {
+		f.n = n
+	}
+}
+
+func (f *function) stitching(xRefTable *model.XRefTable, d types.Dict, depth int) error {
+	a, err := xRefTable.DereferenceArray(d["Functions"])
+	if err != nil || len(a) == 0 {
+		return errCorruptFunction
+	}
+	for _, o := range a {
+		fn, err := parseFunction(xRefTable, o, depth+1)
+		if err != nil {
+			return err
+		}
+		f.fns = append(f.fns, fn)
+	}
+	f.bounds = numbers(xRefTable, d["Bounds"])
+	f.encode = numbers(xRefTable, d["Encode"])
+	if len(f.bounds) != len(f.fns)-1 || len(f.encode) != 2*len(f.fns) {
+		return errCorruptFunction
+	}
+	return nil
+}
+
+func parsePSProc(tokens []string, i int) ([]psOp, int, error) {
+	var prog []psOp
+	for i < len(tokens) {
+		t := tokens[i]
+		i++
+		switch t {
+		case "{":
+			proc, j, err := parsePSProc(tokens, i)
+			if err != nil {
+				return nil, 0, err
+			}
+			i = j
+			prog = append(prog, psOp{then: proc, isProcCall: true})
+		case "}":
+			return prog, i, nil
+		case "if", "ifelse":
+			// Attach the preceding procedures.
+			if t == "if" && len(prog) >= 1 && prog[len(prog)-1].isProcCall {
+				p := prog[len(prog)-1].then
+				prog = append(prog[:len(prog)-1], psOp{op: "if", then: p})
+				continue
+			}
+			if t == "ifelse" && len(prog) >= 2 && prog[len(prog)-1].isProcCall && prog[len(prog)-2].isProcCall {
+				p1, p2 := prog[len(prog)-2].then, prog[len(prog)-1].then
+				prog = append(prog[:len(prog)-2], psOp{op: "ifelse", then: p1, els: p2})
+				continue
+			}
+			return nil, 0, errCorruptFunction
+		default:
+			if v, err := strconv.ParseFloat(t, 64); err == nil {
+				prog = append(prog, psOp{op: "num", val: v})
+				continue
+			}
+			prog = append(prog, psOp{op: t})
+		}
+	}
+	return prog, i, nil
+}
+
+func (f *function) postScript(sd *types.StreamDict) error {
+	if sd == nil || len(f.rng) < 2 {
+		return errCorruptFunction
+	}
+	if err := sd.Decode(); err != nil {
+		return err
+	}
+	s := string(sd.Content)
+	s = strings.NewReplacer("{", " { ", "}", " } ").Replace(s)
+	tokens := strings.Fields(s)
+	if len(tokens) < 2 || tokens[0] != "{" {
+		return errCorruptFunction
+	}
+	prog, _, err := parsePSProc(tokens, 1)
+	if err != nil {
+		return err
+	}
+	f.prog = prog
+	return nil
+}
+
+type psStack struct {
+	vals []float64
+	ops  int
+}
+
+func (s *psStack) push(v ...float64) {
+	s.vals = append(s.vals, v...)
+}
+
+func (s *psStack) pop() float64 {
+	n := len(s.vals)
+	if n == 0 {
+		return 0
+	}
+	v := s.vals[n-1]
+	s.vals = s.vals[:n-1]
+	return v
+}
+
+func boolVal(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (s *psStack) binary(op string) bool {
+	switch op {
+	case "add", "sub", "mul", "div", "idiv", "mod", "exp", "atan", "and", "or", "xor", "bitshift", "eq", "ne", "gt", "ge", "lt", "le":
+	default:
+		return false
+	}
+	b, a := s.pop(), s.pop()
+	var v float64
+	switch op {
+	case "add":
+		v = a + b
+	case "sub":
+		v = a - b
+	case "mul":
+		v = a * b
+	case "div":
+		if b != 0 {
+			v = a / b
+		}
+	case "idiv":
+		if int(b) != 0 {
+			v = float64(int(a) / int(b))
+		}
+	case "mod":
+		if int(b) != 0 {
+			v = float64(int(a) % int(b))
+		}
+	case "exp":
+		v = math.Pow(a, b)
+	case "atan":
+		v = math.Atan2(a, b) * 180 / math.Pi
+		if v < 0 {
+			v += 360
+		}
+	case "and":
+		v = float64(int(a) & int(b))
+	case "or":
+		v = float64(int(a) | int(b))
+	case "xor":
+		v = float64(int(a) ^ int(b))
+	case "bitshift":
+		if b >= 0 {
+			v = float64(int(a) << uint(b))
+		} else {
+			v = float64(int(a) >> uint(-b))
+		}
+	case "eq":
+		v = boolVal(a == b)
+	case "ne":
+		v = boolVal(a != b)
+	case "gt":
+		v = boolVal(a > b)
+	case "ge":
+		v = boolVal(a >= b)
+	case "lt":
+		v = boolVal(a < b)
+	case "le":
+		v = boolVal(a <= b)
+	}
+	s.push(v)
+	return true
+}
+
+func (s *psStack) unary(op string) bool {
+	var fn func(float64) float64
+	switch op {
+	case "abs":
+		fn = math.Abs
+	case "neg":
+		fn = func(a float64) float64 { return -a }
+	case "ceiling":
+		fn = math.Ceil
+	case "floor":
+		fn = math.Floor
+	case "round":
+		fn = func(a float64) float64 { return math.Floor(a + 0.5) }
+	case "truncate", "cvi":
+		fn = math.Trunc
+	case "cvr":
+		fn = func(a float64) float64 { return a }
+	case "sqrt":
+		fn = func(a float64) float64 { return math.Sqrt(math.Max(0, a)) }
+	case "sin":
+		fn = func(a float64) float64 { return math.Sin(a * math.Pi / 180) }
+	case "cos":
+		fn = func(a float64) float64 { return math.Cos(a * math.Pi / 180) }
+	case "ln":
+		fn = math.Log
+	case "log":
+		fn = math.Log10
+	case "not":
+		fn = func(a float64) float64 {
+			if a == 0 || a == 1 {
+				return 1 - a
+			}
+			return float64(^int(a))
+		}
+	default:
+		return false
+	}
+	s.push(fn(s.pop()))
+	return true
+}
+
+func (s *psStack) exec(prog []psOp) {
+	for _, op := range prog {
+		if s.ops++; s.ops > maxPSOps {
+			return
+		}
+		if s.binary(op.op) || s.unary(op.op) {
+			continue
+		}
+		n := len(s.vals)
+		switch op.op {
+		case "num":
+			s.push(op.val)
+		case "true":
+			s.push(1)
+		case "false":
+			s.push(0)
+		case "dup":
+			if n > 0 {
+				s.push(s.vals[n-1])
+			}
+		case "pop":
+			s.pop()
+		case "exch":
+			if n > 1 {
+				s.vals[n-1], s.vals[n-2] = s.vals[n-2], s.vals[n-1]
+			}
+		case "copy":
+			k := int(s.pop())
+			if n = len(s.vals); k > 0 && k <= n {
+				s.push(append([]float64(nil), s.vals[n-k:]...)...)
+			}
+		case "index":
+			k := int(s.pop())
+			if n = len(s.vals); k >= 0 && k < n {
+				s.push(s.vals[n-1-k])
+			}
+		case "roll":
+			j, k := int(s.pop()), int(s.pop())
+			if n = len(s.vals); k > 0 && k <= n {
+				vv := append([]float64(nil), s.vals[n-k:]...)
+				j = ((j % k) + k) % k
+				for i := range vv {
+					s.vals[n-k+(i+j)%k] = vv[i]
+				}
+			}
+		case "if":
+			if s.pop() != 0 {
+				s.exec(op.then)
+			}
+		case "ifelse":
+			if s.pop() != 0 {
+				s.exec(op.then)
+			} else {
+				s.exec(op.els)
+			}
+		}
+	}
+}
+
+func (f *function) evalSampled(in []float64) []float64 {
+	m, n := len(f.size), len(f.rng)/2
+	if len(in) < m {
+		return make([]float64, n)
+	}
+
+	// Multilinear interpolation between the neighbouring samples.
+	e := make([]float64, m)
+	for i := 0; i < m; i++ {
+		x := clip(in[i], f.domain[2*i], f.domain[2*i+1])
+		e[i] = clip(interpolate(x, f.domain[2*i], f.domain[2*i+1], f.encode[2*i], f.encode[2*i+1]), 0, float64(f.size[i]-1))
+	}
+
+	out := make([]float64, n)
+	for corner := 0; corner < 1<<m; corner++ {
+		w, idx, stride := 1., 0, 1
+		for i := 0; i < m; i++ {
+			lo := math.Floor(e[i])
+			frac := e[i] - lo
+			k := int(lo)
+			if corner&(1<<i) != 0 {
+				w *= frac
+				if k+1 < f.size[i] {
+					k++
+				}
+			} else {
+				w *= 1 - frac
+			}
+			idx += k * stride
+			stride *= f.size[i]
+		}
+		if w == 0 {
+			continue
+		}
+		for j := 0; j < n; j++ {
+			if k := idx*n + j; k < len(f.samples) {
+				out[j] += w * f.samples[k]
+			}
+		}
+	}
+
+	for j := range out {
+		out[j] = clip(interpolate(out[j], 0, 1, f.decode[2*j], f.decode[2*j+1]), f.rng[2*j], f.rng[2*j+1])
+	}
+	return out
+}
+
+func (f *function) evalStitching(x float64) []float64 {
+	k := len(f.fns) - 1
+	for i, b := range f.bounds {
+		if x < b {
+			k = i
+			break
+		}
+	}
+	lo, hi := f.domain[0], f.domain[1]
+	if k > 0 {
+		lo = f.bounds[k-1]
+	}
+	if k < len(f.bounds) {
+		hi = f.bounds[k]
+	}
+	return f.fns[k].eval([]float64{interpolate(x, lo, hi, f.encode[2*k], f.encode[2*k+1])})
+}
+
+// eval evaluates f for the input values in.
+func (f *function) eval(in []float64) []float64 {
+	if f.typ == -1 {
+		out := make([]float64, 0, len(f.fns))
+		for _, fn := range f.fns {
+			out = append(out, fn.eval(in)...)
+		}
+		return out
+	}
+
+	in = append([]float64(nil), in...)
+	for i := range in {
+		if 2*i+1 < len(f.domain) {
+			in[i] = clip(in[i], f.domain[2*i], f.domain[2*i+1])
+		}
+	}
+
+	var out []float64
+
+	switch f.typ {
+
+	case 0:
+		return f.evalSampled(in)
+
+	case 2:
+		x := 0.
+		if len(in) > 0 {
+			x = in[0]
+		}
+		out = make([]float64, min(len(f.c0), len(f.c1)))
+		p := math.Pow(x, f.n)
+		for i := range out {
+			out[i] = f.c0[i] + p*(f.c1[i]-f.c0[i])
+		}
+
+	case 3:
+		if len(in) == 0 {
+			return nil
+		}
+		out = f.evalStitching(in[0])
+
+	case 4:
+		s := &psStack{}
+		s.push(in...)
+		s.exec(f.prog)
+		out = s.vals
+		if n := len(f.rng) / 2; len(out) > n {
+			out = out[len(out)-n:]
+		}
+	}
+
+	for i := range out {
+		if 2*i+1 < len(f.rng) {
+			out[i] = clip(out[i], f.rng[2*i], f.rng[2*i+1])
+		}
+	}
+	return out
+}
+
+// bitReader reads big endian bit fields.
+type bitReader struct {
+	bb  []byte
+	pos int // in bits
+}
+
+func (br *bitReader) read(n int) uint32 {
+	var v uint32
+	for i := 0; i < n; i++ {
+		byteNr := br.pos / 8
+		if byteNr >= len(br.bb) {
+			v <<= 1
+			br.pos++
+			continue
+		}
+		bit := br.bb[byteNr] >> (7 - br.pos%8) & 1
+		v = v<<1 | uint32(bit)
+		br.pos++
+	}
+	return v
+}
+
+// align skips to the next byte boundary.
+func (br *bitReader) align() {
+	br.pos = (br.pos + 7) / 8 * 8
+}
+
+func (br *bitReader) eof() bool {
+	return br.pos/8 >= len(br.bb)
+}
@@ -0,0 +1,541 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/raster"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// See 8.9 Images
+
+// The maximum number of pixels of an image to be rendered.
+const maxImagePixels = 1 << 26
+
+// decodedImage is an image converted to RGB.
+type decodedImage struct {
+	w, h  int
+	rgb   []uint8 // nil for stencil masks
+	alpha []uint8 // nil for opaque images
+}
+
+var inlineImageKeys = map[string]string{
+	"BPC": "BitsPerComponent",
+	"CS":  "ColorSpace",
+	"D":   "Decode",
+	"DP":  "DecodeParms",
+	"F":   "Filter",
+	"H":   "Height",
+	"IM":  "ImageMask",
+	"I":   "Interpolate",
+	"W":   "Width",
+}
+
+var inlineImageFilters = map[string]string{
+	"AHx": filter.ASCIIHex,
+	"A85": filter.ASCII85,
+	"LZW": filter.LZW,
+	"Fl":  filter.Flate,
+	"RL":  filter.RunLength,
+	"CCF": filter.CCITTFax,
+	"DCT": filter.DCT,
+}
+
+// expandInlineImageDict replaces the abbreviations used in the dict of an inline image.
+func expandInlineImageDict(d types.Dict) types.Dict {
+	filterName := func(o types.Object) types.Object {
+		if n, ok := o.(types.Name); ok {
+			if s, ok := inlineImageFilters[n.Value()]; ok {
+				return types.Name(s)
+			}
+		}
+		return o
+	}
+
+	d1 := types.Dict{}
+	for k, v := range d {
+		if s, ok := inlineImageKeys[k]; ok {
+			k = s
+		}
+		if k == "Filter" {
+			if a, ok := v.(types.Array); ok {
+				a1 := make(types.Array, len(a))
+				for i, o := range a {
+					a1[i] = filterName(o)
+				}
+				v = a1
+			} else {
+				v = filterName(v)
+			}
+		}
+		d1[k] = v
+	}
+	return d1
+}
+
+// imageData returns the data of sd decoded up to an image specific filter along with the name of that filter.
+func imageData(sd *types.StreamDict) ([]byte, string, error) {
+	fp := sd.FilterPipeline
+	if n := len(fp); n > 0 {
+		switch last := fp[n-1].Name; last {
+		case filter.DCT, filter.JPX:
+			if n == 1 {
+				return sd.Raw, last, nil
+			}
+			sd1 := *sd
+			sd1.FilterPipeline, sd1.Content = fp[:n-1], nil
+			if err := sd1.Decode(); err != nil {
+				return nil, "", err
+			}
+			return sd1.Content, last, nil
+		}
+	}
+
+	if err := sd.Decode(); err != nil {
+		return nil, "", err
+	}
+	return sd.Content, "", nil
+}
+
+// jpegSamples decodes a JPEG image into 8 bit samples.
+func jpegSamples(bb []byte) ([]byte, int, int, int, error) {
+	im, err := jpeg.Decode(bytes.NewReader(bb))
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	b := im.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	switch im := im.(type) {
+
+	case *image.Gray:
+		data := make([]byte, 0, w*h)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			data = append(data, im.Pix[im.PixOffset(b.Min.X, y):im.PixOffset(b.Max.X, y)]...)
+		}
+		return data, w, h, 1, nil
+
+	case *image.CMYK:
+		data := make([]byte, 0, 4*w*h)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			data = append(data, im.Pix[im.PixOffset(b.Min.X, y):im.PixOffset(b.Max.X, y)]...)
+		}
+		return data, w, h, 4, nil
+	}
+
+	data := make([]byte, 0, 3*w*h)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			cr, cg, cb, _ := im.At(x, y).RGBA()
+			data = append(data, byte(cr>>8), byte(cg>>8), byte(cb>>8))
+		}
+	}
+	return data, w, h, 3, nil
+}
+
+// jpxSamples returns the 8 bit samples of a JPEG 2000 image along with its dimensions and number of components.
+func jpxSamples(bb []byte) ([]byte, int, int, int, error) {
+	info, err := filter.DecodeJPXInfo(bb)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	f, err := filter.NewFilter(filter.JPX, nil)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	r, err := f.Decode(bytes.NewReader(bb))
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, 0, 0, 0, err
+	}
+
+	w, h, n := info.Width, info.Height, info.Comps
+	if n != 1 && n != 3 && n != 4 || buf.Len() < w*h*n {
+		return nil, 0, 0, 0, errors.New("pdfcpu: unsupported JPEG 2000 image")
+	}
+
+	return buf.Bytes(), w, h, n, nil
+}
+
+func rowSamples(row []byte, bpc int, out []uint16) {
+	switch bpc {
+	case 8:
+		for i := range out {
+			out[i] = 0
+			if i < len(row) {
+				out[i] = uint16(row[i])
+			}
+		}
+	case 16:
+		for i := range out {
+			out[i] = 0
+			if 2*i+1 < len(row) {
+				out[i] = uint16(row[2*i])<<8 | uint16(row[2*i+1])
+			}
+		}
+	default:
+		br := bitReader{bb: row}
+		for i := range out {
+			out[i] = uint16(br.read(bpc))
+		}
+	}
+}
+
+// resample scales the w x h channel values cc to w1 x h1 using nearest neighbours.
+func resample(cc []uint8, w, h, w1, h1 int) []uint8 {
+	if w == w1 && h == h1 {
+		return cc
+	}
+	res := make([]uint8, w1*h1)
+	for y := 0; y < h1; y++ {
+		sy := y * h / h1
+		for x := 0; x < w1; x++ {
+			res[y*w1+x] = cc[sy*w+x*w/w1]
+		}
+	}
+	return res
+}
+
+// imageMask returns the alpha channel of im resulting from its SMask or Mask entry.
+func (r *renderer) imageMask(d types.Dict, im *decodedImage, depth int) []uint8 {
+	if depth > 0 {
+		return nil
+	}
+
+	if sd, _, err := r.ctx.DereferenceStreamDict(d["SMask"]); err == nil && sd != nil {
+		m := r.decodeImage(sd, nil, depth+1)
+		if m == nil || m.rgb == nil {
+			return nil
+		}
+		gray := make([]uint8, m.w*m.h)
+		for i := range gray {
+			gray[i] = m.rgb[3*i]
+		}
+		return resample(gray, m.w, m.h, im.w, im.h)
+	}
+
+	if sd, _, err := r.ctx.DereferenceStreamDict(d["Mask"]); err == nil && sd != nil {
+		m := r.decodeImage(sd, nil, depth+1)
+		if m == nil || m.rgb != nil {
+			return nil
+		}
+		return resample(m.alpha, m.w, m.h, im.w, im.h)
+	}
+
+	return nil
+}
+
+// decodeImage converts the image XObject or inline image sd to RGB.
+func (r *renderer) decodeImage(sd *types.StreamDict, resources types.Dict, depth int) *decodedImage {
+	d := sd.Dict
+
+	w, h := d.IntEntry("Width"), d.IntEntry("Height")
+	if w == nil || h == nil || *w <= 0 || *h <= 0 || *w**h > maxImagePixels {
+		return nil
+	}
+	im := &decodedImage{w: *w, h: *h}
+
+	if err := r.ctx.ResolveJBIG2Globals(sd); err != nil {
+		return nil
+	}
+
+	data, last, err := imageData(sd)
+	if err != nil {
+		return nil
+	}
+
+	stencil := false
+	if b := d.BooleanEntry("ImageMask"); b != nil && *b {
+		stencil = true
+	}
+
+	bpc := 1
+	if i := d.IntEntry("BitsPerComponent"); i != nil {
+		bpc = *i
+	}
+
+	var cs *colorSpace
+	if !stencil {
+		if cs, err = r.colorSpace(resources, d["ColorSpace"], 0); err != nil {
+			cs = nil
+		}
+	}
+
+	decode := numbers(r.ctx.XRefTable, d["Decode"])
+
+	switch last {
+
+	case filter.DCT:
+		var n, w1, h1 int
+		if data, w1, h1, n, err = jpegSamples(data); err != nil {
+			return nil
+		}
+		im.w, im.h, bpc = w1, h1, 8
+		if stencil || n == 4 || cs == nil || cs.n != n {
+			// Adobe CMYK JPEGs are inverted by the JPEG decoder.
+			cs, decode, stencil = []*colorSpace{nil, csGray, nil, csRGB, csCMYK}[n], nil, false
+		}
+
+	case filter.JPX:
+		var n, w1, h1 int
+		if data, w1, h1, n, err = jpxSamples(data); err != nil {
+			return nil
+		}
+		im.w, im.h, bpc, decode = w1, h1, 8, nil
+		if stencil || cs == nil || cs.n != n {
+			cs, stencil = []*colorSpace{nil, csGray, nil, csRGB, csCMYK}[n], false
+		}
+	}
+
+	n := 1
+	if !stencil {
+		if cs == nil || cs.n == 0 || cs.family == model.PatternCS {
+			return nil
+		}
+		n = cs.n
+	}
+
+	if bpc != 1 && bpc != 2 && bpc != 4 && bpc != 8 && bpc != 16 {
+		return nil
+	}
+
+	if len(decode) != 2*n {
+		if stencil {
+			decode = []float64{0, 1}
+		} else {
+			decode = cs.defaultDecode(bpc)
+		}
+	}
+
+	maxV := math.Exp2(float64(bpc)) - 1
+	decoded := func(i int, s uint16) float64 {
+		return decode[2*i] + float64(s)*(decode[2*i+1]-decode[2*i])/maxV
+	}
+
+	// Color key masking
+	var colorKey []int
+	if a, err := r.ctx.DereferenceArray(d["Mask"]); err == nil && len(a) == 2*n {
+		for _, o := range a {
+			i, err := r.ctx.DereferenceInteger(o)
+			if err != nil || i == nil {
+				colorKey = nil
+				break
+			}
+			colorKey = append(colorKey, i.Value())
+		}
+	}
+
+	var lut [][3]uint8
+	if n == 1 && !stencil {
+		lut = make([][3]uint8, int(maxV)+1)
+		for s := range lut {
+			c := cs.rgb([]float64{decoded(0, uint16(s))})
+			lut[s] = [3]uint8{uint8(c[0]*255 + 0.5), uint8(c[1]*255 + 0.5), uint8(c[2]*255 + 0.5)}
+		}
+	}
+
+	if stencil {
+		im.alpha = make([]uint8, im.w*im.h)
+	} else {
+		im.rgb = make([]uint8, 3*im.w*im.h)
+	}
+	if colorKey != nil {
+		im.alpha = make([]uint8, im.w*im.h)
+	}
+
+	stride := (im.w*n*bpc + 7) / 8
+	samples := make([]uint16, im.w*n)
+	vals := make([]float64, n)
+
+	for y := 0; y < im.h; y++ {
+		var row []byte
+		if off := y * stride; off < len(data) {
+			row = data[off:min(off+stride, len(data))]
+		}
+		rowSamples(row, bpc, samples)
+
+		for x := 0; x < im.w; x++ {
+			p := y*im.w + x
+			ss := samples[x*n : x*n+n]
+
+			if stencil {
+				if decoded(0, ss[0]) < 0.5 {
+					im.alpha[p] = 255
+				}
+				continue
+			}
+
+			if colorKey != nil {
+				im.alpha[p] = 0
+				for i, s := range ss {
+					if int(s) < colorKey[2*i] || int(s) > colorKey[2*i+1] {
+						im.alpha[p] = 255
+						break
+					}
+				}
+			}
+
+			if lut != nil {
+				c := lut[ss[0]]
+				copy(im.rgb[3*p:], c[:])
+				continue
+			}
+
+			for i, s := range ss {
+				vals[i] = decoded(i, s)
+			}
+			c := cs.rgb(vals)
+			im.rgb[3*p], im.rgb[3*p+1], im.rgb[3*p+2] = uint8(c[0]*255+0.5), uint8(c[1]*255+0.5), uint8(c[2]*255+0.5)
+		}
+	}
+
+	if !stencil {
+		if alpha := r.imageMask(d, im, depth); alpha != nil {
+			im.alpha = alpha
+		}
+	}
+
+	return im
+}
+
+// drawImage paints im into the unit square of user space.
+func (r *renderer) drawImage(im *decodedImage) {
+	ctm := r.gs.ctm
+
+	var p raster.Path
+	p.Rect(0, 0, 1, 1)
+	mask := raster.Fill(p.Transform(ctm).Flatten(flatness), false, r.clipBounds())
+	if mask == nil {
+		return
+	}
+	mask = raster.Intersect(mask, r.rs.clip)
+
+	inv, ok := invert(ctm)
+	if !ok {
+		return
+	}
+
+	// Supersample images scaled down.
+	sx := float64(im.w) / math.Max(1, math.Hypot(ctm[0][0], ctm[0][1]))
+	sy := float64(im.h) / math.Max(1, math.Hypot(ctm[1][0], ctm[1][1]))
+	k := int(math.Min(4, math.Max(1, math.Ceil(math.Max(sx, sy)))))
+	kk := float64(k * k)
+
+	rect := mask.Rect.Intersect(r.img.Rect)
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			m := mask.Pix[mask.PixOffset(x, y)]
+			if m == 0 {
+				continue
+			}
+
+			var c rgbColor
+			var ca float64
+
+			for j := 0; j < k; j++ {
+				for i := 0; i < k; i++ {
+					pt := inv.Transform(types.Point{X: float64(x) + (float64(i)+0.5)/float64(k), Y: float64(y) + (float64(j)+0.5)/float64(k)})
+					ix := min(max(int(pt.X*float64(im.w)), 0), im.w-1)
+					iy := min(max(int((1-pt.Y)*float64(im.h)), 0), im.h-1)
+					ip := iy*im.w + ix
+					a := 1.
+					if im.alpha != nil {
+						a = float64(im.alpha[ip]) / 255
+					}
+					if im.rgb != nil {
+						c[0] += float64(im.rgb[3*ip]) * a
+						c[1] += float64(im.rgb[3*ip+1]) * a
+						c[2] += float64(im.rgb[3*ip+2]) * a
+					}
+					ca += a
+				}
+			}
+
+			if ca == 0 {
+				continue
+			}
+
+			if im.rgb != nil {
+				c = rgbColor{c[0] / ca / 255, c[1] / ca / 255, c[2] / ca / 255}
+			} else {
+				// Stencil masks are painted using the fill color.
+				c = r.rs.fill.rgb
+				if r.rs.fill.pattern != nil {
+					var pa float64
+					c, pa = r.rs.fill.pattern.colorAt(x, y)
+					ca *= pa
+				}
+			}
+
+			a := ca / kk * float64(m) / 255 * r.rs.fillAlpha
+			if a > 0 {
+				pi := r.img.PixOffset(x, y)
+				blend(r.img.Pix[pi:pi+4], c, a)
+			}
+		}
+	}
+}
+
+func (r *renderer) imageXObject(sd *types.StreamDict, objNr int, resources types.Dict) {
+	im, ok := r.images[objNr]
+	if !ok {
+		im = r.decodeImage(sd, resources, 0)
+		if objNr >= 0 {
+			r.images[objNr] = im
+		}
+	}
+	if im != nil {
+		r.drawImage(im)
+	}
+}
+
+func (r *renderer) inlineImage(op content.Operation, resources types.Dict) {
+	if op.ImageDict == nil {
+		return
+	}
+	d := expandInlineImageDict(op.ImageDict)
+
+	fp, err := pdfFilterPipeline(context.Background(), r.ctx, d)
+	if err != nil {
+		return
+	}
+
+	sd := types.NewStreamDict(d, 0, nil, nil, fp)
+	sd.Raw = op.ImageData
+
+	if im := r.decodeImage(&sd, resources, 0); im != nil {
+		r.drawImage(im)
+	}
+}
@@ -0,0 +1,724 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// See 8.7 Patterns
+
+const (
+	// The maximum width and height of a rendered tiling pattern cell in pixels.
+	maxPatternCell = 1024
+
+	// The maximum nesting level of patterns.
+	maxPatternDepth = 4
+
+	lutSize = 256
+)
+
+// shading is a paint source for a shading of any type.
+type shading struct {
+	typ        int
+	cs         *colorSpace
+	fn         *function
+	domain     []float64
+	coords     []float64
+	extend     [2]bool
+	inv        matrix.Matrix // device space to shading space
+	fnInv      matrix.Matrix // Type 1: shading space to domain space
+	bbox       []float64
+	background *rgbColor
+	lut        []rgbColor  // Types 2 and 3
+	layer      *image.RGBA // Types 4 to 7
+}
+
+// meshVertex is a vertex in device space along with its color values.
+type meshVertex struct {
+	p types.Point
+	c []float64
+}
+
+func (sh *shading) color(vals []float64) rgbColor {
+	if sh.fn != nil {
+		vals = sh.fn.eval(vals)
+	}
+	return sh.cs.rgb(vals)
+}
+
+func (sh *shading) extended(s float64) (float64, bool) {
+	if s < 0 {
+		if !sh.extend[0] {
+			return 0, false
+		}
+		s = 0
+	}
+	if s > 1 {
+		if !sh.extend[1] {
+			return 0, false
+		}
+		s = 1
+	}
+	return s, true
+}
+
+func (sh *shading) axial(p types.Point) (float64, bool) {
+	x0, y0, x1, y1 := sh.coords[0], sh.coords[1], sh.coords[2], sh.coords[3]
+	dx, dy := x1-x0, y1-y0
+	den := dx*dx + dy*dy
+	if den == 0 {
+		return 0, false
+	}
+	return sh.extended(((p.X-x0)*dx + (p.Y-y0)*dy) / den)
+}
+
+func (sh *shading) radial(p types.Point) (float64, bool) {
+	x0, y0, r0, x1, y1, r1 := sh.coords[0], sh.coords[1], sh.coords[2], sh.coords[3], sh.coords[4], sh.coords[5]
+	cx, cy, dr := x1-x0, y1-y0, r1-r0
+	px, py := p.X-x0, p.Y-y0
+
+	// Solve |p - c(s)| = r(s) for the largest s with r(s) >= 0.
+	a := cx*cx + cy*cy - dr*dr
+	b := px*cx + py*cy + r0*dr
+	c := px*px + py*py - r0*r0
+
+	var ss []float64
+	if math.Abs(a) < 1e-12 {
+		if b == 0 {
+			return 0, false
+		}
+		ss = []float64{c / (2 * b)}
+	} else {
+		disc := b*b - a*c
+		if disc < 0 {
+			return 0, false
+		}
+		sq := math.Sqrt(disc)
+		s1, s2 := (b+sq)/a, (b-sq)/a
+		ss = []float64{math.Max(s1, s2), math.Min(s1, s2)}
+	}
+
+	for _, s := range ss {
+		if r0+s*dr < 0 {
+			continue
+		}
+		if s, ok := sh.extended(s); ok {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+func (sh *shading) colorAt(x, y int) (rgbColor, float64) {
+	if sh.layer != nil {
+		if (image.Point{X: x, Y: y}).In(sh.layer.Rect) {
+			i := sh.layer.PixOffset(x, y)
+			if pix := sh.layer.Pix[i : i+4]; pix[3] != 0 {
+				return rgbColor{float64(pix[0]) / 255, float64(pix[1]) / 255, float64(pix[2]) / 255}, 1
+			}
+		}
+		if sh.background != nil {
+			return *sh.background, 1
+		}
+		return rgbColor{}, 0
+	}
+
+	p := sh.inv.Transform(types.Point{X: float64(x) + 0.5, Y: float64(y) + 0.5})
+	if b := sh.bbox; b != nil && (p.X < b[0] || p.X > b[2] || p.Y < b[1] || p.Y > b[3]) {
+		return rgbColor{}, 0
+	}
+
+	var (
+		c  rgbColor
+		s  float64
+		ok bool
+	)
+
+	switch sh.typ {
+	case 1:
+		q := sh.fnInv.Transform(p)
+		d := sh.domain
+		if ok = q.X >= d[0] && q.X <= d[1] && q.Y >= d[2] && q.Y <= d[3]; ok {
+			c = sh.color([]float64{q.X, q.Y})
+		}
+	case 2:
+		s, ok = sh.axial(p)
+	case 3:
+		s, ok = sh.radial(p)
+	}
+
+	if !ok {
+		if sh.background != nil {
+			return *sh.background, 1
+		}
+		return rgbColor{}, 0
+	}
+
+	if sh.lut != nil {
+		c = sh.lut[int(s*(lutSize-1)+0.5)]
+	}
+
+	return c, 1
+}
+
+// triangle paints a Gouraud shaded triangle into the mesh layer.
+func (sh *shading) triangle(a, b, c meshVertex) {
+	r := image.Rect(
+		int(math.Floor(math.Min(a.p.X, math.Min(b.p.X, c.p.X)))),
+		int(math.Floor(math.Min(a.p.Y, math.Min(b.p.Y, c.p.Y)))),
+		int(math.Ceil(math.Max(a.p.X, math.Max(b.p.X, c.p.X))))+1,
+		int(math.Ceil(math.Max(a.p.Y, math.Max(b.p.Y, c.p.Y))))+1,
+	).Intersect(sh.layer.Rect)
+
+	area := (b.p.X-a.p.X)*(c.p.Y-a.p.Y) - (c.p.X-a.p.X)*(b.p.Y-a.p.Y)
+	if r.Empty() || math.Abs(area) < 1e-12 {
+		return
+	}
+
+	vals := make([]float64, len(a.c))
+	const eps = -1e-9
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		py := float64(y) + 0.5
+		for x := r.Min.X; x < r.Max.X; x++ {
+			px := float64(x) + 0.5
+			wa := ((b.p.X-px)*(c.p.Y-py) - (c.p.X-px)*(b.p.Y-py)) / area
+			wb := ((c.p.X-px)*(a.p.Y-py) - (a.p.X-px)*(c.p.Y-py)) / area
+			wc := 1 - wa - wb
+			if wa < eps || wb < eps || wc < eps {
+				continue
+			}
+			for i := range vals {
+				vals[i] = wa*a.c[i] + wb*b.c[i] + wc*c.c[i]
+			}
+			col := sh.color(vals)
+			i := sh.layer.PixOffset(x, y)
+			sh.layer.Pix[i], sh.layer.Pix[i+1], sh.layer.Pix[i+2], sh.layer.Pix[i+3] = uint8(col[0]*255+0.5), uint8(col[1]*255+0.5), uint8(col[2]*255+0.5), 255
+		}
+	}
+}
+
+// The order of the control points of a tensor-product patch within the shading data.
+var patchPointOrder = [16][2]int{
+	{0, 0}, {0, 1}, {0, 2}, {0, 3}, {1, 3}, {2, 3}, {3, 3}, {3, 2},
+	{3, 1}, {3, 0}, {2, 0}, {1, 0}, {1, 1}, {1, 2}, {2, 2}, {2, 1},
+}
+
+// patch is a tensor-product patch with the colors of the corners p00, p03, p33 and p30.
+type patch struct {
+	p [4][4]types.Point
+	c [4][]float64
+}
+
+func bernstein(t float64) [4]float64 {
+	mt := 1 - t
+	return [4]float64{mt * mt * mt, 3 * t * mt * mt, 3 * t * t * mt, t * t * t}
+}
+
+// coonsInterior computes the interior control points of a Coons patch.
+func (pt *patch) coonsInterior() {
+	p := &pt.p
+	f := func(a, b, c, d, e, g, h, i types.Point) types.Point {
+		x := (-4*a.X + 6*(b.X+c.X) - 2*(d.X+e.X) + 3*(g.X+h.X) - i.X) / 9
+		y := (-4*a.Y + 6*(b.Y+c.Y) - 2*(d.Y+e.Y) + 3*(g.Y+h.Y) - i.Y) / 9
+		return types.Point{X: x, Y: y}
+	}
+	p[1][1] = f(p[0][0], p[0][1], p[1][0], p[0][3], p[3][0], p[3][1], p[1][3], p[3][3])
+	p[1][2] = f(p[0][3], p[0][2], p[1][3], p[0][0], p[3][3], p[3][2], p[1][0], p[3][0])
+	p[2][1] = f(p[3][0], p[3][1], p[2][0], p[3][3], p[0][0], p[0][1], p[2][3], p[0][3])
+	p[2][2] = f(p[3][3], p[3][2], p[2][3], p[3][0], p[0][3], p[0][2], p[2][0], p[0][0])
+}
+
+func (sh *shading) patch(pt *patch) {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for i := range pt.p {
+		for _, q := range pt.p[i] {
+			minX, minY = math.Min(minX, q.X), math.Min(minY, q.Y)
+			maxX, maxY = math.Max(maxX, q.X), math.Max(maxY, q.Y)
+		}
+	}
+	if !image.Rect(int(minX), int(minY), int(maxX)+1, int(maxY)+1).Overlaps(sh.layer.Rect) {
+		return
+	}
+
+	n := int(math.Min(64, math.Max(2, math.Ceil(math.Max(maxX-minX, maxY-minY)/4))))
+
+	grid := make([][]meshVertex, n+1)
+	for i := 0; i <= n; i++ {
+		u := float64(i) / float64(n)
+		bu := bernstein(u)
+		grid[i] = make([]meshVertex, n+1)
+		for j := 0; j <= n; j++ {
+			v := float64(j) / float64(n)
+			bv := bernstein(v)
+			var q types.Point
+			for k := 0; k < 4; k++ {
+				for l := 0; l < 4; l++ {
+					q.X += bu[k] * bv[l] * pt.p[k][l].X
+					q.Y += bu[k] * bv[l] * pt.p[k][l].Y
+				}
+			}
+			c := make([]float64, len(pt.c[0]))
+			for k := range c {
+				c[k] = (1-u)*(1-v)*pt.c[0][k] + (1-u)*v*pt.c[1][k] + u*v*pt.c[2][k] + u*(1-v)*pt.c[3][k]
+			}
+			grid[i][j] = meshVertex{p: q, c: c}
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			sh.triangle(grid[i][j], grid[i+1][j], grid[i][j+1])
+			sh.triangle(grid[i+1][j], grid[i+1][j+1], grid[i][j+1])
+		}
+	}
+}
+
+// mesh paints the triangles or patches of the shading types 4 to 7 into the mesh layer.
+func (r *renderer) mesh(sh *shading, sd *types.StreamDict) {
+	if err := sd.Decode(); err != nil {
+		return
+	}
+	d := sd.Dict
+
+	bpCoord, bpComp, bpFlag := 0, 0, 0
+	if i := d.IntEntry("BitsPerCoordinate"); i != nil {
+		bpCoord = *i
+	}
+	if i := d.IntEntry("BitsPerComponent"); i != nil {
+		bpComp = *i
+	}
+	if i := d.IntEntry("BitsPerFlag"); i != nil {
+		bpFlag = *i
+	}
+	if bpCoord <= 0 || bpCoord > 32 || bpComp <= 0 || bpComp > 16 || (sh.typ != 5 && (bpFlag <= 0 || bpFlag > 8)) {
+		return
+	}
+
+	nc := sh.cs.n
+	if sh.fn != nil {
+		nc = 1
+	}
+	decode := numbers(r.ctx.XRefTable, d["Decode"])
+	if len(decode) < 4+2*nc {
+		return
+	}
+
+	m, ok := invert(sh.inv)
+	if !ok {
+		return
+	}
+
+	br := &bitReader{bb: sd.Content}
+	maxCoord, maxComp := math.Exp2(float64(bpCoord))-1, math.Exp2(float64(bpComp))-1
+
+	point := func() types.Point {
+		x := interpolate(float64(br.read(bpCoord)), 0, maxCoord, decode[0], decode[1])
+		y := interpolate(float64(br.read(bpCoord)), 0, maxCoord, decode[2], decode[3])
+		return m.Transform(types.Point{X: x, Y: y})
+	}
+	color := func() []float64 {
+		c := make([]float64, nc)
+		for i := range c {
+			c[i] = interpolate(float64(br.read(bpComp)), 0, maxComp, decode[4+2*i], decode[5+2*i])
+		}
+		return c
+	}
+	available := func(bits int) bool {
+		return br.pos+bits <= 8*len(br.bb)
+	}
+
+	vertexBits := 2*bpCoord + nc*bpComp
+
+	switch sh.typ {
+
+	case 4:
+		var tri []meshVertex
+		for available(bpFlag + vertexBits) {
+			f := br.read(bpFlag)
+			v := meshVertex{p: point(), c: color()}
+			br.align()
+			if f == 0 && len(tri) == 3 {
+				tri = tri[:0]
+			}
+			if len(tri) < 3 {
+				if tri = append(tri, v); len(tri) == 3 {
+					sh.triangle(tri[0], tri[1], tri[2])
+				}
+				continue
+			}
+			if f == 1 {
+				tri = []meshVertex{tri[1], tri[2], v}
+			} else {
+				tri = []meshVertex{tri[0], tri[2], v}
+			}
+			sh.triangle(tri[0], tri[1], tri[2])
+		}
+
+	case 5:
+		vpr := d.IntEntry("VerticesPerRow")
+		if vpr == nil || *vpr < 2 {
+			return
+		}
+		var vv []meshVertex
+		for available(vertexBits) {
+			vv = append(vv, meshVertex{p: point(), c: color()})
+		}
+		w := *vpr
+		for i := 0; i+1 < len(vv)/w; i++ {
+			for j := 0; j+1 < w; j++ {
+				a, b, c, e := vv[i*w+j], vv[i*w+j+1], vv[(i+1)*w+j], vv[(i+1)*w+j+1]
+				sh.triangle(a, b, c)
+				sh.triangle(b, e, c)
+			}
+		}
+
+	case 6, 7:
+		np := 12
+		if sh.typ == 7 {
+			np = 16
+		}
+		var prev *patch
+		for available(bpFlag + (np-4)*2*bpCoord + 2*nc*bpComp) {
+			f := br.read(bpFlag)
+			pt := &patch{}
+			first, firstColor := 0, 0
+			if f != 0 && prev != nil {
+				p, c := prev.p, prev.c
+				var edge [4]types.Point
+				switch f {
+				case 1:
+					edge, pt.c[0], pt.c[1] = [4]types.Point{p[0][3], p[1][3], p[2][3], p[3][3]}, c[1], c[2]
+				case 2:
+					edge, pt.c[0], pt.c[1] = [4]types.Point{p[3][3], p[3][2], p[3][1], p[3][0]}, c[2], c[3]
+				default:
+					edge, pt.c[0], pt.c[1] = [4]types.Point{p[3][0], p[2][0], p[1][0], p[0][0]}, c[3], c[0]
+				}
+				for i := 0; i < 4; i++ {
+					pt.p[0][i] = edge[i]
+				}
+				first, firstColor = 4, 2
+			}
+			for _, ij := range patchPointOrder[first:np] {
+				pt.p[ij[0]][ij[1]] = point()
+			}
+			for i := firstColor; i < 4; i++ {
+				pt.c[i] = color()
+			}
+			br.align()
+			if sh.typ == 6 {
+				pt.coonsInterior()
+			}
+			sh.patch(pt)
+			prev = pt
+		}
+	}
+}
+
+// shading returns a paint source for the shading o using m as shading space to device space mapping.
+// The background color applies to shading patterns only.
+func (r *renderer) shading(o types.Object, m matrix.Matrix, bounds image.Rectangle, background bool) *shading {
+	var (
+		sd *types.StreamDict
+		d  types.Dict
+	)
+	if sd1, _, err := r.ctx.DereferenceStreamDict(o); err == nil && sd1 != nil {
+		sd, d = sd1, sd1.Dict
+	} else if d, err = r.ctx.DereferenceDict(o); err != nil || d == nil {
+		return nil
+	}
+
+	typ := d.IntEntry("ShadingType")
+	if typ == nil || *typ < 1 || *typ > 7 {
+		return nil
+	}
+
+	cs, err := r.colorSpace(nil, d["ColorSpace"], 0)
+	if err != nil || cs.n == 0 {
+		return nil
+	}
+
+	inv, ok := invert(m)
+	if !ok {
+		return nil
+	}
+
+	sh := &shading{typ: *typ, cs: cs, inv: inv}
+
+	if _, found := d.Find("Function"); found {
+		if sh.fn, err = parseFunction(r.ctx.XRefTable, d["Function"], 0); err != nil {
+			return nil
+		}
+	}
+
+	if bbox := numbers(r.ctx.XRefTable, d["BBox"]); len(bbox) == 4 {
+		sh.bbox = []float64{math.Min(bbox[0], bbox[2]), math.Min(bbox[1], bbox[3]), math.Max(bbox[0], bbox[2]), math.Max(bbox[1], bbox[3])}
+	}
+
+	if bg := numbers(r.ctx.XRefTable, d["Background"]); background && len(bg) == cs.n {
+		c := cs.rgb(bg)
+		sh.background = &c
+	}
+
+	switch sh.typ {
+
+	case 1:
+		if sh.fn == nil {
+			return nil
+		}
+		sh.domain = numbers(r.ctx.XRefTable, d["Domain"])
+		if len(sh.domain) != 4 {
+			sh.domain = []float64{0, 1, 0, 1}
+		}
+		sh.fnInv = matrix.IdentMatrix
+		if ff := numbers(r.ctx.XRefTable, d["Matrix"]); len(ff) == 6 {
+			if sh.fnInv, ok = invert(matrixFor(ff)); !ok {
+				return nil
+			}
+		}
+
+	case 2, 3:
+		n := 4
+		if sh.typ == 3 {
+			n = 6
+		}
+		if sh.coords = numbers(r.ctx.XRefTable, d["Coords"]); len(sh.coords) != n || sh.fn == nil {
+			return nil
+		}
+		sh.domain = numbers(r.ctx.XRefTable, d["Domain"])
+		if len(sh.domain) != 2 {
+			sh.domain = []float64{0, 1}
+		}
+		if a, err := r.ctx.DereferenceArray(d["Extend"]); err == nil && len(a) == 2 {
+			for i, o := range a {
+				if b, ok := o.(types.Boolean); ok {
+					sh.extend[i] = b.Value()
+				}
+			}
+		}
+		sh.lut = make([]rgbColor, lutSize)
+		for i := range sh.lut {
+			t := interpolate(float64(i), 0, lutSize-1, sh.domain[0], sh.domain[1])
+			sh.lut[i] = sh.color([]float64{t})
+		}
+
+	default:
+		if sd == nil {
+			return nil
+		}
+		sh.layer = image.NewRGBA(bounds)
+		r.mesh(sh, sd)
+	}
+
+	return sh
+}
+
+// shade paints the shading name of resources into the current clipping region.
+func (r *renderer) shade(resources types.Dict, name string) {
+	shs, err := r.ctx.DereferenceDict(resources["Shading"])
+	if err != nil || shs == nil {
+		return
+	}
+	o, found := shs.Find(name)
+	if !found {
+		return
+	}
+
+	bounds := r.clipBounds()
+	sh := r.shading(o, r.gs.ctm, bounds, false)
+	if sh == nil {
+		return
+	}
+
+	mask := image.NewAlpha(bounds)
+	for i := range mask.Pix {
+		mask.Pix[i] = 0xFF
+	}
+	r.composite(mask, paint{pattern: sh}, r.rs.fillAlpha)
+}
+
+// tilingPattern is a paint source repeating a rendered pattern cell.
+type tilingPattern struct {
+	cell *image.RGBA
+	inv  matrix.Matrix // device space to cell space
+}
+
+func (tp *tilingPattern) colorAt(x, y int) (rgbColor, float64) {
+	p := tp.inv.Transform(types.Point{X: float64(x) + 0.5, Y: float64(y) + 0.5})
+	w, h := tp.cell.Rect.Dx(), tp.cell.Rect.Dy()
+	ix, iy := int(math.Floor(p.X))%w, int(math.Floor(p.Y))%h
+	if ix < 0 {
+		ix += w
+	}
+	if iy < 0 {
+		iy += h
+	}
+	i := tp.cell.PixOffset(ix, iy)
+	pix := tp.cell.Pix[i : i+4]
+	if pix[3] == 0 {
+		return rgbColor{}, 0
+	}
+	a := float64(pix[3]) / 255
+	return rgbColor{float64(pix[0]) / 255 / a, float64(pix[1]) / 255 / a, float64(pix[2]) / 255 / a}, a
+}
+
+// tiling renders a cell of the tiling pattern sd using m as pattern space to device space mapping.
+// Uncolored patterns are painted using c.
+func (r *renderer) tiling(sd *types.StreamDict, m matrix.Matrix, c rgbColor) paintSource {
+	d := sd.Dict
+
+	bbox := numbers(r.ctx.XRefTable, d["BBox"])
+	xStep, err := r.ctx.DereferenceNumber(d["XStep"])
+	if err != nil {
+		return nil
+	}
+	yStep, err := r.ctx.DereferenceNumber(d["YStep"])
+	if err != nil || len(bbox) != 4 {
+		return nil
+	}
+	xStep, yStep = math.Abs(xStep), math.Abs(yStep)
+
+	s := math.Sqrt(math.Abs(m[0][0]*m[1][1] - m[0][1]*m[1][0]))
+	if s == 0 || xStep == 0 || yStep == 0 {
+		return nil
+	}
+
+	cw := int(math.Min(maxPatternCell, math.Max(1, math.Ceil(xStep*s))))
+	ch := int(math.Min(maxPatternCell, math.Max(1, math.Ceil(yStep*s))))
+	sx, sy := float64(cw)/xStep, float64(ch)/yStep
+
+	x0, y0 := math.Min(bbox[0], bbox[2]), math.Min(bbox[1], bbox[3])
+	toCell := translation(-x0, -y0).Multiply(matrix.Matrix{{sx, 0, 0}, {0, -sy, 0}, {0, 0, 1}}).Multiply(translation(0, float64(ch)))
+
+	if err := sd.Decode(); err != nil {
+		return nil
+	}
+	ops, err := content.Parse(sd.Content)
+	if err != nil {
+		return nil
+	}
+	res, err := r.ctx.DereferenceDict(d["Resources"])
+	if err != nil {
+		return nil
+	}
+
+	cell := image.NewRGBA(image.Rect(0, 0, cw, ch))
+	cr := newRenderer(r.ctx, cell, toCell, r.renderCache)
+	if pt := d.IntEntry("PaintType"); pt != nil && *pt == 2 {
+		cr.rs.fill = paint{cs: csRGB, rgb: c}
+		cr.rs.stroke = cr.rs.fill
+		cr.uncolored = true
+	}
+
+	// Render neighbouring cells overlapping this one.
+	n := 0
+	if math.Abs(bbox[2]-bbox[0]) > xStep || math.Abs(bbox[3]-bbox[1]) > yStep {
+		n = 1
+	}
+	for i := -n; i <= n; i++ {
+		for j := -n; j <= n; j++ {
+			restore := cr.enter(translation(float64(i)*xStep, float64(j)*yStep))
+			cr.clipRect([]float64{x0, y0, math.Max(bbox[0], bbox[2]), math.Max(bbox[1], bbox[3])})
+			cr.process(ops, res, 0)
+			restore()
+		}
+	}
+
+	inv, ok := invert(m)
+	if !ok {
+		return nil
+	}
+
+	return &tilingPattern{cell: cell, inv: inv.Multiply(toCell)}
+}
+
+// pattern returns a paint source for the pattern name of resources.
+// Uncolored tiling patterns are painted using c.
+func (r *renderer) pattern(resources types.Dict, name string, c rgbColor) paintSource {
+	pats, err := r.ctx.DereferenceDict(resources["Pattern"])
+	if err != nil || pats == nil {
+		return transparent{}
+	}
+	o, found := pats.Find(name)
+	if !found {
+		return transparent{}
+	}
+
+	var key string
+	if ir, ok := o.(types.IndirectRef); ok {
+		key = fmt.Sprintf("%d %v %v", ir.ObjectNumber.Value(), r.base, c)
+		if ps, ok := r.patterns[key]; ok {
+			return ps
+		}
+	}
+
+	var ps paintSource = transparent{}
+
+	if r.patternDepth < maxPatternDepth {
+		r.patternDepth++
+		if p := r.newPattern(o, c); p != nil {
+			ps = p
+		}
+		r.patternDepth--
+	}
+
+	if key != "" {
+		r.patterns[key] = ps
+	}
+	return ps
+}
+
+func (r *renderer) newPattern(o types.Object, c rgbColor) paintSource {
+	var d types.Dict
+	sd, _, err := r.ctx.DereferenceStreamDict(o)
+	if err == nil && sd != nil {
+		d = sd.Dict
+	} else if d, err = r.ctx.DereferenceDict(o); err != nil || d == nil {
+		return nil
+	}
+
+	m := r.base
+	if ff := numbers(r.ctx.XRefTable, d["Matrix"]); len(ff) == 6 {
+		m = matrixFor(ff).Multiply(r.base)
+	}
+
+	pt := d.IntEntry("PatternType")
+	if pt == nil {
+		return nil
+	}
+
+	switch *pt {
+	case 1:
+		if sd == nil {
+			return nil
+		}
+		return r.tiling(sd, m, c)
+	case 2:
+		if sh := r.shading(d["Shading"], m, r.img.Rect, true); sh != nil {
+			return sh
+		}
+	}
+
+	return nil
+}
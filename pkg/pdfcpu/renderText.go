@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/content"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/raster"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// See 9.3.6 Text Rendering Mode and 9.6.5 Type 3 Fonts
+
+// loadFont prepares the current font selected as fontName for rendering.
+func (r *renderer) loadFont(resources types.Dict, fontName string) {
+	dec := r.gs.font
+	if dec == nil {
+		return
+	}
+	if _, ok := r.fonts[dec]; ok {
+		return
+	}
+
+	fonts, err := r.ctx.DereferenceDict(resources["Font"])
+	if err != nil || fonts == nil {
+		return
+	}
+	o, _ := fonts.Find(fontName)
+	d, err := r.ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return
+	}
+
+	r.fonts[dec] = &renderFont{
+		d:        d,
+		outlines: font.NewOutlines(r.ctx.XRefTable, d, dec),
+		procs:    map[string][]content.Operation{},
+	}
+}
+
+// glyph paints g according to the current text rendering mode.
+func (r *renderer) glyph(g placedGlyph, resources types.Dict, depth int) {
+	dec := r.gs.font
+	f := r.fonts[dec]
+	if f == nil {
+		return
+	}
+
+	mode := r.rs.renderMode
+
+	if dec.Subtype == "Type3" {
+		if mode != 3 && mode != 7 {
+			r.type3Glyph(g, f, resources, depth)
+		}
+		return
+	}
+
+	if mode >= 4 {
+		r.textClipping = true
+	}
+
+	p := f.outlines.Outline(g.Glyph)
+	if p == nil || p.Empty() {
+		return
+	}
+
+	switch mode {
+	case 0, 2, 4, 6:
+		pl := p.Transform(g.trm).Flatten(flatness)
+		r.composite(raster.Fill(pl, false, r.clipBounds()), r.rs.fill, r.rs.fillAlpha)
+	}
+
+	switch mode {
+	case 1, 2, 5, 6:
+		// The line width applies in user space.
+		if inv, ok := invert(r.gs.ctm); ok {
+			r.strokePath(p.Transform(g.trm.Multiply(inv)))
+		}
+	}
+
+	if mode >= 4 {
+		r.textClip.Append(p.Transform(g.trm))
+	}
+}
+
+// charProc returns the parsed glyph description for the glyph name of the Type3 font f.
+func (r *renderer) charProc(f *renderFont, name string) []content.Operation {
+	if ops, ok := f.procs[name]; ok {
+		return ops
+	}
+
+	var ops []content.Operation
+	if procs, err := r.ctx.DereferenceDict(f.d["CharProcs"]); err == nil && procs != nil {
+		if sd, _, err := r.ctx.DereferenceStreamDict(procs[name]); err == nil && sd != nil {
+			if err := sd.Decode(); err == nil {
+				ops, _ = content.Parse(sd.Content)
+			}
+		}
+	}
+
+	f.procs[name] = ops
+	return ops
+}
+
+// type3Glyph renders g by executing its glyph description.
+func (r *renderer) type3Glyph(g placedGlyph, f *renderFont, resources types.Dict, depth int) {
+	if depth >= maxFormDepth || len(g.Code) == 0 {
+		return
+	}
+
+	ops := r.charProc(f, r.gs.font.GlyphName(g.Code[0]))
+	if len(ops) == 0 {
+		return
+	}
+
+	fm := matrix.Matrix{{0.001, 0, 0}, {0, 0.001, 0}, {0, 0, 1}}
+	if ff := numbers(r.ctx.XRefTable, f.d["FontMatrix"]); len(ff) == 6 {
+		fm = matrixFor(ff)
+	}
+
+	inv, ok := invert(r.gs.ctm)
+	if !ok {
+		return
+	}
+
+	res, err := r.ctx.DereferenceDict(f.d["Resources"])
+	if err != nil || res == nil {
+		res = resources
+	}
+
+	// Glyph space maps to device space via FontMatrix x Trm.
+	restore := r.enter(fm.Multiply(g.trm).Multiply(inv))
+	defer restore()
+
+	r.process(ops, res, depth+1)
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// See 7.5.6 Incremental Updates
+
+// Revision represents a revision of an incrementally updated document.
+type Revision struct {
+	Nr         int   `json:"revision"`
+	Size       int64 `json:"size"`       // Length of the document at this revision, ends with %%EOF.
+	XRefOffset int64 `json:"xrefOffset"` // Offset of the last cross reference section of this revision.
+	Objects    int   `json:"objects"`    // Number of objects in use.
+	Changed    []int `json:"changed"`    // Objects added or modified by this revision.
+	Freed      []int `json:"freed,omitempty"`
+}
+
+func (r Revision) String() string {
+	s := fmt.Sprintf("%3d: size=%d xref@%d objects=%d changed=%s", r.Nr, r.Size, r.XRefOffset, r.Objects, objNrRanges(r.Changed))
+	if len(r.Freed) > 0 {
+		s += " freed=" + objNrRanges(r.Freed)
+	}
+	return s
+}
+
+// objNrRanges returns a compact representation of sorted object numbers eg. 1-5,7,9-10
+func objNrRanges(objNrs []int) string {
+	if len(objNrs) == 0 {
+		return "-"
+	}
+
+	var ss []string
+	for i := 0; i < len(objNrs); {
+		j := i
+		for j+1 < len(objNrs) && objNrs[j+1] == objNrs[j]+1 {
+			j++
+		}
+		s := strconv.Itoa(objNrs[i])
+		if j > i {
+			s += "-" + strconv.Itoa(objNrs[j])
+		}
+		ss = append(ss, s)
+		i = j + 1
+	}
+
+	return strings.Join(ss, ",")
+}
+
+var (
+	eofMarker     = []byte("%%EOF")
+	reStartXRef   = regexp.MustCompile(`startxref\s+\d+\s*$`)
+	ErrNoRevision = errors.New("pdfcpu: no such revision")
+)
+
+// revisionEnds returns the file offsets following all %%EOF markers which terminate a trailer.
+func revisionEnds(ra io.ReaderAt, size int64) ([]int64, error) {
+	const chunkSize = 1 << 16
+
+	var ends []int64
+
+	buf := make([]byte, chunkSize+len(eofMarker)-1)
+
+	for off := int64(0); off < size; off += chunkSize {
+		n, err := ra.ReadAt(buf[:min(int64(len(buf)), size-off)], off)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		bb := buf[:n]
+
+		for i := 0; ; {
+			j := bytes.Index(bb[i:], eofMarker)
+			if j < 0 || i+j >= chunkSize {
+				break
+			}
+			pos := off + int64(i+j)
+			i += j + len(eofMarker)
+
+			ok, err := trailerEnd(ra, pos)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+
+			end := pos + int64(len(eofMarker))
+			eol := make([]byte, 2)
+			m, _ := ra.ReadAt(eol, end)
+			if m > 0 && eol[0] == '\r' {
+				end++
+				if m > 1 && eol[1] == '\n' {
+					end++
+				}
+			} else if m > 0 && eol[0] == '\n' {
+				end++
+			}
+
+			ends = append(ends, end)
+		}
+	}
+
+	return ends, nil
+}
+
+// trailerEnd returns true if the %%EOF marker at pos is preceded by startxref.
+func trailerEnd(ra io.ReaderAt, pos int64) (bool, error) {
+	from := max(0, pos-64)
+	bb := make([]byte, pos-from)
+	if _, err := ra.ReadAt(bb, from); err != nil && err != io.EOF {
+		return false, err
+	}
+	return reStartXRef.Match(bb), nil
+}
+
+type xRefEntrySig struct {
+	free                    bool
+	offset                  int64
+	gen, objStream, objStrI int
+}
+
+func entrySig(e *model.XRefTableEntry) xRefEntrySig {
+	sig := xRefEntrySig{free: e.Free, objStream: -1}
+	if e.Offset != nil {
+		sig.offset = *e.Offset
+	}
+	if e.Generation != nil {
+		sig.gen = *e.Generation
+	}
+	if e.ObjectStream != nil {
+		sig.objStream = *e.ObjectStream
+		sig.objStrI = *e.ObjectStreamInd
+	}
+	return sig
+}
+
+func readXRefTableOnly(c context.Context, rs io.ReadSeeker, conf *model.Configuration) (*model.Context, error) {
+	ctx, err := model.NewContext(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+	if err := readXRefTable(c, ctx); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// Revisions returns all revisions of the document in ra, the oldest first.
+// The first part of a linearized file is not considered a revision on its own.
+func Revisions(ra io.ReaderAt, size int64, conf *model.Configuration) ([]Revision, error) {
+	return RevisionsWithContext(context.Background(), ra, size, conf)
+}
+
+// RevisionsWithContext returns all revisions of the document in ra, the oldest first.
+// If the passed Go context is cancelled, reading will be interrupted.
+func RevisionsWithContext(c context.Context, ra io.ReaderAt, size int64, conf *model.Configuration) ([]Revision, error) {
+	ends, err := revisionEnds(ra, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		revs []Revision
+		prev map[int]xRefEntrySig
+	)
+
+	for _, end := range ends {
+		ctx, err := readXRefTableOnly(c, io.NewSectionReader(ra, 0, end), conf)
+		if err != nil {
+			if log.InfoEnabled() {
+				log.Info.Printf("Revisions: skipping incomplete revision ending at %d: %v\n", end, err)
+			}
+			continue
+		}
+
+		rev := Revision{Nr: len(revs) + 1, Size: end, Changed: []int{}}
+		if ctx.Write.OffsetPrevXRef != nil {
+			rev.XRefOffset = *ctx.Write.OffsetPrevXRef
+		}
+
+		m := map[int]xRefEntrySig{}
+		for objNr, e := range ctx.Table {
+			if objNr == 0 {
+				continue
+			}
+			sig := entrySig(e)
+			m[objNr] = sig
+			if !sig.free {
+				rev.Objects++
+			}
+			old, found := prev[objNr]
+			if found && old == sig {
+				continue
+			}
+			if sig.free {
+				if found && !old.free {
+					rev.Freed = append(rev.Freed, objNr)
+				}
+				continue
+			}
+			rev.Changed = append(rev.Changed, objNr)
+		}
+		sort.Ints(rev.Changed)
+		sort.Ints(rev.Freed)
+
+		prev = m
+		revs = append(revs, rev)
+	}
+
+	if len(revs) == 0 {
+		return nil, errors.New("pdfcpu: no revisions found")
+	}
+
+	return revs, nil
+}
+
+// RevisionSize returns the length of the document in ra at revision nr.
+func RevisionSize(ra io.ReaderAt, size int64, nr int, conf *model.Configuration) (int64, error) {
+	revs, err := Revisions(ra, size, conf)
+	if err != nil {
+		return 0, err
+	}
+	if nr < 1 || nr > len(revs) {
+		return 0, errors.Wrapf(ErrNoRevision, "revision %d of %d", nr, len(revs))
+	}
+	return revs[nr-1].Size, nil
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// See RFC 5652 Cryptographic Message Syntax (CMS)
+
+var (
+	oidData       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+
+	oidAttributeContentType          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttributeMessageDigest        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidAttributeSigningTime          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidAttributeSigningCertificateV2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+
+	oidDigestAlgorithmSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+
+	oidEncryptionAlgorithmRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidSignatureECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+
+	errUnsupportedSignatureKeyType = errors.New("pdfcpu: unsupported signing key type (need RSA or ECDSA)")
+)
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type encapsulatedContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapsulatedContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue // issuerAndSerialNumber or [0] subjectKeyIdentifier
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+type essCertIDv2 struct {
+	CertHash []byte // hashAlgorithm defaults to SHA-256
+}
+
+type signingCertificateV2 struct {
+	Certs []essCertIDv2
+}
+
+func tlv(class, tag int, content []byte) ([]byte, error) {
+	return asn1.Marshal(asn1.RawValue{Class: class, Tag: tag, IsCompound: true, Bytes: content})
+}
+
+func newAttribute(oid asn1.ObjectIdentifier, val interface{}) ([]byte, error) {
+	bb, err := asn1.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := tlv(asn1.ClassUniversal, asn1.TagSet, bb)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(attribute{Type: oid, Values: asn1.RawValue{FullBytes: vals}})
+}
+
+// signedAttributes returns the content octets of the SET OF signed attributes, sorted according to DER.
+func signedAttributes(digest []byte, cert *x509.Certificate, signingTime time.Time, cades bool) ([]byte, error) {
+	attrs := [][]byte{}
+
+	a, err := newAttribute(oidAttributeContentType, oidData)
+	if err != nil {
+		return nil, err
+	}
+	attrs = append(attrs, a)
+
+	if a, err = newAttribute(oidAttributeMessageDigest, digest); err != nil {
+		return nil, err
+	}
+	attrs = append(attrs, a)
+
+	if cades {
+		// PAdES baseline signatures carry the signing time in the signature dict (M)
+		// and bind the signer certificate via ESS signing-certificate-v2.
+		h := sha256.Sum256(cert.Raw)
+		if a, err = newAttribute(oidAttributeSigningCertificateV2, signingCertificateV2{Certs: []essCertIDv2{{CertHash: h[:]}}}); err != nil {
+			return nil, err
+		}
+	} else if a, err = newAttribute(oidAttributeSigningTime, signingTime.UTC()); err != nil {
+		return nil, err
+	}
+	attrs = append(attrs, a)
+
+	sort.Slice(attrs, func(i, j int) bool { return bytes.Compare(attrs[i], attrs[j]) < 0 })
+
+	return bytes.Join(attrs, nil), nil
+}
+
+func signatureAlgorithm(pub crypto.PublicKey) (pkix.AlgorithmIdentifier, error) {
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidEncryptionAlgorithmRSA, Parameters: asn1.NullRawValue}, nil
+	case *ecdsa.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSignatureECDSAWithSHA256}, nil
+	}
+	return pkix.AlgorithmIdentifier{}, errUnsupportedSignatureKeyType
+}
+
+// NewSignedData creates a DER encoded detached CMS SignedData (ContentInfo) for digest, a SHA-256 hash of the signed content.
+// certs[0] is expected to be the signer certificate matching signer.
+// For cades == true the result is compatible to ETSI.CAdES.detached (PAdES baseline).
+func NewSignedData(digest []byte, signer crypto.Signer, certs []*x509.Certificate, signingTime time.Time, cades bool) ([]byte, error) {
+	if len(certs) == 0 {
+		return nil, errors.New("pdfcpu: missing signer certificate")
+	}
+	cert := certs[0]
+
+	sigAlg, err := signatureAlgorithm(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := signedAttributes(digest, cert, signingTime, cades)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature is calculated over the DER encoding of the SET OF signed attributes.
+	attrSet, err := tlv(asn1.ClassUniversal, asn1.TagSet, attrs)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(attrSet)
+
+	sig, err := signer.Sign(rand.Reader, h[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	signedAttrs, err := tlv(asn1.ClassContextSpecific, 0, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	var bb []byte
+	for _, c := range certs {
+		bb = append(bb, c.Raw...)
+	}
+	rawCerts, err := tlv(asn1.ClassContextSpecific, 0, bb)
+	if err != nil {
+		return nil, err
+	}
+
+	digestAlg := pkix.AlgorithmIdentifier{Algorithm: oidDigestAlgorithmSHA256}
+
+	sid, err := asn1.Marshal(issuerAndSerialNumber{Issuer: asn1.RawValue{FullBytes: cert.RawIssuer}, SerialNumber: cert.SerialNumber})
+	if err != nil {
+		return nil, err
+	}
+
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{digestAlg},
+		EncapContentInfo: encapsulatedContentInfo{EContentType: oidData},
+		Certificates:     asn1.RawValue{FullBytes: rawCerts},
+		SignerInfos: []signerInfo{{
+			Version:            1,
+			SID:                asn1.RawValue{FullBytes: sid},
+			DigestAlgorithm:    digestAlg,
+			SignedAttrs:        asn1.RawValue{FullBytes: signedAttrs},
+			SignatureAlgorithm: sigAlg,
+			Signature:          sig,
+		}},
+	}
+
+	content, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, err
+	}
+
+	// encoding/asn1 writes FullBytes verbatim, so apply the explicit [0] tag here.
+	if content, err = tlv(asn1.ClassContextSpecific, 0, content); err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{FullBytes: content},
+	})
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"time"
+
+	_ "crypto/sha1"
+	_ "crypto/sha512"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	oidDigestAlgorithmSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidDigestAlgorithmSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidDigestAlgorithmSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+
+	oidSignatureRSAPSS = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 10}
+)
+
+// cmsSignature is the relevant content of a parsed CMS SignedData with a single signer.
+type cmsSignature struct {
+	certs         []*x509.Certificate
+	signer        *x509.Certificate
+	hash          crypto.Hash
+	eContent      []byte    // encapsulated content, nil for detached signatures
+	signedAttrs   []byte    // DER encoded SET OF signed attributes, nil if absent
+	messageDigest []byte    // value of the message-digest attribute
+	signingTime   time.Time // value of the signing-time attribute
+	sigAlg        asn1.ObjectIdentifier
+	signature     []byte
+}
+
+func hashForOID(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	switch {
+	case oid.Equal(oidDigestAlgorithmSHA1):
+		return crypto.SHA1, nil
+	case oid.Equal(oidDigestAlgorithmSHA256):
+		return crypto.SHA256, nil
+	case oid.Equal(oidDigestAlgorithmSHA384):
+		return crypto.SHA384, nil
+	case oid.Equal(oidDigestAlgorithmSHA512):
+		return crypto.SHA512, nil
+	}
+	return 0, errors.Errorf("pdfcpu: unsupported digest algorithm: %s", oid)
+}
+
+func (cs *cmsSignature) parseSignedAttributes(raw asn1.RawValue) error {
+	if len(raw.FullBytes) == 0 {
+		return nil
+	}
+
+	// The signature covers the DER encoding of the SET OF attributes rather than the implicitly tagged [0].
+	set, err := tlv(asn1.ClassUniversal, asn1.TagSet, raw.Bytes)
+	if err != nil {
+		return err
+	}
+	cs.signedAttrs = set
+
+	var attrs []attribute
+	if _, err := asn1.UnmarshalWithParams(set, &attrs, "set"); err != nil {
+		return errors.Wrap(err, "pdfcpu: corrupt signed attributes")
+	}
+
+	for _, a := range attrs {
+		switch {
+		case a.Type.Equal(oidAttributeMessageDigest):
+			var vals []asn1.RawValue
+			if _, err := asn1.UnmarshalWithParams(a.Values.FullBytes, &vals, "set"); err != nil || len(vals) != 1 {
+				return errors.New("pdfcpu: corrupt message digest attribute")
+			}
+			if _, err := asn1.Unmarshal(vals[0].FullBytes, &cs.messageDigest); err != nil {
+				return errors.Wrap(err, "pdfcpu: corrupt message digest attribute")
+			}
+		case a.Type.Equal(oidAttributeSigningTime):
+			var vals []time.Time
+			if _, err := asn1.UnmarshalWithParams(a.Values.FullBytes, &vals, "set"); err == nil && len(vals) == 1 {
+				cs.signingTime = vals[0]
+			}
+		}
+	}
+
+	if cs.messageDigest == nil {
+		return errors.New("pdfcpu: missing message digest attribute")
+	}
+
+	return nil
+}
+
+func findSigner(sid asn1.RawValue, certs []*x509.Certificate) *x509.Certificate {
+	if sid.Class == asn1.ClassContextSpecific && sid.Tag == 0 {
+		// subjectKeyIdentifier
+		for _, c := range certs {
+			if bytes.Equal(c.SubjectKeyId, sid.Bytes) {
+				return c
+			}
+		}
+		return nil
+	}
+
+	var ias issuerAndSerialNumber
+	if _, err := asn1.Unmarshal(sid.FullBytes, &ias); err != nil {
+		return nil
+	}
+
+	for _, c := range certs {
+		if bytes.Equal(c.RawIssuer, ias.Issuer.FullBytes) && c.SerialNumber.Cmp(ias.SerialNumber) == 0 {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// explicitContent strips the explicit [0] tag encoding/asn1 leaves in place for raw values.
+func explicitContent(raw asn1.RawValue) []byte {
+	if raw.Class == asn1.ClassContextSpecific && raw.Tag == 0 && raw.IsCompound {
+		return raw.Bytes
+	}
+	return raw.FullBytes
+}
+
+// parseSignedData parses a DER encoded CMS ContentInfo containing a SignedData.
+// Trailing bytes (eg. zero padding of a signature placeholder) are ignored.
+func parseSignedData(der []byte) (*cmsSignature, error) {
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(der, &ci); err != nil {
+		return nil, errors.Wrap(err, "pdfcpu: corrupt CMS content info")
+	}
+
+	if !ci.ContentType.Equal(oidSignedData) {
+		return nil, errors.Errorf("pdfcpu: unexpected CMS content type: %s", ci.ContentType)
+	}
+
+	var sd signedData
+	if _, err := asn1.Unmarshal(explicitContent(ci.Content), &sd); err != nil {
+		return nil, errors.Wrap(err, "pdfcpu: corrupt CMS signed data")
+	}
+
+	if len(sd.SignerInfos) != 1 {
+		return nil, errors.Errorf("pdfcpu: expected 1 CMS signer, got %d", len(sd.SignerInfos))
+	}
+	si := sd.SignerInfos[0]
+
+	cs := &cmsSignature{sigAlg: si.SignatureAlgorithm.Algorithm, signature: si.Signature}
+
+	if len(sd.Certificates.Bytes) > 0 {
+		certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "pdfcpu: corrupt CMS certificates")
+		}
+		cs.certs = certs
+	}
+
+	if cs.signer = findSigner(si.SID, cs.certs); cs.signer == nil {
+		return nil, errors.New("pdfcpu: missing CMS signer certificate")
+	}
+
+	h, err := hashForOID(si.DigestAlgorithm.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	cs.hash = h
+
+	if len(sd.EncapContentInfo.EContent.FullBytes) > 0 {
+		if _, err := asn1.Unmarshal(explicitContent(sd.EncapContentInfo.EContent), &cs.eContent); err != nil {
+			return nil, errors.Wrap(err, "pdfcpu: corrupt CMS encapsulated content")
+		}
+	}
+
+	if err := cs.parseSignedAttributes(si.SignedAttrs); err != nil {
+		return nil, err
+	}
+
+	return cs, nil
+}
+
+func (cs *cmsSignature) checkSignature(digest []byte) error {
+	if cs.sigAlg.Equal(oidSignatureRSAPSS) {
+		return errors.New("pdfcpu: RSASSA-PSS signatures are not supported")
+	}
+
+	switch pub := cs.signer.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(pub, cs.hash, digest, cs.signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, digest, cs.signature) {
+			return errors.New("pdfcpu: ECDSA verification failure")
+		}
+		return nil
+	}
+
+	return errUnsupportedSignatureKeyType
+}
+
+// verify checks the digest of the signed content against the message digest attribute and the signer's signature.
+// contentDigest is the cs.hash digest of the signed content.
+func (cs *cmsSignature) verify(contentDigest []byte) (digestOK bool, err error) {
+	if cs.signedAttrs == nil {
+		// Without signed attributes the signature directly covers the content digest.
+		if err := cs.checkSignature(contentDigest); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	digestOK = bytes.Equal(cs.messageDigest, contentDigest)
+
+	h := cs.hash.New()
+	h.Write(cs.signedAttrs)
+
+	return digestOK, cs.checkSignature(h.Sum(nil))
+}
+
+// verifyChain verifies the signer certificate against roots using the embedded certificates as intermediates.
+func (cs *cmsSignature) verifyChain(roots *x509.CertPool, at time.Time) error {
+	intermediates := x509.NewCertPool()
+	for _, c := range cs.certs {
+		if c != cs.signer {
+			intermediates.AddCert(c)
+		}
+	}
+
+	_, err := cs.signer.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   at,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+
+	return err
+}
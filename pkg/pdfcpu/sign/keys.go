@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+)
+
+// ParseCertificates parses all PEM encoded certificates in bb.
+// Raw DER input is accepted as a single certificate.
+func ParseCertificates(bb []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for {
+		var block *pem.Block
+		block, bb = pem.Decode(bb)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 && len(bb) > 0 {
+		cert, err := x509.ParseCertificate(bb)
+		if err != nil {
+			return nil, errors.New("pdfcpu: no certificates found")
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("pdfcpu: no certificates found")
+	}
+
+	return certs, nil
+}
+
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	if k, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		signer, ok := k.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("pdfcpu: unsupported private key type")
+		}
+		return signer, nil
+	}
+
+	if k, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return k, nil
+	}
+
+	if k, err := x509.ParseECPrivateKey(der); err == nil {
+		return k, nil
+	}
+
+	return nil, errors.New("pdfcpu: unable to parse private key")
+}
+
+// ParsePrivateKey parses the first PEM encoded private key in bb (PKCS#1, PKCS#8 or SEC 1).
+// Raw DER input is also accepted.
+func ParsePrivateKey(bb []byte) (crypto.Signer, error) {
+	for {
+		var block *pem.Block
+		block, bb = pem.Decode(bb)
+		if block == nil {
+			break
+		}
+		if block.Type == "ENCRYPTED PRIVATE KEY" {
+			return nil, errors.New("pdfcpu: encrypted private keys are not supported")
+		}
+		if block.Type == "PRIVATE KEY" || block.Type == "RSA PRIVATE KEY" || block.Type == "EC PRIVATE KEY" {
+			return parsePrivateKey(block.Bytes)
+		}
+	}
+
+	return parsePrivateKey(bb)
+}
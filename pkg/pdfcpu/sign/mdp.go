@@ -0,0 +1,491 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// See 12.8.2.2 DocMDP and 12.8.2.4 FieldMDP
+
+// Object categories used for judging modifications.
+const (
+	kindAnnotation = "annotation"
+	kindAnnots     = "annotation list"
+	kindAppearance = "appearance"
+	kindCatalog    = "catalog"
+	kindContent    = "content"
+	kindDSS        = "dss"
+	kindForm       = "form"
+	kindMetadata   = "metadata"
+	kindPage       = "page"
+	kindSignature  = "signature"
+)
+
+// classifier assigns a category to every object reachable from the catalog.
+type classifier struct {
+	xRefTable  *model.XRefTable
+	signed     *classifier // classification of the signed revision, nil for the signed revision itself
+	kinds      map[int]string
+	fieldNames map[int]string
+	content    map[int]bool // objects reachable from page contents or page resources
+}
+
+func newClassifier(xRefTable *model.XRefTable, signed *classifier) *classifier {
+	c := &classifier{
+		xRefTable:  xRefTable,
+		signed:     signed,
+		kinds:      map[int]string{},
+		fieldNames: map[int]string{},
+		content:    map[int]bool{},
+	}
+	c.classify()
+	return c
+}
+
+// relabels returns true if objNr belongs to the signed revision under a category other than kind.
+func (c *classifier) relabels(objNr int, kind string) bool {
+	if c.signed == nil || object(c.signed.xRefTable, objNr) == nil {
+		return false
+	}
+	return c.signed.kind(objNr) != kind
+}
+
+// mark assigns kind to o if o is an unclassified indirect reference and returns the dereferenced object.
+// Objects of the signed revision keep their category and are not followed from elsewhere.
+func (c *classifier) mark(o types.Object, kind string) types.Object {
+	if indRef, ok := o.(types.IndirectRef); ok {
+		objNr := indRef.ObjectNumber.Value()
+		if _, found := c.kinds[objNr]; !found {
+			if c.relabels(objNr, kind) {
+				return nil
+			}
+			c.kinds[objNr] = kind
+		}
+		o1, err := c.xRefTable.Dereference(indRef)
+		if err != nil {
+			return nil
+		}
+		return o1
+	}
+	return o
+}
+
+// markDeep assigns kind to all unclassified objects reachable from o without leaving the subtree via parent links.
+func (c *classifier) markDeep(o types.Object, kind string) {
+	if indRef, ok := o.(types.IndirectRef); ok {
+		if _, found := c.kinds[indRef.ObjectNumber.Value()]; found {
+			return
+		}
+	}
+
+	switch o := c.mark(o, kind).(type) {
+	case types.Dict:
+		for k, v := range o {
+			if k != "Parent" && k != "P" {
+				c.markDeep(v, kind)
+			}
+		}
+	case types.StreamDict:
+		for k, v := range o.Dict {
+			if k != "Parent" && k != "P" {
+				c.markDeep(v, kind)
+			}
+		}
+	case types.Array:
+		for _, v := range o {
+			c.markDeep(v, kind)
+		}
+	}
+}
+
+func (c *classifier) dict(o types.Object, kind string) types.Dict {
+	d, _ := c.mark(o, kind).(types.Dict)
+	return d
+}
+
+func (c *classifier) array(o types.Object, kind string) types.Array {
+	a, _ := c.mark(o, kind).(types.Array)
+	return a
+}
+
+func (c *classifier) fields(fields types.Array, parent string) {
+	for _, o := range fields {
+		if indRef, ok := o.(types.IndirectRef); ok {
+			if _, found := c.kinds[indRef.ObjectNumber.Value()]; found {
+				continue
+			}
+		}
+
+		d := c.dict(o, kindForm)
+		if d == nil {
+			continue
+		}
+
+		name := parent
+		if s, err := c.xRefTable.DereferenceText(d["T"]); err == nil && s != "" {
+			name = fullyQualifiedName(parent, s)
+		}
+		if indRef, ok := o.(types.IndirectRef); ok {
+			c.fieldNames[indRef.ObjectNumber.Value()] = name
+		}
+
+		if v, found := d.Find("V"); found {
+			if sd, err := c.xRefTable.DereferenceDict(v); err == nil && sd != nil && sd["ByteRange"] != nil {
+				c.markDeep(v, kindSignature)
+			} else {
+				c.markDeep(v, kindForm)
+			}
+		}
+
+		c.markDeep(d["AP"], kindAppearance)
+		c.markDeep(d["Lock"], kindForm)
+		c.markDeep(d["SV"], kindForm)
+
+		c.fields(c.array(d["Kids"], kindForm), name)
+	}
+}
+
+func (c *classifier) annotations(annots types.Array) {
+	for _, o := range annots {
+		kind := kindAnnotation
+		if d, err := c.xRefTable.DereferenceDict(o); err == nil && d != nil {
+			if st := d.Subtype(); st != nil && *st == "Widget" {
+				kind = kindForm
+			}
+		}
+		d := c.dict(o, kind)
+		if d == nil {
+			continue
+		}
+		// Appearances of annotations other than widgets are part of the annotation.
+		apKind := kindAnnotation
+		if kind == kindForm {
+			apKind = kindAppearance
+		}
+		c.markDeep(d["AP"], apKind)
+		c.markDeep(d["Popup"], kindAnnotation)
+	}
+}
+
+// markContent records all objects reachable from o as page content.
+func (c *classifier) markContent(o types.Object) {
+	if indRef, ok := o.(types.IndirectRef); ok {
+		objNr := indRef.ObjectNumber.Value()
+		if c.content[objNr] {
+			return
+		}
+		c.content[objNr] = true
+		o1, err := c.xRefTable.Dereference(indRef)
+		if err != nil {
+			return
+		}
+		o = o1
+	}
+
+	switch o := o.(type) {
+	case types.Dict:
+		for k, v := range o {
+			if k != "Parent" && k != "P" {
+				c.markContent(v)
+			}
+		}
+	case types.StreamDict:
+		for k, v := range o.Dict {
+			if k != "Parent" && k != "P" {
+				c.markContent(v)
+			}
+		}
+	case types.Array:
+		for _, v := range o {
+			c.markContent(v)
+		}
+	}
+}
+
+func (c *classifier) pages(o types.Object) {
+	if indRef, ok := o.(types.IndirectRef); ok {
+		if _, found := c.kinds[indRef.ObjectNumber.Value()]; found {
+			return
+		}
+	}
+
+	d := c.dict(o, kindPage)
+	if d == nil {
+		return
+	}
+
+	c.markContent(d["Resources"])
+
+	if t := d.Type(); t != nil && *t == "Pages" {
+		for _, kid := range c.array(d["Kids"], kindPage) {
+			c.pages(kid)
+		}
+		return
+	}
+
+	c.markContent(d["Contents"])
+	c.annotations(c.array(d["Annots"], kindAnnots))
+}
+
+func (c *classifier) classify() {
+	if c.xRefTable.Root != nil {
+		c.kinds[c.xRefTable.Root.ObjectNumber.Value()] = kindCatalog
+	}
+	if c.xRefTable.Info != nil {
+		c.markDeep(*c.xRefTable.Info, kindMetadata)
+	}
+
+	root, err := c.xRefTable.Catalog()
+	if err != nil {
+		return
+	}
+
+	c.markDeep(root["Metadata"], kindMetadata)
+	c.markDeep(root["DSS"], kindDSS)
+
+	if form := c.dict(root["AcroForm"], kindForm); form != nil {
+		c.fields(c.array(form["Fields"], kindForm), "")
+		c.markDeep(form["DR"], kindAppearance)
+	}
+
+	c.pages(root["Pages"])
+}
+
+func fieldOrWidget(d types.Dict) bool {
+	if d == nil {
+		return false
+	}
+	if st := d.Subtype(); st != nil && *st == "Widget" {
+		return true
+	}
+	for _, k := range []string{"FT", "T", "Kids"} {
+		if _, found := d.Find(k); found {
+			return true
+		}
+	}
+	return false
+}
+
+// signatureField returns true if d is a signature field or a widget of a signature field.
+func (c *classifier) signatureField(d types.Dict) bool {
+	for i := 0; d != nil && i < 32; i++ {
+		if ft := d.NameEntry("FT"); ft != nil {
+			return *ft == "Sig"
+		}
+		d1, err := c.xRefTable.DereferenceDict(d["Parent"])
+		if err != nil {
+			return false
+		}
+		d = d1
+	}
+	return false
+}
+
+func (c *classifier) kind(objNr int) string {
+	if k, ok := c.kinds[objNr]; ok {
+		return k
+	}
+	return kindContent
+}
+
+func serialize(o types.Object) string {
+	if sd, ok := o.(types.StreamDict); ok {
+		return sd.Dict.PDFString() + string(sd.Raw)
+	}
+	return o.PDFString()
+}
+
+// structural returns true for objects not carrying any document content.
+func structural(o types.Object) bool {
+	switch o := o.(type) {
+	case types.ObjectStreamDict, types.XRefStreamDict:
+		return true
+	case types.Dict:
+		return o.IsLinearizationParmDict()
+	}
+	return false
+}
+
+func object(xRefTable *model.XRefTable, objNr int) types.Object {
+	entry, found := xRefTable.Find(objNr)
+	if !found || entry.Free || entry.Object == nil {
+		return nil
+	}
+	return entry.Object
+}
+
+// changedKeys returns the keys of d2 whose values differ from d1.
+func changedKeys(d1, d2 types.Dict) map[string]bool {
+	m := map[string]bool{}
+	for k, v := range d2 {
+		if v1, found := d1[k]; !found || v1.PDFString() != v.PDFString() {
+			m[k] = true
+		}
+	}
+	for k := range d1 {
+		if _, found := d2[k]; !found {
+			m[k] = true
+		}
+	}
+	return m
+}
+
+func onlyKeys(m map[string]bool, keys ...string) bool {
+	for k := range m {
+		ok := false
+		for _, k1 := range keys {
+			if k == k1 {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// allowed judges a modification according to DocMDP permissions p and FieldMDP locks.
+// Approval signatures (p == 0) are judged like p == 3.
+func allowed(m Modification, c *classifier, o1, o2 types.Object, p int, locks []fieldLock) bool {
+	if p == 0 {
+		p = 3
+	}
+
+	switch m.Kind {
+
+	case kindDSS:
+		return true
+
+	case kindCatalog:
+		d1, _ := o1.(types.Dict)
+		d2, _ := o2.(types.Dict)
+		keys := changedKeys(d1, d2)
+		if onlyKeys(keys, "DSS", "Extensions") {
+			return true
+		}
+		return p >= 2 && onlyKeys(keys, "AcroForm", "DSS", "Extensions")
+
+	case kindSignature:
+		// Existing signatures must not be touched.
+		return m.Change == "added" && p >= 2
+
+	case kindForm:
+		if p < 2 {
+			return false
+		}
+		if m.Change == "added" {
+			// Only signature fields may be added, existing fields may be filled in.
+			d2, _ := o2.(types.Dict)
+			return !fieldOrWidget(d2) || c.signatureField(d2)
+		}
+		name := c.fieldNames[m.ObjNr]
+		for _, fl := range locks {
+			if name != "" && fl.locks(name) {
+				return false
+			}
+		}
+		return true
+
+	case kindAppearance, kindAnnots:
+		return p >= 2
+
+	case kindMetadata:
+		return p >= 3
+
+	case kindPage:
+		d1, _ := o1.(types.Dict)
+		d2, _ := o2.(types.Dict)
+		return p >= 2 && d1 != nil && d2 != nil && onlyKeys(changedKeys(d1, d2), "Annots")
+
+	case kindAnnotation:
+		return p >= 3
+	}
+
+	return false
+}
+
+// modifications returns all objects added, changed or removed by the revisions following ctxOld up to ctxNew.
+func modifications(ctxOld, ctxNew *model.Context, p int, locks []fieldLock) []Modification {
+	cOld := newClassifier(ctxOld.XRefTable, nil)
+	cNew := newClassifier(ctxNew.XRefTable, cOld)
+
+	objNrs := map[int]bool{}
+	for objNr := range ctxNew.Table {
+		objNrs[objNr] = true
+	}
+	for objNr := range ctxOld.Table {
+		objNrs[objNr] = true
+	}
+
+	var mm []Modification
+
+	for objNr := range objNrs {
+		if objNr == 0 {
+			continue
+		}
+
+		o1, o2 := object(ctxOld.XRefTable, objNr), object(ctxNew.XRefTable, objNr)
+		if (o1 != nil && structural(o1)) || (o2 != nil && structural(o2)) {
+			continue
+		}
+
+		var m Modification
+		c := cNew
+
+		switch {
+		case o1 == nil && o2 == nil:
+			continue
+		case o1 == nil:
+			// New objects showing up on a page are content no matter where else they are referenced.
+			kind := cNew.kind(objNr)
+			if cNew.content[objNr] {
+				kind = kindContent
+			}
+			m = Modification{ObjNr: objNr, Kind: kind, Change: "added"}
+		case o2 == nil:
+			m = Modification{ObjNr: objNr, Kind: cOld.kind(objNr), Change: "removed"}
+			c = cOld
+		case serialize(o1) == serialize(o2):
+			continue
+		default:
+			// Changed objects are judged by their category in the signed revision.
+			kind := cOld.kind(objNr)
+			if cOld.content[objNr] || cNew.content[objNr] {
+				kind = kindContent
+			}
+			m = Modification{ObjNr: objNr, Kind: kind, Change: "changed"}
+			c = cOld
+			if cNew.kind(objNr) != cOld.kind(objNr) {
+				// An object must not change its category.
+				m.Kind = cOld.kind(objNr) + " -> " + cNew.kind(objNr)
+			}
+		}
+
+		m.Allowed = allowed(m, c, o1, o2, p, locks)
+		mm = append(mm, m)
+	}
+
+	sort.Slice(mm, func(i, j int) bool { return mm[i].ObjNr < mm[j].ObjNr })
+
+	return mm
+}
@@ -0,0 +1,457 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sign provides digital signing of PDF files via CMS (PKCS#7) signatures embedded in an incremental update.
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// The ByteRange placeholder reserves enough room for 4 integers up to 10 digits.
+const byteRangePlaceholder = types.Integer(math.MaxInt32)
+
+// ParseSignatureConfig parses a signature command string into an internal structure.
+func ParseSignatureConfig(s string) (*model.SignatureConfig, error) {
+	sc := model.DefaultSignatureConfig()
+
+	if s == "" {
+		return sc, nil
+	}
+
+	ss := strings.Split(s, ",")
+
+	for _, s := range ss {
+
+		ss1 := strings.SplitN(s, ":", 2)
+		if len(ss1) != 2 {
+			return nil, errors.New("pdfcpu: Invalid signature configuration string. Please consult pdfcpu help sign")
+		}
+
+		paramPrefix := strings.TrimSpace(ss1[0])
+		paramValueStr := strings.TrimSpace(ss1[1])
+
+		if err := model.SignatureParamMap.Handle(paramPrefix, paramValueStr, sc); err != nil {
+			return nil, err
+		}
+	}
+
+	return sc, nil
+}
+
+func stringLiteral(s string) (types.StringLiteral, error) {
+	s1, err := types.EscapedUTF16String(s)
+	if err != nil {
+		return "", err
+	}
+	return types.StringLiteral(*s1), nil
+}
+
+func signatureDict(sc *model.SignatureConfig, signingTime time.Time) (types.Dict, error) {
+	d := types.Dict(map[string]types.Object{
+		"Type":      types.Name("Sig"),
+		"Filter":    types.Name("Adobe.PPKLite"),
+		"SubFilter": types.Name(sc.Format),
+		"ByteRange": types.Array{types.Integer(0), byteRangePlaceholder, byteRangePlaceholder, byteRangePlaceholder},
+		"Contents":  types.HexLiteral(strings.Repeat("0", 2*sc.Size)),
+		"M":         types.StringLiteral(types.DateString(signingTime)),
+	})
+
+	for k, v := range map[string]string{
+		"Name":        sc.Name,
+		"Reason":      sc.Reason,
+		"Location":    sc.Location,
+		"ContactInfo": sc.ContactInfo,
+	} {
+		if v == "" {
+			continue
+		}
+		sl, err := stringLiteral(v)
+		if err != nil {
+			return nil, err
+		}
+		d[k] = sl
+	}
+
+	if sc.DocMDP > 0 {
+		d["Reference"] = types.Array{
+			types.Dict(map[string]types.Object{
+				"Type":            types.Name("SigRef"),
+				"TransformMethod": types.Name("DocMDP"),
+				"TransformParams": types.Dict(map[string]types.Object{
+					"Type": types.Name("TransformParams"),
+					"P":    types.Integer(sc.DocMDP),
+					"V":    types.Name("1.2"),
+				}),
+			}),
+		}
+	}
+
+	return d, nil
+}
+
+// certify turns the signature sigIndRef into the certification signature of ctx.
+func certify(ctx *model.Context, form types.Dict, sigIndRef types.IndirectRef) error {
+	if f := form.IntEntry("SigFlags"); f != nil && *f&1 > 0 {
+		return errors.New("pdfcpu: a certification signature must be the first signature of a document")
+	}
+
+	if _, found := ctx.RootDict.Find("Perms"); found {
+		return errors.New("pdfcpu: document already has permissions (Perms)")
+	}
+
+	ctx.RootDict["Perms"] = types.Dict(map[string]types.Object{"DocMDP": sigIndRef})
+	ctx.Write.IncrementWithObjNr(ctx.Root.ObjectNumber.Value())
+
+	return nil
+}
+
+// acroForm returns the AcroForm dict of ctx and the number of the object to be written if the AcroForm dict gets modified.
+func acroForm(ctx *model.Context) (types.Dict, int, error) {
+	rootObjNr := ctx.Root.ObjectNumber.Value()
+
+	o, found := ctx.RootDict.Find("AcroForm")
+	if !found {
+		d := types.NewDict()
+		indRef, err := ctx.IndRefForNewObject(d)
+		if err != nil {
+			return nil, 0, err
+		}
+		ctx.RootDict["AcroForm"] = *indRef
+		ctx.Write.IncrementWithObjNr(rootObjNr)
+		return d, indRef.ObjectNumber.Value(), nil
+	}
+
+	if indRef, ok := o.(types.IndirectRef); ok {
+		d, err := ctx.DereferenceDict(indRef)
+		if err != nil {
+			return nil, 0, err
+		}
+		if d == nil {
+			d = types.NewDict()
+			entry, _ := ctx.FindTableEntryForIndRef(&indRef)
+			if entry == nil {
+				return nil, 0, errors.New("pdfcpu: corrupt AcroForm")
+			}
+			entry.Object = d
+		}
+		return d, indRef.ObjectNumber.Value(), nil
+	}
+
+	d, ok := o.(types.Dict)
+	if !ok {
+		return nil, 0, errors.New("pdfcpu: corrupt AcroForm")
+	}
+
+	return d, rootObjNr, nil
+}
+
+func fieldName(xRefTable *model.XRefTable, d types.Dict) string {
+	o, found := d.Find("T")
+	if !found {
+		return ""
+	}
+	s, err := xRefTable.DereferenceStringOrHexLiteral(o, model.V10, nil)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// emptySignatureField returns an existing unsigned signature field called name.
+func emptySignatureField(xRefTable *model.XRefTable, fields types.Array, name string) (*types.IndirectRef, types.Dict, error) {
+	for _, o := range fields {
+		indRef, ok := o.(types.IndirectRef)
+		if !ok {
+			continue
+		}
+		d, err := xRefTable.DereferenceDict(indRef)
+		if err != nil || d == nil {
+			continue
+		}
+		if fieldName(xRefTable, d) != name {
+			continue
+		}
+		if ft := d.NameEntry("FT"); ft == nil || *ft != "Sig" {
+			return nil, nil, errors.Errorf("pdfcpu: field \"%s\" already exists and is not a signature field", name)
+		}
+		if _, found := d.Find("V"); found {
+			return nil, nil, errors.Errorf("pdfcpu: signature field \"%s\" is already signed", name)
+		}
+		return &indRef, d, nil
+	}
+
+	return nil, nil, nil
+}
+
+func addAnnotToPage(ctx *model.Context, pageNr int, annotIndRef types.IndirectRef) (*types.IndirectRef, error) {
+	pageDictIndRef, err := ctx.PageDictIndRef(pageNr)
+	if err != nil {
+		return nil, err
+	}
+
+	pageDict, err := ctx.DereferenceDict(*pageDictIndRef)
+	if err != nil {
+		return nil, err
+	}
+
+	o, found := pageDict.Find("Annots")
+	if !found {
+		pageDict["Annots"] = types.Array{annotIndRef}
+		ctx.Write.IncrementWithObjNr(pageDictIndRef.ObjectNumber.Value())
+		return pageDictIndRef, nil
+	}
+
+	if indRef, ok := o.(types.IndirectRef); ok {
+		annots, err := ctx.DereferenceArray(indRef)
+		if err != nil {
+			return nil, err
+		}
+		entry, ok := ctx.FindTableEntryForIndRef(&indRef)
+		if !ok {
+			return nil, errors.Errorf("pdfcpu: page %d: corrupt Annots", pageNr)
+		}
+		entry.Object = append(annots, annotIndRef)
+		ctx.Write.IncrementWithObjNr(indRef.ObjectNumber.Value())
+		return pageDictIndRef, nil
+	}
+
+	annots, ok := o.(types.Array)
+	if !ok {
+		return nil, errors.Errorf("pdfcpu: page %d: corrupt Annots", pageNr)
+	}
+	pageDict["Annots"] = append(annots, annotIndRef)
+	ctx.Write.IncrementWithObjNr(pageDictIndRef.ObjectNumber.Value())
+
+	return pageDictIndRef, nil
+}
+
+func addField(ctx *model.Context, form types.Dict, fieldIndRef types.IndirectRef) error {
+	o, found := form.Find("Fields")
+	if !found {
+		form["Fields"] = types.Array{fieldIndRef}
+		return nil
+	}
+
+	if indRef, ok := o.(types.IndirectRef); ok {
+		fields, err := ctx.DereferenceArray(indRef)
+		if err != nil {
+			return err
+		}
+		entry, ok := ctx.FindTableEntryForIndRef(&indRef)
+		if !ok {
+			return errors.New("pdfcpu: corrupt AcroForm Fields")
+		}
+		entry.Object = append(fields, fieldIndRef)
+		ctx.Write.IncrementWithObjNr(indRef.ObjectNumber.Value())
+		return nil
+	}
+
+	fields, ok := o.(types.Array)
+	if !ok {
+		return errors.New("pdfcpu: corrupt AcroForm Fields")
+	}
+	form["Fields"] = append(fields, fieldIndRef)
+
+	return nil
+}
+
+// PrepareSignature adds a signature dict with placeholders for ByteRange and Contents to ctx.
+// For sc.DocMDP > 0 a certification signature restricting subsequent changes gets prepared.
+// The signature dict becomes the value of either an existing unsigned signature field named sc.FieldName
+// or a new invisible signature field on page 1.
+// All affected objects are marked for incremental writing.
+// Returns the object number of the signature dict.
+func PrepareSignature(ctx *model.Context, sc *model.SignatureConfig, signingTime time.Time) (int, error) {
+	if ctx.Encrypt != nil {
+		return 0, errors.New("pdfcpu: signing encrypted files is not supported")
+	}
+
+	if sc.Size < 1024 {
+		return 0, errors.Errorf("pdfcpu: signature size too small: %d", sc.Size)
+	}
+
+	sigDict, err := signatureDict(sc, signingTime)
+	if err != nil {
+		return 0, err
+	}
+
+	sigIndRef, err := ctx.IndRefForNewObject(sigDict)
+	if err != nil {
+		return 0, err
+	}
+	sigObjNr := sigIndRef.ObjectNumber.Value()
+	ctx.Write.IncrementWithObjNr(sigObjNr)
+
+	form, formObjNr, err := acroForm(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if sc.DocMDP > 0 {
+		if err := certify(ctx, form, *sigIndRef); err != nil {
+			return 0, err
+		}
+	}
+
+	// SignaturesExist | AppendOnly
+	form["SigFlags"] = types.Integer(3)
+	ctx.Write.IncrementWithObjNr(formObjNr)
+
+	var fields types.Array
+	if o, found := form.Find("Fields"); found {
+		if fields, err = ctx.DereferenceArray(o); err != nil {
+			return 0, err
+		}
+	}
+
+	fieldIndRef, fieldDict, err := emptySignatureField(ctx.XRefTable, fields, sc.FieldName)
+	if err != nil {
+		return 0, err
+	}
+
+	if fieldIndRef != nil {
+		fieldDict["V"] = *sigIndRef
+		ctx.Write.IncrementWithObjNr(fieldIndRef.ObjectNumber.Value())
+		return sigObjNr, nil
+	}
+
+	fn, err := stringLiteral(sc.FieldName)
+	if err != nil {
+		return 0, err
+	}
+
+	// An invisible signature field merged with its widget annotation.
+	fieldDict = types.Dict(map[string]types.Object{
+		"Type":    types.Name("Annot"),
+		"Subtype": types.Name("Widget"),
+		"FT":      types.Name("Sig"),
+		"T":       fn,
+		"V":       *sigIndRef,
+		"F":       types.Integer(model.AnnPrint + model.AnnLocked),
+		"Rect":    types.NewRectangle(0, 0, 0, 0).Array(),
+	})
+
+	if fieldIndRef, err = ctx.IndRefForNewObject(fieldDict); err != nil {
+		return 0, err
+	}
+	ctx.Write.IncrementWithObjNr(fieldIndRef.ObjectNumber.Value())
+
+	pageDictIndRef, err := addAnnotToPage(ctx, 1, *fieldIndRef)
+	if err != nil {
+		return 0, err
+	}
+	fieldDict["P"] = *pageDictIndRef
+
+	if err := addField(ctx, form, *fieldIndRef); err != nil {
+		return 0, err
+	}
+
+	return sigObjNr, nil
+}
+
+func placeholder(bb []byte, off int, key string, open, close byte) (int, int, error) {
+	i := bytes.Index(bb[off:], []byte("/"+key))
+	if i < 0 {
+		return 0, 0, errors.Errorf("pdfcpu: signature: missing %s", key)
+	}
+	i += off + len(key) + 1
+
+	j := bytes.IndexByte(bb[i:], open)
+	if j < 0 {
+		return 0, 0, errors.Errorf("pdfcpu: signature: corrupt %s", key)
+	}
+	j += i
+
+	k := bytes.IndexByte(bb[j:], close)
+	if k < 0 {
+		return 0, 0, errors.Errorf("pdfcpu: signature: corrupt %s", key)
+	}
+
+	return j, j + k + 1, nil
+}
+
+// ApplySignature fills in the ByteRange and Contents placeholders of the signature dict written at offset sigOff in incr.
+// incr is the incremental update to be appended to the original file rs of size size.
+func ApplySignature(
+	rs io.ReadSeeker,
+	size int64,
+	incr []byte,
+	sigOff int,
+	signer crypto.Signer,
+	certs []*x509.Certificate,
+	sc *model.SignatureConfig,
+	signingTime time.Time) error {
+
+	// Locate the placeholders.
+	i1, i2, err := placeholder(incr, sigOff, "ByteRange", '[', ']')
+	if err != nil {
+		return err
+	}
+
+	j1, j2, err := placeholder(incr, sigOff, "Contents", '<', '>')
+	if err != nil {
+		return err
+	}
+
+	// The signed byte range covers the whole file except for the Contents value including its delimiters.
+	from := size + int64(j1)
+	to := size + int64(j2)
+	total := size + int64(len(incr))
+
+	br := fmt.Sprintf("[0 %d %d %d", from, to, total-to)
+	if len(br) > i2-i1-1 {
+		return errors.New("pdfcpu: signature: ByteRange overflow")
+	}
+	copy(incr[i1:i2-1], br+strings.Repeat(" ", i2-i1-1-len(br)))
+
+	h := sha256.New()
+
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(h, rs, size); err != nil {
+		return err
+	}
+	h.Write(incr[:j1])
+	h.Write(incr[j2:])
+
+	sd, err := NewSignedData(h.Sum(nil), signer, certs, signingTime, sc.Format == model.SignatureFormatCAdES)
+	if err != nil {
+		return err
+	}
+
+	s := hex.EncodeToString(sd)
+	if len(s) > j2-j1-2 {
+		return errors.Errorf("pdfcpu: signature too large (%d bytes), please increase signature size", len(sd))
+	}
+	copy(incr[j1+1:], s)
+
+	return nil
+}
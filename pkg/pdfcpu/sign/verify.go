@@ -0,0 +1,637 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// SignatureFormatPKCS7SHA1 is the deprecated SubFilter for signatures encapsulating a SHA-1 digest of the signed bytes.
+const SignatureFormatPKCS7SHA1 = "adbe.pkcs7.sha1"
+
+// Modification represents an object added, changed or removed after a signature has been applied.
+type Modification struct {
+	ObjNr   int
+	Kind    string // signature, form, appearance, annotation, page, catalog, metadata, dss, content ..
+	Change  string // added, changed, removed
+	Allowed bool
+}
+
+func (m Modification) String() string {
+	return fmt.Sprintf("obj#%d %s %s (%s)", m.ObjNr, m.Kind, m.Change, okString(m.Allowed, "permitted", "not permitted"))
+}
+
+// SignatureResult represents the outcome of the verification of a single signature.
+type SignatureResult struct {
+	FieldName     string
+	SubFilter     string
+	Signer        string // common name of the signer certificate
+	Name          string
+	Reason        string
+	Location      string
+	ContactInfo   string
+	SigningTime   time.Time
+	ByteRange     [4]int64
+	ByteRangeOK   bool // the byte range is well formed and lies within the file
+	DocMDP        int  // DocMDP permissions of a certification signature, 0 for approval signatures
+	DigestOK      bool // the digest of the signed byte range matches
+	SignatureOK   bool // the CMS signature is cryptographically valid
+	Trusted       bool // the signer certificate chains up to the trust store
+	AppendedBytes int64
+	Modifications []Modification
+	Problems      []string
+}
+
+// Certification returns true for a certification (DocMDP) signature.
+func (sr SignatureResult) Certification() bool {
+	return sr.DocMDP > 0
+}
+
+// ModificationsAllowed returns true if all revisions following this signature only touch permitted objects.
+func (sr SignatureResult) ModificationsAllowed() bool {
+	for _, m := range sr.Modifications {
+		if !m.Allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// Valid returns true if the signature is intact, trusted and followed by permitted modifications only.
+func (sr SignatureResult) Valid() bool {
+	return sr.DigestOK && sr.SignatureOK && sr.Trusted && sr.ModificationsAllowed()
+}
+
+func docMDPString(p int) string {
+	switch p {
+	case 1:
+		return "no changes permitted"
+	case 2:
+		return "form filling and signing permitted"
+	case 3:
+		return "form filling, signing and annotating permitted"
+	}
+	return "none"
+}
+
+func okString(ok bool, yes, no string) string {
+	if ok {
+		return yes
+	}
+	return no
+}
+
+// Strings returns a textual report for sr.
+func (sr SignatureResult) Strings() []string {
+	ss := []string{fmt.Sprintf("Signature field: %s", sr.FieldName)}
+
+	add := func(k, v string) {
+		if v != "" {
+			ss = append(ss, fmt.Sprintf("  %-14s %s", k+":", v))
+		}
+	}
+
+	add("SubFilter", sr.SubFilter)
+	add("Signer", sr.Signer)
+	add("Name", sr.Name)
+	if !sr.SigningTime.IsZero() {
+		add("Signing time", sr.SigningTime.Format(time.RFC3339))
+	}
+	add("Reason", sr.Reason)
+	add("Location", sr.Location)
+	add("Contact", sr.ContactInfo)
+	add("Type", okString(sr.Certification(), "certification ("+docMDPString(sr.DocMDP)+")", "approval"))
+	switch {
+	case sr.ByteRangeOK:
+		add("ByteRange", fmt.Sprintf("%v", sr.ByteRange))
+	case sr.ByteRange != [4]int64{}:
+		add("ByteRange", fmt.Sprintf("%v (invalid)", sr.ByteRange))
+	default:
+		add("ByteRange", "invalid")
+	}
+	add("Integrity", okString(sr.DigestOK, "ok", "document altered or corrupted"))
+	add("Signature", okString(sr.SignatureOK, "valid", "invalid"))
+	add("Certificate", okString(sr.Trusted, "trusted", "not trusted"))
+
+	if sr.AppendedBytes == 0 {
+		add("Coverage", "entire document")
+	} else {
+		add("Coverage", fmt.Sprintf("%d bytes appended by later revisions", sr.AppendedBytes))
+	}
+
+	if len(sr.Modifications) > 0 {
+		add("Modifications", okString(sr.ModificationsAllowed(), "permitted", "not permitted"))
+		for _, m := range sr.Modifications {
+			ss = append(ss, "    "+m.String())
+		}
+	}
+
+	for _, s := range sr.Problems {
+		add("Problem", s)
+	}
+
+	add("Status", okString(sr.Valid(), "valid", "invalid"))
+
+	return ss
+}
+
+// LoadTrustStore returns a certificate pool containing all PEM encoded certificates found in dir.
+func LoadTrustStore(dir string) (*x509.CertPool, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	n := 0
+
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(f.Name())) {
+		case ".pem", ".crt", ".cer":
+		default:
+			continue
+		}
+		bb, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		certs, err := ParseCertificates(bb)
+		if err != nil {
+			continue
+		}
+		for _, c := range certs {
+			pool.AddCert(c)
+			n++
+		}
+	}
+
+	if n == 0 {
+		return nil, errors.Errorf("pdfcpu: no certificates found in trust store %s", dir)
+	}
+
+	return pool, nil
+}
+
+// signature represents a signature field value found in the AcroForm of a document.
+type signature struct {
+	fieldName string
+	indRef    *types.IndirectRef // signature dict
+	d         types.Dict
+	byteRange [4]int64
+	docMDP    int
+	locks     []fieldLock
+	result    *SignatureResult
+}
+
+// signedLen returns the length of the revision covered by s.
+func (s signature) signedLen() int64 {
+	return s.byteRange[2] + s.byteRange[3]
+}
+
+// fieldLock represents FieldMDP permissions either from a signature reference or a signature field's Lock dict.
+type fieldLock struct {
+	action string // All, Include, Exclude
+	fields []string
+}
+
+func (fl fieldLock) locks(fieldName string) bool {
+	in := false
+	for _, s := range fl.fields {
+		if s == fieldName || strings.HasPrefix(fieldName, s+".") {
+			in = true
+			break
+		}
+	}
+	switch fl.action {
+	case "All":
+		return true
+	case "Include":
+		return in
+	case "Exclude":
+		return !in
+	}
+	return false
+}
+
+func parseFieldLock(xRefTable *model.XRefTable, d types.Dict) *fieldLock {
+	action := d.NameEntry("Action")
+	if action == nil {
+		return nil
+	}
+	fl := &fieldLock{action: *action}
+	if arr, err := xRefTable.DereferenceArray(d["Fields"]); err == nil {
+		for _, o := range arr {
+			if s, err := xRefTable.DereferenceText(o); err == nil {
+				fl.fields = append(fl.fields, s)
+			}
+		}
+	}
+	return fl
+}
+
+// parseReferences processes the signature reference dicts of s for DocMDP and FieldMDP.
+func (s *signature) parseReferences(xRefTable *model.XRefTable) {
+	refs, err := xRefTable.DereferenceArray(s.d["Reference"])
+	if err != nil {
+		return
+	}
+
+	for _, o := range refs {
+		d, err := xRefTable.DereferenceDict(o)
+		if err != nil || d == nil {
+			continue
+		}
+		tm := d.NameEntry("TransformMethod")
+		if tm == nil {
+			continue
+		}
+		params, err := xRefTable.DereferenceDict(d["TransformParams"])
+		if err != nil {
+			continue
+		}
+		switch *tm {
+		case "DocMDP":
+			s.docMDP = 2
+			if params != nil {
+				if p := params.IntEntry("P"); p != nil && *p >= 1 && *p <= 3 {
+					s.docMDP = *p
+				}
+			}
+		case "FieldMDP":
+			if params != nil {
+				if fl := parseFieldLock(xRefTable, params); fl != nil {
+					s.locks = append(s.locks, *fl)
+				}
+			}
+		}
+	}
+}
+
+func fullyQualifiedName(parent, partial string) string {
+	if parent == "" {
+		return partial
+	}
+	if partial == "" {
+		return parent
+	}
+	return parent + "." + partial
+}
+
+// collectSignatures walks the field tree of fields and appends all signed signature fields to sigs.
+func collectSignatures(xRefTable *model.XRefTable, fields types.Array, parent, ft string, sigs *[]*signature, visited map[int]bool) error {
+	for _, o := range fields {
+		if indRef, ok := o.(types.IndirectRef); ok {
+			if visited[indRef.ObjectNumber.Value()] {
+				continue
+			}
+			visited[indRef.ObjectNumber.Value()] = true
+		}
+
+		d, err := xRefTable.DereferenceDict(o)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+
+		name := parent
+		if s, err := xRefTable.DereferenceText(d["T"]); err == nil && s != "" {
+			name = fullyQualifiedName(parent, s)
+		}
+
+		fieldType := ft
+		if n := d.NameEntry("FT"); n != nil {
+			fieldType = *n
+		}
+
+		if kids, err := xRefTable.DereferenceArray(d["Kids"]); err == nil && len(kids) > 0 {
+			if err := collectSignatures(xRefTable, kids, name, fieldType, sigs, visited); err != nil {
+				return err
+			}
+		}
+
+		if fieldType != "Sig" {
+			continue
+		}
+
+		o, found := d.Find("V")
+		if !found {
+			continue
+		}
+
+		sd, err := xRefTable.DereferenceDict(o)
+		if err != nil || sd == nil {
+			continue
+		}
+
+		s := &signature{fieldName: name, d: sd}
+		if indRef, ok := o.(types.IndirectRef); ok {
+			s.indRef = &indRef
+		}
+
+		if lock, err := xRefTable.DereferenceDict(d["Lock"]); err == nil && lock != nil {
+			if fl := parseFieldLock(xRefTable, lock); fl != nil {
+				s.locks = append(s.locks, *fl)
+			}
+		}
+
+		*sigs = append(*sigs, s)
+	}
+
+	return nil
+}
+
+func signatures(ctx *model.Context) ([]*signature, error) {
+	root, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	o, found := root.Find("AcroForm")
+	if !found {
+		return nil, nil
+	}
+
+	form, err := ctx.DereferenceDict(o)
+	if err != nil || form == nil {
+		return nil, err
+	}
+
+	fields, err := ctx.DereferenceArray(form["Fields"])
+	if err != nil {
+		return nil, err
+	}
+
+	var sigs []*signature
+	if err := collectSignatures(ctx.XRefTable, fields, "", "", &sigs, map[int]bool{}); err != nil {
+		return nil, err
+	}
+
+	// Perms DocMDP identifies the certification signature.
+	var certIndRef *types.IndirectRef
+	if perms, err := ctx.DereferenceDict(root["Perms"]); err == nil && perms != nil {
+		certIndRef = perms.IndirectRefEntry("DocMDP")
+	}
+
+	for _, s := range sigs {
+		s.parseReferences(ctx.XRefTable)
+		if s.docMDP == 0 && certIndRef != nil && s.indRef != nil && *certIndRef == *s.indRef {
+			s.docMDP = 2
+		}
+	}
+
+	return sigs, nil
+}
+
+func (s *signature) parseByteRange(xRefTable *model.XRefTable, fileSize int64) error {
+	arr, err := xRefTable.DereferenceArray(s.d["ByteRange"])
+	if err != nil || len(arr) != 4 {
+		return errors.New("pdfcpu: missing or corrupt ByteRange")
+	}
+
+	var br [4]int64
+	for i, o := range arr {
+		i1, err := xRefTable.DereferenceInteger(o)
+		if err != nil || i1 == nil || *i1 < 0 {
+			return errors.New("pdfcpu: corrupt ByteRange")
+		}
+		br[i] = int64(*i1)
+	}
+	s.byteRange = br
+
+	if br[0] != 0 || br[1] >= br[2] || br[2]+br[3] > fileSize {
+		return errors.Errorf("pdfcpu: invalid ByteRange %v", br)
+	}
+
+	return nil
+}
+
+// contents returns the hex decoded signature value found in between the two byte ranges.
+func (s *signature) contents(bb []byte) ([]byte, error) {
+	br := s.byteRange
+	gap := bytes.TrimSpace(bb[br[1]:br[2]])
+
+	if len(gap) < 2 || gap[0] != '<' || gap[len(gap)-1] != '>' {
+		return nil, errors.New("pdfcpu: ByteRange does not exclude exactly the signature value")
+	}
+
+	h := bytes.Map(func(r rune) rune {
+		if r == ' ' || r == '\n' || r == '\r' || r == '\t' {
+			return -1
+		}
+		return r
+	}, gap[1:len(gap)-1])
+
+	if len(h)%2 == 1 {
+		h = append(h, '0')
+	}
+
+	cms := make([]byte, hex.DecodedLen(len(h)))
+	if _, err := hex.Decode(cms, h); err != nil {
+		return nil, errors.Wrap(err, "pdfcpu: corrupt signature value")
+	}
+
+	return cms, nil
+}
+
+func (s *signature) textEntry(xRefTable *model.XRefTable, key string) string {
+	t, err := xRefTable.DereferenceText(s.d[key])
+	if err != nil {
+		return ""
+	}
+	return t
+}
+
+// verify verifies the cryptographic integrity of s within the file bb.
+func (s *signature) verify(xRefTable *model.XRefTable, bb []byte, roots *x509.CertPool) {
+	sr := &SignatureResult{
+		FieldName:   s.fieldName,
+		Name:        s.textEntry(xRefTable, "Name"),
+		Reason:      s.textEntry(xRefTable, "Reason"),
+		Location:    s.textEntry(xRefTable, "Location"),
+		ContactInfo: s.textEntry(xRefTable, "ContactInfo"),
+		DocMDP:      s.docMDP,
+	}
+	s.result = sr
+
+	if m, err := xRefTable.DereferenceText(s.d["M"]); err == nil {
+		if t, ok := types.DateTime(m, true); ok {
+			sr.SigningTime = t
+		}
+	}
+
+	if sf := s.d.NameEntry("SubFilter"); sf != nil {
+		sr.SubFilter = *sf
+	}
+
+	problem := func(err error) {
+		sr.Problems = append(sr.Problems, strings.TrimPrefix(err.Error(), "pdfcpu: "))
+	}
+
+	if err := s.parseByteRange(xRefTable, int64(len(bb))); err != nil {
+		// Report what has been parsed but never rely on it.
+		sr.ByteRange = s.byteRange
+		problem(err)
+		return
+	}
+	br := s.byteRange
+	sr.ByteRange = br
+	sr.ByteRangeOK = true
+	sr.AppendedBytes = int64(len(bb)) - s.signedLen()
+
+	switch sr.SubFilter {
+	case model.SignatureFormatPKCS7, model.SignatureFormatCAdES, SignatureFormatPKCS7SHA1:
+	default:
+		problem(errors.Errorf("pdfcpu: unsupported SubFilter: %s", sr.SubFilter))
+		return
+	}
+
+	der, err := s.contents(bb)
+	if err != nil {
+		problem(err)
+		return
+	}
+
+	cs, err := parseSignedData(der)
+	if err != nil {
+		problem(err)
+		return
+	}
+
+	if cs.signer.Subject.CommonName != "" {
+		sr.Signer = cs.signer.Subject.CommonName
+	} else {
+		sr.Signer = cs.signer.Subject.String()
+	}
+
+	if !cs.signingTime.IsZero() {
+		sr.SigningTime = cs.signingTime
+	}
+
+	// The signed content is the concatenation of both byte ranges.
+	h := cs.hash.New()
+	h.Write(bb[br[0] : br[0]+br[1]])
+	h.Write(bb[br[2] : br[2]+br[3]])
+	digest := h.Sum(nil)
+
+	if sr.SubFilter == SignatureFormatPKCS7SHA1 {
+		// The encapsulated content is the SHA-1 digest of the signed byte range.
+		h := sha1.New()
+		h.Write(bb[br[0] : br[0]+br[1]])
+		h.Write(bb[br[2] : br[2]+br[3]])
+		if !bytes.Equal(cs.eContent, h.Sum(nil)) {
+			problem(errors.New("pdfcpu: encapsulated digest mismatch"))
+			return
+		}
+		h1 := cs.hash.New()
+		h1.Write(cs.eContent)
+		digest = h1.Sum(nil)
+	}
+
+	if sr.DigestOK, err = cs.verify(digest); err != nil {
+		problem(err)
+	} else {
+		sr.SignatureOK = true
+	}
+
+	if !sr.DigestOK {
+		return
+	}
+
+	if roots == nil {
+		problem(errors.New("pdfcpu: no trust store available"))
+		return
+	}
+
+	// The signing time is claimed by the signer and therefore not trustworthy.
+	// Without a verified RFC 3161 timestamp token the chain has to be valid now.
+	if err := cs.verifyChain(roots, time.Now()); err != nil {
+		problem(err)
+		return
+	}
+
+	sr.Trusted = true
+}
+
+// VerifySignatures verifies all signatures of the document bb whose context is ctx against the certificates in roots.
+// readRevision is used to read the revision covered by a signature for detecting modifications made by later revisions.
+func VerifySignatures(
+	ctx *model.Context,
+	bb []byte,
+	roots *x509.CertPool,
+	readRevision func(size int64) (*model.Context, error)) ([]*SignatureResult, error) {
+
+	sigs, err := signatures(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range sigs {
+		s.verify(ctx.XRefTable, bb, roots)
+	}
+
+	// Process signatures in the order they have been applied.
+	sort.SliceStable(sigs, func(i, j int) bool { return sigs[i].signedLen() < sigs[j].signedLen() })
+
+	for i, s := range sigs {
+		if !s.result.ByteRangeOK || s.result.AppendedBytes == 0 {
+			continue
+		}
+
+		ctxRev, err := readRevision(s.signedLen())
+		if err != nil {
+			s.result.Problems = append(s.result.Problems, fmt.Sprintf("signed revision unreadable: %v", err))
+			continue
+		}
+
+		// Permissions granted by this and any earlier signature remain in effect.
+		p := 0
+		var locks []fieldLock
+		for _, s1 := range sigs[:i+1] {
+			if s1.docMDP > 0 && (p == 0 || s1.docMDP < p) {
+				p = s1.docMDP
+			}
+			locks = append(locks, s1.locks...)
+		}
+
+		s.result.Modifications = modifications(ctxRev, ctx, p, locks)
+	}
+
+	results := make([]*SignatureResult, len(sigs))
+	for i, s := range sigs {
+		results[i] = s.result
+	}
+
+	return results, nil
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/pdfcpu/pdfcpu/pkg/filter"
 	"github.com/pdfcpu/pdfcpu/pkg/font"
 	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/barcode"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/draw"
 	pdffont "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
@@ -474,6 +475,13 @@ func parseWatermarkDetails(mode int, modeParm, s string, onTop bool, u types.Dis
 	wm.OnTop = onTop
 	wm.InpUnit = u
 
+	if mode == model.WMBarcode {
+		// Barcodes default to upright black modules of 1 point.
+		wm.FillColor = color.Black
+		wm.Diagonal = model.NoDiagonal
+		wm.Scale, wm.ScaleAbs = 1, true
+	}
+
 	ss := strings.Split(s, ",")
 	if len(ss) > 0 && len(ss[0]) == 0 {
 		return wm, setWatermarkType(mode, modeParm, wm)
@@ -511,6 +519,12 @@ func ParsePDFWatermarkDetails(fileName, desc string, onTop bool, u types.Display
 	return parseWatermarkDetails(model.WMPDF, fileName, desc, onTop, u)
 }
 
+// ParseBarcodeWatermarkDetails parses a barcode Watermark/Stamp command string into an internal structure.
+// code is a barcode type followed by a colon and the content template eg. "qr:https://pdfcpu.io/%p".
+func ParseBarcodeWatermarkDetails(code, desc string, onTop bool, u types.DisplayUnit) (*model.Watermark, error) {
+	return parseWatermarkDetails(model.WMBarcode, code, desc, onTop, u)
+}
+
 func onTopString(onTop bool) string {
 	e := "watermark"
 	if onTop {
@@ -645,6 +659,29 @@ func setPDFWatermark(s string, wm *model.Watermark) error {
 	return nil
 }
 
+func setBarcodeWatermark(s string, wm *model.Watermark) error {
+	ss := strings.SplitN(s, ":", 2)
+	if len(ss) != 2 || ss[1] == "" {
+		return errors.Errorf("pdfcpu: barcode: please provide type:content, got: %s", s)
+	}
+
+	sym, err := barcode.ParseSymbology(ss[0])
+	if err != nil {
+		return err
+	}
+
+	// Fail early for content not encodable regardless of the page.
+	t, _ := format.Text(ss[1], "", 1, 1)
+	if _, err := sym.Encode(t); err != nil {
+		return err
+	}
+
+	wm.Barcode = sym
+	wm.TextString = ss[1]
+
+	return nil
+}
+
 func setWatermarkType(mode int, s string, wm *model.Watermark) (err error) {
 	wm.Mode = mode
 	switch wm.Mode {
@@ -656,6 +693,9 @@ func setWatermarkType(mode int, s string, wm *model.Watermark) (err error) {
 
 	case model.WMPDF:
 		err = setPDFWatermark(s, wm)
+
+	case model.WMBarcode:
+		err = setBarcodeWatermark(s, wm)
 	}
 	return err
 }
@@ -767,6 +807,10 @@ func createResourcesForWM(ctx *model.Context, wm *model.Watermark) error {
 	if wm.IsImage() {
 		return createImageResForWM(ctx, wm)
 	}
+	if wm.IsBarcode() {
+		// Barcodes are made of paths only.
+		return nil
+	}
 	return createFontResForWM(ctx, wm)
 }
 
@@ -888,6 +932,10 @@ func createFormResDict(ctx *model.Context, pageNr int, wm *model.Watermark) (*ty
 		return ctx.IndRefForNewObject(d)
 	}
 
+	if wm.IsBarcode() {
+		return nil, nil
+	}
+
 	d := types.Dict(
 		map[string]types.Object{
 			"Font":    types.Dict(map[string]types.Object{"F1": *wm.Font}),
@@ -933,12 +981,18 @@ func imageFormContent(w io.Writer, wm model.Watermark) {
 	fmt.Fprintf(w, "q %f 0 0 %f 0 0 cm /Im0 Do Q", wm.Bb.Width(), wm.Bb.Height()) // TODO dont need Q
 }
 
+func barcodeFormContent(w io.Writer, wm model.Watermark) {
+	wm.Code.Draw(w, types.RectForDim(wm.Bb.Width(), wm.Bb.Height()), wm.FillColor, wm.BgColor)
+}
+
 func formContent(w io.Writer, pageNr int, wm model.Watermark) error {
 	switch true {
 	case wm.IsPDF():
 		return pdfFormContent(w, pageNr, wm)
 	case wm.IsImage():
 		imageFormContent(w, wm)
+	case wm.IsBarcode():
+		barcodeFormContent(w, wm)
 	}
 	return nil
 }
@@ -999,22 +1053,44 @@ func drawBoundingBox(b *bytes.Buffer, wm model.Watermark, bb *types.Rectangle) {
 	)
 }
 
-func calcFormBoundingBox(xRefTable *model.XRefTable, w io.Writer, timestampFormat string, pageNr, pageCount int, wm *model.Watermark) bool {
+// setupBarcode encodes the barcode content for pageNr.
+func setupBarcode(wm *model.Watermark, timestampFormat string, pageNr, pageCount int) (bool, error) {
+	t, unique := format.Text(wm.TextString, timestampFormat, pageNr, pageCount)
+	c, err := wm.Barcode.Encode(t)
+	if err != nil {
+		return false, errors.Errorf("%v (page %d)", err, pageNr)
+	}
+	wm.Code = c
+	wm.Width, wm.Height = c.Width(), c.Height()
+	return unique, nil
+}
+
+func calcFormBoundingBox(xRefTable *model.XRefTable, w io.Writer, timestampFormat string, pageNr, pageCount int, wm *model.Watermark) (bool, error) {
 	var unique bool
-	if wm.IsImage() || wm.IsPDF() {
+	switch {
+	case wm.IsImage() || wm.IsPDF():
 		wm.CalcBoundingBox(pageNr)
-	} else {
+	case wm.IsBarcode():
+		var err error
+		if unique, err = setupBarcode(wm, timestampFormat, pageNr, pageCount); err != nil {
+			return false, err
+		}
+		wm.CalcBoundingBox(pageNr)
+	default:
 		var td model.TextDescriptor
 		td, unique = setupTextDescriptor(*wm, timestampFormat, pageNr, pageCount)
 		// Render td into b and return the bounding box.
 		wm.Bb = model.WriteMultiLine(xRefTable, w, types.RectForDim(wm.Vp.Width(), wm.Vp.Height()), nil, td)
 	}
-	return unique
+	return unique, nil
 }
 
 func createForm(ctx *model.Context, pageNr, pageCount int, wm *model.Watermark, withBB bool) error {
 	var b bytes.Buffer
-	unique := calcFormBoundingBox(ctx.XRefTable, &b, ctx.Configuration.TimestampFormat, pageNr, pageCount, wm)
+	unique, err := calcFormBoundingBox(ctx.XRefTable, &b, ctx.Configuration.TimestampFormat, pageNr, pageCount, wm)
+	if err != nil {
+		return err
+	}
 
 	// The forms bounding box is dependent on the page dimensions.
 	bb := wm.Bb
@@ -1030,7 +1106,7 @@ func createForm(ctx *model.Context, pageNr, pageCount int, wm *model.Watermark,
 		}
 	}
 
-	if wm.IsImage() || wm.IsPDF() {
+	if !wm.IsText() {
 		if err := formContent(&b, pageNr, *wm); err != nil {
 			return err
 		}
@@ -1145,6 +1221,11 @@ func updatePageResourcesForWM(ctx *model.Context, resDict types.Dict, wm model.W
 	return nil
 }
 
+// artifactMarker returns the marked content sequence prefix for pagination artifacts of subtype.
+func artifactMarker(subtype string) string {
+	return "/Artifact <</Subtype /" + subtype + " /Type /Pagination >>BDC"
+}
+
 func wmContent(wm *model.Watermark, gsID, xoID string) []byte {
 	m := wm.CalcTransformMatrix()
 	p1 := m.Transform(types.Point{X: wm.Bb.LL.X, Y: wm.Bb.LL.Y})
@@ -1152,7 +1233,11 @@ func wmContent(wm *model.Watermark, gsID, xoID string) []byte {
 	p3 := m.Transform(types.Point{X: wm.Bb.UR.X, Y: wm.Bb.UR.Y})
 	p4 := m.Transform(types.Point{X: wm.Bb.LL.X, Y: wm.Bb.UR.Y})
 	wm.BbTrans = types.QuadLiteral{P1: p1, P2: p2, P3: p3, P4: p4}
-	insertOCG := " /Artifact <</Subtype /Watermark /Type /Pagination >>BDC q %.5f %.5f %.5f %.5f %.5f %.5f cm /%s gs /%s Do Q EMC "
+	subtype := wm.Artifact
+	if subtype == "" {
+		subtype = "Watermark"
+	}
+	insertOCG := " " + artifactMarker(subtype) + " q %.5f %.5f %.5f %.5f %.5f %.5f cm /%s gs /%s Do Q EMC "
 	var b bytes.Buffer
 	fmt.Fprintf(&b, insertOCG, m[0][0], m[0][1], m[1][0], m[1][1], m[2][0], m[2][1], gsID, xoID)
 	return b.Bytes()
@@ -1456,6 +1541,10 @@ func createResourcesForPageNr(
 		return createPDFResForWM(ctx, wm)
 	}
 
+	if wm.IsBarcode() {
+		return nil
+	}
+
 	// Text watermark
 
 	if font.IsUserFont(wm.FontName) {
@@ -1687,7 +1776,18 @@ func removeForms(ctx *model.Context, d types.Dict, ids []string, i int) error {
 	return removeResDictEntry(ctx, d, "XObject", ids, i)
 }
 
-func removeArtifacts(sd *types.StreamDict, i int) (ok bool, extGStates []string, forms []string, err error) {
+// firstArtifact returns the position of the first pagination artifact of one of subtypes in s.
+func firstArtifact(s string, subtypes []string) int {
+	beg := -1
+	for _, st := range subtypes {
+		if i := strings.Index(s, artifactMarker(st)); i >= 0 && (beg < 0 || i < beg) {
+			beg = i
+		}
+	}
+	return beg
+}
+
+func removeArtifacts(sd *types.StreamDict, i int, subtypes []string) (ok bool, extGStates []string, forms []string, err error) {
 	err = sd.Decode()
 	if err == filter.ErrUnsupportedFilter {
 		if log.InfoEnabled() {
@@ -1705,7 +1805,7 @@ func removeArtifacts(sd *types.StreamDict, i int) (ok bool, extGStates []string,
 
 	for {
 		s := string(sd.Content)
-		beg := strings.Index(s, "/Artifact <</Subtype /Watermark /Type /Pagination >>BDC")
+		beg := firstArtifact(s, subtypes)
 		if beg < 0 {
 			break
 		}
@@ -1748,10 +1848,10 @@ func removeArtifacts(sd *types.StreamDict, i int) (ok bool, extGStates []string,
 	return patched, extGStates, forms, err
 }
 
-func removeArtifactsFromPage(ctx *model.Context, sd *types.StreamDict, resDict types.Dict, i int) (bool, error) {
+func removeArtifactsFromPage(ctx *model.Context, sd *types.StreamDict, resDict types.Dict, i int, subtypes []string) (bool, error) {
 	// Remove watermark artifacts and locate id's
 	// of used extGStates and forms.
-	ok, extGStates, forms, err := removeArtifacts(sd, i)
+	ok, extGStates, forms, err := removeArtifacts(sd, i, subtypes)
 	if err != nil {
 		return false, err
 	}
@@ -1794,12 +1894,12 @@ func locatePageContentAndResourceDict(ctx *model.Context, pageNr int) (types.Obj
 	return o, pageDictIndRef, resDict, nil
 }
 
-func removeArtifacts1(ctx *model.Context, o types.Object, entry *model.XRefTableEntry, resDict types.Dict, pageNr int) (bool, error) {
+func removeArtifacts1(ctx *model.Context, o types.Object, entry *model.XRefTableEntry, resDict types.Dict, pageNr int, subtypes []string) (bool, error) {
 	found := false
 	switch o := o.(type) {
 
 	case types.StreamDict:
-		ok, err := removeArtifactsFromPage(ctx, &o, resDict, pageNr)
+		ok, err := removeArtifactsFromPage(ctx, &o, resDict, pageNr, subtypes)
 		if err != nil {
 			return false, err
 		}
@@ -1817,7 +1917,7 @@ func removeArtifacts1(ctx *model.Context, o types.Object, entry *model.XRefTable
 		entry, _ := ctx.FindTableEntry(objNr, genNr)
 		sd, _ := (entry.Object).(types.StreamDict)
 
-		ok, err := removeArtifactsFromPage(ctx, &sd, resDict, pageNr)
+		ok, err := removeArtifactsFromPage(ctx, &sd, resDict, pageNr, subtypes)
 		if err != nil {
 			return false, err
 		}
@@ -1838,7 +1938,7 @@ func removeArtifacts1(ctx *model.Context, o types.Object, entry *model.XRefTable
 			entry, _ := ctx.FindTableEntry(objNr, genNr)
 			sd, _ := (entry.Object).(types.StreamDict)
 
-			ok, err = removeArtifactsFromPage(ctx, &sd, resDict, pageNr)
+			ok, err = removeArtifactsFromPage(ctx, &sd, resDict, pageNr, subtypes)
 			if err != nil {
 				return false, err
 			}
@@ -1852,10 +1952,11 @@ func removeArtifacts1(ctx *model.Context, o types.Object, entry *model.XRefTable
 	return found, nil
 }
 
-func removePageWatermark(ctx *model.Context, pageNr int) (bool, error) {
+// removePageArtifacts removes all pagination artifacts of one of subtypes from page pageNr.
+func removePageArtifacts(ctx *model.Context, pageNr int, subtypes []string) (bool, *types.IndirectRef, error) {
 	o, pageDictIndRef, resDict, err := locatePageContentAndResourceDict(ctx, pageNr)
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
 
 	var entry *model.XRefTableEntry
@@ -1868,7 +1969,16 @@ func removePageWatermark(ctx *model.Context, pageNr int) (bool, error) {
 		o = entry.Object
 	}
 
-	found, err := removeArtifacts1(ctx, o, entry, resDict, pageNr)
+	found, err := removeArtifacts1(ctx, o, entry, resDict, pageNr, subtypes)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return found, pageDictIndRef, nil
+}
+
+func removePageWatermark(ctx *model.Context, pageNr int) (bool, error) {
+	found, pageDictIndRef, err := removePageArtifacts(ctx, pageNr, []string{"Watermark"})
 	if err != nil {
 		return false, err
 	}
@@ -2005,7 +2115,7 @@ func detectArtifacts(sd *types.StreamDict) (bool, error) {
 		return false, err
 	}
 	// Watermarks may begin or end the content stream.
-	i := strings.Index(string(sd.Content), "/Artifact <</Subtype /Watermark /Type /Pagination >>BDC")
+	i := strings.Index(string(sd.Content), artifactMarker("Watermark"))
 	return i >= 0, nil
 }
 
@@ -30,8 +30,9 @@ import (
 
 // PDFFilter represents a PDF stream filter object.
 type PDFFilter struct {
-	Name        string
-	DecodeParms Dict
+	Name         string
+	DecodeParms  Dict
+	JBIG2Globals []byte // Decoded JBIG2Globals stream content for JBIG2Decode.
 }
 
 // StreamDict represents a PDF stream dict object.
@@ -75,6 +76,7 @@ func (sd StreamDict) Clone() Object {
 		if v.DecodeParms != nil {
 			f.DecodeParms = v.DecodeParms.Clone().(Dict)
 		}
+		f.JBIG2Globals = v.JBIG2Globals
 		pl[k] = f
 	}
 	sd1.FilterPipeline = pl
@@ -363,6 +365,10 @@ func (sd *StreamDict) decodeLength(maxLen int64) ([]byte, error) {
 			return nil, err
 		}
 
+		if f.Name == filter.JBIG2 {
+			fi = filter.NewJBIG2Filter(parms, f.JBIG2Globals)
+		}
+
 		if maxLen >= 0 && idx == len(sd.FilterPipeline)-1 {
 			c, err = fi.DecodeLength(b, maxLen)
 		} else {
@@ -0,0 +1,835 @@
+/*
+Copyright 2025 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// See ISO 19005-1:2005 (PDF/A-1), ISO 19005-2:2011 (PDF/A-2) and ISO 19005-3:2012 (PDF/A-3).
+
+type pdfaRule int
+
+const (
+	ruleEncryption pdfaRule = iota
+	ruleOutputIntent
+	ruleFontEmbedding
+	ruleAction
+	ruleAdditionalAction
+	ruleAnnotation
+	ruleMetadata
+	ruleMetadataInfo
+	ruleIdentification
+	ruleTransparency
+	ruleEmbeddedFile
+)
+
+// pdfaClauses maps each rule to its clause in parts 1, 2 and 3.
+var pdfaClauses = map[pdfaRule][3]string{
+	ruleEncryption:       {"6.1.3", "6.1.3", "6.1.3"},
+	ruleOutputIntent:     {"6.2.2", "6.2.3", "6.2.3"},
+	ruleFontEmbedding:    {"6.3.4", "6.2.11.4.1", "6.2.11.4.1"},
+	ruleAction:           {"6.6.1", "6.5.1", "6.5.1"},
+	ruleAdditionalAction: {"6.6.2", "6.5.2", "6.5.2"},
+	ruleAnnotation:       {"6.5.3", "6.3.1", "6.3.1"},
+	ruleMetadata:         {"6.7.2", "6.6.2.1", "6.6.2.1"},
+	ruleMetadataInfo:     {"6.7.3", "6.6.3", "6.6.3"},
+	ruleIdentification:   {"6.7.11", "6.6.4", "6.6.4"},
+	ruleTransparency:     {"6.4", "6.2.10", "6.2.10"},
+	ruleEmbeddedFile:     {"6.1.11", "6.8", "6.8"},
+}
+
+// XMP namespaces relevant for PDF/A.
+const (
+	nsRDF    = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	nsDC     = "http://purl.org/dc/elements/1.1/"
+	nsXMP    = "http://ns.adobe.com/xap/1.0/"
+	nsPDF    = "http://ns.adobe.com/pdf/1.3/"
+	nsPDFAID = "http://www.aiim.org/pdfa/ns/id/"
+)
+
+// infoXMPProperties maps document information dict entries to their XMP equivalents.
+var infoXMPProperties = []struct {
+	key, prop string
+	date      bool
+}{
+	{"Title", nsDC + "title", false},
+	{"Author", nsDC + "creator", false},
+	{"Subject", nsDC + "description", false},
+	{"Keywords", nsPDF + "Keywords", false},
+	{"Creator", nsXMP + "CreatorTool", false},
+	{"Producer", nsPDF + "Producer", false},
+	{"CreationDate", nsXMP + "CreateDate", true},
+	{"ModDate", nsXMP + "ModifyDate", true},
+}
+
+var (
+	pdfa1Annotations = []string{"FileAttachment", "Sound", "Movie", "Screen", "3D", "RichMedia"}
+	pdfa2Annotations = []string{"Sound", "Movie", "Screen", "3D", "RichMedia"}
+
+	blendModes = []string{
+		"Normal", "Compatible", "Multiply", "Screen", "Overlay", "Darken", "Lighten", "ColorDodge", "ColorBurn",
+		"HardLight", "SoftLight", "Difference", "Exclusion", "Hue", "Saturation", "Color", "Luminosity",
+	}
+)
+
+type pdfaChecker struct {
+	xRefTable    *model.XRefTable
+	part         int
+	visited      types.IntSet
+	outputIntent bool
+	violations   []model.PDFAViolation
+}
+
+// PDFA checks xRefTable against the PDF/A conformance level profile and returns all rule violations found.
+func PDFA(xRefTable *model.XRefTable, profile string) ([]model.PDFAViolation, error) {
+	part := model.PDFAPart(profile)
+	if part == 0 {
+		return nil, errors.Errorf("pdfcpu: unsupported validation profile: %s", profile)
+	}
+
+	rootDict, err := xRefTable.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &pdfaChecker{xRefTable: xRefTable, part: part, visited: types.IntSet{}}
+
+	if xRefTable.Encrypt != nil {
+		c.report(ruleEncryption, xRefTable.Encrypt.ObjectNumber.Value(), "encryption is not permitted")
+	}
+
+	c.checkOutputIntents(rootDict)
+	c.checkMetadata(rootDict)
+	c.checkCatalog(rootDict)
+	c.checkEmbeddedFiles(rootDict)
+
+	for pageNr := 1; pageNr <= xRefTable.PageCount; pageNr++ {
+		d, indRef, inhPAttrs, err := xRefTable.PageDict(pageNr, false)
+		if err != nil {
+			return nil, err
+		}
+		if d == nil {
+			continue
+		}
+		objNr := 0
+		if indRef != nil {
+			objNr = indRef.ObjectNumber.Value()
+		}
+		var res types.Dict
+		if inhPAttrs != nil {
+			res = inhPAttrs.Resources
+		}
+		c.checkPage(d, objNr, res)
+	}
+
+	return c.violations, nil
+}
+
+func (c *pdfaChecker) report(r pdfaRule, objNr int, format string, args ...interface{}) {
+	c.violations = append(c.violations, model.PDFAViolation{
+		Rule:  pdfaClauses[r][c.part-1],
+		ObjNr: objNr,
+		Msg:   fmt.Sprintf(format, args...),
+	})
+}
+
+// visit returns true if the object objNr has not been checked yet.
+// Direct objects (objNr 0) are always checked.
+func (c *pdfaChecker) visit(objNr int) bool {
+	if objNr == 0 {
+		return true
+	}
+	if c.visited[objNr] {
+		return false
+	}
+	c.visited[objNr] = true
+	return true
+}
+
+func objNumber(o types.Object) int {
+	if ir, ok := o.(types.IndirectRef); ok {
+		return ir.ObjectNumber.Value()
+	}
+	return 0
+}
+
+func (c *pdfaChecker) dict(o types.Object) (types.Dict, int) {
+	d, err := c.xRefTable.DereferenceDict(o)
+	if err != nil {
+		return nil, objNumber(o)
+	}
+	return d, objNumber(o)
+}
+
+func (c *pdfaChecker) stream(o types.Object) (*types.StreamDict, int) {
+	sd, _, err := c.xRefTable.DereferenceStreamDict(o)
+	if err != nil {
+		return nil, objNumber(o)
+	}
+	return sd, objNumber(o)
+}
+
+func (c *pdfaChecker) array(o types.Object) types.Array {
+	a, err := c.xRefTable.DereferenceArray(o)
+	if err != nil {
+		return nil
+	}
+	return a
+}
+
+func (c *pdfaChecker) name(o types.Object) string {
+	o, err := c.xRefTable.Dereference(o)
+	if err != nil {
+		return ""
+	}
+	if n, ok := o.(types.Name); ok {
+		return n.Value()
+	}
+	return ""
+}
+
+// values returns the values of d ordered by key.
+func values(d types.Dict) []types.Object {
+	keys := make([]string, 0, len(d))
+	for k := range d {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	oo := make([]types.Object, len(keys))
+	for i, k := range keys {
+		oo[i] = d[k]
+	}
+	return oo
+}
+
+func (c *pdfaChecker) checkOutputIntents(rootDict types.Dict) {
+	found := false
+	profileObjNr := 0
+	for _, o := range c.array(rootDict["OutputIntents"]) {
+		d, objNr := c.dict(o)
+		if d == nil || c.name(d["S"]) != "GTS_PDFA1" {
+			continue
+		}
+		found = true
+		sd, sdObjNr := c.stream(d["DestOutputProfile"])
+		if sd == nil {
+			c.report(ruleOutputIntent, objNr, "output intent without DestOutputProfile")
+			continue
+		}
+		if profileObjNr > 0 && sdObjNr != profileObjNr {
+			c.report(ruleOutputIntent, objNr, "output intents using different destination profiles")
+		}
+		profileObjNr = sdObjNr
+		if c.checkICCProfile(sd, sdObjNr) {
+			c.outputIntent = true
+		}
+	}
+
+	if !found {
+		c.report(ruleOutputIntent, 0, "missing PDF/A output intent (GTS_PDFA1) with ICC profile")
+	}
+}
+
+// checkICCProfile checks the header of an ICC profile used as destination output profile.
+func (c *pdfaChecker) checkICCProfile(sd *types.StreamDict, objNr int) bool {
+	if err := sd.Decode(); err != nil {
+		c.report(ruleOutputIntent, objNr, "undecodable ICC profile: %v", err)
+		return false
+	}
+
+	bb := sd.Content
+	if len(bb) < 128 || string(bb[36:40]) != "acsp" {
+		c.report(ruleOutputIntent, objNr, "invalid ICC profile")
+		return false
+	}
+
+	if major := int(bb[8]); c.part == 1 && major >= 4 || major > 4 {
+		c.report(ruleOutputIntent, objNr, "ICC profile version %d not permitted", major)
+		return false
+	}
+
+	if class := string(bb[12:16]); class != "prtr" && class != "mntr" {
+		c.report(ruleOutputIntent, objNr, "ICC profile device class %q not permitted", class)
+		return false
+	}
+
+	return true
+}
+
+func (c *pdfaChecker) checkMetadata(rootDict types.Dict) {
+	sd, objNr := c.stream(rootDict["Metadata"])
+	if sd == nil {
+		c.report(ruleMetadata, 0, "missing XMP metadata stream")
+		return
+	}
+
+	if _, found := sd.Find("Filter"); found {
+		c.report(ruleMetadata, objNr, "metadata stream shall not be filtered")
+	}
+
+	if err := sd.Decode(); err != nil {
+		c.report(ruleMetadata, objNr, "undecodable metadata stream: %v", err)
+		return
+	}
+
+	props, err := xmpProperties(sd.Content)
+	if err != nil {
+		c.report(ruleMetadata, objNr, "malformed XMP metadata: %v", err)
+		return
+	}
+
+	c.checkIdentification(props, objNr)
+	c.checkInfoDict(props)
+}
+
+func (c *pdfaChecker) checkIdentification(props map[string][]string, objNr int) {
+	part, conf := props[nsPDFAID+"part"], props[nsPDFAID+"conformance"]
+	if len(part) == 0 || len(conf) == 0 {
+		c.report(ruleIdentification, objNr, "missing PDF/A identification schema")
+		return
+	}
+
+	if part[0] != strconv.Itoa(c.part) {
+		c.report(ruleIdentification, objNr, "identification claims PDF/A-%s", part[0])
+	}
+
+	levels := []string{"A", "B", "U"}
+	if c.part == 1 {
+		levels = levels[:2]
+	}
+	if !types.MemberOf(conf[0], levels) {
+		c.report(ruleIdentification, objNr, "invalid conformance level %q", conf[0])
+	}
+}
+
+// checkInfoDict checks the document information dict against its XMP equivalents.
+func (c *pdfaChecker) checkInfoDict(props map[string][]string) {
+	if c.xRefTable.Info == nil {
+		return
+	}
+
+	d, objNr := c.dict(*c.xRefTable.Info)
+	if d == nil {
+		return
+	}
+
+	for _, e := range infoXMPProperties {
+		o, found := d.Find(e.key)
+		if !found {
+			continue
+		}
+		s, err := c.xRefTable.DereferenceText(o)
+		if err != nil || s == "" {
+			continue
+		}
+		vv := props[e.prop]
+		if len(vv) == 0 {
+			c.report(ruleMetadataInfo, objNr, "info entry %s missing in XMP metadata", e.key)
+			continue
+		}
+		if e.date {
+			if !sameDate(s, vv[0]) {
+				c.report(ruleMetadataInfo, objNr, "info entry %s (%s) does not match XMP metadata (%s)", e.key, s, vv[0])
+			}
+			continue
+		}
+		if !types.MemberOf(s, vv) && s != strings.Join(vv, ", ") {
+			c.report(ruleMetadataInfo, objNr, "info entry %s %q does not match XMP metadata %q", e.key, s, strings.Join(vv, ", "))
+		}
+	}
+}
+
+// sameDate compares a PDF date with an XMP date.
+func sameDate(pdfDate, xmpDate string) bool {
+	t1, ok := types.DateTime(pdfDate, true)
+	if !ok {
+		return false
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04Z07:00", "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"} {
+		if t2, err := time.Parse(layout, xmpDate); err == nil {
+			return t1.Truncate(time.Second).Equal(t2.Truncate(time.Second))
+		}
+	}
+	return false
+}
+
+// xmpProperties returns the values of all top level properties of the rdf:Description elements
+// of an XMP packet keyed by namespace URI and property name.
+// Array properties (rdf:Alt, rdf:Bag, rdf:Seq) yield one value per rdf:li.
+func xmpProperties(bb []byte) (map[string][]string, error) {
+	props := map[string][]string{}
+	dec := xml.NewDecoder(bytes.NewReader(bb))
+
+	var (
+		stack     []xml.Name
+		prop      string
+		propDepth int
+		items     bool
+		text      strings.Builder
+	)
+
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := t.(type) {
+
+		case xml.StartElement:
+			inDesc := len(stack) > 0 && stack[len(stack)-1] == xml.Name{Space: nsRDF, Local: "Description"}
+			stack = append(stack, t.Name)
+
+			if prop != "" {
+				if t.Name == (xml.Name{Space: nsRDF, Local: "li"}) {
+					items = true
+					text.Reset()
+				}
+				continue
+			}
+
+			if t.Name == (xml.Name{Space: nsRDF, Local: "Description"}) {
+				// Simple properties may be expressed as attributes.
+				for _, a := range t.Attr {
+					if a.Name.Space != "" && a.Name.Space != nsRDF && a.Name.Space != "xmlns" {
+						k := a.Name.Space + a.Name.Local
+						props[k] = append(props[k], a.Value)
+					}
+				}
+				continue
+			}
+
+			if inDesc {
+				prop, propDepth, items = t.Name.Space+t.Name.Local, len(stack), false
+				text.Reset()
+			}
+
+		case xml.CharData:
+			text.Write(t)
+
+		case xml.EndElement:
+			if prop != "" {
+				if len(stack) == propDepth {
+					if s := strings.TrimSpace(text.String()); !items && s != "" {
+						props[prop] = append(props[prop], s)
+					}
+					prop = ""
+				} else if t.Name == (xml.Name{Space: nsRDF, Local: "li"}) {
+					props[prop] = append(props[prop], strings.TrimSpace(text.String()))
+					text.Reset()
+				}
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	return props, nil
+}
+
+func (c *pdfaChecker) checkCatalog(rootDict types.Dict) {
+	if _, found := rootDict.Find("AA"); found {
+		c.report(ruleAdditionalAction, objNumber(rootDict["AA"]), "catalog additional actions not permitted")
+	}
+
+	if o, found := rootDict.Find("OpenAction"); found {
+		if d, _ := c.dict(o); d != nil {
+			c.checkAction(o)
+		}
+	}
+
+	if names, _ := c.dict(rootDict["Names"]); names != nil {
+		if o, found := names.Find("JavaScript"); found {
+			c.report(ruleAction, objNumber(o), "JavaScript name tree not permitted")
+		}
+	}
+
+	if outlines, _ := c.dict(rootDict["Outlines"]); outlines != nil {
+		c.checkOutlineItems(outlines["First"])
+	}
+
+	if form, _ := c.dict(rootDict["AcroForm"]); form != nil {
+		for _, o := range c.array(form["Fields"]) {
+			c.checkField(o)
+		}
+	}
+}
+
+func (c *pdfaChecker) checkOutlineItems(o types.Object) {
+	for o != nil {
+		d, objNr := c.dict(o)
+		if d == nil || !c.visit(objNr) {
+			return
+		}
+		if a, found := d.Find("A"); found {
+			c.checkAction(a)
+		}
+		c.checkOutlineItems(d["First"])
+		o = d["Next"]
+	}
+}
+
+func (c *pdfaChecker) checkField(o types.Object) {
+	d, objNr := c.dict(o)
+	if d == nil || !c.visit(objNr) {
+		return
+	}
+	if _, found := d.Find("AA"); found {
+		c.report(ruleAdditionalAction, objNr, "form field additional actions not permitted")
+	}
+	if a, found := d.Find("A"); found {
+		c.checkAction(a)
+	}
+	for _, kid := range c.array(d["Kids"]) {
+		c.checkField(kid)
+	}
+}
+
+func (c *pdfaChecker) checkAction(o types.Object) {
+	d, objNr := c.dict(o)
+	if d == nil || !c.visit(objNr) {
+		return
+	}
+
+	s, n := c.name(d["S"]), c.name(d["N"])
+	if !model.PDFAActionPermitted(c.part, s, n) {
+		if s == "Named" {
+			c.report(ruleAction, objNr, "named action %s not permitted", n)
+		} else {
+			c.report(ruleAction, objNr, "%s action not permitted", s)
+		}
+	}
+
+	next, err := c.xRefTable.Dereference(d["Next"])
+	if err != nil {
+		return
+	}
+	switch next := next.(type) {
+	case types.Dict:
+		c.checkAction(d["Next"])
+	case types.Array:
+		for _, o := range next {
+			c.checkAction(o)
+		}
+	}
+}
+
+// embeddedFileSpecs returns the values of the embedded files name tree rooted at o.
+func (c *pdfaChecker) embeddedFileSpecs(o types.Object, specs []types.Object) []types.Object {
+	d, objNr := c.dict(o)
+	if d == nil || !c.visit(objNr) {
+		return specs
+	}
+	names := c.array(d["Names"])
+	for i := 1; i < len(names); i += 2 {
+		specs = append(specs, names[i])
+	}
+	for _, kid := range c.array(d["Kids"]) {
+		specs = c.embeddedFileSpecs(kid, specs)
+	}
+	return specs
+}
+
+func (c *pdfaChecker) checkEmbeddedFiles(rootDict types.Dict) {
+	names, _ := c.dict(rootDict["Names"])
+	if names == nil {
+		return
+	}
+
+	o, found := names.Find("EmbeddedFiles")
+	if !found {
+		return
+	}
+
+	if c.part == 1 {
+		c.report(ruleEmbeddedFile, objNumber(o), "embedded files not permitted")
+		return
+	}
+
+	for _, spec := range c.embeddedFileSpecs(o, nil) {
+		c.checkFileSpec(spec)
+	}
+}
+
+// checkFileSpec checks the file specification for an embedded file.
+func (c *pdfaChecker) checkFileSpec(o types.Object) {
+	d, objNr := c.dict(o)
+	if d == nil || !c.visit(objNr) {
+		return
+	}
+
+	ef, _ := c.dict(d["EF"])
+	if ef == nil {
+		return
+	}
+
+	sd, sdObjNr := c.stream(ef["F"])
+	if sd == nil {
+		return
+	}
+
+	if c.part == 2 {
+		// Embedded files shall be PDF/A conforming files themselves.
+		// Since PDF/A metadata streams are unfiltered the identification schema may be located directly.
+		if err := sd.Decode(); err != nil || !bytes.HasPrefix(bytes.TrimLeft(sd.Content, " \t\r\n"), []byte("%PDF-")) {
+			c.report(ruleEmbeddedFile, sdObjNr, "embedded file is not a PDF file")
+			return
+		}
+		if !bytes.Contains(sd.Content, []byte(nsPDFAID)) {
+			c.report(ruleEmbeddedFile, sdObjNr, "embedded file is not a PDF/A file")
+		}
+		return
+	}
+
+	for _, k := range []string{"F", "UF", "AFRelationship"} {
+		if _, found := d.Find(k); !found {
+			c.report(ruleEmbeddedFile, objNr, "file specification missing %s", k)
+		}
+	}
+
+	if _, found := sd.Find("Subtype"); !found {
+		c.report(ruleEmbeddedFile, sdObjNr, "embedded file missing MIME type (Subtype)")
+	}
+
+	if params, _ := c.dict(sd.Dict["Params"]); params == nil || params["ModDate"] == nil {
+		c.report(ruleEmbeddedFile, sdObjNr, "embedded file missing modification date (Params/ModDate)")
+	}
+}
+
+func (c *pdfaChecker) checkPage(d types.Dict, objNr int, res types.Dict) {
+	if _, found := d.Find("AA"); found {
+		c.report(ruleAdditionalAction, objNr, "page additional actions not permitted")
+	}
+
+	if g, _ := c.dict(d["Group"]); g != nil && c.name(g["S"]) == "Transparency" {
+		if c.part == 1 {
+			c.report(ruleTransparency, objNr, "page transparency group not permitted")
+		} else if _, found := g.Find("CS"); !found && !c.outputIntent {
+			c.report(ruleTransparency, objNr, "page transparency group without blending color space")
+		}
+	}
+
+	c.checkResources(res)
+
+	for _, o := range c.array(d["Annots"]) {
+		c.checkAnnotation(o)
+	}
+}
+
+func (c *pdfaChecker) checkAnnotation(o types.Object) {
+	d, objNr := c.dict(o)
+	if d == nil || !c.visit(objNr) {
+		return
+	}
+
+	forbidden := pdfa2Annotations
+	if c.part == 1 {
+		forbidden = pdfa1Annotations
+	}
+
+	subtype := c.name(d["Subtype"])
+	if types.MemberOf(subtype, forbidden) {
+		c.report(ruleAnnotation, objNr, "%s annotation not permitted", subtype)
+	}
+
+	if _, found := d.Find("AA"); found {
+		c.report(ruleAdditionalAction, objNr, "annotation additional actions not permitted")
+	}
+
+	if a, found := d.Find("A"); found {
+		c.checkAction(a)
+	}
+
+	if subtype == "FileAttachment" && c.part > 1 {
+		c.checkFileSpec(d["FS"])
+	}
+
+	ap, _ := c.dict(d["AP"])
+	if ap == nil {
+		return
+	}
+	for _, k := range []string{"N", "R", "D"} {
+		o, err := c.xRefTable.Dereference(ap[k])
+		if err != nil {
+			continue
+		}
+		switch o := o.(type) {
+		case types.StreamDict:
+			c.checkXObject(ap[k])
+		case types.Dict:
+			// Appearance subdictionary keyed by appearance state.
+			for _, v := range values(o) {
+				c.checkXObject(v)
+			}
+		}
+	}
+}
+
+func (c *pdfaChecker) checkResources(res types.Dict) {
+	if res == nil {
+		return
+	}
+
+	if d, _ := c.dict(res["Font"]); d != nil {
+		for _, o := range values(d) {
+			c.checkFont(o)
+		}
+	}
+
+	if d, _ := c.dict(res["XObject"]); d != nil {
+		for _, o := range values(d) {
+			c.checkXObject(o)
+		}
+	}
+
+	if d, _ := c.dict(res["ExtGState"]); d != nil {
+		for _, o := range values(d) {
+			c.checkExtGState(o)
+		}
+	}
+
+	if d, _ := c.dict(res["Pattern"]); d != nil {
+		for _, o := range values(d) {
+			if sd, objNr := c.stream(o); sd != nil && c.visit(objNr) {
+				// Tiling pattern
+				res, _ := c.dict(sd.Dict["Resources"])
+				c.checkResources(res)
+			} else if d, _ := c.dict(o); d != nil {
+				// Shading pattern
+				c.checkExtGState(d["ExtGState"])
+			}
+		}
+	}
+}
+
+func (c *pdfaChecker) checkFont(o types.Object) {
+	d, objNr := c.dict(o)
+	if d == nil || !c.visit(objNr) {
+		return
+	}
+
+	baseFont := c.name(d["BaseFont"])
+
+	switch c.name(d["Subtype"]) {
+	case "Type3":
+		res, _ := c.dict(d["Resources"])
+		c.checkResources(res)
+		return
+	case "Type0":
+		if df := c.array(d["DescendantFonts"]); len(df) > 0 {
+			d, _ = c.dict(df[0])
+		}
+		if d == nil {
+			return
+		}
+	}
+
+	fd, _ := c.dict(d["FontDescriptor"])
+	if fd != nil {
+		for _, k := range []string{"FontFile", "FontFile2", "FontFile3"} {
+			if _, found := fd.Find(k); found {
+				return
+			}
+		}
+	}
+
+	c.report(ruleFontEmbedding, objNr, "font %s not embedded", baseFont)
+}
+
+func (c *pdfaChecker) checkXObject(o types.Object) {
+	sd, objNr := c.stream(o)
+	if sd == nil || !c.visit(objNr) {
+		return
+	}
+
+	switch c.name(sd.Dict["Subtype"]) {
+
+	case "Image":
+		if _, found := sd.Find("SMask"); found && c.part == 1 {
+			c.report(ruleTransparency, objNr, "image soft mask not permitted")
+		}
+
+	case "Form":
+		if g, _ := c.dict(sd.Dict["Group"]); g != nil && c.name(g["S"]) == "Transparency" && c.part == 1 {
+			c.report(ruleTransparency, objNr, "transparency group not permitted")
+		}
+		res, _ := c.dict(sd.Dict["Resources"])
+		c.checkResources(res)
+	}
+}
+
+func (c *pdfaChecker) checkExtGState(o types.Object) {
+	d, objNr := c.dict(o)
+	if d == nil || !c.visit(objNr) {
+		return
+	}
+
+	if smask, found := d.Find("SMask"); found && c.name(smask) != "None" {
+		if c.part == 1 {
+			c.report(ruleTransparency, objNr, "soft mask not permitted")
+		} else if sm, _ := c.dict(smask); sm != nil {
+			c.checkXObject(sm["G"])
+		}
+	}
+
+	if c.part == 1 {
+		for _, k := range []string{"CA", "ca"} {
+			if o, found := d.Find(k); found {
+				if f, err := c.xRefTable.DereferenceNumber(o); err == nil && f != 1 {
+					c.report(ruleTransparency, objNr, "%s %.2f not permitted", k, f)
+				}
+			}
+		}
+	}
+
+	o, found := d.Find("BM")
+	if !found {
+		return
+	}
+
+	permitted := blendModes
+	if c.part == 1 {
+		permitted = blendModes[:2]
+	}
+
+	bms := []string{c.name(o)}
+	if a := c.array(o); a != nil {
+		bms = bms[:0]
+		for _, o := range a {
+			bms = append(bms, c.name(o))
+		}
+	}
+	for _, bm := range bms {
+		if !types.MemberOf(bm, permitted) {
+			c.report(ruleTransparency, objNr, "blend mode %s not permitted", bm)
+		}
+	}
+}
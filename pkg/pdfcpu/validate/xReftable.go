@@ -90,6 +90,79 @@ func XRefTable(ctx *model.Context) error {
 	return nil
 }
 
+// XRefTableLazy validates the parts of a lazily read xRefTable needed by commands not modifying the document.
+// Unlike XRefTable it does not walk the page tree, outlines, form fields or name trees.
+func XRefTableLazy(ctx *model.Context) error {
+	if log.ValidateEnabled() {
+		log.Validate.Println("*** validateXRefTableLazy begin ***")
+	}
+
+	xRefTable := ctx.XRefTable
+
+	if err := validateDocumentInfoObject(xRefTable); err != nil {
+		return err
+	}
+
+	d, err := xRefTable.Catalog()
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return errors.New("pdfcpu: validateXRefTableLazy: missing root dict")
+	}
+
+	_, err = validateNameEntry(xRefTable, d, "rootDict", "Type", REQUIRED, model.V10, func(s string) bool { return s == "Catalog" })
+	if err != nil {
+		return err
+	}
+
+	if err = xRefTable.EnsurePageCount(); err != nil {
+		return err
+	}
+
+	for _, f := range []struct {
+		validate     func(xRefTable *model.XRefTable, d types.Dict, required bool, sinceVersion model.Version) (err error)
+		sinceVersion model.Version
+	}{
+		{validateViewerPreferences, model.V12},
+		{validatePageLayout, model.V10},
+		{validatePageMode, model.V10},
+		{validateMarkInfo, model.V14},
+	} {
+		if xRefTable.Version() < f.sinceVersion {
+			continue
+		}
+		if err = f.validate(xRefTable, d, OPTIONAL, f.sinceVersion); err != nil {
+			return err
+		}
+	}
+
+	// Locate form and outlines without validating their trees.
+	if o, found := d.Find("AcroForm"); found {
+		if xRefTable.Form, err = xRefTable.DereferenceDict(o); err != nil {
+			return err
+		}
+		if xRefTable.Form != nil {
+			if sf := xRefTable.Form.IntEntry("SigFlags"); sf != nil {
+				xRefTable.SignatureExist = *sf&1 > 0
+				xRefTable.AppendOnly = *sf&2 > 0
+			}
+		}
+	}
+
+	if o, found := d.Find("Outlines"); found {
+		if xRefTable.Outlines, err = xRefTable.DereferenceDict(o); err != nil {
+			return err
+		}
+	}
+
+	if log.ValidateEnabled() {
+		log.Validate.Println("*** validateXRefTableLazy end ***")
+	}
+
+	return nil
+}
+
 func fixInfoDict(xRefTable *model.XRefTable, rootDict types.Dict) error {
 	indRef := rootDict.IndirectRefEntry("Metadata")
 	ok, err := model.EqualObjects(*indRef, *xRefTable.Info, xRefTable)
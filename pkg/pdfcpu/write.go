@@ -110,47 +110,56 @@ func Write(ctx *model.Context) (err error) {
 		v = model.V20
 	}
 
-	if err = writeHeader(ctx.Write, v); err != nil {
-		return err
-	}
-
 	// Ensure there is no root version.
 	if ctx.RootVersion != nil {
 		ctx.RootDict.Delete("Version")
 	}
 
-	if log.WriteEnabled() {
-		log.Write.Printf("offset after writeHeader: %d\n", ctx.Write.Offset)
+	if ctx.Linearize {
+		err = writeLinearized(ctx, v)
+	} else {
+		err = writeNonLinearized(ctx, v)
+	}
+	if err != nil {
+		return err
 	}
 
-	if err := writeObjects(ctx); err != nil {
+	if err = setFileSizeOfWrittenFile(ctx.Write); err != nil {
 		return err
 	}
 
-	// Mark redundant objects as free.
-	// eg. duplicate resources, compressed objects, linearization dicts..
-	deleteRedundantObjects(ctx)
+	if ctx.Read != nil {
+		ctx.Write.BinaryImageSize = ctx.Read.BinaryImageSize
+		ctx.Write.BinaryFontSize = ctx.Read.BinaryFontSize
+		logWriteStats(ctx)
+	}
 
-	if err = writeXRef(ctx); err != nil {
+	return nil
+}
+
+func writeNonLinearized(ctx *model.Context, v model.Version) error {
+	if err := writeHeader(ctx.Write, v); err != nil {
 		return err
 	}
 
-	// Write pdf trailer.
-	if err = writeTrailer(ctx.Write); err != nil {
-		return err
+	if log.WriteEnabled() {
+		log.Write.Printf("offset after writeHeader: %d\n", ctx.Write.Offset)
 	}
 
-	if err = setFileSizeOfWrittenFile(ctx.Write); err != nil {
+	if err := writeObjects(ctx); err != nil {
 		return err
 	}
 
-	if ctx.Read != nil {
-		ctx.Write.BinaryImageSize = ctx.Read.BinaryImageSize
-		ctx.Write.BinaryFontSize = ctx.Read.BinaryFontSize
-		logWriteStats(ctx)
+	// Mark redundant objects as free.
+	// eg. duplicate resources, compressed objects, linearization dicts..
+	deleteRedundantObjects(ctx)
+
+	if err := writeXRef(ctx); err != nil {
+		return err
 	}
 
-	return nil
+	// Write pdf trailer.
+	return writeTrailer(ctx.Write)
 }
 
 // WriteIncrement writes a PDF increment..
@@ -174,6 +183,13 @@ func prepareContextForWriting(ctx *model.Context) error {
 		return err
 	}
 
+	if ctx.PDFAProfile != "" {
+		// Keep XMP metadata in sync with the updated info dict.
+		if err := ensurePDFAMetadata(ctx.XRefTable); err != nil {
+			return err
+		}
+	}
+
 	return handleEncryption(ctx)
 }
 
@@ -916,6 +932,14 @@ func setupEncryption(ctx *model.Context) error {
 		return errors.New("pdfcpu: unsupported encryption algorithm (PDF 2.0 assumes AES/256)")
 	}
 
+	if len(ctx.Recipients) > 0 {
+		d, err := setupPubSecEncryption(ctx)
+		if err != nil {
+			return err
+		}
+		return insertEncryptDict(ctx, d)
+	}
+
 	d := newEncryptDict(
 		ctx.XRefTable.Version(),
 		ctx.EncryptUsingAES,
@@ -943,6 +967,10 @@ func setupEncryption(ctx *model.Context) error {
 		return err
 	}
 
+	return insertEncryptDict(ctx, d)
+}
+
+func insertEncryptDict(ctx *model.Context, d types.Dict) error {
 	xRefTableEntry := model.NewXRefTableEntryGen0(d)
 
 	// Reuse free objects (including recycled objects from this run).
@@ -961,6 +989,10 @@ func updateEncryption(ctx *model.Context) error {
 		return errors.New("pdfcpu: This file is not encrypted - nothing written.")
 	}
 
+	if pubSec(ctx.E) {
+		return errors.New("pdfcpu: not supported for files encrypted for certificate recipients")
+	}
+
 	d, err := ctx.EncryptDict()
 	if err != nil {
 		return err
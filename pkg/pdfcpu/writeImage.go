@@ -941,6 +941,46 @@ func renderDCTToPNG(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool
 	return renderCMYKToPng(im, resourceName)
 }
 
+// jpxColorSpaceFits returns true if the color space of sd applies to JPEG 2000 samples with n components.
+func jpxColorSpaceFits(xRefTable *model.XRefTable, sd *types.StreamDict, n int) bool {
+	o, err := xRefTable.DereferenceDictEntry(sd.Dict, "ColorSpace")
+	if err != nil || o == nil {
+		return false
+	}
+	if a, ok := o.(types.Array); ok && len(a) > 0 && a[0] == types.Name(model.IndexedCS) {
+		return n == 1
+	}
+	comp, err := ColorSpaceComponents(xRefTable, sd)
+	return err == nil && comp == n
+}
+
+// renderJPX renders a JPXDecode image based on its decoded 8 bit samples.
+func renderJPX(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, resourceName string, objNr int) (io.Reader, string, error) {
+	data, _, err := imageData(sd)
+	if err != nil {
+		return nil, "", err
+	}
+
+	bb, w, h, n, err := jpxSamples(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sd1 := *sd
+	sd1.Dict = sd.Dict.Clone().(types.Dict)
+	sd1.Content, sd1.FilterPipeline = bb, nil
+	sd1.Update("Width", types.Integer(w))
+	sd1.Update("Height", types.Integer(h))
+	sd1.Update("BitsPerComponent", types.Integer(8))
+	sd1.Delete("Decode")
+
+	if !jpxColorSpaceFits(xRefTable, &sd1, n) {
+		sd1.Update("ColorSpace", types.Name([]string{"", model.DeviceGrayCS, "", model.DeviceRGBCS, model.DeviceCMYKCS}[n]))
+	}
+
+	return renderImage(xRefTable, &sd1, thumb, resourceName, objNr)
+}
+
 // RenderImage returns a reader for a decoded image stream.
 func RenderImage(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, resourceName string, objNr int) (io.Reader, string, error) {
 	// Image compression is the last filter in the pipeline.
@@ -953,7 +993,7 @@ func RenderImage(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, r
 
 	switch f {
 
-	case filter.Flate, filter.LZW, filter.CCITTFax, filter.RunLength:
+	case filter.Flate, filter.LZW, filter.CCITTFax, filter.RunLength, filter.JBIG2:
 		return renderImage(xRefTable, sd, thumb, resourceName, objNr)
 
 	case filter.DCT:
@@ -963,7 +1003,7 @@ func RenderImage(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, r
 		return bytes.NewReader(sd.Content), "jpg", nil
 
 	case filter.JPX:
-		return bytes.NewReader(sd.Content), "jpx", nil
+		return renderJPX(xRefTable, sd, thumb, resourceName, objNr)
 	}
 
 	return nil, "", nil
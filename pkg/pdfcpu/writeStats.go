@@ -60,10 +60,22 @@ func logWriteStats(ctx *model.Context) {
 	l, str = ctx.Optimize.DuplicateFontObjectsString()
 	log.Stats.Printf("%d original redundant font entries: %s", l, str)
 
+	// Subset font programs
+	l, str = ctx.Optimize.SubsetFontsString()
+	log.Stats.Printf("%d subset font entries saving %s: %s", l, types.ByteSize(ctx.Optimize.FontBytesSaved), str)
+
 	// Duplicate image objects
 	l, str = ctx.Optimize.DuplicateImageObjectsString()
 	log.Stats.Printf("%d original redundant image entries: %s", l, str)
 
+	// Recompressed image objects
+	l, str = ctx.Optimize.RecompressedImagesString()
+	log.Stats.Printf("%d recompressed image entries saving %s: %s", l, types.ByteSize(ctx.Optimize.ImageBytesSaved), str)
+
+	// Image objects not worth recompressing
+	l, str = ctx.Optimize.SkippedImagesString()
+	log.Stats.Printf("%d skipped image entries: %s", l, str)
+
 	// Duplicate info objects
 	l, str = ctx.Optimize.DuplicateInfoObjectsString()
 	log.Stats.Printf("%d original redundant info entries: %s", l, str)
@@ -88,7 +100,8 @@ func statsHeadLine() *string {
 	hl += "P_LastModified;P_Resources;P_MediaBox;P_CropBox;P_BleedBox;P_TrimBox;P_ArtBox;"
 	hl += "P_BoxColorInfo;P_Contents;P_Rotate;P_Group;P_Thumb;P_B;P_Dur;P_Trans;P_Annots;"
 	hl += "P_AA;P_Metadata;P_PieceInfo;P_StructParents;P_ID;P_PZ;P_SeparationInfo;P_Tabs;"
-	hl += "P_TemplateInstantiated;P_PresSteps;P_UserUnit;P_VP;\n"
+	hl += "P_TemplateInstantiated;P_PresSteps;P_UserUnit;P_VP;"
+	hl += "font_subset;font_saved;img_recompressed;img_skipped;img_saved;\n"
 
 	return &hl
 }
@@ -152,7 +165,7 @@ func statsLine(ctx *model.Context) *string {
 		nonreferencedObjs = fmt.Sprintf("%d:%s", len(ctx.Optimize.NonReferencedObjs), strings.Join(s, ","))
 	}
 
-	line := fmt.Sprintf("%s;%s;%s;%s;%s;%s;%s;%s;%s;%v;%v;%v;%v;%d;%d;%s;%s;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v\n",
+	line := fmt.Sprintf("%s;%s;%s;%s;%s;%s;%s;%s;%s;%v;%v;%v;%v;%d;%d;%s;%s;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%v;%d;%s;%d;%d;%s\n",
 		filepath.Base(ctx.Read.FileName),
 		version,
 		xRefTable.Author,
@@ -224,7 +237,12 @@ func statsLine(ctx *model.Context) *string {
 		xRefTable.Stats.UsesPageAttr(model.PageTemplateInstantiated),
 		xRefTable.Stats.UsesPageAttr(model.PagePresSteps),
 		xRefTable.Stats.UsesPageAttr(model.PageUserUnit),
-		xRefTable.Stats.UsesPageAttr(model.PageVP))
+		xRefTable.Stats.UsesPageAttr(model.PageVP),
+		len(ctx.Optimize.SubsetFonts),
+		types.ByteSize(ctx.Optimize.FontBytesSaved),
+		len(ctx.Optimize.RecompressedImages),
+		len(ctx.Optimize.SkippedImages),
+		types.ByteSize(ctx.Optimize.ImageBytesSaved))
 
 	return &line
 }